@@ -25,15 +25,24 @@ const (
 )
 
 type Post struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
-	Slug          string                 `protobuf:"bytes,5,opt,name=slug,proto3" json:"slug,omitempty"`
-	Published     bool                   `protobuf:"varint,6,opt,name=published,proto3" json:"published,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId  string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title   string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Content string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Slug    string                 `protobuf:"bytes,5,opt,name=slug,proto3" json:"slug,omitempty"`
+	// True when status is "published"; kept for older clients.
+	Published bool                   `protobuf:"varint,6,opt,name=published,proto3" json:"published,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// draft, published or archived.
+	Status string `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	// Sanitized HTML rendering of content; set only when render_html was asked for.
+	ContentHtml   string `protobuf:"bytes,10,opt,name=content_html,json=contentHtml,proto3" json:"content_html,omitempty"`
+	CoverImageUrl string `protobuf:"bytes,11,opt,name=cover_image_url,json=coverImageUrl,proto3" json:"cover_image_url,omitempty"`
+	LikeCount     int64  `protobuf:"varint,12,opt,name=like_count,json=likeCount,proto3" json:"like_count,omitempty"`
+	// Whether the requesting user likes the post; unset for anonymous reads.
+	LikedByMe     *wrapperspb.BoolValue `protobuf:"bytes,13,opt,name=liked_by_me,json=likedByMe,proto3" json:"liked_by_me,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -124,6 +133,41 @@ func (x *Post) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Post) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Post) GetContentHtml() string {
+	if x != nil {
+		return x.ContentHtml
+	}
+	return ""
+}
+
+func (x *Post) GetCoverImageUrl() string {
+	if x != nil {
+		return x.CoverImageUrl
+	}
+	return ""
+}
+
+func (x *Post) GetLikeCount() int64 {
+	if x != nil {
+		return x.LikeCount
+	}
+	return 0
+}
+
+func (x *Post) GetLikedByMe() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.LikedByMe
+	}
+	return nil
+}
+
 type PostSummary struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -133,6 +177,10 @@ type PostSummary struct {
 	Published     bool                   `protobuf:"varint,5,opt,name=published,proto3" json:"published,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	CoverImageUrl string                 `protobuf:"bytes,9,opt,name=cover_image_url,json=coverImageUrl,proto3" json:"cover_image_url,omitempty"`
+	// The author's excerpt, or the start of the content when there is none.
+	Excerpt       string `protobuf:"bytes,10,opt,name=excerpt,proto3" json:"excerpt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -216,6 +264,27 @@ func (x *PostSummary) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *PostSummary) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PostSummary) GetCoverImageUrl() string {
+	if x != nil {
+		return x.CoverImageUrl
+	}
+	return ""
+}
+
+func (x *PostSummary) GetExcerpt() string {
+	if x != nil {
+		return x.Excerpt
+	}
+	return ""
+}
+
 type CreatePostRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -223,6 +292,9 @@ type CreatePostRequest struct {
 	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
 	Slug          string                 `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
 	Published     bool                   `protobuf:"varint,5,opt,name=published,proto3" json:"published,omitempty"`
+	CoverImageUrl string                 `protobuf:"bytes,6,opt,name=cover_image_url,json=coverImageUrl,proto3" json:"cover_image_url,omitempty"`
+	// Optional; generated from the content when empty.
+	Excerpt       string `protobuf:"bytes,7,opt,name=excerpt,proto3" json:"excerpt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -292,14 +364,32 @@ func (x *CreatePostRequest) GetPublished() bool {
 	return false
 }
 
+func (x *CreatePostRequest) GetCoverImageUrl() string {
+	if x != nil {
+		return x.CoverImageUrl
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetExcerpt() string {
+	if x != nil {
+		return x.Excerpt
+	}
+	return ""
+}
+
 type UpdatePostRequest struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        string                  `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Title         *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Content       *wrapperspb.StringValue `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
-	Slug          *wrapperspb.StringValue `protobuf:"bytes,5,opt,name=slug,proto3" json:"slug,omitempty"`
-	Published     *wrapperspb.BoolValue   `protobuf:"bytes,6,opt,name=published,proto3" json:"published,omitempty"`
+	state     protoimpl.MessageState  `protogen:"open.v1"`
+	Id        string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId    string                  `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title     *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Content   *wrapperspb.StringValue `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Slug      *wrapperspb.StringValue `protobuf:"bytes,5,opt,name=slug,proto3" json:"slug,omitempty"`
+	Published *wrapperspb.BoolValue   `protobuf:"bytes,6,opt,name=published,proto3" json:"published,omitempty"`
+	// An empty value removes the cover image.
+	CoverImageUrl *wrapperspb.StringValue `protobuf:"bytes,7,opt,name=cover_image_url,json=coverImageUrl,proto3" json:"cover_image_url,omitempty"`
+	// An empty value goes back to an excerpt generated from the content.
+	Excerpt       *wrapperspb.StringValue `protobuf:"bytes,8,opt,name=excerpt,proto3" json:"excerpt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -376,10 +466,25 @@ func (x *UpdatePostRequest) GetPublished() *wrapperspb.BoolValue {
 	return nil
 }
 
+func (x *UpdatePostRequest) GetCoverImageUrl() *wrapperspb.StringValue {
+	if x != nil {
+		return x.CoverImageUrl
+	}
+	return nil
+}
+
+func (x *UpdatePostRequest) GetExcerpt() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Excerpt
+	}
+	return nil
+}
+
 type GetPostRequest struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	RequestingUserId string                 `protobuf:"bytes,2,opt,name=requesting_user_id,json=requestingUserId,proto3" json:"requesting_user_id,omitempty"`
+	RenderHtml       bool                   `protobuf:"varint,3,opt,name=render_html,json=renderHtml,proto3" json:"render_html,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -428,11 +533,21 @@ func (x *GetPostRequest) GetRequestingUserId() string {
 	return ""
 }
 
+func (x *GetPostRequest) GetRenderHtml() bool {
+	if x != nil {
+		return x.RenderHtml
+	}
+	return false
+}
+
 type GetPostBySlugRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Slug  string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	// Set to also find the requesting user's own unpublished posts.
+	RequestingUserId string `protobuf:"bytes,2,opt,name=requesting_user_id,json=requestingUserId,proto3" json:"requesting_user_id,omitempty"`
+	RenderHtml       bool   `protobuf:"varint,3,opt,name=render_html,json=renderHtml,proto3" json:"render_html,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *GetPostBySlugRequest) Reset() {
@@ -472,6 +587,20 @@ func (x *GetPostBySlugRequest) GetSlug() string {
 	return ""
 }
 
+func (x *GetPostBySlugRequest) GetRequestingUserId() string {
+	if x != nil {
+		return x.RequestingUserId
+	}
+	return ""
+}
+
+func (x *GetPostBySlugRequest) GetRenderHtml() bool {
+	if x != nil {
+		return x.RenderHtml
+	}
+	return false
+}
+
 type DeletePostRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -524,29 +653,28 @@ func (x *DeletePostRequest) GetUserId() string {
 	return ""
 }
 
-type ListPostsRequest struct {
+type DeletePostsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	PublishedOnly bool                   `protobuf:"varint,3,opt,name=published_only,json=publishedOnly,proto3" json:"published_only,omitempty"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListPostsRequest) Reset() {
-	*x = ListPostsRequest{}
+func (x *DeletePostsRequest) Reset() {
+	*x = DeletePostsRequest{}
 	mi := &file_proto_post_v1_post_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPostsRequest) String() string {
+func (x *DeletePostsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPostsRequest) ProtoMessage() {}
+func (*DeletePostsRequest) ProtoMessage() {}
 
-func (x *ListPostsRequest) ProtoReflect() protoreflect.Message {
+func (x *DeletePostsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_post_v1_post_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -558,55 +686,48 @@ func (x *ListPostsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPostsRequest.ProtoReflect.Descriptor instead.
-func (*ListPostsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeletePostsRequest.ProtoReflect.Descriptor instead.
+func (*DeletePostsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ListPostsRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
-	}
-	return 0
-}
-
-func (x *ListPostsRequest) GetOffset() int32 {
+func (x *DeletePostsRequest) GetIds() []string {
 	if x != nil {
-		return x.Offset
+		return x.Ids
 	}
-	return 0
+	return nil
 }
 
-func (x *ListPostsRequest) GetPublishedOnly() bool {
+func (x *DeletePostsRequest) GetUserId() string {
 	if x != nil {
-		return x.PublishedOnly
+		return x.UserId
 	}
-	return false
+	return ""
 }
 
-type GetUserPostsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+type DeletePostResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// False when the post does not exist or belongs to someone else.
+	Deleted       bool `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserPostsRequest) Reset() {
-	*x = GetUserPostsRequest{}
+func (x *DeletePostResult) Reset() {
+	*x = DeletePostResult{}
 	mi := &file_proto_post_v1_post_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserPostsRequest) String() string {
+func (x *DeletePostResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserPostsRequest) ProtoMessage() {}
+func (*DeletePostResult) ProtoMessage() {}
 
-func (x *GetUserPostsRequest) ProtoReflect() protoreflect.Message {
+func (x *DeletePostResult) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_post_v1_post_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -618,56 +739,47 @@ func (x *GetUserPostsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserPostsRequest.ProtoReflect.Descriptor instead.
-func (*GetUserPostsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeletePostResult.ProtoReflect.Descriptor instead.
+func (*DeletePostResult) Descriptor() ([]byte, []int) {
 	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetUserPostsRequest) GetUserId() string {
+func (x *DeletePostResult) GetId() string {
 	if x != nil {
-		return x.UserId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *GetUserPostsRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
-	}
-	return 0
-}
-
-func (x *GetUserPostsRequest) GetOffset() int32 {
+func (x *DeletePostResult) GetDeleted() bool {
 	if x != nil {
-		return x.Offset
+		return x.Deleted
 	}
-	return 0
+	return false
 }
 
-type SearchPostsRequest struct {
+type DeletePostsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	PublishedOnly bool                   `protobuf:"varint,4,opt,name=published_only,json=publishedOnly,proto3" json:"published_only,omitempty"`
+	Results       []*DeletePostResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Deleted       int32                  `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SearchPostsRequest) Reset() {
-	*x = SearchPostsRequest{}
+func (x *DeletePostsResponse) Reset() {
+	*x = DeletePostsResponse{}
 	mi := &file_proto_post_v1_post_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchPostsRequest) String() string {
+func (x *DeletePostsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchPostsRequest) ProtoMessage() {}
+func (*DeletePostsResponse) ProtoMessage() {}
 
-func (x *SearchPostsRequest) ProtoReflect() protoreflect.Message {
+func (x *DeletePostsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_post_v1_post_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -679,60 +791,51 @@ func (x *SearchPostsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchPostsRequest.ProtoReflect.Descriptor instead.
-func (*SearchPostsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeletePostsResponse.ProtoReflect.Descriptor instead.
+func (*DeletePostsResponse) Descriptor() ([]byte, []int) {
 	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *SearchPostsRequest) GetQuery() string {
-	if x != nil {
-		return x.Query
-	}
-	return ""
-}
-
-func (x *SearchPostsRequest) GetLimit() int32 {
+func (x *DeletePostsResponse) GetResults() []*DeletePostResult {
 	if x != nil {
-		return x.Limit
+		return x.Results
 	}
-	return 0
+	return nil
 }
 
-func (x *SearchPostsRequest) GetOffset() int32 {
+func (x *DeletePostsResponse) GetDeleted() int32 {
 	if x != nil {
-		return x.Offset
+		return x.Deleted
 	}
 	return 0
 }
 
-func (x *SearchPostsRequest) GetPublishedOnly() bool {
-	if x != nil {
-		return x.PublishedOnly
-	}
-	return false
-}
-
-type GetStatsRequest struct {
+type ListPostsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	PublishedOnly bool                   `protobuf:"varint,3,opt,name=published_only,json=publishedOnly,proto3" json:"published_only,omitempty"`
+	// Optional created_at bounds, inclusive.
+	From          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsRequest) Reset() {
-	*x = GetStatsRequest{}
+func (x *ListPostsRequest) Reset() {
+	*x = ListPostsRequest{}
 	mi := &file_proto_post_v1_post_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsRequest) String() string {
+func (x *ListPostsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsRequest) ProtoMessage() {}
+func (*ListPostsRequest) ProtoMessage() {}
 
-func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+func (x *ListPostsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_post_v1_post_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -744,42 +847,72 @@ func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListPostsRequest.ProtoReflect.Descriptor instead.
+func (*ListPostsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GetStatsRequest) GetUserId() string {
+func (x *ListPostsRequest) GetLimit() int32 {
 	if x != nil {
-		return x.UserId
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-type ListPostsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Posts         []*PostSummary         `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ListPostsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
 }
 
-func (x *ListPostsResponse) Reset() {
-	*x = ListPostsResponse{}
+func (x *ListPostsRequest) GetPublishedOnly() bool {
+	if x != nil {
+		return x.PublishedOnly
+	}
+	return false
+}
+
+func (x *ListPostsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListPostsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type GetUserPostsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit  int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Empty means published. Other statuses need requesting_user_id == user_id.
+	Status           string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	RequestingUserId string `protobuf:"bytes,5,opt,name=requesting_user_id,json=requestingUserId,proto3" json:"requesting_user_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetUserPostsRequest) Reset() {
+	*x = GetUserPostsRequest{}
 	mi := &file_proto_post_v1_post_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPostsResponse) String() string {
+func (x *GetUserPostsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPostsResponse) ProtoMessage() {}
+func (*GetUserPostsRequest) ProtoMessage() {}
 
-func (x *ListPostsResponse) ProtoReflect() protoreflect.Message {
+func (x *GetUserPostsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_post_v1_post_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -791,26 +924,230 @@ func (x *ListPostsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPostsResponse.ProtoReflect.Descriptor instead.
-func (*ListPostsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetUserPostsRequest.ProtoReflect.Descriptor instead.
+func (*GetUserPostsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ListPostsResponse) GetPosts() []*PostSummary {
+func (x *GetUserPostsRequest) GetUserId() string {
 	if x != nil {
-		return x.Posts
+		return x.UserId
 	}
-	return nil
+	return ""
 }
 
-func (x *ListPostsResponse) GetLimit() int32 {
+func (x *GetUserPostsRequest) GetLimit() int32 {
 	if x != nil {
 		return x.Limit
 	}
 	return 0
 }
 
-func (x *ListPostsResponse) GetOffset() int32 {
+func (x *GetUserPostsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetUserPostsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetUserPostsRequest) GetRequestingUserId() string {
+	if x != nil {
+		return x.RequestingUserId
+	}
+	return ""
+}
+
+type SearchPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	PublishedOnly bool                   `protobuf:"varint,4,opt,name=published_only,json=publishedOnly,proto3" json:"published_only,omitempty"`
+	// Optional created_at bounds, inclusive.
+	From          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPostsRequest) Reset() {
+	*x = SearchPostsRequest{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPostsRequest) ProtoMessage() {}
+
+func (x *SearchPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPostsRequest.ProtoReflect.Descriptor instead.
+func (*SearchPostsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchPostsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchPostsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchPostsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchPostsRequest) GetPublishedOnly() bool {
+	if x != nil {
+		return x.PublishedOnly
+	}
+	return false
+}
+
+func (x *SearchPostsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *SearchPostsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetStatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListPostsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Posts         []*PostSummary         `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPostsResponse) Reset() {
+	*x = ListPostsResponse{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPostsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPostsResponse) ProtoMessage() {}
+
+func (x *ListPostsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPostsResponse.ProtoReflect.Descriptor instead.
+func (*ListPostsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListPostsResponse) GetPosts() []*PostSummary {
+	if x != nil {
+		return x.Posts
+	}
+	return nil
+}
+
+func (x *ListPostsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListPostsResponse) GetOffset() int32 {
 	if x != nil {
 		return x.Offset
 	}
@@ -819,34 +1156,208 @@ func (x *ListPostsResponse) GetOffset() int32 {
 
 func (x *ListPostsResponse) GetTotal() int32 {
 	if x != nil {
-		return x.Total
+		return x.Total
+	}
+	return 0
+}
+
+type PostStatsResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	TotalPublishedPosts int64                  `protobuf:"varint,1,opt,name=total_published_posts,json=totalPublishedPosts,proto3" json:"total_published_posts,omitempty"`
+	UserPostsCount      int64                  `protobuf:"varint,2,opt,name=user_posts_count,json=userPostsCount,proto3" json:"user_posts_count,omitempty"`
+	// Only populated when user_id is set on the request.
+	UserDraftCount     int64 `protobuf:"varint,3,opt,name=user_draft_count,json=userDraftCount,proto3" json:"user_draft_count,omitempty"`
+	UserScheduledCount int64 `protobuf:"varint,4,opt,name=user_scheduled_count,json=userScheduledCount,proto3" json:"user_scheduled_count,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PostStatsResponse) Reset() {
+	*x = PostStatsResponse{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostStatsResponse) ProtoMessage() {}
+
+func (x *PostStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostStatsResponse.ProtoReflect.Descriptor instead.
+func (*PostStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PostStatsResponse) GetTotalPublishedPosts() int64 {
+	if x != nil {
+		return x.TotalPublishedPosts
+	}
+	return 0
+}
+
+func (x *PostStatsResponse) GetUserPostsCount() int64 {
+	if x != nil {
+		return x.UserPostsCount
+	}
+	return 0
+}
+
+func (x *PostStatsResponse) GetUserDraftCount() int64 {
+	if x != nil {
+		return x.UserDraftCount
+	}
+	return 0
+}
+
+func (x *PostStatsResponse) GetUserScheduledCount() int64 {
+	if x != nil {
+		return x.UserScheduledCount
+	}
+	return 0
+}
+
+type ArchivePostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchivePostRequest) Reset() {
+	*x = ArchivePostRequest{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchivePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivePostRequest) ProtoMessage() {}
+
+func (x *ArchivePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivePostRequest.ProtoReflect.Descriptor instead.
+func (*ArchivePostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ArchivePostRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ArchivePostRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type LikePostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LikePostRequest) Reset() {
+	*x = LikePostRequest{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LikePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LikePostRequest) ProtoMessage() {}
+
+func (x *LikePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-type PostStatsResponse struct {
-	state               protoimpl.MessageState `protogen:"open.v1"`
-	TotalPublishedPosts int64                  `protobuf:"varint,1,opt,name=total_published_posts,json=totalPublishedPosts,proto3" json:"total_published_posts,omitempty"`
-	UserPostsCount      int64                  `protobuf:"varint,2,opt,name=user_posts_count,json=userPostsCount,proto3" json:"user_posts_count,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+// Deprecated: Use LikePostRequest.ProtoReflect.Descriptor instead.
+func (*LikePostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *PostStatsResponse) Reset() {
-	*x = PostStatsResponse{}
-	mi := &file_proto_post_v1_post_proto_msgTypes[12]
+func (x *LikePostRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LikePostRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type PostLikeState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	LikeCount     int64                  `protobuf:"varint,2,opt,name=like_count,json=likeCount,proto3" json:"like_count,omitempty"`
+	LikedByMe     bool                   `protobuf:"varint,3,opt,name=liked_by_me,json=likedByMe,proto3" json:"liked_by_me,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostLikeState) Reset() {
+	*x = PostLikeState{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PostStatsResponse) String() string {
+func (x *PostLikeState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PostStatsResponse) ProtoMessage() {}
+func (*PostLikeState) ProtoMessage() {}
 
-func (x *PostStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_post_v1_post_proto_msgTypes[12]
+func (x *PostLikeState) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -857,30 +1368,314 @@ func (x *PostStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PostStatsResponse.ProtoReflect.Descriptor instead.
-func (*PostStatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use PostLikeState.ProtoReflect.Descriptor instead.
+func (*PostLikeState) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *PostStatsResponse) GetTotalPublishedPosts() int64 {
+func (x *PostLikeState) GetPostId() string {
 	if x != nil {
-		return x.TotalPublishedPosts
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *PostLikeState) GetLikeCount() int64 {
+	if x != nil {
+		return x.LikeCount
 	}
 	return 0
 }
 
-func (x *PostStatsResponse) GetUserPostsCount() int64 {
+func (x *PostLikeState) GetLikedByMe() bool {
 	if x != nil {
-		return x.UserPostsCount
+		return x.LikedByMe
+	}
+	return false
+}
+
+type ClonePostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClonePostRequest) Reset() {
+	*x = ClonePostRequest{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClonePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClonePostRequest) ProtoMessage() {}
+
+func (x *ClonePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClonePostRequest.ProtoReflect.Descriptor instead.
+func (*ClonePostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ClonePostRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ClonePostRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type CreatePreviewTokenRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Zero means the link works until revoked.
+	ExpiresInHours int32 `protobuf:"varint,3,opt,name=expires_in_hours,json=expiresInHours,proto3" json:"expires_in_hours,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreatePreviewTokenRequest) Reset() {
+	*x = CreatePreviewTokenRequest{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePreviewTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePreviewTokenRequest) ProtoMessage() {}
+
+func (x *CreatePreviewTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePreviewTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreatePreviewTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreatePreviewTokenRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreatePreviewTokenRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreatePreviewTokenRequest) GetExpiresInHours() int32 {
+	if x != nil {
+		return x.ExpiresInHours
 	}
 	return 0
 }
 
+type PreviewToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewToken) Reset() {
+	*x = PreviewToken{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewToken) ProtoMessage() {}
+
+func (x *PreviewToken) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewToken.ProtoReflect.Descriptor instead.
+func (*PreviewToken) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PreviewToken) GetPostId() string {
+	if x != nil {
+		return x.PostId
+	}
+	return ""
+}
+
+func (x *PreviewToken) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PreviewToken) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type RevokePreviewTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokePreviewTokenRequest) Reset() {
+	*x = RevokePreviewTokenRequest{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokePreviewTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokePreviewTokenRequest) ProtoMessage() {}
+
+func (x *RevokePreviewTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokePreviewTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokePreviewTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RevokePreviewTokenRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevokePreviewTokenRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetPostByPreviewTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RenderHtml    bool                   `protobuf:"varint,2,opt,name=render_html,json=renderHtml,proto3" json:"render_html,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostByPreviewTokenRequest) Reset() {
+	*x = GetPostByPreviewTokenRequest{}
+	mi := &file_proto_post_v1_post_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostByPreviewTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostByPreviewTokenRequest) ProtoMessage() {}
+
+func (x *GetPostByPreviewTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_post_v1_post_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostByPreviewTokenRequest.ProtoReflect.Descriptor instead.
+func (*GetPostByPreviewTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_post_v1_post_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetPostByPreviewTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *GetPostByPreviewTokenRequest) GetRenderHtml() bool {
+	if x != nil {
+		return x.RenderHtml
+	}
+	return false
+}
+
 var File_proto_post_v1_post_proto protoreflect.FileDescriptor
 
 const file_proto_post_v1_post_proto_rawDesc = "" +
 	"\n" +
-	"\x18proto/post/v1/post.proto\x12\apost.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x87\x02\n" +
+	"\x18proto/post/v1/post.proto\x12\apost.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\xc5\x03\n" +
 	"\x04Post\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
@@ -891,7 +1686,14 @@ const file_proto_post_v1_post_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xf4\x01\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12!\n" +
+	"\fcontent_html\x18\n" +
+	" \x01(\tR\vcontentHtml\x12&\n" +
+	"\x0fcover_image_url\x18\v \x01(\tR\rcoverImageUrl\x12\x1d\n" +
+	"\n" +
+	"like_count\x18\f \x01(\x03R\tlikeCount\x12:\n" +
+	"\vliked_by_me\x18\r \x01(\v2\x1a.google.protobuf.BoolValueR\tlikedByMe\"\xce\x02\n" +
 	"\vPostSummary\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
@@ -901,51 +1703,111 @@ const file_proto_post_v1_post_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x8e\x01\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12&\n" +
+	"\x0fcover_image_url\x18\t \x01(\tR\rcoverImageUrl\x12\x18\n" +
+	"\aexcerpt\x18\n" +
+	" \x01(\tR\aexcerpt\"\xd0\x01\n" +
 	"\x11CreatePostRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x12\n" +
 	"\x04slug\x18\x04 \x01(\tR\x04slug\x12\x1c\n" +
-	"\tpublished\x18\x05 \x01(\bR\tpublished\"\x94\x02\n" +
+	"\tpublished\x18\x05 \x01(\bR\tpublished\x12&\n" +
+	"\x0fcover_image_url\x18\x06 \x01(\tR\rcoverImageUrl\x12\x18\n" +
+	"\aexcerpt\x18\a \x01(\tR\aexcerpt\"\x92\x03\n" +
 	"\x11UpdatePostRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x122\n" +
 	"\x05title\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\x05title\x126\n" +
 	"\acontent\x18\x04 \x01(\v2\x1c.google.protobuf.StringValueR\acontent\x120\n" +
 	"\x04slug\x18\x05 \x01(\v2\x1c.google.protobuf.StringValueR\x04slug\x128\n" +
-	"\tpublished\x18\x06 \x01(\v2\x1a.google.protobuf.BoolValueR\tpublished\"N\n" +
+	"\tpublished\x18\x06 \x01(\v2\x1a.google.protobuf.BoolValueR\tpublished\x12D\n" +
+	"\x0fcover_image_url\x18\a \x01(\v2\x1c.google.protobuf.StringValueR\rcoverImageUrl\x126\n" +
+	"\aexcerpt\x18\b \x01(\v2\x1c.google.protobuf.StringValueR\aexcerpt\"o\n" +
 	"\x0eGetPostRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12,\n" +
-	"\x12requesting_user_id\x18\x02 \x01(\tR\x10requestingUserId\"*\n" +
+	"\x12requesting_user_id\x18\x02 \x01(\tR\x10requestingUserId\x12\x1f\n" +
+	"\vrender_html\x18\x03 \x01(\bR\n" +
+	"renderHtml\"y\n" +
 	"\x14GetPostBySlugRequest\x12\x12\n" +
-	"\x04slug\x18\x01 \x01(\tR\x04slug\"<\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12,\n" +
+	"\x12requesting_user_id\x18\x02 \x01(\tR\x10requestingUserId\x12\x1f\n" +
+	"\vrender_html\x18\x03 \x01(\bR\n" +
+	"renderHtml\"<\n" +
 	"\x11DeletePostRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"g\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"?\n" +
+	"\x12DeletePostsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"<\n" +
+	"\x10DeletePostResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\adeleted\x18\x02 \x01(\bR\adeleted\"d\n" +
+	"\x13DeletePostsResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.post.v1.DeletePostResultR\aresults\x12\x18\n" +
+	"\adeleted\x18\x02 \x01(\x05R\adeleted\"\xc3\x01\n" +
 	"\x10ListPostsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12%\n" +
-	"\x0epublished_only\x18\x03 \x01(\bR\rpublishedOnly\"\\\n" +
+	"\x0epublished_only\x18\x03 \x01(\bR\rpublishedOnly\x12.\n" +
+	"\x04from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\xa2\x01\n" +
 	"\x13GetUserPostsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\x7f\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12,\n" +
+	"\x12requesting_user_id\x18\x05 \x01(\tR\x10requestingUserId\"\xdb\x01\n" +
 	"\x12SearchPostsRequest\x12\x14\n" +
 	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12%\n" +
-	"\x0epublished_only\x18\x04 \x01(\bR\rpublishedOnly\"*\n" +
+	"\x0epublished_only\x18\x04 \x01(\bR\rpublishedOnly\x12.\n" +
+	"\x04from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"*\n" +
 	"\x0fGetStatsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x83\x01\n" +
 	"\x11ListPostsResponse\x12*\n" +
 	"\x05posts\x18\x01 \x03(\v2\x14.post.v1.PostSummaryR\x05posts\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x14\n" +
-	"\x05total\x18\x04 \x01(\x05R\x05total\"q\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\"\xcd\x01\n" +
 	"\x11PostStatsResponse\x122\n" +
 	"\x15total_published_posts\x18\x01 \x01(\x03R\x13totalPublishedPosts\x12(\n" +
-	"\x10user_posts_count\x18\x02 \x01(\x03R\x0euserPostsCount2\x8a\x05\n" +
+	"\x10user_posts_count\x18\x02 \x01(\x03R\x0euserPostsCount\x12(\n" +
+	"\x10user_draft_count\x18\x03 \x01(\x03R\x0euserDraftCount\x120\n" +
+	"\x14user_scheduled_count\x18\x04 \x01(\x03R\x12userScheduledCount\"=\n" +
+	"\x12ArchivePostRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\":\n" +
+	"\x0fLikePostRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"g\n" +
+	"\rPostLikeState\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x1d\n" +
+	"\n" +
+	"like_count\x18\x02 \x01(\x03R\tlikeCount\x12\x1e\n" +
+	"\vliked_by_me\x18\x03 \x01(\bR\tlikedByMe\";\n" +
+	"\x10ClonePostRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"n\n" +
+	"\x19CreatePreviewTokenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12(\n" +
+	"\x10expires_in_hours\x18\x03 \x01(\x05R\x0eexpiresInHours\"x\n" +
+	"\fPreviewToken\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"D\n" +
+	"\x19RevokePreviewTokenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"U\n" +
+	"\x1cGetPostByPreviewTokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1f\n" +
+	"\vrender_html\x18\x02 \x01(\bR\n" +
+	"renderHtml2\xb6\t\n" +
 	"\vPostService\x127\n" +
 	"\n" +
 	"CreatePost\x12\x1a.post.v1.CreatePostRequest\x1a\r.post.v1.Post\x121\n" +
@@ -954,11 +1816,20 @@ const file_proto_post_v1_post_proto_rawDesc = "" +
 	"\n" +
 	"UpdatePost\x12\x1a.post.v1.UpdatePostRequest\x1a\r.post.v1.Post\x12@\n" +
 	"\n" +
-	"DeletePost\x12\x1a.post.v1.DeletePostRequest\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"DeletePost\x12\x1a.post.v1.DeletePostRequest\x1a\x16.google.protobuf.Empty\x12H\n" +
+	"\vDeletePosts\x12\x1b.post.v1.DeletePostsRequest\x1a\x1c.post.v1.DeletePostsResponse\x125\n" +
+	"\tClonePost\x12\x19.post.v1.ClonePostRequest\x1a\r.post.v1.Post\x129\n" +
+	"\vArchivePost\x12\x1b.post.v1.ArchivePostRequest\x1a\r.post.v1.Post\x12<\n" +
+	"\bLikePost\x12\x18.post.v1.LikePostRequest\x1a\x16.post.v1.PostLikeState\x12>\n" +
+	"\n" +
+	"UnlikePost\x12\x18.post.v1.LikePostRequest\x1a\x16.post.v1.PostLikeState\x12B\n" +
 	"\tListPosts\x12\x19.post.v1.ListPostsRequest\x1a\x1a.post.v1.ListPostsResponse\x12H\n" +
 	"\fGetUserPosts\x12\x1c.post.v1.GetUserPostsRequest\x1a\x1a.post.v1.ListPostsResponse\x12F\n" +
 	"\vSearchPosts\x12\x1b.post.v1.SearchPostsRequest\x1a\x1a.post.v1.ListPostsResponse\x12@\n" +
-	"\bGetStats\x12\x18.post.v1.GetStatsRequest\x1a\x1a.post.v1.PostStatsResponse\x12=\n" +
+	"\bGetStats\x12\x18.post.v1.GetStatsRequest\x1a\x1a.post.v1.PostStatsResponse\x12O\n" +
+	"\x12CreatePreviewToken\x12\".post.v1.CreatePreviewTokenRequest\x1a\x15.post.v1.PreviewToken\x12P\n" +
+	"\x12RevokePreviewToken\x12\".post.v1.RevokePreviewTokenRequest\x1a\x16.google.protobuf.Empty\x12M\n" +
+	"\x15GetPostByPreviewToken\x12%.post.v1.GetPostByPreviewTokenRequest\x1a\r.post.v1.Post\x12=\n" +
 	"\vHealthCheck\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.EmptyB=Z;github.com/nikitashilov/microblog_grpc/proto/post/v1;postv1b\x06proto3"
 
 var (
@@ -973,61 +1844,97 @@ func file_proto_post_v1_post_proto_rawDescGZIP() []byte {
 	return file_proto_post_v1_post_proto_rawDescData
 }
 
-var file_proto_post_v1_post_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_post_v1_post_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_proto_post_v1_post_proto_goTypes = []any{
-	(*Post)(nil),                   // 0: post.v1.Post
-	(*PostSummary)(nil),            // 1: post.v1.PostSummary
-	(*CreatePostRequest)(nil),      // 2: post.v1.CreatePostRequest
-	(*UpdatePostRequest)(nil),      // 3: post.v1.UpdatePostRequest
-	(*GetPostRequest)(nil),         // 4: post.v1.GetPostRequest
-	(*GetPostBySlugRequest)(nil),   // 5: post.v1.GetPostBySlugRequest
-	(*DeletePostRequest)(nil),      // 6: post.v1.DeletePostRequest
-	(*ListPostsRequest)(nil),       // 7: post.v1.ListPostsRequest
-	(*GetUserPostsRequest)(nil),    // 8: post.v1.GetUserPostsRequest
-	(*SearchPostsRequest)(nil),     // 9: post.v1.SearchPostsRequest
-	(*GetStatsRequest)(nil),        // 10: post.v1.GetStatsRequest
-	(*ListPostsResponse)(nil),      // 11: post.v1.ListPostsResponse
-	(*PostStatsResponse)(nil),      // 12: post.v1.PostStatsResponse
-	(*timestamppb.Timestamp)(nil),  // 13: google.protobuf.Timestamp
-	(*wrapperspb.StringValue)(nil), // 14: google.protobuf.StringValue
-	(*wrapperspb.BoolValue)(nil),   // 15: google.protobuf.BoolValue
-	(*emptypb.Empty)(nil),          // 16: google.protobuf.Empty
+	(*Post)(nil),                         // 0: post.v1.Post
+	(*PostSummary)(nil),                  // 1: post.v1.PostSummary
+	(*CreatePostRequest)(nil),            // 2: post.v1.CreatePostRequest
+	(*UpdatePostRequest)(nil),            // 3: post.v1.UpdatePostRequest
+	(*GetPostRequest)(nil),               // 4: post.v1.GetPostRequest
+	(*GetPostBySlugRequest)(nil),         // 5: post.v1.GetPostBySlugRequest
+	(*DeletePostRequest)(nil),            // 6: post.v1.DeletePostRequest
+	(*DeletePostsRequest)(nil),           // 7: post.v1.DeletePostsRequest
+	(*DeletePostResult)(nil),             // 8: post.v1.DeletePostResult
+	(*DeletePostsResponse)(nil),          // 9: post.v1.DeletePostsResponse
+	(*ListPostsRequest)(nil),             // 10: post.v1.ListPostsRequest
+	(*GetUserPostsRequest)(nil),          // 11: post.v1.GetUserPostsRequest
+	(*SearchPostsRequest)(nil),           // 12: post.v1.SearchPostsRequest
+	(*GetStatsRequest)(nil),              // 13: post.v1.GetStatsRequest
+	(*ListPostsResponse)(nil),            // 14: post.v1.ListPostsResponse
+	(*PostStatsResponse)(nil),            // 15: post.v1.PostStatsResponse
+	(*ArchivePostRequest)(nil),           // 16: post.v1.ArchivePostRequest
+	(*LikePostRequest)(nil),              // 17: post.v1.LikePostRequest
+	(*PostLikeState)(nil),                // 18: post.v1.PostLikeState
+	(*ClonePostRequest)(nil),             // 19: post.v1.ClonePostRequest
+	(*CreatePreviewTokenRequest)(nil),    // 20: post.v1.CreatePreviewTokenRequest
+	(*PreviewToken)(nil),                 // 21: post.v1.PreviewToken
+	(*RevokePreviewTokenRequest)(nil),    // 22: post.v1.RevokePreviewTokenRequest
+	(*GetPostByPreviewTokenRequest)(nil), // 23: post.v1.GetPostByPreviewTokenRequest
+	(*timestamppb.Timestamp)(nil),        // 24: google.protobuf.Timestamp
+	(*wrapperspb.BoolValue)(nil),         // 25: google.protobuf.BoolValue
+	(*wrapperspb.StringValue)(nil),       // 26: google.protobuf.StringValue
+	(*emptypb.Empty)(nil),                // 27: google.protobuf.Empty
 }
 var file_proto_post_v1_post_proto_depIdxs = []int32{
-	13, // 0: post.v1.Post.created_at:type_name -> google.protobuf.Timestamp
-	13, // 1: post.v1.Post.updated_at:type_name -> google.protobuf.Timestamp
-	13, // 2: post.v1.PostSummary.created_at:type_name -> google.protobuf.Timestamp
-	13, // 3: post.v1.PostSummary.updated_at:type_name -> google.protobuf.Timestamp
-	14, // 4: post.v1.UpdatePostRequest.title:type_name -> google.protobuf.StringValue
-	14, // 5: post.v1.UpdatePostRequest.content:type_name -> google.protobuf.StringValue
-	14, // 6: post.v1.UpdatePostRequest.slug:type_name -> google.protobuf.StringValue
-	15, // 7: post.v1.UpdatePostRequest.published:type_name -> google.protobuf.BoolValue
-	1,  // 8: post.v1.ListPostsResponse.posts:type_name -> post.v1.PostSummary
-	2,  // 9: post.v1.PostService.CreatePost:input_type -> post.v1.CreatePostRequest
-	4,  // 10: post.v1.PostService.GetPost:input_type -> post.v1.GetPostRequest
-	5,  // 11: post.v1.PostService.GetPostBySlug:input_type -> post.v1.GetPostBySlugRequest
-	3,  // 12: post.v1.PostService.UpdatePost:input_type -> post.v1.UpdatePostRequest
-	6,  // 13: post.v1.PostService.DeletePost:input_type -> post.v1.DeletePostRequest
-	7,  // 14: post.v1.PostService.ListPosts:input_type -> post.v1.ListPostsRequest
-	8,  // 15: post.v1.PostService.GetUserPosts:input_type -> post.v1.GetUserPostsRequest
-	9,  // 16: post.v1.PostService.SearchPosts:input_type -> post.v1.SearchPostsRequest
-	10, // 17: post.v1.PostService.GetStats:input_type -> post.v1.GetStatsRequest
-	16, // 18: post.v1.PostService.HealthCheck:input_type -> google.protobuf.Empty
-	0,  // 19: post.v1.PostService.CreatePost:output_type -> post.v1.Post
-	0,  // 20: post.v1.PostService.GetPost:output_type -> post.v1.Post
-	0,  // 21: post.v1.PostService.GetPostBySlug:output_type -> post.v1.Post
-	0,  // 22: post.v1.PostService.UpdatePost:output_type -> post.v1.Post
-	16, // 23: post.v1.PostService.DeletePost:output_type -> google.protobuf.Empty
-	11, // 24: post.v1.PostService.ListPosts:output_type -> post.v1.ListPostsResponse
-	11, // 25: post.v1.PostService.GetUserPosts:output_type -> post.v1.ListPostsResponse
-	11, // 26: post.v1.PostService.SearchPosts:output_type -> post.v1.ListPostsResponse
-	12, // 27: post.v1.PostService.GetStats:output_type -> post.v1.PostStatsResponse
-	16, // 28: post.v1.PostService.HealthCheck:output_type -> google.protobuf.Empty
-	19, // [19:29] is the sub-list for method output_type
-	9,  // [9:19] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	24, // 0: post.v1.Post.created_at:type_name -> google.protobuf.Timestamp
+	24, // 1: post.v1.Post.updated_at:type_name -> google.protobuf.Timestamp
+	25, // 2: post.v1.Post.liked_by_me:type_name -> google.protobuf.BoolValue
+	24, // 3: post.v1.PostSummary.created_at:type_name -> google.protobuf.Timestamp
+	24, // 4: post.v1.PostSummary.updated_at:type_name -> google.protobuf.Timestamp
+	26, // 5: post.v1.UpdatePostRequest.title:type_name -> google.protobuf.StringValue
+	26, // 6: post.v1.UpdatePostRequest.content:type_name -> google.protobuf.StringValue
+	26, // 7: post.v1.UpdatePostRequest.slug:type_name -> google.protobuf.StringValue
+	25, // 8: post.v1.UpdatePostRequest.published:type_name -> google.protobuf.BoolValue
+	26, // 9: post.v1.UpdatePostRequest.cover_image_url:type_name -> google.protobuf.StringValue
+	26, // 10: post.v1.UpdatePostRequest.excerpt:type_name -> google.protobuf.StringValue
+	8,  // 11: post.v1.DeletePostsResponse.results:type_name -> post.v1.DeletePostResult
+	24, // 12: post.v1.ListPostsRequest.from:type_name -> google.protobuf.Timestamp
+	24, // 13: post.v1.ListPostsRequest.to:type_name -> google.protobuf.Timestamp
+	24, // 14: post.v1.SearchPostsRequest.from:type_name -> google.protobuf.Timestamp
+	24, // 15: post.v1.SearchPostsRequest.to:type_name -> google.protobuf.Timestamp
+	1,  // 16: post.v1.ListPostsResponse.posts:type_name -> post.v1.PostSummary
+	24, // 17: post.v1.PreviewToken.expires_at:type_name -> google.protobuf.Timestamp
+	2,  // 18: post.v1.PostService.CreatePost:input_type -> post.v1.CreatePostRequest
+	4,  // 19: post.v1.PostService.GetPost:input_type -> post.v1.GetPostRequest
+	5,  // 20: post.v1.PostService.GetPostBySlug:input_type -> post.v1.GetPostBySlugRequest
+	3,  // 21: post.v1.PostService.UpdatePost:input_type -> post.v1.UpdatePostRequest
+	6,  // 22: post.v1.PostService.DeletePost:input_type -> post.v1.DeletePostRequest
+	7,  // 23: post.v1.PostService.DeletePosts:input_type -> post.v1.DeletePostsRequest
+	19, // 24: post.v1.PostService.ClonePost:input_type -> post.v1.ClonePostRequest
+	16, // 25: post.v1.PostService.ArchivePost:input_type -> post.v1.ArchivePostRequest
+	17, // 26: post.v1.PostService.LikePost:input_type -> post.v1.LikePostRequest
+	17, // 27: post.v1.PostService.UnlikePost:input_type -> post.v1.LikePostRequest
+	10, // 28: post.v1.PostService.ListPosts:input_type -> post.v1.ListPostsRequest
+	11, // 29: post.v1.PostService.GetUserPosts:input_type -> post.v1.GetUserPostsRequest
+	12, // 30: post.v1.PostService.SearchPosts:input_type -> post.v1.SearchPostsRequest
+	13, // 31: post.v1.PostService.GetStats:input_type -> post.v1.GetStatsRequest
+	20, // 32: post.v1.PostService.CreatePreviewToken:input_type -> post.v1.CreatePreviewTokenRequest
+	22, // 33: post.v1.PostService.RevokePreviewToken:input_type -> post.v1.RevokePreviewTokenRequest
+	23, // 34: post.v1.PostService.GetPostByPreviewToken:input_type -> post.v1.GetPostByPreviewTokenRequest
+	27, // 35: post.v1.PostService.HealthCheck:input_type -> google.protobuf.Empty
+	0,  // 36: post.v1.PostService.CreatePost:output_type -> post.v1.Post
+	0,  // 37: post.v1.PostService.GetPost:output_type -> post.v1.Post
+	0,  // 38: post.v1.PostService.GetPostBySlug:output_type -> post.v1.Post
+	0,  // 39: post.v1.PostService.UpdatePost:output_type -> post.v1.Post
+	27, // 40: post.v1.PostService.DeletePost:output_type -> google.protobuf.Empty
+	9,  // 41: post.v1.PostService.DeletePosts:output_type -> post.v1.DeletePostsResponse
+	0,  // 42: post.v1.PostService.ClonePost:output_type -> post.v1.Post
+	0,  // 43: post.v1.PostService.ArchivePost:output_type -> post.v1.Post
+	18, // 44: post.v1.PostService.LikePost:output_type -> post.v1.PostLikeState
+	18, // 45: post.v1.PostService.UnlikePost:output_type -> post.v1.PostLikeState
+	14, // 46: post.v1.PostService.ListPosts:output_type -> post.v1.ListPostsResponse
+	14, // 47: post.v1.PostService.GetUserPosts:output_type -> post.v1.ListPostsResponse
+	14, // 48: post.v1.PostService.SearchPosts:output_type -> post.v1.ListPostsResponse
+	15, // 49: post.v1.PostService.GetStats:output_type -> post.v1.PostStatsResponse
+	21, // 50: post.v1.PostService.CreatePreviewToken:output_type -> post.v1.PreviewToken
+	27, // 51: post.v1.PostService.RevokePreviewToken:output_type -> google.protobuf.Empty
+	0,  // 52: post.v1.PostService.GetPostByPreviewToken:output_type -> post.v1.Post
+	27, // 53: post.v1.PostService.HealthCheck:output_type -> google.protobuf.Empty
+	36, // [36:54] is the sub-list for method output_type
+	18, // [18:36] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_proto_post_v1_post_proto_init() }
@@ -1041,7 +1948,7 @@ func file_proto_post_v1_post_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_post_v1_post_proto_rawDesc), len(file_proto_post_v1_post_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
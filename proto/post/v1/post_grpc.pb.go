@@ -20,16 +20,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PostService_CreatePost_FullMethodName    = "/post.v1.PostService/CreatePost"
-	PostService_GetPost_FullMethodName       = "/post.v1.PostService/GetPost"
-	PostService_GetPostBySlug_FullMethodName = "/post.v1.PostService/GetPostBySlug"
-	PostService_UpdatePost_FullMethodName    = "/post.v1.PostService/UpdatePost"
-	PostService_DeletePost_FullMethodName    = "/post.v1.PostService/DeletePost"
-	PostService_ListPosts_FullMethodName     = "/post.v1.PostService/ListPosts"
-	PostService_GetUserPosts_FullMethodName  = "/post.v1.PostService/GetUserPosts"
-	PostService_SearchPosts_FullMethodName   = "/post.v1.PostService/SearchPosts"
-	PostService_GetStats_FullMethodName      = "/post.v1.PostService/GetStats"
-	PostService_HealthCheck_FullMethodName   = "/post.v1.PostService/HealthCheck"
+	PostService_CreatePost_FullMethodName            = "/post.v1.PostService/CreatePost"
+	PostService_GetPost_FullMethodName               = "/post.v1.PostService/GetPost"
+	PostService_GetPostBySlug_FullMethodName         = "/post.v1.PostService/GetPostBySlug"
+	PostService_UpdatePost_FullMethodName            = "/post.v1.PostService/UpdatePost"
+	PostService_DeletePost_FullMethodName            = "/post.v1.PostService/DeletePost"
+	PostService_DeletePosts_FullMethodName           = "/post.v1.PostService/DeletePosts"
+	PostService_ClonePost_FullMethodName             = "/post.v1.PostService/ClonePost"
+	PostService_ArchivePost_FullMethodName           = "/post.v1.PostService/ArchivePost"
+	PostService_LikePost_FullMethodName              = "/post.v1.PostService/LikePost"
+	PostService_UnlikePost_FullMethodName            = "/post.v1.PostService/UnlikePost"
+	PostService_ListPosts_FullMethodName             = "/post.v1.PostService/ListPosts"
+	PostService_GetUserPosts_FullMethodName          = "/post.v1.PostService/GetUserPosts"
+	PostService_SearchPosts_FullMethodName           = "/post.v1.PostService/SearchPosts"
+	PostService_GetStats_FullMethodName              = "/post.v1.PostService/GetStats"
+	PostService_CreatePreviewToken_FullMethodName    = "/post.v1.PostService/CreatePreviewToken"
+	PostService_RevokePreviewToken_FullMethodName    = "/post.v1.PostService/RevokePreviewToken"
+	PostService_GetPostByPreviewToken_FullMethodName = "/post.v1.PostService/GetPostByPreviewToken"
+	PostService_HealthCheck_FullMethodName           = "/post.v1.PostService/HealthCheck"
 )
 
 // PostServiceClient is the client API for PostService service.
@@ -41,10 +49,19 @@ type PostServiceClient interface {
 	GetPostBySlug(ctx context.Context, in *GetPostBySlugRequest, opts ...grpc.CallOption) (*Post, error)
 	UpdatePost(ctx context.Context, in *UpdatePostRequest, opts ...grpc.CallOption) (*Post, error)
 	DeletePost(ctx context.Context, in *DeletePostRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeletePosts(ctx context.Context, in *DeletePostsRequest, opts ...grpc.CallOption) (*DeletePostsResponse, error)
+	ClonePost(ctx context.Context, in *ClonePostRequest, opts ...grpc.CallOption) (*Post, error)
+	ArchivePost(ctx context.Context, in *ArchivePostRequest, opts ...grpc.CallOption) (*Post, error)
+	// Liking and unliking are idempotent.
+	LikePost(ctx context.Context, in *LikePostRequest, opts ...grpc.CallOption) (*PostLikeState, error)
+	UnlikePost(ctx context.Context, in *LikePostRequest, opts ...grpc.CallOption) (*PostLikeState, error)
 	ListPosts(ctx context.Context, in *ListPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error)
 	GetUserPosts(ctx context.Context, in *GetUserPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error)
 	SearchPosts(ctx context.Context, in *SearchPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error)
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*PostStatsResponse, error)
+	CreatePreviewToken(ctx context.Context, in *CreatePreviewTokenRequest, opts ...grpc.CallOption) (*PreviewToken, error)
+	RevokePreviewToken(ctx context.Context, in *RevokePreviewTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetPostByPreviewToken(ctx context.Context, in *GetPostByPreviewTokenRequest, opts ...grpc.CallOption) (*Post, error)
 	HealthCheck(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
@@ -106,6 +123,56 @@ func (c *postServiceClient) DeletePost(ctx context.Context, in *DeletePostReques
 	return out, nil
 }
 
+func (c *postServiceClient) DeletePosts(ctx context.Context, in *DeletePostsRequest, opts ...grpc.CallOption) (*DeletePostsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePostsResponse)
+	err := c.cc.Invoke(ctx, PostService_DeletePosts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postServiceClient) ClonePost(ctx context.Context, in *ClonePostRequest, opts ...grpc.CallOption) (*Post, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Post)
+	err := c.cc.Invoke(ctx, PostService_ClonePost_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postServiceClient) ArchivePost(ctx context.Context, in *ArchivePostRequest, opts ...grpc.CallOption) (*Post, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Post)
+	err := c.cc.Invoke(ctx, PostService_ArchivePost_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postServiceClient) LikePost(ctx context.Context, in *LikePostRequest, opts ...grpc.CallOption) (*PostLikeState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PostLikeState)
+	err := c.cc.Invoke(ctx, PostService_LikePost_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postServiceClient) UnlikePost(ctx context.Context, in *LikePostRequest, opts ...grpc.CallOption) (*PostLikeState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PostLikeState)
+	err := c.cc.Invoke(ctx, PostService_UnlikePost_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *postServiceClient) ListPosts(ctx context.Context, in *ListPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListPostsResponse)
@@ -146,6 +213,36 @@ func (c *postServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, o
 	return out, nil
 }
 
+func (c *postServiceClient) CreatePreviewToken(ctx context.Context, in *CreatePreviewTokenRequest, opts ...grpc.CallOption) (*PreviewToken, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PreviewToken)
+	err := c.cc.Invoke(ctx, PostService_CreatePreviewToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postServiceClient) RevokePreviewToken(ctx context.Context, in *RevokePreviewTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PostService_RevokePreviewToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postServiceClient) GetPostByPreviewToken(ctx context.Context, in *GetPostByPreviewTokenRequest, opts ...grpc.CallOption) (*Post, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Post)
+	err := c.cc.Invoke(ctx, PostService_GetPostByPreviewToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *postServiceClient) HealthCheck(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -165,10 +262,19 @@ type PostServiceServer interface {
 	GetPostBySlug(context.Context, *GetPostBySlugRequest) (*Post, error)
 	UpdatePost(context.Context, *UpdatePostRequest) (*Post, error)
 	DeletePost(context.Context, *DeletePostRequest) (*emptypb.Empty, error)
+	DeletePosts(context.Context, *DeletePostsRequest) (*DeletePostsResponse, error)
+	ClonePost(context.Context, *ClonePostRequest) (*Post, error)
+	ArchivePost(context.Context, *ArchivePostRequest) (*Post, error)
+	// Liking and unliking are idempotent.
+	LikePost(context.Context, *LikePostRequest) (*PostLikeState, error)
+	UnlikePost(context.Context, *LikePostRequest) (*PostLikeState, error)
 	ListPosts(context.Context, *ListPostsRequest) (*ListPostsResponse, error)
 	GetUserPosts(context.Context, *GetUserPostsRequest) (*ListPostsResponse, error)
 	SearchPosts(context.Context, *SearchPostsRequest) (*ListPostsResponse, error)
 	GetStats(context.Context, *GetStatsRequest) (*PostStatsResponse, error)
+	CreatePreviewToken(context.Context, *CreatePreviewTokenRequest) (*PreviewToken, error)
+	RevokePreviewToken(context.Context, *RevokePreviewTokenRequest) (*emptypb.Empty, error)
+	GetPostByPreviewToken(context.Context, *GetPostByPreviewTokenRequest) (*Post, error)
 	HealthCheck(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	mustEmbedUnimplementedPostServiceServer()
 }
@@ -195,6 +301,21 @@ func (UnimplementedPostServiceServer) UpdatePost(context.Context, *UpdatePostReq
 func (UnimplementedPostServiceServer) DeletePost(context.Context, *DeletePostRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeletePost not implemented")
 }
+func (UnimplementedPostServiceServer) DeletePosts(context.Context, *DeletePostsRequest) (*DeletePostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePosts not implemented")
+}
+func (UnimplementedPostServiceServer) ClonePost(context.Context, *ClonePostRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClonePost not implemented")
+}
+func (UnimplementedPostServiceServer) ArchivePost(context.Context, *ArchivePostRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchivePost not implemented")
+}
+func (UnimplementedPostServiceServer) LikePost(context.Context, *LikePostRequest) (*PostLikeState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LikePost not implemented")
+}
+func (UnimplementedPostServiceServer) UnlikePost(context.Context, *LikePostRequest) (*PostLikeState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlikePost not implemented")
+}
 func (UnimplementedPostServiceServer) ListPosts(context.Context, *ListPostsRequest) (*ListPostsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListPosts not implemented")
 }
@@ -207,6 +328,15 @@ func (UnimplementedPostServiceServer) SearchPosts(context.Context, *SearchPostsR
 func (UnimplementedPostServiceServer) GetStats(context.Context, *GetStatsRequest) (*PostStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
 }
+func (UnimplementedPostServiceServer) CreatePreviewToken(context.Context, *CreatePreviewTokenRequest) (*PreviewToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePreviewToken not implemented")
+}
+func (UnimplementedPostServiceServer) RevokePreviewToken(context.Context, *RevokePreviewTokenRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokePreviewToken not implemented")
+}
+func (UnimplementedPostServiceServer) GetPostByPreviewToken(context.Context, *GetPostByPreviewTokenRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPostByPreviewToken not implemented")
+}
 func (UnimplementedPostServiceServer) HealthCheck(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
@@ -321,6 +451,96 @@ func _PostService_DeletePost_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PostService_DeletePosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).DeletePosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PostService_DeletePosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).DeletePosts(ctx, req.(*DeletePostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostService_ClonePost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClonePostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).ClonePost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PostService_ClonePost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).ClonePost(ctx, req.(*ClonePostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostService_ArchivePost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchivePostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).ArchivePost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PostService_ArchivePost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).ArchivePost(ctx, req.(*ArchivePostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostService_LikePost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LikePostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).LikePost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PostService_LikePost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).LikePost(ctx, req.(*LikePostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostService_UnlikePost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LikePostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).UnlikePost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PostService_UnlikePost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).UnlikePost(ctx, req.(*LikePostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PostService_ListPosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPostsRequest)
 	if err := dec(in); err != nil {
@@ -393,6 +613,60 @@ func _PostService_GetStats_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PostService_CreatePreviewToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePreviewTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).CreatePreviewToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PostService_CreatePreviewToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).CreatePreviewToken(ctx, req.(*CreatePreviewTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostService_RevokePreviewToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokePreviewTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).RevokePreviewToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PostService_RevokePreviewToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).RevokePreviewToken(ctx, req.(*RevokePreviewTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostService_GetPostByPreviewToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPostByPreviewTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostServiceServer).GetPostByPreviewToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PostService_GetPostByPreviewToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostServiceServer).GetPostByPreviewToken(ctx, req.(*GetPostByPreviewTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PostService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -438,6 +712,26 @@ var PostService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeletePost",
 			Handler:    _PostService_DeletePost_Handler,
 		},
+		{
+			MethodName: "DeletePosts",
+			Handler:    _PostService_DeletePosts_Handler,
+		},
+		{
+			MethodName: "ClonePost",
+			Handler:    _PostService_ClonePost_Handler,
+		},
+		{
+			MethodName: "ArchivePost",
+			Handler:    _PostService_ArchivePost_Handler,
+		},
+		{
+			MethodName: "LikePost",
+			Handler:    _PostService_LikePost_Handler,
+		},
+		{
+			MethodName: "UnlikePost",
+			Handler:    _PostService_UnlikePost_Handler,
+		},
 		{
 			MethodName: "ListPosts",
 			Handler:    _PostService_ListPosts_Handler,
@@ -454,6 +748,18 @@ var PostService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStats",
 			Handler:    _PostService_GetStats_Handler,
 		},
+		{
+			MethodName: "CreatePreviewToken",
+			Handler:    _PostService_CreatePreviewToken_Handler,
+		},
+		{
+			MethodName: "RevokePreviewToken",
+			Handler:    _PostService_RevokePreviewToken_Handler,
+		},
+		{
+			MethodName: "GetPostByPreviewToken",
+			Handler:    _PostService_GetPostByPreviewToken_Handler,
+		},
 		{
 			MethodName: "HealthCheck",
 			Handler:    _PostService_HealthCheck_Handler,
@@ -0,0 +1,97 @@
+// Package requestid carries the correlation ID that ties one client request
+// to the log lines it produces in the gateway and every downstream service.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Header is the HTTP header that carries the ID in and out of the gateway.
+	Header = "X-Request-ID"
+	// MetadataKey is the gRPC metadata key downstream services read it from.
+	MetadataKey = "x-request-id"
+
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the ID stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New generates a random 128-bit ID.
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether a client-supplied ID is safe to reuse: non-empty, at
+// most 128 characters, and limited to letters, digits, '-', '_' and '.' so it
+// cannot forge log lines or smuggle header values downstream.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// FromIncomingContext returns the ID the caller attached as x-request-id
+// metadata, or "" if there is none. Invalid values are dropped rather than
+// logged.
+func FromIncomingContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(MetadataKey)
+	if len(values) == 0 || !Valid(values[0]) {
+		return ""
+	}
+	return values[0]
+}
+
+// UnaryServerInterceptor stores the incoming x-request-id in the handler's
+// context, where FromContext finds it.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if id := FromIncomingContext(ctx); id != "" {
+			ctx = NewContext(ctx, id)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// UnaryClientInterceptor forwards the ID stored in the call's context to the
+// next service as x-request-id metadata.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id := FromContext(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,64 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryClientInterceptorForwardsMetadata(t *testing.T) {
+	var forwarded []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		forwarded = md.Get(MetadataKey)
+		return nil
+	}
+
+	ctx := NewContext(context.Background(), "trace-789")
+	if err := UnaryClientInterceptor()(ctx, "/test", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forwarded) != 1 || forwarded[0] != "trace-789" {
+		t.Fatalf("expected x-request-id trace-789, got %v", forwarded)
+	}
+
+	forwarded = nil
+	if err := UnaryClientInterceptor()(context.Background(), "/test", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forwarded) != 0 {
+		t.Fatalf("expected no metadata without a request ID, got %v", forwarded)
+	}
+}
+
+func TestUnaryServerInterceptorStoresValidID(t *testing.T) {
+	cases := []struct {
+		name string
+		md   metadata.MD
+		want string
+	}{
+		{"forwarded", metadata.Pairs(MetadataKey, "trace-789"), "trace-789"},
+		{"missing", metadata.MD{}, ""},
+		{"forged log line", metadata.Pairs(MetadataKey, "abc\nlevel=error"), ""},
+		{"too long", metadata.Pairs(MetadataKey, strings.Repeat("a", maxLength+1)), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				got = FromContext(ctx)
+				return nil, nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+			if _, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q in the handler context, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -30,8 +30,11 @@ type SearchRequest struct {
 	PostsLimit       int32                  `protobuf:"varint,4,opt,name=posts_limit,json=postsLimit,proto3" json:"posts_limit,omitempty"`
 	UsersCursor      string                 `protobuf:"bytes,5,opt,name=users_cursor,json=usersCursor,proto3" json:"users_cursor,omitempty"`
 	PostsCursor      string                 `protobuf:"bytes,6,opt,name=posts_cursor,json=postsCursor,proto3" json:"posts_cursor,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// include_content returns each post's full content in addition to the
+	// preview. Content searches are limited to a smaller page size.
+	IncludeContent bool `protobuf:"varint,7,opt,name=include_content,json=includeContent,proto3" json:"include_content,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *SearchRequest) Reset() {
@@ -106,6 +109,13 @@ func (x *SearchRequest) GetPostsCursor() string {
 	return ""
 }
 
+func (x *SearchRequest) GetIncludeContent() bool {
+	if x != nil {
+		return x.IncludeContent
+	}
+	return false
+}
+
 type SearchUserHit struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -182,6 +192,7 @@ type SearchPostHit struct {
 	Slug           string                 `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
 	ContentPreview string                 `protobuf:"bytes,5,opt,name=content_preview,json=contentPreview,proto3" json:"content_preview,omitempty"`
 	Published      bool                   `protobuf:"varint,6,opt,name=published,proto3" json:"published,omitempty"`
+	Content        string                 `protobuf:"bytes,7,opt,name=content,proto3" json:"content,omitempty"` // set only when include_content was requested
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -258,6 +269,13 @@ func (x *SearchPostHit) GetPublished() bool {
 	return false
 }
 
+func (x *SearchPostHit) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
 type SearchResponse struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	Users           []*SearchUserHit       `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
@@ -346,7 +364,7 @@ var File_search_v1_search_proto protoreflect.FileDescriptor
 
 const file_search_v1_search_proto_rawDesc = "" +
 	"\n" +
-	"\x16search/v1/search.proto\x12\tsearch.v1\x1a\x1bgoogle/protobuf/empty.proto\"\xdb\x01\n" +
+	"\x16search/v1/search.proto\x12\tsearch.v1\x1a\x1bgoogle/protobuf/empty.proto\"\x84\x02\n" +
 	"\rSearchRequest\x12\x14\n" +
 	"\x05query\x18\x01 \x01(\tR\x05query\x12,\n" +
 	"\x12requesting_user_id\x18\x02 \x01(\tR\x10requestingUserId\x12\x1f\n" +
@@ -355,19 +373,21 @@ const file_search_v1_search_proto_rawDesc = "" +
 	"\vposts_limit\x18\x04 \x01(\x05R\n" +
 	"postsLimit\x12!\n" +
 	"\fusers_cursor\x18\x05 \x01(\tR\vusersCursor\x12!\n" +
-	"\fposts_cursor\x18\x06 \x01(\tR\vpostsCursor\"_\n" +
+	"\fposts_cursor\x18\x06 \x01(\tR\vpostsCursor\x12'\n" +
+	"\x0finclude_content\x18\a \x01(\bR\x0eincludeContent\"_\n" +
 	"\rSearchUserHit\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
 	"\apicture\x18\x03 \x01(\tR\apicture\x12\x10\n" +
-	"\x03bio\x18\x04 \x01(\tR\x03bio\"\xa9\x01\n" +
+	"\x03bio\x18\x04 \x01(\tR\x03bio\"\xc3\x01\n" +
 	"\rSearchPostHit\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05title\x18\x03 \x01(\tR\x05title\x12\x12\n" +
 	"\x04slug\x18\x04 \x01(\tR\x04slug\x12'\n" +
 	"\x0fcontent_preview\x18\x05 \x01(\tR\x0econtentPreview\x12\x1c\n" +
-	"\tpublished\x18\x06 \x01(\bR\tpublished\"\x92\x02\n" +
+	"\tpublished\x18\x06 \x01(\bR\tpublished\x12\x18\n" +
+	"\acontent\x18\a \x01(\tR\acontent\"\x92\x02\n" +
 	"\x0eSearchResponse\x12.\n" +
 	"\x05users\x18\x01 \x03(\v2\x18.search.v1.SearchUserHitR\x05users\x12.\n" +
 	"\x05posts\x18\x02 \x03(\v2\x18.search.v1.SearchPostHitR\x05posts\x12*\n" +
@@ -30,7 +30,9 @@ type CreateUserRequest struct {
 	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
 	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
 	Picture       string                 `protobuf:"bytes,4,opt,name=picture,proto3" json:"picture,omitempty"`
-	Password      string                 `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"` // optional; for email/password signup only
+	Password      string                 `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`                                 // optional; for email/password signup only
+	Username      string                 `protobuf:"bytes,6,opt,name=username,proto3" json:"username,omitempty"`                                 // optional; lowercase [a-z0-9_], 3-30 chars, unique
+	EmailVerified bool                   `protobuf:"varint,7,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"` // set by auth-service when the identity provider verified the email
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -100,6 +102,20 @@ func (x *CreateUserRequest) GetPassword() string {
 	return ""
 }
 
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
 type UpdateUserRequest struct {
 	state         protoimpl.MessageState  `protogen:"open.v1"`
 	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -109,6 +125,7 @@ type UpdateUserRequest struct {
 	Location      *wrapperspb.StringValue `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
 	Website       *wrapperspb.StringValue `protobuf:"bytes,6,opt,name=website,proto3" json:"website,omitempty"`
 	ActorId       string                  `protobuf:"bytes,7,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Username      *wrapperspb.StringValue `protobuf:"bytes,8,opt,name=username,proto3" json:"username,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -192,10 +209,19 @@ func (x *UpdateUserRequest) GetActorId() string {
 	return ""
 }
 
+func (x *UpdateUserRequest) GetUsername() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Username
+	}
+	return nil
+}
+
 type DeleteUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	// Role of the actor from validated token claims; "admin" may delete any user.
+	ActorRole     string `protobuf:"bytes,3,opt,name=actor_role,json=actorRole,proto3" json:"actor_role,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -244,6 +270,135 @@ func (x *DeleteUserRequest) GetActorId() string {
 	return ""
 }
 
+func (x *DeleteUserRequest) GetActorRole() string {
+	if x != nil {
+		return x.ActorRole
+	}
+	return ""
+}
+
+// DeactivateUserRequest hides a user until reactivated. Self or admin only.
+type DeactivateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorRole     string                 `protobuf:"bytes,3,opt,name=actor_role,json=actorRole,proto3" json:"actor_role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateUserRequest) Reset() {
+	*x = DeactivateUserRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateUserRequest) ProtoMessage() {}
+
+func (x *DeactivateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateUserRequest.ProtoReflect.Descriptor instead.
+func (*DeactivateUserRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DeactivateUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeactivateUserRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *DeactivateUserRequest) GetActorRole() string {
+	if x != nil {
+		return x.ActorRole
+	}
+	return ""
+}
+
+// ReactivateUserRequest restores a deactivated user. Self or admin only.
+type ReactivateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorRole     string                 `protobuf:"bytes,3,opt,name=actor_role,json=actorRole,proto3" json:"actor_role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReactivateUserRequest) Reset() {
+	*x = ReactivateUserRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactivateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactivateUserRequest) ProtoMessage() {}
+
+func (x *ReactivateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactivateUserRequest.ProtoReflect.Descriptor instead.
+func (*ReactivateUserRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReactivateUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReactivateUserRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *ReactivateUserRequest) GetActorRole() string {
+	if x != nil {
+		return x.ActorRole
+	}
+	return ""
+}
+
 type GetUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -253,7 +408,7 @@ type GetUserRequest struct {
 
 func (x *GetUserRequest) Reset() {
 	*x = GetUserRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[3]
+	mi := &file_user_v1_user_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -265,7 +420,7 @@ func (x *GetUserRequest) String() string {
 func (*GetUserRequest) ProtoMessage() {}
 
 func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[3]
+	mi := &file_user_v1_user_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -278,7 +433,7 @@ func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
 func (*GetUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{3}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetUserRequest) GetId() string {
@@ -289,15 +444,19 @@ func (x *GetUserRequest) GetId() string {
 }
 
 type GetUserByEmailRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Email string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// Sign-in flows set this so an account its owner deactivated is
+	// reactivated and returned rather than reported not found. An account an
+	// admin deactivated is PermissionDenied (ACCOUNT_DEACTIVATED).
+	Reactivate    bool `protobuf:"varint,2,opt,name=reactivate,proto3" json:"reactivate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetUserByEmailRequest) Reset() {
 	*x = GetUserByEmailRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[4]
+	mi := &file_user_v1_user_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -309,7 +468,7 @@ func (x *GetUserByEmailRequest) String() string {
 func (*GetUserByEmailRequest) ProtoMessage() {}
 
 func (x *GetUserByEmailRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[4]
+	mi := &file_user_v1_user_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -322,7 +481,7 @@ func (x *GetUserByEmailRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserByEmailRequest.ProtoReflect.Descriptor instead.
 func (*GetUserByEmailRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{4}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetUserByEmailRequest) GetEmail() string {
@@ -332,6 +491,13 @@ func (x *GetUserByEmailRequest) GetEmail() string {
 	return ""
 }
 
+func (x *GetUserByEmailRequest) GetReactivate() bool {
+	if x != nil {
+		return x.Reactivate
+	}
+	return false
+}
+
 type GetUserProfileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -341,7 +507,7 @@ type GetUserProfileRequest struct {
 
 func (x *GetUserProfileRequest) Reset() {
 	*x = GetUserProfileRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[5]
+	mi := &file_user_v1_user_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -353,7 +519,7 @@ func (x *GetUserProfileRequest) String() string {
 func (*GetUserProfileRequest) ProtoMessage() {}
 
 func (x *GetUserProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[5]
+	mi := &file_user_v1_user_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,7 +532,7 @@ func (x *GetUserProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserProfileRequest.ProtoReflect.Descriptor instead.
 func (*GetUserProfileRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{5}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetUserProfileRequest) GetId() string {
@@ -376,17 +542,66 @@ func (x *GetUserProfileRequest) GetId() string {
 	return ""
 }
 
-type ListUsersRequest struct {
+type GetUserByUsernameRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *GetUserByUsernameRequest) Reset() {
+	*x = GetUserByUsernameRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserByUsernameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserByUsernameRequest) ProtoMessage() {}
+
+func (x *GetUserByUsernameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserByUsernameRequest.ProtoReflect.Descriptor instead.
+func (*GetUserByUsernameRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetUserByUsernameRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ListUsersRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Limit           int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset          int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	IncludeInactive bool                   `protobuf:"varint,3,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"` // admin listing only
+	Sort            string                 `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`                                               // created_at (default), name or email
+	Order           string                 `protobuf:"bytes,5,opt,name=order,proto3" json:"order,omitempty"`                                             // asc or desc; desc for created_at and asc otherwise by default
+	CreatedAfter    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`           // inclusive
+	CreatedBefore   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`        // exclusive
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *ListUsersRequest) Reset() {
 	*x = ListUsersRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[6]
+	mi := &file_user_v1_user_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -398,7 +613,7 @@ func (x *ListUsersRequest) String() string {
 func (*ListUsersRequest) ProtoMessage() {}
 
 func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[6]
+	mi := &file_user_v1_user_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -411,7 +626,7 @@ func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
 func (*ListUsersRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{6}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ListUsersRequest) GetLimit() int32 {
@@ -428,6 +643,41 @@ func (x *ListUsersRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *ListUsersRequest) GetIncludeInactive() bool {
+	if x != nil {
+		return x.IncludeInactive
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListUsersRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
 type SearchUsersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
@@ -439,7 +689,7 @@ type SearchUsersRequest struct {
 
 func (x *SearchUsersRequest) Reset() {
 	*x = SearchUsersRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[7]
+	mi := &file_user_v1_user_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -451,7 +701,7 @@ func (x *SearchUsersRequest) String() string {
 func (*SearchUsersRequest) ProtoMessage() {}
 
 func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[7]
+	mi := &file_user_v1_user_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -464,7 +714,7 @@ func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
 func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{7}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *SearchUsersRequest) GetQuery() string {
@@ -489,24 +739,33 @@ func (x *SearchUsersRequest) GetOffset() int32 {
 }
 
 type User struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Picture       string                 `protobuf:"bytes,4,opt,name=picture,proto3" json:"picture,omitempty"`
-	Bio           string                 `protobuf:"bytes,5,opt,name=bio,proto3" json:"bio,omitempty"`
-	Location      string                 `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
-	Website       string                 `protobuf:"bytes,7,opt,name=website,proto3" json:"website,omitempty"`
-	IsActive      bool                   `protobuf:"varint,8,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// PII. Only populated for owner/internal paths (GetUser of self, GetUserByEmail,
+	// ValidateCredentials, Create/Update). The gateway strips it for cross-user reads.
+	// Never populate it for list/search results (see toProtoListUsers).
+	Email           string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name            string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Picture         string                 `protobuf:"bytes,4,opt,name=picture,proto3" json:"picture,omitempty"`
+	Bio             string                 `protobuf:"bytes,5,opt,name=bio,proto3" json:"bio,omitempty"`
+	Location        string                 `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
+	Website         string                 `protobuf:"bytes,7,opt,name=website,proto3" json:"website,omitempty"`
+	IsActive        bool                   `protobuf:"varint,8,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Username        string                 `protobuf:"bytes,11,opt,name=username,proto3" json:"username,omitempty"`
+	Role            string                 `protobuf:"bytes,12,opt,name=role,proto3" json:"role,omitempty"` // "user" or "admin"
+	EmailVerified   bool                   `protobuf:"varint,13,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
+	EmailVerifiedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=email_verified_at,json=emailVerifiedAt,proto3" json:"email_verified_at,omitempty"`
+	LastSeenAt      *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	Status          string                 `protobuf:"bytes,16,opt,name=status,proto3" json:"status,omitempty"` // "active", "deactivated" or "deleted"
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *User) Reset() {
 	*x = User{}
-	mi := &file_user_v1_user_proto_msgTypes[8]
+	mi := &file_user_v1_user_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -518,7 +777,7 @@ func (x *User) String() string {
 func (*User) ProtoMessage() {}
 
 func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[8]
+	mi := &file_user_v1_user_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -531,7 +790,7 @@ func (x *User) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use User.ProtoReflect.Descriptor instead.
 func (*User) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{8}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *User) GetId() string {
@@ -604,36 +863,82 @@ func (x *User) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-type UserProfile struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Picture       string                 `protobuf:"bytes,4,opt,name=picture,proto3" json:"picture,omitempty"`
-	Bio           string                 `protobuf:"bytes,5,opt,name=bio,proto3" json:"bio,omitempty"`
-	Location      string                 `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
-	Website       string                 `protobuf:"bytes,7,opt,name=website,proto3" json:"website,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
 }
 
-func (x *UserProfile) Reset() {
-	*x = UserProfile{}
-	mi := &file_user_v1_user_proto_msgTypes[9]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *User) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
 }
 
-func (x *UserProfile) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *User) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
 }
 
-func (*UserProfile) ProtoMessage() {}
+func (x *User) GetEmailVerifiedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EmailVerifiedAt
+	}
+	return nil
+}
 
-func (x *UserProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[9]
+func (x *User) GetLastSeenAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		return x.LastSeenAt
+	}
+	return nil
+}
+
+func (x *User) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type UserProfile struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Deprecated/unused: UserProfile is the public discovery view (public profile,
+	// search, follower/following lists) and must never expose email. Left in place
+	// for wire compatibility; do not populate it.
+	Email         string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Picture       string `protobuf:"bytes,4,opt,name=picture,proto3" json:"picture,omitempty"`
+	Bio           string `protobuf:"bytes,5,opt,name=bio,proto3" json:"bio,omitempty"`
+	Location      string `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
+	Website       string `protobuf:"bytes,7,opt,name=website,proto3" json:"website,omitempty"`
+	Username      string `protobuf:"bytes,8,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserProfile) Reset() {
+	*x = UserProfile{}
+	mi := &file_user_v1_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserProfile) ProtoMessage() {}
+
+func (x *UserProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
@@ -644,7 +949,7 @@ func (x *UserProfile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserProfile.ProtoReflect.Descriptor instead.
 func (*UserProfile) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{9}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *UserProfile) GetId() string {
@@ -696,19 +1001,27 @@ func (x *UserProfile) GetWebsite() string {
 	return ""
 }
 
+func (x *UserProfile) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
 type ListUsersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
 	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
 	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListUsersResponse) Reset() {
 	*x = ListUsersResponse{}
-	mi := &file_user_v1_user_proto_msgTypes[10]
+	mi := &file_user_v1_user_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -720,7 +1033,7 @@ func (x *ListUsersResponse) String() string {
 func (*ListUsersResponse) ProtoMessage() {}
 
 func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[10]
+	mi := &file_user_v1_user_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -733,7 +1046,7 @@ func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
 func (*ListUsersResponse) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{10}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ListUsersResponse) GetUsers() []*User {
@@ -764,6 +1077,13 @@ func (x *ListUsersResponse) GetTotal() int32 {
 	return 0
 }
 
+func (x *ListUsersResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
 type UserStatsResponse struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	TotalActiveUsers int64                  `protobuf:"varint,1,opt,name=total_active_users,json=totalActiveUsers,proto3" json:"total_active_users,omitempty"`
@@ -773,7 +1093,7 @@ type UserStatsResponse struct {
 
 func (x *UserStatsResponse) Reset() {
 	*x = UserStatsResponse{}
-	mi := &file_user_v1_user_proto_msgTypes[11]
+	mi := &file_user_v1_user_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -785,7 +1105,7 @@ func (x *UserStatsResponse) String() string {
 func (*UserStatsResponse) ProtoMessage() {}
 
 func (x *UserStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[11]
+	mi := &file_user_v1_user_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -798,7 +1118,7 @@ func (x *UserStatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserStatsResponse.ProtoReflect.Descriptor instead.
 func (*UserStatsResponse) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{11}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *UserStatsResponse) GetTotalActiveUsers() int64 {
@@ -818,7 +1138,7 @@ type FollowRequest struct {
 
 func (x *FollowRequest) Reset() {
 	*x = FollowRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[12]
+	mi := &file_user_v1_user_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -830,7 +1150,7 @@ func (x *FollowRequest) String() string {
 func (*FollowRequest) ProtoMessage() {}
 
 func (x *FollowRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[12]
+	mi := &file_user_v1_user_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -843,7 +1163,7 @@ func (x *FollowRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FollowRequest.ProtoReflect.Descriptor instead.
 func (*FollowRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{12}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *FollowRequest) GetFollowerId() string {
@@ -870,7 +1190,7 @@ type UnfollowRequest struct {
 
 func (x *UnfollowRequest) Reset() {
 	*x = UnfollowRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[13]
+	mi := &file_user_v1_user_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -882,7 +1202,7 @@ func (x *UnfollowRequest) String() string {
 func (*UnfollowRequest) ProtoMessage() {}
 
 func (x *UnfollowRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[13]
+	mi := &file_user_v1_user_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -895,7 +1215,7 @@ func (x *UnfollowRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnfollowRequest.ProtoReflect.Descriptor instead.
 func (*UnfollowRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{13}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *UnfollowRequest) GetFollowerId() string {
@@ -923,7 +1243,7 @@ type GetFollowersRequest struct {
 
 func (x *GetFollowersRequest) Reset() {
 	*x = GetFollowersRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[14]
+	mi := &file_user_v1_user_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -935,7 +1255,7 @@ func (x *GetFollowersRequest) String() string {
 func (*GetFollowersRequest) ProtoMessage() {}
 
 func (x *GetFollowersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[14]
+	mi := &file_user_v1_user_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -948,7 +1268,7 @@ func (x *GetFollowersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFollowersRequest.ProtoReflect.Descriptor instead.
 func (*GetFollowersRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{14}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetFollowersRequest) GetUserId() string {
@@ -983,7 +1303,7 @@ type GetFollowingRequest struct {
 
 func (x *GetFollowingRequest) Reset() {
 	*x = GetFollowingRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[15]
+	mi := &file_user_v1_user_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -995,7 +1315,7 @@ func (x *GetFollowingRequest) String() string {
 func (*GetFollowingRequest) ProtoMessage() {}
 
 func (x *GetFollowingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[15]
+	mi := &file_user_v1_user_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1008,7 +1328,7 @@ func (x *GetFollowingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFollowingRequest.ProtoReflect.Descriptor instead.
 func (*GetFollowingRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{15}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GetFollowingRequest) GetUserId() string {
@@ -1036,13 +1356,14 @@ type ListFollowResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Users         []*UserProfile         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
 	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"` // all followers (GetFollowers) or followees (GetFollowing)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListFollowResponse) Reset() {
 	*x = ListFollowResponse{}
-	mi := &file_user_v1_user_proto_msgTypes[16]
+	mi := &file_user_v1_user_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1054,7 +1375,7 @@ func (x *ListFollowResponse) String() string {
 func (*ListFollowResponse) ProtoMessage() {}
 
 func (x *ListFollowResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[16]
+	mi := &file_user_v1_user_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1067,7 +1388,7 @@ func (x *ListFollowResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListFollowResponse.ProtoReflect.Descriptor instead.
 func (*ListFollowResponse) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{16}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListFollowResponse) GetUsers() []*UserProfile {
@@ -1084,6 +1405,13 @@ func (x *ListFollowResponse) GetNextCursor() string {
 	return ""
 }
 
+func (x *ListFollowResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
 type AreFollowedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FollowerId    string                 `protobuf:"bytes,1,opt,name=follower_id,json=followerId,proto3" json:"follower_id,omitempty"`
@@ -1094,7 +1422,7 @@ type AreFollowedRequest struct {
 
 func (x *AreFollowedRequest) Reset() {
 	*x = AreFollowedRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[17]
+	mi := &file_user_v1_user_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1106,7 +1434,7 @@ func (x *AreFollowedRequest) String() string {
 func (*AreFollowedRequest) ProtoMessage() {}
 
 func (x *AreFollowedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[17]
+	mi := &file_user_v1_user_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1119,7 +1447,7 @@ func (x *AreFollowedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AreFollowedRequest.ProtoReflect.Descriptor instead.
 func (*AreFollowedRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{17}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *AreFollowedRequest) GetFollowerId() string {
@@ -1145,7 +1473,7 @@ type AreFollowedResponse struct {
 
 func (x *AreFollowedResponse) Reset() {
 	*x = AreFollowedResponse{}
-	mi := &file_user_v1_user_proto_msgTypes[18]
+	mi := &file_user_v1_user_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1157,7 +1485,7 @@ func (x *AreFollowedResponse) String() string {
 func (*AreFollowedResponse) ProtoMessage() {}
 
 func (x *AreFollowedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[18]
+	mi := &file_user_v1_user_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1170,7 +1498,7 @@ func (x *AreFollowedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AreFollowedResponse.ProtoReflect.Descriptor instead.
 func (*AreFollowedResponse) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{18}
+	return file_user_v1_user_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *AreFollowedResponse) GetFollowedIds() []string {
@@ -1180,29 +1508,31 @@ func (x *AreFollowedResponse) GetFollowedIds() []string {
 	return nil
 }
 
-type ValidateCredentialsRequest struct {
+// BlockRequest stops blocked_id from following blocker_id and leaves blocker_id
+// out of blocked_id's follower fan-out.
+type BlockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	BlockerId     string                 `protobuf:"bytes,1,opt,name=blocker_id,json=blockerId,proto3" json:"blocker_id,omitempty"`
+	BlockedId     string                 `protobuf:"bytes,2,opt,name=blocked_id,json=blockedId,proto3" json:"blocked_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ValidateCredentialsRequest) Reset() {
-	*x = ValidateCredentialsRequest{}
-	mi := &file_user_v1_user_proto_msgTypes[19]
+func (x *BlockRequest) Reset() {
+	*x = BlockRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateCredentialsRequest) String() string {
+func (x *BlockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateCredentialsRequest) ProtoMessage() {}
+func (*BlockRequest) ProtoMessage() {}
 
-func (x *ValidateCredentialsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[19]
+func (x *BlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1213,50 +1543,48 @@ func (x *ValidateCredentialsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateCredentialsRequest.ProtoReflect.Descriptor instead.
-func (*ValidateCredentialsRequest) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use BlockRequest.ProtoReflect.Descriptor instead.
+func (*BlockRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ValidateCredentialsRequest) GetEmail() string {
+func (x *BlockRequest) GetBlockerId() string {
 	if x != nil {
-		return x.Email
+		return x.BlockerId
 	}
 	return ""
 }
 
-func (x *ValidateCredentialsRequest) GetPassword() string {
+func (x *BlockRequest) GetBlockedId() string {
 	if x != nil {
-		return x.Password
+		return x.BlockedId
 	}
 	return ""
 }
 
-type ValidateCredentialsResponse struct {
+type UnblockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Picture       string                 `protobuf:"bytes,4,opt,name=picture,proto3" json:"picture,omitempty"`
+	BlockerId     string                 `protobuf:"bytes,1,opt,name=blocker_id,json=blockerId,proto3" json:"blocker_id,omitempty"`
+	BlockedId     string                 `protobuf:"bytes,2,opt,name=blocked_id,json=blockedId,proto3" json:"blocked_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ValidateCredentialsResponse) Reset() {
-	*x = ValidateCredentialsResponse{}
-	mi := &file_user_v1_user_proto_msgTypes[20]
+func (x *UnblockRequest) Reset() {
+	*x = UnblockRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateCredentialsResponse) String() string {
+func (x *UnblockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateCredentialsResponse) ProtoMessage() {}
+func (*UnblockRequest) ProtoMessage() {}
 
-func (x *ValidateCredentialsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_v1_user_proto_msgTypes[20]
+func (x *UnblockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1267,150 +1595,841 @@ func (x *ValidateCredentialsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateCredentialsResponse.ProtoReflect.Descriptor instead.
-func (*ValidateCredentialsResponse) Descriptor() ([]byte, []int) {
-	return file_user_v1_user_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use UnblockRequest.ProtoReflect.Descriptor instead.
+func (*UnblockRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *ValidateCredentialsResponse) GetId() string {
+func (x *UnblockRequest) GetBlockerId() string {
 	if x != nil {
-		return x.Id
+		return x.BlockerId
 	}
 	return ""
 }
 
-func (x *ValidateCredentialsResponse) GetEmail() string {
+func (x *UnblockRequest) GetBlockedId() string {
 	if x != nil {
-		return x.Email
+		return x.BlockedId
 	}
 	return ""
 }
 
-func (x *ValidateCredentialsResponse) GetName() string {
+type ListBlockedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedRequest) Reset() {
+	*x = ListBlockedRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedRequest) ProtoMessage() {}
+
+func (x *ListBlockedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[24]
 	if x != nil {
-		return x.Name
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockedRequest.ProtoReflect.Descriptor instead.
+func (*ListBlockedRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListBlockedRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *ValidateCredentialsResponse) GetPicture() string {
+func (x *ListBlockedRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Picture
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListBlockedRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
 	}
 	return ""
 }
 
-var File_user_v1_user_proto protoreflect.FileDescriptor
+type ListBlockedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserProfile         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_user_v1_user_proto_rawDesc = "" +
-	"\n" +
-	"\x12user/v1/user.proto\x12\auser.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x83\x01\n" +
-	"\x11CreateUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
-	"\apicture\x18\x04 \x01(\tR\apicture\x12\x1a\n" +
-	"\bpassword\x18\x05 \x01(\tR\bpassword\"\xca\x02\n" +
-	"\x11UpdateUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
-	"\x04name\x18\x02 \x01(\v2\x1c.google.protobuf.StringValueR\x04name\x126\n" +
-	"\apicture\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\apicture\x12.\n" +
-	"\x03bio\x18\x04 \x01(\v2\x1c.google.protobuf.StringValueR\x03bio\x128\n" +
-	"\blocation\x18\x05 \x01(\v2\x1c.google.protobuf.StringValueR\blocation\x126\n" +
-	"\awebsite\x18\x06 \x01(\v2\x1c.google.protobuf.StringValueR\awebsite\x12\x19\n" +
-	"\bactor_id\x18\a \x01(\tR\aactorId\">\n" +
-	"\x11DeleteUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
-	"\bactor_id\x18\x02 \x01(\tR\aactorId\" \n" +
-	"\x0eGetUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"-\n" +
-	"\x15GetUserByEmailRequest\x12\x14\n" +
-	"\x05email\x18\x01 \x01(\tR\x05email\"'\n" +
-	"\x15GetUserProfileRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"@\n" +
-	"\x10ListUsersRequest\x12\x14\n" +
-	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"X\n" +
-	"\x12SearchUsersRequest\x12\x14\n" +
-	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\xb5\x02\n" +
-	"\x04User\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
-	"\apicture\x18\x04 \x01(\tR\apicture\x12\x10\n" +
-	"\x03bio\x18\x05 \x01(\tR\x03bio\x12\x1a\n" +
-	"\blocation\x18\x06 \x01(\tR\blocation\x12\x18\n" +
-	"\awebsite\x18\a \x01(\tR\awebsite\x12\x1b\n" +
-	"\tis_active\x18\b \x01(\bR\bisActive\x129\n" +
-	"\n" +
-	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa9\x01\n" +
-	"\vUserProfile\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
-	"\apicture\x18\x04 \x01(\tR\apicture\x12\x10\n" +
-	"\x03bio\x18\x05 \x01(\tR\x03bio\x12\x1a\n" +
-	"\blocation\x18\x06 \x01(\tR\blocation\x12\x18\n" +
-	"\awebsite\x18\a \x01(\tR\awebsite\"|\n" +
-	"\x11ListUsersResponse\x12#\n" +
-	"\x05users\x18\x01 \x03(\v2\r.user.v1.UserR\x05users\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x14\n" +
-	"\x05total\x18\x04 \x01(\x05R\x05total\"A\n" +
-	"\x11UserStatsResponse\x12,\n" +
-	"\x12total_active_users\x18\x01 \x01(\x03R\x10totalActiveUsers\"Q\n" +
-	"\rFollowRequest\x12\x1f\n" +
-	"\vfollower_id\x18\x01 \x01(\tR\n" +
-	"followerId\x12\x1f\n" +
-	"\vfollowee_id\x18\x02 \x01(\tR\n" +
-	"followeeId\"S\n" +
-	"\x0fUnfollowRequest\x12\x1f\n" +
-	"\vfollower_id\x18\x01 \x01(\tR\n" +
-	"followerId\x12\x1f\n" +
-	"\vfollowee_id\x18\x02 \x01(\tR\n" +
-	"followeeId\"\\\n" +
-	"\x13GetFollowersRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\\\n" +
-	"\x13GetFollowingRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"a\n" +
-	"\x12ListFollowResponse\x12*\n" +
-	"\x05users\x18\x01 \x03(\v2\x14.user.v1.UserProfileR\x05users\x12\x1f\n" +
-	"\vnext_cursor\x18\x02 \x01(\tR\n" +
-	"nextCursor\"X\n" +
-	"\x12AreFollowedRequest\x12\x1f\n" +
-	"\vfollower_id\x18\x01 \x01(\tR\n" +
-	"followerId\x12!\n" +
+func (x *ListBlockedResponse) Reset() {
+	*x = ListBlockedResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedResponse) ProtoMessage() {}
+
+func (x *ListBlockedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockedResponse.ProtoReflect.Descriptor instead.
+func (*ListBlockedResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListBlockedResponse) GetUsers() []*UserProfile {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListBlockedResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ValidateCredentialsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCredentialsRequest) Reset() {
+	*x = ValidateCredentialsRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCredentialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCredentialsRequest) ProtoMessage() {}
+
+func (x *ValidateCredentialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCredentialsRequest.ProtoReflect.Descriptor instead.
+func (*ValidateCredentialsRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ValidateCredentialsRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ValidateCredentialsRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// VerifyEmailRequest marks a user's email verified. Admin only.
+type VerifyEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorRole     string                 `protobuf:"bytes,3,opt,name=actor_role,json=actorRole,proto3" json:"actor_role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailRequest) Reset() {
+	*x = VerifyEmailRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailRequest) ProtoMessage() {}
+
+func (x *VerifyEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *VerifyEmailRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VerifyEmailRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *VerifyEmailRequest) GetActorRole() string {
+	if x != nil {
+		return x.ActorRole
+	}
+	return ""
+}
+
+// ChangeEmailRequest stores email as id's pending address and sends a
+// confirmation token to it. Self only.
+type ChangeEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEmailRequest) Reset() {
+	*x = ChangeEmailRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmailRequest) ProtoMessage() {}
+
+func (x *ChangeEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmailRequest.ProtoReflect.Descriptor instead.
+func (*ChangeEmailRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ChangeEmailRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChangeEmailRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *ChangeEmailRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// ChangeEmailResponse goes to the account owner only; the token itself is sent
+// to the new address.
+type ChangeEmailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PendingEmail  string                 `protobuf:"bytes,1,opt,name=pending_email,json=pendingEmail,proto3" json:"pending_email,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEmailResponse) Reset() {
+	*x = ChangeEmailResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmailResponse) ProtoMessage() {}
+
+func (x *ChangeEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmailResponse.ProtoReflect.Descriptor instead.
+func (*ChangeEmailResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ChangeEmailResponse) GetPendingEmail() string {
+	if x != nil {
+		return x.PendingEmail
+	}
+	return ""
+}
+
+func (x *ChangeEmailResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// VerifyEmailChangeRequest makes the pending address id's verified email when
+// token matches. Self only.
+type VerifyEmailChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailChangeRequest) Reset() {
+	*x = VerifyEmailChangeRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailChangeRequest) ProtoMessage() {}
+
+func (x *VerifyEmailChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailChangeRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailChangeRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *VerifyEmailChangeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VerifyEmailChangeRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *VerifyEmailChangeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// TouchLastSeenRequest records that a user was just active. The gateway
+// throttles these calls; the service writes on every one.
+type TouchLastSeenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TouchLastSeenRequest) Reset() {
+	*x = TouchLastSeenRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TouchLastSeenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TouchLastSeenRequest) ProtoMessage() {}
+
+func (x *TouchLastSeenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TouchLastSeenRequest.ProtoReflect.Descriptor instead.
+func (*TouchLastSeenRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TouchLastSeenRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// GetUsersBatchRequest resolves up to 200 distinct user IDs at once.
+type GetUsersBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersBatchRequest) Reset() {
+	*x = GetUsersBatchRequest{}
+	mi := &file_user_v1_user_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersBatchRequest) ProtoMessage() {}
+
+func (x *GetUsersBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetUsersBatchRequest) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetUsersBatchRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// GetUsersBatchResponse maps each found ID to its public profile. Unknown and
+// deactivated IDs are simply absent.
+type GetUsersBatchResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Users         map[string]*UserProfile `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersBatchResponse) Reset() {
+	*x = GetUsersBatchResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersBatchResponse) ProtoMessage() {}
+
+func (x *GetUsersBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetUsersBatchResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetUsersBatchResponse) GetUsers() map[string]*UserProfile {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type ValidateCredentialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Picture       string                 `protobuf:"bytes,4,opt,name=picture,proto3" json:"picture,omitempty"`
+	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCredentialsResponse) Reset() {
+	*x = ValidateCredentialsResponse{}
+	mi := &file_user_v1_user_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCredentialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCredentialsResponse) ProtoMessage() {}
+
+func (x *ValidateCredentialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_v1_user_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCredentialsResponse.ProtoReflect.Descriptor instead.
+func (*ValidateCredentialsResponse) Descriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ValidateCredentialsResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ValidateCredentialsResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ValidateCredentialsResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ValidateCredentialsResponse) GetPicture() string {
+	if x != nil {
+		return x.Picture
+	}
+	return ""
+}
+
+func (x *ValidateCredentialsResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+var File_user_v1_user_proto protoreflect.FileDescriptor
+
+const file_user_v1_user_proto_rawDesc = "" +
+	"\n" +
+	"\x12user/v1/user.proto\x12\auser.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\xc6\x01\n" +
+	"\x11CreateUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
+	"\apicture\x18\x04 \x01(\tR\apicture\x12\x1a\n" +
+	"\bpassword\x18\x05 \x01(\tR\bpassword\x12\x1a\n" +
+	"\busername\x18\x06 \x01(\tR\busername\x12%\n" +
+	"\x0eemail_verified\x18\a \x01(\bR\remailVerified\"\x84\x03\n" +
+	"\x11UpdateUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x04name\x18\x02 \x01(\v2\x1c.google.protobuf.StringValueR\x04name\x126\n" +
+	"\apicture\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\apicture\x12.\n" +
+	"\x03bio\x18\x04 \x01(\v2\x1c.google.protobuf.StringValueR\x03bio\x128\n" +
+	"\blocation\x18\x05 \x01(\v2\x1c.google.protobuf.StringValueR\blocation\x126\n" +
+	"\awebsite\x18\x06 \x01(\v2\x1c.google.protobuf.StringValueR\awebsite\x12\x19\n" +
+	"\bactor_id\x18\a \x01(\tR\aactorId\x128\n" +
+	"\busername\x18\b \x01(\v2\x1c.google.protobuf.StringValueR\busername\"]\n" +
+	"\x11DeleteUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x1d\n" +
+	"\n" +
+	"actor_role\x18\x03 \x01(\tR\tactorRole\"a\n" +
+	"\x15DeactivateUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x1d\n" +
+	"\n" +
+	"actor_role\x18\x03 \x01(\tR\tactorRole\"a\n" +
+	"\x15ReactivateUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x1d\n" +
+	"\n" +
+	"actor_role\x18\x03 \x01(\tR\tactorRole\" \n" +
+	"\x0eGetUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"M\n" +
+	"\x15GetUserByEmailRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1e\n" +
+	"\n" +
+	"reactivate\x18\x02 \x01(\bR\n" +
+	"reactivate\"'\n" +
+	"\x15GetUserProfileRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
+	"\x18GetUserByUsernameRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\x99\x02\n" +
+	"\x10ListUsersRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12)\n" +
+	"\x10include_inactive\x18\x03 \x01(\bR\x0fincludeInactive\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x05 \x01(\tR\x05order\x12?\n" +
+	"\rcreated_after\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\"X\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\xaa\x04\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
+	"\apicture\x18\x04 \x01(\tR\apicture\x12\x10\n" +
+	"\x03bio\x18\x05 \x01(\tR\x03bio\x12\x1a\n" +
+	"\blocation\x18\x06 \x01(\tR\blocation\x12\x18\n" +
+	"\awebsite\x18\a \x01(\tR\awebsite\x12\x1b\n" +
+	"\tis_active\x18\b \x01(\bR\bisActive\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\busername\x18\v \x01(\tR\busername\x12\x12\n" +
+	"\x04role\x18\f \x01(\tR\x04role\x12%\n" +
+	"\x0eemail_verified\x18\r \x01(\bR\remailVerified\x12F\n" +
+	"\x11email_verified_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x0femailVerifiedAt\x12<\n" +
+	"\flast_seen_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastSeenAt\x12\x16\n" +
+	"\x06status\x18\x10 \x01(\tR\x06status\"\xc5\x01\n" +
+	"\vUserProfile\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
+	"\apicture\x18\x04 \x01(\tR\apicture\x12\x10\n" +
+	"\x03bio\x18\x05 \x01(\tR\x03bio\x12\x1a\n" +
+	"\blocation\x18\x06 \x01(\tR\blocation\x12\x18\n" +
+	"\awebsite\x18\a \x01(\tR\awebsite\x12\x1a\n" +
+	"\busername\x18\b \x01(\tR\busername\"\x97\x01\n" +
+	"\x11ListUsersResponse\x12#\n" +
+	"\x05users\x18\x01 \x03(\v2\r.user.v1.UserR\x05users\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\x12\x19\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"A\n" +
+	"\x11UserStatsResponse\x12,\n" +
+	"\x12total_active_users\x18\x01 \x01(\x03R\x10totalActiveUsers\"Q\n" +
+	"\rFollowRequest\x12\x1f\n" +
+	"\vfollower_id\x18\x01 \x01(\tR\n" +
+	"followerId\x12\x1f\n" +
+	"\vfollowee_id\x18\x02 \x01(\tR\n" +
+	"followeeId\"S\n" +
+	"\x0fUnfollowRequest\x12\x1f\n" +
+	"\vfollower_id\x18\x01 \x01(\tR\n" +
+	"followerId\x12\x1f\n" +
+	"\vfollowee_id\x18\x02 \x01(\tR\n" +
+	"followeeId\"\\\n" +
+	"\x13GetFollowersRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\\\n" +
+	"\x13GetFollowingRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"w\n" +
+	"\x12ListFollowResponse\x12*\n" +
+	"\x05users\x18\x01 \x03(\v2\x14.user.v1.UserProfileR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\"X\n" +
+	"\x12AreFollowedRequest\x12\x1f\n" +
+	"\vfollower_id\x18\x01 \x01(\tR\n" +
+	"followerId\x12!\n" +
 	"\ffollowee_ids\x18\x02 \x03(\tR\vfolloweeIds\"8\n" +
 	"\x13AreFollowedResponse\x12!\n" +
-	"\ffollowed_ids\x18\x01 \x03(\tR\vfollowedIds\"N\n" +
+	"\ffollowed_ids\x18\x01 \x03(\tR\vfollowedIds\"L\n" +
+	"\fBlockRequest\x12\x1d\n" +
+	"\n" +
+	"blocker_id\x18\x01 \x01(\tR\tblockerId\x12\x1d\n" +
+	"\n" +
+	"blocked_id\x18\x02 \x01(\tR\tblockedId\"N\n" +
+	"\x0eUnblockRequest\x12\x1d\n" +
+	"\n" +
+	"blocker_id\x18\x01 \x01(\tR\tblockerId\x12\x1d\n" +
+	"\n" +
+	"blocked_id\x18\x02 \x01(\tR\tblockedId\"[\n" +
+	"\x12ListBlockedRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"b\n" +
+	"\x13ListBlockedResponse\x12*\n" +
+	"\x05users\x18\x01 \x03(\v2\x14.user.v1.UserProfileR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"N\n" +
 	"\x1aValidateCredentialsRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"q\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"^\n" +
+	"\x12VerifyEmailRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x1d\n" +
+	"\n" +
+	"actor_role\x18\x03 \x01(\tR\tactorRole\"U\n" +
+	"\x12ChangeEmailRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"u\n" +
+	"\x13ChangeEmailResponse\x12#\n" +
+	"\rpending_email\x18\x01 \x01(\tR\fpendingEmail\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"[\n" +
+	"\x18VerifyEmailChangeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"&\n" +
+	"\x14TouchLastSeenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"(\n" +
+	"\x14GetUsersBatchRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\xa8\x01\n" +
+	"\x15GetUsersBatchResponse\x12?\n" +
+	"\x05users\x18\x01 \x03(\v2).user.v1.GetUsersBatchResponse.UsersEntryR\x05users\x1aN\n" +
+	"\n" +
+	"UsersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.user.v1.UserProfileR\x05value:\x028\x01\"\x85\x01\n" +
 	"\x1bValidateCredentialsResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
 	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
-	"\apicture\x18\x04 \x01(\tR\apicture2\xc2\b\n" +
+	"\apicture\x18\x04 \x01(\tR\apicture\x12\x12\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role2\xbd\x0e\n" +
 	"\vUserService\x127\n" +
 	"\n" +
 	"CreateUser\x12\x1a.user.v1.CreateUserRequest\x1a\r.user.v1.User\x12`\n" +
 	"\x13ValidateCredentials\x12#.user.v1.ValidateCredentialsRequest\x1a$.user.v1.ValidateCredentialsResponse\x121\n" +
 	"\aGetUser\x12\x17.user.v1.GetUserRequest\x1a\r.user.v1.User\x12?\n" +
 	"\x0eGetUserByEmail\x12\x1e.user.v1.GetUserByEmailRequest\x1a\r.user.v1.User\x12F\n" +
-	"\x0eGetUserProfile\x12\x1e.user.v1.GetUserProfileRequest\x1a\x14.user.v1.UserProfile\x127\n" +
+	"\x0eGetUserProfile\x12\x1e.user.v1.GetUserProfileRequest\x1a\x14.user.v1.UserProfile\x12L\n" +
+	"\x11GetUserByUsername\x12!.user.v1.GetUserByUsernameRequest\x1a\x14.user.v1.UserProfile\x12N\n" +
+	"\rGetUsersBatch\x12\x1d.user.v1.GetUsersBatchRequest\x1a\x1e.user.v1.GetUsersBatchResponse\x129\n" +
+	"\vVerifyEmail\x12\x1b.user.v1.VerifyEmailRequest\x1a\r.user.v1.User\x12H\n" +
+	"\vChangeEmail\x12\x1b.user.v1.ChangeEmailRequest\x1a\x1c.user.v1.ChangeEmailResponse\x12E\n" +
+	"\x11VerifyEmailChange\x12!.user.v1.VerifyEmailChangeRequest\x1a\r.user.v1.User\x12F\n" +
+	"\rTouchLastSeen\x12\x1d.user.v1.TouchLastSeenRequest\x1a\x16.google.protobuf.Empty\x127\n" +
 	"\n" +
 	"UpdateUser\x12\x1a.user.v1.UpdateUserRequest\x1a\r.user.v1.User\x12@\n" +
 	"\n" +
-	"DeleteUser\x12\x1a.user.v1.DeleteUserRequest\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"DeleteUser\x12\x1a.user.v1.DeleteUserRequest\x1a\x16.google.protobuf.Empty\x12H\n" +
+	"\x0eDeactivateUser\x12\x1e.user.v1.DeactivateUserRequest\x1a\x16.google.protobuf.Empty\x12?\n" +
+	"\x0eReactivateUser\x12\x1e.user.v1.ReactivateUserRequest\x1a\r.user.v1.User\x12B\n" +
 	"\tListUsers\x12\x19.user.v1.ListUsersRequest\x1a\x1a.user.v1.ListUsersResponse\x12F\n" +
 	"\vSearchUsers\x12\x1b.user.v1.SearchUsersRequest\x1a\x1a.user.v1.ListUsersResponse\x12>\n" +
 	"\bGetStats\x12\x16.google.protobuf.Empty\x1a\x1a.user.v1.UserStatsResponse\x128\n" +
@@ -1418,7 +2437,10 @@ const file_user_v1_user_proto_rawDesc = "" +
 	"\bUnfollow\x12\x18.user.v1.UnfollowRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
 	"\fGetFollowers\x12\x1c.user.v1.GetFollowersRequest\x1a\x1b.user.v1.ListFollowResponse\x12I\n" +
 	"\fGetFollowing\x12\x1c.user.v1.GetFollowingRequest\x1a\x1b.user.v1.ListFollowResponse\x12H\n" +
-	"\vAreFollowed\x12\x1b.user.v1.AreFollowedRequest\x1a\x1c.user.v1.AreFollowedResponse\x12=\n" +
+	"\vAreFollowed\x12\x1b.user.v1.AreFollowedRequest\x1a\x1c.user.v1.AreFollowedResponse\x126\n" +
+	"\x05Block\x12\x15.user.v1.BlockRequest\x1a\x16.google.protobuf.Empty\x12:\n" +
+	"\aUnblock\x12\x17.user.v1.UnblockRequest\x1a\x16.google.protobuf.Empty\x12H\n" +
+	"\vListBlocked\x12\x1b.user.v1.ListBlockedRequest\x1a\x1c.user.v1.ListBlockedResponse\x12=\n" +
 	"\vHealthCheck\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.EmptyB=Z;github.com/nikitashilov/microblog_grpc/proto/user/v1;userv1b\x06proto3"
 
 var (
@@ -1433,80 +2455,126 @@ func file_user_v1_user_proto_rawDescGZIP() []byte {
 	return file_user_v1_user_proto_rawDescData
 }
 
-var file_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_user_v1_user_proto_goTypes = []any{
 	(*CreateUserRequest)(nil),           // 0: user.v1.CreateUserRequest
 	(*UpdateUserRequest)(nil),           // 1: user.v1.UpdateUserRequest
 	(*DeleteUserRequest)(nil),           // 2: user.v1.DeleteUserRequest
-	(*GetUserRequest)(nil),              // 3: user.v1.GetUserRequest
-	(*GetUserByEmailRequest)(nil),       // 4: user.v1.GetUserByEmailRequest
-	(*GetUserProfileRequest)(nil),       // 5: user.v1.GetUserProfileRequest
-	(*ListUsersRequest)(nil),            // 6: user.v1.ListUsersRequest
-	(*SearchUsersRequest)(nil),          // 7: user.v1.SearchUsersRequest
-	(*User)(nil),                        // 8: user.v1.User
-	(*UserProfile)(nil),                 // 9: user.v1.UserProfile
-	(*ListUsersResponse)(nil),           // 10: user.v1.ListUsersResponse
-	(*UserStatsResponse)(nil),           // 11: user.v1.UserStatsResponse
-	(*FollowRequest)(nil),               // 12: user.v1.FollowRequest
-	(*UnfollowRequest)(nil),             // 13: user.v1.UnfollowRequest
-	(*GetFollowersRequest)(nil),         // 14: user.v1.GetFollowersRequest
-	(*GetFollowingRequest)(nil),         // 15: user.v1.GetFollowingRequest
-	(*ListFollowResponse)(nil),          // 16: user.v1.ListFollowResponse
-	(*AreFollowedRequest)(nil),          // 17: user.v1.AreFollowedRequest
-	(*AreFollowedResponse)(nil),         // 18: user.v1.AreFollowedResponse
-	(*ValidateCredentialsRequest)(nil),  // 19: user.v1.ValidateCredentialsRequest
-	(*ValidateCredentialsResponse)(nil), // 20: user.v1.ValidateCredentialsResponse
-	(*wrapperspb.StringValue)(nil),      // 21: google.protobuf.StringValue
-	(*timestamppb.Timestamp)(nil),       // 22: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),               // 23: google.protobuf.Empty
+	(*DeactivateUserRequest)(nil),       // 3: user.v1.DeactivateUserRequest
+	(*ReactivateUserRequest)(nil),       // 4: user.v1.ReactivateUserRequest
+	(*GetUserRequest)(nil),              // 5: user.v1.GetUserRequest
+	(*GetUserByEmailRequest)(nil),       // 6: user.v1.GetUserByEmailRequest
+	(*GetUserProfileRequest)(nil),       // 7: user.v1.GetUserProfileRequest
+	(*GetUserByUsernameRequest)(nil),    // 8: user.v1.GetUserByUsernameRequest
+	(*ListUsersRequest)(nil),            // 9: user.v1.ListUsersRequest
+	(*SearchUsersRequest)(nil),          // 10: user.v1.SearchUsersRequest
+	(*User)(nil),                        // 11: user.v1.User
+	(*UserProfile)(nil),                 // 12: user.v1.UserProfile
+	(*ListUsersResponse)(nil),           // 13: user.v1.ListUsersResponse
+	(*UserStatsResponse)(nil),           // 14: user.v1.UserStatsResponse
+	(*FollowRequest)(nil),               // 15: user.v1.FollowRequest
+	(*UnfollowRequest)(nil),             // 16: user.v1.UnfollowRequest
+	(*GetFollowersRequest)(nil),         // 17: user.v1.GetFollowersRequest
+	(*GetFollowingRequest)(nil),         // 18: user.v1.GetFollowingRequest
+	(*ListFollowResponse)(nil),          // 19: user.v1.ListFollowResponse
+	(*AreFollowedRequest)(nil),          // 20: user.v1.AreFollowedRequest
+	(*AreFollowedResponse)(nil),         // 21: user.v1.AreFollowedResponse
+	(*BlockRequest)(nil),                // 22: user.v1.BlockRequest
+	(*UnblockRequest)(nil),              // 23: user.v1.UnblockRequest
+	(*ListBlockedRequest)(nil),          // 24: user.v1.ListBlockedRequest
+	(*ListBlockedResponse)(nil),         // 25: user.v1.ListBlockedResponse
+	(*ValidateCredentialsRequest)(nil),  // 26: user.v1.ValidateCredentialsRequest
+	(*VerifyEmailRequest)(nil),          // 27: user.v1.VerifyEmailRequest
+	(*ChangeEmailRequest)(nil),          // 28: user.v1.ChangeEmailRequest
+	(*ChangeEmailResponse)(nil),         // 29: user.v1.ChangeEmailResponse
+	(*VerifyEmailChangeRequest)(nil),    // 30: user.v1.VerifyEmailChangeRequest
+	(*TouchLastSeenRequest)(nil),        // 31: user.v1.TouchLastSeenRequest
+	(*GetUsersBatchRequest)(nil),        // 32: user.v1.GetUsersBatchRequest
+	(*GetUsersBatchResponse)(nil),       // 33: user.v1.GetUsersBatchResponse
+	(*ValidateCredentialsResponse)(nil), // 34: user.v1.ValidateCredentialsResponse
+	nil,                                 // 35: user.v1.GetUsersBatchResponse.UsersEntry
+	(*wrapperspb.StringValue)(nil),      // 36: google.protobuf.StringValue
+	(*timestamppb.Timestamp)(nil),       // 37: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),               // 38: google.protobuf.Empty
 }
 var file_user_v1_user_proto_depIdxs = []int32{
-	21, // 0: user.v1.UpdateUserRequest.name:type_name -> google.protobuf.StringValue
-	21, // 1: user.v1.UpdateUserRequest.picture:type_name -> google.protobuf.StringValue
-	21, // 2: user.v1.UpdateUserRequest.bio:type_name -> google.protobuf.StringValue
-	21, // 3: user.v1.UpdateUserRequest.location:type_name -> google.protobuf.StringValue
-	21, // 4: user.v1.UpdateUserRequest.website:type_name -> google.protobuf.StringValue
-	22, // 5: user.v1.User.created_at:type_name -> google.protobuf.Timestamp
-	22, // 6: user.v1.User.updated_at:type_name -> google.protobuf.Timestamp
-	8,  // 7: user.v1.ListUsersResponse.users:type_name -> user.v1.User
-	9,  // 8: user.v1.ListFollowResponse.users:type_name -> user.v1.UserProfile
-	0,  // 9: user.v1.UserService.CreateUser:input_type -> user.v1.CreateUserRequest
-	19, // 10: user.v1.UserService.ValidateCredentials:input_type -> user.v1.ValidateCredentialsRequest
-	3,  // 11: user.v1.UserService.GetUser:input_type -> user.v1.GetUserRequest
-	4,  // 12: user.v1.UserService.GetUserByEmail:input_type -> user.v1.GetUserByEmailRequest
-	5,  // 13: user.v1.UserService.GetUserProfile:input_type -> user.v1.GetUserProfileRequest
-	1,  // 14: user.v1.UserService.UpdateUser:input_type -> user.v1.UpdateUserRequest
-	2,  // 15: user.v1.UserService.DeleteUser:input_type -> user.v1.DeleteUserRequest
-	6,  // 16: user.v1.UserService.ListUsers:input_type -> user.v1.ListUsersRequest
-	7,  // 17: user.v1.UserService.SearchUsers:input_type -> user.v1.SearchUsersRequest
-	23, // 18: user.v1.UserService.GetStats:input_type -> google.protobuf.Empty
-	12, // 19: user.v1.UserService.Follow:input_type -> user.v1.FollowRequest
-	13, // 20: user.v1.UserService.Unfollow:input_type -> user.v1.UnfollowRequest
-	14, // 21: user.v1.UserService.GetFollowers:input_type -> user.v1.GetFollowersRequest
-	15, // 22: user.v1.UserService.GetFollowing:input_type -> user.v1.GetFollowingRequest
-	17, // 23: user.v1.UserService.AreFollowed:input_type -> user.v1.AreFollowedRequest
-	23, // 24: user.v1.UserService.HealthCheck:input_type -> google.protobuf.Empty
-	8,  // 25: user.v1.UserService.CreateUser:output_type -> user.v1.User
-	20, // 26: user.v1.UserService.ValidateCredentials:output_type -> user.v1.ValidateCredentialsResponse
-	8,  // 27: user.v1.UserService.GetUser:output_type -> user.v1.User
-	8,  // 28: user.v1.UserService.GetUserByEmail:output_type -> user.v1.User
-	9,  // 29: user.v1.UserService.GetUserProfile:output_type -> user.v1.UserProfile
-	8,  // 30: user.v1.UserService.UpdateUser:output_type -> user.v1.User
-	23, // 31: user.v1.UserService.DeleteUser:output_type -> google.protobuf.Empty
-	10, // 32: user.v1.UserService.ListUsers:output_type -> user.v1.ListUsersResponse
-	10, // 33: user.v1.UserService.SearchUsers:output_type -> user.v1.ListUsersResponse
-	11, // 34: user.v1.UserService.GetStats:output_type -> user.v1.UserStatsResponse
-	23, // 35: user.v1.UserService.Follow:output_type -> google.protobuf.Empty
-	23, // 36: user.v1.UserService.Unfollow:output_type -> google.protobuf.Empty
-	16, // 37: user.v1.UserService.GetFollowers:output_type -> user.v1.ListFollowResponse
-	16, // 38: user.v1.UserService.GetFollowing:output_type -> user.v1.ListFollowResponse
-	18, // 39: user.v1.UserService.AreFollowed:output_type -> user.v1.AreFollowedResponse
-	23, // 40: user.v1.UserService.HealthCheck:output_type -> google.protobuf.Empty
-	25, // [25:41] is the sub-list for method output_type
-	9,  // [9:25] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	36, // 0: user.v1.UpdateUserRequest.name:type_name -> google.protobuf.StringValue
+	36, // 1: user.v1.UpdateUserRequest.picture:type_name -> google.protobuf.StringValue
+	36, // 2: user.v1.UpdateUserRequest.bio:type_name -> google.protobuf.StringValue
+	36, // 3: user.v1.UpdateUserRequest.location:type_name -> google.protobuf.StringValue
+	36, // 4: user.v1.UpdateUserRequest.website:type_name -> google.protobuf.StringValue
+	36, // 5: user.v1.UpdateUserRequest.username:type_name -> google.protobuf.StringValue
+	37, // 6: user.v1.ListUsersRequest.created_after:type_name -> google.protobuf.Timestamp
+	37, // 7: user.v1.ListUsersRequest.created_before:type_name -> google.protobuf.Timestamp
+	37, // 8: user.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	37, // 9: user.v1.User.updated_at:type_name -> google.protobuf.Timestamp
+	37, // 10: user.v1.User.email_verified_at:type_name -> google.protobuf.Timestamp
+	37, // 11: user.v1.User.last_seen_at:type_name -> google.protobuf.Timestamp
+	11, // 12: user.v1.ListUsersResponse.users:type_name -> user.v1.User
+	12, // 13: user.v1.ListFollowResponse.users:type_name -> user.v1.UserProfile
+	12, // 14: user.v1.ListBlockedResponse.users:type_name -> user.v1.UserProfile
+	37, // 15: user.v1.ChangeEmailResponse.expires_at:type_name -> google.protobuf.Timestamp
+	35, // 16: user.v1.GetUsersBatchResponse.users:type_name -> user.v1.GetUsersBatchResponse.UsersEntry
+	12, // 17: user.v1.GetUsersBatchResponse.UsersEntry.value:type_name -> user.v1.UserProfile
+	0,  // 18: user.v1.UserService.CreateUser:input_type -> user.v1.CreateUserRequest
+	26, // 19: user.v1.UserService.ValidateCredentials:input_type -> user.v1.ValidateCredentialsRequest
+	5,  // 20: user.v1.UserService.GetUser:input_type -> user.v1.GetUserRequest
+	6,  // 21: user.v1.UserService.GetUserByEmail:input_type -> user.v1.GetUserByEmailRequest
+	7,  // 22: user.v1.UserService.GetUserProfile:input_type -> user.v1.GetUserProfileRequest
+	8,  // 23: user.v1.UserService.GetUserByUsername:input_type -> user.v1.GetUserByUsernameRequest
+	32, // 24: user.v1.UserService.GetUsersBatch:input_type -> user.v1.GetUsersBatchRequest
+	27, // 25: user.v1.UserService.VerifyEmail:input_type -> user.v1.VerifyEmailRequest
+	28, // 26: user.v1.UserService.ChangeEmail:input_type -> user.v1.ChangeEmailRequest
+	30, // 27: user.v1.UserService.VerifyEmailChange:input_type -> user.v1.VerifyEmailChangeRequest
+	31, // 28: user.v1.UserService.TouchLastSeen:input_type -> user.v1.TouchLastSeenRequest
+	1,  // 29: user.v1.UserService.UpdateUser:input_type -> user.v1.UpdateUserRequest
+	2,  // 30: user.v1.UserService.DeleteUser:input_type -> user.v1.DeleteUserRequest
+	3,  // 31: user.v1.UserService.DeactivateUser:input_type -> user.v1.DeactivateUserRequest
+	4,  // 32: user.v1.UserService.ReactivateUser:input_type -> user.v1.ReactivateUserRequest
+	9,  // 33: user.v1.UserService.ListUsers:input_type -> user.v1.ListUsersRequest
+	10, // 34: user.v1.UserService.SearchUsers:input_type -> user.v1.SearchUsersRequest
+	38, // 35: user.v1.UserService.GetStats:input_type -> google.protobuf.Empty
+	15, // 36: user.v1.UserService.Follow:input_type -> user.v1.FollowRequest
+	16, // 37: user.v1.UserService.Unfollow:input_type -> user.v1.UnfollowRequest
+	17, // 38: user.v1.UserService.GetFollowers:input_type -> user.v1.GetFollowersRequest
+	18, // 39: user.v1.UserService.GetFollowing:input_type -> user.v1.GetFollowingRequest
+	20, // 40: user.v1.UserService.AreFollowed:input_type -> user.v1.AreFollowedRequest
+	22, // 41: user.v1.UserService.Block:input_type -> user.v1.BlockRequest
+	23, // 42: user.v1.UserService.Unblock:input_type -> user.v1.UnblockRequest
+	24, // 43: user.v1.UserService.ListBlocked:input_type -> user.v1.ListBlockedRequest
+	38, // 44: user.v1.UserService.HealthCheck:input_type -> google.protobuf.Empty
+	11, // 45: user.v1.UserService.CreateUser:output_type -> user.v1.User
+	34, // 46: user.v1.UserService.ValidateCredentials:output_type -> user.v1.ValidateCredentialsResponse
+	11, // 47: user.v1.UserService.GetUser:output_type -> user.v1.User
+	11, // 48: user.v1.UserService.GetUserByEmail:output_type -> user.v1.User
+	12, // 49: user.v1.UserService.GetUserProfile:output_type -> user.v1.UserProfile
+	12, // 50: user.v1.UserService.GetUserByUsername:output_type -> user.v1.UserProfile
+	33, // 51: user.v1.UserService.GetUsersBatch:output_type -> user.v1.GetUsersBatchResponse
+	11, // 52: user.v1.UserService.VerifyEmail:output_type -> user.v1.User
+	29, // 53: user.v1.UserService.ChangeEmail:output_type -> user.v1.ChangeEmailResponse
+	11, // 54: user.v1.UserService.VerifyEmailChange:output_type -> user.v1.User
+	38, // 55: user.v1.UserService.TouchLastSeen:output_type -> google.protobuf.Empty
+	11, // 56: user.v1.UserService.UpdateUser:output_type -> user.v1.User
+	38, // 57: user.v1.UserService.DeleteUser:output_type -> google.protobuf.Empty
+	38, // 58: user.v1.UserService.DeactivateUser:output_type -> google.protobuf.Empty
+	11, // 59: user.v1.UserService.ReactivateUser:output_type -> user.v1.User
+	13, // 60: user.v1.UserService.ListUsers:output_type -> user.v1.ListUsersResponse
+	13, // 61: user.v1.UserService.SearchUsers:output_type -> user.v1.ListUsersResponse
+	14, // 62: user.v1.UserService.GetStats:output_type -> user.v1.UserStatsResponse
+	38, // 63: user.v1.UserService.Follow:output_type -> google.protobuf.Empty
+	38, // 64: user.v1.UserService.Unfollow:output_type -> google.protobuf.Empty
+	19, // 65: user.v1.UserService.GetFollowers:output_type -> user.v1.ListFollowResponse
+	19, // 66: user.v1.UserService.GetFollowing:output_type -> user.v1.ListFollowResponse
+	21, // 67: user.v1.UserService.AreFollowed:output_type -> user.v1.AreFollowedResponse
+	38, // 68: user.v1.UserService.Block:output_type -> google.protobuf.Empty
+	38, // 69: user.v1.UserService.Unblock:output_type -> google.protobuf.Empty
+	25, // 70: user.v1.UserService.ListBlocked:output_type -> user.v1.ListBlockedResponse
+	38, // 71: user.v1.UserService.HealthCheck:output_type -> google.protobuf.Empty
+	45, // [45:72] is the sub-list for method output_type
+	18, // [18:45] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_user_v1_user_proto_init() }
@@ -1520,7 +2588,7 @@ func file_user_v1_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_v1_user_proto_rawDesc), len(file_user_v1_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   21,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"api-gateway/internal/config"
+	"api-gateway/internal/models"
 	authv1 "github.com/nikitashilov/microblog_grpc/proto/auth/v1"
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
@@ -14,6 +16,7 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"api-gateway/pkg/logger"
+	"api-gateway/pkg/tracing"
 )
 
 const defaultAuthTimeout = 10 * time.Second
@@ -31,7 +34,7 @@ type AuthClient struct {
 	logger *logger.Logger
 }
 
-func NewAuthClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Logger) (*AuthClient, error) {
+func NewAuthClient(addr string, tlsCfg config.GRPCTLSConfig, retryCfg config.GRPCRetryConfig, logger *logger.Logger) (*AuthClient, error) {
 	creds, err := buildClientTransportCredentials(tlsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("build auth client transport credentials: %w", err)
@@ -45,7 +48,12 @@ func NewAuthClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Logg
 			Timeout:             keepaliveTimeout,
 			PermitWithoutStream: keepalivePermitWithoutStream,
 		}),
-		grpc.WithUnaryInterceptor(unaryClientLoggingInterceptor(logger)),
+		grpc.WithChainUnaryInterceptor(
+			tracing.UnaryClientInterceptor(),
+			requestid.UnaryClientInterceptor(),
+			unaryClientLoggingInterceptor(logger),
+			unaryClientRetryInterceptor(retryCfg, logger),
+		),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("connect to auth gRPC service: %w", err)
@@ -87,15 +95,51 @@ func (c *AuthClient) HandleGoogleCallback(ctx context.Context, state, code strin
 	return resp, nil
 }
 
+func (c *AuthClient) GetGitHubAuthURL(ctx context.Context, req *authv1.GetGoogleAuthURLRequest) (*authv1.GetGoogleAuthURLResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
+	defer cancel()
+
+	if req == nil {
+		req = &authv1.GetGoogleAuthURLRequest{}
+	}
+
+	resp, err := c.client.GetGitHubAuthURL(ctx, req)
+	if err != nil {
+		return nil, c.wrapError("get github auth url", err)
+	}
+
+	return resp, nil
+}
+
+func (c *AuthClient) HandleGitHubCallback(ctx context.Context, state, code string) (*authv1.GoogleCallbackResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
+	defer cancel()
+
+	req := &authv1.GoogleCallbackRequest{State: state, Code: code}
+	resp, err := c.client.HandleGitHubCallback(ctx, req)
+	if err != nil {
+		return nil, c.wrapError("handle github callback", err)
+	}
+
+	return resp, nil
+}
+
 func (c *AuthClient) ExchangeAuthCode(ctx context.Context, authCode string) (*authv1.ExchangeAuthCodeResponse, error) {
-	return c.ExchangeAuthCodeWithVerifier(ctx, authCode, "")
+	return c.ExchangeAuthCodeWithVerifier(ctx, authCode, "", "", "")
 }
 
-func (c *AuthClient) ExchangeAuthCodeWithVerifier(ctx context.Context, authCode, codeVerifier string) (*authv1.ExchangeAuthCodeResponse, error) {
+// ExchangeAuthCodeWithVerifier forwards the caller's IP and User-Agent so
+// auth-service can throttle repeated failures and record the session device.
+func (c *AuthClient) ExchangeAuthCodeWithVerifier(ctx context.Context, authCode, codeVerifier, clientIP, userAgent string) (*authv1.ExchangeAuthCodeResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
 	defer cancel()
 
-	req := &authv1.ExchangeAuthCodeRequest{AuthCode: authCode, CodeVerifier: codeVerifier}
+	req := &authv1.ExchangeAuthCodeRequest{
+		AuthCode:     authCode,
+		CodeVerifier: codeVerifier,
+		ClientIp:     clientIP,
+		UserAgent:    userAgent,
+	}
 	resp, err := c.client.ExchangeAuthCode(ctx, req)
 	if err != nil {
 		return nil, c.wrapError("exchange auth code", err)
@@ -104,11 +148,11 @@ func (c *AuthClient) ExchangeAuthCodeWithVerifier(ctx context.Context, authCode,
 	return resp, nil
 }
 
-func (c *AuthClient) RefreshToken(ctx context.Context, refreshToken string) (*authv1.RefreshTokenResponse, error) {
+func (c *AuthClient) RefreshToken(ctx context.Context, refreshToken, clientIP, userAgent string) (*authv1.RefreshTokenResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
 	defer cancel()
 
-	req := &authv1.RefreshTokenRequest{RefreshToken: refreshToken}
+	req := &authv1.RefreshTokenRequest{RefreshToken: refreshToken, ClientIp: clientIP, UserAgent: userAgent}
 	resp, err := c.client.RefreshToken(ctx, req)
 	if err != nil {
 		return nil, c.wrapError("refresh token", err)
@@ -129,6 +173,81 @@ func (c *AuthClient) Logout(ctx context.Context, accessToken string) error {
 	return nil
 }
 
+// LogoutAll ends every session of the user and returns how many were ended.
+func (c *AuthClient) LogoutAll(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
+	defer cancel()
+
+	resp, err := c.client.LogoutAll(ctx, &authv1.LogoutAllRequest{UserId: userID})
+	if err != nil {
+		return 0, c.wrapError("logout all", err)
+	}
+
+	return int(resp.GetSessionsTerminated()), nil
+}
+
+func (c *AuthClient) ListSessions(ctx context.Context, userID string) ([]*models.SessionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
+	defer cancel()
+
+	resp, err := c.client.ListSessions(ctx, &authv1.ListSessionsRequest{UserId: userID})
+	if err != nil {
+		return nil, c.wrapError("list sessions", err)
+	}
+
+	sessions := make([]*models.SessionResponse, 0, len(resp.GetSessions()))
+	for _, s := range resp.GetSessions() {
+		sessions = append(sessions, &models.SessionResponse{
+			ID:         s.GetId(),
+			TokenTypes: s.GetTokenTypes(),
+			CreatedAt:  s.GetCreatedAt().AsTime(),
+			ExpiresAt:  s.GetExpiresAt().AsTime(),
+			IP:         s.GetIp(),
+			UserAgent:  s.GetUserAgent(),
+		})
+	}
+
+	return sessions, nil
+}
+
+func (c *AuthClient) IntrospectTokens(ctx context.Context, tokens []string) ([]*models.TokenIntrospectionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
+	defer cancel()
+
+	resp, err := c.client.IntrospectTokens(ctx, &authv1.IntrospectTokensRequest{Tokens: tokens})
+	if err != nil {
+		return nil, c.wrapError("introspect tokens", err)
+	}
+
+	results := make([]*models.TokenIntrospectionResponse, 0, len(resp.GetResults()))
+	for _, r := range resp.GetResults() {
+		result := &models.TokenIntrospectionResponse{
+			Active: r.GetActive(),
+			UserID: r.GetUserId(),
+			Email:  r.GetEmail(),
+		}
+		if r.GetExpiresAt() != nil {
+			expiresAt := r.GetExpiresAt().AsTime()
+			result.ExpiresAt = &expiresAt
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (c *AuthClient) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
+	defer cancel()
+
+	req := &authv1.RevokeSessionRequest{UserId: userID, SessionId: sessionID}
+	if _, err := c.client.RevokeSession(ctx, req); err != nil {
+		return c.wrapError("revoke session", err)
+	}
+
+	return nil
+}
+
 func (c *AuthClient) ValidateToken(ctx context.Context, token string) (*authv1.ValidateTokenResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultAuthTimeout)
 	defer cancel()
@@ -191,28 +310,34 @@ func (c *AuthClient) wrapError(action string, err error) error {
 		return nil
 	}
 
-	if st, ok := status.FromError(err); ok {
-		return status.Errorf(st.Code(), "%s: %s", action, st.Message())
+	if clientErr := newClientError(action, err); clientErr != nil {
+		return clientErr
 	}
 
 	return fmt.Errorf("%s: %w", action, err)
 }
 
 // unaryClientLoggingInterceptor logs gRPC client requests and responses
-func unaryClientLoggingInterceptor(logger *logger.Logger) grpc.UnaryClientInterceptor {
+func unaryClientLoggingInterceptor(log *logger.Logger) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		start := time.Now()
 		err := invoker(ctx, method, req, reply, cc, opts...)
 		duration := time.Since(start)
 
+		fields := []logger.Field{
+			logger.F("method", method),
+			logger.F("request_id", requestid.FromContext(ctx)),
+			logger.F("duration_ms", duration.Milliseconds()),
+		}
 		if err != nil {
 			if st, ok := status.FromError(err); ok {
-				logger.Warn(fmt.Sprintf("gRPC call %s failed: %s (code: %s, duration: %v)", method, st.Message(), st.Code(), duration))
+				fields = append(fields, logger.F("code", st.Code()), logger.F("error", st.Message()))
 			} else {
-				logger.Warn(fmt.Sprintf("gRPC call %s failed: %v (duration: %v)", method, err, duration))
+				fields = append(fields, logger.Err(err))
 			}
+			log.Warn("gRPC call failed", fields...)
 		} else {
-			logger.Debug(fmt.Sprintf("gRPC call %s succeeded (duration: %v)", method, duration))
+			log.Debug("gRPC call succeeded", fields...)
 		}
 
 		return err
@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"net/http"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ClientError is a failed call to a backend service, described the way the
+// service itself would have answered over HTTP. The gRPC clients return it for
+// every error the service reported; inspect it with errors.As.
+type ClientError struct {
+	Status  int
+	Code    string // application error code, e.g. POST_NOT_FOUND; empty when the service sent none
+	Message string
+
+	action string
+	grpc   *status.Status
+}
+
+func (e *ClientError) Error() string {
+	if e.action == "" {
+		return e.Message
+	}
+	return e.action + ": " + e.Message
+}
+
+// GRPCStatus keeps status.Code and status.FromError working on a ClientError.
+func (e *ClientError) GRPCStatus() *status.Status {
+	if e.grpc == nil {
+		return status.New(codes.Unknown, e.Message)
+	}
+	return e.grpc
+}
+
+// newClientError describes the gRPC status err returned by action. Services
+// attach their application error as an ErrorInfo detail carrying the code and
+// HTTP status; without one the status is derived from the gRPC code. It
+// returns nil for errors that are not gRPC statuses.
+func newClientError(action string, err error) *ClientError {
+	st, ok := status.FromError(err)
+	if !ok || st == nil {
+		return nil
+	}
+
+	clientErr := &ClientError{
+		Status:  HTTPStatusFromGRPC(st.Code()),
+		Message: st.Message(),
+		action:  action,
+		grpc:    st,
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok {
+			continue
+		}
+		clientErr.Code = info.GetReason()
+		if httpStatus, err := strconv.Atoi(info.GetMetadata()["http_status"]); err == nil &&
+			httpStatus >= 400 && httpStatus <= 599 {
+			clientErr.Status = httpStatus
+		}
+		break
+	}
+	return clientErr
+}
+
+// HTTPStatusFromGRPC maps a gRPC code to the HTTP status the gateway answers
+// with when the service did not say which one it meant.
+func HTTPStatusFromGRPC(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
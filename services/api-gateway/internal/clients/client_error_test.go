@@ -0,0 +1,109 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"api-gateway/pkg/logger"
+
+	authv1 "github.com/nikitashilov/microblog_grpc/proto/auth/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func appStatusError(t *testing.T, code codes.Code, reason string, httpStatus string, message string) error {
+	t.Helper()
+	st, err := status.New(code, message).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Metadata: map[string]string{"http_status": httpStatus},
+	})
+	if err != nil {
+		t.Fatalf("attach error info: %v", err)
+	}
+	return st.Err()
+}
+
+func TestNewClientError_ReadsErrorInfo(t *testing.T) {
+	err := appStatusError(t, codes.AlreadyExists, "USERNAME_TAKEN", "409", "Username is already taken")
+
+	clientErr := newClientError("update user", err)
+	if clientErr == nil {
+		t.Fatal("expected a client error")
+	}
+	if clientErr.Status != http.StatusConflict || clientErr.Code != "USERNAME_TAKEN" || clientErr.Message != "Username is already taken" {
+		t.Fatalf("unexpected client error: %+v", clientErr)
+	}
+	if clientErr.Error() != "update user: Username is already taken" {
+		t.Fatalf("unexpected error string %q", clientErr.Error())
+	}
+	if status.Code(clientErr) != codes.AlreadyExists {
+		t.Fatalf("expected the gRPC code to be kept, got %s", status.Code(clientErr))
+	}
+}
+
+func TestNewClientError_PrefersReportedHTTPStatus(t *testing.T) {
+	// The gRPC code is coarser than the HTTP status the service meant.
+	err := appStatusError(t, codes.Unauthenticated, "UNAUTHORIZED_ACCESS", "403", "Unauthorized access")
+
+	if clientErr := newClientError("get post", err); clientErr.Status != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", clientErr.Status)
+	}
+}
+
+func TestNewClientError_WithoutDetailsMapsGRPCCode(t *testing.T) {
+	clientErr := newClientError("get post", status.Error(codes.NotFound, "not found"))
+	if clientErr == nil {
+		t.Fatal("expected a client error")
+	}
+	if clientErr.Status != http.StatusNotFound || clientErr.Code != "" {
+		t.Fatalf("unexpected client error: %+v", clientErr)
+	}
+}
+
+// A locked post reports 423 through its ErrorInfo; a bare FailedPrecondition
+// is a plain bad request.
+func TestNewClientError_FailedPrecondition(t *testing.T) {
+	locked := appStatusError(t, codes.FailedPrecondition, "POST_LOCKED", "423", "Post is locked")
+	if clientErr := newClientError("update post", locked); clientErr.Status != http.StatusLocked {
+		t.Fatalf("expected 423 from the error info, got %d", clientErr.Status)
+	}
+	if clientErr := newClientError("update post", status.Error(codes.FailedPrecondition, "not ready")); clientErr.Status != http.StatusBadRequest {
+		t.Fatalf("expected 400 without error info, got %d", clientErr.Status)
+	}
+}
+
+func TestNewClientError_IgnoresNonGRPCErrors(t *testing.T) {
+	if clientErr := newClientError("get post", errors.New("boom")); clientErr != nil {
+		t.Fatalf("expected a plain error to be rejected, got %+v", clientErr)
+	}
+}
+
+// stubRegisterClient fails Register with err; every other RPC panics via the
+// embedded nil interface.
+type stubRegisterClient struct {
+	authv1.AuthServiceClient
+	err error
+}
+
+func (s *stubRegisterClient) Register(ctx context.Context, in *authv1.RegisterRequest, opts ...grpc.CallOption) (*authv1.RegisterResponse, error) {
+	return nil, s.err
+}
+
+func TestAuthClientRegister_ReturnsClientError(t *testing.T) {
+	stub := &stubRegisterClient{err: appStatusError(t, codes.AlreadyExists, "USER_ALREADY_EXISTS", "409", "User with this email already exists")}
+	client := &AuthClient{client: stub, logger: logger.New("error")}
+
+	_, err := client.Register(context.Background(), "dev@example.com", "password123", "Dev")
+
+	var clientErr *ClientError
+	if !errors.As(err, &clientErr) {
+		t.Fatalf("expected a *ClientError, got %T: %v", err, err)
+	}
+	if clientErr.Status != http.StatusConflict || clientErr.Code != "USER_ALREADY_EXISTS" {
+		t.Fatalf("unexpected client error: %+v", clientErr)
+	}
+}
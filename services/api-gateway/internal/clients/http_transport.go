@@ -0,0 +1,49 @@
+package clients
+
+import (
+	"net/http"
+	"time"
+
+	"api-gateway/internal/config"
+)
+
+// NewHTTPTransport builds the transport shared by the gateway's HTTP clients
+// and proxies. Keeping one pool per process lets requests to the same
+// downstream service reuse keep-alive connections instead of dialing (and
+// handshaking) for each call.
+func NewHTTPTransport(cfg config.HTTPClientConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.MaxIdleConns
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = time.Duration(cfg.IdleConnTimeoutSeconds) * time.Second
+	return transport
+}
+
+// InternalTokenHeader carries the shared secret downstream services check
+// before trusting identity headers such as X-User-ID.
+const InternalTokenHeader = "X-Internal-Token"
+
+// InternalTokenTransport attaches token to every outgoing request so
+// downstream services can tell gateway calls apart from direct ones. An empty
+// token returns base unchanged.
+func InternalTokenTransport(base http.RoundTripper, token string) http.RoundTripper {
+	if token == "" {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &internalTokenTransport{base: base, token: token}
+}
+
+type internalTokenTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+func (t *internalTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(req.Context())
+	req.Header.Set(InternalTokenHeader, t.token)
+	return t.base.RoundTrip(req)
+}
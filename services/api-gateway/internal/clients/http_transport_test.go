@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+)
+
+func TestHTTPTransportReusesConnections(t *testing.T) {
+	var newConns atomic.Int32
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"message":"ok","data":{"unread_count":1}}`)
+	}))
+	backend.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	backend.Start()
+	defer backend.Close()
+
+	transport := NewHTTPTransport(config.HTTPClientConfig{MaxIdleConns: 10, MaxIdleConnsPerHost: 2, IdleConnTimeoutSeconds: 30})
+	defer transport.CloseIdleConnections()
+
+	client, err := NewNotificationClient(backend.URL, transport, logger.New("info"))
+	if err != nil {
+		t.Fatalf("NewNotificationClient: %v", err)
+	}
+
+	caller := NotificationCaller{UserID: "user1", Token: "token"}
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetUnreadCount(context.Background(), caller); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("health check: %v", err)
+	}
+
+	if got := newConns.Load(); got != 1 {
+		t.Fatalf("expected sequential requests to share one connection, got %d", got)
+	}
+}
+
+func TestNewHTTPTransportAppliesConfig(t *testing.T) {
+	transport := NewHTTPTransport(config.HTTPClientConfig{MaxIdleConns: 50, MaxIdleConnsPerHost: 7, IdleConnTimeoutSeconds: 15})
+
+	if transport.MaxIdleConns != 50 || transport.MaxIdleConnsPerHost != 7 || transport.IdleConnTimeout.Seconds() != 15 {
+		t.Fatalf("unexpected transport settings: idle=%d perHost=%d timeout=%v", transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+}
+
+func TestInternalTokenTransportAttachesToken(t *testing.T) {
+	var got string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(InternalTokenHeader)
+	}))
+	defer backend.Close()
+
+	client := &http.Client{Transport: InternalTokenTransport(nil, "secret-token")}
+	req, _ := http.NewRequest(http.MethodGet, backend.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+
+	if got != "secret-token" {
+		t.Fatalf("expected the internal token to be sent, got %q", got)
+	}
+	if req.Header.Get(InternalTokenHeader) != "" {
+		t.Fatal("expected the caller's request to be left untouched")
+	}
+}
@@ -0,0 +1,272 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"api-gateway/internal/models"
+	"api-gateway/pkg/logger"
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
+)
+
+const (
+	defaultNotificationTimeout = 10 * time.Second
+	notificationHealthTimeout  = 3 * time.Second
+	// maxNotificationResponseBytes bounds how much of a notification service
+	// response is read; list pages are capped at 100 items upstream.
+	maxNotificationResponseBytes = 4 << 20
+)
+
+// NotificationClient calls the notification service's HTTP API on behalf of an
+// authenticated user. The caller's bearer token is forwarded so the service
+// verifies the identity itself; X-User-ID and X-Request-ID are forwarded too.
+type NotificationClient struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	logger     *logger.Logger
+}
+
+// NotificationError is a non-2xx response from the notification service,
+// carrying the status and error code it returned.
+type NotificationError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *NotificationError) Error() string {
+	return fmt.Sprintf("notification service returned %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// NotificationCaller identifies the user a notification request is made for.
+type NotificationCaller struct {
+	UserID string
+	Token  string
+}
+
+type notificationEnvelope struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Data    json.RawMessage   `json:"data"`
+	Error   *models.ErrorData `json:"error"`
+}
+
+// NewNotificationClient creates a client for the notification service at
+// baseURL. transport is normally the gateway's shared pool (NewHTTPTransport);
+// nil uses http.DefaultTransport.
+func NewNotificationClient(baseURL string, transport http.RoundTripper, logger *logger.Logger) (*NotificationClient, error) {
+	target, err := url.Parse(strings.TrimSuffix(strings.TrimSpace(baseURL), "/"))
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid notification service URL %q", baseURL)
+	}
+
+	return &NotificationClient{
+		baseURL:    target,
+		httpClient: &http.Client{Transport: transport},
+		logger:     logger,
+	}, nil
+}
+
+// Transport returns the round tripper the client sends requests through, so
+// proxies to the same service can share its connection pool.
+func (c *NotificationClient) Transport() http.RoundTripper {
+	if c.httpClient.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.httpClient.Transport
+}
+
+// BaseURL returns the notification service's root URL.
+func (c *NotificationClient) BaseURL() *url.URL {
+	u := *c.baseURL
+	return &u
+}
+
+func (c *NotificationClient) ListNotifications(ctx context.Context, caller NotificationCaller, req models.ListNotificationsRequest) (*models.ListNotificationsResponse, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(req.Limit))
+	query.Set("offset", strconv.Itoa(req.Offset))
+	if req.Unread {
+		query.Set("unread", "true")
+	}
+	if req.IncludeArchived {
+		query.Set("include_archived", "true")
+	}
+	if req.Type != "" {
+		query.Set("type", req.Type)
+	}
+	if req.Priority != "" {
+		query.Set("priority", req.Priority)
+	}
+
+	var resp models.ListNotificationsResponse
+	if err := c.do(ctx, caller, http.MethodGet, "/api/v1/notifications", query, nil, &resp); err != nil {
+		return nil, c.wrapError("list notifications", err)
+	}
+	return &resp, nil
+}
+
+func (c *NotificationClient) GetNotification(ctx context.Context, caller NotificationCaller, id string) (*models.NotificationResponse, error) {
+	var resp models.NotificationResponse
+	if err := c.do(ctx, caller, http.MethodGet, "/api/v1/notifications/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, c.wrapError("get notification", err)
+	}
+	return &resp, nil
+}
+
+func (c *NotificationClient) MarkAsRead(ctx context.Context, caller NotificationCaller, req models.MarkNotificationsReadRequest) error {
+	if err := c.do(ctx, caller, http.MethodPut, "/api/v1/notifications/mark-read", nil, req, nil); err != nil {
+		return c.wrapError("mark notifications as read", err)
+	}
+	return nil
+}
+
+func (c *NotificationClient) GetUnreadCount(ctx context.Context, caller NotificationCaller) (int64, error) {
+	var resp models.UnreadCountResponse
+	if err := c.do(ctx, caller, http.MethodGet, "/api/v1/notifications/unread-count", nil, nil, &resp); err != nil {
+		return 0, c.wrapError("get unread notification count", err)
+	}
+	return resp.UnreadCount, nil
+}
+
+func (c *NotificationClient) GetCountsByType(ctx context.Context, caller NotificationCaller, req models.NotificationCountsRequest) (*models.NotificationCountsResponse, error) {
+	query := url.Values{}
+	query.Set("unread", strconv.FormatBool(req.Unread))
+
+	var resp models.NotificationCountsResponse
+	if err := c.do(ctx, caller, http.MethodGet, "/api/v1/notifications/counts", query, nil, &resp); err != nil {
+		return nil, c.wrapError("get notification counts", err)
+	}
+	return &resp, nil
+}
+
+func (c *NotificationClient) ArchiveNotification(ctx context.Context, caller NotificationCaller, id string) error {
+	if err := c.do(ctx, caller, http.MethodPost, "/api/v1/notifications/"+url.PathEscape(id)+"/archive", nil, nil, nil); err != nil {
+		return c.wrapError("archive notification", err)
+	}
+	return nil
+}
+
+func (c *NotificationClient) UnarchiveNotification(ctx context.Context, caller NotificationCaller, id string) error {
+	if err := c.do(ctx, caller, http.MethodPost, "/api/v1/notifications/"+url.PathEscape(id)+"/unarchive", nil, nil, nil); err != nil {
+		return c.wrapError("unarchive notification", err)
+	}
+	return nil
+}
+
+func (c *NotificationClient) DeleteNotification(ctx context.Context, caller NotificationCaller, id string) error {
+	if err := c.do(ctx, caller, http.MethodDelete, "/api/v1/notifications/"+url.PathEscape(id), nil, nil, nil); err != nil {
+		return c.wrapError("delete notification", err)
+	}
+	return nil
+}
+
+// HealthCheck calls the notification service's unauthenticated /ready endpoint,
+// which also verifies its database and RabbitMQ connections.
+func (c *NotificationClient) HealthCheck(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, notificationHealthTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint("/ready", nil), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return c.wrapError("notification health check", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxNotificationResponseBytes))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("notification health check: unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *NotificationClient) do(ctx context.Context, caller NotificationCaller, method, path string, query url.Values, body, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultNotificationTimeout)
+	defer cancel()
+
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint(path, query), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if caller.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+caller.Token)
+	}
+	if caller.UserID != "" {
+		req.Header.Set("X-User-ID", caller.UserID)
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope notificationEnvelope
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxNotificationResponseBytes)).Decode(&envelope); err != nil && err != io.EOF {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return &NotificationError{StatusCode: resp.StatusCode, Code: "NOTIFICATION_SERVICE_ERROR", Message: http.StatusText(resp.StatusCode)}
+		}
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		notificationErr := &NotificationError{StatusCode: resp.StatusCode, Code: "NOTIFICATION_SERVICE_ERROR", Message: http.StatusText(resp.StatusCode)}
+		if envelope.Error != nil {
+			notificationErr.Code = envelope.Error.Code
+			notificationErr.Message = envelope.Error.Message
+		}
+		return notificationErr
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return fmt.Errorf("decode response data: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *NotificationClient) endpoint(path string, query url.Values) string {
+	endpoint := c.baseURL.String() + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint
+}
+
+func (c *NotificationClient) wrapError(action string, err error) error {
+	if _, ok := err.(*NotificationError); ok {
+		return err
+	}
+	c.logger.Warn(fmt.Sprintf("Notification service call failed (%s): %v", action, err))
+	return fmt.Errorf("%s: %w", action, err)
+}
@@ -8,12 +8,14 @@ import (
 	"api-gateway/internal/config"
 	"api-gateway/internal/models"
 	"api-gateway/pkg/logger"
+	"api-gateway/pkg/tracing"
 
 	postv1 "github.com/nikitashilov/microblog_grpc/proto/post/v1"
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -27,23 +29,27 @@ type PostClient struct {
 }
 
 type CreatePostInput struct {
-	UserID    string `json:"-"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	Slug      string `json:"slug,omitempty"`
-	Published bool   `json:"published,omitempty"`
+	UserID        string `json:"-"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	Slug          string `json:"slug,omitempty"`
+	Published     bool   `json:"published,omitempty"`
+	CoverImageURL string `json:"cover_image_url,omitempty"`
+	Excerpt       string `json:"excerpt,omitempty"`
 }
 
 type UpdatePostInput struct {
-	ID        string  `json:"-"`
-	UserID    string  `json:"-"`
-	Title     *string `json:"title,omitempty"`
-	Content   *string `json:"content,omitempty"`
-	Slug      *string `json:"slug,omitempty"`
-	Published *bool   `json:"published,omitempty"`
+	ID            string  `json:"-"`
+	UserID        string  `json:"-"`
+	Title         *string `json:"title,omitempty"`
+	Content       *string `json:"content,omitempty"`
+	Slug          *string `json:"slug,omitempty"`
+	Published     *bool   `json:"published,omitempty"`
+	CoverImageURL *string `json:"cover_image_url,omitempty"`
+	Excerpt       *string `json:"excerpt,omitempty"`
 }
 
-func NewPostClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Logger) (*PostClient, error) {
+func NewPostClient(addr string, tlsCfg config.GRPCTLSConfig, retryCfg config.GRPCRetryConfig, logger *logger.Logger) (*PostClient, error) {
 	creds, err := buildClientTransportCredentials(tlsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("build post client transport credentials: %w", err)
@@ -57,7 +63,12 @@ func NewPostClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Logg
 			Timeout:             keepaliveTimeout,
 			PermitWithoutStream: keepalivePermitWithoutStream,
 		}),
-		grpc.WithUnaryInterceptor(unaryClientLoggingInterceptor(logger)),
+		grpc.WithChainUnaryInterceptor(
+			tracing.UnaryClientInterceptor(),
+			requestid.UnaryClientInterceptor(),
+			unaryClientLoggingInterceptor(logger),
+			unaryClientRetryInterceptor(retryCfg, logger),
+		),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("connect to post gRPC service: %w", err)
@@ -79,11 +90,13 @@ func (c *PostClient) CreatePost(ctx context.Context, input *CreatePostInput) (*m
 	defer cancel()
 
 	req := &postv1.CreatePostRequest{
-		UserId:    input.UserID,
-		Title:     input.Title,
-		Content:   input.Content,
-		Slug:      input.Slug,
-		Published: input.Published,
+		UserId:        input.UserID,
+		Title:         input.Title,
+		Content:       input.Content,
+		Slug:          input.Slug,
+		Published:     input.Published,
+		CoverImageUrl: input.CoverImageURL,
+		Excerpt:       input.Excerpt,
 	}
 
 	resp, err := c.client.CreatePost(ctx, req)
@@ -94,11 +107,11 @@ func (c *PostClient) CreatePost(ctx context.Context, input *CreatePostInput) (*m
 	return postFromProto(resp), nil
 }
 
-func (c *PostClient) GetPost(ctx context.Context, id, requestingUserID string) (*models.PostResponse, error) {
+func (c *PostClient) GetPost(ctx context.Context, id, requestingUserID string, renderHTML bool) (*models.PostResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
 	defer cancel()
 
-	req := &postv1.GetPostRequest{Id: id, RequestingUserId: requestingUserID}
+	req := &postv1.GetPostRequest{Id: id, RequestingUserId: requestingUserID, RenderHtml: renderHTML}
 	resp, err := c.client.GetPost(ctx, req)
 	if err != nil {
 		return nil, c.wrapError("get post", err)
@@ -107,11 +120,14 @@ func (c *PostClient) GetPost(ctx context.Context, id, requestingUserID string) (
 	return postFromProto(resp), nil
 }
 
-func (c *PostClient) GetPostBySlug(ctx context.Context, slug string) (*models.PostResponse, error) {
+// GetPostBySlug returns a published post by slug; when requestingUserID is set,
+// that user's own drafts are found too.
+func (c *PostClient) GetPostBySlug(ctx context.Context, slug, requestingUserID string, renderHTML bool) (*models.PostResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
 	defer cancel()
 
-	resp, err := c.client.GetPostBySlug(ctx, &postv1.GetPostBySlugRequest{Slug: slug})
+	req := &postv1.GetPostBySlugRequest{Slug: slug, RequestingUserId: requestingUserID, RenderHtml: renderHTML}
+	resp, err := c.client.GetPostBySlug(ctx, req)
 	if err != nil {
 		return nil, c.wrapError("get post by slug", err)
 	}
@@ -144,6 +160,12 @@ func (c *PostClient) UpdatePost(ctx context.Context, input *UpdatePostInput) (*m
 	if input.Published != nil {
 		req.Published = wrapperspb.Bool(*input.Published)
 	}
+	if input.CoverImageURL != nil {
+		req.CoverImageUrl = wrapperspb.String(*input.CoverImageURL)
+	}
+	if input.Excerpt != nil {
+		req.Excerpt = wrapperspb.String(*input.Excerpt)
+	}
 
 	resp, err := c.client.UpdatePost(ctx, req)
 	if err != nil {
@@ -164,11 +186,125 @@ func (c *PostClient) DeletePost(ctx context.Context, id, userID string) error {
 	return nil
 }
 
-func (c *PostClient) ListPosts(ctx context.Context, limit, offset int, publishedOnly bool) (*models.ListPostsResponse, error) {
+// DeletePosts deletes those of ids that userID owns, skipping the rest.
+func (c *PostClient) DeletePosts(ctx context.Context, ids []string, userID string) (*models.BulkDeletePostsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
+	defer cancel()
+
+	req := &postv1.DeletePostsRequest{Ids: ids, UserId: userID}
+	resp, err := c.client.DeletePosts(ctx, req)
+	if err != nil {
+		return nil, c.wrapError("delete posts", err)
+	}
+
+	results := make([]models.BulkDeleteResult, 0, len(resp.GetResults()))
+	for _, result := range resp.GetResults() {
+		results = append(results, models.BulkDeleteResult{ID: result.GetId(), Deleted: result.GetDeleted()})
+	}
+	return &models.BulkDeletePostsResponse{Results: results, Deleted: int(resp.GetDeleted())}, nil
+}
+
+// ArchivePost retires the owner's post without deleting it.
+func (c *PostClient) ArchivePost(ctx context.Context, id, userID string) (*models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
+	defer cancel()
+
+	resp, err := c.client.ArchivePost(ctx, &postv1.ArchivePostRequest{Id: id, UserId: userID})
+	if err != nil {
+		return nil, c.wrapError("archive post", err)
+	}
+
+	return postFromProto(resp), nil
+}
+
+// LikePost likes a post for userID. Liking it again changes nothing.
+func (c *PostClient) LikePost(ctx context.Context, id, userID string) (*models.PostLikeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
+	defer cancel()
+
+	resp, err := c.client.LikePost(ctx, &postv1.LikePostRequest{Id: id, UserId: userID})
+	if err != nil {
+		return nil, c.wrapError("like post", err)
+	}
+
+	return likeStateFromProto(resp), nil
+}
+
+// UnlikePost removes userID's like of a post, if there is one.
+func (c *PostClient) UnlikePost(ctx context.Context, id, userID string) (*models.PostLikeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
+	defer cancel()
+
+	resp, err := c.client.UnlikePost(ctx, &postv1.LikePostRequest{Id: id, UserId: userID})
+	if err != nil {
+		return nil, c.wrapError("unlike post", err)
+	}
+
+	return likeStateFromProto(resp), nil
+}
+
+// ClonePost copies the owner's post into a new unpublished draft.
+func (c *PostClient) ClonePost(ctx context.Context, id, userID string) (*models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
+	defer cancel()
+
+	resp, err := c.client.ClonePost(ctx, &postv1.ClonePostRequest{Id: id, UserId: userID})
+	if err != nil {
+		return nil, c.wrapError("clone post", err)
+	}
+
+	return postFromProto(resp), nil
+}
+
+// CreatePreviewToken issues a draft preview link token for the owner's post,
+// revoking any earlier one.
+func (c *PostClient) CreatePreviewToken(ctx context.Context, id, userID string, expiresInHours int) (*models.PreviewTokenResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
+	defer cancel()
+
+	req := &postv1.CreatePreviewTokenRequest{Id: id, UserId: userID, ExpiresInHours: int32(expiresInHours)}
+	resp, err := c.client.CreatePreviewToken(ctx, req)
+	if err != nil {
+		return nil, c.wrapError("create preview token", err)
+	}
+
+	token := &models.PreviewTokenResponse{PostID: resp.GetPostId(), Token: resp.GetToken()}
+	if resp.GetExpiresAt() != nil {
+		expiresAt := resp.GetExpiresAt().AsTime()
+		token.ExpiresAt = &expiresAt
+	}
+	return token, nil
+}
+
+func (c *PostClient) RevokePreviewToken(ctx context.Context, id, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
+	defer cancel()
+
+	req := &postv1.RevokePreviewTokenRequest{Id: id, UserId: userID}
+	if _, err := c.client.RevokePreviewToken(ctx, req); err != nil {
+		return c.wrapError("revoke preview token", err)
+	}
+	return nil
+}
+
+func (c *PostClient) GetPostByPreviewToken(ctx context.Context, token string, renderHTML bool) (*models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
+	defer cancel()
+
+	resp, err := c.client.GetPostByPreviewToken(ctx, &postv1.GetPostByPreviewTokenRequest{Token: token, RenderHtml: renderHTML})
+	if err != nil {
+		return nil, c.wrapError("get post by preview token", err)
+	}
+
+	return postFromProto(resp), nil
+}
+
+func (c *PostClient) ListPosts(ctx context.Context, limit, offset int, publishedOnly bool, createdIn models.DateRange) (*models.ListPostsResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
 	defer cancel()
 
 	req := &postv1.ListPostsRequest{Limit: int32(limit), Offset: int32(offset), PublishedOnly: publishedOnly}
+	req.From, req.To = dateRangeToProto(createdIn)
 	resp, err := c.client.ListPosts(ctx, req)
 	if err != nil {
 		return nil, c.wrapError("list posts", err)
@@ -177,11 +313,19 @@ func (c *PostClient) ListPosts(ctx context.Context, limit, offset int, published
 	return listPostsFromProto(resp), nil
 }
 
-func (c *PostClient) GetUserPosts(ctx context.Context, userID string, limit, offset int) (*models.ListPostsResponse, error) {
+// GetUserPosts lists userID's posts with status, published when empty. Other
+// statuses are only listed when requestingUserID is userID.
+func (c *PostClient) GetUserPosts(ctx context.Context, userID, status, requestingUserID string, limit, offset int) (*models.ListPostsResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
 	defer cancel()
 
-	req := &postv1.GetUserPostsRequest{UserId: userID, Limit: int32(limit), Offset: int32(offset)}
+	req := &postv1.GetUserPostsRequest{
+		UserId:           userID,
+		Limit:            int32(limit),
+		Offset:           int32(offset),
+		Status:           status,
+		RequestingUserId: requestingUserID,
+	}
 	resp, err := c.client.GetUserPosts(ctx, req)
 	if err != nil {
 		return nil, c.wrapError("get user posts", err)
@@ -190,11 +334,12 @@ func (c *PostClient) GetUserPosts(ctx context.Context, userID string, limit, off
 	return listPostsFromProto(resp), nil
 }
 
-func (c *PostClient) SearchPosts(ctx context.Context, query string, limit, offset int, publishedOnly bool) (*models.ListPostsResponse, error) {
+func (c *PostClient) SearchPosts(ctx context.Context, query string, limit, offset int, publishedOnly bool, createdIn models.DateRange) (*models.ListPostsResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultPostTimeout)
 	defer cancel()
 
 	req := &postv1.SearchPostsRequest{Query: query, Limit: int32(limit), Offset: int32(offset), PublishedOnly: publishedOnly}
+	req.From, req.To = dateRangeToProto(createdIn)
 	resp, err := c.client.SearchPosts(ctx, req)
 	if err != nil {
 		return nil, c.wrapError("search posts", err)
@@ -215,6 +360,8 @@ func (c *PostClient) GetStats(ctx context.Context, userID string) (*models.PostS
 	return &models.PostStatsResponse{
 		TotalPublishedPosts: resp.GetTotalPublishedPosts(),
 		UserPostsCount:      resp.GetUserPostsCount(),
+		UserDraftCount:      resp.GetUserDraftCount(),
+		UserScheduledCount:  resp.GetUserScheduledCount(),
 	}, nil
 }
 
@@ -240,8 +387,8 @@ func (c *PostClient) wrapError(action string, err error) error {
 		return nil
 	}
 
-	if st, ok := status.FromError(err); ok {
-		return status.Errorf(st.Code(), "%s: %s", action, st.Message())
+	if clientErr := newClientError(action, err); clientErr != nil {
+		return clientErr
 	}
 
 	return fmt.Errorf("%s: %w", action, err)
@@ -252,15 +399,32 @@ func postFromProto(p *postv1.Post) *models.PostResponse {
 		return nil
 	}
 
-	return &models.PostResponse{
-		ID:        p.GetId(),
-		UserID:    p.GetUserId(),
-		Title:     p.GetTitle(),
-		Content:   p.GetContent(),
-		Slug:      p.GetSlug(),
-		Published: p.GetPublished(),
-		CreatedAt: timestampToTime(p.GetCreatedAt()),
-		UpdatedAt: timestampToTime(p.GetUpdatedAt()),
+	post := &models.PostResponse{
+		ID:            p.GetId(),
+		UserID:        p.GetUserId(),
+		Title:         p.GetTitle(),
+		Content:       p.GetContent(),
+		Slug:          p.GetSlug(),
+		Status:        p.GetStatus(),
+		Published:     p.GetPublished(),
+		CoverImageURL: p.GetCoverImageUrl(),
+		CreatedAt:     timestampToTime(p.GetCreatedAt()),
+		UpdatedAt:     timestampToTime(p.GetUpdatedAt()),
+		ContentHTML:   p.GetContentHtml(),
+		LikeCount:     p.GetLikeCount(),
+	}
+	if p.GetLikedByMe() != nil {
+		likedByMe := p.GetLikedByMe().GetValue()
+		post.LikedByMe = &likedByMe
+	}
+	return post
+}
+
+func likeStateFromProto(s *postv1.PostLikeState) *models.PostLikeResponse {
+	return &models.PostLikeResponse{
+		PostID:    s.GetPostId(),
+		LikeCount: s.GetLikeCount(),
+		LikedByMe: s.GetLikedByMe(),
 	}
 }
 
@@ -270,14 +434,27 @@ func summaryFromProto(s *postv1.PostSummary) *models.PostSummaryResponse {
 	}
 
 	return &models.PostSummaryResponse{
-		ID:        s.GetId(),
-		UserID:    s.GetUserId(),
-		Title:     s.GetTitle(),
-		Slug:      s.GetSlug(),
-		Published: s.GetPublished(),
-		CreatedAt: timestampToTime(s.GetCreatedAt()),
-		UpdatedAt: timestampToTime(s.GetUpdatedAt()),
+		ID:            s.GetId(),
+		UserID:        s.GetUserId(),
+		Title:         s.GetTitle(),
+		Slug:          s.GetSlug(),
+		Status:        s.GetStatus(),
+		Published:     s.GetPublished(),
+		CoverImageURL: s.GetCoverImageUrl(),
+		Excerpt:       s.GetExcerpt(),
+		CreatedAt:     timestampToTime(s.GetCreatedAt()),
+		UpdatedAt:     timestampToTime(s.GetUpdatedAt()),
+	}
+}
+
+func dateRangeToProto(createdIn models.DateRange) (from, to *timestamppb.Timestamp) {
+	if createdIn.From != nil {
+		from = timestamppb.New(*createdIn.From)
+	}
+	if createdIn.To != nil {
+		to = timestamppb.New(*createdIn.To)
 	}
+	return from, to
 }
 
 func listPostsFromProto(resp *postv1.ListPostsResponse) *models.ListPostsResponse {
@@ -2,6 +2,9 @@ package clients
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand"
 	"time"
 
 	"api-gateway/internal/config"
@@ -9,6 +12,10 @@ import (
 	"github.com/go-redis/redis/v8"
 )
 
+// ErrRedisUnavailable wraps failures to reach Redis or run a command on it,
+// as opposed to the caller's context ending first. Test for it with errors.Is.
+var ErrRedisUnavailable = errors.New("redis unavailable")
+
 type RedisClient struct {
 	client *redis.Client
 }
@@ -31,6 +38,11 @@ func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, ex
 	return r.client.Set(ctx, key, value, expiration).Err()
 }
 
+// SetNX sets key only if it does not exist and reports whether it did.
+func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, expiration).Result()
+}
+
 func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
 	return r.client.Incr(ctx, key).Result()
 }
@@ -47,6 +59,65 @@ func (r *RedisClient) Del(ctx context.Context, keys ...string) error {
 	return r.client.Del(ctx, keys...).Err()
 }
 
+// slidingWindowScript records a request in a sorted set of request timestamps
+// (ms) if fewer than limit fall inside the window ending now. It returns
+// {allowed, remaining, reset_ms}, where reset_ms is when the oldest request in
+// the window expires and frees a slot.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', key, now, ARGV[4])
+  count = count + 1
+  allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local reset = now + window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+  reset = tonumber(oldest[2]) + window
+end
+return {allowed, limit - count, reset}
+`)
+
+// SlidingWindowResult is the outcome of SlidingWindowAllow.
+type SlidingWindowResult struct {
+	Allowed   bool
+	Remaining int
+	ResetAt   time.Time // when the next slot frees up
+}
+
+// SlidingWindowAllow admits a request under key if fewer than limit requests
+// were admitted in the window ending at now. The check and the insert run as
+// one script, so concurrent gateways cannot overshoot the limit. Errors
+// reaching Redis wrap ErrRedisUnavailable.
+func (r *RedisClient) SlidingWindowAllow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (SlidingWindowResult, error) {
+	nowMs := now.UnixMilli()
+	member := fmt.Sprintf("%d-%d", nowMs, rand.Int63())
+	values, err := slidingWindowScript.Run(ctx, r.client, []string{key}, nowMs, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		if ctx.Err() != nil {
+			return SlidingWindowResult{}, ctx.Err()
+		}
+		return SlidingWindowResult{}, fmt.Errorf("%w: %v", ErrRedisUnavailable, err)
+	}
+	if len(values) != 3 {
+		return SlidingWindowResult{}, fmt.Errorf("sliding window script returned %d values", len(values))
+	}
+	return SlidingWindowResult{
+		Allowed:   values[0] == 1,
+		Remaining: int(values[1]),
+		ResetAt:   time.UnixMilli(values[2]),
+	}, nil
+}
+
 func (r *RedisClient) Close() error {
 	return r.client.Close()
 }
@@ -0,0 +1,116 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+
+	authv1 "github.com/nikitashilov/microblog_grpc/proto/auth/v1"
+	postv1 "github.com/nikitashilov/microblog_grpc/proto/post/v1"
+	searchv1 "github.com/nikitashilov/microblog_grpc/proto/search/v1"
+	userv1 "github.com/nikitashilov/microblog_grpc/proto/user/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// idempotentMethods lists the read-only RPCs that are safe to retry. Anything
+// that creates, mutates or consumes state (login, token refresh, auth code
+// exchange, OAuth state generation, credential checks that count failures)
+// is deliberately absent and is never retried automatically.
+var idempotentMethods = map[string]bool{
+	authv1.AuthService_ValidateToken_FullMethodName:    true,
+	authv1.AuthService_IntrospectTokens_FullMethodName: true,
+	authv1.AuthService_ListSessions_FullMethodName:     true,
+	authv1.AuthService_HealthCheck_FullMethodName:      true,
+
+	userv1.UserService_GetUser_FullMethodName:           true,
+	userv1.UserService_GetUserByEmail_FullMethodName:    true,
+	userv1.UserService_GetUserByUsername_FullMethodName: true,
+	userv1.UserService_GetUserProfile_FullMethodName:    true,
+	userv1.UserService_GetUsersBatch_FullMethodName:     true,
+	userv1.UserService_ListUsers_FullMethodName:         true,
+	userv1.UserService_SearchUsers_FullMethodName:       true,
+	userv1.UserService_GetFollowers_FullMethodName:      true,
+	userv1.UserService_GetFollowing_FullMethodName:      true,
+	userv1.UserService_AreFollowed_FullMethodName:       true,
+	userv1.UserService_GetStats_FullMethodName:          true,
+	userv1.UserService_HealthCheck_FullMethodName:       true,
+
+	postv1.PostService_GetPost_FullMethodName:               true,
+	postv1.PostService_GetPostBySlug_FullMethodName:         true,
+	postv1.PostService_GetPostByPreviewToken_FullMethodName: true,
+	postv1.PostService_GetUserPosts_FullMethodName:          true,
+	postv1.PostService_ListPosts_FullMethodName:             true,
+	postv1.PostService_SearchPosts_FullMethodName:           true,
+	postv1.PostService_GetStats_FullMethodName:              true,
+	postv1.PostService_HealthCheck_FullMethodName:           true,
+
+	searchv1.SearchService_Search_FullMethodName:      true,
+	searchv1.SearchService_HealthCheck_FullMethodName: true,
+}
+
+// isRetryableCode reports whether a failed call may succeed on another attempt:
+// the connection could not be used (Unavailable) or the server failed with an
+// internal error.
+func isRetryableCode(code codes.Code) bool {
+	return code == codes.Unavailable || code == codes.Internal
+}
+
+// unaryClientRetryInterceptor retries idempotent RPCs that fail with a
+// retryable code, backing off exponentially with jitter between attempts.
+// Retries never outlive the caller's context: an attempt is skipped when the
+// backoff would run past the deadline.
+func unaryClientRetryInterceptor(cfg config.GRPCRetryConfig, logger *logger.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if cfg.MaxAttempts <= 1 || !idempotentMethods[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		var err error
+		for attempt := 1; ; attempt++ {
+			err = invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= cfg.MaxAttempts || !isRetryableCode(status.Code(err)) {
+				return err
+			}
+
+			wait := retryBackoff(cfg, attempt)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+				return err
+			}
+
+			logger.Debug(fmt.Sprintf("gRPC call %s failed with %s, retrying in %v (attempt %d/%d)", method, status.Code(err), wait, attempt+1, cfg.MaxAttempts))
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// retryBackoff returns the delay before the attempt following the given one:
+// InitialBackoffMs doubled per attempt, capped at MaxBackoffMs, with the upper
+// half randomised so that concurrent callers do not retry in lockstep.
+func retryBackoff(cfg config.GRPCRetryConfig, attempt int) time.Duration {
+	backoff := time.Duration(cfg.InitialBackoffMs) * time.Millisecond
+	maxBackoff := time.Duration(cfg.MaxBackoffMs) * time.Millisecond
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
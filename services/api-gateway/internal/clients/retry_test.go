@@ -0,0 +1,126 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+
+	postv1 "github.com/nikitashilov/microblog_grpc/proto/post/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingInvoker fails with the given codes in order, then succeeds.
+func failingInvoker(calls *int, failures ...codes.Code) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= len(failures) {
+			return status.Error(failures[*calls-1], "boom")
+		}
+		return nil
+	}
+}
+
+func testRetryConfig() config.GRPCRetryConfig {
+	return config.GRPCRetryConfig{MaxAttempts: 3, InitialBackoffMs: 1, MaxBackoffMs: 2}
+}
+
+func TestRetryInterceptorRetriesIdempotentCalls(t *testing.T) {
+	interceptor := unaryClientRetryInterceptor(testRetryConfig(), logger.New("info"))
+
+	calls := 0
+	err := interceptor(context.Background(), postv1.PostService_GetPost_FullMethodName, nil, nil, nil,
+		failingInvoker(&calls, codes.Unavailable, codes.Internal))
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryInterceptorStopsAtMaxAttempts(t *testing.T) {
+	interceptor := unaryClientRetryInterceptor(testRetryConfig(), logger.New("info"))
+
+	calls := 0
+	err := interceptor(context.Background(), postv1.PostService_ListPosts_FullMethodName, nil, nil, nil,
+		failingInvoker(&calls, codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.Unavailable))
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryInterceptorNeverRetriesWrites(t *testing.T) {
+	interceptor := unaryClientRetryInterceptor(testRetryConfig(), logger.New("info"))
+
+	for _, method := range []string{
+		postv1.PostService_CreatePost_FullMethodName,
+		postv1.PostService_UpdatePost_FullMethodName,
+		postv1.PostService_DeletePost_FullMethodName,
+	} {
+		calls := 0
+		err := interceptor(context.Background(), method, nil, nil, nil, failingInvoker(&calls, codes.Unavailable))
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("%s: expected Unavailable, got %v", method, err)
+		}
+		if calls != 1 {
+			t.Errorf("%s: expected a single attempt, got %d", method, calls)
+		}
+	}
+}
+
+func TestRetryInterceptorSkipsNonRetryableCodes(t *testing.T) {
+	interceptor := unaryClientRetryInterceptor(testRetryConfig(), logger.New("info"))
+
+	calls := 0
+	err := interceptor(context.Background(), postv1.PostService_GetPost_FullMethodName, nil, nil, nil,
+		failingInvoker(&calls, codes.NotFound))
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestRetryInterceptorRespectsDeadline(t *testing.T) {
+	cfg := config.GRPCRetryConfig{MaxAttempts: 5, InitialBackoffMs: 500, MaxBackoffMs: 1000}
+	interceptor := unaryClientRetryInterceptor(cfg, logger.New("info"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := interceptor(ctx, postv1.PostService_GetPost_FullMethodName, nil, nil, nil,
+		failingInvoker(&calls, codes.Unavailable, codes.Unavailable))
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry past the deadline, got %d attempts", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("expected to give up immediately, took %v", elapsed)
+	}
+}
+
+func TestRetryBackoffIsCapped(t *testing.T) {
+	cfg := config.GRPCRetryConfig{MaxAttempts: 10, InitialBackoffMs: 100, MaxBackoffMs: 400}
+	for attempt := 1; attempt <= 8; attempt++ {
+		wait := retryBackoff(cfg, attempt)
+		if wait > 400*time.Millisecond {
+			t.Fatalf("attempt %d: backoff %v exceeds the cap", attempt, wait)
+		}
+		if wait < 50*time.Millisecond {
+			t.Fatalf("attempt %d: backoff %v below half the initial delay", attempt, wait)
+		}
+	}
+}
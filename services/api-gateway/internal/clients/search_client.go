@@ -7,7 +7,9 @@ import (
 
 	"api-gateway/internal/config"
 	"api-gateway/pkg/logger"
+	"api-gateway/pkg/tracing"
 
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	searchv1 "github.com/nikitashilov/microblog_grpc/proto/search/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
@@ -22,7 +24,7 @@ type SearchClient struct {
 	logger *logger.Logger
 }
 
-func NewSearchClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Logger) (*SearchClient, error) {
+func NewSearchClient(addr string, tlsCfg config.GRPCTLSConfig, retryCfg config.GRPCRetryConfig, logger *logger.Logger) (*SearchClient, error) {
 	creds, err := buildClientTransportCredentials(tlsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("build search client transport credentials: %w", err)
@@ -36,7 +38,12 @@ func NewSearchClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Lo
 			Timeout:             keepaliveTimeout,
 			PermitWithoutStream: keepalivePermitWithoutStream,
 		}),
-		grpc.WithUnaryInterceptor(unaryClientLoggingInterceptor(logger)),
+		grpc.WithChainUnaryInterceptor(
+			tracing.UnaryClientInterceptor(),
+			requestid.UnaryClientInterceptor(),
+			unaryClientLoggingInterceptor(logger),
+			unaryClientRetryInterceptor(retryCfg, logger),
+		),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("connect to search gRPC service: %w", err)
@@ -48,7 +55,7 @@ func NewSearchClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Lo
 	}, nil
 }
 
-func (c *SearchClient) Search(ctx context.Context, query, requestingUserID string, usersLimit, postsLimit int32, usersCursor, postsCursor string) (*searchv1.SearchResponse, error) {
+func (c *SearchClient) Search(ctx context.Context, query, requestingUserID string, usersLimit, postsLimit int32, usersCursor, postsCursor string, includeContent bool) (*searchv1.SearchResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultSearchTimeout)
 	defer cancel()
 	return c.client.Search(ctx, &searchv1.SearchRequest{
@@ -58,6 +65,7 @@ func (c *SearchClient) Search(ctx context.Context, query, requestingUserID strin
 		PostsLimit:       postsLimit,
 		UsersCursor:      usersCursor,
 		PostsCursor:      postsCursor,
+		IncludeContent:   includeContent,
 	})
 }
 
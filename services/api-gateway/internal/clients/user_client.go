@@ -8,11 +8,12 @@ import (
 	"api-gateway/internal/config"
 	"api-gateway/internal/models"
 	"api-gateway/pkg/logger"
+	"api-gateway/pkg/tracing"
 
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	userv1 "github.com/nikitashilov/microblog_grpc/proto/user/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -28,10 +29,39 @@ type UserClient struct {
 }
 
 type CreateUserInput struct {
-	ID      string `json:"-"` // only from JWT in api-gateway handler; client cannot choose id
-	Email   string `json:"email" binding:"required"`
-	Name    string `json:"name" binding:"required"`
-	Picture string `json:"picture,omitempty"`
+	ID       string `json:"-"` // only from JWT in api-gateway handler; client cannot choose id
+	Email    string `json:"email" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Username string `json:"username,omitempty"`
+	Picture  string `json:"picture,omitempty"`
+}
+
+// ListUsersInput pages, orders and filters ListUsers and ListAllUsers. The
+// user service validates Sort and Order.
+type ListUsersInput struct {
+	Limit         int
+	Offset        int
+	Sort          string
+	Order         string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+func (in ListUsersInput) toProto(includeInactive bool) *userv1.ListUsersRequest {
+	req := &userv1.ListUsersRequest{
+		Limit:           int32(in.Limit),
+		Offset:          int32(in.Offset),
+		Sort:            in.Sort,
+		Order:           in.Order,
+		IncludeInactive: includeInactive,
+	}
+	if in.CreatedAfter != nil {
+		req.CreatedAfter = timestamppb.New(*in.CreatedAfter)
+	}
+	if in.CreatedBefore != nil {
+		req.CreatedBefore = timestamppb.New(*in.CreatedBefore)
+	}
+	return req
 }
 
 type UpdateUserInput struct {
@@ -42,9 +72,10 @@ type UpdateUserInput struct {
 	Bio      *string `json:"bio,omitempty"`
 	Location *string `json:"location,omitempty"`
 	Website  *string `json:"website,omitempty"`
+	Username *string `json:"username,omitempty"`
 }
 
-func NewUserClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Logger) (*UserClient, error) {
+func NewUserClient(addr string, tlsCfg config.GRPCTLSConfig, retryCfg config.GRPCRetryConfig, logger *logger.Logger) (*UserClient, error) {
 	creds, err := buildClientTransportCredentials(tlsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("build user client transport credentials: %w", err)
@@ -58,7 +89,12 @@ func NewUserClient(addr string, tlsCfg config.GRPCTLSConfig, logger *logger.Logg
 			Timeout:             keepaliveTimeout,
 			PermitWithoutStream: keepalivePermitWithoutStream,
 		}),
-		grpc.WithUnaryInterceptor(unaryClientLoggingInterceptor(logger)),
+		grpc.WithChainUnaryInterceptor(
+			tracing.UnaryClientInterceptor(),
+			requestid.UnaryClientInterceptor(),
+			unaryClientLoggingInterceptor(logger),
+			unaryClientRetryInterceptor(retryCfg, logger),
+		),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("connect to user gRPC service: %w", err)
@@ -80,10 +116,11 @@ func (c *UserClient) CreateUser(ctx context.Context, input *CreateUserInput) (*m
 	defer cancel()
 
 	req := &userv1.CreateUserRequest{
-		Id:      input.ID,
-		Email:   input.Email,
-		Name:    input.Name,
-		Picture: input.Picture,
+		Id:       input.ID,
+		Email:    input.Email,
+		Name:     input.Name,
+		Picture:  input.Picture,
+		Username: input.Username,
 	}
 
 	resp, err := c.client.CreateUser(ctx, req)
@@ -118,6 +155,18 @@ func (c *UserClient) GetUserProfile(ctx context.Context, id string) (*models.Use
 	return userProfileFromProto(resp), nil
 }
 
+func (c *UserClient) GetUserByUsername(ctx context.Context, username string) (*models.UserProfileResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	resp, err := c.client.GetUserByUsername(ctx, &userv1.GetUserByUsernameRequest{Username: username})
+	if err != nil {
+		return nil, c.wrapError("get user by username", err)
+	}
+
+	return userProfileFromProto(resp), nil
+}
+
 func (c *UserClient) UpdateUser(ctx context.Context, input *UpdateUserInput) (*models.UserResponse, error) {
 	if input == nil {
 		return nil, fmt.Errorf("update user input is required")
@@ -146,6 +195,9 @@ func (c *UserClient) UpdateUser(ctx context.Context, input *UpdateUserInput) (*m
 	if input.Website != nil {
 		req.Website = wrapperspb.String(*input.Website)
 	}
+	if input.Username != nil {
+		req.Username = wrapperspb.String(*input.Username)
+	}
 
 	resp, err := c.client.UpdateUser(ctx, req)
 	if err != nil {
@@ -155,11 +207,13 @@ func (c *UserClient) UpdateUser(ctx context.Context, input *UpdateUserInput) (*m
 	return userFromProto(resp), nil
 }
 
-func (c *UserClient) DeleteUser(ctx context.Context, id, actorID string) error {
+// DeleteUser deletes id on behalf of actorID. An actorRole of "admin" lets the
+// user service delete accounts other than the actor's own.
+func (c *UserClient) DeleteUser(ctx context.Context, id, actorID, actorRole string) error {
 	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
 	defer cancel()
 
-	req := &userv1.DeleteUserRequest{Id: id, ActorId: actorID}
+	req := &userv1.DeleteUserRequest{Id: id, ActorId: actorID, ActorRole: actorRole}
 	if _, err := c.client.DeleteUser(ctx, req); err != nil {
 		return c.wrapError("delete user", err)
 	}
@@ -167,12 +221,57 @@ func (c *UserClient) DeleteUser(ctx context.Context, id, actorID string) error {
 	return nil
 }
 
-func (c *UserClient) ListUsers(ctx context.Context, limit, offset int) (*models.ListUsersResponse, error) {
+// DeactivateUser hides id until it is reactivated. Only the account owner or
+// an admin actor may do this.
+func (c *UserClient) DeactivateUser(ctx context.Context, id, actorID, actorRole string) error {
 	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
 	defer cancel()
 
-	req := &userv1.ListUsersRequest{Limit: int32(limit), Offset: int32(offset)}
-	resp, err := c.client.ListUsers(ctx, req)
+	req := &userv1.DeactivateUserRequest{Id: id, ActorId: actorID, ActorRole: actorRole}
+	if _, err := c.client.DeactivateUser(ctx, req); err != nil {
+		return c.wrapError("deactivate user", err)
+	}
+
+	return nil
+}
+
+// ReactivateUser restores a deactivated id. Only the account owner or an
+// admin actor may do this.
+func (c *UserClient) ReactivateUser(ctx context.Context, id, actorID, actorRole string) (*models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	req := &userv1.ReactivateUserRequest{Id: id, ActorId: actorID, ActorRole: actorRole}
+	resp, err := c.client.ReactivateUser(ctx, req)
+	if err != nil {
+		return nil, c.wrapError("reactivate user", err)
+	}
+
+	return userFromProto(resp), nil
+}
+
+// GetUsersBatch resolves public profiles for up to 200 distinct IDs in one call.
+func (c *UserClient) GetUsersBatch(ctx context.Context, ids []string) (*models.UsersBatchResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	resp, err := c.client.GetUsersBatch(ctx, &userv1.GetUsersBatchRequest{Ids: ids})
+	if err != nil {
+		return nil, c.wrapError("get users batch", err)
+	}
+
+	users := make(map[string]*models.UserProfileResponse, len(resp.GetUsers()))
+	for id, profile := range resp.GetUsers() {
+		users[id] = userProfileFromProto(profile)
+	}
+	return &models.UsersBatchResponse{Users: users}, nil
+}
+
+func (c *UserClient) ListUsers(ctx context.Context, input ListUsersInput) (*models.ListUsersResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	resp, err := c.client.ListUsers(ctx, input.toProto(false))
 	if err != nil {
 		return nil, c.wrapError("list users", err)
 	}
@@ -180,6 +279,73 @@ func (c *UserClient) ListUsers(ctx context.Context, limit, offset int) (*models.
 	return listUsersFromProto(resp), nil
 }
 
+// VerifyEmail marks id's email verified on behalf of an admin actor.
+func (c *UserClient) VerifyEmail(ctx context.Context, id, actorID, actorRole string) (*models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	req := &userv1.VerifyEmailRequest{Id: id, ActorId: actorID, ActorRole: actorRole}
+	resp, err := c.client.VerifyEmail(ctx, req)
+	if err != nil {
+		return nil, c.wrapError("verify email", err)
+	}
+
+	return userFromProto(resp), nil
+}
+
+// ChangeEmail starts moving id to email; the user service only accepts it
+// from id itself.
+func (c *UserClient) ChangeEmail(ctx context.Context, id, actorID, email string) (*models.ChangeEmailResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	req := &userv1.ChangeEmailRequest{Id: id, ActorId: actorID, Email: email}
+	resp, err := c.client.ChangeEmail(ctx, req)
+	if err != nil {
+		return nil, c.wrapError("change email", err)
+	}
+
+	return &models.ChangeEmailResponse{PendingEmail: resp.GetPendingEmail(), ExpiresAt: timestampToTime(resp.GetExpiresAt())}, nil
+}
+
+func (c *UserClient) VerifyEmailChange(ctx context.Context, id, actorID, token string) (*models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	req := &userv1.VerifyEmailChangeRequest{Id: id, ActorId: actorID, Token: token}
+	resp, err := c.client.VerifyEmailChange(ctx, req)
+	if err != nil {
+		return nil, c.wrapError("verify email change", err)
+	}
+
+	return userFromProto(resp), nil
+}
+
+// TouchLastSeen records that id was just active.
+func (c *UserClient) TouchLastSeen(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	if _, err := c.client.TouchLastSeen(ctx, &userv1.TouchLastSeenRequest{Id: id}); err != nil {
+		return c.wrapError("touch last seen", err)
+	}
+	return nil
+}
+
+// ListAllUsers pages through every user, deactivated ones included. It backs
+// the admin-only listing.
+func (c *UserClient) ListAllUsers(ctx context.Context, input ListUsersInput) (*models.ListUsersResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	resp, err := c.client.ListUsers(ctx, input.toProto(true))
+	if err != nil {
+		return nil, c.wrapError("list all users", err)
+	}
+
+	return listUsersFromProto(resp), nil
+}
+
 func (c *UserClient) SearchUsers(ctx context.Context, query string, limit, offset int) (*models.ListUsersResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
 	defer cancel()
@@ -228,8 +394,8 @@ func (c *UserClient) wrapError(action string, err error) error {
 		return nil
 	}
 
-	if st, ok := status.FromError(err); ok {
-		return status.Errorf(st.Code(), "%s: %s", action, st.Message())
+	if clientErr := newClientError(action, err); clientErr != nil {
+		return clientErr
 	}
 
 	return fmt.Errorf("%s: %w", action, err)
@@ -241,16 +407,22 @@ func userFromProto(u *userv1.User) *models.UserResponse {
 	}
 
 	return &models.UserResponse{
-		ID:        u.GetId(),
-		Email:     u.GetEmail(),
-		Name:      u.GetName(),
-		Picture:   u.GetPicture(),
-		Bio:       u.GetBio(),
-		Location:  u.GetLocation(),
-		Website:   u.GetWebsite(),
-		IsActive:  u.GetIsActive(),
-		CreatedAt: timestampToTime(u.GetCreatedAt()),
-		UpdatedAt: timestampToTime(u.GetUpdatedAt()),
+		ID:              u.GetId(),
+		Email:           u.GetEmail(),
+		Name:            u.GetName(),
+		Username:        u.GetUsername(),
+		Picture:         u.GetPicture(),
+		Bio:             u.GetBio(),
+		Location:        u.GetLocation(),
+		Website:         u.GetWebsite(),
+		Role:            u.GetRole(),
+		EmailVerified:   u.GetEmailVerified(),
+		EmailVerifiedAt: optionalTimestampToTime(u.GetEmailVerifiedAt()),
+		LastSeenAt:      optionalTimestampToTime(u.GetLastSeenAt()),
+		Status:          u.GetStatus(),
+		IsActive:        u.GetIsActive(),
+		CreatedAt:       timestampToTime(u.GetCreatedAt()),
+		UpdatedAt:       timestampToTime(u.GetUpdatedAt()),
 	}
 }
 
@@ -262,6 +434,7 @@ func userProfileFromProto(p *userv1.UserProfile) *models.UserProfileResponse {
 	return &models.UserProfileResponse{
 		ID:       p.GetId(),
 		Name:     p.GetName(),
+		Username: p.GetUsername(),
 		Picture:  p.GetPicture(),
 		Bio:      p.GetBio(),
 		Location: p.GetLocation(),
@@ -280,11 +453,20 @@ func listUsersFromProto(resp *userv1.ListUsersResponse) *models.ListUsersRespons
 	}
 
 	return &models.ListUsersResponse{
-		Users:  users,
-		Limit:  int(resp.GetLimit()),
-		Offset: int(resp.GetOffset()),
-		Total:  int(resp.GetTotal()),
+		Users:   users,
+		Limit:   int(resp.GetLimit()),
+		Offset:  int(resp.GetOffset()),
+		Total:   int(resp.GetTotal()),
+		HasMore: resp.GetHasMore(),
+	}
+}
+
+func optionalTimestampToTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
 	}
+	t := ts.AsTime()
+	return &t
 }
 
 func timestampToTime(ts *timestamppb.Timestamp) time.Time {
@@ -297,15 +479,19 @@ func timestampToTime(ts *timestamppb.Timestamp) time.Time {
 func (c *UserClient) Follow(ctx context.Context, followerID, followeeID string) error {
 	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
 	defer cancel()
-	_, err := c.client.Follow(ctx, &userv1.FollowRequest{FollowerId: followerID, FolloweeId: followeeID})
-	return err
+	if _, err := c.client.Follow(ctx, &userv1.FollowRequest{FollowerId: followerID, FolloweeId: followeeID}); err != nil {
+		return c.wrapError("follow", err)
+	}
+	return nil
 }
 
 func (c *UserClient) Unfollow(ctx context.Context, followerID, followeeID string) error {
 	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
 	defer cancel()
-	_, err := c.client.Unfollow(ctx, &userv1.UnfollowRequest{FollowerId: followerID, FolloweeId: followeeID})
-	return err
+	if _, err := c.client.Unfollow(ctx, &userv1.UnfollowRequest{FollowerId: followerID, FolloweeId: followeeID}); err != nil {
+		return c.wrapError("unfollow", err)
+	}
+	return nil
 }
 
 func (c *UserClient) GetFollowers(ctx context.Context, userID string, limit int, cursor string) (*models.ListFollowResponse, error) {
@@ -328,6 +514,38 @@ func (c *UserClient) GetFollowing(ctx context.Context, userID string, limit int,
 	return listFollowFromProto(resp), nil
 }
 
+func (c *UserClient) Block(ctx context.Context, blockerID, blockedID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+	if _, err := c.client.Block(ctx, &userv1.BlockRequest{BlockerId: blockerID, BlockedId: blockedID}); err != nil {
+		return c.wrapError("block", err)
+	}
+	return nil
+}
+
+func (c *UserClient) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+	if _, err := c.client.Unblock(ctx, &userv1.UnblockRequest{BlockerId: blockerID, BlockedId: blockedID}); err != nil {
+		return c.wrapError("unblock", err)
+	}
+	return nil
+}
+
+func (c *UserClient) ListBlocked(ctx context.Context, userID string, limit int, cursor string) (*models.ListBlockedResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+	resp, err := c.client.ListBlocked(ctx, &userv1.ListBlockedRequest{UserId: userID, Limit: int32(limit), Cursor: cursor})
+	if err != nil {
+		return nil, c.wrapError("list blocked", err)
+	}
+	users := make([]*models.UserProfileResponse, 0, len(resp.GetUsers()))
+	for _, u := range resp.GetUsers() {
+		users = append(users, userProfileFromProto(u))
+	}
+	return &models.ListBlockedResponse{Users: users, NextCursor: resp.GetNextCursor()}, nil
+}
+
 func listFollowFromProto(resp *userv1.ListFollowResponse) *models.ListFollowResponse {
 	if resp == nil {
 		return nil
@@ -336,5 +554,5 @@ func listFollowFromProto(resp *userv1.ListFollowResponse) *models.ListFollowResp
 	for _, u := range resp.GetUsers() {
 		users = append(users, userProfileFromProto(u))
 	}
-	return &models.ListFollowResponse{Users: users, NextCursor: resp.GetNextCursor()}
+	return &models.ListFollowResponse{Users: users, NextCursor: resp.GetNextCursor(), Total: resp.GetTotal()}
 }
@@ -0,0 +1,135 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api-gateway/pkg/logger"
+
+	userv1 "github.com/nikitashilov/microblog_grpc/proto/user/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// stubUserServiceClient answers ListUsers from a canned response; every other
+// RPC panics via the embedded nil interface.
+type stubUserServiceClient struct {
+	userv1.UserServiceClient
+	resp *userv1.ListUsersResponse
+	req  *userv1.ListUsersRequest
+}
+
+func (s *stubUserServiceClient) ListUsers(ctx context.Context, in *userv1.ListUsersRequest, opts ...grpc.CallOption) (*userv1.ListUsersResponse, error) {
+	s.req = in
+	return s.resp, nil
+}
+
+func TestUserClientListUsersReturnsEveryUser(t *testing.T) {
+	stub := &stubUserServiceClient{resp: &userv1.ListUsersResponse{
+		Users: []*userv1.User{
+			{Id: "u1", Email: "a@example.com", Name: "Alice"},
+			{Id: "u2", Email: "b@example.com", Name: "Bob"},
+			{Id: "u3", Email: "c@example.com", Name: "Carol"},
+		},
+		Limit:  3,
+		Offset: 6,
+		Total:  42,
+	}}
+	client := &UserClient{client: stub, logger: logger.New("info")}
+
+	resp, err := client.ListUsers(context.Background(), ListUsersInput{Limit: 3, Offset: 6})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if stub.req.GetLimit() != 3 || stub.req.GetOffset() != 6 {
+		t.Errorf("expected limit=3 offset=6 forwarded, got limit=%d offset=%d", stub.req.GetLimit(), stub.req.GetOffset())
+	}
+	if len(resp.Users) != 3 || resp.Users[2].ID != "u3" {
+		t.Fatalf("expected all three users, got %+v", resp.Users)
+	}
+	if resp.Total != 42 || resp.Limit != 3 || resp.Offset != 6 {
+		t.Errorf("expected total=42 limit=3 offset=6, got total=%d limit=%d offset=%d", resp.Total, resp.Limit, resp.Offset)
+	}
+}
+
+type stubBatchUserServiceClient struct {
+	userv1.UserServiceClient
+	req *userv1.GetUsersBatchRequest
+}
+
+func (s *stubBatchUserServiceClient) GetUsersBatch(ctx context.Context, in *userv1.GetUsersBatchRequest, opts ...grpc.CallOption) (*userv1.GetUsersBatchResponse, error) {
+	s.req = in
+	return &userv1.GetUsersBatchResponse{Users: map[string]*userv1.UserProfile{
+		"u1": {Id: "u1", Name: "Alice"},
+		"u2": {Id: "u2", Name: "Bob"},
+	}}, nil
+}
+
+func TestUserClientGetUsersBatch(t *testing.T) {
+	stub := &stubBatchUserServiceClient{}
+	client := &UserClient{client: stub, logger: logger.New("info")}
+
+	resp, err := client.GetUsersBatch(context.Background(), []string{"u1", "u2", "gone"})
+	if err != nil {
+		t.Fatalf("GetUsersBatch: %v", err)
+	}
+	if len(stub.req.GetIds()) != 3 {
+		t.Errorf("expected ids forwarded as-is, got %v", stub.req.GetIds())
+	}
+	if len(resp.Users) != 2 || resp.Users["u1"].Name != "Alice" || resp.Users["u2"].Name != "Bob" {
+		t.Fatalf("unexpected users: %+v", resp.Users)
+	}
+}
+
+type stubStatusUserServiceClient struct {
+	userv1.UserServiceClient
+	deactivated *userv1.DeactivateUserRequest
+}
+
+func (s *stubStatusUserServiceClient) DeactivateUser(ctx context.Context, in *userv1.DeactivateUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	s.deactivated = in
+	return &emptypb.Empty{}, nil
+}
+
+func (s *stubStatusUserServiceClient) ReactivateUser(ctx context.Context, in *userv1.ReactivateUserRequest, opts ...grpc.CallOption) (*userv1.User, error) {
+	return &userv1.User{Id: in.GetId(), Status: "active", IsActive: true}, nil
+}
+
+func TestUserClientDeactivateAndReactivate(t *testing.T) {
+	stub := &stubStatusUserServiceClient{}
+	client := &UserClient{client: stub, logger: logger.New("info")}
+
+	if err := client.DeactivateUser(context.Background(), "u1", "admin-1", "admin"); err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+	if stub.deactivated.GetId() != "u1" || stub.deactivated.GetActorId() != "admin-1" || stub.deactivated.GetActorRole() != "admin" {
+		t.Errorf("expected id and actor forwarded, got %+v", stub.deactivated)
+	}
+
+	user, err := client.ReactivateUser(context.Background(), "u1", "u1", "user")
+	if err != nil {
+		t.Fatalf("ReactivateUser: %v", err)
+	}
+	if user.ID != "u1" || user.Status != "active" || !user.IsActive {
+		t.Errorf("unexpected reactivated user: %+v", user)
+	}
+}
+
+func TestUserClientListAllUsersForwardsSortAndCreatedRange(t *testing.T) {
+	stub := &stubUserServiceClient{resp: &userv1.ListUsersResponse{}}
+	client := &UserClient{client: stub, logger: logger.New("info")}
+
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	input := ListUsersInput{Limit: 20, Sort: "email", Order: "asc", CreatedAfter: &after}
+	if _, err := client.ListAllUsers(context.Background(), input); err != nil {
+		t.Fatalf("ListAllUsers: %v", err)
+	}
+	req := stub.req
+	if !req.GetIncludeInactive() || req.GetSort() != "email" || req.GetOrder() != "asc" {
+		t.Errorf("expected the admin listing sorted by email ascending, got %+v", req)
+	}
+	if !req.GetCreatedAfter().AsTime().Equal(after) || req.GetCreatedBefore() != nil {
+		t.Errorf("expected only created_after forwarded, got %v / %v", req.GetCreatedAfter(), req.GetCreatedBefore())
+	}
+}
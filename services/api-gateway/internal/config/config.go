@@ -10,17 +10,54 @@ import (
 type Config struct {
 	Port                     string
 	Environment              string
+	MetricsEnabled           bool // exposes /metrics and records Prometheus metrics
 	LogLevel                 string
+	LogFormat                string // "json" or "text"; defaults to json in production
+	Tracing                  TracingConfig
 	Server                   ServerConfig
 	Redis                    RedisConfig
 	Services                 ServicesConfig
 	GRPCTLS                  GRPCTLSConfig
+	GRPCRetry                GRPCRetryConfig
+	HTTPClient               HTTPClientConfig
 	ServiceTransportSecurity string
-	RequestMaxBodyBytes      int64
-	TrustedProxies           []string
-	RateLimit                RateLimitConfig
-	CORS                     CORSConfig
-	Auth                     AuthConfig
+	// InternalServiceToken is sent as X-Internal-Token on every downstream HTTP
+	// request; services only trust X-User-ID alongside it. Required in production.
+	InternalServiceToken    string
+	RequestMaxBodyBytes     int64
+	RequestMaxAuthBodyBytes int64 // tighter cap for /api/v1/auth/*
+	RequestTimeout          RequestTimeoutConfig
+	Compression             CompressionConfig
+	TrustedProxies          []string
+	RateLimit               RateLimitConfig
+	Idempotency             IdempotencyConfig
+	CORS                    CORSConfig
+	Auth                    AuthConfig
+	AccessLog               AccessLogConfig
+	HealthCheck             HealthCheckConfig
+}
+
+// HealthCheckConfig controls the background probing of downstream services
+// behind the health endpoints. A result older than CacheTTLMs, e.g. because
+// probes hang, is reported as unknown rather than served as current.
+type HealthCheckConfig struct {
+	ProbeIntervalMs int
+	CacheTTLMs      int
+}
+
+// AccessLogConfig controls which requests reach the access log. Mutating
+// requests and server errors are always logged regardless of these settings.
+type AccessLogConfig struct {
+	SkipPaths   []string // never logged
+	SamplePaths []string // logged once every SampleRate requests
+	SampleRate  int
+}
+
+// IdempotencyConfig controls replay of POST responses for requests carrying
+// an Idempotency-Key header.
+type IdempotencyConfig struct {
+	Enabled    bool
+	TTLSeconds int // how long a successful response is replayed
 }
 
 // AuthConfig holds auth-related options (e.g. refresh token in HttpOnly cookie).
@@ -29,12 +66,20 @@ type AuthConfig struct {
 	RefreshTokenCookieName     string
 	RefreshTokenCookieSameSite string // Lax, Strict, None
 	CookieDomain               string // optional; empty = current host
+	// AutoProvisionUsers creates a missing user record from the token claims on
+	// a user's first post, covering logins whose user registration failed.
+	AutoProvisionUsers bool
 }
 
 type ServerConfig struct {
 	ReadTimeout  int
 	WriteTimeout int
 	IdleTimeout  int
+	// ShutdownDelay is how long (seconds) /ready reports draining before the
+	// listener closes, giving load balancers time to deregister the instance.
+	ShutdownDelay int
+	// ShutdownTimeout bounds (seconds) the wait for in-flight requests.
+	ShutdownTimeout int
 }
 
 type RedisConfig struct {
@@ -61,14 +106,51 @@ type GRPCTLSConfig struct {
 	RequireClientCert bool
 }
 
+// GRPCRetryConfig bounds retries of idempotent (read-only) gRPC calls to the
+// backend services. MaxAttempts counts the first call; 1 disables retries.
+type GRPCRetryConfig struct {
+	MaxAttempts      int
+	InitialBackoffMs int
+	MaxBackoffMs     int
+}
+
+// HTTPClientConfig tunes the connection pool shared by the gateway's HTTP
+// clients to downstream services.
+type HTTPClientConfig struct {
+	MaxIdleConns           int
+	MaxIdleConnsPerHost    int
+	IdleConnTimeoutSeconds int
+}
+
 type RateLimitConfig struct {
+	// RequestsPerMinute and BurstSize limit anonymous requests per client IP.
 	RequestsPerMinute int
 	BurstSize         int
+	// UserRequestsPerMinute and UserBurstSize limit authenticated requests
+	// per user ID, independent of the IP they come from.
+	UserRequestsPerMinute int
+	UserBurstSize         int
 	// AuthRequestsPerMinute is the stricter per-IP limit applied to the
 	// unauthenticated credential/token endpoints (login, register, refresh,
 	// exchange) to blunt brute-force and credential stuffing.
 	AuthRequestsPerMinute int
-	Enabled               bool
+	// PreAuthRequestsPerMinute and PreAuthBurstSize are a per-IP ceiling
+	// checked before the bearer token is validated, so a flood of bad tokens
+	// is cut off before each one costs an auth-service call. Keep it well
+	// above the user limit: every user behind one NAT shares it.
+	PreAuthRequestsPerMinute int
+	PreAuthBurstSize         int
+	// Routes maps a route prefix to its own limit, which replaces the IP and
+	// user limits on matching routes and is counted separately from them.
+	// The longest matching prefix applies.
+	Routes  map[string]RouteRateLimit
+	Enabled bool
+}
+
+// RouteRateLimit is the limit for one route prefix, per client IP or user.
+type RouteRateLimit struct {
+	RequestsPerMinute int
+	BurstSize         int
 }
 
 type CORSConfig struct {
@@ -79,15 +161,46 @@ type CORSConfig struct {
 	AllowCredentials bool
 }
 
+// RequestTimeoutConfig bounds how long a request may take end to end,
+// downstream calls included. RoutesMs maps a route prefix to its own timeout
+// and wins over DefaultMs; the longest matching prefix applies and 0 disables
+// the timeout for that prefix.
+type RequestTimeoutConfig struct {
+	DefaultMs int
+	RoutesMs  map[string]int
+}
+
+// CompressionConfig controls gzip/deflate encoding of responses. Disabling it
+// makes responses readable on the wire when debugging.
+type CompressionConfig struct {
+	Enabled      bool
+	MinSizeBytes int // smaller bodies are sent uncompressed
+}
+
+// TracingConfig enables OpenTelemetry trace export. An empty Endpoint keeps
+// tracing a no-op apart from propagating incoming trace context.
+type TracingConfig struct {
+	Endpoint    string
+	SampleRatio float64
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
-		Port:        getEnv("PORT", "8080"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
+		Port:           getEnv("PORT", "8080"),
+		Environment:    getEnv("ENVIRONMENT", "development"),
+		MetricsEnabled: getEnvAsBool("METRICS_ENABLED", true),
+		LogLevel:       getEnv("LOG_LEVEL", "info"),
+		LogFormat:      getEnv("LOG_FORMAT", defaultLogFormat(getEnv("ENVIRONMENT", "development"))),
+		Tracing: TracingConfig{
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			SampleRatio: getEnvAsFloat("OTEL_TRACES_SAMPLE_RATIO", 1),
+		},
 		Server: ServerConfig{
-			ReadTimeout:  getEnvAsInt("SERVER_READ_TIMEOUT", 30),
-			WriteTimeout: getEnvAsInt("SERVER_WRITE_TIMEOUT", 30),
-			IdleTimeout:  getEnvAsInt("SERVER_IDLE_TIMEOUT", 60),
+			ReadTimeout:     getEnvAsInt("SERVER_READ_TIMEOUT", 30),
+			WriteTimeout:    getEnvAsInt("SERVER_WRITE_TIMEOUT", 30),
+			IdleTimeout:     getEnvAsInt("SERVER_IDLE_TIMEOUT", 60),
+			ShutdownDelay:   getEnvAsInt("SERVER_SHUTDOWN_DELAY", 5),
+			ShutdownTimeout: getEnvAsInt("SERVER_SHUTDOWN_TIMEOUT", 30),
 		},
 		Redis: RedisConfig{
 			URL:      getEnv("REDIS_URL", "redis:6379"),
@@ -110,14 +223,41 @@ func Load() (*Config, error) {
 			KeyFile:           getEnv("GRPC_TLS_KEY_FILE", ""),
 			RequireClientCert: getEnvAsBool("GRPC_TLS_REQUIRE_CLIENT_CERT", false),
 		},
+		GRPCRetry: GRPCRetryConfig{
+			MaxAttempts:      getEnvAsInt("GRPC_RETRY_MAX_ATTEMPTS", 3),
+			InitialBackoffMs: getEnvAsInt("GRPC_RETRY_INITIAL_BACKOFF_MS", 100),
+			MaxBackoffMs:     getEnvAsInt("GRPC_RETRY_MAX_BACKOFF_MS", 1000),
+		},
+		HTTPClient: HTTPClientConfig{
+			MaxIdleConns:           getEnvAsInt("HTTP_CLIENT_MAX_IDLE_CONNS", 100),
+			MaxIdleConnsPerHost:    getEnvAsInt("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", 20),
+			IdleConnTimeoutSeconds: getEnvAsInt("HTTP_CLIENT_IDLE_CONN_TIMEOUT", 90),
+		},
 		ServiceTransportSecurity: resolveTransportSecurityMode(getEnv("SERVICE_TRANSPORT_SECURITY", ""), getEnv("ENVIRONMENT", "development"), getEnvAsBool("GRPC_TLS_ENABLED", false)),
+		InternalServiceToken:     getEnv("INTERNAL_SERVICE_TOKEN", ""),
 		RequestMaxBodyBytes:      int64(getEnvAsInt("REQUEST_MAX_BODY_BYTES", 1<<20)),
-		TrustedProxies:           parseCSV(getEnv("TRUSTED_PROXIES", "")),
+		RequestMaxAuthBodyBytes:  int64(getEnvAsInt("REQUEST_MAX_AUTH_BODY_BYTES", 64<<10)),
+		RequestTimeout: RequestTimeoutConfig{
+			DefaultMs: getEnvAsInt("REQUEST_TIMEOUT_MS", 10000),
+		},
+		Compression: CompressionConfig{
+			Enabled:      getEnvAsBool("COMPRESSION_ENABLED", true),
+			MinSizeBytes: getEnvAsInt("COMPRESSION_MIN_SIZE_BYTES", 1024),
+		},
+		TrustedProxies: parseCSV(getEnv("TRUSTED_PROXIES", "")),
 		RateLimit: RateLimitConfig{
-			RequestsPerMinute:     getEnvAsInt("RATE_LIMIT_RPM", 100),
-			BurstSize:             getEnvAsInt("RATE_LIMIT_BURST", 20),
-			AuthRequestsPerMinute: getEnvAsInt("RATE_LIMIT_AUTH_RPM", 10),
-			Enabled:               getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			RequestsPerMinute:        getEnvAsInt("RATE_LIMIT_RPM", 100),
+			BurstSize:                getEnvAsInt("RATE_LIMIT_BURST", 20),
+			UserRequestsPerMinute:    getEnvAsInt("RATE_LIMIT_USER_RPM", 100),
+			UserBurstSize:            getEnvAsInt("RATE_LIMIT_USER_BURST", 20),
+			AuthRequestsPerMinute:    getEnvAsInt("RATE_LIMIT_AUTH_RPM", 10),
+			PreAuthRequestsPerMinute: getEnvAsInt("RATE_LIMIT_PREAUTH_RPM", 600),
+			PreAuthBurstSize:         getEnvAsInt("RATE_LIMIT_PREAUTH_BURST", 100),
+			Enabled:                  getEnvAsBool("RATE_LIMIT_ENABLED", true),
+		},
+		Idempotency: IdempotencyConfig{
+			Enabled:    getEnvAsBool("IDEMPOTENCY_ENABLED", true),
+			TTLSeconds: getEnvAsInt("IDEMPOTENCY_TTL_SECONDS", 600),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: defaultCSV(
@@ -130,7 +270,7 @@ func Load() (*Config, error) {
 			),
 			AllowedHeaders: defaultCSV(
 				parseCSV(getEnv("CORS_ALLOWED_HEADERS", "")),
-				[]string{"Content-Type", "Authorization"},
+				[]string{"Content-Type", "Authorization", "X-Request-ID", "Idempotency-Key"},
 			),
 			ExposeHeaders: defaultCSV(
 				parseCSV(getEnv("CORS_EXPOSE_HEADERS", "")),
@@ -142,6 +282,8 @@ func Load() (*Config, error) {
 					"X-RateLimit-Limit",
 					"X-RateLimit-Remaining",
 					"X-RateLimit-Reset",
+					"X-Request-ID",
+					"Idempotent-Replayed",
 				},
 			),
 			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", true),
@@ -151,8 +293,30 @@ func Load() (*Config, error) {
 			RefreshTokenCookieName:     getEnv("AUTH_REFRESH_TOKEN_COOKIE_NAME", "refresh_token"),
 			RefreshTokenCookieSameSite: getEnv("AUTH_REFRESH_TOKEN_COOKIE_SAMESITE", "Lax"),
 			CookieDomain:               getEnv("AUTH_COOKIE_DOMAIN", ""),
+			AutoProvisionUsers:         getEnvAsBool("AUTH_AUTO_PROVISION_USERS", true),
+		},
+		AccessLog: AccessLogConfig{
+			SkipPaths:   parseCSV(getEnv("ACCESS_LOG_SKIP_PATHS", "/health,/metrics")),
+			SamplePaths: parseCSV(getEnv("ACCESS_LOG_SAMPLE_PATHS", "")),
+			SampleRate:  getEnvAsInt("ACCESS_LOG_SAMPLE_RATE", 10),
 		},
+		HealthCheck: HealthCheckConfig{
+			ProbeIntervalMs: getEnvAsInt("HEALTH_PROBE_INTERVAL_MS", 5000),
+			CacheTTLMs:      getEnvAsInt("HEALTH_CACHE_TTL_MS", 15000),
+		},
+	}
+
+	routeTimeouts, err := parseRouteTimeouts(getEnv("ROUTE_TIMEOUTS_MS", "/api/v1/auth/validate=2000,/api/v1/search=15000"))
+	if err != nil {
+		return nil, err
 	}
+	cfg.RequestTimeout.RoutesMs = routeTimeouts
+
+	routeRateLimits, err := parseRouteRateLimits(getEnv("RATE_LIMIT_ROUTES", "/api/v1/search=300:60"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.RateLimit.Routes = routeRateLimits
 
 	if err := cfg.validate(); err != nil {
 		return nil, err
@@ -162,6 +326,15 @@ func Load() (*Config, error) {
 }
 
 func (c *Config) validate() error {
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		return fmt.Errorf("LOG_FORMAT must be json or text")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("OTEL_TRACES_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if c.Server.ShutdownDelay < 0 || c.Server.ShutdownTimeout < 1 {
+		return fmt.Errorf("SERVER_SHUTDOWN_DELAY must not be negative and SERVER_SHUTDOWN_TIMEOUT must be at least 1")
+	}
 	if c.Services.AuthURL == "" {
 		return fmt.Errorf("AUTH_SERVICE_URL is required")
 	}
@@ -192,12 +365,45 @@ func (c *Config) validate() error {
 	if err := validateTransportSecurityMode(c.Environment, c.ServiceTransportSecurity, c.GRPCTLS.Enabled); err != nil {
 		return err
 	}
+	if c.Environment == "production" && c.InternalServiceToken == "" {
+		return fmt.Errorf("INTERNAL_SERVICE_TOKEN is required in production")
+	}
+	if c.InternalServiceToken != "" && len(c.InternalServiceToken) < 32 {
+		return fmt.Errorf("INTERNAL_SERVICE_TOKEN must be at least 32 characters")
+	}
 	if c.CORS.AllowCredentials && hasCSVValue(c.CORS.AllowedOrigins, "*") {
 		return fmt.Errorf("CORS_ALLOWED_ORIGINS cannot contain * when CORS_ALLOW_CREDENTIALS=true")
 	}
+	if c.GRPCRetry.MaxAttempts < 1 {
+		return fmt.Errorf("GRPC_RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.GRPCRetry.InitialBackoffMs < 0 || c.GRPCRetry.MaxBackoffMs < c.GRPCRetry.InitialBackoffMs {
+		return fmt.Errorf("GRPC_RETRY_INITIAL_BACKOFF_MS must be non-negative and not exceed GRPC_RETRY_MAX_BACKOFF_MS")
+	}
+	if c.HTTPClient.MaxIdleConns < 0 || c.HTTPClient.MaxIdleConnsPerHost < 1 || c.HTTPClient.IdleConnTimeoutSeconds < 0 {
+		return fmt.Errorf("HTTP_CLIENT_MAX_IDLE_CONNS and HTTP_CLIENT_IDLE_CONN_TIMEOUT must be non-negative and HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST at least 1")
+	}
 	if c.RequestMaxBodyBytes <= 0 {
 		return fmt.Errorf("REQUEST_MAX_BODY_BYTES must be greater than 0")
 	}
+	if c.RequestMaxAuthBodyBytes <= 0 || c.RequestMaxAuthBodyBytes > c.RequestMaxBodyBytes {
+		return fmt.Errorf("REQUEST_MAX_AUTH_BODY_BYTES must be greater than 0 and at most REQUEST_MAX_BODY_BYTES")
+	}
+	if c.RequestTimeout.DefaultMs < 1 {
+		return fmt.Errorf("REQUEST_TIMEOUT_MS must be at least 1")
+	}
+	if c.Compression.MinSizeBytes < 0 {
+		return fmt.Errorf("COMPRESSION_MIN_SIZE_BYTES must not be negative")
+	}
+	if c.AccessLog.SampleRate < 1 {
+		return fmt.Errorf("ACCESS_LOG_SAMPLE_RATE must be at least 1")
+	}
+	if c.HealthCheck.ProbeIntervalMs < 1 || c.HealthCheck.CacheTTLMs < c.HealthCheck.ProbeIntervalMs {
+		return fmt.Errorf("HEALTH_PROBE_INTERVAL_MS must be at least 1 and not exceed HEALTH_CACHE_TTL_MS")
+	}
+	if c.Idempotency.Enabled && c.Idempotency.TTLSeconds < 1 {
+		return fmt.Errorf("IDEMPOTENCY_TTL_SECONDS must be at least 1")
+	}
 	if c.RateLimit.Enabled {
 		if c.RateLimit.RequestsPerMinute < 1 {
 			return fmt.Errorf("RATE_LIMIT_RPM must be at least 1")
@@ -205,6 +411,12 @@ func (c *Config) validate() error {
 		if c.RateLimit.BurstSize < 1 {
 			return fmt.Errorf("RATE_LIMIT_BURST must be at least 1")
 		}
+		if c.RateLimit.UserRequestsPerMinute < 1 {
+			return fmt.Errorf("RATE_LIMIT_USER_RPM must be at least 1")
+		}
+		if c.RateLimit.UserBurstSize < 1 {
+			return fmt.Errorf("RATE_LIMIT_USER_BURST must be at least 1")
+		}
 		if c.RateLimit.AuthRequestsPerMinute < 1 {
 			return fmt.Errorf("RATE_LIMIT_AUTH_RPM must be at least 1")
 		}
@@ -212,6 +424,13 @@ func (c *Config) validate() error {
 	return nil
 }
 
+func defaultLogFormat(environment string) string {
+	if environment == "production" {
+		return "json"
+	}
+	return "text"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -228,6 +447,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -253,6 +481,45 @@ func parseCSV(value string) []string {
 	return result
 }
 
+// parseRouteTimeouts parses "prefix=ms,prefix=ms" into a map of route prefix
+// to timeout in milliseconds.
+func parseRouteTimeouts(value string) (map[string]int, error) {
+	routes := make(map[string]int)
+	for _, entry := range parseCSV(value) {
+		prefix, ms, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		timeout, err := strconv.Atoi(strings.TrimSpace(ms))
+		if !ok || !strings.HasPrefix(prefix, "/") || err != nil || timeout < 0 {
+			return nil, fmt.Errorf("ROUTE_TIMEOUTS_MS entry %q must look like /route/prefix=milliseconds", entry)
+		}
+		routes[prefix] = timeout
+	}
+	return routes, nil
+}
+
+// parseRouteRateLimits parses "prefix=rpm[:burst],..." into a map of route
+// prefix to limit. The burst defaults to the per-minute limit.
+func parseRouteRateLimits(value string) (map[string]RouteRateLimit, error) {
+	routes := make(map[string]RouteRateLimit)
+	for _, entry := range parseCSV(value) {
+		prefix, spec, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		rpm, burst, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+		limit := RouteRateLimit{}
+		var rpmErr, burstErr error
+		limit.RequestsPerMinute, rpmErr = strconv.Atoi(rpm)
+		limit.BurstSize = limit.RequestsPerMinute
+		if hasBurst {
+			limit.BurstSize, burstErr = strconv.Atoi(burst)
+		}
+		if !ok || !strings.HasPrefix(prefix, "/") || rpmErr != nil || burstErr != nil || limit.RequestsPerMinute < 1 || limit.BurstSize < 1 {
+			return nil, fmt.Errorf("RATE_LIMIT_ROUTES entry %q must look like /route/prefix=requests_per_minute[:burst]", entry)
+		}
+		routes[prefix] = limit
+	}
+	return routes, nil
+}
+
 func defaultCSV(value []string, fallback []string) []string {
 	if len(value) == 0 {
 		return fallback
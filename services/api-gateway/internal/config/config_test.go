@@ -5,6 +5,8 @@ import (
 	"testing"
 )
 
+const testInternalToken = "0123456789abcdef0123456789abcdef"
+
 func TestLoadProductionRequiresTransportSecurityMode(t *testing.T) {
 	t.Setenv("ENVIRONMENT", "production")
 	t.Setenv("REDIS_PASSWORD", "redis-password")
@@ -21,6 +23,7 @@ func TestLoadProductionAllowsMeshTransportMode(t *testing.T) {
 	t.Setenv("ENVIRONMENT", "production")
 	t.Setenv("SERVICE_TRANSPORT_SECURITY", "mesh")
 	t.Setenv("REDIS_PASSWORD", "redis-password")
+	t.Setenv("INTERNAL_SERVICE_TOKEN", testInternalToken)
 	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
 
 	cfg, err := Load()
@@ -36,6 +39,7 @@ func TestLoadProductionRejectsWildcardCredentialsCORS(t *testing.T) {
 	t.Setenv("ENVIRONMENT", "production")
 	t.Setenv("SERVICE_TRANSPORT_SECURITY", "mesh")
 	t.Setenv("REDIS_PASSWORD", "redis-password")
+	t.Setenv("INTERNAL_SERVICE_TOKEN", testInternalToken)
 	t.Setenv("CORS_ALLOWED_ORIGINS", "*")
 	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
 
@@ -45,6 +49,18 @@ func TestLoadProductionRejectsWildcardCredentialsCORS(t *testing.T) {
 	}
 }
 
+func TestLoadProductionRequiresInternalServiceToken(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "production")
+	t.Setenv("SERVICE_TRANSPORT_SECURITY", "mesh")
+	t.Setenv("REDIS_PASSWORD", "redis-password")
+	t.Setenv("INTERNAL_SERVICE_TOKEN", "")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "INTERNAL_SERVICE_TOKEN") {
+		t.Fatalf("expected INTERNAL_SERVICE_TOKEN error, got %v", err)
+	}
+}
+
 func TestLoadRejectsInvalidRequestBodyLimit(t *testing.T) {
 	t.Setenv("REQUEST_MAX_BODY_BYTES", "0")
 
@@ -53,3 +69,52 @@ func TestLoadRejectsInvalidRequestBodyLimit(t *testing.T) {
 		t.Fatalf("expected REQUEST_MAX_BODY_BYTES error, got %v", err)
 	}
 }
+
+func TestLoadRejectsAuthBodyLimitAboveGlobalLimit(t *testing.T) {
+	t.Setenv("REQUEST_MAX_BODY_BYTES", "1024")
+	t.Setenv("REQUEST_MAX_AUTH_BODY_BYTES", "2048")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "REQUEST_MAX_AUTH_BODY_BYTES") {
+		t.Fatalf("expected REQUEST_MAX_AUTH_BODY_BYTES error, got %v", err)
+	}
+}
+
+func TestLoadParsesRouteTimeouts(t *testing.T) {
+	t.Setenv("ROUTE_TIMEOUTS_MS", "/api/v1/auth/validate=1500, /api/v1/search=20000")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.RequestTimeout.RoutesMs["/api/v1/auth/validate"] != 1500 || cfg.RequestTimeout.RoutesMs["/api/v1/search"] != 20000 {
+		t.Fatalf("unexpected route timeouts %v", cfg.RequestTimeout.RoutesMs)
+	}
+
+	t.Setenv("ROUTE_TIMEOUTS_MS", "/api/v1/search")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "ROUTE_TIMEOUTS_MS") {
+		t.Fatalf("expected ROUTE_TIMEOUTS_MS error, got %v", err)
+	}
+}
+
+func TestLoadParsesRouteRateLimits(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ROUTES", "/api/v1/search=300:60, /api/v1/posts=30")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.RateLimit.Routes["/api/v1/search"]; got != (RouteRateLimit{RequestsPerMinute: 300, BurstSize: 60}) {
+		t.Fatalf("unexpected search limit %+v", got)
+	}
+	if got := cfg.RateLimit.Routes["/api/v1/posts"]; got != (RouteRateLimit{RequestsPerMinute: 30, BurstSize: 30}) {
+		t.Fatalf("expected the burst to default to the limit, got %+v", got)
+	}
+
+	for _, invalid := range []string{"/api/v1/search", "api/v1/search=10", "/api/v1/search=0", "/api/v1/search=10:x"} {
+		t.Setenv("RATE_LIMIT_ROUTES", invalid)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "RATE_LIMIT_ROUTES") {
+			t.Fatalf("%q: expected RATE_LIMIT_ROUTES error, got %v", invalid, err)
+		}
+	}
+}
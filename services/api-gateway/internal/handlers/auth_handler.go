@@ -1,14 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	"api-gateway/internal/clients"
 	"api-gateway/internal/config"
@@ -49,7 +49,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	resp, err := h.authClient.Register(c.Request.Context(), req.Email, req.Password, req.Name)
 	if err != nil {
-		if st, ok := status.FromError(err); ok && st.Code() == codes.AlreadyExists {
+		var clientErr *clients.ClientError
+		if errors.As(err, &clientErr) && clientErr.Status == http.StatusConflict {
 			utils.ErrorResponse(c, http.StatusConflict, "USER_ALREADY_EXISTS", "User with this email already exists")
 			return
 		}
@@ -76,7 +77,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	resp, err := h.authClient.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		if st, ok := status.FromError(err); ok && st.Code() == codes.Unauthenticated {
+		var clientErr *clients.ClientError
+		if errors.As(err, &clientErr) && clientErr.Status == http.StatusUnauthorized {
 			utils.ErrorResponse(c, http.StatusUnauthorized, "INVALID_CREDENTIALS", "Invalid email or password")
 			return
 		}
@@ -89,7 +91,27 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Login successful", buildAuthResponse(resp.GetUser(), resp.GetTokens()))
 }
 
+type oauthURLFunc func(ctx context.Context, req *authv1.GetGoogleAuthURLRequest) (*authv1.GetGoogleAuthURLResponse, error)
+
+type oauthCallbackFunc func(ctx context.Context, state, code string) (*authv1.GoogleCallbackResponse, error)
+
 func (h *AuthHandler) GetGoogleAuthURL(c *gin.Context) {
+	h.getOAuthAuthURL(c, "Google", h.authClient.GetGoogleAuthURL)
+}
+
+func (h *AuthHandler) GetGitHubAuthURL(c *gin.Context) {
+	h.getOAuthAuthURL(c, "GitHub", h.authClient.GetGitHubAuthURL)
+}
+
+func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+	h.oauthCallback(c, "Google", h.authClient.HandleGoogleCallback)
+}
+
+func (h *AuthHandler) GitHubCallback(c *gin.Context) {
+	h.oauthCallback(c, "GitHub", h.authClient.HandleGitHubCallback)
+}
+
+func (h *AuthHandler) getOAuthAuthURL(c *gin.Context, provider string, getAuthURL oauthURLFunc) {
 	platformRaw := strings.ToLower(strings.TrimSpace(c.DefaultQuery("platform", "web")))
 	platform := authv1.OAuthPlatform_OAUTH_PLATFORM_WEB
 	switch platformRaw {
@@ -110,36 +132,40 @@ func (h *AuthHandler) GetGoogleAuthURL(c *gin.Context) {
 		ClientState:         c.Query("client_state"),
 	}
 
-	resp, err := h.authClient.GetGoogleAuthURL(c.Request.Context(), req)
+	failedMessage := fmt.Sprintf("Failed to get %s auth URL", provider)
+	resp, err := getAuthURL(c.Request.Context(), req)
 	if err != nil {
-		if st, ok := status.FromError(err); ok {
-			switch st.Code() {
-			case codes.InvalidArgument:
-				utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", st.Message())
-			case codes.Unauthenticated, codes.PermissionDenied:
-				utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", st.Message())
+		var clientErr *clients.ClientError
+		if errors.As(err, &clientErr) {
+			switch clientErr.Status {
+			case http.StatusBadRequest:
+				utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", clientErr.Message)
+			case http.StatusUnauthorized, http.StatusForbidden:
+				utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", clientErr.Message)
+			case http.StatusNotFound:
+				utils.ErrorResponse(c, http.StatusNotFound, "PROVIDER_NOT_ENABLED", clientErr.Message)
 			default:
-				utils.ErrorResponse(c, http.StatusInternalServerError, "AUTH_URL_FAILED", "Failed to get Google auth URL")
+				utils.ErrorResponse(c, http.StatusInternalServerError, "AUTH_URL_FAILED", failedMessage)
 			}
 			return
 		}
 
-		h.logger.Error("Get Google auth URL failed: " + err.Error())
-		utils.ErrorResponse(c, http.StatusInternalServerError, "AUTH_URL_FAILED", "Failed to get Google auth URL")
+		h.logger.Error(fmt.Sprintf("Get %s auth URL failed: %s", provider, err.Error()))
+		utils.ErrorResponse(c, http.StatusInternalServerError, "AUTH_URL_FAILED", failedMessage)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Google auth URL generated", resp)
+	utils.SuccessResponse(c, http.StatusOK, provider+" auth URL generated", resp)
 }
 
-func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+func (h *AuthHandler) oauthCallback(c *gin.Context, provider string, handleCallback oauthCallbackFunc) {
 	// Do not log the raw query string: it carries the OAuth `state` and one-time
 	// `code`, which are sensitive and must not land in logs.
-	h.logger.Info("Received Google OAuth callback")
+	h.logger.Info(fmt.Sprintf("Received %s OAuth callback", provider))
 
 	if errParam := c.Query("error"); errParam != "" {
-		h.logger.Warn("Google OAuth error: " + errParam)
-		utils.ErrorResponse(c, http.StatusBadRequest, "GOOGLE_OAUTH_ERROR", errParam)
+		h.logger.Warn(fmt.Sprintf("%s OAuth error: %s", provider, errParam))
+		utils.ErrorResponse(c, http.StatusBadRequest, strings.ToUpper(provider)+"_OAUTH_ERROR", errParam)
 		return
 	}
 
@@ -150,21 +176,41 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authClient.HandleGoogleCallback(c.Request.Context(), stateParam, codeParam)
+	failedMessage := provider + " callback failed"
+	resp, err := handleCallback(c.Request.Context(), stateParam, codeParam)
 	if err != nil {
-		h.logger.Error("Google callback failed: " + err.Error())
-		if st, ok := status.FromError(err); ok && st.Code() == codes.Unauthenticated {
-			utils.ErrorResponse(c, http.StatusUnauthorized, "INVALID_CALLBACK", st.Message())
-			return
+		h.logger.Error(fmt.Sprintf("%s callback failed: %s", provider, err.Error()))
+		var clientErr *clients.ClientError
+		if errors.As(err, &clientErr) {
+			switch clientErr.Status {
+			case http.StatusUnauthorized:
+				utils.ErrorResponse(c, http.StatusUnauthorized, "INVALID_CALLBACK", clientErr.Message)
+				return
+			case http.StatusForbidden:
+				// auth-service denies a login for an unverified provider email or
+				// a domain outside the allowlist.
+				code := "EMAIL_DOMAIN_NOT_ALLOWED"
+				if clientErr.Code == "EMAIL_NOT_VERIFIED" {
+					code = "EMAIL_NOT_VERIFIED"
+				}
+				utils.ErrorResponse(c, http.StatusForbidden, code, clientErr.Message)
+				return
+			case http.StatusNotFound:
+				utils.ErrorResponse(c, http.StatusNotFound, "PROVIDER_NOT_ENABLED", clientErr.Message)
+				return
+			case http.StatusGatewayTimeout:
+				utils.ErrorResponse(c, http.StatusGatewayTimeout, "OAUTH_TIMEOUT", clientErr.Message)
+				return
+			}
 		}
-		utils.ErrorResponse(c, http.StatusInternalServerError, "CALLBACK_FAILED", "Google callback failed")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "CALLBACK_FAILED", failedMessage)
 		return
 	}
 
 	redirectURL, buildErr := buildClientRedirectURL(resp.GetClientRedirectUri(), resp.GetAuthCode(), resp.GetClientState())
 	if buildErr != nil {
 		h.logger.Error("Failed to build callback redirect URL: " + buildErr.Error())
-		utils.ErrorResponse(c, http.StatusInternalServerError, "CALLBACK_FAILED", "Google callback failed")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "CALLBACK_FAILED", failedMessage)
 		return
 	}
 
@@ -183,15 +229,18 @@ func (h *AuthHandler) ExchangeAuthCode(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authClient.ExchangeAuthCodeWithVerifier(c.Request.Context(), req.AuthCode, req.CodeVerifier)
+	resp, err := h.authClient.ExchangeAuthCodeWithVerifier(c.Request.Context(), req.AuthCode, req.CodeVerifier, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
 		h.logger.Error("Auth code exchange failed: " + err.Error())
-		if st, ok := status.FromError(err); ok {
-			switch st.Code() {
-			case codes.Unauthenticated, codes.PermissionDenied:
-				utils.ErrorResponse(c, http.StatusUnauthorized, "EXCHANGE_FAILED", st.Message())
-			case codes.InvalidArgument:
-				utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", st.Message())
+		var clientErr *clients.ClientError
+		if errors.As(err, &clientErr) {
+			switch clientErr.Status {
+			case http.StatusUnauthorized, http.StatusForbidden:
+				utils.ErrorResponse(c, http.StatusUnauthorized, "EXCHANGE_FAILED", clientErr.Message)
+			case http.StatusBadRequest:
+				utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", clientErr.Message)
+			case http.StatusTooManyRequests:
+				utils.ErrorResponse(c, http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS", clientErr.Message)
 			default:
 				utils.ErrorResponse(c, http.StatusInternalServerError, "EXCHANGE_FAILED", "Auth code exchange failed")
 			}
@@ -212,9 +261,14 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authClient.RefreshToken(c.Request.Context(), refreshToken)
+	resp, err := h.authClient.RefreshToken(c.Request.Context(), refreshToken, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
 		h.logger.Error("Token refresh failed: " + err.Error())
+		var clientErr *clients.ClientError
+		if errors.As(err, &clientErr) && clientErr.Status == http.StatusTooManyRequests {
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS", "Too many failed attempts; try again later")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusUnauthorized, "REFRESH_FAILED", "Token refresh failed")
 		return
 	}
@@ -240,6 +294,104 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Logged out successfully", nil)
 }
 
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	terminated, err := h.authClient.LogoutAll(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Logout from all devices failed: " + err.Error())
+		utils.ErrorResponse(c, http.StatusInternalServerError, "LOGOUT_FAILED", "Logout failed")
+		return
+	}
+
+	h.clearRefreshTokenCookie(c)
+	utils.SuccessResponse(c, http.StatusOK, "Logged out from all devices successfully", &models.LogoutAllResponse{
+		SessionsTerminated: terminated,
+	})
+}
+
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	sessions, err := h.authClient.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("List sessions failed: " + err.Error())
+		utils.ErrorResponse(c, http.StatusInternalServerError, "SESSIONS_FAILED", "Failed to retrieve sessions")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sessions retrieved successfully", &models.ListSessionsResponse{Sessions: sessions})
+}
+
+// maxIntrospectTokens mirrors auth-service's batch cap so oversized requests
+// are rejected without a round trip.
+const maxIntrospectTokens = 100
+
+func (h *AuthHandler) IntrospectTokens(c *gin.Context) {
+	var req struct {
+		Tokens []string `json:"tokens" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid introspect request: " + err.Error())
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format")
+		return
+	}
+	if len(req.Tokens) == 0 || len(req.Tokens) > maxIntrospectTokens {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("tokens must contain between 1 and %d entries", maxIntrospectTokens))
+		return
+	}
+
+	results, err := h.authClient.IntrospectTokens(c.Request.Context(), req.Tokens)
+	if err != nil {
+		h.logger.Error("Token introspection failed: " + err.Error())
+		var clientErr *clients.ClientError
+		if errors.As(err, &clientErr) && clientErr.Status == http.StatusBadRequest {
+			utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid introspection request")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "INTROSPECTION_FAILED", "Failed to introspect tokens")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tokens introspected successfully", &models.IntrospectTokensResponse{Results: results})
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	sessionID := c.Param("id")
+	if sessionID == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Session ID is required")
+		return
+	}
+
+	if err := h.authClient.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		var clientErr *clients.ClientError
+		if errors.As(err, &clientErr) && clientErr.Status == http.StatusNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "SESSION_NOT_FOUND", "Session not found")
+			return
+		}
+		h.logger.Error("Revoke session failed: " + err.Error())
+		utils.ErrorResponse(c, http.StatusInternalServerError, "REVOKE_FAILED", "Failed to revoke session")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
 func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	token, exists := c.Get("token")
 	if !exists {
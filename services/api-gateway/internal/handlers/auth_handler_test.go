@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api-gateway/internal/clients"
+	"api-gateway/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	authv1 "github.com/nikitashilov/microblog_grpc/proto/auth/v1"
+)
+
+func TestOAuthCallback_MapsClientErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &AuthHandler{logger: logger.New("error")}
+
+	cases := []struct {
+		name       string
+		err        *clients.ClientError
+		wantStatus int
+		wantCode   string
+	}{
+		{"invalid state", &clients.ClientError{Status: http.StatusUnauthorized, Code: "INVALID_OAUTH_STATE", Message: "Invalid or expired OAuth state"}, http.StatusUnauthorized, "INVALID_CALLBACK"},
+		{"unverified email", &clients.ClientError{Status: http.StatusForbidden, Code: "EMAIL_NOT_VERIFIED", Message: "Email address is not verified with the provider"}, http.StatusForbidden, "EMAIL_NOT_VERIFIED"},
+		{"domain not allowed", &clients.ClientError{Status: http.StatusForbidden, Code: "EMAIL_DOMAIN_NOT_ALLOWED", Message: "Email domain is not allowed to sign in"}, http.StatusForbidden, "EMAIL_DOMAIN_NOT_ALLOWED"},
+		{"timeout", &clients.ClientError{Status: http.StatusGatewayTimeout, Code: "OAUTH_TIMEOUT", Message: "Sign-in provider did not respond in time"}, http.StatusGatewayTimeout, "OAUTH_TIMEOUT"},
+		{"internal", &clients.ClientError{Status: http.StatusInternalServerError, Message: "internal server error"}, http.StatusInternalServerError, "CALLBACK_FAILED"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/callback", func(c *gin.Context) {
+				h.oauthCallback(c, "Google", func(ctx context.Context, state, code string) (*authv1.GoogleCallbackResponse, error) {
+					return nil, tc.err
+				})
+			})
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?state=s&code=c", nil))
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d body %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), `"code":"`+tc.wantCode+`"`) {
+				t.Fatalf("expected code %s, got %s", tc.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
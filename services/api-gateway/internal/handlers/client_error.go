@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"api-gateway/internal/clients"
+	"api-gateway/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// asClientError returns the downstream failure behind err. Errors that did not
+// come from a downstream service are reported as a 500.
+func asClientError(err error) *clients.ClientError {
+	var clientErr *clients.ClientError
+	if errors.As(err, &clientErr) {
+		return clientErr
+	}
+	return &clients.ClientError{Status: http.StatusInternalServerError}
+}
+
+// respondClientError answers with the status and error code a downstream
+// service reported. The service's message is passed on only alongside its own
+// code; otherwise fallbackCode and fallbackMessage describe the failure.
+func respondClientError(c *gin.Context, clientErr *clients.ClientError, fallbackCode, fallbackMessage string) {
+	if clientErr.Code == "" {
+		utils.ErrorResponse(c, clientErr.Status, fallbackCode, fallbackMessage)
+		return
+	}
+	utils.ErrorResponse(c, clientErr.Status, clientErr.Code, clientErr.Message)
+}
@@ -2,78 +2,154 @@ package handlers
 
 import (
 	"context"
-	"fmt"
 	"net/http"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"api-gateway/internal/clients"
+	"api-gateway/internal/config"
 	"api-gateway/pkg/logger"
 	"api-gateway/pkg/utils"
 )
 
+// HealthHandler serves the gateway's health endpoints. Downstream services are
+// probed by Run in the background and the endpoints report the cached
+// results, so frequent liveness probes put no load on the services.
 type HealthHandler struct {
-	authClient      *clients.AuthClient
-	userClient      *clients.UserClient
-	postClient      *clients.PostClient
-	notificationURL string
-	healthClient    *http.Client
-	logger          *logger.Logger
+	authClient         *clients.AuthClient
+	userClient         *clients.UserClient
+	postClient         *clients.PostClient
+	notificationClient *clients.NotificationClient
+	logger             *logger.Logger
+	draining           atomic.Bool
+
+	probes        []healthProbe
+	probeInterval time.Duration
+	cacheTTL      time.Duration
+	mu            sync.RWMutex
+	cache         map[string]*cachedHealth
 }
 
-func NewHealthHandler(authClient *clients.AuthClient, userClient *clients.UserClient, postClient *clients.PostClient, notificationURL string, logger *logger.Logger) *HealthHandler {
-	return &HealthHandler{
-		authClient:      authClient,
-		userClient:      userClient,
-		postClient:      postClient,
-		notificationURL: strings.TrimSuffix(strings.TrimSpace(notificationURL), "/"),
-		healthClient: &http.Client{
-			Timeout: 3 * time.Second,
-		},
-		logger: logger,
-	}
+// cachedHealth is the latest probe result for one downstream service.
+type cachedHealth struct {
+	result      DependencyStatus
+	checkedAt   time.Time
+	lastSuccess time.Time
 }
 
-func (h *HealthHandler) HealthCheck(c *gin.Context) {
-	services := map[string]string{
-		"auth-service":         "healthy",
-		"user-service":         "healthy",
-		"post-service":         "healthy",
-		"notification-service": "healthy",
+func NewHealthHandler(authClient *clients.AuthClient, userClient *clients.UserClient, postClient *clients.PostClient, notificationClient *clients.NotificationClient, cfg config.HealthCheckConfig, logger *logger.Logger) *HealthHandler {
+	h := &HealthHandler{
+		authClient:         authClient,
+		userClient:         userClient,
+		postClient:         postClient,
+		notificationClient: notificationClient,
+		logger:             logger,
+		probeInterval:      time.Duration(cfg.ProbeIntervalMs) * time.Millisecond,
+		cacheTTL:           time.Duration(cfg.CacheTTLMs) * time.Millisecond,
+		cache:              make(map[string]*cachedHealth),
 	}
+	h.probes = []healthProbe{
+		{name: "auth-service", check: authClient.HealthCheck},
+		{name: "user-service", check: userClient.HealthCheck},
+		{name: "post-service", check: postClient.HealthCheck},
+		{name: "notification-service", check: notificationClient.HealthCheck},
+	}
+	return h
+}
 
-	// Check auth service
-	if err := h.authClient.HealthCheck(c.Request.Context()); err != nil {
-		services["auth-service"] = "unhealthy"
-		h.logger.Warn("Auth service health check failed: " + err.Error())
+// Run probes every downstream service now and then every probe interval,
+// until ctx is done. Each round is bounded by the interval, so a hanging
+// service cannot hold up the next one.
+func (h *HealthHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.probeInterval)
+	defer ticker.Stop()
+	for {
+		h.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
+}
 
-	// Check user service
-	if err := h.userClient.HealthCheck(c.Request.Context()); err != nil {
-		services["user-service"] = "unhealthy"
-		h.logger.Warn("User service health check failed: " + err.Error())
+func (h *HealthHandler) refresh(ctx context.Context) {
+	roundCtx, cancel := context.WithTimeout(ctx, h.probeInterval)
+	defer cancel()
+	results := h.probe(roundCtx, h.probes)
+	if ctx.Err() != nil {
+		return
 	}
 
-	// Check post service
-	if err := h.postClient.HealthCheck(c.Request.Context()); err != nil {
-		services["post-service"] = "unhealthy"
-		h.logger.Warn("Post service health check failed: " + err.Error())
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for name, result := range results {
+		entry, ok := h.cache[name]
+		if !ok {
+			entry = &cachedHealth{}
+			h.cache[name] = entry
+		}
+		// Log transitions only; every probe would flood the log during an outage.
+		changed := !ok || entry.result.Status != result.Status
+		switch {
+		case changed && result.Status != "healthy":
+			h.logger.Warn("downstream health check failed", logger.F("service", name), logger.F("error", result.Error))
+		case changed && ok:
+			h.logger.Info("downstream health check recovered", logger.F("service", name))
+		}
+		entry.result = result
+		entry.checkedAt = now
+		if result.Status == "healthy" {
+			entry.lastSuccess = now
+		}
 	}
+}
 
-	// Check notification service (HTTP health endpoint)
-	if err := h.checkNotificationService(c.Request.Context()); err != nil {
-		services["notification-service"] = "unhealthy"
-		h.logger.Warn("Notification service health check failed: " + err.Error())
+// snapshot returns the cached result of every probed service. A service not
+// yet probed, or whose last result is older than the cache TTL, is unknown.
+func (h *HealthHandler) snapshot(now time.Time) map[string]DependencyStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	services := make(map[string]DependencyStatus, len(h.probes))
+	for _, p := range h.probes {
+		entry, ok := h.cache[p.name]
+		if !ok || now.Sub(entry.checkedAt) > h.cacheTTL {
+			services[p.name] = DependencyStatus{Status: "unknown"}
+			if ok && !entry.lastSuccess.IsZero() {
+				services[p.name] = DependencyStatus{Status: "unknown", LastSuccessAgeMs: ageMs(now, entry.lastSuccess)}
+			}
+			continue
+		}
+		result := entry.result
+		result.CheckedAgeMs = ageMs(now, entry.checkedAt)
+		if !entry.lastSuccess.IsZero() {
+			result.LastSuccessAgeMs = ageMs(now, entry.lastSuccess)
+		}
+		services[p.name] = result
 	}
+	return services
+}
+
+func ageMs(now, at time.Time) *int64 {
+	age := now.Sub(at).Milliseconds()
+	return &age
+}
 
-	// Determine overall status
+// HealthCheck reports each downstream service as healthy or not, from the
+// cached probe results.
+func (h *HealthHandler) HealthCheck(c *gin.Context) {
+	services := make(map[string]string)
 	overallStatus := "healthy"
-	for _, status := range services {
-		if status == "unhealthy" {
+	for name, dependency := range h.snapshot(time.Now()) {
+		services[name] = "healthy"
+		if dependency.Status != "healthy" {
+			services[name] = "unhealthy"
 			overallStatus = "degraded"
-			break
 		}
 	}
 
@@ -91,25 +167,99 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	utils.SuccessResponse(c, statusCode, "Health check completed", response)
 }
 
-func (h *HealthHandler) checkNotificationService(ctx context.Context) error {
-	if h.notificationURL == "" {
-		return fmt.Errorf("notification service URL is not configured")
+// DependencyStatus is one downstream service's result in the detailed health
+// report. CheckedAgeMs is how long ago it was probed and LastSuccessAgeMs how
+// long ago it last answered healthy; nil when it never did.
+type DependencyStatus struct {
+	Status           string `json:"status"` // healthy, unhealthy or unknown
+	LatencyMs        int64  `json:"latency_ms"`
+	Error            string `json:"-"`
+	CheckedAgeMs     *int64 `json:"checked_age_ms,omitempty"`
+	LastSuccessAgeMs *int64 `json:"last_success_age_ms"`
+}
+
+// StartDraining makes Readiness report the gateway as not ready, so load
+// balancers stop sending it new requests while in-flight ones finish.
+func (h *HealthHandler) StartDraining() {
+	h.draining.Store(true)
+}
+
+// Readiness reports whether the gateway should receive traffic. It only
+// reflects the gateway's own state: a downstream outage degrades every
+// replica alike, so pulling them from the load balancer would not help.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	if h.draining.Load() {
+		utils.SuccessResponse(c, http.StatusServiceUnavailable, "Service is draining", gin.H{
+			"status":  "draining",
+			"service": "api-gateway",
+		})
+		return
 	}
+	utils.SuccessResponse(c, http.StatusOK, "Service is ready", gin.H{
+		"status":  "ready",
+		"service": "api-gateway",
+	})
+}
+
+type healthProbe struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// DetailedHealthCheck reports each downstream service's cached status, probe
+// latency and the age of its last successful check. Downstream health checks
+// verify their own database, Redis and RabbitMQ connections, so a service
+// whose dependency is down is reported unhealthy. Any service not healthy
+// makes the gateway "degraded" and the response 503.
+func (h *HealthHandler) DetailedHealthCheck(c *gin.Context) {
+	services := h.snapshot(time.Now())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.notificationURL+"/health", nil)
-	if err != nil {
-		return err
+	overallStatus := "healthy"
+	for _, dependency := range services {
+		if dependency.Status != "healthy" {
+			overallStatus = "degraded"
+			break
+		}
 	}
 
-	resp, err := h.healthClient.Do(req)
-	if err != nil {
-		return err
+	statusCode := http.StatusOK
+	if overallStatus == "degraded" {
+		statusCode = http.StatusServiceUnavailable
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	utils.SuccessResponse(c, statusCode, "Health check completed", gin.H{
+		"status":   overallStatus,
+		"service":  "api-gateway",
+		"services": services,
+	})
+}
+
+func (h *HealthHandler) probe(ctx context.Context, probes []healthProbe) map[string]DependencyStatus {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]DependencyStatus, len(probes))
+	)
+
+	for _, p := range probes {
+		wg.Add(1)
+		go func(p healthProbe) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := p.check(ctx)
+			result := DependencyStatus{Status: "healthy", LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = "unhealthy"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[p.name] = result
+			mu.Unlock()
+		}(p)
 	}
+	wg.Wait()
 
-	return nil
+	return results
 }
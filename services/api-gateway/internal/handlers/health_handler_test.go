@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/pkg/logger"
+)
+
+func TestHealthProbeReportsEachDependency(t *testing.T) {
+	h := &HealthHandler{logger: logger.New("info")}
+
+	results := h.probe(context.Background(), []healthProbe{
+		{name: "auth-service", check: func(context.Context) error { return nil }},
+		{name: "post-service", check: func(context.Context) error { return errors.New("connection refused") }},
+	})
+
+	if got := results["auth-service"].Status; got != "healthy" {
+		t.Errorf("expected auth-service healthy, got %q", got)
+	}
+	if got := results["post-service"].Status; got != "unhealthy" {
+		t.Errorf("expected post-service unhealthy, got %q", got)
+	}
+}
+
+func TestHealthProbeRunsChecksConcurrently(t *testing.T) {
+	h := &HealthHandler{logger: logger.New("info")}
+
+	slow := func(context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}
+
+	start := time.Now()
+	results := h.probe(context.Background(), []healthProbe{
+		{name: "auth-service", check: slow},
+		{name: "user-service", check: slow},
+		{name: "post-service", check: slow},
+	})
+	if elapsed := time.Since(start); elapsed >= 250*time.Millisecond {
+		t.Fatalf("expected checks to run concurrently, took %v", elapsed)
+	}
+	for name, result := range results {
+		if result.LatencyMs < 100 {
+			t.Errorf("%s: expected latency of at least 100ms, got %dms", name, result.LatencyMs)
+		}
+	}
+}
+
+func TestReadinessReportsDraining(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &HealthHandler{logger: logger.New("info")}
+	router := gin.New()
+	router.GET("/ready", h.Readiness)
+
+	serve := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return w.Code
+	}
+
+	if code := serve(); code != http.StatusOK {
+		t.Fatalf("expected ready before draining, got %d", code)
+	}
+	h.StartDraining()
+	if code := serve(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while draining, got %d", code)
+	}
+}
+
+func newCachedHealthHandler(probes ...healthProbe) *HealthHandler {
+	return &HealthHandler{
+		logger:        logger.New("info"),
+		probes:        probes,
+		probeInterval: time.Second,
+		cacheTTL:      5 * time.Second,
+		cache:         make(map[string]*cachedHealth),
+	}
+}
+
+func TestDetailedHealthServesCachedResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls atomic.Int32
+	h := newCachedHealthHandler(healthProbe{name: "post-service", check: func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}})
+	router := gin.New()
+	router.GET("/health/detailed", h.DetailedHealthCheck)
+	serve := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/detailed", nil))
+		return w.Code
+	}
+
+	if code := serve(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before the first probe, got %d", code)
+	}
+	h.refresh(context.Background())
+	for i := 0; i < 5; i++ {
+		if code := serve(); code != http.StatusOK {
+			t.Fatalf("expected 200 from the cached result, got %d", code)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected requests not to probe the service, got %d probes", got)
+	}
+}
+
+func TestHealthSnapshotReportsAgesAndExpiresStaleResults(t *testing.T) {
+	var failing atomic.Bool
+	h := newCachedHealthHandler(healthProbe{name: "post-service", check: func(context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}})
+
+	h.refresh(context.Background())
+	failing.Store(true)
+	h.refresh(context.Background())
+	checkedAt := h.cache["post-service"].checkedAt
+	lastSuccess := h.cache["post-service"].lastSuccess
+
+	result := h.snapshot(checkedAt.Add(2 * time.Second))["post-service"]
+	if result.Status != "unhealthy" || *result.CheckedAgeMs != 2000 {
+		t.Fatalf("expected an unhealthy result checked 2s ago, got %+v", result)
+	}
+	if want := checkedAt.Add(2 * time.Second).Sub(lastSuccess).Milliseconds(); result.LastSuccessAgeMs == nil || *result.LastSuccessAgeMs != want {
+		t.Fatalf("expected the last success %dms ago, got %v", want, result.LastSuccessAgeMs)
+	}
+
+	stale := h.snapshot(checkedAt.Add(6 * time.Second))["post-service"]
+	if stale.Status != "unknown" || stale.LastSuccessAgeMs == nil {
+		t.Fatalf("expected a result past the TTL to be unknown but keep its last success, got %+v", stale)
+	}
+}
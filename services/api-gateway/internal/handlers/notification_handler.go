@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/clients"
+	"api-gateway/internal/models"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/utils"
+)
+
+const notificationStreamPath = "/api/v1/notifications/stream"
+
+// NotificationHandler serves the notification endpoints through the
+// notification service's HTTP API and relays its event stream.
+type NotificationHandler struct {
+	notificationClient *clients.NotificationClient
+	streamProxy        *httputil.ReverseProxy
+	logger             *logger.Logger
+}
+
+func NewNotificationHandler(notificationClient *clients.NotificationClient, logger *logger.Logger) *NotificationHandler {
+	target := notificationClient.BaseURL()
+
+	h := &NotificationHandler{notificationClient: notificationClient, logger: logger}
+	h.streamProxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.URL.Path = notificationStreamPath
+			r.Out.URL.RawPath = ""
+			r.SetXForwarded()
+		},
+		Transport: notificationClient.Transport(),
+		// Flush every write so events reach the client as they arrive
+		// instead of being buffered.
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if r.Context().Err() != nil {
+				return
+			}
+			h.logger.Warn("Notification stream proxy failed: " + err.Error())
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadGateway)
+			_ = json.NewEncoder(w).Encode(models.APIResponse{
+				Success: false,
+				Message: "Request failed",
+				Error:   &models.ErrorData{Code: "NOTIFICATION_SERVICE_UNAVAILABLE", Message: "Notification service unavailable"},
+			})
+		},
+	}
+	return h
+}
+
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+
+	var req models.ListNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters")
+		return
+	}
+
+	response, err := h.notificationClient.ListNotifications(c.Request.Context(), caller, req)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications retrieved successfully", response)
+}
+
+func (h *NotificationHandler) GetNotification(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.notificationClient.GetNotification(c.Request.Context(), caller, c.Param("id"))
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification retrieved successfully", response)
+}
+
+func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+
+	var req models.MarkNotificationsReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format")
+		return
+	}
+
+	if err := h.notificationClient.MarkAsRead(c.Request.Context(), caller, req); err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications marked as read successfully", nil)
+}
+
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.notificationClient.GetUnreadCount(c.Request.Context(), caller)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unread count retrieved successfully", models.UnreadCountResponse{UnreadCount: count})
+}
+
+func (h *NotificationHandler) GetCountsByType(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+
+	var req models.NotificationCountsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters")
+		return
+	}
+
+	response, err := h.notificationClient.GetCountsByType(c.Request.Context(), caller, req)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification counts retrieved successfully", response)
+}
+
+func (h *NotificationHandler) ArchiveNotification(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationClient.ArchiveNotification(c.Request.Context(), caller, c.Param("id")); err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification archived successfully", nil)
+}
+
+func (h *NotificationHandler) UnarchiveNotification(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationClient.UnarchiveNotification(c.Request.Context(), caller, c.Param("id")); err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification unarchived successfully", nil)
+}
+
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationClient.DeleteNotification(c.Request.Context(), caller, c.Param("id")); err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification deleted successfully", nil)
+}
+
+// StreamNotifications relays the caller's Server-Sent Events stream from the
+// notification service without buffering. The bearer token is forwarded
+// as-is; the notification service verifies it again.
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	// Streams outlive the server's WriteTimeout.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to clear write deadline for notification stream: " + err.Error())
+	}
+
+	c.Request.Header.Set("X-User-ID", userID.(string))
+	h.streamProxy.ServeHTTP(c.Writer, c.Request)
+}
+
+// notificationCaller returns the authenticated user and bearer token set by
+// AuthMiddleware, writing a 401 when they are missing.
+func notificationCaller(c *gin.Context) (clients.NotificationCaller, bool) {
+	userID := c.GetString("userID")
+	token := c.GetString("token")
+	if userID == "" || token == "" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return clients.NotificationCaller{}, false
+	}
+	return clients.NotificationCaller{UserID: userID, Token: token}, true
+}
+
+// handleNotificationError passes the notification service's client errors
+// (4xx) through unchanged and reports everything else as the service being
+// unavailable.
+func (h *NotificationHandler) handleNotificationError(c *gin.Context, err error) {
+	var notificationErr *clients.NotificationError
+	if errors.As(err, &notificationErr) && notificationErr.StatusCode < http.StatusInternalServerError {
+		utils.ErrorResponse(c, notificationErr.StatusCode, notificationErr.Code, notificationErr.Message)
+		return
+	}
+
+	h.logger.Error("Notification service operation failed: " + err.Error())
+	utils.ErrorResponse(c, http.StatusServiceUnavailable, "NOTIFICATION_SERVICE_UNAVAILABLE", "Notification service unavailable")
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api-gateway/internal/clients"
+	"api-gateway/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNotificationHandler_StreamIsRelayedWithoutBuffering(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	seen := make(chan *http.Request, 1)
+	release := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen <- r
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: notification\ndata: {\"id\":\"n1\"}\n\n")
+		w.(http.Flusher).Flush()
+		// Hold the stream open: the client must see the event before the
+		// backend response completes.
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer backend.Close()
+	defer close(release)
+
+	h := newTestNotificationHandler(t, backend.URL)
+	router := gin.New()
+	router.GET("/api/v1/notifications/stream", func(c *gin.Context) {
+		c.Set("userID", "user1")
+		h.StreamNotifications(c)
+	})
+	gateway := httptest.NewServer(router)
+	defer gateway.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, gateway.URL+"/api/v1/notifications/stream", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	upstream := <-seen
+	if upstream.URL.Path != "/api/v1/notifications/stream" {
+		t.Errorf("expected stream path upstream, got %s", upstream.URL.Path)
+	}
+	if upstream.Header.Get("Authorization") != "Bearer token" || upstream.Header.Get("X-User-ID") != "user1" {
+		t.Errorf("expected token and user forwarded, got %v", upstream.Header)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	for lines.Scan() {
+		if strings.HasPrefix(lines.Text(), "data: ") {
+			if !strings.Contains(lines.Text(), `"n1"`) {
+				t.Errorf("unexpected event data %s", lines.Text())
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended before the first event: %v", lines.Err())
+}
+
+func newTestNotificationHandler(t *testing.T, url string) *NotificationHandler {
+	t.Helper()
+	client, err := clients.NewNotificationClient(url, nil, logger.New("info"))
+	if err != nil {
+		t.Fatalf("NewNotificationClient: %v", err)
+	}
+	return NewNotificationHandler(client, logger.New("info"))
+}
+
+// authenticated stands in for AuthMiddleware.
+func authenticated(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("userID", "user1")
+		c.Set("token", "token")
+		handler(c)
+	}
+}
+
+func TestNewNotificationClient_RejectsInvalidURL(t *testing.T) {
+	if _, err := clients.NewNotificationClient("not a url", nil, logger.New("info")); err == nil {
+		t.Fatal("expected an invalid notification URL to be rejected")
+	}
+}
+
+func TestNotificationHandler_ListForwardsCallerAndQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var upstream *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream = r
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"message":"ok","data":{"notifications":[{"id":"n1","user_id":"user1","type":"post_created","title":"t","message":"m","read":false,"created_at":"2026-01-02T03:04:05Z"}],"limit":5,"offset":10,"total":11,"has_more":false,"unread_count":3}}`)
+	}))
+	defer backend.Close()
+
+	h := newTestNotificationHandler(t, backend.URL)
+	router := gin.New()
+	router.GET("/api/v1/notifications", authenticated(h.ListNotifications))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/notifications?limit=5&offset=10&unread=true&type=post_created&priority=high", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if upstream.URL.Path != "/api/v1/notifications" {
+		t.Errorf("expected list path upstream, got %s", upstream.URL.Path)
+	}
+	query := upstream.URL.Query()
+	if query.Get("limit") != "5" || query.Get("offset") != "10" || query.Get("unread") != "true" || query.Get("type") != "post_created" || query.Get("priority") != "high" {
+		t.Errorf("expected query forwarded, got %s", upstream.URL.RawQuery)
+	}
+	if upstream.Header.Get("Authorization") != "Bearer token" || upstream.Header.Get("X-User-ID") != "user1" {
+		t.Errorf("expected token and user forwarded, got %v", upstream.Header)
+	}
+	if !strings.Contains(w.Body.String(), `"id":"n1"`) || !strings.Contains(w.Body.String(), `"unread_count":3`) {
+		t.Errorf("expected notifications in response, got %s", w.Body.String())
+	}
+}
+
+func TestNotificationHandler_CountsForwardUnreadFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var upstream *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream = r
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"message":"ok","data":{"counts":{"comment_added":3,"system_alert":1,"post_created":0},"total":4,"unread_only":true}}`)
+	}))
+	defer backend.Close()
+
+	h := newTestNotificationHandler(t, backend.URL)
+	router := gin.New()
+	router.GET("/api/v1/notifications/counts", authenticated(h.GetCountsByType))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/notifications/counts?unread=true", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if upstream.URL.Path != "/api/v1/notifications/counts" || upstream.URL.Query().Get("unread") != "true" {
+		t.Errorf("expected the unread filter forwarded, got %s", upstream.URL)
+	}
+	if !strings.Contains(w.Body.String(), `"comment_added":3`) || !strings.Contains(w.Body.String(), `"post_created":0`) {
+		t.Errorf("expected every count in the response, got %s", w.Body.String())
+	}
+}
+
+func TestNotificationHandler_ArchiveIsForwarded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var upstream *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream = r
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"message":"ok"}`)
+	}))
+	defer backend.Close()
+
+	h := newTestNotificationHandler(t, backend.URL)
+	router := gin.New()
+	router.POST("/api/v1/notifications/:id/archive", authenticated(h.ArchiveNotification))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/notifications/n1/archive", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if upstream.Method != http.MethodPost || upstream.URL.Path != "/api/v1/notifications/n1/archive" {
+		t.Errorf("expected the archive call upstream, got %s %s", upstream.Method, upstream.URL.Path)
+	}
+}
+
+func TestNotificationHandler_PassesThroughClientErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"success":false,"message":"req failed","error":{"code":"NOTIFICATION_NOT_FOUND","message":"Notification not found"}}`)
+	}))
+	defer backend.Close()
+
+	h := newTestNotificationHandler(t, backend.URL)
+	router := gin.New()
+	router.DELETE("/api/v1/notifications/:id", authenticated(h.DeleteNotification))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/notifications/n1", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "NOTIFICATION_NOT_FOUND") {
+		t.Errorf("expected upstream error code, got %s", w.Body.String())
+	}
+}
+
+func TestNotificationHandler_UnreachableServiceIsUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	backend := httptest.NewServer(http.NotFoundHandler())
+	backend.Close()
+
+	h := newTestNotificationHandler(t, backend.URL)
+	router := gin.New()
+	router.GET("/api/v1/notifications/unread-count", authenticated(h.GetUnreadCount))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/notifications/unread-count", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", w.Code)
+	}
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	"api-gateway/internal/clients"
 	"api-gateway/internal/models"
@@ -14,15 +16,32 @@ import (
 	"api-gateway/pkg/utils"
 )
 
+// UserProvisioner is the subset of the user client PostHandler needs to
+// create a missing user record before a user's first post.
+type UserProvisioner interface {
+	GetUser(ctx context.Context, id string) (*models.UserResponse, error)
+	CreateUser(ctx context.Context, input *clients.CreateUserInput) (*models.UserResponse, error)
+}
+
+// AuthorLookup is the subset of the user client PostHandler needs to attach
+// a post's author to the aggregated post endpoints.
+type AuthorLookup interface {
+	GetUserProfile(ctx context.Context, id string) (*models.UserProfileResponse, error)
+}
+
 type PostHandler struct {
-	postClient *clients.PostClient
-	logger     *logger.Logger
+	postClient      *clients.PostClient
+	userProvisioner UserProvisioner // nil disables auto-provisioning
+	authors         AuthorLookup
+	logger          *logger.Logger
 }
 
-func NewPostHandler(postClient *clients.PostClient, logger *logger.Logger) *PostHandler {
+func NewPostHandler(postClient *clients.PostClient, userProvisioner UserProvisioner, authors AuthorLookup, logger *logger.Logger) *PostHandler {
 	return &PostHandler{
-		postClient: postClient,
-		logger:     logger,
+		postClient:      postClient,
+		userProvisioner: userProvisioner,
+		authors:         authors,
+		logger:          logger,
 	}
 }
 
@@ -41,12 +60,20 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
+	if err := h.ensureUserProvisioned(c.Request.Context(), userID.(string), c.GetString("userEmail")); err != nil {
+		h.logger.Error("Failed to provision user " + userID.(string) + ": " + err.Error())
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "USER_PROVISIONING_FAILED", "Failed to set up user profile")
+		return
+	}
+
 	input := &clients.CreatePostInput{
-		UserID:    userID.(string),
-		Title:     req.Title,
-		Content:   req.Content,
-		Slug:      req.Slug,
-		Published: req.Published,
+		UserID:        userID.(string),
+		Title:         req.Title,
+		Content:       req.Content,
+		Slug:          req.Slug,
+		Published:     req.Published,
+		CoverImageURL: req.CoverImageURL,
+		Excerpt:       req.Excerpt,
 	}
 
 	response, err := h.postClient.CreatePost(c.Request.Context(), input)
@@ -72,7 +99,7 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 		userIDStr = userID.(string)
 	}
 
-	response, err := h.postClient.GetPost(c.Request.Context(), id, userIDStr)
+	response, err := h.postClient.GetPost(c.Request.Context(), id, userIDStr, wantsHTML(c))
 	if err != nil {
 		h.handlePostError(c, err, "POST_NOT_FOUND", "Post not found")
 		return
@@ -89,7 +116,7 @@ func (h *PostHandler) GetPostBySlug(c *gin.Context) {
 		return
 	}
 
-	response, err := h.postClient.GetPostBySlug(c.Request.Context(), slug)
+	response, err := h.postClient.GetPostBySlug(c.Request.Context(), slug, c.GetString("userID"), wantsHTML(c))
 	if err != nil {
 		h.handlePostError(c, err, "POST_NOT_FOUND", "Post not found")
 		return
@@ -98,6 +125,65 @@ func (h *PostHandler) GetPostBySlug(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Post retrieved successfully", response)
 }
 
+// GetPostWithAuthor returns a post together with its author's public profile.
+// The post service applies the usual published/ownership rules to the post.
+func (h *PostHandler) GetPostWithAuthor(c *gin.Context) {
+	id := c.Param("id")
+
+	if id == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Post ID is required")
+		return
+	}
+
+	post, err := h.postClient.GetPost(c.Request.Context(), id, c.GetString("userID"), wantsHTML(c))
+	if err != nil {
+		h.handlePostError(c, err, "POST_NOT_FOUND", "Post not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post retrieved successfully", h.withAuthor(c.Request.Context(), post))
+}
+
+// GetPostBySlugWithAuthor is the slug variant of GetPostWithAuthor.
+func (h *PostHandler) GetPostBySlugWithAuthor(c *gin.Context) {
+	slug := c.Param("slug")
+
+	if slug == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Post slug is required")
+		return
+	}
+
+	post, err := h.postClient.GetPostBySlug(c.Request.Context(), slug, c.GetString("userID"), wantsHTML(c))
+	if err != nil {
+		h.handlePostError(c, err, "POST_NOT_FOUND", "Post not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post retrieved successfully", h.withAuthor(c.Request.Context(), post))
+}
+
+// withAuthor looks up the post's author. The author ID is only known once the
+// post is loaded, so the two calls run in sequence. A failed lookup (deleted
+// or deactivated author, user service down) still returns the post, with a
+// null author, rather than failing the whole request.
+func (h *PostHandler) withAuthor(ctx context.Context, post *models.PostResponse) *models.PostWithAuthorResponse {
+	response := &models.PostWithAuthorResponse{Post: post}
+	if h.authors == nil || post.UserID == "" {
+		return response
+	}
+
+	author, err := h.authors.GetUserProfile(ctx, post.UserID)
+	if err != nil {
+		if asClientError(err).Status != http.StatusNotFound {
+			h.logger.Warn(fmt.Sprintf("Failed to load author %s of post %s: %v", post.UserID, post.ID, err))
+		}
+		return response
+	}
+
+	response.Author = author
+	return response
+}
+
 func (h *PostHandler) UpdatePost(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -119,12 +205,14 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	}
 
 	input := &clients.UpdatePostInput{
-		ID:        id,
-		UserID:    userID.(string),
-		Title:     req.Title,
-		Content:   req.Content,
-		Slug:      req.Slug,
-		Published: req.Published,
+		ID:            id,
+		UserID:        userID.(string),
+		Title:         req.Title,
+		Content:       req.Content,
+		Slug:          req.Slug,
+		Published:     req.Published,
+		CoverImageURL: req.CoverImageURL,
+		Excerpt:       req.Excerpt,
 	}
 
 	response, err := h.postClient.UpdatePost(c.Request.Context(), input)
@@ -157,6 +245,192 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Post deleted successfully", nil)
 }
 
+// BulkDeletePosts deletes many of the caller's posts at once. Ids the caller
+// does not own are skipped and reported as not deleted.
+func (h *PostHandler) BulkDeletePosts(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	var req models.BulkDeletePostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid bulk delete request: " + err.Error())
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Between 1 and 100 post ids are required")
+		return
+	}
+
+	response, err := h.postClient.DeletePosts(c.Request.Context(), req.IDs, userID.(string))
+	if err != nil {
+		h.handlePostError(c, err, "DELETE_FAILED", "Failed to delete posts")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Posts deleted successfully", response)
+}
+
+// ArchivePost retires one of the caller's posts: it leaves public listings,
+// search and slug lookups but stays visible to the caller.
+func (h *PostHandler) ArchivePost(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Post ID is required")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	response, err := h.postClient.ArchivePost(c.Request.Context(), id, userID.(string))
+	if err != nil {
+		h.handlePostError(c, err, "ARCHIVE_FAILED", "Failed to archive post")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post archived successfully", response)
+}
+
+func (h *PostHandler) LikePost(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Post ID is required")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	response, err := h.postClient.LikePost(c.Request.Context(), id, userID.(string))
+	if err != nil {
+		h.handlePostError(c, err, "LIKE_FAILED", "Failed to like post")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post liked successfully", response)
+}
+
+func (h *PostHandler) UnlikePost(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Post ID is required")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	response, err := h.postClient.UnlikePost(c.Request.Context(), id, userID.(string))
+	if err != nil {
+		h.handlePostError(c, err, "UNLIKE_FAILED", "Failed to unlike post")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post unliked successfully", response)
+}
+
+func (h *PostHandler) ClonePost(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Post ID is required")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	response, err := h.postClient.ClonePost(c.Request.Context(), id, userID.(string))
+	if err != nil {
+		h.handlePostError(c, err, "CLONE_FAILED", "Failed to clone post")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Post cloned successfully", response)
+}
+
+// GetPostByPreviewToken serves a draft preview link without login; the token
+// is the credential.
+func (h *PostHandler) GetPostByPreviewToken(c *gin.Context) {
+	token := c.Param("token")
+
+	if token == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Preview token is required")
+		return
+	}
+
+	response, err := h.postClient.GetPostByPreviewToken(c.Request.Context(), token, wantsHTML(c))
+	if err != nil {
+		h.handlePostError(c, err, "POST_NOT_FOUND", "Post not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post retrieved successfully", response)
+}
+
+func (h *PostHandler) CreatePreviewToken(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Post ID is required")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	var req models.CreatePreviewTokenRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Invalid preview token request: " + err.Error())
+			utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format")
+			return
+		}
+	}
+
+	response, err := h.postClient.CreatePreviewToken(c.Request.Context(), id, userID.(string), req.ExpiresInHours)
+	if err != nil {
+		h.handlePostError(c, err, "PREVIEW_TOKEN_FAILED", "Failed to create preview token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Preview token generated successfully", response)
+}
+
+func (h *PostHandler) RevokePreviewToken(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Post ID is required")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	if err := h.postClient.RevokePreviewToken(c.Request.Context(), id, userID.(string)); err != nil {
+		h.handlePostError(c, err, "PREVIEW_TOKEN_FAILED", "Failed to revoke preview token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Preview token revoked successfully", nil)
+}
+
 func (h *PostHandler) ListPosts(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "20")
 	offsetStr := c.DefaultQuery("offset", "0")
@@ -171,10 +445,15 @@ func (h *PostHandler) ListPosts(c *gin.Context) {
 		offset = 0
 	}
 
+	createdIn, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
 	// Public route must never expose drafts, ignore client override.
 	publishedOnly := true
 
-	response, err := h.postClient.ListPosts(c.Request.Context(), limit, offset, publishedOnly)
+	response, err := h.postClient.ListPosts(c.Request.Context(), limit, offset, publishedOnly, createdIn)
 	if err != nil {
 		h.handlePostError(c, err, "LIST_FAILED", "Failed to retrieve posts")
 		return
@@ -203,7 +482,17 @@ func (h *PostHandler) GetUserPosts(c *gin.Context) {
 		offset = 0
 	}
 
-	response, err := h.postClient.GetUserPosts(c.Request.Context(), userID, limit, offset)
+	// Drafts and archived posts are listed only on the authenticated route,
+	// and only to their owner; post-service enforces the latter.
+	status := c.Query("status")
+	switch status {
+	case "", "draft", "published", "archived":
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "status must be draft, published or archived")
+		return
+	}
+
+	response, err := h.postClient.GetUserPosts(c.Request.Context(), userID, status, c.GetString("userID"), limit, offset)
 	if err != nil {
 		h.handlePostError(c, err, "USER_POSTS_FAILED", "Failed to retrieve user posts")
 		return
@@ -232,10 +521,15 @@ func (h *PostHandler) SearchPosts(c *gin.Context) {
 		offset = 0
 	}
 
+	createdIn, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
 	// Public route must never expose drafts, ignore client override.
 	publishedOnly := true
 
-	response, err := h.postClient.SearchPosts(c.Request.Context(), query, limit, offset, publishedOnly)
+	response, err := h.postClient.SearchPosts(c.Request.Context(), query, limit, offset, publishedOnly, createdIn)
 	if err != nil {
 		h.handlePostError(c, err, "SEARCH_FAILED", "Failed to search posts")
 		return
@@ -244,6 +538,44 @@ func (h *PostHandler) SearchPosts(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Post search completed successfully", response)
 }
 
+// wantsHTML reports whether the caller asked for rendered content with
+// render=html; post-service then adds content_html next to the Markdown.
+func wantsHTML(c *gin.Context) bool {
+	return c.Query("render") == "html"
+}
+
+// parseDateRange reads the optional RFC3339 from/to query parameters. On a
+// malformed or inverted range it answers 400 and returns false.
+func parseDateRange(c *gin.Context) (models.DateRange, bool) {
+	var createdIn models.DateRange
+	var err error
+	if createdIn.From, err = parseTimeQuery(c, "from"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "from must be an RFC3339 timestamp")
+		return models.DateRange{}, false
+	}
+	if createdIn.To, err = parseTimeQuery(c, "to"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "to must be an RFC3339 timestamp")
+		return models.DateRange{}, false
+	}
+	if createdIn.From != nil && createdIn.To != nil && createdIn.From.After(*createdIn.To) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "from must not be after to")
+		return models.DateRange{}, false
+	}
+	return createdIn, true
+}
+
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 func (h *PostHandler) GetStats(c *gin.Context) {
 	userID := ""
 	if uid, exists := c.Get("userID"); exists {
@@ -271,29 +603,53 @@ func (h *PostHandler) handlePostError(c *gin.Context, err error, code, message s
 		return
 	}
 
-	if st, ok := status.FromError(err); ok {
-		switch st.Code() {
-		case codes.NotFound:
-			utils.ErrorResponse(c, http.StatusNotFound, code, message)
-			return
-		case codes.AlreadyExists:
-			utils.ErrorResponse(c, http.StatusConflict, code, message)
-			return
-		case codes.InvalidArgument:
-			utils.ErrorResponse(c, http.StatusBadRequest, code, message)
-			return
-		case codes.PermissionDenied:
-			utils.ErrorResponse(c, http.StatusForbidden, code, message)
-			return
-		case codes.Unauthenticated:
-			utils.ErrorResponse(c, http.StatusUnauthorized, code, message)
-			return
-		case codes.Unavailable:
-			utils.ErrorResponse(c, http.StatusServiceUnavailable, code, message)
-			return
-		}
+	clientErr := asClientError(err)
+	if clientErr.Status >= http.StatusInternalServerError {
+		h.logger.Error("Post service operation failed: " + err.Error())
+	}
+	respondClientError(c, clientErr, code, message)
+}
+
+// ensureUserProvisioned creates the caller's user record from the token claims
+// when user-service reports it missing, e.g. because registration failed during
+// login. It is idempotent: an existing record, or one created concurrently, is
+// left as is. Any other lookup failure is logged and does not block the caller;
+// only a failed provisioning attempt is returned.
+func (h *PostHandler) ensureUserProvisioned(ctx context.Context, userID, email string) error {
+	if h.userProvisioner == nil {
+		return nil
+	}
+
+	_, err := h.userProvisioner.GetUser(ctx, userID)
+	if err == nil {
+		return nil
+	}
+	if asClientError(err).Status != http.StatusNotFound {
+		h.logger.Warn("Skipping user provisioning check for " + userID + ": " + err.Error())
+		return nil
+	}
+	if email == "" {
+		return fmt.Errorf("user record missing and token has no email")
+	}
+
+	h.logger.Warn("User " + userID + " has no user record, provisioning from token claims")
+	_, err = h.userProvisioner.CreateUser(ctx, &clients.CreateUserInput{
+		ID:    userID,
+		Email: email,
+		Name:  nameFromEmail(email),
+	})
+	if err != nil && asClientError(err).Status != http.StatusConflict {
+		return err
 	}
 
-	h.logger.Error("Post service operation failed: " + err.Error())
-	utils.ErrorResponse(c, http.StatusInternalServerError, code, message)
+	return nil
+}
+
+// nameFromEmail derives a placeholder display name the user can change later.
+func nameFromEmail(email string) string {
+	name, _, _ := strings.Cut(email, "@")
+	if name == "" {
+		return email
+	}
+	return name
 }
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api-gateway/internal/clients"
+	"api-gateway/internal/models"
+	"api-gateway/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockUserProvisioner struct {
+	getErr    error
+	createErr error
+	created   []*clients.CreateUserInput
+}
+
+func (m *mockUserProvisioner) GetUser(ctx context.Context, id string) (*models.UserResponse, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	return &models.UserResponse{ID: id}, nil
+}
+
+func (m *mockUserProvisioner) CreateUser(ctx context.Context, input *clients.CreateUserInput) (*models.UserResponse, error) {
+	m.created = append(m.created, input)
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	return &models.UserResponse{ID: input.ID, Email: input.Email, Name: input.Name}, nil
+}
+
+func TestEnsureUserProvisioned_CreatesMissingUserFromClaims(t *testing.T) {
+	users := &mockUserProvisioner{getErr: &clients.ClientError{Status: http.StatusNotFound, Code: "USER_NOT_FOUND", Message: "User not found"}}
+	h := NewPostHandler(nil, users, nil, logger.New("error"))
+
+	if err := h.ensureUserProvisioned(context.Background(), "user-1", "jane.doe@example.com"); err != nil {
+		t.Fatalf("ensureUserProvisioned: %v", err)
+	}
+	if len(users.created) != 1 {
+		t.Fatalf("expected one create, got %d", len(users.created))
+	}
+	got := users.created[0]
+	if got.ID != "user-1" || got.Email != "jane.doe@example.com" || got.Name != "jane.doe" {
+		t.Fatalf("unexpected create input: %+v", got)
+	}
+}
+
+func TestEnsureUserProvisioned_ExistingUserIsLeftAlone(t *testing.T) {
+	users := &mockUserProvisioner{}
+	h := NewPostHandler(nil, users, nil, logger.New("error"))
+
+	if err := h.ensureUserProvisioned(context.Background(), "user-1", "dev@example.com"); err != nil {
+		t.Fatalf("ensureUserProvisioned: %v", err)
+	}
+	if len(users.created) != 0 {
+		t.Fatalf("expected no create for an existing user, got %d", len(users.created))
+	}
+}
+
+func TestEnsureUserProvisioned_ConcurrentCreateIsIdempotent(t *testing.T) {
+	users := &mockUserProvisioner{
+		getErr:    &clients.ClientError{Status: http.StatusNotFound, Code: "USER_NOT_FOUND", Message: "User not found"},
+		createErr: &clients.ClientError{Status: http.StatusConflict, Code: "USER_ALREADY_EXISTS", Message: "User already exists"},
+	}
+	h := NewPostHandler(nil, users, nil, logger.New("error"))
+
+	if err := h.ensureUserProvisioned(context.Background(), "user-1", "dev@example.com"); err != nil {
+		t.Fatalf("expected AlreadyExists to be treated as success, got %v", err)
+	}
+}
+
+func TestEnsureUserProvisioned_DisabledSkipsLookup(t *testing.T) {
+	h := NewPostHandler(nil, nil, nil, logger.New("error"))
+
+	if err := h.ensureUserProvisioned(context.Background(), "user-1", "dev@example.com"); err != nil {
+		t.Fatalf("expected no-op when auto-provisioning is disabled, got %v", err)
+	}
+}
+
+func TestEnsureUserProvisioned_LookupFailureDoesNotBlock(t *testing.T) {
+	users := &mockUserProvisioner{getErr: &clients.ClientError{Status: http.StatusServiceUnavailable, Message: "connection refused"}}
+	h := NewPostHandler(nil, users, nil, logger.New("error"))
+
+	if err := h.ensureUserProvisioned(context.Background(), "user-1", "dev@example.com"); err != nil {
+		t.Fatalf("expected a failed lookup to be skipped, got %v", err)
+	}
+	if len(users.created) != 0 {
+		t.Fatalf("expected no create without a NotFound lookup, got %d", len(users.created))
+	}
+}
+
+func TestCreatePost_ProvisioningFailureIsReported(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := &mockUserProvisioner{
+		getErr:    &clients.ClientError{Status: http.StatusNotFound, Code: "USER_NOT_FOUND", Message: "User not found"},
+		createErr: &clients.ClientError{Status: http.StatusServiceUnavailable, Message: "connection refused"},
+	}
+	h := NewPostHandler(nil, users, nil, logger.New("error"))
+
+	r := gin.New()
+	r.POST("/posts", func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Set("userEmail", "dev@example.com")
+		h.CreatePost(c)
+	})
+
+	body := `{"title":"Hello","content":"World"}`
+	req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d body %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "USER_PROVISIONING_FAILED") {
+		t.Fatalf("expected USER_PROVISIONING_FAILED, got %s", rec.Body.String())
+	}
+}
+
+type mockAuthorLookup struct {
+	err    error
+	lookup []string
+}
+
+func (m *mockAuthorLookup) GetUserProfile(ctx context.Context, id string) (*models.UserProfileResponse, error) {
+	m.lookup = append(m.lookup, id)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.UserProfileResponse{ID: id, Name: "Jane"}, nil
+}
+
+func TestWithAuthor_AttachesAuthorProfile(t *testing.T) {
+	authors := &mockAuthorLookup{}
+	h := NewPostHandler(nil, nil, authors, logger.New("error"))
+
+	resp := h.withAuthor(context.Background(), &models.PostResponse{ID: "post-1", UserID: "user-1"})
+
+	if resp.Post == nil || resp.Post.ID != "post-1" {
+		t.Fatalf("expected the post in the response, got %+v", resp.Post)
+	}
+	if resp.Author == nil || resp.Author.ID != "user-1" || resp.Author.Name != "Jane" {
+		t.Fatalf("expected author user-1, got %+v", resp.Author)
+	}
+	if len(authors.lookup) != 1 || authors.lookup[0] != "user-1" {
+		t.Fatalf("expected a single lookup of user-1, got %v", authors.lookup)
+	}
+}
+
+func TestWithAuthor_FailedLookupKeepsPost(t *testing.T) {
+	for _, err := range []error{
+		&clients.ClientError{Status: http.StatusNotFound, Code: "USER_NOT_FOUND", Message: "User not found"},
+		&clients.ClientError{Status: http.StatusServiceUnavailable, Message: "connection refused"},
+	} {
+		h := NewPostHandler(nil, nil, &mockAuthorLookup{err: err}, logger.New("error"))
+
+		resp := h.withAuthor(context.Background(), &models.PostResponse{ID: "post-1", UserID: "user-1"})
+
+		if resp.Post == nil || resp.Post.ID != "post-1" {
+			t.Fatalf("%v: expected the post to be returned, got %+v", err, resp.Post)
+		}
+		if resp.Author != nil {
+			t.Fatalf("%v: expected a null author, got %+v", err, resp.Author)
+		}
+	}
+}
+
+func TestHandlePostError_PropagatesDownstreamStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPostHandler(nil, nil, nil, logger.New("error"))
+
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"not found", &clients.ClientError{Status: http.StatusNotFound, Code: "POST_NOT_FOUND", Message: "Post not found"}, http.StatusNotFound, "POST_NOT_FOUND"},
+		{"forbidden", &clients.ClientError{Status: http.StatusForbidden, Code: "UNAUTHORIZED_ACCESS", Message: "Unauthorized access"}, http.StatusForbidden, "UNAUTHORIZED_ACCESS"},
+		{"conflict", &clients.ClientError{Status: http.StatusConflict, Code: "POST_ALREADY_EXISTS", Message: "Post already exists"}, http.StatusConflict, "POST_ALREADY_EXISTS"},
+		{"locked", &clients.ClientError{Status: http.StatusLocked, Code: "POST_LOCKED", Message: "Post is locked"}, http.StatusLocked, "POST_LOCKED"},
+		{"no code", &clients.ClientError{Status: http.StatusServiceUnavailable, Message: "connection refused"}, http.StatusServiceUnavailable, "UPDATE_FAILED"},
+		{"wrapped", fmt.Errorf("update: %w", &clients.ClientError{Status: http.StatusGatewayTimeout, Message: "context deadline exceeded"}), http.StatusGatewayTimeout, "UPDATE_FAILED"},
+		{"not downstream", errors.New("boom"), http.StatusInternalServerError, "UPDATE_FAILED"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.PUT("/posts/:id", func(c *gin.Context) {
+				h.handlePostError(c, tc.err, "UPDATE_FAILED", "Failed to update post")
+			})
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/posts/post-1", nil))
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d body %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), `"code":"`+tc.wantCode+`"`) {
+				t.Fatalf("expected code %s, got %s", tc.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestListPosts_RejectsInvalidDateRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPostHandler(nil, nil, nil, logger.New("error"))
+
+	r := gin.New()
+	r.GET("/posts", h.ListPosts)
+	r.GET("/posts/search", h.SearchPosts)
+
+	for _, target := range []string{
+		"/posts?from=yesterday",
+		"/posts?from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z",
+		"/posts/search?q=go&to=2024-13-01T00:00:00Z",
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d body %s", target, rec.Code, rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), "INVALID_REQUEST") {
+			t.Fatalf("%s: expected INVALID_REQUEST, got %s", target, rec.Body.String())
+		}
+	}
+}
+
+func TestBulkDeletePosts_RejectsEmptyAndOversizedBatches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPostHandler(nil, nil, nil, logger.New("error"))
+
+	r := gin.New()
+	r.POST("/posts/bulk-delete", func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		h.BulkDeletePosts(c)
+	})
+
+	ids := make([]string, 101)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%q", fmt.Sprintf("post-%d", i))
+	}
+	for _, body := range []string{`{}`, `{"ids":[]}`, `{"ids":[""]}`, `{"ids":[` + strings.Join(ids, ",") + `]}`} {
+		req := httptest.NewRequest(http.MethodPost, "/posts/bulk-delete", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %.40s, got %d body %s", body, rec.Code, rec.Body.String())
+		}
+	}
+}
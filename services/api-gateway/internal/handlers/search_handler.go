@@ -17,7 +17,7 @@ import (
 
 // SearchClient is the minimal interface used by SearchHandler for testability.
 type SearchClient interface {
-	Search(ctx context.Context, query, requestingUserID string, usersLimit, postsLimit int32, usersCursor, postsCursor string) (*searchv1.SearchResponse, error)
+	Search(ctx context.Context, query, requestingUserID string, usersLimit, postsLimit int32, usersCursor, postsCursor string, includeContent bool) (*searchv1.SearchResponse, error)
 }
 
 type SearchHandler struct {
@@ -58,7 +58,19 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	usersCursor := c.Query("users_cursor")
 	postsCursor := c.Query("posts_cursor")
 
-	resp, err := h.searchClient.Search(c.Request.Context(), query, requestingUserID, int32(usersLimit), int32(postsLimit), usersCursor, postsCursor)
+	// Full post content is opt-in via ?include=content; search-service applies a
+	// smaller page cap to those requests.
+	includeContent := false
+	switch c.Query("include") {
+	case "":
+	case "content":
+		includeContent = true
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_INCLUDE", "include must be 'content'")
+		return
+	}
+
+	resp, err := h.searchClient.Search(c.Request.Context(), query, requestingUserID, int32(usersLimit), int32(postsLimit), usersCursor, postsCursor, includeContent)
 	if err != nil {
 		h.handleSearchError(c, err)
 		return
@@ -75,10 +87,9 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Search completed successfully", data)
 }
 
+// protoUsersToMap always returns a non-nil slice so that a search with no
+// matches serializes as [] rather than null.
 func protoUsersToMap(users []*searchv1.SearchUserHit) []map[string]interface{} {
-	if users == nil {
-		return nil
-	}
 	out := make([]map[string]interface{}, 0, len(users))
 	for _, u := range users {
 		out = append(out, map[string]interface{}{
@@ -92,19 +103,20 @@ func protoUsersToMap(users []*searchv1.SearchUserHit) []map[string]interface{} {
 }
 
 func protoPostsToMap(posts []*searchv1.SearchPostHit) []map[string]interface{} {
-	if posts == nil {
-		return nil
-	}
 	out := make([]map[string]interface{}, 0, len(posts))
 	for _, p := range posts {
-		out = append(out, map[string]interface{}{
+		post := map[string]interface{}{
 			"id":              p.GetId(),
 			"user_id":         p.GetUserId(),
 			"title":           p.GetTitle(),
 			"slug":            p.GetSlug(),
 			"content_preview": p.GetContentPreview(),
 			"published":       p.GetPublished(),
-		})
+		}
+		if p.GetContent() != "" {
+			post["content"] = p.GetContent()
+		}
+		out = append(out, post)
 	}
 	return out
 }
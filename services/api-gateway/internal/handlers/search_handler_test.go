@@ -4,20 +4,25 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"api-gateway/pkg/logger"
 
 	"github.com/gin-gonic/gin"
 	searchv1 "github.com/nikitashilov/microblog_grpc/proto/search/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type mockSearchClient struct {
-	resp *searchv1.SearchResponse
-	err  error
+	resp           *searchv1.SearchResponse
+	err            error
+	includeContent bool
 }
 
-func (m *mockSearchClient) Search(ctx context.Context, query, requestingUserID string, usersLimit, postsLimit int32, usersCursor, postsCursor string) (*searchv1.SearchResponse, error) {
+func (m *mockSearchClient) Search(ctx context.Context, query, requestingUserID string, usersLimit, postsLimit int32, usersCursor, postsCursor string, includeContent bool) (*searchv1.SearchResponse, error) {
+	m.includeContent = includeContent
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -94,3 +99,142 @@ func TestSearchHandler_Search_MissingQuery(t *testing.T) {
 		t.Errorf("expected status 400 when q missing, got %d", rec.Code)
 	}
 }
+
+func TestSearchHandler_Search_NoMatchesReturnsEmptyLists(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("info")
+	h := NewSearchHandler(&mockSearchClient{resp: &searchv1.SearchResponse{}}, log)
+
+	r := gin.New()
+	r.GET("/search", func(c *gin.Context) {
+		c.Set("userID", "user1")
+		h.Search(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=nothing", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for no matches, got %d body %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"users":[]`) || !strings.Contains(body, `"posts":[]`) {
+		t.Errorf("expected empty users and posts arrays, got %s", body)
+	}
+}
+
+func TestSearchHandler_Search_DownstreamErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("info")
+
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"internal", status.Error(codes.Internal, "opensearch failure"), http.StatusInternalServerError},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewSearchHandler(&mockSearchClient{err: tc.err}, log)
+
+			r := gin.New()
+			r.GET("/search", func(c *gin.Context) {
+				c.Set("userID", "user1")
+				h.Search(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/search?q=alice", nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Errorf("expected status %d, got %d", tc.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestSearchHandler_Search_IncludeContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mock := &mockSearchClient{
+		resp: &searchv1.SearchResponse{
+			Posts: []*searchv1.SearchPostHit{
+				{Id: "p1", Title: "Hello", ContentPreview: "Hi...", Content: "Hi there, full text", Published: true},
+			},
+		},
+	}
+	h := NewSearchHandler(mock, logger.New("info"))
+
+	r := gin.New()
+	r.GET("/search", func(c *gin.Context) {
+		c.Set("userID", "requesting-user")
+		h.Search(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=hello&include=content", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d body %s", rec.Code, rec.Body.String())
+	}
+	if !mock.includeContent {
+		t.Error("expected include=content to be forwarded to search-service")
+	}
+	if !strings.Contains(rec.Body.String(), `"content":"Hi there, full text"`) {
+		t.Errorf("expected full content in response, got %s", rec.Body.String())
+	}
+}
+
+func TestSearchHandler_Search_DefaultOmitsContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mock := &mockSearchClient{
+		resp: &searchv1.SearchResponse{
+			Posts: []*searchv1.SearchPostHit{{Id: "p1", Title: "Hello", ContentPreview: "Hi..."}},
+		},
+	}
+	h := NewSearchHandler(mock, logger.New("info"))
+
+	r := gin.New()
+	r.GET("/search", func(c *gin.Context) {
+		c.Set("userID", "requesting-user")
+		h.Search(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=hello", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d body %s", rec.Code, rec.Body.String())
+	}
+	if mock.includeContent {
+		t.Error("expected content to be excluded by default")
+	}
+	if strings.Contains(rec.Body.String(), `"content":`) {
+		t.Errorf("expected summary-only posts, got %s", rec.Body.String())
+	}
+}
+
+func TestSearchHandler_Search_InvalidInclude(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewSearchHandler(&mockSearchClient{}, logger.New("info"))
+
+	r := gin.New()
+	r.GET("/search", func(c *gin.Context) {
+		c.Set("userID", "requesting-user")
+		h.Search(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=hello&include=comments", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d body %s", rec.Code, rec.Body.String())
+	}
+}
@@ -1,28 +1,37 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	"api-gateway/internal/clients"
+	"api-gateway/internal/models"
 	"api-gateway/pkg/logger"
 	"api-gateway/pkg/utils"
 )
 
+// SessionRevoker is the subset of the auth client UserHandler needs to end a
+// deactivated user's sessions.
+type SessionRevoker interface {
+	LogoutAll(ctx context.Context, userID string) (int, error)
+}
+
 type UserHandler struct {
 	userClient *clients.UserClient
+	sessions   SessionRevoker
 	logger     *logger.Logger
 }
 
 const maxOffset = 5000
 
-func NewUserHandler(userClient *clients.UserClient, logger *logger.Logger) *UserHandler {
+func NewUserHandler(userClient *clients.UserClient, sessions SessionRevoker, logger *logger.Logger) *UserHandler {
 	return &UserHandler{
 		userClient: userClient,
+		sessions:   sessions,
 		logger:     logger,
 	}
 }
@@ -117,33 +126,195 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.userClient.DeleteUser(c.Request.Context(), id, userID.(string)); err != nil {
+	if err := h.userClient.DeleteUser(c.Request.Context(), id, userID.(string), ""); err != nil {
 		h.handleUserError(c, err, "DELETE_FAILED", "Failed to delete user")
 		return
 	}
 	utils.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
 }
 
-func (h *UserHandler) ListUsers(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
+// DeactivateUser hides the account until it is reactivated and ends its
+// sessions, so existing refresh tokens stop working. The role is forwarded so
+// admins can deactivate other accounts. Deactivation is idempotent, so a
+// caller can retry when ending the sessions fails.
+func (h *UserHandler) DeactivateUser(c *gin.Context) {
+	id := c.Param("id")
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 || limit > 100 {
-		limit = 20
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 || offset > maxOffset {
-		offset = 0
+	if err := h.userClient.DeactivateUser(c.Request.Context(), id, userID.(string), c.GetString("userRole")); err != nil {
+		h.handleUserError(c, err, "DEACTIVATE_FAILED", "Failed to deactivate user")
+		return
+	}
+	if _, err := h.sessions.LogoutAll(c.Request.Context(), id); err != nil {
+		h.logger.Error("Failed to end sessions of deactivated user " + id + ": " + err.Error())
+		respondClientError(c, asClientError(err), "SESSION_REVOKE_FAILED", "User deactivated, but ending its sessions failed")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "User deactivated successfully", nil)
+}
+
+// ReactivateUser restores a deactivated account. Deleted accounts cannot be
+// reactivated.
+func (h *UserHandler) ReactivateUser(c *gin.Context) {
+	id := c.Param("id")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	response, err := h.userClient.ReactivateUser(c.Request.Context(), id, userID.(string), c.GetString("userRole"))
+	if err != nil {
+		h.handleUserError(c, err, "REACTIVATE_FAILED", "Failed to reactivate user")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "User reactivated successfully", response)
+}
+
+// AdminDeleteUser deletes any user. The route is guarded by RequireRole, and
+// the validated role is forwarded so the user service can enforce it too.
+func (h *UserHandler) AdminDeleteUser(c *gin.Context) {
+	id := c.Param("id")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	if err := h.userClient.DeleteUser(c.Request.Context(), id, userID.(string), c.GetString("userRole")); err != nil {
+		h.handleUserError(c, err, "DELETE_FAILED", "Failed to delete user")
+		return
 	}
+	utils.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
+}
 
+func (h *UserHandler) ListUsers(c *gin.Context) {
 	if _, exists := c.Get("userID"); !exists {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
 		return
 	}
 
-	response, err := h.userClient.ListUsers(c.Request.Context(), limit, offset)
+	input, ok := listUsersInput(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.userClient.ListUsers(c.Request.Context(), input)
+	if err != nil {
+		h.handleUserError(c, err, "LIST_FAILED", "Failed to retrieve users")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", response)
+}
+
+type usersBatchRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// GetUsersBatch resolves author profiles for a page of content in one call.
+func (h *UserHandler) GetUsersBatch(c *gin.Context) {
+	var req usersBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "A JSON body with an ids array is required")
+		return
+	}
+
+	response, err := h.userClient.GetUsersBatch(c.Request.Context(), req.IDs)
+	if err != nil {
+		h.handleUserError(c, err, "BATCH_FAILED", "Failed to retrieve users")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", response)
+}
+
+// VerifyEmail lets an admin mark a user's email verified by hand.
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	id := c.Param("id")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	response, err := h.userClient.VerifyEmail(c.Request.Context(), id, userID.(string), c.GetString("userRole"))
+	if err != nil {
+		h.handleUserError(c, err, "VERIFY_EMAIL_FAILED", "Failed to verify email")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verified successfully", response)
+}
+
+// ChangeEmail starts moving the caller to a new address; it takes effect once
+// VerifyEmailChange confirms the token sent there.
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid change email request: " + err.Error())
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_EMAIL", "Invalid email address")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	response, err := h.userClient.ChangeEmail(c.Request.Context(), id, userID.(string), req.Email)
+	if err != nil {
+		h.handleUserError(c, err, "EMAIL_CHANGE_FAILED", "Failed to change email")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Verification sent to the new email", response)
+}
+
+func (h *UserHandler) VerifyEmailChange(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.VerifyEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid verify email change request: " + err.Error())
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format")
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+
+	response, err := h.userClient.VerifyEmailChange(c.Request.Context(), id, userID.(string), req.Token)
+	if err != nil {
+		h.handleUserError(c, err, "EMAIL_CHANGE_FAILED", "Failed to change email")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email changed successfully", response)
+}
+
+// AdminListUsers lists every user, including deactivated accounts.
+func (h *UserHandler) AdminListUsers(c *gin.Context) {
+	input, ok := listUsersInput(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.userClient.ListAllUsers(c.Request.Context(), input)
 	if err != nil {
 		h.handleUserError(c, err, "LIST_FAILED", "Failed to retrieve users")
 		return
@@ -152,6 +323,47 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", response)
 }
 
+// listUsersInput reads the paging, sort and created-date query parameters of
+// the user listings. Sort and order go to the user service as given; a
+// malformed date is answered here with 400.
+func listUsersInput(c *gin.Context) (clients.ListUsersInput, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 || offset > maxOffset {
+		offset = 0
+	}
+
+	input := clients.ListUsersInput{
+		Limit:  limit,
+		Offset: offset,
+		Sort:   c.Query("sort"),
+		Order:  c.Query("order"),
+	}
+	for _, bound := range []struct {
+		param string
+		dst   **time.Time
+	}{
+		{"created_after", &input.CreatedAfter},
+		{"created_before", &input.CreatedBefore},
+	} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", bound.param+" must be an RFC 3339 timestamp")
+			return input, false
+		}
+		*bound.dst = &parsed
+	}
+	return input, true
+}
+
 func (h *UserHandler) SearchUsers(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
@@ -193,6 +405,18 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User profile retrieved successfully", response)
 }
 
+func (h *UserHandler) GetUserProfileByUsername(c *gin.Context) {
+	username := c.Param("username")
+
+	response, err := h.userClient.GetUserByUsername(c.Request.Context(), username)
+	if err != nil {
+		h.handleUserError(c, err, "PROFILE_NOT_FOUND", "User profile not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User profile retrieved successfully", response)
+}
+
 func (h *UserHandler) GetStats(c *gin.Context) {
 	response, err := h.userClient.GetStats(c.Request.Context())
 	if err != nil {
@@ -279,31 +503,75 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Following retrieved successfully", response)
 }
 
+func (h *UserHandler) Block(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	blockedID := c.Param("id")
+	if blockedID == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "User ID is required")
+		return
+	}
+	if err := h.userClient.Block(c.Request.Context(), userID.(string), blockedID); err != nil {
+		h.handleUserError(c, err, "BLOCK_FAILED", "Failed to block user")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Blocked successfully", nil)
+}
+
+func (h *UserHandler) Unblock(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	blockedID := c.Param("id")
+	if blockedID == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "User ID is required")
+		return
+	}
+	if err := h.userClient.Unblock(c.Request.Context(), userID.(string), blockedID); err != nil {
+		h.handleUserError(c, err, "UNBLOCK_FAILED", "Failed to unblock user")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Unblocked successfully", nil)
+}
+
+// GetBlocked lists the users the caller blocks; a block list is private.
+func (h *UserHandler) GetBlocked(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	if c.Param("id") != userID.(string) {
+		utils.ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", "You can only view your own blocked users")
+		return
+	}
+	limitStr := c.DefaultQuery("limit", "20")
+	cursor := c.DefaultQuery("cursor", "")
+	limit, _ := strconv.Atoi(limitStr)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	response, err := h.userClient.ListBlocked(c.Request.Context(), userID.(string), limit, cursor)
+	if err != nil {
+		h.handleUserError(c, err, "BLOCKED_FAILED", "Failed to retrieve blocked users")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Blocked users retrieved successfully", response)
+}
+
 func (h *UserHandler) handleUserError(c *gin.Context, err error, code, message string) {
 	if err == nil {
 		return
 	}
 
-	if st, ok := status.FromError(err); ok {
-		switch st.Code() {
-		case codes.NotFound:
-			utils.ErrorResponse(c, http.StatusNotFound, code, message)
-			return
-		case codes.AlreadyExists:
-			utils.ErrorResponse(c, http.StatusConflict, code, message)
-			return
-		case codes.InvalidArgument:
-			utils.ErrorResponse(c, http.StatusBadRequest, code, message)
-			return
-		case codes.PermissionDenied:
-			utils.ErrorResponse(c, http.StatusForbidden, code, message)
-			return
-		case codes.Unauthenticated:
-			utils.ErrorResponse(c, http.StatusUnauthorized, code, message)
-			return
-		}
+	clientErr := asClientError(err)
+	if clientErr.Status >= http.StatusInternalServerError {
+		h.logger.Error("User service operation failed: " + err.Error())
 	}
-
-	h.logger.Error("User service operation failed: " + err.Error())
-	utils.ErrorResponse(c, http.StatusInternalServerError, code, message)
+	respondClientError(c, clientErr, code, message)
 }
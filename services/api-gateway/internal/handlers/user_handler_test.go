@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api-gateway/internal/clients"
+	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	userv1 "github.com/nikitashilov/microblog_grpc/proto/user/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// deactivateUserServer answers DeactivateUser with err.
+type deactivateUserServer struct {
+	userv1.UnimplementedUserServiceServer
+	err         error
+	deactivated []string
+}
+
+func (s *deactivateUserServer) DeactivateUser(ctx context.Context, req *userv1.DeactivateUserRequest) (*emptypb.Empty, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.deactivated = append(s.deactivated, req.GetId())
+	return &emptypb.Empty{}, nil
+}
+
+type mockSessionRevoker struct {
+	err     error
+	revoked []string
+}
+
+func (m *mockSessionRevoker) LogoutAll(ctx context.Context, userID string) (int, error) {
+	m.revoked = append(m.revoked, userID)
+	return 2, m.err
+}
+
+func newTestUserClient(t *testing.T, server userv1.UserServiceServer) *clients.UserClient {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	userv1.RegisterUserServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	client, err := clients.NewUserClient(listener.Addr().String(), config.GRPCTLSConfig{}, config.GRPCRetryConfig{}, logger.New("info"))
+	if err != nil {
+		t.Fatalf("NewUserClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func deactivate(handler *UserHandler, id string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users/:id/deactivate", authenticated(handler.DeactivateUser))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users/"+id+"/deactivate", nil))
+	return rec
+}
+
+func TestDeactivateUser_EndsSessions(t *testing.T) {
+	users := &deactivateUserServer{}
+	sessions := &mockSessionRevoker{}
+	handler := NewUserHandler(newTestUserClient(t, users), sessions, logger.New("info"))
+
+	if rec := deactivate(handler, "user1"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(users.deactivated) != 1 || len(sessions.revoked) != 1 || sessions.revoked[0] != "user1" {
+		t.Fatalf("expected user1 deactivated and signed out, got %v and %v", users.deactivated, sessions.revoked)
+	}
+}
+
+func TestDeactivateUser_ReportsFailedSessionRevocation(t *testing.T) {
+	sessions := &mockSessionRevoker{err: errors.New("auth service unreachable")}
+	handler := NewUserHandler(newTestUserClient(t, &deactivateUserServer{}), sessions, logger.New("info"))
+
+	rec := deactivate(handler, "user1")
+	if rec.Code < http.StatusInternalServerError {
+		t.Fatalf("expected a server error when sessions stay open, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDeactivateUser_KeepsSessionsWhenDeactivationFails(t *testing.T) {
+	users := &deactivateUserServer{err: status.Error(codes.PermissionDenied, "not allowed")}
+	sessions := &mockSessionRevoker{}
+	handler := NewUserHandler(newTestUserClient(t, users), sessions, logger.New("info"))
+
+	if rec := deactivate(handler, "user2"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(sessions.revoked) != 0 {
+		t.Fatalf("expected no sessions ended, got %v", sessions.revoked)
+	}
+}
@@ -11,8 +11,21 @@ import (
 	"api-gateway/pkg/utils"
 )
 
+// UserRoleHeader carries the caller's role from validated token claims. Any
+// client-supplied value is dropped before authentication runs.
+const UserRoleHeader = "X-User-Role"
+
+// Roles carried in token claims. RoleUser is assumed for tokens issued
+// before roles were added to claims.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 func AuthMiddleware(authClient *clients.AuthClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		c.Request.Header.Del(UserRoleHeader)
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			utils.ErrorResponse(c, http.StatusUnauthorized, "MISSING_TOKEN", "Authorization header required")
@@ -48,6 +61,7 @@ func AuthMiddleware(authClient *clients.AuthClient) gin.HandlerFunc {
 		// Set user information in context
 		c.Set("userID", resp.GetUserId())
 		c.Set("userEmail", resp.GetEmail())
+		setUserRole(c, resp.GetRole())
 		c.Set("token", tokenString)
 		c.Next()
 	}
@@ -55,6 +69,8 @@ func AuthMiddleware(authClient *clients.AuthClient) gin.HandlerFunc {
 
 func OptionalAuthMiddleware(authClient *clients.AuthClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		c.Request.Header.Del(UserRoleHeader)
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.Next()
@@ -72,9 +88,38 @@ func OptionalAuthMiddleware(authClient *clients.AuthClient) gin.HandlerFunc {
 		if err == nil && resp.GetValid() {
 			c.Set("userID", resp.GetUserId())
 			c.Set("userEmail", resp.GetEmail())
+			setUserRole(c, resp.GetRole())
 			c.Set("token", tokenString)
 		}
 
 		c.Next()
 	}
 }
+
+// RequireRole rejects requests whose validated role is not role. It must run
+// after AuthMiddleware.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("userID"); !exists {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+			c.Abort()
+			return
+		}
+
+		if c.GetString("userRole") != role {
+			utils.ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", "Insufficient permissions")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func setUserRole(c *gin.Context, role string) {
+	if role == "" {
+		role = RoleUser
+	}
+	c.Set("userRole", role)
+	c.Request.Header.Set(UserRoleHeader, role)
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newRequireRoleRouter(identity func(c *gin.Context)) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(identity, RequireRole(RoleAdmin))
+	router.GET("/admin", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestRequireRoleAllowsMatchingRole(t *testing.T) {
+	router := newRequireRoleRouter(func(c *gin.Context) {
+		c.Set("userID", "admin-1")
+		setUserRole(c, RoleAdmin)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestRequireRoleRejectsOtherRoles(t *testing.T) {
+	router := newRequireRoleRouter(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		setUserRole(c, "")
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestRequireRoleIgnoresClientRoleHeader(t *testing.T) {
+	// Only the role set from validated claims counts; a spoofed header does not.
+	router := newRequireRoleRouter(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set(UserRoleHeader, RoleAdmin)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestRequireRoleRequiresAuthentication(t *testing.T) {
+	router := newRequireRoleRouter(func(c *gin.Context) {})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
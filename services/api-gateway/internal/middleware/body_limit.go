@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/pkg/utils"
+)
+
+// RouteBodyLimit overrides the body limit for routes under Prefix.
+type RouteBodyLimit struct {
+	Prefix   string
+	MaxBytes int64
+}
+
+// BodyLimit caps request bodies at maxBytes, or at the limit of the first
+// override whose prefix matches the route. A declared Content-Length over the
+// limit is rejected with 413 before anything is read; otherwise the body is
+// wrapped in http.MaxBytesReader, so RequestValidator and ShouldBindJSON stop
+// reading at the limit. It must run before any middleware that reads the body.
+func BodyLimit(maxBytes int64, overrides ...RouteBodyLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		limit := maxBytes
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		for _, override := range overrides {
+			if strings.HasPrefix(route, override.Prefix) {
+				limit = override.MaxBytes
+				break
+			}
+		}
+
+		if c.Request.ContentLength > limit {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE", "Request body is too large")
+			c.Abort()
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
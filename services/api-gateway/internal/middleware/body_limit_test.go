@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBodyLimitRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodyLimit(64, RouteBodyLimit{Prefix: "/api/v1/auth/", MaxBytes: 16}), RequestValidator(64))
+	handler := func(c *gin.Context) {
+		var body map[string]string
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+	router.POST("/api/v1/posts", handler)
+	router.POST("/api/v1/auth/login", handler)
+	return router
+}
+
+func TestBodyLimitRejectsDeclaredOversizeBody(t *testing.T) {
+	router := newBodyLimitRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/posts", strings.NewReader(`{"content":"`+strings.Repeat("x", 100)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", rec.Code)
+	}
+}
+
+func TestBodyLimitStopsUndeclaredOversizeBody(t *testing.T) {
+	router := newBodyLimitRouter()
+
+	// No Content-Length, as with a chunked upload.
+	body := io.MultiReader(strings.NewReader(`{"content":"`), strings.NewReader(strings.Repeat("x", 100)+`"}`))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/posts", body)
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", rec.Code)
+	}
+}
+
+func TestBodyLimitAppliesTighterAuthLimit(t *testing.T) {
+	router := newBodyLimitRouter()
+	payload := `{"email":"someone@example.com"}`
+
+	for path, want := range map[string]int{
+		"/api/v1/posts":      http.StatusNoContent,
+		"/api/v1/auth/login": http.StatusRequestEntityTooLarge,
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != want {
+			t.Fatalf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+}
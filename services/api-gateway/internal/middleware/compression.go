@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompressionOptions configures Compression.
+type CompressionOptions struct {
+	// MinSizeBytes is the smallest body worth compressing; shorter responses
+	// are sent as is.
+	MinSizeBytes int
+	// SkipPrefixes lists route prefixes that are never compressed, such as
+	// streams that must be flushed event by event.
+	SkipPrefixes []string
+}
+
+// incompressibleTypes are media types that are already compressed or are
+// streamed; compressing them again only costs CPU.
+var incompressibleTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/octet-stream",
+	"application/pdf",
+	"text/event-stream",
+}
+
+var (
+	gzipWriters  = sync.Pool{New: func() interface{} { return gzip.NewWriter(io.Discard) }}
+	flateWriters = sync.Pool{New: func() interface{} {
+		w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression)
+		return w
+	}}
+)
+
+// Compression gzip- or deflate-encodes responses for clients that accept it.
+// The body is buffered until it reaches opts.MinSizeBytes, so small responses
+// go out unchanged; responses that already carry a Content-Encoding or have an
+// incompressible Content-Type are passed through. Vary: Accept-Encoding is set
+// on every response the middleware handles so caches keep the variants apart.
+func Compression(opts CompressionOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || skipCompression(c, opts.SkipPrefixes) {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: opts.MinSizeBytes}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+func skipCompression(c *gin.Context, prefixes []string) bool {
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(route, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding picks gzip, then deflate, from an Accept-Encoding header,
+// honouring q=0 exclusions and the * wildcard. It returns "" when neither is
+// acceptable.
+func negotiateEncoding(header string) string {
+	accepted := map[string]bool{}
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		ok := true
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				ok = false
+			}
+		}
+		if name == "*" {
+			wildcard = ok
+			continue
+		}
+		accepted[name] = ok
+	}
+
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if ok, listed := accepted[encoding]; (listed && ok) || (!listed && wildcard) {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter holds the body back until it is large enough to compress,
+// then decides once between compressing and passing through.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	buf        bytes.Buffer
+	decided    bool
+	compressor interface {
+		io.WriteCloser
+		Flush() error
+	}
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		return w.writeBody(data)
+	}
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports buffered bytes as written so later middleware does not try
+// to write a second response.
+func (w *compressWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends what is buffered so far; a handler flushing is streaming, so the
+// size threshold no longer applies.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(w.buf.Len() > 0); err != nil {
+			return
+		}
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide commits to compressing (when compress is true and the response is
+// eligible) or passing through, then writes out the buffered bytes.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.Header()
+	if compress && w.compressible(header) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		switch w.encoding {
+		case "gzip":
+			gz := gzipWriters.Get().(*gzip.Writer)
+			gz.Reset(w.ResponseWriter)
+			w.compressor = gz
+		default:
+			fl := flateWriters.Get().(*flate.Writer)
+			fl.Reset(w.ResponseWriter)
+			w.compressor = fl
+		}
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.writeBody(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) compressible(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, skip := range incompressibleTypes {
+		if strings.HasPrefix(contentType, skip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *compressWriter) writeBody(data []byte) (int, error) {
+	if w.compressor != nil {
+		return w.compressor.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// finish writes a body that stayed under the threshold as is, or closes the
+// compressor and returns it to its pool.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+		return
+	}
+	if w.compressor == nil {
+		return
+	}
+	_ = w.compressor.Close()
+	switch compressor := w.compressor.(type) {
+	case *gzip.Writer:
+		gzipWriters.Put(compressor)
+	case *flate.Writer:
+		flateWriters.Put(compressor)
+	}
+	w.compressor = nil
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var largeBody = `{"posts":"` + strings.Repeat("lorem ipsum ", 200) + `"}`
+
+func newCompressionRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Compression(CompressionOptions{MinSizeBytes: 512, SkipPrefixes: []string{"/api/v1/notifications/stream"}}))
+	router.GET("/api/v1/posts", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(largeBody))
+	})
+	router.GET("/api/v1/posts/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/api/v1/avatar", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(largeBody))
+	})
+	router.GET("/api/v1/notifications/stream", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/event-stream", []byte(largeBody))
+	})
+	return router
+}
+
+func serveCompression(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompressionGzipsLargeResponses(t *testing.T) {
+	rec := serveCompression(newCompressionRouter(), "/api/v1/posts", "br;q=1.0, gzip;q=0.8")
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected Vary: Accept-Encoding, got %q", rec.Header().Get("Vary"))
+	}
+	if rec.Body.Len() >= len(largeBody) {
+		t.Fatalf("expected a smaller body, got %d bytes", rec.Body.Len())
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	body, _ := io.ReadAll(reader)
+	if string(body) != largeBody {
+		t.Fatal("expected the decompressed body to match")
+	}
+}
+
+func TestCompressionFallsBackToDeflate(t *testing.T) {
+	rec := serveCompression(newCompressionRouter(), "/api/v1/posts", "gzip;q=0, deflate")
+
+	if rec.Header().Get("Content-Encoding") != "deflate" {
+		t.Fatalf("expected deflate encoding, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	body, _ := io.ReadAll(flate.NewReader(rec.Body))
+	if string(body) != largeBody {
+		t.Fatal("expected the inflated body to match")
+	}
+}
+
+func TestCompressionLeavesResponsesUnencoded(t *testing.T) {
+	router := newCompressionRouter()
+
+	cases := []struct {
+		name, path, acceptEncoding, body string
+	}{
+		{"no Accept-Encoding", "/api/v1/posts", "", largeBody},
+		{"below threshold", "/api/v1/posts/small", "gzip", `{"ok":true}`},
+		{"compressed media type", "/api/v1/avatar", "gzip", largeBody},
+		{"stream route", "/api/v1/notifications/stream", "gzip", largeBody},
+	}
+	for _, tc := range cases {
+		rec := serveCompression(router, tc.path, tc.acceptEncoding)
+		if rec.Header().Get("Content-Encoding") != "" {
+			t.Fatalf("%s: expected no Content-Encoding, got %q", tc.name, rec.Header().Get("Content-Encoding"))
+		}
+		if rec.Body.String() != tc.body {
+			t.Fatalf("%s: expected the body unchanged", tc.name)
+		}
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"gzip, deflate":         "gzip",
+		"deflate":               "deflate",
+		"*":                     "gzip",
+		"*, gzip;q=0":           "deflate",
+		"identity":              "",
+		"gzip;q=0, deflate;q=0": "",
+	}
+	for header, want := range cases {
+		if got := negotiateEncoding(header); got != want {
+			t.Fatalf("Accept-Encoding %q: expected %q, got %q", header, want, got)
+		}
+	}
+}
@@ -1,6 +1,14 @@
 package middleware
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/config"
+)
 
 func TestResolveAllowedOrigin(t *testing.T) {
 	tests := []struct {
@@ -62,3 +70,66 @@ func TestResolveAllowedOrigin(t *testing.T) {
 		})
 	}
 }
+
+func serveCORS(cfg config.CORSConfig, method, origin string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CORS(cfg))
+	router.GET("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(method, "/resource", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	cfg := config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}
+
+	rec := serveCORS(cfg, http.MethodGet, "https://app.example.com")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected the request origin to be echoed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Fatalf("expected credentials to be allowed, got %q", got)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	cfg := config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}
+
+	rec := serveCORS(cfg, http.MethodGet, "https://evil.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no Access-Control-Allow-Origin, got %q", got)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	cfg := config.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+	}
+
+	rec := serveCORS(cfg, http.MethodOptions, "https://app.example.com")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected the request origin to be echoed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+		t.Fatalf("expected configured methods, got %q", got)
+	}
+
+	rec = serveCORS(cfg, http.MethodOptions, "https://evil.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected disallowed preflight to get no Access-Control-Allow-Origin, got %q", got)
+	}
+}
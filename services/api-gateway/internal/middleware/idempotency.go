@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+
+	"api-gateway/internal/clients"
+	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/utils"
+)
+
+const (
+	// IdempotencyKeyHeader is the client-chosen key that marks retries of the
+	// same POST request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses served from the cache.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyPrefix       = "idem"
+	maxIdempotencyKeyLen    = 255
+	idempotencyLockTTL      = 30 * time.Second
+	idempotencyRedisTimeout = 3 * time.Second
+)
+
+type idempotencyStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) error
+}
+
+// idempotencyRecord is stored under idem:<user>:<key>. A record without
+// Completed marks a request that is still being processed.
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotency replays the first successful response to an authenticated POST
+// carrying an Idempotency-Key header, so a double-submit does not create a
+// second resource. Keys are scoped per user and kept for cfg.TTLSeconds.
+//
+//   - A retry with the same key and the same method, path and body gets the
+//     cached status and body back with Idempotent-Replayed: true.
+//   - Reusing a key with a different request is rejected with 422.
+//   - A retry while the first request is still running is rejected with 409.
+//   - Non-2xx responses are not cached, so the client may retry with the key.
+//
+// Requests without the header, non-POST requests and anonymous requests pass
+// through. If Redis is unavailable the request is forwarded without
+// idempotency protection. It must run after AuthMiddleware.
+func Idempotency(redisClient *clients.RedisClient, cfg config.IdempotencyConfig, logger *logger.Logger) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return idempotency(redisClient, time.Duration(cfg.TTLSeconds)*time.Second, logger)
+}
+
+func idempotency(store idempotencyStore, ttl time.Duration, logger *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		userID := c.GetString("userID")
+		if c.Request.Method != http.MethodPost || key == "" || userID == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY", "Idempotency-Key must be 1-255 printable ASCII characters")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST_BODY", "Invalid request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		storeKey := fmt.Sprintf("%s:%s:%s", idempotencyPrefix, userID, key)
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		ctx, cancel := context.WithTimeout(c.Request.Context(), idempotencyRedisTimeout)
+		acquired, err := store.SetNX(ctx, storeKey, pending, idempotencyLockTTL)
+		if err != nil {
+			cancel()
+			logger.Warn(fmt.Sprintf("idempotency store unavailable, forwarding without replay protection: %v", err))
+			c.Next()
+			return
+		}
+		if !acquired {
+			raw, err := store.Get(ctx, storeKey)
+			cancel()
+			if err != nil {
+				if !errors.Is(err, redis.Nil) {
+					logger.Warn(fmt.Sprintf("idempotency store unavailable, forwarding without replay protection: %v", err))
+				}
+				// The record expired between SetNX and Get; treat it as new.
+				c.Next()
+				return
+			}
+			replayIdempotent(c, raw, fingerprint)
+			return
+		}
+		cancel()
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		saved := false
+		defer func() {
+			if !saved {
+				releaseIdempotencyKey(store, storeKey, logger)
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return
+		}
+
+		record, err := json.Marshal(idempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			return
+		}
+
+		// Detached from the request context: the response has already been sent.
+		saveCtx, saveCancel := context.WithTimeout(context.Background(), idempotencyRedisTimeout)
+		defer saveCancel()
+		if err := store.Set(saveCtx, storeKey, record, ttl); err != nil {
+			logger.Warn(fmt.Sprintf("failed to store idempotent response for key %s: %v", storeKey, err))
+			return
+		}
+		saved = true
+	}
+}
+
+func replayIdempotent(c *gin.Context, raw, fingerprint string) {
+	var record idempotencyRecord
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		utils.ErrorResponse(c, http.StatusConflict, "IDEMPOTENCY_KEY_CONFLICT", "Idempotency-Key is in an inconsistent state; use a new key")
+		c.Abort()
+		return
+	}
+	if record.Fingerprint != fingerprint {
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used for a different request")
+		c.Abort()
+		return
+	}
+	if !record.Completed {
+		utils.ErrorResponse(c, http.StatusConflict, "IDEMPOTENCY_REQUEST_IN_PROGRESS", "A request with this Idempotency-Key is still being processed")
+		c.Abort()
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.Status, record.ContentType, record.Body)
+	c.Abort()
+}
+
+func releaseIdempotencyKey(store idempotencyStore, storeKey string, logger *logger.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), idempotencyRedisTimeout)
+	defer cancel()
+	if err := store.Del(ctx, storeKey); err != nil {
+		logger.Warn(fmt.Sprintf("failed to release idempotency key %s: %v", storeKey, err))
+	}
+}
+
+func requestFingerprint(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(uri))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotencyRecorder copies the response body so it can be cached.
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
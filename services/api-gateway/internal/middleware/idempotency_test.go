@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+
+	"api-gateway/pkg/logger"
+)
+
+type memoryIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]string
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{keys: map[string]string{}}
+}
+
+func (m *memoryIdempotencyStore) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.keys[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (m *memoryIdempotencyStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys[key] = string(value.([]byte))
+	return nil
+}
+
+func (m *memoryIdempotencyStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.keys[key]; ok {
+		return false, nil
+	}
+	m.keys[key] = string(value.([]byte))
+	return true, nil
+}
+
+func (m *memoryIdempotencyStore) Del(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.keys, key)
+	}
+	return nil
+}
+
+func newIdempotencyRouter(store idempotencyStore, userID string, status *int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set("userID", userID)
+		}
+	}, idempotency(store, time.Minute, logger.New("info")))
+	router.POST("/posts", func(c *gin.Context) {
+		*calls++
+		c.JSON(*status, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func postWithKey(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysSuccessfulResponse(t *testing.T) {
+	status, calls := http.StatusCreated, 0
+	router := newIdempotencyRouter(newMemoryIdempotencyStore(), "user-1", &status, &calls)
+
+	first := postWithKey(router, "key-1", `{"title":"a"}`)
+	second := postWithKey(router, "key-1", `{"title":"a"}`)
+
+	if calls != 1 {
+		t.Fatalf("expected the handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated {
+		t.Fatalf("expected replayed 201, got %d", second.Code)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Fatalf("expected replayed body %q, got %q", first.Body.String(), second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatal("expected the replay to be marked")
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatal("expected the first response not to be marked as a replay")
+	}
+}
+
+func TestIdempotencyRejectsKeyReuseWithDifferentBody(t *testing.T) {
+	status, calls := http.StatusCreated, 0
+	router := newIdempotencyRouter(newMemoryIdempotencyStore(), "user-1", &status, &calls)
+
+	postWithKey(router, "key-1", `{"title":"a"}`)
+	rec := postWithKey(router, "key-1", `{"title":"b"}`)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rec.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the handler to run once, ran %d times", calls)
+	}
+}
+
+func TestIdempotencyDoesNotCacheFailures(t *testing.T) {
+	status, calls := http.StatusServiceUnavailable, 0
+	router := newIdempotencyRouter(newMemoryIdempotencyStore(), "user-1", &status, &calls)
+
+	postWithKey(router, "key-1", `{}`)
+	status = http.StatusCreated
+	rec := postWithKey(router, "key-1", `{}`)
+
+	if rec.Code != http.StatusCreated || calls != 2 {
+		t.Fatalf("expected the retry to be forwarded, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotencyScopesKeysPerUser(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	status, calls := http.StatusCreated, 0
+
+	postWithKey(newIdempotencyRouter(store, "user-1", &status, &calls), "key-1", `{}`)
+	rec := postWithKey(newIdempotencyRouter(store, "user-2", &status, &calls), "key-1", `{}`)
+
+	if rec.Header().Get(IdempotentReplayedHeader) != "" || calls != 2 {
+		t.Fatalf("expected another user's key not to be replayed, got %d calls", calls)
+	}
+}
+
+func TestIdempotencyRejectsConcurrentRetry(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	status, calls := http.StatusCreated, 0
+	router := newIdempotencyRouter(store, "user-1", &status, &calls)
+
+	// Simulate a first request that is still running.
+	pending := `{"fingerprint":"` + requestFingerprint(http.MethodPost, "/posts", []byte(`{}`)) + `","completed":false}`
+	store.keys["idem:user-1:key-1"] = pending
+
+	rec := postWithKey(router, "key-1", `{}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+	if calls != 0 {
+		t.Fatalf("expected the handler not to run, ran %d times", calls)
+	}
+}
+
+func TestIdempotencyPassesThroughWithoutKeyOrUser(t *testing.T) {
+	status, calls := http.StatusCreated, 0
+
+	router := newIdempotencyRouter(newMemoryIdempotencyStore(), "user-1", &status, &calls)
+	postWithKey(router, "", `{}`)
+	postWithKey(router, "", `{}`)
+
+	anonymous := newIdempotencyRouter(newMemoryIdempotencyStore(), "", &status, &calls)
+	postWithKey(anonymous, "key-1", `{}`)
+	postWithKey(anonymous, "key-1", `{}`)
+
+	if calls != 4 {
+		t.Fatalf("expected every request to be forwarded, got %d calls", calls)
+	}
+}
+
+func TestIdempotencyRejectsInvalidKey(t *testing.T) {
+	status, calls := http.StatusCreated, 0
+	router := newIdempotencyRouter(newMemoryIdempotencyStore(), "user-1", &status, &calls)
+
+	rec := postWithKey(router, strings.Repeat("k", maxIdempotencyKeyLen+1), `{}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
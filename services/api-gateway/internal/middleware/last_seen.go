@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/clients"
+	"api-gateway/pkg/logger"
+)
+
+const (
+	// lastSeenInterval is the minimum gap between last-seen writes per user.
+	lastSeenInterval = 5 * time.Minute
+	lastSeenTimeout  = 3 * time.Second
+	lastSeenPrefix   = "last_seen"
+)
+
+type lastSeenThrottle interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+}
+
+type lastSeenRecorder interface {
+	TouchLastSeen(ctx context.Context, id string) error
+}
+
+// LastSeen records the authenticated user's activity in the background. A
+// short Redis key limits writes to one per user per lastSeenInterval, and
+// failures are logged without affecting the response. It must run after
+// AuthMiddleware.
+func LastSeen(redisClient *clients.RedisClient, userClient *clients.UserClient, logger *logger.Logger) gin.HandlerFunc {
+	return lastSeen(redisClient, userClient, logger)
+}
+
+func lastSeen(throttle lastSeenThrottle, recorder lastSeenRecorder, logger *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID != "" {
+			go touchLastSeen(throttle, recorder, logger, userID)
+		}
+		c.Next()
+	}
+}
+
+func touchLastSeen(throttle lastSeenThrottle, recorder lastSeenRecorder, logger *logger.Logger, userID string) {
+	// Detached from the request context: the response may finish first.
+	ctx, cancel := context.WithTimeout(context.Background(), lastSeenTimeout)
+	defer cancel()
+
+	acquired, err := throttle.SetNX(ctx, fmt.Sprintf("%s:%s", lastSeenPrefix, userID), 1, lastSeenInterval)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("last seen throttle unavailable for user %s: %v", userID, err))
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	if err := recorder.TouchLastSeen(ctx, userID); err != nil {
+		logger.Warn(fmt.Sprintf("failed to update last seen for user %s: %v", userID, err))
+	}
+}
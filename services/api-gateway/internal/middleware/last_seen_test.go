@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/pkg/logger"
+)
+
+type memoryThrottle struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (m *memoryThrottle) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.keys[key] {
+		return false, nil
+	}
+	m.keys[key] = true
+	return true, nil
+}
+
+type chanRecorder chan string
+
+func (r chanRecorder) TouchLastSeen(ctx context.Context, id string) error {
+	r <- id
+	return nil
+}
+
+func newLastSeenRouter(throttle lastSeenThrottle, recorder lastSeenRecorder, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set("userID", userID)
+		}
+	}, lastSeen(throttle, recorder, logger.New("info")))
+	router.GET("/me", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestLastSeenThrottlesPerUser(t *testing.T) {
+	recorder := make(chanRecorder, 4)
+	router := newLastSeenRouter(&memoryThrottle{keys: map[string]bool{}}, recorder, "user-1")
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me", nil))
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+		}
+	}
+
+	select {
+	case id := <-recorder:
+		if id != "user-1" {
+			t.Errorf("expected user-1 recorded, got %q", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected last seen to be recorded")
+	}
+	select {
+	case id := <-recorder:
+		t.Errorf("expected a single write within the interval, got another for %q", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestLastSeenSkipsAnonymousRequests(t *testing.T) {
+	recorder := make(chanRecorder, 1)
+	router := newLastSeenRouter(&memoryThrottle{keys: map[string]bool{}}, recorder, "")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me", nil))
+
+	select {
+	case id := <-recorder:
+		t.Errorf("expected no write for anonymous request, got %q", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
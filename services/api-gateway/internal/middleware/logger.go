@@ -1,26 +1,109 @@
 package middleware
 
 import (
-	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
 
-	"api-gateway/pkg/logger"
+	"api-gateway/internal/config"
+	logpkg "api-gateway/pkg/logger"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RequestLogger(logger *logger.Logger) gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		log := fmt.Sprintf("[%s] %s %s %d %s %s %s",
-			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
-			param.Method,
-			param.Path,
-			param.StatusCode,
-			param.Latency,
-			param.ClientIP,
-			param.ErrorMessage,
-		)
-
-		logger.Info(log)
-		return ""
+func RequestLogger(logger *logpkg.Logger, cfg config.AccessLogConfig) gin.HandlerFunc {
+	filter := newAccessLogFilter(cfg)
+
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(param gin.LogFormatterParams) string {
+			requestID, _ := param.Keys[ContextRequestIDKey].(string)
+			fields := []logpkg.Field{
+				logpkg.F("status", param.StatusCode),
+				logpkg.F("latency_ms", param.Latency.Milliseconds()),
+				logpkg.F("client_ip", param.ClientIP),
+				logpkg.F("request_id", requestID),
+			}
+			if userID, _ := param.Keys["userID"].(string); userID != "" {
+				fields = append(fields, logpkg.F("user_id", userID))
+			}
+			if param.ErrorMessage != "" {
+				fields = append(fields, logpkg.F("error", param.ErrorMessage))
+			}
+
+			logger.Info(param.Method+" "+redactPath(param.Path), fields...)
+			return ""
+		},
+		Skip: filter.skip,
 	})
 }
+
+// secretPathPrefixes are routes whose next path segment is a bearer secret.
+// Anyone who reads it from the access log can use it.
+var secretPathPrefixes = []string{"/posts/preview/"}
+
+// redactPath replaces the secret segment of path, and anything after it, with
+// "[REDACTED]".
+func redactPath(path string) string {
+	for _, prefix := range secretPathPrefixes {
+		if i := strings.Index(path, prefix); i >= 0 {
+			return path[:i+len(prefix)] + "[REDACTED]"
+		}
+	}
+	return path
+}
+
+// accessLogFilter drops noisy read-only requests (health checks, metric
+// scrapes, polling endpoints) from the access log. Mutations and server errors
+// are always logged.
+type accessLogFilter struct {
+	skipPaths   map[string]struct{}
+	samplePaths map[string]struct{}
+	sampleRate  uint64
+	sampled     atomic.Uint64
+}
+
+func newAccessLogFilter(cfg config.AccessLogConfig) *accessLogFilter {
+	rate := cfg.SampleRate
+	if rate < 1 {
+		rate = 1
+	}
+
+	return &accessLogFilter{
+		skipPaths:   toPathSet(cfg.SkipPaths),
+		samplePaths: toPathSet(cfg.SamplePaths),
+		sampleRate:  uint64(rate),
+	}
+}
+
+func (f *accessLogFilter) skip(c *gin.Context) bool {
+	if !isReadOnlyMethod(c.Request.Method) || c.Writer.Status() >= http.StatusInternalServerError {
+		return false
+	}
+
+	path := c.Request.URL.Path
+	if _, ok := f.skipPaths[path]; ok {
+		return true
+	}
+	if _, ok := f.samplePaths[path]; ok {
+		return (f.sampled.Add(1)-1)%f.sampleRate != 0
+	}
+
+	return false
+}
+
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func toPathSet(paths []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		set[path] = struct{}{}
+	}
+	return set
+}
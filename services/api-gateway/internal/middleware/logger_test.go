@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+)
+
+func newLoggedRouter(t *testing.T, cfg config.AccessLogConfig) (*gin.Engine, *bytes.Buffer) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	appLogger := logger.New("info")
+	appLogger.SetOutput(&buf)
+
+	router := gin.New()
+	router.Use(RequestLogger(appLogger, cfg))
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/health", handler)
+	router.POST("/health", handler)
+	router.GET("/api/v1/poll", handler)
+	router.GET("/api/v1/posts", handler)
+	router.GET("/api/v1/public/posts/preview/:token", handler)
+	return router, &buf
+}
+
+func serve(router *gin.Engine, method, path string) {
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+}
+
+func TestRequestLoggerSkipsHealthChecks(t *testing.T) {
+	router, buf := newLoggedRouter(t, config.AccessLogConfig{SkipPaths: []string{"/health"}, SampleRate: 1})
+
+	serve(router, http.MethodGet, "/health")
+	if buf.Len() != 0 {
+		t.Fatalf("expected health check not to be logged, got %q", buf.String())
+	}
+
+	serve(router, http.MethodGet, "/api/v1/posts")
+	if !strings.Contains(buf.String(), "/api/v1/posts") {
+		t.Fatalf("expected regular request to be logged, got %q", buf.String())
+	}
+}
+
+func TestRequestLoggerAlwaysLogsMutations(t *testing.T) {
+	router, buf := newLoggedRouter(t, config.AccessLogConfig{SkipPaths: []string{"/health"}, SampleRate: 1})
+
+	serve(router, http.MethodPost, "/health")
+	if !strings.Contains(buf.String(), "POST /health") {
+		t.Fatalf("expected POST to be logged on a skipped path, got %q", buf.String())
+	}
+}
+
+func TestRequestLoggerSamplesConfiguredPaths(t *testing.T) {
+	router, buf := newLoggedRouter(t, config.AccessLogConfig{SamplePaths: []string{"/api/v1/poll"}, SampleRate: 3})
+
+	for i := 0; i < 6; i++ {
+		serve(router, http.MethodGet, "/api/v1/poll")
+	}
+	if got := strings.Count(buf.String(), "/api/v1/poll"); got != 2 {
+		t.Fatalf("expected 2 of 6 sampled requests to be logged, got %d", got)
+	}
+}
+
+func TestRequestLoggerRedactsPreviewTokens(t *testing.T) {
+	router, buf := newLoggedRouter(t, config.AccessLogConfig{SampleRate: 1})
+
+	serve(router, http.MethodGet, "/api/v1/public/posts/preview/s3cr3t-token?format=html")
+	if strings.Contains(buf.String(), "s3cr3t-token") {
+		t.Fatalf("expected the preview token to be redacted, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "/api/v1/public/posts/preview/[REDACTED]") {
+		t.Fatalf("expected the redacted path to be logged, got %q", buf.String())
+	}
+}
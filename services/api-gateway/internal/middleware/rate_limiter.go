@@ -2,10 +2,14 @@
 package middleware
 
 import (
+	"container/list"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,19 +17,48 @@ import (
 
 	"api-gateway/internal/clients"
 	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+	"api-gateway/pkg/metrics"
 	"api-gateway/pkg/utils"
 )
 
-// RateLimit is the general per-IP limiter applied to all routes. On a Redis
-// error it falls back to a per-IP in-memory limiter (not a single shared
-// bucket), so one client cannot consume everyone's allowance during a Redis
-// outage.
-func RateLimit(redisClient *clients.RedisClient, cfg config.RateLimitConfig) gin.HandlerFunc {
-	return rateLimit(redisClient, rateLimitOptions{
+// RateLimit is the general limiter. Authenticated requests are counted per
+// user ID (so users sharing a NAT do not starve each other), anonymous ones
+// per client IP; the two limits are configured independently. It must run
+// after AuthMiddleware/OptionalAuthMiddleware for the user ID to be visible.
+// On a Redis error it falls back to a per-key in-memory limiter (not a single
+// shared bucket), so one client cannot consume everyone's allowance during a
+// Redis outage, and goes back to Redis once it answers again. Routes under a
+// prefix in cfg.Routes get that limit instead, counted in their own buckets;
+// X-RateLimit-Limit reports whichever limit applied.
+func RateLimit(redisClient *clients.RedisClient, cfg config.RateLimitConfig, logger *logger.Logger) gin.HandlerFunc {
+	return rateLimit(redisClient, logger, rateLimitOptions{
+		name:               "general",
+		enabled:            cfg.Enabled,
+		requestsPerMin:     cfg.RequestsPerMinute,
+		burstSize:          cfg.BurstSize,
+		keyPrefix:          "rl:ip",
+		userRequestsPerMin: cfg.UserRequestsPerMinute,
+		userBurstSize:      cfg.UserBurstSize,
+		userKeyPrefix:      "rl:user",
+		routes:             cfg.Routes,
+		failClosed:         false,
+	})
+}
+
+// PreAuthRateLimit is a per-IP ceiling for routes that validate a bearer
+// token. It runs before AuthMiddleware/OptionalAuthMiddleware, so requests
+// with a missing or bad token are limited before each one costs a
+// ValidateToken call; RateLimit still applies its per-user limit after auth.
+// Its buckets are separate from RateLimit's per-IP ones, so anonymous
+// requests are not counted twice against the same limit.
+func PreAuthRateLimit(redisClient *clients.RedisClient, cfg config.RateLimitConfig, logger *logger.Logger) gin.HandlerFunc {
+	return rateLimit(redisClient, logger, rateLimitOptions{
+		name:           "preauth",
 		enabled:        cfg.Enabled,
-		requestsPerMin: cfg.RequestsPerMinute,
-		burstSize:      cfg.BurstSize,
-		keyPrefix:      "rate_limit",
+		requestsPerMin: cfg.PreAuthRequestsPerMinute,
+		burstSize:      cfg.PreAuthBurstSize,
+		keyPrefix:      "rl:preauth",
 		failClosed:     false,
 	})
 }
@@ -35,8 +68,9 @@ func RateLimit(redisClient *clients.RedisClient, cfg config.RateLimitConfig) gin
 // brute-force, credential stuffing, and auth_code/refresh-token guessing, and
 // fails closed: if Redis is unavailable the request is rejected rather than
 // allowed.
-func AuthRateLimit(redisClient *clients.RedisClient, cfg config.RateLimitConfig) gin.HandlerFunc {
-	return rateLimit(redisClient, rateLimitOptions{
+func AuthRateLimit(redisClient *clients.RedisClient, cfg config.RateLimitConfig, logger *logger.Logger) gin.HandlerFunc {
+	return rateLimit(redisClient, logger, rateLimitOptions{
+		name:           "auth",
 		enabled:        cfg.Enabled,
 		requestsPerMin: cfg.AuthRequestsPerMinute,
 		burstSize:      cfg.AuthRequestsPerMinute,
@@ -46,77 +80,166 @@ func AuthRateLimit(redisClient *clients.RedisClient, cfg config.RateLimitConfig)
 }
 
 type rateLimitOptions struct {
+	// name labels the limiter in logs and metrics.
+	name           string
 	enabled        bool
 	requestsPerMin int
 	burstSize      int
 	keyPrefix      string
+	// userKeyPrefix, when set, keys authenticated requests on the user ID
+	// with their own limit instead of on the client IP.
+	userRequestsPerMin int
+	userBurstSize      int
+	userKeyPrefix      string
+	// routes overrides the limit for matched routes under a prefix.
+	routes map[string]config.RouteRateLimit
 	// failClosed controls behaviour when Redis is unavailable: true rejects the
-	// request (used for auth endpoints); false falls back to a per-IP in-memory
+	// request (used for auth endpoints); false falls back to a per-key in-memory
 	// limiter (used for general traffic).
 	failClosed bool
+	// now is the clock the window is measured against; tests replace it.
+	now func() time.Time
 }
 
-func rateLimit(redisClient *clients.RedisClient, opts rateLimitOptions) gin.HandlerFunc {
+const (
+	// rateLimitWindow is the span every limit is counted over.
+	rateLimitWindow = time.Minute
+	// redisProbeInterval is how often a degraded limiter lets one request
+	// try Redis again; the rest skip it rather than each waiting on a dead
+	// connection.
+	redisProbeInterval = 5 * time.Second
+)
+
+func rateLimit(redisClient *clients.RedisClient, logger *logger.Logger, opts rateLimitOptions) gin.HandlerFunc {
 	if !opts.enabled {
 		return func(c *gin.Context) { c.Next() }
 	}
 
 	// Defensive clamp: a misconfigured limit/burst of 0 must not panic
 	// (rate.Every divides by it) or silently disable limiting.
-	if opts.requestsPerMin < 1 {
-		opts.requestsPerMin = 1
-	}
-	if opts.burstSize < 1 {
-		opts.burstSize = 1
+	opts.requestsPerMin, opts.burstSize = clampLimit(opts.requestsPerMin, opts.burstSize)
+	opts.userRequestsPerMin, opts.userBurstSize = clampLimit(opts.userRequestsPerMin, opts.userBurstSize)
+
+	if opts.now == nil {
+		opts.now = time.Now
 	}
 
-	fallback := newPerIPLimiters(opts.requestsPerMin, opts.burstSize)
+	ipFallback := newPerKeyLimiters(opts.requestsPerMin, opts.burstSize, maxFallbackEntries)
+	userFallback := newPerKeyLimiters(opts.userRequestsPerMin, opts.userBurstSize, maxFallbackEntries)
+	routes := make([]*routeLimit, 0, len(opts.routes))
+	for prefix, route := range opts.routes {
+		requestsPerMin, burstSize := clampLimit(route.RequestsPerMinute, route.BurstSize)
+		routes = append(routes, &routeLimit{
+			prefix:         prefix,
+			requestsPerMin: requestsPerMin,
+			fallback:       newPerKeyLimiters(requestsPerMin, burstSize, maxFallbackEntries),
+		})
+	}
+	redis := &redisHealth{limiter: opts.name, logger: logger}
 
 	return func(c *gin.Context) {
-		// Key on the client IP only. The previous User-Agent component was
-		// attacker-controlled, letting a single client mint an unlimited number
-		// of buckets and bypass the limit entirely. ClientIP is derived from the
-		// trusted-proxy configuration, so it cannot be spoofed via X-Forwarded-For.
-		clientIP := c.ClientIP()
-		key := fmt.Sprintf("%s:%s", opts.keyPrefix, clientIP)
-
-		allowed, err := checkRateLimit(redisClient, key, opts.requestsPerMin, c)
+		// Anonymous traffic is keyed on the client IP only. The previous
+		// User-Agent component was attacker-controlled, letting a single client
+		// mint an unlimited number of buckets and bypass the limit entirely.
+		// ClientIP is derived from the trusted-proxy configuration, so it cannot
+		// be spoofed via X-Forwarded-For.
+		key := fmt.Sprintf("%s:%s", opts.keyPrefix, c.ClientIP())
+		limit := opts.requestsPerMin
+		fallback := ipFallback
+		if userID := c.GetString("userID"); userID != "" && opts.userKeyPrefix != "" {
+			key = fmt.Sprintf("%s:%s", opts.userKeyPrefix, userID)
+			limit = opts.userRequestsPerMin
+			fallback = userFallback
+		}
+		if route := matchRouteLimit(c.FullPath(), routes); route != nil {
+			key = route.prefix + ":" + key
+			limit = route.requestsPerMin
+			fallback = route.fallback
+		}
+
+		now := opts.now()
+		var result clients.SlidingWindowResult
+		err := clients.ErrRedisUnavailable
+		if redis.shouldTry(now) {
+			result, err = checkRateLimit(c, redisClient, key, limit, now)
+			redis.observe(err, now)
+		}
 		if err != nil {
 			if opts.failClosed {
 				utils.ErrorResponse(c, http.StatusServiceUnavailable, "RATE_LIMIT_UNAVAILABLE", "Service temporarily unavailable, please retry")
 				c.Abort()
 				return
 			}
-			// General traffic: per-IP in-memory fallback so limiting survives a
-			// Redis outage without collapsing to one shared bucket.
-			if !fallback.allow(clientIP) {
-				rejectRateLimited(c, opts.requestsPerMin)
+			// General traffic: per-key in-memory fallback so limiting survives
+			// a Redis outage without collapsing to one shared bucket.
+			allowed := fallback.allow(key)
+			metrics.RateLimitFallbackDecision(opts.name, allowed)
+			if !allowed {
+				rejectRateLimited(c, limit)
 				return
 			}
+			c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
 			c.Next()
 			return
 		}
 
-		if !allowed {
-			ttl := getRateLimitTTL(redisClient, key, c)
-			c.Header("X-RateLimit-Limit", strconv.Itoa(opts.requestsPerMin))
-			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
-
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(result.ResetAt, now)))
 			utils.ErrorResponse(c, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "Rate limit exceeded. Try again later.")
 			c.Abort()
 			return
 		}
 
-		remaining := getRemainingRequests(redisClient, key, opts.requestsPerMin, c)
-		c.Header("X-RateLimit-Limit", strconv.Itoa(opts.requestsPerMin))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
-
 		c.Next()
 	}
 }
 
+// routeLimit is the limit for routes under prefix, with its own fallback
+// buckets.
+type routeLimit struct {
+	prefix         string
+	requestsPerMin int
+	fallback       *perKeyLimiters
+}
+
+// matchRouteLimit returns the limit with the longest prefix of the matched
+// route, or nil. Unmatched requests (an empty route) keep the global limit.
+func matchRouteLimit(route string, routes []*routeLimit) *routeLimit {
+	if route == "" {
+		return nil
+	}
+	var match *routeLimit
+	for _, candidate := range routes {
+		if strings.HasPrefix(route, candidate.prefix) && (match == nil || len(candidate.prefix) > len(match.prefix)) {
+			match = candidate
+		}
+	}
+	return match
+}
+
+func clampLimit(requestsPerMin, burstSize int) (int, int) {
+	if requestsPerMin < 1 {
+		requestsPerMin = 1
+	}
+	if burstSize < 1 {
+		burstSize = 1
+	}
+	return requestsPerMin, burstSize
+}
+
+// retryAfterSeconds rounds the wait until resetAt up to whole seconds, never
+// below one.
+func retryAfterSeconds(resetAt, now time.Time) int {
+	seconds := int((resetAt.Sub(now) + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
 func rejectRateLimited(c *gin.Context, limit int) {
 	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
 	c.Header("X-RateLimit-Remaining", "0")
@@ -124,86 +247,113 @@ func rejectRateLimited(c *gin.Context, limit int) {
 	c.Abort()
 }
 
-func checkRateLimit(redisClient *clients.RedisClient, key string, limit int, c *gin.Context) (bool, error) {
-	ctx := c.Request.Context()
+// checkRateLimit counts the request against a sliding window of
+// rateLimitWindow ending now, so the limit holds for any window-sized span
+// instead of resetting at fixed minute boundaries.
+func checkRateLimit(c *gin.Context, redisClient *clients.RedisClient, key string, limit int, now time.Time) (clients.SlidingWindowResult, error) {
+	return redisClient.SlidingWindowAllow(c.Request.Context(), key, limit, rateLimitWindow, now)
+}
 
-	// Increment the counter
-	count, err := redisClient.Incr(ctx, key)
-	if err != nil {
-		return false, err
-	}
+// redisHealth tracks whether a limiter can reach Redis. After a failure the
+// limiter is degraded: it logs and reports that once, then lets a single
+// request probe Redis every redisProbeInterval and returns to Redis-backed
+// limiting on the first probe that succeeds.
+type redisHealth struct {
+	limiter string
+	logger  *logger.Logger
 
-	// Set expiration on first request
-	if count == 1 {
-		if err = redisClient.Expire(ctx, key, time.Minute); err != nil {
-			return false, err
-		}
-	}
-
-	// Check if limit exceeded
-	return count <= int64(limit), nil
+	degraded  atomic.Bool
+	mu        sync.Mutex
+	nextProbe time.Time
 }
 
-func getRemainingRequests(redisClient *clients.RedisClient, key string, limit int, c *gin.Context) int {
-	ctx := c.Request.Context()
-
-	countStr, err := redisClient.Get(ctx, key)
-	if err != nil {
-		return limit
+// shouldTry reports whether this request should ask Redis.
+func (h *redisHealth) shouldTry(now time.Time) bool {
+	if !h.degraded.Load() {
+		return true
 	}
-
-	count, err := strconv.Atoi(countStr)
-	if err != nil {
-		return limit
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if now.Before(h.nextProbe) {
+		return false
 	}
+	h.nextProbe = now.Add(redisProbeInterval)
+	return true
+}
 
-	remaining := limit - count
-	if remaining < 0 {
-		return 0
+// observe records the outcome of a Redis check. Only ErrRedisUnavailable
+// degrades the limiter; a request whose own context ended says nothing about
+// Redis.
+func (h *redisHealth) observe(err error, now time.Time) {
+	if err == nil {
+		if h.degraded.CompareAndSwap(true, false) {
+			metrics.SetRateLimitDegraded(h.limiter, false)
+			h.logger.Info("rate limiter reconnected to redis", logger.F("limiter", h.limiter))
+		}
+		return
+	}
+	if !errors.Is(err, clients.ErrRedisUnavailable) {
+		return
 	}
-	return remaining
-}
 
-func getRateLimitTTL(redisClient *clients.RedisClient, key string, c *gin.Context) time.Duration {
-	// For now, return default
-	// You could implement Redis TTL command here if needed
-	return time.Minute
+	h.mu.Lock()
+	h.nextProbe = now.Add(redisProbeInterval)
+	h.mu.Unlock()
+	if h.degraded.CompareAndSwap(false, true) {
+		metrics.SetRateLimitDegraded(h.limiter, true)
+		h.logger.Warn("rate limiter cannot reach redis, limiting in memory until it recovers",
+			logger.F("limiter", h.limiter), logger.Err(err))
+	}
 }
 
-// perIPLimiters holds in-memory token-bucket limiters keyed by client IP. It is
-// used only as a fallback when Redis is unavailable, preserving per-client
-// limiting instead of degrading to a single global bucket.
-type perIPLimiters struct {
+// perKeyLimiters holds in-memory token-bucket limiters keyed like the Redis
+// counters (client IP or user ID). It is used only as a fallback when Redis is
+// unavailable, preserving per-client limiting instead of degrading to a single
+// global bucket. It keeps at most capacity limiters, evicting the least
+// recently used, so churning keys cannot grow it without bound and an active
+// client keeps its bucket.
+type perKeyLimiters struct {
 	mu       sync.Mutex
-	limiters map[string]*rate.Limiter
+	limiters map[string]*list.Element
+	recent   *list.List // of *keyedLimiter, most recently used first
+	capacity int
 	rps      rate.Limit
 	burst    int
 }
 
-// maxFallbackEntries bounds memory during a sustained Redis outage when client
-// IPs churn; on overflow the table is reset (everyone gets a fresh bucket).
+type keyedLimiter struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+// maxFallbackEntries bounds memory during a sustained Redis outage.
 const maxFallbackEntries = 10000
 
-func newPerIPLimiters(requestsPerMin, burst int) *perIPLimiters {
-	return &perIPLimiters{
-		limiters: make(map[string]*rate.Limiter),
+func newPerKeyLimiters(requestsPerMin, burst, capacity int) *perKeyLimiters {
+	return &perKeyLimiters{
+		limiters: make(map[string]*list.Element),
+		recent:   list.New(),
+		capacity: capacity,
 		rps:      rate.Every(time.Minute / time.Duration(requestsPerMin)),
 		burst:    burst,
 	}
 }
 
-func (p *perIPLimiters) allow(ip string) bool {
+func (p *perKeyLimiters) allow(key string) bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if len(p.limiters) > maxFallbackEntries {
-		p.limiters = make(map[string]*rate.Limiter)
+	if element, ok := p.limiters[key]; ok {
+		p.recent.MoveToFront(element)
+		return element.Value.(*keyedLimiter).limiter.Allow()
 	}
 
-	limiter, ok := p.limiters[ip]
-	if !ok {
-		limiter = rate.NewLimiter(p.rps, p.burst)
-		p.limiters[ip] = limiter
+	if p.recent.Len() >= p.capacity {
+		oldest := p.recent.Back()
+		p.recent.Remove(oldest)
+		delete(p.limiters, oldest.Value.(*keyedLimiter).key)
 	}
-	return limiter.Allow()
+	entry := &keyedLimiter{key: key, limiter: rate.NewLimiter(p.rps, p.burst)}
+	p.limiters[key] = p.recent.PushFront(entry)
+	return entry.limiter.Allow()
 }
@@ -0,0 +1,327 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/clients"
+	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+)
+
+// newFallbackRateLimitRouter points the limiter at an unreachable Redis so
+// every request goes through the in-memory fallback, which shares the
+// limiter's keys. The X-Test-User header stands in for AuthMiddleware.
+func newFallbackRateLimitRouter(cfg config.RateLimitConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	redisClient := clients.NewRedisClient(config.RedisConfig{URL: "127.0.0.1:1"})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("userID", userID)
+		}
+		c.Next()
+	})
+	router.Use(RateLimit(redisClient, cfg, logger.New("info")))
+	router.GET("/api/v1/posts", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func requestAs(router *gin.Engine, userID string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimitKeysAuthenticatedRequestsOnUserID(t *testing.T) {
+	router := newFallbackRateLimitRouter(config.RateLimitConfig{
+		Enabled:               true,
+		RequestsPerMinute:     1,
+		BurstSize:             1,
+		UserRequestsPerMinute: 1,
+		UserBurstSize:         2,
+	})
+
+	for i := 0; i < 2; i++ {
+		if code := requestAs(router, "user-a"); code != http.StatusOK {
+			t.Fatalf("request %d for user-a: expected 200, got %d", i+1, code)
+		}
+	}
+	if code := requestAs(router, "user-a"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected user-a to be limited after its burst, got %d", code)
+	}
+
+	// Same IP, different user: its own bucket.
+	if code := requestAs(router, "user-b"); code != http.StatusOK {
+		t.Fatalf("expected user-b behind the same IP to be allowed, got %d", code)
+	}
+	// Same IP, anonymous: the per-IP bucket is independent of the users'.
+	if code := requestAs(router, ""); code != http.StatusOK {
+		t.Fatalf("expected first anonymous request to be allowed, got %d", code)
+	}
+	if code := requestAs(router, ""); code != http.StatusTooManyRequests {
+		t.Fatalf("expected anonymous traffic to hit the per-IP limit, got %d", code)
+	}
+}
+
+func TestPreAuthRateLimitRunsBeforeTokenValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	redisClient := clients.NewRedisClient(config.RedisConfig{URL: "127.0.0.1:1"})
+	validations := 0
+
+	router := gin.New()
+	router.Use(PreAuthRateLimit(redisClient, config.RateLimitConfig{
+		Enabled:                  true,
+		PreAuthRequestsPerMinute: 1,
+		PreAuthBurstSize:         3,
+	}, logger.New("info")))
+	// Stands in for AuthMiddleware rejecting a bad token after a ValidateToken call.
+	router.Use(func(c *gin.Context) {
+		validations++
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	router.GET("/api/v1/posts", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 3; i++ {
+		if code := requestAs(router, ""); code != http.StatusUnauthorized {
+			t.Fatalf("request %d: expected the token check to run, got %d", i+1, code)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if code := requestAs(router, ""); code != http.StatusTooManyRequests {
+			t.Fatalf("expected the flood to be limited, got %d", code)
+		}
+	}
+	if validations != 3 {
+		t.Fatalf("expected 3 token validations, got %d", validations)
+	}
+}
+
+// newWindowRateLimitRouter runs the Redis sliding window against miniredis
+// with a clock the test controls.
+func newWindowRateLimitRouter(t *testing.T, limit int, clock *time.Time) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	redisClient := clients.NewRedisClient(config.RedisConfig{URL: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	router := gin.New()
+	router.Use(rateLimit(redisClient, logger.New("info"), rateLimitOptions{
+		enabled:        true,
+		requestsPerMin: limit,
+		burstSize:      limit,
+		keyPrefix:      "rl:ip",
+		now:            func() time.Time { return *clock },
+	}))
+	router.GET("/api/v1/posts", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func serveAt(router *gin.Engine) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSlidingWindowHoldsAcrossMinuteBoundary(t *testing.T) {
+	// Three requests in the last second of a minute. A fixed per-minute
+	// counter would admit three more a second later; the window must not.
+	clock := time.Date(2024, 1, 1, 12, 0, 59, 0, time.UTC)
+	router := newWindowRateLimitRouter(t, 3, &clock)
+
+	for i := 0; i < 3; i++ {
+		w := serveAt(router)
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, w.Code)
+		}
+		if got, want := w.Header().Get("X-RateLimit-Remaining"), strconv.Itoa(2-i); got != want {
+			t.Fatalf("request %d: expected remaining %s, got %s", i+1, want, got)
+		}
+	}
+
+	clock = clock.Add(time.Second) // 12:01:00, a fresh fixed window
+	w := serveAt(router)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the limit to carry across the minute boundary, got %d", w.Code)
+	}
+	wantReset := time.Date(2024, 1, 1, 12, 1, 59, 0, time.UTC).Unix()
+	if got := w.Header().Get("X-RateLimit-Reset"); got != strconv.FormatInt(wantReset, 10) {
+		t.Fatalf("expected reset at %d (oldest request + window), got %s", wantReset, got)
+	}
+	if got := w.Header().Get("Retry-After"); got != "59" {
+		t.Fatalf("expected Retry-After 59, got %s", got)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Fatalf("expected remaining 0, got %s", got)
+	}
+
+	// Once the window has slid past the first requests they free up again.
+	clock = time.Date(2024, 1, 1, 12, 1, 59, int(time.Millisecond), time.UTC)
+	if w := serveAt(router); w.Code != http.StatusOK {
+		t.Fatalf("expected a request to be admitted after the window slid, got %d", w.Code)
+	}
+}
+
+func TestSlidingWindowRejectedRequestsDoNotConsumeSlots(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	router := newWindowRateLimitRouter(t, 1, &clock)
+
+	if w := serveAt(router); w.Code != http.StatusOK {
+		t.Fatalf("expected the first request to pass, got %d", w.Code)
+	}
+	for i := 0; i < 5; i++ {
+		clock = clock.Add(10 * time.Second)
+		if w := serveAt(router); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected request at +%ds to be limited, got %d", (i+1)*10, w.Code)
+		}
+	}
+
+	clock = time.Date(2024, 1, 1, 12, 1, 0, int(time.Millisecond), time.UTC)
+	if w := serveAt(router); w.Code != http.StatusOK {
+		t.Fatalf("expected rejected requests not to extend the window, got %d", w.Code)
+	}
+}
+
+func TestFallbackLimitersEvictLeastRecentlyUsed(t *testing.T) {
+	limiters := newPerKeyLimiters(1, 1, 2)
+
+	if !limiters.allow("a") || !limiters.allow("b") {
+		t.Fatal("expected first requests for a and b to pass")
+	}
+	// a is used again (and limited), so b is now the least recently used.
+	if limiters.allow("a") {
+		t.Fatal("expected a to be limited after its burst")
+	}
+	if !limiters.allow("c") {
+		t.Fatal("expected first request for c to pass")
+	}
+
+	if limiters.allow("a") {
+		t.Fatal("expected a to keep its exhausted bucket")
+	}
+	if !limiters.allow("b") {
+		t.Fatal("expected b to have been evicted and start with a fresh bucket")
+	}
+}
+
+func TestRateLimitFallsBackWhileRedisIsDownAndRecovers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	redisClient := clients.NewRedisClient(config.RedisConfig{URL: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	router := gin.New()
+	router.Use(rateLimit(redisClient, logger.New("info"), rateLimitOptions{
+		name:           "general",
+		enabled:        true,
+		requestsPerMin: 1,
+		burstSize:      1,
+		keyPrefix:      "rl:ip",
+		now:            func() time.Time { return clock },
+	}))
+	router.GET("/api/v1/posts", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serveFrom := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+
+	// Each client keeps its own allowance while Redis is down.
+	if w := serveFrom("203.0.113.7"); w.Code != http.StatusOK {
+		t.Fatalf("expected the first client's request to pass, got %d", w.Code)
+	}
+	if w := serveFrom("203.0.113.7"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the first client to be limited in memory, got %d", w.Code)
+	}
+	if w := serveFrom("203.0.113.8"); w.Code != http.StatusOK {
+		t.Fatalf("expected another client not to share the first one's bucket, got %d", w.Code)
+	}
+
+	// Redis is back, but the limiter only probes it once the interval passed.
+	server.SetError("")
+	if w := serveFrom("203.0.113.9"); w.Header().Get("X-RateLimit-Reset") != "" {
+		t.Fatal("expected the limiter to stay in memory until the next probe")
+	}
+	clock = clock.Add(redisProbeInterval)
+	w := serveFrom("203.0.113.9")
+	if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Reset") == "" {
+		t.Fatalf("expected the probe to go back to Redis, got %d %v", w.Code, w.Header())
+	}
+	if w := serveFrom("203.0.113.7"); w.Header().Get("X-RateLimit-Reset") == "" {
+		t.Fatal("expected later requests to use Redis again")
+	}
+}
+
+func TestRateLimitAppliesRouteSpecificLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	redisClient := clients.NewRedisClient(config.RedisConfig{URL: server.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	router := gin.New()
+	router.Use(RateLimit(redisClient, config.RateLimitConfig{
+		Enabled:               true,
+		RequestsPerMinute:     1,
+		BurstSize:             1,
+		UserRequestsPerMinute: 1,
+		UserBurstSize:         1,
+		Routes: map[string]config.RouteRateLimit{
+			"/api/v1/search":       {RequestsPerMinute: 3, BurstSize: 3},
+			"/api/v1/search/users": {RequestsPerMinute: 2, BurstSize: 2},
+		},
+	}, logger.New("info")))
+	for _, route := range []string{"/api/v1/posts", "/api/v1/search/posts", "/api/v1/search/users"} {
+		router.GET(route, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	admitted := func(path string, requests int) (int, string) {
+		allowed, limit := 0, ""
+		for i := 0; i < requests; i++ {
+			w := get(path)
+			if w.Code == http.StatusOK {
+				allowed++
+			}
+			limit = w.Header().Get("X-RateLimit-Limit")
+		}
+		return allowed, limit
+	}
+
+	if allowed, limit := admitted("/api/v1/posts", 3); allowed != 1 || limit != "1" {
+		t.Fatalf("expected the global limit of 1 on an unconfigured route, got %d admitted, limit %s", allowed, limit)
+	}
+	// Counted apart from the global limit, which is already used up.
+	if allowed, limit := admitted("/api/v1/search/posts", 5); allowed != 3 || limit != "3" {
+		t.Fatalf("expected the search limit of 3, got %d admitted, limit %s", allowed, limit)
+	}
+	if allowed, limit := admitted("/api/v1/search/users", 5); allowed != 2 || limit != "2" {
+		t.Fatalf("expected the longest prefix's limit of 2, got %d admitted, limit %s", allowed, limit)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
+)
+
+// ContextRequestIDKey is the gin context key holding the request's correlation ID.
+const ContextRequestIDKey = "requestID"
+
+// RequestID reuses a well-formed X-Request-ID from the client or generates a
+// new one. The ID is echoed in the response, stored in the gin context for the
+// access log, attached to the request context so the gRPC clients forward it,
+// and set on the request headers so proxied HTTP calls carry it as well.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Set(ContextRequestIDKey, id)
+		c.Request.Header.Set(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/config"
+	"api-gateway/pkg/logger"
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
+)
+
+func newRequestIDRouter(seen *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/api/v1/posts", func(c *gin.Context) {
+		*seen = requestid.FromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRequestIDReusesClientValue(t *testing.T) {
+	var seen string
+	router := newRequestIDRouter(&seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+	req.Header.Set(requestid.Header, "trace-123.abc")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get(requestid.Header); got != "trace-123.abc" {
+		t.Errorf("expected response header to echo the client ID, got %q", got)
+	}
+	if seen != "trace-123.abc" {
+		t.Errorf("expected request context to carry the client ID, got %q", seen)
+	}
+}
+
+func TestRequestIDReplacesMissingOrMalformedValues(t *testing.T) {
+	for _, header := range []string{"", "bad id\nINFO forged", strings.Repeat("a", 129)} {
+		var seen string
+		router := newRequestIDRouter(&seen)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+		if header != "" {
+			req.Header.Set(requestid.Header, header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		got := w.Header().Get(requestid.Header)
+		if got == "" || got == header || !requestid.Valid(got) {
+			t.Errorf("header %q: expected a generated ID, got %q", header, got)
+		}
+		if seen != got {
+			t.Errorf("header %q: context ID %q does not match response ID %q", header, seen, got)
+		}
+	}
+}
+
+func TestRequestLoggerIncludesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf strings.Builder
+	appLogger := logger.New("info")
+	appLogger.SetOutput(&buf)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(RequestLogger(appLogger, config.AccessLogConfig{SampleRate: 1}))
+	router.GET("/api/v1/posts", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+	req.Header.Set(requestid.Header, "trace-456")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(buf.String(), "request_id=trace-456") {
+		t.Fatalf("expected access log to include the request ID, got %q", buf.String())
+	}
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/pkg/utils"
+)
+
+// RouteTimeout overrides the request timeout for routes under Prefix. A zero
+// Timeout exempts the routes, e.g. long-lived streams.
+type RouteTimeout struct {
+	Prefix  string
+	Timeout time.Duration
+}
+
+// Timeout bounds each request with a deadline on its context: defaultTimeout,
+// or the timeout of the longest override prefix matching the route. Downstream
+// gRPC and HTTP calls inherit the deadline, so they are cancelled when it
+// passes. A handler that returns after the deadline without having written a
+// response gets a 504; whatever it tries to write at that point (typically a
+// generic 503 for the cancelled call) is discarded.
+func Timeout(defaultTimeout time.Duration, overrides ...RouteTimeout) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := routeTimeout(c, defaultTimeout, overrides)
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.timedOut || (!writer.Written() && errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+			utils.ErrorResponse(c, http.StatusGatewayTimeout, "GATEWAY_TIMEOUT", "Request timed out")
+		}
+	}
+}
+
+func routeTimeout(c *gin.Context, defaultTimeout time.Duration, overrides []RouteTimeout) time.Duration {
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+
+	timeout, matched := defaultTimeout, 0
+	for _, override := range overrides {
+		if strings.HasPrefix(route, override.Prefix) && len(override.Prefix) > matched {
+			timeout, matched = override.Timeout, len(override.Prefix)
+		}
+	}
+	return timeout
+}
+
+// timeoutWriter drops the handler's response once the deadline has passed, so
+// Timeout can answer with a 504 instead.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	timedOut bool
+}
+
+func (w *timeoutWriter) expired() bool {
+	if w.timedOut {
+		return true
+	}
+	if !w.ResponseWriter.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timedOut = true
+	}
+	return w.timedOut
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.expired() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.expired() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
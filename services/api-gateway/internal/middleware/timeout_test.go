@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeoutCancelsDownstreamCall(t *testing.T) {
+	downstreamCancelled := make(chan struct{})
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(downstreamCancelled)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer downstream.Close()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(5*time.Second, RouteTimeout{Prefix: "/api/v1/auth/validate", Timeout: 50 * time.Millisecond}))
+
+	var doErr error
+	router.GET("/api/v1/auth/validate", func(c *gin.Context) {
+		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, downstream.URL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		doErr = err
+		// What a handler typically does with a failed downstream call; the
+		// middleware must replace it with a 504.
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "unavailable"})
+	})
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/validate", nil))
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the route timeout to apply, took %s", elapsed)
+	}
+	if !errors.Is(doErr, context.DeadlineExceeded) {
+		t.Fatalf("expected httpClient.Do to be aborted by the deadline, got %v", doErr)
+	}
+	select {
+	case <-downstreamCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the downstream request to be cancelled")
+	}
+}
+
+func TestTimeoutUsesLongestMatchingPrefix(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(time.Second,
+		RouteTimeout{Prefix: "/api/v1/notifications", Timeout: 2 * time.Second},
+		RouteTimeout{Prefix: "/api/v1/notifications/stream"},
+	))
+
+	deadlines := map[string]bool{}
+	record := func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		deadlines[c.FullPath()] = ok
+		c.Status(http.StatusNoContent)
+	}
+	router.GET("/api/v1/notifications", record)
+	router.GET("/api/v1/notifications/stream", record)
+
+	for _, path := range []string{"/api/v1/notifications", "/api/v1/notifications/stream"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("%s: expected 204, got %d", path, rec.Code)
+		}
+	}
+	if !deadlines["/api/v1/notifications"] {
+		t.Fatal("expected a deadline on the list route")
+	}
+	if deadlines["/api/v1/notifications/stream"] {
+		t.Fatal("expected the stream to be exempt from the timeout")
+	}
+}
+
+func TestTimeoutLeavesFastResponsesAlone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(time.Second))
+	router.GET("/api/v1/posts", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"ok":true}` {
+		t.Fatalf("expected the handler's response, got %d %s", rec.Code, rec.Body.String())
+	}
+}
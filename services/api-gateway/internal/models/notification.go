@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Notification models mirror the notification service's JSON payloads.
+type NotificationResponse struct {
+	ID         string                 `json:"id"`
+	UserID     string                 `json:"user_id"`
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Message    string                 `json:"message"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Priority   string                 `json:"priority,omitempty"`
+	Read       bool                   `json:"read"`
+	CreatedAt  time.Time              `json:"created_at"`
+	ReadAt     *time.Time             `json:"read_at,omitempty"`
+	ArchivedAt *time.Time             `json:"archived_at,omitempty"`
+}
+
+type ListNotificationsRequest struct {
+	Limit    int    `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset,default=0" binding:"omitempty,min=0"`
+	Unread   bool   `form:"unread,default=false"`
+	Type     string `form:"type" binding:"omitempty,max=50"`
+	Priority string `form:"priority" binding:"omitempty,oneof=low normal high"`
+	// IncludeArchived lists archived notifications alongside the inbox.
+	IncludeArchived bool `form:"include_archived,default=false"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []*NotificationResponse `json:"notifications"`
+	Limit         int                     `json:"limit"`
+	Offset        int                     `json:"offset"`
+	Total         int64                   `json:"total"`
+	HasMore       bool                    `json:"has_more"`
+	UnreadCount   int64                   `json:"unread_count"`
+}
+
+type MarkNotificationsReadRequest struct {
+	NotificationIDs []string `json:"notification_ids,omitempty" binding:"omitempty,max=100"`
+	MarkAll         bool     `json:"mark_all,omitempty"`
+}
+
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type NotificationCountsRequest struct {
+	Unread bool `form:"unread,default=false"`
+}
+
+// NotificationCountsResponse has an entry for every notification type, 0 when
+// the user has none of it.
+type NotificationCountsResponse struct {
+	Counts     map[string]int64 `json:"counts"`
+	Total      int64            `json:"total"`
+	UnreadOnly bool             `json:"unread_only"`
+}
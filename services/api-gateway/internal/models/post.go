@@ -3,24 +3,56 @@ package models
 import "time"
 
 type PostResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	Slug      string    `json:"slug"`
-	Published bool      `json:"published"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content"`
+	Slug          string    `json:"slug"`
+	Status        string    `json:"status"`    // draft, published or archived
+	Published     bool      `json:"published"` // status == published, kept for older clients
+	CoverImageURL string    `json:"cover_image_url,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	LikeCount     int64     `json:"like_count"`
+	// LikedByMe says whether the caller likes the post; absent for
+	// anonymous requests.
+	LikedByMe *bool `json:"liked_by_me,omitempty"`
+	// ContentHTML is the sanitized HTML rendering of Content, present only
+	// with render=html.
+	ContentHTML string `json:"content_html,omitempty"`
+}
+
+// PostLikeResponse is a post's like state after the caller liked or unliked it.
+type PostLikeResponse struct {
+	PostID    string `json:"post_id"`
+	LikeCount int64  `json:"like_count"`
+	LikedByMe bool   `json:"liked_by_me"`
+}
+
+// PostWithAuthorResponse is a post with its author's public profile. Author
+// is null when the profile could not be loaded.
+type PostWithAuthorResponse struct {
+	Post   *PostResponse        `json:"post"`
+	Author *UserProfileResponse `json:"author"`
 }
 
 type PostSummaryResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Title     string    `json:"title"`
-	Slug      string    `json:"slug"`
-	Published bool      `json:"published"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Title         string    `json:"title"`
+	Slug          string    `json:"slug"`
+	Status        string    `json:"status"`    // draft, published or archived
+	Published     bool      `json:"published"` // status == published, kept for older clients
+	CoverImageURL string    `json:"cover_image_url,omitempty"`
+	Excerpt       string    `json:"excerpt"` // author-written, or the start of the content
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// DateRange bounds a listing by creation time, inclusive. Nil ends are open.
+type DateRange struct {
+	From *time.Time
+	To   *time.Time
 }
 
 type ListPostsResponse struct {
@@ -33,18 +65,52 @@ type ListPostsResponse struct {
 type PostStatsResponse struct {
 	TotalPublishedPosts int64 `json:"total_published_posts"`
 	UserPostsCount      int64 `json:"user_posts_count,omitempty"`
+	UserDraftCount      int64 `json:"user_draft_count,omitempty"`
+	UserScheduledCount  int64 `json:"user_scheduled_count,omitempty"`
 }
 
 type CreatePostRequest struct {
-	Title     string `json:"title" binding:"required,min=1,max=200"`
-	Content   string `json:"content" binding:"required,min=1,max=50000"`
-	Slug      string `json:"slug,omitempty" binding:"omitempty,min=3,max=100"`
-	Published bool   `json:"published,omitempty"`
+	Title         string `json:"title" binding:"required,min=1,max=200"`
+	Content       string `json:"content" binding:"required,min=1,max=50000"`
+	Slug          string `json:"slug,omitempty" binding:"omitempty,min=3,max=100"`
+	Published     bool   `json:"published,omitempty"`
+	CoverImageURL string `json:"cover_image_url,omitempty" binding:"omitempty,max=2048"` // optional http(s) URL
+	Excerpt       string `json:"excerpt,omitempty" binding:"omitempty,max=300"`          // generated from content when empty
 }
 
 type UpdatePostRequest struct {
-	Title     *string `json:"title,omitempty" binding:"omitempty,min=1,max=200"`
-	Content   *string `json:"content,omitempty" binding:"omitempty,min=1,max=50000"`
-	Slug      *string `json:"slug,omitempty" binding:"omitempty,min=3,max=100"`
-	Published *bool   `json:"published,omitempty"`
+	Title         *string `json:"title,omitempty" binding:"omitempty,min=1,max=200"`
+	Content       *string `json:"content,omitempty" binding:"omitempty,min=1,max=50000"`
+	Slug          *string `json:"slug,omitempty" binding:"omitempty,min=3,max=100"`
+	Published     *bool   `json:"published,omitempty"`
+	CoverImageURL *string `json:"cover_image_url,omitempty" binding:"omitempty,max=2048"` // empty removes the cover image
+	Excerpt       *string `json:"excerpt,omitempty" binding:"omitempty,max=300"`          // empty goes back to a generated excerpt
+}
+
+type CreatePreviewTokenRequest struct {
+	// ExpiresInHours limits how long the link works; zero means until revoked.
+	ExpiresInHours int `json:"expires_in_hours,omitempty" binding:"omitempty,min=1,max=720"`
+}
+
+type PreviewTokenResponse struct {
+	PostID    string     `json:"post_id"`
+	Token     string     `json:"token"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// BulkDeletePostsRequest names up to 100 of the caller's posts to delete.
+type BulkDeletePostsRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+// BulkDeleteResult reports one requested id; Deleted is false for posts that
+// do not exist or are not the caller's.
+type BulkDeleteResult struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+type BulkDeletePostsResponse struct {
+	Results []BulkDeleteResult `json:"results"`
+	Deleted int                `json:"deleted"`
 }
@@ -39,13 +39,31 @@ type TokenValidationResponse struct {
 	Email  string `json:"email,omitempty"`
 }
 
-// Notification models (for future implementation)
-type NotificationResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Type      string    `json:"type"`
-	Title     string    `json:"title"`
-	Message   string    `json:"message"`
-	Read      bool      `json:"read"`
-	CreatedAt time.Time `json:"created_at"`
+type LogoutAllResponse struct {
+	SessionsTerminated int `json:"sessions_terminated"`
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	TokenTypes []string  `json:"token_types"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IP         string    `json:"ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
+
+type ListSessionsResponse struct {
+	Sessions []*SessionResponse `json:"sessions"`
+}
+
+type TokenIntrospectionResponse struct {
+	Active    bool       `json:"active"`
+	UserID    string     `json:"user_id,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// IntrospectTokensResponse lists results in the order the tokens were sent.
+type IntrospectTokensResponse struct {
+	Results []*TokenIntrospectionResponse `json:"results"`
 }
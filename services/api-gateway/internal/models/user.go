@@ -4,16 +4,22 @@ import "time"
 
 // User models
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Picture   string    `json:"picture,omitempty"`
-	Bio       string    `json:"bio,omitempty"`
-	Location  string    `json:"location,omitempty"`
-	Website   string    `json:"website,omitempty"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Username        string     `json:"username,omitempty"`
+	Picture         string     `json:"picture,omitempty"`
+	Bio             string     `json:"bio,omitempty"`
+	Location        string     `json:"location,omitempty"`
+	Website         string     `json:"website,omitempty"`
+	Role            string     `json:"role,omitempty"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	LastSeenAt      *time.Time `json:"last_seen_at,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // UserProfileResponse is the public/discovery view of a user. It deliberately
@@ -22,24 +28,53 @@ type UserResponse struct {
 type UserProfileResponse struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
 	Picture  string `json:"picture,omitempty"`
 	Bio      string `json:"bio,omitempty"`
 	Location string `json:"location,omitempty"`
 	Website  string `json:"website,omitempty"`
 }
 
+// UsersBatchResponse maps each found user ID to its public profile; unknown
+// and deactivated IDs are absent.
+type UsersBatchResponse struct {
+	Users map[string]*UserProfileResponse `json:"users"`
+}
+
 type ListUsersResponse struct {
-	Users  []*UserResponse `json:"users"`
-	Limit  int             `json:"limit"`
-	Offset int             `json:"offset"`
-	Total  int             `json:"total"`
+	Users   []*UserResponse `json:"users"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+	Total   int             `json:"total"`
+	HasMore bool            `json:"has_more"`
 }
 
 type UserStatsResponse struct {
 	TotalActiveUsers int64 `json:"total_active_users"`
 }
 
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ChangeEmailResponse confirms a change is pending; the token goes to the new
+// address only.
+type ChangeEmailResponse struct {
+	PendingEmail string    `json:"pending_email"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type VerifyEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ListBlockedResponse struct {
+	Users      []*UserProfileResponse `json:"users"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
 type ListFollowResponse struct {
 	Users      []*UserProfileResponse `json:"users"`
 	NextCursor string                 `json:"next_cursor,omitempty"`
+	Total      int64                  `json:"total"`
 }
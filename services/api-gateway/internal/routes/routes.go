@@ -2,6 +2,7 @@ package routes
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -9,24 +10,50 @@ import (
 	"api-gateway/internal/config"
 	"api-gateway/internal/handlers"
 	"api-gateway/internal/middleware"
+	"api-gateway/pkg/logger"
 	"api-gateway/pkg/metrics"
 	"api-gateway/pkg/utils"
 )
 
+// notificationStreamRoute is the SSE proxy; it is exempt from the request
+// timeout and from compression, both of which assume a bounded response.
+const notificationStreamRoute = "/api/v1/notifications/stream"
+
 func SetupRoutes(
 	router *gin.Engine,
 	authHandler *handlers.AuthHandler,
 	userHandler *handlers.UserHandler,
 	postHandler *handlers.PostHandler,
 	searchHandler *handlers.SearchHandler,
+	notificationHandler *handlers.NotificationHandler,
 	healthHandler *handlers.HealthHandler,
 	authClient *clients.AuthClient,
+	userClient *clients.UserClient,
 	redisClient *clients.RedisClient,
 	cfg *config.Config,
+	appLogger *logger.Logger,
 ) {
+	// Best-effort activity tracking for authenticated routes.
+	lastSeen := middleware.LastSeen(redisClient, userClient, appLogger)
+
+	// The general limiter keys on the user ID when one is set, so each group
+	// installs it after its auth middleware; anonymous requests fall back to
+	// the client IP. Groups that validate a token put the per-IP pre-auth
+	// limiter in front, so bad tokens cannot flood auth-service unlimited.
+	rateLimit := middleware.RateLimit(redisClient, cfg.RateLimit, appLogger)
+	preAuthLimit := middleware.PreAuthRateLimit(redisClient, cfg.RateLimit, appLogger)
+
+	// Replays the first successful response to a POST retried with the same
+	// Idempotency-Key; keys are scoped per user, so it runs after auth.
+	idempotency := middleware.Idempotency(redisClient, cfg.Idempotency, appLogger)
+
 	// Health check route (no auth required)
 	router.GET("/health", healthHandler.HealthCheck)
-	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/health/detailed", healthHandler.DetailedHealthCheck)
+	router.GET("/ready", healthHandler.Readiness)
+	if cfg.MetricsEnabled {
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
 
 	// Root index — a plain GET / would otherwise hit Gin's bare-text 404.
 	router.GET("/", func(c *gin.Context) {
@@ -35,6 +62,8 @@ func SetupRoutes(
 			"status":  "ok",
 			"endpoints": []string{
 				"/health",
+				"/health/detailed",
+				"/ready",
 				"/metrics",
 				"/api/v1/auth",
 				"/api/v1/public/users",
@@ -42,18 +71,31 @@ func SetupRoutes(
 				"/api/v1/users",
 				"/api/v1/posts",
 				"/api/v1/search",
+				"/api/v1/notifications",
+				"/api/v1/admin",
 			},
 		})
 	})
 
 	// Consistent JSON for unmatched routes instead of Gin's plain "404 page not found".
-	router.NoRoute(func(c *gin.Context) {
+	router.NoRoute(rateLimit, func(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusNotFound, "NOT_FOUND", "Route not found")
 	})
 
-	// Global middleware
-	router.Use(middleware.RequestValidator(cfg.RequestMaxBodyBytes))
-	router.Use(middleware.RateLimit(redisClient, cfg.RateLimit))
+	// Global middleware. Compression wraps everything below it so error
+	// responses are encoded too; the timeout goes next so auth lookups count
+	// against it; the body limit must run before anything reads the body.
+	if cfg.Compression.Enabled {
+		router.Use(middleware.Compression(middleware.CompressionOptions{
+			MinSizeBytes: cfg.Compression.MinSizeBytes,
+			SkipPrefixes: []string{notificationStreamRoute},
+		}))
+	}
+	router.Use(
+		middleware.Timeout(time.Duration(cfg.RequestTimeout.DefaultMs)*time.Millisecond, routeTimeouts(cfg.RequestTimeout)...),
+		middleware.BodyLimit(cfg.RequestMaxBodyBytes, middleware.RouteBodyLimit{Prefix: "/api/v1/auth/", MaxBytes: cfg.RequestMaxAuthBodyBytes}),
+		middleware.RequestValidator(cfg.RequestMaxBodyBytes),
+	)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
@@ -61,14 +103,19 @@ func SetupRoutes(
 		// Auth routes (no authentication required)
 		authGroup := v1.Group("/auth")
 		{
+			authPublic := authGroup.Group("")
+			authPublic.Use(rateLimit)
+
 			// OAuth2 redirect endpoints (general limiter only).
-			authGroup.GET("/google", authHandler.GetGoogleAuthURL)
-			authGroup.GET("/google/callback", authHandler.GoogleCallback)
+			authPublic.GET("/google", authHandler.GetGoogleAuthURL)
+			authPublic.GET("/google/callback", authHandler.GoogleCallback)
+			authPublic.GET("/github", authHandler.GetGitHubAuthURL)
+			authPublic.GET("/github/callback", authHandler.GitHubCallback)
 
 			// Credential/token endpoints carry a stricter per-IP limit to blunt
 			// brute-force, credential stuffing, and auth_code/refresh-token guessing.
-			authLimited := authGroup.Group("")
-			authLimited.Use(middleware.AuthRateLimit(redisClient, cfg.RateLimit))
+			authLimited := authPublic.Group("")
+			authLimited.Use(middleware.AuthRateLimit(redisClient, cfg.RateLimit, appLogger))
 			{
 				// Email/password
 				authLimited.POST("/register", authHandler.Register)
@@ -83,16 +130,19 @@ func SetupRoutes(
 
 			// Protected auth routes
 			authProtected := authGroup.Group("")
-			authProtected.Use(middleware.AuthMiddleware(authClient))
+			authProtected.Use(preAuthLimit, middleware.AuthMiddleware(authClient), rateLimit)
 			{
 				authProtected.POST("/logout", authHandler.Logout)
+				authProtected.POST("/logout-all", authHandler.LogoutAll)
+				authProtected.GET("/sessions", authHandler.ListSessions)
+				authProtected.DELETE("/sessions/:id", authHandler.RevokeSession)
 				authProtected.GET("/validate", authHandler.ValidateToken)
 			}
 		}
 
 		// Public routes (no authentication required)
 		publicGroup := v1.Group("/public")
-		publicGroup.Use(middleware.OptionalAuthMiddleware(authClient))
+		publicGroup.Use(preAuthLimit, middleware.OptionalAuthMiddleware(authClient), rateLimit)
 		{
 			// Public user routes
 			publicUsers := publicGroup.Group("/users")
@@ -100,6 +150,7 @@ func SetupRoutes(
 				publicUsers.GET("/search", userHandler.SearchUsers)
 				publicUsers.GET("/stats", userHandler.GetStats)
 				publicUsers.GET("/:id/profile", userHandler.GetUserProfile)
+				publicUsers.GET("/by-username/:username", userHandler.GetUserProfileByUsername)
 			}
 
 			// Public post routes
@@ -109,13 +160,15 @@ func SetupRoutes(
 				publicPosts.GET("/search", postHandler.SearchPosts)
 				// publicPosts.GET("/stats", postHandler.GetPostStats)
 				publicPosts.GET("/slug/:slug", postHandler.GetPostBySlug)
+				publicPosts.GET("/slug/:slug/full", postHandler.GetPostBySlugWithAuthor)
 				publicPosts.GET("/user/:userId", postHandler.GetUserPosts)
+				publicPosts.GET("/preview/:token", postHandler.GetPostByPreviewToken)
 			}
 		}
 
 		// Protected routes (authentication required)
 		protectedGroup := v1.Group("")
-		protectedGroup.Use(middleware.AuthMiddleware(authClient))
+		protectedGroup.Use(preAuthLimit, middleware.AuthMiddleware(authClient), rateLimit, lastSeen, idempotency)
 		{
 			// Combined search (users + posts, cursor-based)
 			protectedGroup.GET("/search", searchHandler.Search)
@@ -125,23 +178,76 @@ func SetupRoutes(
 			{
 				users.POST("", userHandler.CreateUser)
 				users.GET("", userHandler.ListUsers)
+				users.POST("/batch", userHandler.GetUsersBatch)
 				users.GET("/:id", userHandler.GetUser)
 				users.PUT("/:id", userHandler.UpdateUser)
 				users.DELETE("/:id", userHandler.DeleteUser)
+				users.POST("/:id/deactivate", userHandler.DeactivateUser)
+				users.POST("/:id/reactivate", userHandler.ReactivateUser)
+				users.POST("/:id/verify-email", middleware.RequireRole(middleware.RoleAdmin), userHandler.VerifyEmail)
+				users.POST("/:id/change-email", userHandler.ChangeEmail)
+				users.POST("/:id/verify-email-change", userHandler.VerifyEmailChange)
 				users.POST("/:id/follow", userHandler.Follow)
 				users.DELETE("/:id/follow", userHandler.Unfollow)
 				users.GET("/:id/followers", userHandler.GetFollowers)
 				users.GET("/:id/following", userHandler.GetFollowing)
+				users.POST("/:id/block", userHandler.Block)
+				users.DELETE("/:id/block", userHandler.Unblock)
+				users.GET("/:id/blocked", userHandler.GetBlocked)
+			}
+
+			// Notification routes (proxied to the notification service)
+			notifications := protectedGroup.Group("/notifications")
+			{
+				notifications.GET("", notificationHandler.ListNotifications)
+				notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+				notifications.GET("/counts", notificationHandler.GetCountsByType)
+				notifications.GET("/stream", notificationHandler.StreamNotifications)
+				notifications.PUT("/mark-read", notificationHandler.MarkAsRead)
+				notifications.GET("/:id", notificationHandler.GetNotification)
+				notifications.POST("/:id/archive", notificationHandler.ArchiveNotification)
+				notifications.POST("/:id/unarchive", notificationHandler.UnarchiveNotification)
+				notifications.DELETE("/:id", notificationHandler.DeleteNotification)
 			}
 
 			// Post routes
 			posts := protectedGroup.Group("/posts")
 			{
 				posts.POST("", postHandler.CreatePost)
+				posts.GET("/user/:userId", postHandler.GetUserPosts) // with ?status= for the owner's drafts and archive
 				posts.GET("/:id", postHandler.GetPost)
+				posts.GET("/:id/full", postHandler.GetPostWithAuthor)
 				posts.PUT("/:id", postHandler.UpdatePost)
 				posts.DELETE("/:id", postHandler.DeletePost)
+				posts.POST("/bulk-delete", postHandler.BulkDeletePosts)
+				posts.POST("/:id/clone", postHandler.ClonePost)
+				posts.POST("/:id/archive", postHandler.ArchivePost)
+				posts.POST("/:id/like", postHandler.LikePost)
+				posts.DELETE("/:id/like", postHandler.UnlikePost)
+				posts.POST("/:id/preview-token", postHandler.CreatePreviewToken)
+				posts.DELETE("/:id/preview-token", postHandler.RevokePreviewToken)
 			}
 		}
+
+		// Admin routes (authentication and the admin role required)
+		adminGroup := v1.Group("/admin")
+		adminGroup.Use(preAuthLimit, middleware.AuthMiddleware(authClient), rateLimit, lastSeen, middleware.RequireRole(middleware.RoleAdmin), idempotency)
+		{
+			adminGroup.GET("/users", userHandler.AdminListUsers)
+			adminGroup.DELETE("/users/:id", userHandler.AdminDeleteUser)
+			// Introspection reveals whether any token is live and whose it is.
+			adminGroup.POST("/auth/introspect", authHandler.IntrospectTokens)
+		}
+	}
+}
+
+// routeTimeouts turns the configured per-route timeouts into middleware
+// overrides. The notification stream is always exempt: it stays open for as
+// long as the client is connected.
+func routeTimeouts(cfg config.RequestTimeoutConfig) []middleware.RouteTimeout {
+	overrides := []middleware.RouteTimeout{{Prefix: notificationStreamRoute}}
+	for prefix, ms := range cfg.RoutesMs {
+		overrides = append(overrides, middleware.RouteTimeout{Prefix: prefix, Timeout: time.Duration(ms) * time.Millisecond})
 	}
+	return overrides
 }
@@ -18,6 +18,7 @@ import (
 	"api-gateway/internal/routes"
 	"api-gateway/pkg/logger"
 	"api-gateway/pkg/metrics"
+	"api-gateway/pkg/tracing"
 )
 
 func main() {
@@ -28,37 +29,63 @@ func main() {
 	}
 
 	// Initialize logger
-	appLogger := logger.New(cfg.LogLevel)
+	appLogger := logger.NewWithOptions(logger.Options{Service: "api-gateway", Level: cfg.LogLevel, Format: cfg.LogFormat})
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{ServiceName: "api-gateway", Endpoint: cfg.Tracing.Endpoint, SampleRatio: cfg.Tracing.SampleRatio})
+	if err != nil {
+		appLogger.Fatal("Failed to initialize tracing: " + err.Error())
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			appLogger.Warn("Failed to flush traces", logger.Err(err))
+		}
+	}()
 
 	// Initialize service clients
 	redisClient := clients.NewRedisClient(cfg.Redis)
-	authClient, err := clients.NewAuthClient(cfg.Services.AuthGRPCAddr, cfg.GRPCTLS, appLogger)
+	authClient, err := clients.NewAuthClient(cfg.Services.AuthGRPCAddr, cfg.GRPCTLS, cfg.GRPCRetry, appLogger)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to auth service: " + err.Error())
 	}
-	userClient, err := clients.NewUserClient(cfg.Services.UserGRPCAddr, cfg.GRPCTLS, appLogger)
+	userClient, err := clients.NewUserClient(cfg.Services.UserGRPCAddr, cfg.GRPCTLS, cfg.GRPCRetry, appLogger)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to user service: " + err.Error())
 	}
-	postClient, err := clients.NewPostClient(cfg.Services.PostGRPCAddr, cfg.GRPCTLS, appLogger)
+	postClient, err := clients.NewPostClient(cfg.Services.PostGRPCAddr, cfg.GRPCTLS, cfg.GRPCRetry, appLogger)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to post service: " + err.Error())
 	}
-	searchClient, err := clients.NewSearchClient(cfg.Services.SearchGRPCAddr, cfg.GRPCTLS, appLogger)
+	searchClient, err := clients.NewSearchClient(cfg.Services.SearchGRPCAddr, cfg.GRPCTLS, cfg.GRPCRetry, appLogger)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to search service: " + err.Error())
 	}
 
+	httpTransport := clients.NewHTTPTransport(cfg.HTTPClient)
+	notificationClient, err := clients.NewNotificationClient(cfg.Services.NotificationURL, tracing.Transport(clients.InternalTokenTransport(httpTransport, cfg.InternalServiceToken)), appLogger)
+	if err != nil {
+		appLogger.Fatal("Failed to configure notification client: " + err.Error())
+	}
+
 	// Test service connections
-	if err := testServiceConnections(authClient, userClient, postClient, searchClient, appLogger); err != nil {
+	if err := testServiceConnections(authClient, userClient, postClient, searchClient, notificationClient, appLogger); err != nil {
 		appLogger.Warn("Some services are not available: " + err.Error())
 	}
 
 	authHandler := handlers.NewAuthHandler(authClient, cfg, appLogger)
-	userHandler := handlers.NewUserHandler(userClient, appLogger)
-	postHandler := handlers.NewPostHandler(postClient, appLogger)
+	userHandler := handlers.NewUserHandler(userClient, authClient, appLogger)
+	var userProvisioner handlers.UserProvisioner
+	if cfg.Auth.AutoProvisionUsers {
+		userProvisioner = userClient
+	}
+	postHandler := handlers.NewPostHandler(postClient, userProvisioner, userClient, appLogger)
 	searchHandler := handlers.NewSearchHandler(searchClient, appLogger)
-	healthHandler := handlers.NewHealthHandler(authClient, userClient, postClient, cfg.Services.NotificationURL, appLogger)
+	notificationHandler := handlers.NewNotificationHandler(notificationClient, appLogger)
+	healthHandler := handlers.NewHealthHandler(authClient, userClient, postClient, notificationClient, cfg.HealthCheck, appLogger)
+	healthCtx, stopHealthProbes := context.WithCancel(context.Background())
+	defer stopHealthProbes()
+	go healthHandler.Run(healthCtx)
 
 	// Setup HTTP server
 	if cfg.Environment == "production" {
@@ -70,17 +97,21 @@ func main() {
 		appLogger.Fatal("Failed to configure trusted proxies: " + err.Error())
 	}
 
-	metrics.Init()
+	if cfg.MetricsEnabled {
+		metrics.Init()
+	}
 
 	// Global middleware
 	router.Use(gin.Recovery())
+	router.Use(tracing.GinMiddleware())
 	router.Use(metrics.GinMiddleware("api-gateway"))
-	router.Use(middleware.RequestLogger(appLogger))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger(appLogger, cfg.AccessLog))
 	router.Use(middleware.CORS(cfg.CORS))
 	router.Use(middleware.SecurityHeaders(cfg.Environment))
 
 	// Setup routes
-	routes.SetupRoutes(router, authHandler, userHandler, postHandler, searchHandler, healthHandler, authClient, redisClient, cfg)
+	routes.SetupRoutes(router, authHandler, userHandler, postHandler, searchHandler, notificationHandler, healthHandler, authClient, userClient, redisClient, cfg, appLogger)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -104,20 +135,33 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	// Report not ready first and keep serving for the shutdown delay, so the
+	// load balancer stops routing here before the listener goes away.
+	healthHandler.StartDraining()
+	server.SetKeepAlivesEnabled(false)
+	appLogger.Info("Draining", logger.F("delay_seconds", cfg.Server.ShutdownDelay))
+	select {
+	case <-time.After(time.Duration(cfg.Server.ShutdownDelay) * time.Second):
+	case <-quit:
+		appLogger.Warn("Second signal received, skipping the drain delay")
+	}
+
 	appLogger.Info("Shutting down server...")
+	stopHealthProbes()
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Shutdown returns once in-flight requests have finished; clients are
+	// closed only after that so no request loses its downstream connection.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		appLogger.Fatal("Server forced to shutdown: " + err.Error())
+		appLogger.Error("Server did not drain in time, closing remaining connections", logger.Err(err))
+		_ = server.Close()
 	}
 
 	// Close service clients
-	err = redisClient.Close()
-	if err != nil {
-		return
+	if err := redisClient.Close(); err != nil {
+		appLogger.Warn("Failed to close redis client: " + err.Error())
 	}
 	if err := authClient.Close(); err != nil {
 		appLogger.Warn("Failed to close auth client: " + err.Error())
@@ -132,10 +176,12 @@ func main() {
 		appLogger.Warn("Failed to close search client: " + err.Error())
 	}
 
+	httpTransport.CloseIdleConnections()
+
 	appLogger.Info("Server exited")
 }
 
-func testServiceConnections(authClient *clients.AuthClient, userClient *clients.UserClient, postClient *clients.PostClient, searchClient *clients.SearchClient, logger *logger.Logger) error {
+func testServiceConnections(authClient *clients.AuthClient, userClient *clients.UserClient, postClient *clients.PostClient, searchClient *clients.SearchClient, notificationClient *clients.NotificationClient, logger *logger.Logger) error {
 
 	logger.Info("Testing service connections...")
 
@@ -167,5 +213,12 @@ func testServiceConnections(authClient *clients.AuthClient, userClient *clients.
 		logger.Info("Search service connected successfully")
 	}
 
+	// Test notification service
+	if err := notificationClient.HealthCheck(context.Background()); err != nil {
+		logger.Warn("Notification service health check failed: " + err.Error())
+	} else {
+		logger.Info("Notification service connected successfully")
+	}
+
 	return nil
 }
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONFormatWritesFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(Options{Service: "api-gateway", Level: "info", Format: FormatJSON})
+	l.SetOutput(&buf)
+
+	l.With(F("request_id", "req-1")).Error("request failed", F("user_id", "user-1"), Err(errors.New("boom")))
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected one JSON object, got %q: %v", buf.String(), err)
+	}
+	want := map[string]string{
+		"level":      "error",
+		"msg":        "request failed",
+		"service":    "api-gateway",
+		"request_id": "req-1",
+		"user_id":    "user-1",
+		"error":      "boom",
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Fatalf("expected %s=%q, got %v", key, value, line[key])
+		}
+	}
+	if _, ok := line["time"]; !ok {
+		t.Fatal("expected a time field")
+	}
+}
+
+func TestTextFormatQuotesValues(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(Options{Level: "info", Format: FormatText})
+	l.SetOutput(&buf)
+
+	l.Info("GET /posts", F("status", 200), F("error", "not found"))
+
+	out := buf.String()
+	if !strings.Contains(out, `[INFO] GET /posts status=200 error="not found"`) {
+		t.Fatalf("unexpected text line %q", out)
+	}
+}
+
+func TestDebugIsGatedByLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(Options{Level: "info", Format: FormatJSON})
+	l.SetOutput(&buf)
+
+	l.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("expected debug to be dropped at info level, got %q", buf.String())
+	}
+
+	l = NewWithOptions(Options{Level: "debug", Format: FormatJSON})
+	l.SetOutput(&buf)
+	l.Debug("shown")
+	if !strings.Contains(buf.String(), `"msg":"shown"`) {
+		t.Fatalf("expected debug line at debug level, got %q", buf.String())
+	}
+}
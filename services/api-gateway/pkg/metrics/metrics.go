@@ -12,9 +12,13 @@ import (
 )
 
 var (
-	reg     = prometheus.NewRegistry()
-	httpReq *prometheus.CounterVec
-	httpDur *prometheus.HistogramVec
+	reg          = prometheus.NewRegistry()
+	httpReq      *prometheus.CounterVec
+	httpDur      *prometheus.HistogramVec
+	httpInFlight *prometheus.GaugeVec
+
+	rateLimitDegraded *prometheus.GaugeVec
+	rateLimitFallback *prometheus.CounterVec
 )
 
 // Init registers collectors and HTTP metrics for this process.
@@ -32,31 +36,88 @@ func Init() {
 		Help:      "HTTP request duration in seconds.",
 		Buckets:   prometheus.DefBuckets,
 	}, []string{"service", "method", "route"})
+	httpInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "microblog",
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	}, []string{"service", "method", "route"})
+
+	rateLimitDegraded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "microblog",
+		Subsystem: "ratelimit",
+		Name:      "degraded",
+		Help:      "1 while the rate limiter cannot reach Redis and limits in memory.",
+	}, []string{"limiter"})
+	rateLimitFallback = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "microblog",
+		Subsystem: "ratelimit",
+		Name:      "fallback_decisions_total",
+		Help:      "Requests decided without Redis, by outcome.",
+	}, []string{"limiter", "outcome"})
 
 	reg.MustRegister(
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 		httpReq,
 		httpDur,
+		httpInFlight,
+		rateLimitDegraded,
+		rateLimitFallback,
 	)
 }
 
+// SetRateLimitDegraded records whether limiter is running without Redis.
+func SetRateLimitDegraded(limiter string, degraded bool) {
+	if rateLimitDegraded == nil {
+		return
+	}
+	value := 0.0
+	if degraded {
+		value = 1
+	}
+	rateLimitDegraded.WithLabelValues(limiter).Set(value)
+}
+
+// RateLimitFallbackDecision counts a request limiter admitted or rejected
+// without Redis.
+func RateLimitFallbackDecision(limiter string, allowed bool) {
+	if rateLimitFallback == nil {
+		return
+	}
+	outcome := "rejected"
+	if allowed {
+		outcome = "allowed"
+	}
+	rateLimitFallback.WithLabelValues(limiter, outcome).Inc()
+}
+
 // Handler exposes /metrics for Prometheus scraping.
 func Handler() http.Handler {
 	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
 }
 
-// GinMiddleware records request counts and latency after routing.
+// GinMiddleware records request counts, latency and in-flight requests after
+// routing. It is a no-op when Init was not called (METRICS_ENABLED=false).
 func GinMiddleware(service string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if httpReq == nil {
+			c.Next()
+			return
+		}
+
 		start := time.Now()
-		c.Next()
-		status := strconv.Itoa(c.Writer.Status())
 		route := c.FullPath()
 		if route == "" {
 			route = c.Request.URL.Path
 		}
 		method := c.Request.Method
+		inFlight := httpInFlight.WithLabelValues(service, method, route)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		c.Next()
+		status := strconv.Itoa(c.Writer.Status())
 		httpReq.WithLabelValues(service, method, route, status).Inc()
 		httpDur.WithLabelValues(service, method, route).Observe(time.Since(start).Seconds())
 	}
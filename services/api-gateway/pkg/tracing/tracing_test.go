@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+func installRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	if _, err := Init(context.Background(), Options{ServiceName: "api-gateway"}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestInitWithoutEndpointIsNoop(t *testing.T) {
+	shutdown, err := Init(context.Background(), Options{ServiceName: "api-gateway"})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestGinMiddlewareAndTransportPropagateTrace(t *testing.T) {
+	recorder := installRecorder(t)
+
+	var downstreamTraceparent string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downstreamTraceparent = r.Header.Get("traceparent")
+	}))
+	defer downstream.Close()
+
+	client := &http.Client{Transport: Transport(nil)}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinMiddleware())
+	router.GET("/api/v1/posts/:id", func(c *gin.Context) {
+		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, downstream.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("downstream call: %v", err)
+			return
+		}
+		resp.Body.Close()
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/42", nil)
+	req.Header.Set("traceparent", "00-"+incomingTraceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected a server and a client span, got %d", len(spans))
+	}
+
+	var server, clientSpan sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		switch span.SpanKind() {
+		case trace.SpanKindServer:
+			server = span
+		case trace.SpanKindClient:
+			clientSpan = span
+		}
+	}
+	if server == nil || clientSpan == nil {
+		t.Fatal("expected one server and one client span")
+	}
+	if server.Name() != "GET /api/v1/posts/:id" {
+		t.Fatalf("expected the span to be named after the route, got %q", server.Name())
+	}
+	if server.SpanContext().TraceID().String() != incomingTraceID {
+		t.Fatalf("expected the incoming trace to be continued, got %s", server.SpanContext().TraceID())
+	}
+	if clientSpan.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Fatal("expected the client span to be a child of the server span")
+	}
+
+	want := "00-" + incomingTraceID + "-" + clientSpan.SpanContext().SpanID().String() + "-01"
+	if downstreamTraceparent != want {
+		t.Fatalf("expected downstream traceparent %q, got %q", want, downstreamTraceparent)
+	}
+}
@@ -23,22 +23,32 @@ func NewAuthError(code, message string, statusCode int) *AuthError {
 }
 
 var (
-	ErrInvalidGoogleCode   = NewAuthError("INVALID_GOOGLE_CODE", "Invalid Google authorization code", http.StatusUnauthorized)
-	ErrInvalidOAuthState   = NewAuthError("INVALID_OAUTH_STATE", "Invalid or expired OAuth state", http.StatusUnauthorized)
-	ErrInvalidRedirectURI  = NewAuthError("INVALID_REDIRECT_URI", "Invalid redirect URI", http.StatusBadRequest)
-	ErrPKCERequired        = NewAuthError("PKCE_REQUIRED", "PKCE code verifier is required", http.StatusBadRequest)
-	ErrInvalidCodeVerifier = NewAuthError("INVALID_CODE_VERIFIER", "Invalid PKCE code verifier", http.StatusBadRequest)
-	ErrInvalidRefreshToken = NewAuthError("INVALID_REFRESH_TOKEN", "Invalid refresh token", http.StatusUnauthorized)
-	ErrInvalidAccessToken  = NewAuthError("INVALID_ACCESS_TOKEN", "Invalid access token", http.StatusUnauthorized)
-	ErrInvalidTokenType    = NewAuthError("INVALID_TOKEN_TYPE", "Invalid token type", http.StatusBadRequest)
-	ErrTokenNotFound       = NewAuthError("TOKEN_NOT_FOUND", "Token not found", http.StatusUnauthorized)
-	ErrTokenBlacklisted    = NewAuthError("TOKEN_BLACKLISTED", "Token has been revoked", http.StatusUnauthorized)
-	ErrTokenGeneration     = NewAuthError("TOKEN_GENERATION_FAILED", "Failed to generate tokens", http.StatusInternalServerError)
-	ErrTokenStorage        = NewAuthError("TOKEN_STORAGE_FAILED", "Failed to store tokens", http.StatusInternalServerError)
-	ErrTokenValidation     = NewAuthError("TOKEN_VALIDATION_FAILED", "Failed to validate token", http.StatusInternalServerError)
-	ErrTokenDeletion       = NewAuthError("TOKEN_DELETION_FAILED", "Failed to delete tokens", http.StatusInternalServerError)
-	ErrInvalidRequest      = NewAuthError("INVALID_REQUEST", "Invalid request parameters", http.StatusBadRequest)
-	ErrInvalidCredentials  = NewAuthError("INVALID_CREDENTIALS", "Invalid email or password", http.StatusUnauthorized)
-	ErrUserAlreadyExists   = NewAuthError("USER_ALREADY_EXISTS", "User with this email already exists", http.StatusConflict)
-	ErrServiceUnavailable  = NewAuthError("SERVICE_UNAVAILABLE", "Authentication service temporarily unavailable", http.StatusServiceUnavailable)
+	ErrInvalidGoogleCode     = NewAuthError("INVALID_GOOGLE_CODE", "Invalid Google authorization code", http.StatusUnauthorized)
+	ErrInvalidOAuthCode      = NewAuthError("INVALID_OAUTH_CODE", "Invalid OAuth authorization code", http.StatusUnauthorized)
+	ErrEmailNotVerified      = NewAuthError("EMAIL_NOT_VERIFIED", "Email address is not verified with the provider", http.StatusForbidden)
+	ErrEmailDomainNotAllowed = NewAuthError("EMAIL_DOMAIN_NOT_ALLOWED", "Email domain is not allowed to sign in", http.StatusForbidden)
+	ErrProviderNotEnabled    = NewAuthError("OAUTH_PROVIDER_NOT_ENABLED", "OAuth provider is not enabled", http.StatusNotFound)
+	ErrInvalidOAuthState     = NewAuthError("INVALID_OAUTH_STATE", "Invalid or expired OAuth state", http.StatusUnauthorized)
+	ErrInvalidRedirectURI    = NewAuthError("INVALID_REDIRECT_URI", "Invalid redirect URI", http.StatusBadRequest)
+	ErrPKCERequired          = NewAuthError("PKCE_REQUIRED", "PKCE code verifier is required", http.StatusBadRequest)
+	ErrInvalidCodeVerifier   = NewAuthError("INVALID_CODE_VERIFIER", "Invalid PKCE code verifier", http.StatusBadRequest)
+	ErrInvalidRefreshToken   = NewAuthError("INVALID_REFRESH_TOKEN", "Invalid refresh token", http.StatusUnauthorized)
+	ErrInvalidAccessToken    = NewAuthError("INVALID_ACCESS_TOKEN", "Invalid access token", http.StatusUnauthorized)
+	ErrInvalidTokenType      = NewAuthError("INVALID_TOKEN_TYPE", "Invalid token type", http.StatusBadRequest)
+	ErrTokenNotFound         = NewAuthError("TOKEN_NOT_FOUND", "Token not found", http.StatusUnauthorized)
+	ErrRefreshTokenReuse     = NewAuthError("REFRESH_TOKEN_REUSE", "Refresh token reuse detected; all sessions have been revoked", http.StatusUnauthorized)
+	ErrTooManyAttempts       = NewAuthError("TOO_MANY_ATTEMPTS", "Too many failed authentication attempts; try again later", http.StatusTooManyRequests)
+	ErrSessionNotFound       = NewAuthError("SESSION_NOT_FOUND", "Session not found", http.StatusNotFound)
+	ErrTokenBlacklisted      = NewAuthError("TOKEN_BLACKLISTED", "Token has been revoked", http.StatusUnauthorized)
+	ErrTokenGeneration       = NewAuthError("TOKEN_GENERATION_FAILED", "Failed to generate tokens", http.StatusInternalServerError)
+	ErrTokenStorage          = NewAuthError("TOKEN_STORAGE_FAILED", "Failed to store tokens", http.StatusInternalServerError)
+	ErrTokenValidation       = NewAuthError("TOKEN_VALIDATION_FAILED", "Failed to validate token", http.StatusInternalServerError)
+	ErrTokenDeletion         = NewAuthError("TOKEN_DELETION_FAILED", "Failed to delete tokens", http.StatusInternalServerError)
+	ErrInvalidRequest        = NewAuthError("INVALID_REQUEST", "Invalid request parameters", http.StatusBadRequest)
+	ErrInvalidCredentials    = NewAuthError("INVALID_CREDENTIALS", "Invalid email or password", http.StatusUnauthorized)
+	ErrAccountDeactivated    = NewAuthError("ACCOUNT_DEACTIVATED", "Account was deactivated by an administrator", http.StatusForbidden)
+	ErrUserAlreadyExists     = NewAuthError("USER_ALREADY_EXISTS", "User with this email already exists", http.StatusConflict)
+	ErrServiceUnavailable    = NewAuthError("SERVICE_UNAVAILABLE", "Authentication service temporarily unavailable", http.StatusServiceUnavailable)
+	ErrOAuthTimeout          = NewAuthError("OAUTH_TIMEOUT", "Sign-in provider did not respond in time", http.StatusGatewayTimeout)
+	ErrUnauthorizedAccess    = NewAuthError("UNAUTHORIZED_ACCESS", "You don't have permission to access this resource", http.StatusForbidden)
 )
@@ -14,8 +14,11 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
+	stdErrors "errors"
 	"fmt"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,54 +26,76 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// UserInfoResult is returned by user-service CreateUser/GetUserByEmail/ValidateCredentials.
+// UserInfoResult is returned by user-service CreateUser/GetUser/GetUserByEmail/ValidateCredentials.
 type UserInfoResult interface {
 	GetId() string
 	GetEmail() string
 	GetName() string
 	GetPicture() string
+	GetRole() string
 }
 
 // UserServiceClient is used by auth-service for user lifecycle operations.
 type UserServiceClient interface {
-	CreateUser(ctx context.Context, id, email, name, picture, password string) (UserInfoResult, error)
-	GetUserByEmail(ctx context.Context, email string) (UserInfoResult, error)
+	CreateUser(ctx context.Context, id, email, name, picture, password string, emailVerified bool) (UserInfoResult, error)
+	// GetUser returns an active user by ID; a deactivated or deleted one is
+	// NotFound.
+	GetUser(ctx context.Context, id string) (UserInfoResult, error)
+	// GetUserByEmail looks up a user; with reactivate set, a deactivated
+	// account is reactivated and returned (used on sign-in).
+	GetUserByEmail(ctx context.Context, email string, reactivate bool) (UserInfoResult, error)
 	ValidateCredentials(ctx context.Context, email, password string) (UserInfoResult, error)
 }
 
 type AuthService struct {
-	tokenRepo     repositories.TokenRepository
-	oauthProvider domainServices.OAuthProvider
-	userClient    UserServiceClient
-	jwtManager    *jwt.Manager
-	jwtConfig     config.JWTConfig
-	googleConfig  config.GoogleConfig
-	logger        *logger.Logger
+	tokenRepo      repositories.TokenRepository
+	oauthProviders map[string]domainServices.OAuthProvider
+	userClient     UserServiceClient
+	jwtManager     *jwt.Manager
+	jwtConfig      config.JWTConfig
+	googleConfig   config.GoogleConfig
+	failureLimit   config.AuthFailureLimitConfig
+	logger         *logger.Logger
 }
 
 func NewAuthService(
 	tokenRepo repositories.TokenRepository,
-	oauthProvider domainServices.OAuthProvider,
+	oauthProviders map[string]domainServices.OAuthProvider,
 	userClient UserServiceClient,
+	jwtManager *jwt.Manager,
 	jwtConfig config.JWTConfig,
 	googleConfig config.GoogleConfig,
+	failureLimit config.AuthFailureLimitConfig,
 	logger *logger.Logger,
 ) *AuthService {
-	jwtManager := jwt.NewManager(jwtConfig.Secret, jwtConfig.Issuer)
-
 	return &AuthService{
-		tokenRepo:     tokenRepo,
-		oauthProvider: oauthProvider,
-		userClient:    userClient,
-		jwtConfig:     jwtConfig,
-		googleConfig:  googleConfig,
-		jwtManager:    jwtManager,
-		logger:        logger,
+		tokenRepo:      tokenRepo,
+		oauthProviders: oauthProviders,
+		userClient:     userClient,
+		jwtConfig:      jwtConfig,
+		googleConfig:   googleConfig,
+		failureLimit:   failureLimit,
+		jwtManager:     jwtManager,
+		logger:         logger,
 	}
 }
 
 // Main OAuth Flow: Get Google Auth URL.
 func (s *AuthService) GetGoogleAuthURL(ctx context.Context, req *dto.GoogleAuthURLRequest) (*dto.GoogleAuthURLResponse, error) {
+	return s.getOAuthAuthURL(ctx, domainServices.ProviderGoogle, req)
+}
+
+// GetGitHubAuthURL starts the same flow as GetGoogleAuthURL against GitHub.
+func (s *AuthService) GetGitHubAuthURL(ctx context.Context, req *dto.GoogleAuthURLRequest) (*dto.GoogleAuthURLResponse, error) {
+	return s.getOAuthAuthURL(ctx, domainServices.ProviderGitHub, req)
+}
+
+func (s *AuthService) getOAuthAuthURL(ctx context.Context, providerName string, req *dto.GoogleAuthURLRequest) (*dto.GoogleAuthURLResponse, error) {
+	provider, ok := s.oauthProviders[providerName]
+	if !ok {
+		return nil, errors.ErrProviderNotEnabled
+	}
+
 	platform, err := normalizeOAuthPlatform(req)
 	if err != nil {
 		return nil, err
@@ -97,13 +122,23 @@ func (s *AuthService) GetGoogleAuthURL(ctx context.Context, req *dto.GoogleAuthU
 		return nil, errors.ErrServiceUnavailable
 	}
 
+	// auth-service runs its own PKCE exchange with the provider, independent of
+	// any challenge the client sent for the auth-code exchange.
+	providerVerifier, err := generateSecureToken(32)
+	if err != nil {
+		s.logger.Error("Failed to generate provider code verifier: " + err.Error())
+		return nil, errors.ErrServiceUnavailable
+	}
+
 	statePayload := &entities.OAuthState{
-		State:               state,
-		Platform:            platform,
-		ClientRedirectURI:   clientRedirectURI,
-		ClientState:         clientState,
-		CodeChallenge:       codeChallenge,
-		CodeChallengeMethod: challengeMethod,
+		State:                state,
+		Provider:             providerName,
+		Platform:             platform,
+		ClientRedirectURI:    clientRedirectURI,
+		ClientState:          clientState,
+		CodeChallenge:        codeChallenge,
+		CodeChallengeMethod:  challengeMethod,
+		ProviderCodeVerifier: providerVerifier,
 	}
 
 	if err := s.tokenRepo.StoreState(ctx, state, statePayload, 10*time.Minute); err != nil {
@@ -111,10 +146,10 @@ func (s *AuthService) GetGoogleAuthURL(ctx context.Context, req *dto.GoogleAuthU
 		return nil, errors.ErrTokenStorage
 	}
 
-	authURL := s.oauthProvider.GetAuthURL(&domainServices.AuthURLRequest{
+	authURL := provider.GetAuthURL(&domainServices.AuthURLRequest{
 		State:               state,
-		CodeChallenge:       codeChallenge,
-		CodeChallengeMethod: challengeMethod,
+		CodeChallenge:       pkceS256Challenge(providerVerifier),
+		CodeChallengeMethod: "S256",
 	})
 
 	return &dto.GoogleAuthURLResponse{
@@ -125,26 +160,72 @@ func (s *AuthService) GetGoogleAuthURL(ctx context.Context, req *dto.GoogleAuthU
 
 // OAuth Callback Handler.
 func (s *AuthService) HandleGoogleCallback(ctx context.Context, req *dto.GoogleCallbackRequest) (*dto.GoogleCallbackResponse, error) {
-	s.logger.Info(fmt.Sprintf("Processing Google callback - state: %s, code length: %d", req.State, len(req.Code)))
+	return s.handleOAuthCallback(ctx, domainServices.ProviderGoogle, req)
+}
+
+// HandleGitHubCallback completes a GitHub login started by GetGitHubAuthURL.
+func (s *AuthService) HandleGitHubCallback(ctx context.Context, req *dto.GoogleCallbackRequest) (*dto.GoogleCallbackResponse, error) {
+	return s.handleOAuthCallback(ctx, domainServices.ProviderGitHub, req)
+}
+
+func (s *AuthService) handleOAuthCallback(ctx context.Context, providerName string, req *dto.GoogleCallbackRequest) (*dto.GoogleCallbackResponse, error) {
+	s.logger.Info(fmt.Sprintf("Processing %s callback - state: %s, code length: %d", providerName, req.State, len(req.Code)))
+
+	provider, ok := s.oauthProviders[providerName]
+	if !ok {
+		return nil, errors.ErrProviderNotEnabled
+	}
 
 	storedState, err := s.tokenRepo.GetAndDeleteState(ctx, req.State)
+	if err != nil && !stdErrors.Is(err, repositories.ErrStateNotFound) {
+		s.logger.Error(fmt.Sprintf("Failed to load OAuth state: %v", err))
+		return nil, errors.ErrServiceUnavailable
+	}
 	if err != nil || storedState == nil || storedState.State != req.State {
 		s.logger.Warn("Invalid or expired OAuth state")
 		return nil, errors.ErrInvalidOAuthState
 	}
+	if stateProvider(storedState) != providerName {
+		s.logger.Warn(fmt.Sprintf("OAuth state issued for %s presented to %s callback", stateProvider(storedState), providerName))
+		return nil, errors.ErrInvalidOAuthState
+	}
+
+	if storedState.ProviderCodeVerifier == "" {
+		s.logger.Warn("OAuth state has no provider code verifier")
+		return nil, errors.ErrInvalidOAuthState
+	}
+
+	invalidCodeErr := errors.ErrInvalidOAuthCode
+	if providerName == domainServices.ProviderGoogle {
+		invalidCodeErr = errors.ErrInvalidGoogleCode
+	}
 
-	userInfo, err := s.oauthProvider.ExchangeCodeForToken(ctx, req.Code)
+	// The provider rejects the exchange if the verifier does not match the
+	// challenge sent with the authorization request.
+	userInfo, err := provider.ExchangeCodeForToken(ctx, req.Code, storedState.ProviderCodeVerifier)
 	if err != nil {
-		s.logger.Error(fmt.Sprintf("Failed to exchange Google code: %v", err))
-		return nil, errors.ErrInvalidGoogleCode
+		if stdErrors.Is(err, domainServices.ErrEmailNotVerified) {
+			s.logger.Warn(fmt.Sprintf("%s login rejected: %v", providerName, err))
+			return nil, errors.ErrEmailNotVerified
+		}
+		if stdErrors.Is(err, domainServices.ErrOAuthTimeout) {
+			s.logger.Error(fmt.Sprintf("%s code exchange timed out: %v", providerName, err))
+			return nil, errors.ErrOAuthTimeout
+		}
+		s.logger.Error(fmt.Sprintf("Failed to exchange %s code: %v", providerName, err))
+		return nil, invalidCodeErr
 	}
 	if !userInfo.IsValid() {
-		s.logger.Error("Invalid user info received from Google")
-		return nil, errors.ErrInvalidGoogleCode
+		if userInfo.ID != "" && userInfo.Email != "" && !userInfo.VerifiedEmail {
+			s.logger.Warn(fmt.Sprintf("%s login rejected: email %s is not verified", providerName, userInfo.Email))
+			return nil, errors.ErrEmailNotVerified
+		}
+		s.logger.Error("Invalid user info received from " + providerName)
+		return nil, invalidCodeErr
 	}
 	if !s.isAllowedEmailDomain(userInfo.Email) {
-		s.logger.Warn("Google account rejected by domain allowlist: " + userInfo.Email)
-		return nil, errors.ErrInvalidGoogleCode
+		s.logger.Warn(fmt.Sprintf("%s account rejected by domain allowlist: %s", providerName, userInfo.Email))
+		return nil, errors.ErrEmailDomainNotAllowed
 	}
 
 	canonicalUser, err := s.ensureUserExists(ctx, userInfo)
@@ -181,9 +262,28 @@ func (s *AuthService) HandleGoogleCallback(ctx context.Context, req *dto.GoogleC
 
 // Exchange temporary auth code for JWT tokens.
 func (s *AuthService) ExchangeAuthCode(ctx context.Context, req *dto.ExchangeAuthCodeRequest) (*dto.ExchangeAuthCodeResponse, error) {
+	if err := s.checkAuthFailures(ctx, req.ClientIP); err != nil {
+		return nil, err
+	}
+
+	client := entities.ClientInfo{IP: req.ClientIP, UserAgent: req.UserAgent}
+	resp, err := s.exchangeAuthCode(ctx, req, client)
+	userID := ""
+	if resp != nil {
+		userID = resp.User.ID
+	}
+	s.recordAuthResult(ctx, client, userID, err)
+	return resp, err
+}
+
+func (s *AuthService) exchangeAuthCode(ctx context.Context, req *dto.ExchangeAuthCodeRequest, client entities.ClientInfo) (*dto.ExchangeAuthCodeResponse, error) {
 	s.logger.Info("Processing auth code exchange")
 
 	authPayload, err := s.tokenRepo.GetAndDeleteAuthCode(ctx, req.AuthCode)
+	if err != nil && !stdErrors.Is(err, repositories.ErrAuthCodeNotFound) {
+		s.logger.Error(fmt.Sprintf("Failed to load auth code: %v", err))
+		return nil, errors.ErrServiceUnavailable
+	}
 	if err != nil || authPayload == nil || authPayload.User == nil {
 		s.logger.Warn(fmt.Sprintf("Invalid or expired auth code: %s", req.AuthCode))
 		return nil, errors.ErrInvalidGoogleCode
@@ -199,7 +299,7 @@ func (s *AuthService) ExchangeAuthCode(ctx context.Context, req *dto.ExchangeAut
 		return nil, errors.ErrTokenGeneration
 	}
 
-	if err := s.storeTokens(ctx, tokenPair, authPayload.User); err != nil {
+	if err := s.storeTokens(ctx, tokenPair, authPayload.User, client); err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to store tokens for user %s: %v", authPayload.User.Email, err))
 		return nil, errors.ErrTokenStorage
 	}
@@ -221,6 +321,21 @@ func (s *AuthService) ExchangeAuthCode(ctx context.Context, req *dto.ExchangeAut
 }
 
 func (s *AuthService) RefreshToken(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.RefreshTokenResponse, error) {
+	if err := s.checkAuthFailures(ctx, req.ClientIP); err != nil {
+		return nil, err
+	}
+
+	client := entities.ClientInfo{IP: req.ClientIP, UserAgent: req.UserAgent}
+	resp, err := s.refreshToken(ctx, req, client)
+	userID := ""
+	if resp != nil {
+		userID = resp.User.ID
+	}
+	s.recordAuthResult(ctx, client, userID, err)
+	return resp, err
+}
+
+func (s *AuthService) refreshToken(ctx context.Context, req *dto.RefreshTokenRequest, client entities.ClientInfo) (*dto.RefreshTokenResponse, error) {
 	s.logger.Info("Processing token refresh")
 
 	// Validate refresh token
@@ -235,6 +350,20 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *dto.RefreshTokenReq
 		return nil, errors.ErrInvalidTokenType
 	}
 
+	// A rotated token being presented again means it was leaked and replayed.
+	reason, err := s.tokenRepo.GetBlacklistReason(ctx, req.RefreshToken)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to check token blacklist: %v", err))
+		return nil, errors.ErrTokenValidation
+	}
+	if reason == repositories.BlacklistReasonRotated {
+		return nil, s.revokeOnRefreshTokenReuse(ctx, claims.UserID)
+	}
+	if reason != "" {
+		s.logger.Warn("Attempted to use blacklisted refresh token")
+		return nil, errors.ErrTokenBlacklisted
+	}
+
 	// Check if token exists in Redis
 	storedToken, err := s.tokenRepo.GetTokenData(ctx, req.RefreshToken)
 	if err != nil {
@@ -242,20 +371,21 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *dto.RefreshTokenReq
 		return nil, errors.ErrTokenNotFound
 	}
 
-	// Check if token is blacklisted
-	blacklisted, err := s.tokenRepo.IsTokenBlacklisted(ctx, req.RefreshToken)
+	// The role is read again rather than copied from the old token, so a
+	// promotion or demotion takes effect on the next refresh.
+	user, err := s.userClient.GetUser(ctx, storedToken.UserID)
 	if err != nil {
-		s.logger.Error(fmt.Sprintf("Failed to check token blacklist: %v", err))
-		return nil, errors.ErrTokenValidation
-	}
-	if blacklisted {
-		s.logger.Warn("Attempted to use blacklisted refresh token")
-		return nil, errors.ErrTokenBlacklisted
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			s.logger.Warn(fmt.Sprintf("Refresh token of a missing or inactive user %s", storedToken.UserID))
+			return nil, errors.ErrInvalidRefreshToken
+		}
+		s.logger.Error(fmt.Sprintf("Failed to load user for refresh: %v", err))
+		return nil, errors.ErrServiceUnavailable
 	}
-
 	userInfo := &entities.GoogleUserInfo{
 		ID:    storedToken.UserID,
 		Email: storedToken.Email,
+		Role:  user.GetRole(),
 	}
 
 	tokenPair, err := s.generateTokenPair(userInfo)
@@ -264,12 +394,35 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *dto.RefreshTokenReq
 		return nil, errors.ErrTokenGeneration
 	}
 
-	if err := s.tokenRepo.BlacklistToken(ctx, req.RefreshToken, time.Duration(s.jwtConfig.RefreshTokenTTL)*time.Hour); err != nil {
-		s.logger.Warn(fmt.Sprintf("Failed to blacklist old refresh token: %v", err))
+	// The rotated pair stays in the same session. Its device context follows
+	// the refreshing client, keeping the previous values when none was sent.
+	sessionID := storedToken.SessionID
+	if sessionID == "" {
+		if sessionID, err = generateSecureToken(16); err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to generate session id: %v", err))
+			return nil, errors.ErrTokenGeneration
+		}
+	}
+	if client.IP == "" {
+		client.IP = storedToken.IP
+	}
+	if client.UserAgent == "" {
+		client.UserAgent = storedToken.UserAgent
 	}
+	newAccessToken, newRefreshToken := s.sessionTokenData(userInfo, tokenPair, sessionID, client)
 
-	if err := s.storeTokens(ctx, tokenPair, userInfo); err != nil {
-		s.logger.Error(fmt.Sprintf("Failed to store new tokens: %v", err))
+	refreshTTL := time.Duration(s.jwtConfig.RefreshTokenTTL) * time.Hour
+	if err := s.tokenRepo.RotateRefreshToken(ctx, req.RefreshToken, tokenPair.RefreshToken, newRefreshToken, refreshTTL); err != nil {
+		if stdErrors.Is(err, repositories.ErrTokenAlreadyRotated) {
+			return nil, s.revokeOnRefreshTokenReuse(ctx, storedToken.UserID)
+		}
+		s.logger.Error(fmt.Sprintf("Failed to rotate refresh token: %v", err))
+		return nil, errors.ErrTokenStorage
+	}
+
+	accessTTL := time.Duration(s.jwtConfig.AccessTokenTTL) * time.Minute
+	if err := s.tokenRepo.StoreAccessToken(ctx, tokenPair.AccessToken, newAccessToken, accessTTL); err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to store new access token: %v", err))
 		return nil, errors.ErrTokenStorage
 	}
 
@@ -287,6 +440,114 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *dto.RefreshTokenReq
 	}, nil
 }
 
+// checkAuthFailures rejects a client that has exhausted its failed attempts,
+// before any token parsing or signature checks are done. Requests without a
+// client IP (e.g. internal callers) are not throttled.
+func (s *AuthService) checkAuthFailures(ctx context.Context, clientIP string) error {
+	if clientIP == "" || s.failureLimit.MaxAttempts <= 0 {
+		return nil
+	}
+
+	failures, err := s.tokenRepo.GetAuthFailures(ctx, clientIP)
+	if err != nil {
+		// Fail open: a Redis hiccup should not lock every client out.
+		s.logger.Error(fmt.Sprintf("Failed to read auth failures for %s: %v", clientIP, err))
+		return nil
+	}
+	if failures >= int64(s.failureLimit.MaxAttempts) {
+		s.logger.Warn(fmt.Sprintf("Too many failed auth attempts from %s", clientIP))
+		return errors.ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+// recordAuthResult writes the attempt to the audit log, then resets the
+// client's failure counter on success or bumps it on client-caused failures.
+// Server-side errors do not count against the client.
+func (s *AuthService) recordAuthResult(ctx context.Context, client entities.ClientInfo, userID string, authErr error) {
+	if err := s.tokenRepo.LogAuthAttempt(ctx, userID, client.IP, client.UserAgent, authErr == nil); err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to log auth attempt: %v", err))
+	}
+
+	clientIP := client.IP
+	if clientIP == "" || s.failureLimit.MaxAttempts <= 0 {
+		return
+	}
+
+	if authErr == nil {
+		if err := s.tokenRepo.ResetAuthFailures(ctx, clientIP); err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to reset auth failures for %s: %v", clientIP, err))
+		}
+		return
+	}
+
+	var appErr *errors.AuthError
+	if !stdErrors.As(authErr, &appErr) || appErr.StatusCode >= 500 {
+		return
+	}
+
+	window := time.Duration(s.failureLimit.WindowMinutes) * time.Minute
+	if _, err := s.tokenRepo.IncrementAuthFailures(ctx, clientIP, window); err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to record auth failure for %s: %v", clientIP, err))
+	}
+}
+
+// GetTokenStats returns token counts for one page of the token store, resuming
+// from the cursor returned by the previous call.
+func (s *AuthService) GetTokenStats(ctx context.Context, req *dto.TokenStatsRequest) (*dto.TokenStatsResponse, error) {
+	var cursor uint64
+	if req.Cursor != "" {
+		parsed, err := strconv.ParseUint(req.Cursor, 10, 64)
+		if err != nil {
+			return nil, errors.ErrInvalidRequest
+		}
+		cursor = parsed
+	}
+
+	count := int64(req.Count)
+	if count <= 0 {
+		count = 500
+	}
+
+	page, err := s.tokenRepo.ScanTokenStats(ctx, cursor, count)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to scan token stats: %v", err))
+		return nil, errors.ErrServiceUnavailable
+	}
+
+	resp := &dto.TokenStatsResponse{
+		AccessTokens:      page.AccessTokens,
+		RefreshTokens:     page.RefreshTokens,
+		BlacklistedTokens: page.BlacklistedTokens,
+		HasMore:           page.NextCursor != 0,
+	}
+	if resp.HasMore {
+		resp.NextCursor = strconv.FormatUint(page.NextCursor, 10)
+	}
+
+	return resp, nil
+}
+
+// JWKS returns the public keys clients use to verify access tokens locally.
+func (s *AuthService) JWKS() jwt.JWKSet {
+	return s.jwtManager.JWKS()
+}
+
+// revokeOnRefreshTokenReuse treats a replayed refresh token as compromised and
+// revokes every token issued to the user, forcing a fresh login.
+func (s *AuthService) revokeOnRefreshTokenReuse(ctx context.Context, userID string) error {
+	s.logger.Warn("Refresh token reuse detected, revoking all tokens for user " + userID)
+
+	if userID != "" {
+		if err := s.tokenRepo.DeleteUserTokens(ctx, userID); err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to revoke tokens after refresh token reuse: %v", err))
+		}
+	}
+
+	return errors.ErrRefreshTokenReuse
+}
+
 func (s *AuthService) Logout(ctx context.Context, req *dto.LogoutRequest) error {
 	s.logger.Info("Processing logout")
 
@@ -309,11 +570,140 @@ func (s *AuthService) Logout(ctx context.Context, req *dto.LogoutRequest) error
 	return nil
 }
 
+// LogoutAll signs the user out of every session. Unlike Logout it needs only
+// the user ID, so it works from a session whose access token has expired.
+// Refresh tokens are blacklisted as well as deleted so a copy captured before
+// the logout cannot be replayed. It returns the number of sessions ended.
+func (s *AuthService) LogoutAll(ctx context.Context, userID string) (int, error) {
+	if strings.TrimSpace(userID) == "" {
+		return 0, errors.ErrInvalidRequest
+	}
+
+	s.logger.Info(fmt.Sprintf("Processing logout from all devices for user: %s", userID))
+
+	refreshTokens, err := s.tokenRepo.ListUserRefreshTokens(ctx, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to list user refresh tokens: %v", err))
+		return 0, errors.ErrTokenDeletion
+	}
+
+	ttl := time.Duration(s.jwtConfig.RefreshTokenTTL) * time.Hour
+	for _, token := range refreshTokens {
+		if err := s.tokenRepo.BlacklistToken(ctx, token, ttl); err != nil {
+			s.logger.Warn(fmt.Sprintf("Failed to blacklist refresh token: %v", err))
+		}
+	}
+
+	if err := s.tokenRepo.DeleteUserTokens(ctx, userID); err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to delete user tokens: %v", err))
+		return 0, errors.ErrTokenDeletion
+	}
+
+	return len(refreshTokens), nil
+}
+
+// ListSessions returns the user's signed-in sessions, newest first.
+func (s *AuthService) ListSessions(ctx context.Context, userID string) ([]*dto.SessionResponse, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	tokens, err := s.tokenRepo.ListUserSessionTokens(ctx, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to list user sessions: %v", err))
+		return nil, errors.ErrServiceUnavailable
+	}
+
+	byID := make(map[string]*dto.SessionResponse)
+	sessions := make([]*dto.SessionResponse, 0)
+	for _, token := range tokens {
+		id := sessionIDOf(token)
+		session, ok := byID[id]
+		if !ok {
+			session = &dto.SessionResponse{ID: id, CreatedAt: token.CreatedAt, ExpiresAt: token.ExpiresAt}
+			byID[id] = session
+			sessions = append(sessions, session)
+		}
+
+		session.TokenTypes = append(session.TokenTypes, token.TokenType)
+		if token.CreatedAt.Before(session.CreatedAt) {
+			session.CreatedAt = token.CreatedAt
+		}
+		if token.ExpiresAt.After(session.ExpiresAt) {
+			session.ExpiresAt = token.ExpiresAt
+		}
+		if session.IP == "" {
+			session.IP = token.IP
+		}
+		if session.UserAgent == "" {
+			session.UserAgent = token.UserAgent
+		}
+	}
+
+	for _, session := range sessions {
+		sort.Strings(session.TokenTypes)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+// RevokeSession signs a single session out, blacklisting its tokens so copies
+// of them cannot be used until they would have expired anyway.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if strings.TrimSpace(userID) == "" || strings.TrimSpace(sessionID) == "" {
+		return errors.ErrInvalidRequest
+	}
+
+	tokens, err := s.tokenRepo.ListUserSessionTokens(ctx, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to list user sessions: %v", err))
+		return errors.ErrServiceUnavailable
+	}
+
+	revoked := 0
+	for _, token := range tokens {
+		if sessionIDOf(token) != sessionID {
+			continue
+		}
+
+		if ttl := time.Until(token.ExpiresAt); ttl > 0 {
+			if err := s.tokenRepo.BlacklistToken(ctx, token.Token, ttl); err != nil {
+				s.logger.Warn(fmt.Sprintf("Failed to blacklist session token: %v", err))
+			}
+		}
+		if err := s.tokenRepo.DeleteToken(ctx, token.Token); err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to delete session token: %v", err))
+			return errors.ErrTokenDeletion
+		}
+		revoked++
+	}
+
+	if revoked == 0 {
+		return errors.ErrSessionNotFound
+	}
+
+	s.logger.Info(fmt.Sprintf("Revoked session %s for user: %s", sessionID, userID))
+	return nil
+}
+
+// sessionIDOf returns the token's session ID. Tokens issued before sessions
+// were tracked get a stable ID derived from the token, one per token.
+func sessionIDOf(token *entities.SessionToken) string {
+	if token.SessionID != "" {
+		return token.SessionID
+	}
+	sum := sha256.Sum256([]byte(token.Token))
+	return "legacy-" + base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
 // Register creates a user in user-service (email/password) and returns JWT tokens.
 func (s *AuthService) Register(ctx context.Context, email, password, name string) (*dto.RegisterResponse, error) {
 	s.logger.Info(fmt.Sprintf("Registering user with email: %s", email))
 
-	userResp, err := s.userClient.CreateUser(ctx, "", email, name, "", password)
+	userResp, err := s.userClient.CreateUser(ctx, "", email, name, "", password, false)
 	if err != nil {
 		if st, ok := status.FromError(err); ok && st.Code() == codes.AlreadyExists {
 			return nil, errors.ErrUserAlreadyExists
@@ -328,6 +718,7 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 		Name:          userResp.GetName(),
 		Picture:       userResp.GetPicture(),
 		VerifiedEmail: true,
+		Role:          userResp.GetRole(),
 	}
 
 	tokenPair, err := s.generateTokenPair(userInfo)
@@ -336,7 +727,7 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 		return nil, errors.ErrTokenGeneration
 	}
 
-	if err := s.storeTokens(ctx, tokenPair, userInfo); err != nil {
+	if err := s.storeTokens(ctx, tokenPair, userInfo, entities.ClientInfo{}); err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to store tokens for user %s: %v", userInfo.Email, err))
 		return nil, errors.ErrTokenStorage
 	}
@@ -376,6 +767,7 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*dto.L
 		Name:          userResp.GetName(),
 		Picture:       userResp.GetPicture(),
 		VerifiedEmail: true,
+		Role:          userResp.GetRole(),
 	}
 
 	tokenPair, err := s.generateTokenPair(userInfo)
@@ -384,7 +776,7 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*dto.L
 		return nil, errors.ErrTokenGeneration
 	}
 
-	if err := s.storeTokens(ctx, tokenPair, userInfo); err != nil {
+	if err := s.storeTokens(ctx, tokenPair, userInfo, entities.ClientInfo{}); err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to store tokens for user %s: %v", userInfo.Email, err))
 		return nil, errors.ErrTokenStorage
 	}
@@ -431,9 +823,63 @@ func (s *AuthService) ValidateToken(ctx context.Context, token string) (*dto.Tok
 		Valid:  true,
 		UserID: claims.UserID,
 		Email:  claims.Email,
+		Role:   claims.Role,
 	}, nil
 }
 
+// maxIntrospectBatch caps the number of tokens one IntrospectTokens call checks.
+const maxIntrospectBatch = 100
+
+// IntrospectTokens reports whether each access token is active. Tokens are
+// checked independently, so a malformed or revoked token only marks its own
+// entry inactive; the blacklist and store lookups share one Redis pipeline.
+func (s *AuthService) IntrospectTokens(ctx context.Context, tokens []string) ([]*dto.TokenIntrospection, error) {
+	if len(tokens) == 0 || len(tokens) > maxIntrospectBatch {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	results := make([]*dto.TokenIntrospection, len(tokens))
+	claims := make([]*entities.TokenClaims, len(tokens))
+	var candidates []string
+	var positions []int
+	for i, token := range tokens {
+		results[i] = &dto.TokenIntrospection{}
+
+		tokenClaims, err := s.jwtManager.ValidateToken(token)
+		if err != nil || tokenClaims.Type != "access" {
+			continue
+		}
+		claims[i] = tokenClaims
+		candidates = append(candidates, token)
+		positions = append(positions, i)
+	}
+	if len(candidates) == 0 {
+		return results, nil
+	}
+
+	states, err := s.tokenRepo.GetAccessTokenStates(ctx, candidates)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to look up token states: %v", err))
+		return nil, errors.ErrTokenValidation
+	}
+
+	for j, state := range states {
+		if state == nil || state.Blacklisted || state.Data == nil {
+			continue
+		}
+		i := positions[j]
+		expiresAt := state.Data.ExpiresAt
+		results[i] = &dto.TokenIntrospection{
+			Active:    true,
+			UserID:    claims[i].UserID,
+			Email:     claims[i].Email,
+			ExpiresAt: &expiresAt,
+		}
+	}
+
+	return results, nil
+}
+
 func (s *AuthService) generateTokenPair(userInfo *entities.GoogleUserInfo) (*entities.TokenPair, error) {
 	accessTokenTTL := time.Duration(s.jwtConfig.AccessTokenTTL) * time.Minute
 	refreshTokenTTL := time.Duration(s.jwtConfig.RefreshTokenTTL) * time.Hour
@@ -441,6 +887,7 @@ func (s *AuthService) generateTokenPair(userInfo *entities.GoogleUserInfo) (*ent
 	accessClaims := &entities.TokenClaims{
 		UserID: userInfo.ID,
 		Email:  userInfo.Email,
+		Role:   userInfo.Role,
 		Type:   "access",
 	}
 	accessToken, err := s.jwtManager.GenerateToken(accessClaims, accessTokenTTL)
@@ -451,6 +898,7 @@ func (s *AuthService) generateTokenPair(userInfo *entities.GoogleUserInfo) (*ent
 	refreshClaims := &entities.TokenClaims{
 		UserID: userInfo.ID,
 		Email:  userInfo.Email,
+		Role:   userInfo.Role,
 		Type:   "refresh",
 	}
 	refreshToken, err := s.jwtManager.GenerateToken(refreshClaims, refreshTokenTTL)
@@ -467,36 +915,60 @@ func (s *AuthService) generateTokenPair(userInfo *entities.GoogleUserInfo) (*ent
 	}, nil
 }
 
-func (s *AuthService) storeTokens(ctx context.Context, tokenPair *entities.TokenPair, userInfo *entities.GoogleUserInfo) error {
-	now := time.Now()
-	storedToken := &entities.StoredToken{
-		UserID:    userInfo.ID,
-		Email:     userInfo.Email,
-		CreatedAt: now,
-		ExpiresAt: tokenPair.ExpiresAt,
+func (s *AuthService) storeTokens(ctx context.Context, tokenPair *entities.TokenPair, userInfo *entities.GoogleUserInfo, client entities.ClientInfo) error {
+	sessionID, err := generateSecureToken(16)
+	if err != nil {
+		return fmt.Errorf("failed to generate session id: %w", err)
 	}
+	accessToken, refreshToken := s.sessionTokenData(userInfo, tokenPair, sessionID, client)
 
 	accessTTL := time.Duration(s.jwtConfig.AccessTokenTTL) * time.Minute
-	if err := s.tokenRepo.StoreAccessToken(ctx, tokenPair.AccessToken, storedToken, accessTTL); err != nil {
+	if err := s.tokenRepo.StoreAccessToken(ctx, tokenPair.AccessToken, accessToken, accessTTL); err != nil {
 		return fmt.Errorf("failed to store access token: %w", err)
 	}
 
 	refreshTTL := time.Duration(s.jwtConfig.RefreshTokenTTL) * time.Hour
-	if err := s.tokenRepo.StoreRefreshToken(ctx, tokenPair.RefreshToken, storedToken, refreshTTL); err != nil {
+	if err := s.tokenRepo.StoreRefreshToken(ctx, tokenPair.RefreshToken, refreshToken, refreshTTL); err != nil {
 		return fmt.Errorf("failed to store refresh token: %w", err)
 	}
 
 	return nil
 }
 
+// sessionTokenData builds the stored metadata for a new token pair. Each
+// token records its own expiry so session listings are accurate.
+func (s *AuthService) sessionTokenData(userInfo *entities.GoogleUserInfo, tokenPair *entities.TokenPair, sessionID string, client entities.ClientInfo) (*entities.StoredToken, *entities.StoredToken) {
+	now := time.Now()
+	access := &entities.StoredToken{
+		UserID:    userInfo.ID,
+		Email:     userInfo.Email,
+		Role:      userInfo.Role,
+		SessionID: sessionID,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		CreatedAt: now,
+		ExpiresAt: tokenPair.ExpiresAt,
+	}
+
+	refresh := *access
+	refresh.ExpiresAt = now.Add(time.Duration(s.jwtConfig.RefreshTokenTTL) * time.Hour)
+
+	return access, &refresh
+}
+
 func (s *AuthService) ensureUserExists(ctx context.Context, googleUser *entities.GoogleUserInfo) (*entities.GoogleUserInfo, error) {
-	createResp, err := s.userClient.CreateUser(ctx, googleUser.ID, googleUser.Email, googleUser.Name, googleUser.Picture, "")
+	createResp, err := s.userClient.CreateUser(ctx, googleUser.ID, googleUser.Email, googleUser.Name, googleUser.Picture, "", googleUser.VerifiedEmail)
 	if err == nil {
 		return mergeUserInfo(createResp, googleUser), nil
 	}
 
 	if st, ok := status.FromError(err); ok && st.Code() == codes.AlreadyExists {
-		existingUser, getErr := s.userClient.GetUserByEmail(ctx, googleUser.Email)
+		// Signing in again restores an account the user deactivated; one an
+		// admin deactivated is refused.
+		existingUser, getErr := s.userClient.GetUserByEmail(ctx, googleUser.Email, true)
+		if st, ok := status.FromError(getErr); ok && st.Code() == codes.PermissionDenied {
+			return nil, errors.ErrAccountDeactivated
+		}
 		if getErr != nil {
 			s.logger.Error(fmt.Sprintf("User exists but fetch by email failed: %v", getErr))
 			return nil, errors.ErrServiceUnavailable
@@ -519,6 +991,7 @@ func mergeUserInfo(result UserInfoResult, fallback *entities.GoogleUserInfo) *en
 		Name:          result.GetName(),
 		Picture:       result.GetPicture(),
 		VerifiedEmail: true,
+		Role:          result.GetRole(),
 	}
 
 	if user.Name == "" && fallback != nil {
@@ -531,6 +1004,14 @@ func mergeUserInfo(result UserInfoResult, fallback *entities.GoogleUserInfo) *en
 	return user
 }
 
+// stateProvider treats states stored before provider tracking existed as Google.
+func stateProvider(state *entities.OAuthState) string {
+	if state.Provider == "" {
+		return domainServices.ProviderGoogle
+	}
+	return state.Provider
+}
+
 func normalizeOAuthPlatform(req *dto.GoogleAuthURLRequest) (entities.OAuthPlatform, error) {
 	if req == nil {
 		return entities.OAuthPlatformWeb, nil
@@ -635,8 +1116,7 @@ func verifyPKCE(codeVerifier, codeChallenge, codeChallengeMethod string) error {
 
 	switch method {
 	case "S256":
-		hash := sha256.Sum256([]byte(verifier))
-		expected := base64.RawURLEncoding.EncodeToString(hash[:])
+		expected := pkceS256Challenge(verifier)
 		if subtle.ConstantTimeCompare([]byte(expected), []byte(codeChallenge)) != 1 {
 			return errors.ErrInvalidCodeVerifier
 		}
@@ -651,6 +1131,11 @@ func verifyPKCE(codeVerifier, codeChallenge, codeChallengeMethod string) error {
 	return nil
 }
 
+func pkceS256Challenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
 func generateSecureToken(size int) (string, error) {
 	buf := make([]byte, size)
 	if _, err := rand.Read(buf); err != nil {
@@ -712,7 +1197,7 @@ func (s *AuthService) isAllowedEmailDomain(email string) bool {
 		return false
 	}
 
-	domain := strings.ToLower(parts[1])
+	domain := strings.TrimSpace(parts[1])
 	for _, allowed := range s.googleConfig.AllowedDomains {
 		if strings.EqualFold(strings.TrimSpace(allowed), domain) {
 			return true
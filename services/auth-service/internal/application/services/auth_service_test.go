@@ -0,0 +1,1039 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/errors"
+	"auth-service/internal/application/services/dto"
+	"auth-service/internal/config"
+	"auth-service/internal/domain/entities"
+	"auth-service/internal/domain/repositories"
+	domainServices "auth-service/internal/domain/services"
+	"auth-service/pkg/jwt"
+	"auth-service/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockTokenRepo struct {
+	states      map[string]*entities.OAuthState
+	authCodes   map[string]*entities.AuthCodePayload
+	tokens      map[string]*entities.StoredToken
+	blacklisted map[string]string
+	refresh     map[string]bool
+	scanKeys    []string
+	failures    map[string]int64
+	attempts    []authAttempt
+	// getDelErr simulates Redis being unreachable on single-use lookups.
+	getDelErr error
+}
+
+type authAttempt struct {
+	userID, ip, userAgent string
+	success               bool
+}
+
+var _ repositories.TokenRepository = (*mockTokenRepo)(nil)
+
+func newMockTokenRepo() *mockTokenRepo {
+	return &mockTokenRepo{
+		states:      make(map[string]*entities.OAuthState),
+		authCodes:   make(map[string]*entities.AuthCodePayload),
+		tokens:      make(map[string]*entities.StoredToken),
+		blacklisted: make(map[string]string),
+		refresh:     make(map[string]bool),
+		failures:    make(map[string]int64),
+	}
+}
+
+func (m *mockTokenRepo) StoreAuthCode(ctx context.Context, authCode string, payload *entities.AuthCodePayload, ttl time.Duration) error {
+	m.authCodes[authCode] = payload
+	return nil
+}
+
+func (m *mockTokenRepo) GetAndDeleteAuthCode(ctx context.Context, authCode string) (*entities.AuthCodePayload, error) {
+	if m.getDelErr != nil {
+		return nil, m.getDelErr
+	}
+	payload, ok := m.authCodes[authCode]
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve auth code payload: %w", repositories.ErrAuthCodeNotFound)
+	}
+	delete(m.authCodes, authCode)
+	return payload, nil
+}
+
+func (m *mockTokenRepo) StoreState(ctx context.Context, state string, payload *entities.OAuthState, ttl time.Duration) error {
+	m.states[state] = payload
+	return nil
+}
+
+func (m *mockTokenRepo) GetAndDeleteState(ctx context.Context, state string) (*entities.OAuthState, error) {
+	if m.getDelErr != nil {
+		return nil, m.getDelErr
+	}
+	payload, ok := m.states[state]
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve oauth state: %w", repositories.ErrStateNotFound)
+	}
+	delete(m.states, state)
+	return payload, nil
+}
+
+func (m *mockTokenRepo) StoreAccessToken(ctx context.Context, token string, data *entities.StoredToken, ttl time.Duration) error {
+	m.tokens[token] = data
+	return nil
+}
+
+func (m *mockTokenRepo) StoreRefreshToken(ctx context.Context, token string, data *entities.StoredToken, ttl time.Duration) error {
+	m.tokens[token] = data
+	m.refresh[token] = true
+	return nil
+}
+
+func (m *mockTokenRepo) GetTokenData(ctx context.Context, token string) (*entities.StoredToken, error) {
+	data, ok := m.tokens[token]
+	if !ok {
+		return nil, fmt.Errorf("token not found")
+	}
+	return data, nil
+}
+
+func (m *mockTokenRepo) GetAccessTokenStates(ctx context.Context, tokens []string) ([]*entities.TokenState, error) {
+	states := make([]*entities.TokenState, len(tokens))
+	for i, token := range tokens {
+		state := &entities.TokenState{Blacklisted: m.blacklisted[token] != ""}
+		if data, ok := m.tokens[token]; ok && !m.refresh[token] {
+			state.Data = data
+		}
+		states[i] = state
+	}
+	return states, nil
+}
+
+func (m *mockTokenRepo) DeleteToken(ctx context.Context, token string) error {
+	delete(m.tokens, token)
+	return nil
+}
+
+func (m *mockTokenRepo) DeleteUserTokens(ctx context.Context, userID string) error {
+	for token, data := range m.tokens {
+		if data.UserID == userID {
+			delete(m.tokens, token)
+		}
+	}
+	return nil
+}
+
+func (m *mockTokenRepo) ListUserSessionTokens(ctx context.Context, userID string) ([]*entities.SessionToken, error) {
+	var tokens []*entities.SessionToken
+	for token, data := range m.tokens {
+		if data.UserID != userID {
+			continue
+		}
+		tokenType := "access"
+		if m.refresh[token] {
+			tokenType = "refresh"
+		}
+		tokens = append(tokens, &entities.SessionToken{
+			Token:     token,
+			TokenType: tokenType,
+			SessionID: data.SessionID,
+			IP:        data.IP,
+			UserAgent: data.UserAgent,
+			CreatedAt: data.CreatedAt,
+			ExpiresAt: data.ExpiresAt,
+		})
+	}
+	return tokens, nil
+}
+
+func (m *mockTokenRepo) ListUserRefreshTokens(ctx context.Context, userID string) ([]string, error) {
+	var tokens []string
+	for token, data := range m.tokens {
+		if m.refresh[token] && data.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *mockTokenRepo) RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *entities.StoredToken, ttl time.Duration) error {
+	if _, ok := m.blacklisted[oldToken]; ok {
+		return repositories.ErrTokenAlreadyRotated
+	}
+	m.blacklisted[oldToken] = repositories.BlacklistReasonRotated
+	delete(m.tokens, oldToken)
+	m.tokens[newToken] = data
+	m.refresh[newToken] = true
+	return nil
+}
+
+// ScanTokenStats pages over scanKeys, using the cursor as an offset. Like a
+// Redis SCAN cursor it is opaque to callers and 0 once the scan is complete.
+func (m *mockTokenRepo) ScanTokenStats(ctx context.Context, cursor uint64, count int64) (*entities.TokenStatsPage, error) {
+	page := &entities.TokenStatsPage{}
+	end := cursor + uint64(count)
+	for i := cursor; i < end && i < uint64(len(m.scanKeys)); i++ {
+		switch {
+		case strings.HasPrefix(m.scanKeys[i], "auth:access:"):
+			page.AccessTokens++
+		case strings.HasPrefix(m.scanKeys[i], "auth:refresh:"):
+			page.RefreshTokens++
+		case strings.HasPrefix(m.scanKeys[i], "auth:blacklist:"):
+			page.BlacklistedTokens++
+		}
+	}
+	if end < uint64(len(m.scanKeys)) {
+		page.NextCursor = end
+	}
+	return page, nil
+}
+
+func (m *mockTokenRepo) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	_, ok := m.blacklisted[token]
+	return ok, nil
+}
+
+func (m *mockTokenRepo) GetBlacklistReason(ctx context.Context, token string) (string, error) {
+	return m.blacklisted[token], nil
+}
+
+func (m *mockTokenRepo) BlacklistToken(ctx context.Context, token string, ttl time.Duration) error {
+	m.blacklisted[token] = "blacklisted"
+	return nil
+}
+
+func (m *mockTokenRepo) GetAuthFailures(ctx context.Context, clientIP string) (int64, error) {
+	return m.failures[clientIP], nil
+}
+
+func (m *mockTokenRepo) IncrementAuthFailures(ctx context.Context, clientIP string, window time.Duration) (int64, error) {
+	m.failures[clientIP]++
+	return m.failures[clientIP], nil
+}
+
+func (m *mockTokenRepo) LogAuthAttempt(ctx context.Context, userID, ip, userAgent string, success bool) error {
+	m.attempts = append(m.attempts, authAttempt{userID: userID, ip: ip, userAgent: userAgent, success: success})
+	return nil
+}
+
+func (m *mockTokenRepo) ResetAuthFailures(ctx context.Context, clientIP string) error {
+	delete(m.failures, clientIP)
+	return nil
+}
+
+type mockOAuthProvider struct {
+	userInfo *entities.GoogleUserInfo
+	// challenge is the PKCE challenge from the last authorization URL; like a
+	// real provider, the exchange fails unless the verifier matches it.
+	challenge       string
+	challengeMethod string
+	verifier        string
+	exchangeErr     error
+}
+
+func (m *mockOAuthProvider) GetAuthURL(req *domainServices.AuthURLRequest) string {
+	m.challenge, m.challengeMethod = req.CodeChallenge, req.CodeChallengeMethod
+	return "https://provider.example.com/auth?state=" + req.State
+}
+
+func (m *mockOAuthProvider) ExchangeCodeForToken(ctx context.Context, code, codeVerifier string) (*entities.GoogleUserInfo, error) {
+	m.verifier = codeVerifier
+	if m.exchangeErr != nil {
+		return nil, m.exchangeErr
+	}
+	if m.challenge != "" && pkceS256Challenge(codeVerifier) != m.challenge {
+		return nil, fmt.Errorf("invalid_grant: code verifier mismatch")
+	}
+	info := *m.userInfo
+	return &info, nil
+}
+
+func (m *mockOAuthProvider) GetUserInfo(ctx context.Context, accessToken string) (*entities.GoogleUserInfo, error) {
+	info := *m.userInfo
+	return &info, nil
+}
+
+type testUserInfo struct {
+	id, email, name, picture, role string
+}
+
+func (u *testUserInfo) GetId() string      { return u.id }
+func (u *testUserInfo) GetEmail() string   { return u.email }
+func (u *testUserInfo) GetName() string    { return u.name }
+func (u *testUserInfo) GetPicture() string { return u.picture }
+func (u *testUserInfo) GetRole() string    { return u.role }
+
+type mockUserClient struct {
+	created []string
+	// verified lists the created emails flagged as provider-verified.
+	verified []string
+	// credentials is returned by ValidateCredentials when set.
+	credentials *testUserInfo
+	// existing makes CreateUser report AlreadyExists and is returned by
+	// GetUserByEmail.
+	existing *testUserInfo
+	// reactivated lists the emails looked up with reactivate set.
+	reactivated []string
+	// lookupErr is returned by GetUserByEmail when set.
+	lookupErr error
+	// users is what GetUser finds when set; IDs missing from it are
+	// NotFound. Unset, GetUser finds every ID with the "user" role.
+	users map[string]*testUserInfo
+}
+
+func (m *mockUserClient) CreateUser(ctx context.Context, id, email, name, picture, password string, emailVerified bool) (UserInfoResult, error) {
+	if m.existing != nil {
+		return nil, status.Error(codes.AlreadyExists, "user already exists")
+	}
+	m.created = append(m.created, email)
+	if emailVerified {
+		m.verified = append(m.verified, email)
+	}
+	return &testUserInfo{id: id, email: email, name: name, picture: picture}, nil
+}
+
+func (m *mockUserClient) GetUser(ctx context.Context, id string) (UserInfoResult, error) {
+	if m.users == nil {
+		return &testUserInfo{id: id, role: "user"}, nil
+	}
+	if user, ok := m.users[id]; ok {
+		return user, nil
+	}
+	return nil, status.Error(codes.NotFound, "user not found")
+}
+
+func (m *mockUserClient) GetUserByEmail(ctx context.Context, email string, reactivate bool) (UserInfoResult, error) {
+	if m.existing == nil {
+		return nil, fmt.Errorf("not implemented")
+	}
+	if reactivate {
+		m.reactivated = append(m.reactivated, email)
+	}
+	if m.lookupErr != nil {
+		return nil, m.lookupErr
+	}
+	return m.existing, nil
+}
+
+func (m *mockUserClient) ValidateCredentials(ctx context.Context, email, password string) (UserInfoResult, error) {
+	if m.credentials != nil {
+		return m.credentials, nil
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
+func newTestAuthService(repo *mockTokenRepo, provider *mockOAuthProvider, users *mockUserClient, allowedDomains []string) *AuthService {
+	return NewAuthService(
+		repo,
+		map[string]domainServices.OAuthProvider{domainServices.ProviderGoogle: provider},
+		users,
+		jwt.NewManager("01234567890123456789012345678901", "auth-service"),
+		config.JWTConfig{
+			Secret:          "01234567890123456789012345678901",
+			AccessTokenTTL:  15,
+			RefreshTokenTTL: 168,
+			Issuer:          "auth-service",
+		},
+		config.GoogleConfig{
+			DefaultWebRedirectURI:  "https://app.example.com/auth/callback",
+			AllowedWebRedirectURIs: []string{"https://app.example.com/auth/callback"},
+			AllowedDomains:         allowedDomains,
+		},
+		config.AuthFailureLimitConfig{MaxAttempts: 3, WindowMinutes: 15},
+		logger.New("error"),
+	)
+}
+
+func googleUser(email string) *mockOAuthProvider {
+	return &mockOAuthProvider{userInfo: &entities.GoogleUserInfo{
+		ID:            "google-123",
+		Email:         email,
+		Name:          "Test User",
+		VerifiedEmail: true,
+	}}
+}
+
+func startGoogleLogin(t *testing.T, svc *AuthService) string {
+	t.Helper()
+	resp, err := svc.GetGoogleAuthURL(context.Background(), &dto.GoogleAuthURLRequest{})
+	if err != nil {
+		t.Fatalf("GetGoogleAuthURL: %v", err)
+	}
+	return resp.State
+}
+
+func TestHandleGoogleCallbackAllowsListedDomain(t *testing.T) {
+	users := &mockUserClient{}
+	svc := newTestAuthService(newMockTokenRepo(), googleUser("dev@Example.COM"), users, []string{"example.com"})
+
+	state := startGoogleLogin(t, svc)
+	resp, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != nil {
+		t.Fatalf("HandleGoogleCallback: %v", err)
+	}
+	if resp.AuthCode == "" {
+		t.Fatal("expected an auth code for an allowed domain")
+	}
+	if len(users.created) != 1 {
+		t.Fatalf("expected user to be provisioned, got %d creates", len(users.created))
+	}
+	if len(users.verified) != 1 {
+		t.Fatal("expected a Google-verified email to be provisioned as verified")
+	}
+}
+
+func TestHandleGoogleCallbackReactivatesExistingAccount(t *testing.T) {
+	users := &mockUserClient{existing: &testUserInfo{id: "user-1", email: "dev@example.com", name: "Dev"}}
+	svc := newTestAuthService(newMockTokenRepo(), googleUser("dev@example.com"), users, nil)
+
+	state := startGoogleLogin(t, svc)
+	if _, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"}); err != nil {
+		t.Fatalf("HandleGoogleCallback: %v", err)
+	}
+	if len(users.reactivated) != 1 || users.reactivated[0] != "dev@example.com" {
+		t.Fatalf("expected the existing account to be looked up with reactivation, got %v", users.reactivated)
+	}
+}
+
+func TestHandleGoogleCallbackRefusesAdminDeactivatedAccount(t *testing.T) {
+	users := &mockUserClient{
+		existing:  &testUserInfo{id: "user-1", email: "dev@example.com"},
+		lookupErr: status.Error(codes.PermissionDenied, "account was deactivated by an administrator"),
+	}
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), users, nil)
+
+	state := startGoogleLogin(t, svc)
+	if _, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"}); err != errors.ErrAccountDeactivated {
+		t.Fatalf("expected ErrAccountDeactivated, got %v", err)
+	}
+}
+
+func TestRegisterCreatesUnverifiedUser(t *testing.T) {
+	users := &mockUserClient{}
+	svc := newTestAuthService(newMockTokenRepo(), googleUser("dev@example.com"), users, nil)
+
+	if _, err := svc.Register(context.Background(), "new@example.com", "password123", "New"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if len(users.created) != 1 || len(users.verified) != 0 {
+		t.Fatalf("expected one unverified user, got created=%v verified=%v", users.created, users.verified)
+	}
+}
+
+func TestHandleGoogleCallbackRejectsUnlistedDomain(t *testing.T) {
+	users := &mockUserClient{}
+	svc := newTestAuthService(newMockTokenRepo(), googleUser("someone@gmail.com"), users, []string{"example.com"})
+
+	state := startGoogleLogin(t, svc)
+	_, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != errors.ErrEmailDomainNotAllowed {
+		t.Fatalf("expected ErrEmailDomainNotAllowed, got %v", err)
+	}
+	if len(users.created) != 0 {
+		t.Fatal("expected no user to be provisioned for a rejected domain")
+	}
+}
+
+func TestHandleGoogleCallbackEmptyAllowlistAllowsAnyDomain(t *testing.T) {
+	svc := newTestAuthService(newMockTokenRepo(), googleUser("someone@gmail.com"), &mockUserClient{}, nil)
+
+	state := startGoogleLogin(t, svc)
+	if _, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"}); err != nil {
+		t.Fatalf("expected any domain to be allowed with an empty allowlist, got %v", err)
+	}
+}
+
+func TestHandleGoogleCallbackRejectsUnverifiedEmail(t *testing.T) {
+	users := &mockUserClient{}
+	provider := googleUser("dev@example.com")
+	provider.exchangeErr = fmt.Errorf("google account dev@example.com: %w", domainServices.ErrEmailNotVerified)
+	svc := newTestAuthService(newMockTokenRepo(), provider, users, nil)
+
+	state := startGoogleLogin(t, svc)
+	_, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != errors.ErrEmailNotVerified {
+		t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+	}
+	if len(users.created) != 0 {
+		t.Fatal("expected no user to be provisioned for an unverified email")
+	}
+}
+
+func TestHandleGoogleCallbackReportsProviderTimeout(t *testing.T) {
+	provider := googleUser("dev@example.com")
+	provider.exchangeErr = fmt.Errorf("google code exchange after 8s: %w", domainServices.ErrOAuthTimeout)
+	svc := newTestAuthService(newMockTokenRepo(), provider, &mockUserClient{}, nil)
+
+	state := startGoogleLogin(t, svc)
+	_, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != errors.ErrOAuthTimeout {
+		t.Fatalf("expected ErrOAuthTimeout, got %v", err)
+	}
+}
+
+func TestHandleGoogleCallbackRejectsUnverifiedProfile(t *testing.T) {
+	provider := googleUser("dev@example.com")
+	provider.userInfo.VerifiedEmail = false
+	svc := newTestAuthService(newMockTokenRepo(), provider, &mockUserClient{}, nil)
+
+	state := startGoogleLogin(t, svc)
+	_, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != errors.ErrEmailNotVerified {
+		t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+	}
+}
+
+func TestHandleGoogleCallbackExpiredStateIsInvalid(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	state := startGoogleLogin(t, svc)
+	delete(repo.states, state) // expired in Redis
+
+	_, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != errors.ErrInvalidOAuthState {
+		t.Fatalf("expected ErrInvalidOAuthState, got %v", err)
+	}
+}
+
+func TestHandleGoogleCallbackStateLookupFailureIsUnavailable(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	state := startGoogleLogin(t, svc)
+	repo.getDelErr = fmt.Errorf("failed to retrieve oauth state: connection refused")
+
+	_, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != errors.ErrServiceUnavailable {
+		t.Fatalf("expected ErrServiceUnavailable, got %v", err)
+	}
+}
+
+func TestExchangeAuthCodeLookupFailureIsNotCountedAsFailure(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	repo.getDelErr = fmt.Errorf("failed to retrieve auth code payload: connection refused")
+
+	_, err := svc.ExchangeAuthCode(context.Background(), &dto.ExchangeAuthCodeRequest{AuthCode: "code-1", ClientIP: "203.0.113.7"})
+	if err != errors.ErrServiceUnavailable {
+		t.Fatalf("expected ErrServiceUnavailable, got %v", err)
+	}
+	if repo.failures["203.0.113.7"] != 0 {
+		t.Fatalf("expected a Redis failure not to count against the client, got %d", repo.failures["203.0.113.7"])
+	}
+}
+
+func issueTokens(t *testing.T, svc *AuthService) *entities.TokenPair {
+	t.Helper()
+	user := &entities.GoogleUserInfo{ID: "user-1", Email: "dev@example.com"}
+	pair, err := svc.generateTokenPair(user)
+	if err != nil {
+		t.Fatalf("generateTokenPair: %v", err)
+	}
+	if err := svc.storeTokens(context.Background(), pair, user, entities.ClientInfo{}); err != nil {
+		t.Fatalf("storeTokens: %v", err)
+	}
+	return pair
+}
+
+func TestRefreshTokenRotatesRefreshToken(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	pair := issueTokens(t, svc)
+
+	resp, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if repo.blacklisted[pair.RefreshToken] != repositories.BlacklistReasonRotated {
+		t.Fatalf("expected old refresh token to be marked rotated, got %q", repo.blacklisted[pair.RefreshToken])
+	}
+	if _, ok := repo.tokens[resp.Tokens.RefreshToken]; !ok {
+		t.Fatal("expected new refresh token to be stored")
+	}
+}
+
+func TestRefreshTokenReadsCurrentRole(t *testing.T) {
+	repo := newMockTokenRepo()
+	users := &mockUserClient{users: map[string]*testUserInfo{"user-1": {id: "user-1", email: "dev@example.com", role: "admin"}}}
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), users, nil)
+	pair := issueTokens(t, svc)
+
+	// The user is promoted after signing in; the refreshed tokens carry it.
+	resp, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if role := repo.tokens[resp.Tokens.AccessToken].Role; role != "admin" {
+		t.Fatalf("expected the refreshed access token to carry the current role, got %q", role)
+	}
+	claims, err := svc.jwtManager.ValidateToken(resp.Tokens.AccessToken)
+	if err != nil || claims.Role != "admin" {
+		t.Fatalf("expected an admin claim, got %+v, %v", claims, err)
+	}
+
+	// Demoted again, the next refresh drops the role.
+	users.users["user-1"].role = "user"
+	resp, err = svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: resp.Tokens.RefreshToken})
+	if err != nil {
+		t.Fatalf("second RefreshToken: %v", err)
+	}
+	if role := repo.tokens[resp.Tokens.AccessToken].Role; role != "user" {
+		t.Fatalf("expected the demotion to apply on refresh, got %q", role)
+	}
+}
+
+func TestRefreshTokenRejectsInactiveUser(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{users: map[string]*testUserInfo{}}, nil)
+	pair := issueTokens(t, svc)
+
+	if _, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken}); err != errors.ErrInvalidRefreshToken {
+		t.Fatalf("expected ErrInvalidRefreshToken for a deactivated user, got %v", err)
+	}
+}
+
+func TestRefreshTokenReplayRevokesAllUserTokens(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	pair := issueTokens(t, svc)
+
+	if _, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken}); err != nil {
+		t.Fatalf("first RefreshToken: %v", err)
+	}
+
+	_, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+	if err != errors.ErrRefreshTokenReuse {
+		t.Fatalf("expected ErrRefreshTokenReuse on replay, got %v", err)
+	}
+	for token, data := range repo.tokens {
+		if data.UserID == "user-1" {
+			t.Fatalf("expected all user tokens to be revoked, found %q", token)
+		}
+	}
+}
+
+func TestRefreshTokenConcurrentRotationIsTreatedAsReuse(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	pair := issueTokens(t, svc)
+
+	// Simulate another request rotating the token between the blacklist check
+	// and the rotation itself.
+	repo.blacklisted[pair.RefreshToken] = ""
+
+	_, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+	if err != errors.ErrRefreshTokenReuse {
+		t.Fatalf("expected ErrRefreshTokenReuse, got %v", err)
+	}
+}
+
+func TestGetTokenStatsPagesThroughAllKeys(t *testing.T) {
+	repo := newMockTokenRepo()
+	for i := 0; i < 5; i++ {
+		repo.scanKeys = append(repo.scanKeys, fmt.Sprintf("auth:access:a%d", i))
+	}
+	for i := 0; i < 4; i++ {
+		repo.scanKeys = append(repo.scanKeys, fmt.Sprintf("auth:refresh:r%d", i))
+	}
+	for i := 0; i < 3; i++ {
+		repo.scanKeys = append(repo.scanKeys, fmt.Sprintf("auth:blacklist:b%d", i))
+	}
+	repo.scanKeys = append(repo.scanKeys, "auth:state:s0", "auth:user_tokens:u0")
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	var access, refresh, blacklisted int64
+	var pages int
+	cursor := ""
+	for {
+		pages++
+		if pages > 10 {
+			t.Fatal("token stats scan did not terminate")
+		}
+		resp, err := svc.GetTokenStats(context.Background(), &dto.TokenStatsRequest{Cursor: cursor, Count: 4})
+		if err != nil {
+			t.Fatalf("GetTokenStats: %v", err)
+		}
+		access += resp.AccessTokens
+		refresh += resp.RefreshTokens
+		blacklisted += resp.BlacklistedTokens
+		if !resp.HasMore {
+			if resp.NextCursor != "" {
+				t.Fatalf("expected no cursor on the last page, got %q", resp.NextCursor)
+			}
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	if pages != 4 {
+		t.Fatalf("expected 4 pages, got %d", pages)
+	}
+	if access != 5 || refresh != 4 || blacklisted != 3 {
+		t.Fatalf("unexpected totals: access=%d refresh=%d blacklisted=%d", access, refresh, blacklisted)
+	}
+}
+
+func TestGetTokenStatsRejectsMalformedCursor(t *testing.T) {
+	svc := newTestAuthService(newMockTokenRepo(), googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	_, err := svc.GetTokenStats(context.Background(), &dto.TokenStatsRequest{Cursor: "abc"})
+	if err != errors.ErrInvalidRequest {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+}
+
+func TestLogoutAllRevokesEverySession(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	first := issueTokens(t, svc)
+	second := issueTokens(t, svc)
+
+	terminated, err := svc.LogoutAll(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+	if terminated != 2 {
+		t.Fatalf("expected 2 sessions terminated, got %d", terminated)
+	}
+	if len(repo.tokens) != 0 {
+		t.Fatalf("expected all tokens deleted, %d remain", len(repo.tokens))
+	}
+
+	for _, pair := range []*entities.TokenPair{first, second} {
+		if _, ok := repo.blacklisted[pair.RefreshToken]; !ok {
+			t.Fatal("expected refresh token to be blacklisted")
+		}
+		if _, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken}); err == nil {
+			t.Fatal("expected refresh with a logged-out token to fail")
+		}
+	}
+}
+
+func TestLogoutAllRequiresUserID(t *testing.T) {
+	svc := newTestAuthService(newMockTokenRepo(), googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	if _, err := svc.LogoutAll(context.Background(), " "); err != errors.ErrInvalidRequest {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+}
+
+func TestListSessionsGroupsTokensBySession(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	issueTokens(t, svc)
+	issueTokens(t, svc)
+
+	sessions, err := svc.ListSessions(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	for _, session := range sessions {
+		if session.ID == "" {
+			t.Fatal("expected an opaque session id")
+		}
+		if len(session.TokenTypes) != 2 || session.TokenTypes[0] != "access" || session.TokenTypes[1] != "refresh" {
+			t.Fatalf("expected access and refresh tokens per session, got %v", session.TokenTypes)
+		}
+		if !session.ExpiresAt.After(session.CreatedAt.Add(time.Hour)) {
+			t.Fatalf("expected session expiry to follow the refresh token, got %v", session.ExpiresAt)
+		}
+	}
+}
+
+func TestRefreshTokenKeepsSession(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	pair := issueTokens(t, svc)
+	sessionID := repo.tokens[pair.RefreshToken].SessionID
+
+	resp, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if got := repo.tokens[resp.Tokens.RefreshToken].SessionID; got != sessionID {
+		t.Fatalf("expected rotated token to stay in session %q, got %q", sessionID, got)
+	}
+}
+
+func TestRevokeSessionRevokesOnlyThatSession(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	revoked := issueTokens(t, svc)
+	kept := issueTokens(t, svc)
+	sessionID := repo.tokens[revoked.RefreshToken].SessionID
+
+	if err := svc.RevokeSession(context.Background(), "user-1", sessionID); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+
+	for _, token := range []string{revoked.AccessToken, revoked.RefreshToken} {
+		if _, ok := repo.tokens[token]; ok {
+			t.Fatal("expected revoked session tokens to be deleted")
+		}
+		if _, ok := repo.blacklisted[token]; !ok {
+			t.Fatal("expected revoked session tokens to be blacklisted")
+		}
+	}
+	if _, ok := repo.tokens[kept.RefreshToken]; !ok {
+		t.Fatal("expected other sessions to be kept")
+	}
+}
+
+func TestRevokeSessionUnknownID(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	issueTokens(t, svc)
+
+	if err := svc.RevokeSession(context.Background(), "user-1", "missing"); err != errors.ErrSessionNotFound {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestExchangeAuthCodeLocksOutAfterRepeatedFailures(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	req := &dto.ExchangeAuthCodeRequest{AuthCode: "bogus", ClientIP: "203.0.113.7"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.ExchangeAuthCode(context.Background(), req); err != errors.ErrInvalidGoogleCode {
+			t.Fatalf("attempt %d: expected ErrInvalidGoogleCode, got %v", i+1, err)
+		}
+	}
+
+	// A valid code is still refused once the limit is reached.
+	repo.authCodes["good"] = &entities.AuthCodePayload{User: &entities.GoogleUserInfo{ID: "user-1", Email: "dev@example.com"}}
+	_, err := svc.ExchangeAuthCode(context.Background(), &dto.ExchangeAuthCodeRequest{AuthCode: "good", ClientIP: "203.0.113.7"})
+	if err != errors.ErrTooManyAttempts {
+		t.Fatalf("expected ErrTooManyAttempts, got %v", err)
+	}
+	if _, ok := repo.authCodes["good"]; !ok {
+		t.Fatal("expected the auth code to be left unconsumed while locked out")
+	}
+
+	// Other clients are unaffected.
+	if _, err := svc.ExchangeAuthCode(context.Background(), &dto.ExchangeAuthCodeRequest{AuthCode: "good", ClientIP: "198.51.100.1"}); err != nil {
+		t.Fatalf("expected a different IP to be allowed, got %v", err)
+	}
+}
+
+func TestRefreshTokenSuccessResetsFailureCounter(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	pair := issueTokens(t, svc)
+	const ip = "203.0.113.7"
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: "not-a-jwt", ClientIP: ip}); err != errors.ErrInvalidRefreshToken {
+			t.Fatalf("attempt %d: expected ErrInvalidRefreshToken, got %v", i+1, err)
+		}
+	}
+	if repo.failures[ip] != 2 {
+		t.Fatalf("expected 2 recorded failures, got %d", repo.failures[ip])
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: pair.RefreshToken, ClientIP: ip}); err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if _, ok := repo.failures[ip]; ok {
+		t.Fatalf("expected failure counter to be cleared after success, got %d", repo.failures[ip])
+	}
+
+	// The cleared counter allows a full budget of failures again.
+	for i := 0; i < 3; i++ {
+		if _, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: "not-a-jwt", ClientIP: ip}); err != errors.ErrInvalidRefreshToken {
+			t.Fatalf("attempt %d after reset: expected ErrInvalidRefreshToken, got %v", i+1, err)
+		}
+	}
+	if _, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: "not-a-jwt", ClientIP: ip}); err != errors.ErrTooManyAttempts {
+		t.Fatalf("expected ErrTooManyAttempts, got %v", err)
+	}
+}
+
+func TestIntrospectTokensIsolatesPerTokenFailures(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	active := issueTokens(t, svc)
+	revoked := issueTokens(t, svc)
+	repo.blacklisted[revoked.AccessToken] = "blacklisted"
+
+	tokens := []string{active.AccessToken, "garbage", revoked.AccessToken, active.RefreshToken}
+	results, err := svc.IntrospectTokens(context.Background(), tokens)
+	if err != nil {
+		t.Fatalf("IntrospectTokens: %v", err)
+	}
+	if len(results) != len(tokens) {
+		t.Fatalf("expected %d results, got %d", len(tokens), len(results))
+	}
+
+	first := results[0]
+	if !first.Active || first.UserID != "user-1" || first.Email != "dev@example.com" || first.ExpiresAt == nil {
+		t.Fatalf("expected first token to be active with claims, got %+v", first)
+	}
+	for i, name := range map[int]string{1: "malformed", 2: "blacklisted", 3: "refresh"} {
+		if results[i].Active || results[i].UserID != "" {
+			t.Errorf("expected %s token to be inactive, got %+v", name, results[i])
+		}
+	}
+}
+
+func TestIntrospectTokensRejectsEmptyAndOversizedBatches(t *testing.T) {
+	svc := newTestAuthService(newMockTokenRepo(), googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	if _, err := svc.IntrospectTokens(context.Background(), nil); err != errors.ErrInvalidRequest {
+		t.Fatalf("expected ErrInvalidRequest for an empty batch, got %v", err)
+	}
+	if _, err := svc.IntrospectTokens(context.Background(), make([]string, maxIntrospectBatch+1)); err != errors.ErrInvalidRequest {
+		t.Fatalf("expected ErrInvalidRequest for an oversized batch, got %v", err)
+	}
+}
+
+func TestGoogleLoginUsesPKCEWithProvider(t *testing.T) {
+	provider := googleUser("dev@example.com")
+	svc := newTestAuthService(newMockTokenRepo(), provider, &mockUserClient{}, nil)
+
+	state := startGoogleLogin(t, svc)
+	if provider.challenge == "" || provider.challengeMethod != "S256" {
+		t.Fatalf("expected an S256 challenge on the auth URL, got %q/%q", provider.challenge, provider.challengeMethod)
+	}
+
+	if _, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"}); err != nil {
+		t.Fatalf("HandleGoogleCallback: %v", err)
+	}
+	if pkceS256Challenge(provider.verifier) != provider.challenge {
+		t.Fatal("expected the stored verifier to be sent with the code exchange")
+	}
+}
+
+func TestHandleGoogleCallbackRejectsMissingProviderVerifier(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	state := startGoogleLogin(t, svc)
+	repo.states[state].ProviderCodeVerifier = ""
+
+	_, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != errors.ErrInvalidOAuthState {
+		t.Fatalf("expected ErrInvalidOAuthState, got %v", err)
+	}
+}
+
+func TestHandleGoogleCallbackRejectsMismatchedProviderVerifier(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	state := startGoogleLogin(t, svc)
+	repo.states[state].ProviderCodeVerifier = strings.Repeat("x", 43)
+
+	_, err := svc.HandleGoogleCallback(context.Background(), &dto.GoogleCallbackRequest{State: state, Code: "code"})
+	if err != errors.ErrInvalidGoogleCode {
+		t.Fatalf("expected ErrInvalidGoogleCode, got %v", err)
+	}
+}
+
+func TestExchangeAuthCodeRecordsClientOnTokens(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	repo.authCodes["code-1"] = &entities.AuthCodePayload{User: &entities.GoogleUserInfo{ID: "user-1", Email: "dev@example.com"}}
+
+	resp, err := svc.ExchangeAuthCode(context.Background(), &dto.ExchangeAuthCodeRequest{
+		AuthCode:  "code-1",
+		ClientIP:  "203.0.113.7",
+		UserAgent: "Mozilla/5.0 (X11; Linux x86_64)",
+	})
+	if err != nil {
+		t.Fatalf("ExchangeAuthCode: %v", err)
+	}
+
+	for _, token := range []string{resp.Tokens.AccessToken, resp.Tokens.RefreshToken} {
+		stored := repo.tokens[token]
+		if stored == nil || stored.IP != "203.0.113.7" || stored.UserAgent != "Mozilla/5.0 (X11; Linux x86_64)" {
+			t.Fatalf("expected client context on stored token, got %+v", stored)
+		}
+	}
+	if len(repo.attempts) != 1 {
+		t.Fatalf("expected one logged attempt, got %d", len(repo.attempts))
+	}
+	if got := repo.attempts[0]; !got.success || got.userID != "user-1" || got.ip != "203.0.113.7" {
+		t.Fatalf("unexpected logged attempt: %+v", got)
+	}
+}
+
+func TestRefreshTokenUpdatesClientContext(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+	pair := issueTokens(t, svc)
+	repo.tokens[pair.RefreshToken].IP = "198.51.100.1"
+	repo.tokens[pair.RefreshToken].UserAgent = "old-agent"
+
+	resp, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{
+		RefreshToken: pair.RefreshToken,
+		ClientIP:     "203.0.113.7",
+	})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	stored := repo.tokens[resp.Tokens.RefreshToken]
+	if stored.IP != "203.0.113.7" {
+		t.Fatalf("expected refreshed session to record the new IP, got %q", stored.IP)
+	}
+	if stored.UserAgent != "old-agent" {
+		t.Fatalf("expected the previous user agent to be kept when none is sent, got %q", stored.UserAgent)
+	}
+}
+
+func TestFailedExchangeIsLogged(t *testing.T) {
+	repo := newMockTokenRepo()
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), &mockUserClient{}, nil)
+
+	if _, err := svc.ExchangeAuthCode(context.Background(), &dto.ExchangeAuthCodeRequest{AuthCode: "bogus", ClientIP: "203.0.113.7", UserAgent: "curl/8.0"}); err == nil {
+		t.Fatal("expected exchange with an unknown code to fail")
+	}
+	if len(repo.attempts) != 1 || repo.attempts[0].success || repo.attempts[0].userAgent != "curl/8.0" {
+		t.Fatalf("expected a failed attempt to be logged, got %+v", repo.attempts)
+	}
+}
+
+func TestLoginCarriesRoleThroughValidationAndRefresh(t *testing.T) {
+	repo := newMockTokenRepo()
+	admin := &testUserInfo{id: "admin-1", email: "admin@example.com", role: "admin"}
+	users := &mockUserClient{credentials: admin, users: map[string]*testUserInfo{"admin-1": admin}}
+	svc := newTestAuthService(repo, googleUser("dev@example.com"), users, nil)
+
+	login, err := svc.Login(context.Background(), "admin@example.com", "secret")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	validation, err := svc.ValidateToken(context.Background(), login.Tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if validation.Role != "admin" {
+		t.Fatalf("expected role admin from the access token, got %q", validation.Role)
+	}
+
+	refreshed, err := svc.RefreshToken(context.Background(), &dto.RefreshTokenRequest{RefreshToken: login.Tokens.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	validation, err = svc.ValidateToken(context.Background(), refreshed.Tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken after refresh: %v", err)
+	}
+	if validation.Role != "admin" {
+		t.Fatalf("expected refreshed access token to keep role admin, got %q", validation.Role)
+	}
+}
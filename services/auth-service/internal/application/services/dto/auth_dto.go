@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type OAuthPlatform string
 
 const (
@@ -35,6 +37,8 @@ type GoogleCallbackResponse struct {
 type ExchangeAuthCodeRequest struct {
 	AuthCode     string `json:"auth_code" binding:"required"`
 	CodeVerifier string `json:"code_verifier,omitempty"`
+	ClientIP     string `json:"-"`
+	UserAgent    string `json:"-"`
 }
 
 type ExchangeAuthCodeResponse struct {
@@ -44,6 +48,8 @@ type ExchangeAuthCodeResponse struct {
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
+	ClientIP     string `json:"-"`
+	UserAgent    string `json:"-"`
 }
 
 // NEW: Make refresh token response consistent with exchange response
@@ -60,6 +66,15 @@ type TokenValidationResponse struct {
 	Valid  bool   `json:"valid"`
 	UserID string `json:"user_id,omitempty"`
 	Email  string `json:"email,omitempty"`
+	Role   string `json:"role,omitempty"`
+}
+
+// TokenIntrospection is the result for one token of a batch introspection.
+type TokenIntrospection struct {
+	Active    bool       `json:"active"`
+	UserID    string     `json:"user_id,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 type UserInfo struct {
@@ -85,3 +100,29 @@ type LoginResponse struct {
 	User   *UserInfo  `json:"user"`
 	Tokens *TokenPair `json:"tokens"`
 }
+
+type TokenStatsRequest struct {
+	Cursor string `form:"cursor"`
+	Count  int    `form:"count,default=500" binding:"omitempty,min=1,max=5000"`
+}
+
+// TokenStatsResponse carries counts for a single page of the token store.
+// Callers sum pages, passing NextCursor back until HasMore is false.
+type TokenStatsResponse struct {
+	AccessTokens      int64  `json:"access_tokens"`
+	RefreshTokens     int64  `json:"refresh_tokens"`
+	BlacklistedTokens int64  `json:"blacklisted_tokens"`
+	NextCursor        string `json:"next_cursor,omitempty"`
+	HasMore           bool   `json:"has_more"`
+}
+
+// SessionResponse describes one signed-in device. ID is opaque and only
+// meaningful for revoking the session.
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	TokenTypes []string  `json:"token_types"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IP         string    `json:"ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"auth-service/internal/config"
+	"auth-service/pkg/tracing"
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	userv1 "github.com/nikitashilov/microblog_grpc/proto/user/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -38,6 +40,7 @@ func NewUserClient(addr string, tlsCfg config.GRPCTLSConfig) (*UserClient, error
 			Timeout:             5 * time.Second,
 			PermitWithoutStream: true,
 		}),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor(), requestid.UnaryClientInterceptor()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("connect to user gRPC service: %w", err)
@@ -50,16 +53,18 @@ func NewUserClient(addr string, tlsCfg config.GRPCTLSConfig) (*UserClient, error
 }
 
 // CreateUser creates a user in user-service (id optional for email/password signup).
-func (c *UserClient) CreateUser(ctx context.Context, id, email, name, picture, password string) (*userv1.User, error) {
+// emailVerified records that the identity provider already verified the email.
+func (c *UserClient) CreateUser(ctx context.Context, id, email, name, picture, password string, emailVerified bool) (*userv1.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
 	defer cancel()
 
 	req := &userv1.CreateUserRequest{
-		Id:       id,
-		Email:    email,
-		Name:     name,
-		Picture:  picture,
-		Password: password,
+		Id:            id,
+		Email:         email,
+		Name:          name,
+		Picture:       picture,
+		Password:      password,
+		EmailVerified: emailVerified,
 	}
 
 	resp, err := c.client.CreateUser(ctx, req)
@@ -88,13 +93,28 @@ func (c *UserClient) ValidateCredentials(ctx context.Context, email, password st
 	return resp, nil
 }
 
-// GetUserByEmail returns a user record by email.
-func (c *UserClient) GetUserByEmail(ctx context.Context, email string) (*userv1.User, error) {
+// GetUser returns an active user record by ID.
+func (c *UserClient) GetUser(ctx context.Context, id string) (*userv1.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
+	defer cancel()
+
+	resp, err := c.client.GetUser(ctx, &userv1.GetUserRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetUserByEmail returns a user record by email. With reactivate set, a
+// deactivated account is reactivated first.
+func (c *UserClient) GetUserByEmail(ctx context.Context, email string, reactivate bool) (*userv1.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, defaultUserTimeout)
 	defer cancel()
 
 	req := &userv1.GetUserByEmailRequest{
-		Email: email,
+		Email:      email,
+		Reactivate: reactivate,
 	}
 
 	resp, err := c.client.GetUserByEmail(ctx, req)
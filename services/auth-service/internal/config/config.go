@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -11,15 +12,23 @@ type Config struct {
 	Port                     string
 	GRPCPort                 string
 	Environment              string
+	MetricsEnabled           bool // exposes /metrics and records Prometheus metrics
 	LogLevel                 string
+	LogFormat                string // "json" or "text"; defaults to json in production
+	Tracing                  TracingConfig
+	StartupRetry             StartupRetryConfig
 	Server                   ServerConfig
 	Redis                    RedisConfig
 	Google                   GoogleConfig
+	GitHub                   GitHubConfig
 	JWT                      JWTConfig
+	AuthFailureLimit         AuthFailureLimitConfig
 	Services                 ServicesConfig
 	GRPCTLS                  GRPCTLSConfig
 	ServiceTransportSecurity string
 	InternalHTTPTrustMode    string
+	InternalServiceToken     string // X-Internal-Token required on /internal routes; empty closes them
+	CORS                     CORSConfig
 	EnableGRPCReflection     bool
 }
 
@@ -41,6 +50,15 @@ type ServerConfig struct {
 	IdleTimeout  int
 }
 
+// StartupRetryConfig bounds how long the service waits for its dependencies
+// to accept connections at startup.
+type StartupRetryConfig struct {
+	Attempts         int
+	InitialBackoffMs int
+	MaxBackoffMs     int
+	MaxWaitSeconds   int
+}
+
 type RedisConfig struct {
 	URL      string
 	Password string
@@ -48,28 +66,81 @@ type RedisConfig struct {
 }
 
 type GoogleConfig struct {
-	ClientID                  string
-	ClientSecret              string
-	RedirectURL               string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// AllowedRedirectURLs lists the provider callback URLs this deployment may
+	// use (e.g. staging and production). RedirectURL must be one of them.
+	AllowedRedirectURLs       []string
 	DefaultWebRedirectURI     string
 	AllowedWebRedirectURIs    []string
 	AllowedMobileRedirectURIs []string
 	AllowedDomains            []string
+	ExchangeTimeout           int // seconds, bounds the code exchange and profile lookup
+}
+
+// GitHubConfig configures the optional GitHub OAuth provider. It is enabled
+// when GITHUB_CLIENT_ID is set and reuses the Google redirect allowlists.
+type GitHubConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+func (g GitHubConfig) Enabled() bool {
+	return g.ClientID != ""
 }
 
 type JWTConfig struct {
+	Algorithm       string // HS256 or RS256
 	Secret          string
-	AccessTokenTTL  int // minutes
-	RefreshTokenTTL int // hours
+	PrivateKeyPath  string // PEM encoded RSA key, required for RS256
+	KeyID           string // optional kid; defaults to the key thumbprint
+	AccessTokenTTL  int    // minutes
+	RefreshTokenTTL int    // hours
 	Issuer          string
 }
 
+// AuthFailureLimitConfig throttles failed code exchanges and token refreshes
+// per client IP. MaxAttempts of 0 disables the limiter.
+type AuthFailureLimitConfig struct {
+	MaxAttempts   int
+	WindowMinutes int
+}
+
+// CORSConfig lists the browser origins allowed to call the service directly.
+// An empty list sends no CORS headers; "*" must be configured explicitly and
+// is rejected together with credentials.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+}
+
+// TracingConfig enables OpenTelemetry trace export. An empty Endpoint keeps
+// tracing a no-op apart from propagating incoming trace context.
+type TracingConfig struct {
+	Endpoint    string
+	SampleRatio float64
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
-		Port:        getEnv("PORT", "8081"),
-		GRPCPort:    getEnv("GRPC_PORT", "50051"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
+		Port:           getEnv("PORT", "8081"),
+		GRPCPort:       getEnv("GRPC_PORT", "50051"),
+		Environment:    getEnv("ENVIRONMENT", "development"),
+		MetricsEnabled: getEnvAsBool("METRICS_ENABLED", true),
+		LogLevel:       getEnv("LOG_LEVEL", "info"),
+		LogFormat:      getEnv("LOG_FORMAT", defaultLogFormat(getEnv("ENVIRONMENT", "development"))),
+		Tracing: TracingConfig{
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			SampleRatio: getEnvAsFloat("OTEL_TRACES_SAMPLE_RATIO", 1),
+		},
+		StartupRetry: StartupRetryConfig{
+			Attempts:         getEnvAsInt("STARTUP_RETRY_ATTEMPTS", 10),
+			InitialBackoffMs: getEnvAsInt("STARTUP_RETRY_INITIAL_BACKOFF_MS", 500),
+			MaxBackoffMs:     getEnvAsInt("STARTUP_RETRY_MAX_BACKOFF_MS", 5000),
+			MaxWaitSeconds:   getEnvAsInt("STARTUP_RETRY_MAX_WAIT_SECONDS", 60),
+		},
 		Server: ServerConfig{
 			ReadTimeout:  getEnvAsInt("SERVER_READ_TIMEOUT", 10),
 			WriteTimeout: getEnvAsInt("SERVER_WRITE_TIMEOUT", 10),
@@ -84,17 +155,31 @@ func Load() (*Config, error) {
 			ClientID:                  os.Getenv("GOOGLE_CLIENT_ID"),
 			ClientSecret:              os.Getenv("GOOGLE_CLIENT_SECRET"),
 			RedirectURL:               os.Getenv("GOOGLE_REDIRECT_URL"),
+			AllowedRedirectURLs:       parseCSV(getEnv("GOOGLE_ALLOWED_REDIRECT_URLS", "")),
 			DefaultWebRedirectURI:     getEnv("GOOGLE_DEFAULT_WEB_REDIRECT_URI", getEnv("FRONTEND_URL", "http://localhost:3000")+"/auth/callback"),
 			AllowedWebRedirectURIs:    parseCSV(getEnv("GOOGLE_ALLOWED_WEB_REDIRECT_URIS", "")),
 			AllowedMobileRedirectURIs: parseCSV(getEnv("GOOGLE_ALLOWED_MOBILE_REDIRECT_URIS", "")),
 			AllowedDomains:            parseCSV(getEnv("GOOGLE_ALLOWED_DOMAINS", "")),
+			ExchangeTimeout:           getEnvAsInt("GOOGLE_EXCHANGE_TIMEOUT", 8),
+		},
+		GitHub: GitHubConfig{
+			ClientID:     os.Getenv("GITHUB_CLIENT_ID"),
+			ClientSecret: os.Getenv("GITHUB_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("GITHUB_REDIRECT_URL"),
 		},
 		JWT: JWTConfig{
+			Algorithm:       strings.ToUpper(strings.TrimSpace(getEnv("JWT_ALGORITHM", "HS256"))),
 			Secret:          os.Getenv("JWT_SECRET"),
+			PrivateKeyPath:  os.Getenv("JWT_PRIVATE_KEY_PATH"),
+			KeyID:           os.Getenv("JWT_KEY_ID"),
 			AccessTokenTTL:  getEnvAsInt("JWT_ACCESS_TTL", 15),   // 15 minutes
 			RefreshTokenTTL: getEnvAsInt("JWT_REFRESH_TTL", 168), // 7 days
 			Issuer:          getEnv("JWT_ISSUER", "auth-service"),
 		},
+		AuthFailureLimit: AuthFailureLimitConfig{
+			MaxAttempts:   getEnvAsInt("AUTH_FAILURE_MAX_ATTEMPTS", 10),
+			WindowMinutes: getEnvAsInt("AUTH_FAILURE_WINDOW_MINUTES", 15),
+		},
 		Services: ServicesConfig{
 			UserGRPCAddr: getEnv("USER_SERVICE_GRPC_ADDR", "localhost:50052"),
 		},
@@ -107,7 +192,12 @@ func Load() (*Config, error) {
 		},
 		ServiceTransportSecurity: resolveTransportSecurityMode(getEnv("SERVICE_TRANSPORT_SECURITY", ""), getEnv("ENVIRONMENT", "development"), getEnvAsBool("GRPC_TLS_ENABLED", false)),
 		InternalHTTPTrustMode:    resolveInternalHTTPTrustMode(getEnv("INTERNAL_HTTP_TRUST_MODE", ""), getEnv("ENVIRONMENT", "development")),
-		EnableGRPCReflection:     getEnvAsBool("GRPC_REFLECTION_ENABLED", getEnv("ENVIRONMENT", "development") != "production"),
+		InternalServiceToken:     getEnv("INTERNAL_SERVICE_TOKEN", ""),
+		CORS: CORSConfig{
+			AllowedOrigins:   parseCSV(getEnv("CORS_ALLOWED_ORIGINS", "")),
+			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", true),
+		},
+		EnableGRPCReflection: getEnvAsBool("GRPC_REFLECTION_ENABLED", getEnv("ENVIRONMENT", "development") != "production"),
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -118,6 +208,18 @@ func Load() (*Config, error) {
 }
 
 func (c *Config) validate() error {
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		return fmt.Errorf("LOG_FORMAT must be json or text")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("OTEL_TRACES_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if c.StartupRetry.Attempts < 1 || c.StartupRetry.MaxWaitSeconds < 1 {
+		return fmt.Errorf("STARTUP_RETRY_ATTEMPTS and STARTUP_RETRY_MAX_WAIT_SECONDS must be at least 1")
+	}
+	if c.StartupRetry.InitialBackoffMs < 0 || c.StartupRetry.MaxBackoffMs < c.StartupRetry.InitialBackoffMs {
+		return fmt.Errorf("STARTUP_RETRY_MAX_BACKOFF_MS must be at least STARTUP_RETRY_INITIAL_BACKOFF_MS")
+	}
 	if c.Google.ClientID == "" {
 		return fmt.Errorf("GOOGLE_CLIENT_ID is required")
 	}
@@ -133,8 +235,40 @@ func (c *Config) validate() error {
 	if len(c.Google.AllowedWebRedirectURIs) == 0 {
 		c.Google.AllowedWebRedirectURIs = []string{c.Google.DefaultWebRedirectURI}
 	}
-	if c.JWT.Secret == "" || len(c.JWT.Secret) < 32 {
-		return fmt.Errorf("JWT_SECRET must be at least 32 characters")
+	if c.Google.ExchangeTimeout < 1 {
+		return fmt.Errorf("GOOGLE_EXCHANGE_TIMEOUT must be >= 1")
+	}
+	if !containsURL(c.Google.AllowedWebRedirectURIs, c.Google.DefaultWebRedirectURI) {
+		return fmt.Errorf("GOOGLE_DEFAULT_WEB_REDIRECT_URI %q is not listed in GOOGLE_ALLOWED_WEB_REDIRECT_URIS", c.Google.DefaultWebRedirectURI)
+	}
+	if c.GitHub.Enabled() {
+		if c.GitHub.ClientSecret == "" {
+			return fmt.Errorf("GITHUB_CLIENT_SECRET is required when GITHUB_CLIENT_ID is set")
+		}
+		if c.GitHub.RedirectURL == "" {
+			return fmt.Errorf("GITHUB_REDIRECT_URL is required when GITHUB_CLIENT_ID is set")
+		}
+	}
+	if err := c.validateRedirectURLs(); err != nil {
+		return err
+	}
+	switch c.JWT.Algorithm {
+	case "HS256":
+		if c.JWT.Secret == "" || len(c.JWT.Secret) < 32 {
+			return fmt.Errorf("JWT_SECRET must be at least 32 characters")
+		}
+	case "RS256":
+		if c.JWT.PrivateKeyPath == "" {
+			return fmt.Errorf("JWT_PRIVATE_KEY_PATH is required when JWT_ALGORITHM=RS256")
+		}
+	default:
+		return fmt.Errorf("JWT_ALGORITHM must be one of HS256, RS256")
+	}
+	if c.AuthFailureLimit.MaxAttempts < 0 {
+		return fmt.Errorf("AUTH_FAILURE_MAX_ATTEMPTS must be >= 0")
+	}
+	if c.AuthFailureLimit.WindowMinutes < 1 {
+		return fmt.Errorf("AUTH_FAILURE_WINDOW_MINUTES must be >= 1")
 	}
 	if c.Environment == "production" && strings.TrimSpace(c.Redis.Password) == "" {
 		return fmt.Errorf("REDIS_PASSWORD is required in production")
@@ -156,23 +290,98 @@ func (c *Config) validate() error {
 	if err := validateInternalHTTPTrustMode(c.Environment, c.InternalHTTPTrustMode); err != nil {
 		return err
 	}
+	if c.CORS.AllowCredentials && containsWildcard(c.CORS.AllowedOrigins) {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS cannot contain * when CORS_ALLOW_CREDENTIALS=true")
+	}
 	if c.Environment == "production" && c.EnableGRPCReflection {
 		return fmt.Errorf("GRPC_REFLECTION_ENABLED cannot be true in production")
 	}
 
-	//// Validate redirect URL
-	//if !isValidRedirectURL(c.Google.RedirectURL) {
-	//	return fmt.Errorf("invalid redirect URL")
-	//}
+	return nil
+}
 
+// validateRedirectURLs checks the provider callback URLs against
+// GOOGLE_ALLOWED_REDIRECT_URLS. When the allowlist is unset it defaults to the
+// configured callbacks, so single-environment deployments need no extra setup.
+func (c *Config) validateRedirectURLs() error {
+	if len(c.Google.AllowedRedirectURLs) == 0 {
+		c.Google.AllowedRedirectURLs = []string{c.Google.RedirectURL}
+		if c.GitHub.Enabled() {
+			c.Google.AllowedRedirectURLs = append(c.Google.AllowedRedirectURLs, c.GitHub.RedirectURL)
+		}
+	}
+
+	for _, allowed := range c.Google.AllowedRedirectURLs {
+		parsed, err := url.Parse(allowed)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("GOOGLE_ALLOWED_REDIRECT_URLS contains invalid URL %q", allowed)
+		}
+	}
+
+	if !containsURL(c.Google.AllowedRedirectURLs, c.Google.RedirectURL) {
+		return fmt.Errorf("GOOGLE_REDIRECT_URL %q is not listed in GOOGLE_ALLOWED_REDIRECT_URLS", c.Google.RedirectURL)
+	}
+	if c.GitHub.Enabled() && !containsURL(c.Google.AllowedRedirectURLs, c.GitHub.RedirectURL) {
+		return fmt.Errorf("GITHUB_REDIRECT_URL %q is not listed in GOOGLE_ALLOWED_REDIRECT_URLS", c.GitHub.RedirectURL)
+	}
 	return nil
 }
 
-//func isValidRedirectURL(url string) bool {
-//	allowedHosts := []string{"localhost", "your-domain.com"}
-//
-//	return true
-//}
+// FrontendURL is the origin of the default web redirect URI, used for the
+// login error page so it always points at an allowlisted frontend.
+func (g GoogleConfig) FrontendURL() string {
+	parsed, err := url.Parse(g.DefaultWebRedirectURI)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// IsAllowedClientRedirectURI reports whether uri is one of the configured web
+// or mobile client redirect URIs.
+func (g GoogleConfig) IsAllowedClientRedirectURI(uri string) bool {
+	return containsURL(g.AllowedWebRedirectURIs, uri) || containsURL(g.AllowedMobileRedirectURIs, uri)
+}
+
+func containsURL(list []string, candidate string) bool {
+	normalized := normalizeURL(candidate)
+	for _, item := range list {
+		if normalizeURL(item) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return strings.TrimSpace(raw)
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	if parsed.Path != "/" {
+		parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+	}
+	parsed.Fragment = ""
+	return parsed.String()
+}
+
+func containsWildcard(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultLogFormat(environment string) string {
+	if environment == "production" {
+		return "json"
+	}
+	return "text"
+}
 
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -190,6 +399,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
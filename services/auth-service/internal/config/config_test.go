@@ -54,3 +54,152 @@ func TestLoadProductionAllowsMeshTransportMode(t *testing.T) {
 		t.Fatalf("expected mesh transport mode, got %q", cfg.ServiceTransportSecurity)
 	}
 }
+
+func TestLoadGitHubRequiresSecretWhenEnabled(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("GITHUB_CLIENT_ID", "github-client-id")
+	t.Setenv("GITHUB_CLIENT_SECRET", "")
+	t.Setenv("GITHUB_REDIRECT_URL", "https://api.example.com/api/v1/auth/github/callback")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "GITHUB_CLIENT_SECRET") {
+		t.Fatalf("expected GITHUB_CLIENT_SECRET error, got %v", err)
+	}
+}
+
+func TestLoadGitHubDisabledByDefault(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("GITHUB_CLIENT_ID", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GitHub.Enabled() {
+		t.Fatal("expected GitHub provider to be disabled without GITHUB_CLIENT_ID")
+	}
+}
+
+func TestLoadRS256RequiresPrivateKeyPath(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("JWT_ALGORITHM", "rs256")
+	t.Setenv("JWT_PRIVATE_KEY_PATH", "")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "JWT_PRIVATE_KEY_PATH") {
+		t.Fatalf("expected JWT_PRIVATE_KEY_PATH error, got %v", err)
+	}
+}
+
+func TestLoadRejectsUnknownJWTAlgorithm(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("JWT_ALGORITHM", "none")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "JWT_ALGORITHM") {
+		t.Fatalf("expected JWT_ALGORITHM error, got %v", err)
+	}
+}
+
+func TestLoadAuthFailureLimitDefaults(t *testing.T) {
+	setRequiredAuthEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.AuthFailureLimit.MaxAttempts != 10 || cfg.AuthFailureLimit.WindowMinutes != 15 {
+		t.Fatalf("unexpected auth failure limit defaults: %+v", cfg.AuthFailureLimit)
+	}
+}
+
+func TestLoadRejectsInvalidAuthFailureWindow(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("AUTH_FAILURE_WINDOW_MINUTES", "0")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "AUTH_FAILURE_WINDOW_MINUTES") {
+		t.Fatalf("expected AUTH_FAILURE_WINDOW_MINUTES error, got %v", err)
+	}
+}
+
+func TestLoadRedirectAllowlistDefaultsToRedirectURL(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("GOOGLE_ALLOWED_REDIRECT_URLS", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Google.AllowedRedirectURLs) != 1 || cfg.Google.AllowedRedirectURLs[0] != cfg.Google.RedirectURL {
+		t.Fatalf("expected allowlist to default to GOOGLE_REDIRECT_URL, got %v", cfg.Google.AllowedRedirectURLs)
+	}
+}
+
+func TestLoadRedirectAllowlistAcceptsListedRedirectURL(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("GOOGLE_ALLOWED_REDIRECT_URLS", "https://staging-api.example.com/api/v1/auth/google/callback,https://api.example.com/api/v1/auth/google/callback/")
+
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+}
+
+func TestLoadRedirectAllowlistRejectsUnlistedRedirectURL(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("GOOGLE_ALLOWED_REDIRECT_URLS", "https://staging-api.example.com/api/v1/auth/google/callback")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "GOOGLE_REDIRECT_URL") {
+		t.Fatalf("expected GOOGLE_REDIRECT_URL allowlist error, got %v", err)
+	}
+}
+
+func TestLoadRedirectAllowlistRejectsUnlistedGitHubRedirectURL(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("GITHUB_CLIENT_ID", "github-client-id")
+	t.Setenv("GITHUB_CLIENT_SECRET", "github-client-secret")
+	t.Setenv("GITHUB_REDIRECT_URL", "https://evil.example.net/callback")
+	t.Setenv("GOOGLE_ALLOWED_REDIRECT_URLS", "https://api.example.com/api/v1/auth/google/callback")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "GITHUB_REDIRECT_URL") {
+		t.Fatalf("expected GITHUB_REDIRECT_URL allowlist error, got %v", err)
+	}
+}
+
+func TestLoadRejectsDefaultWebRedirectOutsideAllowlist(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("GOOGLE_DEFAULT_WEB_REDIRECT_URI", "https://app.example.com/auth/callback")
+	t.Setenv("GOOGLE_ALLOWED_WEB_REDIRECT_URIS", "https://other.example.com/auth/callback")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "GOOGLE_DEFAULT_WEB_REDIRECT_URI") {
+		t.Fatalf("expected GOOGLE_DEFAULT_WEB_REDIRECT_URI error, got %v", err)
+	}
+}
+
+func TestGoogleConfigFrontendURLUsesDefaultWebRedirectOrigin(t *testing.T) {
+	cfg := GoogleConfig{DefaultWebRedirectURI: "https://app.example.com/auth/callback"}
+	if got := cfg.FrontendURL(); got != "https://app.example.com" {
+		t.Fatalf("expected frontend origin, got %q", got)
+	}
+}
+
+func TestLoadGoogleExchangeTimeout(t *testing.T) {
+	setRequiredAuthEnv(t)
+	t.Setenv("GOOGLE_EXCHANGE_TIMEOUT", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Google.ExchangeTimeout != 8 {
+		t.Fatalf("expected default exchange timeout of 8s, got %d", cfg.Google.ExchangeTimeout)
+	}
+
+	t.Setenv("GOOGLE_EXCHANGE_TIMEOUT", "0")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "GOOGLE_EXCHANGE_TIMEOUT") {
+		t.Fatalf("expected GOOGLE_EXCHANGE_TIMEOUT error, got %v", err)
+	}
+}
@@ -9,11 +9,15 @@ const (
 
 type OAuthState struct {
 	State               string        `json:"state"`
+	Provider            string        `json:"provider,omitempty"`
 	Platform            OAuthPlatform `json:"platform"`
 	ClientRedirectURI   string        `json:"client_redirect_uri"`
 	ClientState         string        `json:"client_state,omitempty"`
 	CodeChallenge       string        `json:"code_challenge,omitempty"`
 	CodeChallengeMethod string        `json:"code_challenge_method,omitempty"`
+	// ProviderCodeVerifier is the PKCE verifier auth-service generated for its
+	// own code exchange with the provider.
+	ProviderCodeVerifier string `json:"provider_code_verifier,omitempty"`
 }
 
 type AuthCodePayload struct {
@@ -15,12 +15,53 @@ type TokenPair struct {
 type TokenClaims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
 	Type   string `json:"type"`
 }
 
 type StoredToken struct {
-	UserID    string    `json:"user_id"`
-	Email     string    `json:"email"`
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	// Role is carried so a refreshed token keeps the role it was issued with.
+	Role string `json:"role,omitempty"`
+	// SessionID is shared by the access and refresh token of one sign-in and
+	// carried across refresh-token rotation.
+	SessionID string    `json:"session_id,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
+
+// ClientInfo is the device context of the request that issued a token.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionToken describes one live token belonging to a user session.
+type SessionToken struct {
+	Token     string
+	TokenType string // "access" or "refresh"
+	SessionID string
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// TokenState is the store's view of one access token. Data is nil when the
+// token is unknown or has expired.
+type TokenState struct {
+	Blacklisted bool
+	Data        *StoredToken
+}
+
+// TokenStatsPage holds token counts for one SCAN page of the token store.
+// NextCursor is 0 once the scan is complete.
+type TokenStatsPage struct {
+	AccessTokens      int64
+	RefreshTokens     int64
+	BlacklistedTokens int64
+	NextCursor        uint64
+}
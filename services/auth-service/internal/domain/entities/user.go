@@ -8,6 +8,8 @@ type GoogleUserInfo struct {
 	Name          string `json:"name"`
 	Picture       string `json:"picture"`
 	VerifiedEmail bool   `json:"verified_email"`
+	// Role is the user-service role copied into issued tokens, never read from Google.
+	Role          string `json:"role,omitempty"`
 	GivenName     string `json:"given_name"`
 	FamilyName    string `json:"family_name"`
 	Locale        string `json:"locale"`
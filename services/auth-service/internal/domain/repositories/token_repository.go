@@ -2,11 +2,27 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"auth-service/internal/domain/entities"
 )
 
+// BlacklistReasonRotated marks a refresh token that was exchanged for a new
+// pair. Presenting it again indicates the token was leaked and replayed.
+const BlacklistReasonRotated = "rotated"
+
+// ErrTokenAlreadyRotated is returned by RotateRefreshToken when the old token
+// has already been rotated by a previous refresh.
+var ErrTokenAlreadyRotated = errors.New("refresh token already rotated")
+
+// ErrStateNotFound and ErrAuthCodeNotFound are returned when a single-use OAuth
+// state or auth code was never issued, has expired, or was already consumed.
+var (
+	ErrStateNotFound    = errors.New("oauth state not found or expired")
+	ErrAuthCodeNotFound = errors.New("auth code not found or expired")
+)
+
 type TokenRepository interface {
 	// Auth code management (for OAuth flow)
 	StoreAuthCode(ctx context.Context, authCode string, payload *entities.AuthCodePayload, ttl time.Duration) error
@@ -20,13 +36,36 @@ type TokenRepository interface {
 	StoreAccessToken(ctx context.Context, token string, data *entities.StoredToken, ttl time.Duration) error
 	StoreRefreshToken(ctx context.Context, token string, data *entities.StoredToken, ttl time.Duration) error
 	GetTokenData(ctx context.Context, token string) (*entities.StoredToken, error)
+	// GetAccessTokenStates looks up blacklist status and stored data for many
+	// access tokens in one round trip. Results follow the order of tokens.
+	GetAccessTokenStates(ctx context.Context, tokens []string) ([]*entities.TokenState, error)
 	DeleteToken(ctx context.Context, token string) error
 	DeleteUserTokens(ctx context.Context, userID string) error
+	// ListUserSessionTokens returns the user's live access and refresh tokens.
+	ListUserSessionTokens(ctx context.Context, userID string) ([]*entities.SessionToken, error)
+	// ListUserRefreshTokens returns the user's refresh tokens that have not expired.
+	ListUserRefreshTokens(ctx context.Context, userID string) ([]string, error)
 
-	// Token rotation (security best practice)
+	// Token rotation (security best practice). Fails with ErrTokenAlreadyRotated
+	// if oldToken was already rotated, so concurrent refreshes cannot both win.
 	RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *entities.StoredToken, ttl time.Duration) error
 
+	// Admin statistics, paged by SCAN cursor (0 starts a new scan)
+	ScanTokenStats(ctx context.Context, cursor uint64, count int64) (*entities.TokenStatsPage, error)
+
+	// Failed authentication throttling, counted per client IP. The window
+	// starts with the first failure and is not extended by later ones.
+	GetAuthFailures(ctx context.Context, clientIP string) (int64, error)
+	IncrementAuthFailures(ctx context.Context, clientIP string, window time.Duration) (int64, error)
+	ResetAuthFailures(ctx context.Context, clientIP string) error
+
+	// Audit trail of sign-ins and refreshes. userID is empty when the attempt
+	// failed before the user was known.
+	LogAuthAttempt(ctx context.Context, userID, ip, userAgent string, success bool) error
+
 	// Blacklist management
 	IsTokenBlacklisted(ctx context.Context, token string) (bool, error)
+	// GetBlacklistReason returns the reason a token was blacklisted, or "" if it is not.
+	GetBlacklistReason(ctx context.Context, token string) (string, error)
 	BlacklistToken(ctx context.Context, token string, ttl time.Duration) error
 }
@@ -3,8 +3,26 @@ package domainServices
 import (
 	"auth-service/internal/domain/entities"
 	"context"
+	"errors"
 )
 
+// Supported OAuth provider names.
+const (
+	ProviderGoogle = "google"
+	ProviderGitHub = "github"
+)
+
+// ErrEmailNotVerified is returned (possibly wrapped) by providers when the
+// account's email address has not been verified by the provider.
+var ErrEmailNotVerified = errors.New("provider email is not verified")
+
+// ErrOAuthTimeout is returned (possibly wrapped) by providers when the code
+// exchange did not complete within the configured deadline.
+var ErrOAuthTimeout = errors.New("provider code exchange timed out")
+
+// AuthURLRequest carries auth-service's own PKCE challenge for the provider.
+// The client's challenge is never forwarded; it is enforced by auth-service
+// when the client exchanges its auth code.
 type AuthURLRequest struct {
 	State               string
 	CodeChallenge       string
@@ -13,6 +31,8 @@ type AuthURLRequest struct {
 
 type OAuthProvider interface {
 	GetAuthURL(req *AuthURLRequest) string
-	ExchangeCodeForToken(ctx context.Context, code string) (*entities.GoogleUserInfo, error)
+	// ExchangeCodeForToken redeems code, sending codeVerifier when the provider
+	// was given a PKCE challenge for this login.
+	ExchangeCodeForToken(ctx context.Context, code, codeVerifier string) (*entities.GoogleUserInfo, error)
 	GetUserInfo(ctx context.Context, accessToken string) (*entities.GoogleUserInfo, error)
 }
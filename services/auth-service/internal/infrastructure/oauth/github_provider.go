@@ -0,0 +1,154 @@
+package oauth
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/domain/entities"
+	domainServices "auth-service/internal/domain/services"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+const githubAPIBaseURL = "https://api.github.com"
+
+// githubUserIDPrefix namespaces GitHub account IDs so they cannot collide with
+// Google subject IDs when used as the canonical user ID.
+const githubUserIDPrefix = "github_"
+
+type GitHubProvider struct {
+	config     *oauth2.Config
+	apiBaseURL string
+}
+
+type githubUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+func NewGitHubProvider(cfg config.GitHubConfig) *GitHubProvider {
+	return &GitHubProvider{
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       []string{"read:user", "user:email"},
+			Endpoint:     github.Endpoint,
+		},
+		apiBaseURL: githubAPIBaseURL,
+	}
+}
+
+func (g *GitHubProvider) GetAuthURL(req *domainServices.AuthURLRequest) string {
+	if req == nil {
+		req = &domainServices.AuthURLRequest{}
+	}
+
+	// GitHub logins are not bound with PKCE; the state parameter and the client
+	// secret protect the code exchange.
+	return g.config.AuthCodeURL(req.State, oauth2.SetAuthURLParam("allow_signup", "true"))
+}
+
+func (g *GitHubProvider) ExchangeCodeForToken(ctx context.Context, code, codeVerifier string) (*entities.GoogleUserInfo, error) {
+	token, err := g.config.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+
+	return g.GetUserInfo(ctx, token.AccessToken)
+}
+
+func (g *GitHubProvider) GetUserInfo(ctx context.Context, accessToken string) (*entities.GoogleUserInfo, error) {
+	client := g.config.Client(ctx, &oauth2.Token{AccessToken: accessToken})
+
+	var user githubUser
+	if body, err := fetchGitHubJSON(ctx, client, g.apiBaseURL+"/user", &user); err != nil {
+		return nil, fmt.Errorf("%w (body=%q)", err, compactForLog(body))
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("invalid user info received from GitHub: missing id")
+	}
+
+	// The profile email is the public one and may be empty or unverified, so
+	// the primary verified address from /user/emails is authoritative.
+	var emails []githubEmail
+	if body, err := fetchGitHubJSON(ctx, client, g.apiBaseURL+"/user/emails", &emails); err != nil {
+		return nil, fmt.Errorf("%w (body=%q)", err, compactForLog(body))
+	}
+
+	email, verified := primaryGitHubEmail(emails)
+	if email == "" {
+		email = user.Email
+	}
+
+	name := strings.TrimSpace(user.Name)
+	if name == "" {
+		name = user.Login
+	}
+
+	return &entities.GoogleUserInfo{
+		ID:            githubUserIDPrefix + strconv.FormatInt(user.ID, 10),
+		Email:         email,
+		Name:          name,
+		Picture:       user.AvatarURL,
+		VerifiedEmail: verified,
+	}, nil
+}
+
+func primaryGitHubEmail(emails []githubEmail) (string, bool) {
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			return e.Email, true
+		}
+	}
+	for _, e := range emails {
+		if e.Verified {
+			return e.Email, true
+		}
+	}
+	return "", false
+}
+
+func fetchGitHubJSON(ctx context.Context, client *http.Client, endpoint string, out interface{}) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build %s request failed: %w", endpoint, err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request %s failed: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read %s failed: %w", endpoint, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return body, fmt.Errorf("request %s failed with status %d", endpoint, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return body, fmt.Errorf("parse %s failed: %w", endpoint, err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,73 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"auth-service/internal/config"
+)
+
+func newTestGitHubProvider(t *testing.T, userBody, emailsBody string) *GitHubProvider {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(userBody))
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(emailsBody))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider := NewGitHubProvider(config.GitHubConfig{ClientID: "id", ClientSecret: "secret"})
+	provider.apiBaseURL = server.URL
+	return provider
+}
+
+func TestGitHubGetUserInfoNormalizesProfile(t *testing.T) {
+	provider := newTestGitHubProvider(t,
+		`{"id": 42, "login": "octocat", "name": "", "email": null, "avatar_url": "https://avatars.example.com/42"}`,
+		`[{"email": "old@example.com", "primary": false, "verified": true},
+		  {"email": "octo@example.com", "primary": true, "verified": true}]`,
+	)
+
+	info, err := provider.GetUserInfo(context.Background(), "token")
+	if err != nil {
+		t.Fatalf("GetUserInfo: %v", err)
+	}
+	if info.ID != "github_42" {
+		t.Fatalf("expected namespaced ID github_42, got %q", info.ID)
+	}
+	if info.Email != "octo@example.com" || !info.VerifiedEmail {
+		t.Fatalf("expected primary verified email, got %q (verified=%t)", info.Email, info.VerifiedEmail)
+	}
+	if info.Name != "octocat" {
+		t.Fatalf("expected login as name fallback, got %q", info.Name)
+	}
+	if info.Picture != "https://avatars.example.com/42" {
+		t.Fatalf("expected avatar_url as picture, got %q", info.Picture)
+	}
+	if !info.IsValid() {
+		t.Fatal("expected normalized user info to be valid")
+	}
+}
+
+func TestGitHubGetUserInfoWithoutVerifiedEmailIsInvalid(t *testing.T) {
+	provider := newTestGitHubProvider(t,
+		`{"id": 7, "login": "ghost", "name": "Ghost", "email": "ghost@example.com"}`,
+		`[{"email": "ghost@example.com", "primary": true, "verified": false}]`,
+	)
+
+	info, err := provider.GetUserInfo(context.Background(), "token")
+	if err != nil {
+		t.Fatalf("GetUserInfo: %v", err)
+	}
+	if info.IsValid() {
+		t.Fatal("expected account without a verified email to be rejected")
+	}
+}
@@ -0,0 +1,183 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const googleCertsURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+// defaultGoogleCertsTTL is used when the JWKS response has no usable max-age.
+const defaultGoogleCertsTTL = time.Hour
+
+var googleIssuers = map[string]bool{
+	"accounts.google.com":         true,
+	"https://accounts.google.com": true,
+}
+
+type googleIDTokenClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+// googleIDTokenVerifier checks Google-issued OIDC id_tokens against Google's
+// published signing keys, caching them for the lifetime the response allows.
+type googleIDTokenVerifier struct {
+	certsURL   string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	expires time.Time
+}
+
+func newGoogleIDTokenVerifier() *googleIDTokenVerifier {
+	return &googleIDTokenVerifier{
+		certsURL:   googleCertsURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify validates the signature, issuer, audience and expiry of rawToken.
+// It does not check email_verified; callers decide how to treat that claim.
+func (v *googleIDTokenVerifier) Verify(ctx context.Context, rawToken, audience string) (*googleIDTokenClaims, error) {
+	claims := &googleIDTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return v.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if !googleIssuers[claims.Issuer] {
+		return nil, fmt.Errorf("invalid id_token issuer: %q", claims.Issuer)
+	}
+	if !claims.VerifyAudience(audience, true) {
+		return nil, fmt.Errorf("invalid id_token audience")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("invalid id_token: missing subject")
+	}
+
+	return claims, nil
+}
+
+// key returns the signing key for kid, refetching the key set when it has
+// expired or does not contain kid (Google rotates keys regularly).
+func (v *googleIDTokenVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key, ok := v.keys[kid]; ok && time.Now().Before(v.expires) {
+		return key, nil
+	}
+
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	key, ok := v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown id_token signing key: %q", kid)
+	}
+	return key, nil
+}
+
+func (v *googleIDTokenVerifier) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.certsURL, nil)
+	if err != nil {
+		return fmt.Errorf("build google certs request failed: %w", err)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch google certs failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read google certs failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch google certs failed with status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		return fmt.Errorf("parse google certs failed: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		key, err := parseRSAPublicKey(k.N, k.E)
+		if err != nil {
+			return fmt.Errorf("parse google cert %q failed: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	v.keys = keys
+	v.expires = time.Now().Add(cacheMaxAge(resp.Header.Get("Cache-Control")))
+	return nil
+}
+
+func parseRSAPublicKey(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(eBytes)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("exponent out of range")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: int(exponent.Int64())}, nil
+}
+
+func cacheMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(directive), "=")
+		if !found || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultGoogleCertsTTL
+}
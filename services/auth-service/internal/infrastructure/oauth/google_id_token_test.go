@@ -0,0 +1,177 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"auth-service/internal/config"
+	domainServices "auth-service/internal/domain/services"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const testGoogleKeyID = "google-key-1"
+
+func newTestGoogleCerts(t *testing.T) (*rsa.PrivateKey, *httptest.Server) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": testGoogleKeyID,
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return key, server
+}
+
+func signTestIDToken(t *testing.T, key *rsa.PrivateKey, claims *googleIDTokenClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testGoogleKeyID
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign id_token: %v", err)
+	}
+	return signed
+}
+
+func testIDTokenClaims(audience string, emailVerified bool) *googleIDTokenClaims {
+	return &googleIDTokenClaims{
+		Email:         "dev@example.com",
+		EmailVerified: emailVerified,
+		Name:          "Dev",
+		Picture:       "https://example.com/dev.png",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://accounts.google.com",
+			Subject:   "google-123",
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+}
+
+func TestGoogleIDTokenVerifierAcceptsValidToken(t *testing.T) {
+	key, certs := newTestGoogleCerts(t)
+	verifier := newGoogleIDTokenVerifier()
+	verifier.certsURL = certs.URL
+
+	claims, err := verifier.Verify(context.Background(), signTestIDToken(t, key, testIDTokenClaims("id", true)), "id")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.Subject != "google-123" || claims.Email != "dev@example.com" || !claims.EmailVerified {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestGoogleIDTokenVerifierRejectsWrongAudience(t *testing.T) {
+	key, certs := newTestGoogleCerts(t)
+	verifier := newGoogleIDTokenVerifier()
+	verifier.certsURL = certs.URL
+
+	if _, err := verifier.Verify(context.Background(), signTestIDToken(t, key, testIDTokenClaims("other-client", true)), "id"); err == nil {
+		t.Fatal("expected an id_token for another client to be rejected")
+	}
+}
+
+func TestGoogleIDTokenVerifierRejectsWrongIssuer(t *testing.T) {
+	key, certs := newTestGoogleCerts(t)
+	verifier := newGoogleIDTokenVerifier()
+	verifier.certsURL = certs.URL
+
+	claims := testIDTokenClaims("id", true)
+	claims.Issuer = "https://evil.example.com"
+	if _, err := verifier.Verify(context.Background(), signTestIDToken(t, key, claims), "id"); err == nil {
+		t.Fatal("expected an id_token from another issuer to be rejected")
+	}
+}
+
+func TestGoogleIDTokenVerifierRejectsUnknownKey(t *testing.T) {
+	_, certs := newTestGoogleCerts(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	verifier := newGoogleIDTokenVerifier()
+	verifier.certsURL = certs.URL
+
+	if _, err := verifier.Verify(context.Background(), signTestIDToken(t, otherKey, testIDTokenClaims("id", true)), "id"); err == nil {
+		t.Fatal("expected an id_token signed by an unpublished key to be rejected")
+	}
+}
+
+func newTestGoogleProviderWithIDToken(t *testing.T, idToken string) *GoogleProvider {
+	t.Helper()
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-1",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	provider := NewGoogleProvider(config.GoogleConfig{ClientID: "id", ClientSecret: "secret"})
+	provider.config.Endpoint.TokenURL = tokenServer.URL
+	return provider
+}
+
+func TestGoogleExchangeUsesVerifiedIDTokenClaims(t *testing.T) {
+	key, certs := newTestGoogleCerts(t)
+	provider := newTestGoogleProviderWithIDToken(t, signTestIDToken(t, key, testIDTokenClaims("id", true)))
+	provider.idTokens.certsURL = certs.URL
+
+	userInfo, err := provider.ExchangeCodeForToken(context.Background(), "code", "verifier-1")
+	if err != nil {
+		t.Fatalf("ExchangeCodeForToken: %v", err)
+	}
+	if userInfo.ID != "google-123" || userInfo.Email != "dev@example.com" || !userInfo.VerifiedEmail {
+		t.Fatalf("unexpected user info: %+v", userInfo)
+	}
+}
+
+func TestGoogleExchangeRejectsUnverifiedEmail(t *testing.T) {
+	key, certs := newTestGoogleCerts(t)
+	provider := newTestGoogleProviderWithIDToken(t, signTestIDToken(t, key, testIDTokenClaims("id", false)))
+	provider.idTokens.certsURL = certs.URL
+
+	_, err := provider.ExchangeCodeForToken(context.Background(), "code", "verifier-1")
+	if !errors.Is(err, domainServices.ErrEmailNotVerified) {
+		t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+	}
+}
+
+func TestGoogleExchangeRequiresIDToken(t *testing.T) {
+	provider := newTestGoogleProviderWithIDToken(t, "")
+
+	if _, err := provider.ExchangeCodeForToken(context.Background(), "code", "verifier-1"); err == nil {
+		t.Fatal("expected a token response without id_token to be rejected")
+	}
+}
@@ -6,20 +6,32 @@ import (
 	domainServices "auth-service/internal/domain/services"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
+// defaultGoogleExchangeTimeout applies when GoogleConfig.ExchangeTimeout is unset.
+const defaultGoogleExchangeTimeout = 8 * time.Second
+
 type GoogleProvider struct {
-	config *oauth2.Config
+	config          *oauth2.Config
+	idTokens        *googleIDTokenVerifier
+	exchangeTimeout time.Duration
 }
 
 func NewGoogleProvider(cfg config.GoogleConfig) *GoogleProvider {
+	exchangeTimeout := time.Duration(cfg.ExchangeTimeout) * time.Second
+	if exchangeTimeout <= 0 {
+		exchangeTimeout = defaultGoogleExchangeTimeout
+	}
+
 	return &GoogleProvider{
 		config: &oauth2.Config{
 			ClientID:     cfg.ClientID,
@@ -32,6 +44,8 @@ func NewGoogleProvider(cfg config.GoogleConfig) *GoogleProvider {
 			},
 			Endpoint: google.Endpoint,
 		},
+		idTokens:        newGoogleIDTokenVerifier(),
+		exchangeTimeout: exchangeTimeout,
 	}
 }
 
@@ -59,15 +73,65 @@ func (g *GoogleProvider) GetAuthURL(req *domainServices.AuthURLRequest) string {
 	return g.config.AuthCodeURL(req.State, opts...)
 }
 
-func (g *GoogleProvider) ExchangeCodeForToken(ctx context.Context, code string) (*entities.GoogleUserInfo, error) {
+// ExchangeCodeForToken bounds the token exchange, id_token verification and
+// profile lookup by the configured timeout so a slow Google endpoint cannot
+// hold the callback until the server write timeout.
+func (g *GoogleProvider) ExchangeCodeForToken(ctx context.Context, code, codeVerifier string) (*entities.GoogleUserInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.exchangeTimeout)
+	defer cancel()
+
+	userInfo, err := g.exchangeCode(ctx, code, codeVerifier)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("google code exchange after %s: %w", g.exchangeTimeout, domainServices.ErrOAuthTimeout)
+	}
+	return userInfo, err
+}
+
+func (g *GoogleProvider) exchangeCode(ctx context.Context, code, codeVerifier string) (*entities.GoogleUserInfo, error) {
+	var opts []oauth2.AuthCodeOption
+	if codeVerifier != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	}
+
 	// Exchange authorization code for token
-	token, err := g.config.Exchange(ctx, code)
+	token, err := g.config.Exchange(ctx, code, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("token exchange failed: %w", err)
 	}
 
-	// Use the token to get user info
-	return g.GetUserInfo(ctx, token.AccessToken)
+	// The signed id_token is the authoritative identity; the userinfo endpoint
+	// only fills in profile fields it may omit.
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, fmt.Errorf("token response has no id_token")
+	}
+	claims, err := g.idTokens.Verify(ctx, rawIDToken, g.config.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.EmailVerified {
+		return nil, fmt.Errorf("google account %s: %w", claims.Email, domainServices.ErrEmailNotVerified)
+	}
+
+	userInfo := &entities.GoogleUserInfo{
+		ID:            claims.Subject,
+		Email:         claims.Email,
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+		VerifiedEmail: true,
+	}
+	if userInfo.Name == "" || userInfo.Picture == "" {
+		if profile, err := g.GetUserInfo(ctx, token.AccessToken); err == nil && profile.ID == userInfo.ID {
+			if userInfo.Name == "" {
+				userInfo.Name = profile.Name
+			}
+			if userInfo.Picture == "" {
+				userInfo.Picture = profile.Picture
+			}
+		}
+	}
+
+	return userInfo, nil
 }
 
 func (g *GoogleProvider) GetUserInfo(ctx context.Context, accessToken string) (*entities.GoogleUserInfo, error) {
@@ -0,0 +1,85 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"auth-service/internal/config"
+	domainServices "auth-service/internal/domain/services"
+)
+
+func TestGoogleGetAuthURLIncludesPKCEChallenge(t *testing.T) {
+	provider := NewGoogleProvider(config.GoogleConfig{ClientID: "id", ClientSecret: "secret"})
+
+	authURL := provider.GetAuthURL(&domainServices.AuthURLRequest{State: "state-1", CodeChallenge: "challenge-1"})
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse auth URL: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge") != "challenge-1" || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("expected S256 PKCE challenge in auth URL, got %s", authURL)
+	}
+}
+
+func TestGoogleExchangeSendsCodeVerifier(t *testing.T) {
+	var gotVerifier string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		gotVerifier = r.PostForm.Get("code_verifier")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewGoogleProvider(config.GoogleConfig{ClientID: "id", ClientSecret: "secret"})
+	provider.config.Endpoint.TokenURL = server.URL
+
+	if _, err := provider.ExchangeCodeForToken(context.Background(), "code", "verifier-1"); err == nil {
+		t.Fatal("expected the rejected exchange to fail")
+	}
+	if gotVerifier != "verifier-1" {
+		t.Fatalf("expected code_verifier to be sent to the token endpoint, got %q", gotVerifier)
+	}
+}
+
+func TestGoogleExchangeTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	provider := NewGoogleProvider(config.GoogleConfig{ClientID: "id", ClientSecret: "secret"})
+	provider.config.Endpoint.TokenURL = server.URL
+	provider.exchangeTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := provider.ExchangeCodeForToken(context.Background(), "code", "verifier-1")
+	if !errors.Is(err, domainServices.ErrOAuthTimeout) {
+		t.Fatalf("expected ErrOAuthTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected the exchange to give up at the deadline, took %s", elapsed)
+	}
+}
+
+func TestNewGoogleProviderUsesConfiguredExchangeTimeout(t *testing.T) {
+	provider := NewGoogleProvider(config.GoogleConfig{ExchangeTimeout: 3})
+	if provider.exchangeTimeout != 3*time.Second {
+		t.Fatalf("expected 3s exchange timeout, got %s", provider.exchangeTimeout)
+	}
+	if NewGoogleProvider(config.GoogleConfig{}).exchangeTimeout != defaultGoogleExchangeTimeout {
+		t.Fatal("expected the default exchange timeout when unset")
+	}
+}
@@ -3,6 +3,7 @@ package redis
 import (
 	"auth-service/internal/config"
 	"auth-service/internal/domain/entities"
+	"auth-service/internal/domain/repositories"
 	"context"
 	"encoding/json"
 	"errors"
@@ -42,7 +43,7 @@ func (r *TokenRepository) StoreAuthCode(ctx context.Context, authCode string, pa
 func (r *TokenRepository) GetAndDeleteAuthCode(ctx context.Context, authCode string) (*entities.AuthCodePayload, error) {
 	key := r.authCodeKey(authCode)
 
-	data, err := r.getAndDelete(ctx, key)
+	data, err := r.getAndDelete(ctx, key, repositories.ErrAuthCodeNotFound)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve auth code payload: %w", err)
 	}
@@ -74,7 +75,7 @@ func (r *TokenRepository) StoreState(ctx context.Context, state string, payload
 func (r *TokenRepository) GetAndDeleteState(ctx context.Context, state string) (*entities.OAuthState, error) {
 	key := r.stateKey(state)
 
-	data, err := r.getAndDelete(ctx, key)
+	data, err := r.getAndDelete(ctx, key, repositories.ErrStateNotFound)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve oauth state: %w", err)
 	}
@@ -139,17 +140,9 @@ func (r *TokenRepository) DeleteToken(ctx context.Context, token string) error {
 }
 
 func (r *TokenRepository) DeleteUserTokens(ctx context.Context, userID string) error {
-	keys, err := r.client.SMembers(ctx, r.userTokenIndexKey(userID)).Result()
-	if err != nil && !errors.Is(err, redis.Nil) {
-		return fmt.Errorf("failed to get user token index: %w", err)
-	}
-
-	// Backward compatible fallback for tokens stored before index support.
-	if len(keys) == 0 {
-		keys, err = r.scanUserTokenKeys(ctx, userID)
-		if err != nil {
-			return fmt.Errorf("failed to scan user tokens: %w", err)
-		}
+	keys, err := r.userTokenKeys(ctx, userID)
+	if err != nil {
+		return err
 	}
 	if len(keys) == 0 {
 		return nil
@@ -169,33 +162,182 @@ func (r *TokenRepository) DeleteUserTokens(ctx context.Context, userID string) e
 	return nil
 }
 
-// Token rotation (security best practice)
-func (r *TokenRepository) RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *entities.StoredToken, ttl time.Duration) error {
+func (r *TokenRepository) ListUserRefreshTokens(ctx context.Context, userID string) ([]string, error) {
+	keys, err := r.userTokenKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var refreshKeys []string
+	for _, key := range keys {
+		if strings.HasPrefix(key, "auth:refresh:") {
+			refreshKeys = append(refreshKeys, key)
+		}
+	}
+	if len(refreshKeys) == 0 {
+		return nil, nil
+	}
+
+	// The index can outlive expired tokens, so only report keys still present.
 	pipe := r.client.Pipeline()
+	exists := make([]*redis.IntCmd, len(refreshKeys))
+	for i, key := range refreshKeys {
+		exists[i] = pipe.Exists(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to check refresh tokens: %w", err)
+	}
 
-	// Delete old token
-	oldKey := r.refreshTokenKey(oldToken)
-	pipe.Del(ctx, oldKey)
+	tokens := make([]string, 0, len(refreshKeys))
+	for i, key := range refreshKeys {
+		if exists[i].Val() > 0 {
+			tokens = append(tokens, strings.TrimPrefix(key, "auth:refresh:"))
+		}
+	}
+	return tokens, nil
+}
 
-	// Store new token
-	newKey := r.refreshTokenKey(newToken)
+func (r *TokenRepository) ListUserSessionTokens(ctx context.Context, userID string) ([]*entities.SessionToken, error) {
+	keys, err := r.userTokenKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	pipe := r.client.Pipeline()
+	values := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		values[i] = pipe.Get(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get user tokens: %w", err)
+	}
+
+	var tokens []*entities.SessionToken
+	for i, key := range keys {
+		var tokenType, token string
+		switch {
+		case strings.HasPrefix(key, "auth:access:"):
+			tokenType, token = "access", strings.TrimPrefix(key, "auth:access:")
+		case strings.HasPrefix(key, "auth:refresh:"):
+			tokenType, token = "refresh", strings.TrimPrefix(key, "auth:refresh:")
+		default:
+			continue
+		}
+
+		data, err := values[i].Bytes()
+		if err != nil {
+			// Expired since the index was written.
+			continue
+		}
+
+		var stored entities.StoredToken
+		if err := json.Unmarshal(data, &stored); err != nil || stored.UserID != userID {
+			continue
+		}
+
+		tokens = append(tokens, &entities.SessionToken{
+			Token:     token,
+			TokenType: tokenType,
+			SessionID: stored.SessionID,
+			IP:        stored.IP,
+			UserAgent: stored.UserAgent,
+			CreatedAt: stored.CreatedAt,
+			ExpiresAt: stored.ExpiresAt,
+		})
+	}
+
+	return tokens, nil
+}
+
+func (r *TokenRepository) GetAccessTokenStates(ctx context.Context, tokens []string) ([]*entities.TokenState, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	pipe := r.client.Pipeline()
+	blacklisted := make([]*redis.IntCmd, len(tokens))
+	values := make([]*redis.StringCmd, len(tokens))
+	for i, token := range tokens {
+		blacklisted[i] = pipe.Exists(ctx, r.blacklistKey(token))
+		values[i] = pipe.Get(ctx, r.accessTokenKey(token))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get token states: %w", err)
+	}
+
+	states := make([]*entities.TokenState, len(tokens))
+	for i := range tokens {
+		state := &entities.TokenState{Blacklisted: blacklisted[i].Val() > 0}
+		if data, err := values[i].Bytes(); err == nil {
+			var stored entities.StoredToken
+			if err := json.Unmarshal(data, &stored); err == nil {
+				state.Data = &stored
+			}
+		}
+		states[i] = state
+	}
+
+	return states, nil
+}
+
+// rotateRefreshTokenScript claims the old refresh token (KEYS[1], its blacklist
+// key) and swaps the old token (KEYS[2]) for the new one (KEYS[3]), moving it
+// in the user's token index (KEYS[4], optional). SET NX on the blacklist key
+// only succeeds for the first rotation, so a replay returns 0 with nothing
+// changed; otherwise every write lands together. ARGV: reason, token data, TTL
+// in ms (0 keeps the keys forever).
+var rotateRefreshTokenScript = redis.NewScript(`
+local ttl = tonumber(ARGV[3])
+local claimed
+if ttl > 0 then
+  claimed = redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl, 'NX')
+else
+  claimed = redis.call('SET', KEYS[1], ARGV[1], 'NX')
+end
+if not claimed then
+  return 0
+end
+
+redis.call('DEL', KEYS[2])
+if ttl > 0 then
+  redis.call('SET', KEYS[3], ARGV[2], 'PX', ttl)
+else
+  redis.call('SET', KEYS[3], ARGV[2])
+end
+if KEYS[4] then
+  redis.call('SREM', KEYS[4], KEYS[2])
+  redis.call('SADD', KEYS[4], KEYS[3])
+end
+return 1
+`)
+
+// RotateRefreshToken replaces oldToken with newToken in one script, so a
+// failure never leaves the old token claimed without its replacement stored.
+// A token that was already rotated yields ErrTokenAlreadyRotated.
+func (r *TokenRepository) RotateRefreshToken(ctx context.Context, oldToken, newToken string, data *entities.StoredToken, ttl time.Duration) error {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal token data: %w", err)
 	}
-	pipe.Set(ctx, newKey, jsonData, ttl)
+
+	oldKey := r.refreshTokenKey(oldToken)
+	newKey := r.refreshTokenKey(newToken)
+	keys := []string{r.blacklistKey(oldToken), oldKey, newKey}
 	if data != nil && data.UserID != "" {
-		indexKey := r.userTokenIndexKey(data.UserID)
-		pipe.SRem(ctx, indexKey, oldKey)
-		pipe.SAdd(ctx, indexKey, newKey)
+		keys = append(keys, r.userTokenIndexKey(data.UserID))
 	}
 
-	// Blacklist old token to prevent reuse
-	blacklistKey := r.blacklistKey(oldToken)
-	pipe.Set(ctx, blacklistKey, "rotated", ttl)
-
-	_, err = pipe.Exec(ctx)
-	return err
+	rotated, err := rotateRefreshTokenScript.Run(ctx, r.client, keys, repositories.BlacklistReasonRotated, jsonData, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if rotated == 0 {
+		return repositories.ErrTokenAlreadyRotated
+	}
+	return nil
 }
 
 // Blacklist management
@@ -205,11 +347,53 @@ func (r *TokenRepository) IsTokenBlacklisted(ctx context.Context, token string)
 	return exists > 0, err
 }
 
+func (r *TokenRepository) GetBlacklistReason(ctx context.Context, token string) (string, error) {
+	reason, err := r.client.Get(ctx, r.blacklistKey(token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return reason, err
+}
+
 func (r *TokenRepository) BlacklistToken(ctx context.Context, token string, ttl time.Duration) error {
 	key := r.blacklistKey(token)
 	return r.client.Set(ctx, key, "blacklisted", ttl).Err()
 }
 
+// incrementAuthFailuresScript counts a failure against KEYS[1] and starts its
+// window (ARGV[1], in ms) on the first one, so the counter can never be left
+// without an expiry. Returns the new count.
+var incrementAuthFailuresScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Auth failure counting
+func (r *TokenRepository) GetAuthFailures(ctx context.Context, clientIP string) (int64, error) {
+	count, err := r.client.Get(ctx, r.authFailureKey(clientIP)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// IncrementAuthFailures counts a failure for clientIP in one script; the
+// window starts with the first failure and is not extended by later ones.
+func (r *TokenRepository) IncrementAuthFailures(ctx context.Context, clientIP string, window time.Duration) (int64, error) {
+	count, err := incrementAuthFailuresScript.Run(ctx, r.client, []string{r.authFailureKey(clientIP)}, window.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment auth failures: %w", err)
+	}
+	return count, nil
+}
+
+func (r *TokenRepository) ResetAuthFailures(ctx context.Context, clientIP string) error {
+	return r.client.Del(ctx, r.authFailureKey(clientIP)).Err()
+}
+
 // Security audit logging (optional but recommended)
 func (r *TokenRepository) LogAuthAttempt(ctx context.Context, userID, ip, userAgent string, success bool) error {
 	logEntry := map[string]interface{}{
@@ -264,6 +448,27 @@ func (r *TokenRepository) getToken(ctx context.Context, key string) (*entities.S
 }
 
 // Key generation methods
+func (r *TokenRepository) ScanTokenStats(ctx context.Context, cursor uint64, count int64) (*entities.TokenStatsPage, error) {
+	keys, nextCursor, err := r.client.Scan(ctx, cursor, "auth:*", count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tokens: %w", err)
+	}
+
+	page := &entities.TokenStatsPage{NextCursor: nextCursor}
+	for _, key := range keys {
+		switch {
+		case strings.HasPrefix(key, "auth:access:"):
+			page.AccessTokens++
+		case strings.HasPrefix(key, "auth:refresh:"):
+			page.RefreshTokens++
+		case strings.HasPrefix(key, "auth:blacklist:"):
+			page.BlacklistedTokens++
+		}
+	}
+
+	return page, nil
+}
+
 func (r *TokenRepository) accessTokenKey(token string) string {
 	return fmt.Sprintf("auth:access:%s", token)
 }
@@ -284,10 +489,30 @@ func (r *TokenRepository) stateKey(state string) string {
 	return fmt.Sprintf("auth:state:%s", state)
 }
 
+func (r *TokenRepository) authFailureKey(clientIP string) string {
+	return fmt.Sprintf("auth:fail:%s", clientIP)
+}
+
 func (r *TokenRepository) userTokenIndexKey(userID string) string {
 	return fmt.Sprintf("auth:user_tokens:%s", userID)
 }
 
+func (r *TokenRepository) userTokenKeys(ctx context.Context, userID string) ([]string, error) {
+	keys, err := r.client.SMembers(ctx, r.userTokenIndexKey(userID)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get user token index: %w", err)
+	}
+
+	// Backward compatible fallback for tokens stored before index support.
+	if len(keys) == 0 {
+		keys, err = r.scanUserTokenKeys(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user tokens: %w", err)
+		}
+	}
+	return keys, nil
+}
+
 func (r *TokenRepository) scanUserTokenKeys(ctx context.Context, userID string) ([]string, error) {
 	var (
 		cursor uint64
@@ -327,13 +552,21 @@ func (r *TokenRepository) scanUserTokenKeys(ctx context.Context, userID string)
 	return keys, nil
 }
 
-func (r *TokenRepository) getAndDelete(ctx context.Context, key string) (string, error) {
+// getAndDelete atomically reads and removes key. GETDEL touches nothing when
+// the key is absent, and a missing key is reported as notFound so callers can
+// tell an expired value apart from a Redis failure.
+func (r *TokenRepository) getAndDelete(ctx context.Context, key string, notFound error) (string, error) {
 	data, err := r.client.Do(ctx, "GETDEL", key).Text()
 	if errors.Is(err, redis.Nil) {
-		return "", fmt.Errorf("key not found or expired")
+		return "", notFound
 	}
 	if err != nil {
 		return "", err
 	}
 	return data, nil
 }
+
+// Ping checks that Redis is reachable.
+func (r *TokenRepository) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
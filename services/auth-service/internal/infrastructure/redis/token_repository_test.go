@@ -0,0 +1,213 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain/entities"
+	"auth-service/internal/domain/repositories"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeRedis is a minimal RESP server that understands SET, GET and GETDEL,
+// enough to exercise the single-use lookups without a real Redis. Scripts are
+// not run: EVAL replies with evalReply.
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+	// dels records every key actually removed.
+	dels []string
+	// cmds records every command received.
+	cmds      [][]string
+	evalReply string
+}
+
+func newTestTokenRepository(t *testing.T) (*TokenRepository, *fakeRedis) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	fake := &fakeRedis{data: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go fake.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return &TokenRepository{client: client}, fake
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, f.handle(args)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) handle(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cmds = append(f.cmds, args)
+
+	switch strings.ToUpper(args[0]) {
+	case "SET":
+		f.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "GET", "GETDEL":
+		value, ok := f.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		if strings.EqualFold(args[0], "GETDEL") {
+			delete(f.data, args[1])
+			f.dels = append(f.dels, args[1])
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "EVALSHA":
+		return "-NOSCRIPT No matching script\r\n"
+	case "EVAL":
+		return f.evalReply
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		lengthLine, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(lengthLine, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:length])
+	}
+	return args, nil
+}
+
+func TestGetAndDeleteStateReturnsStoredState(t *testing.T) {
+	repo, fake := newTestTokenRepository(t)
+	ctx := context.Background()
+
+	if err := repo.StoreState(ctx, "state-1", &entities.OAuthState{State: "state-1"}, time.Minute); err != nil {
+		t.Fatalf("StoreState: %v", err)
+	}
+
+	got, err := repo.GetAndDeleteState(ctx, "state-1")
+	if err != nil {
+		t.Fatalf("GetAndDeleteState: %v", err)
+	}
+	if got.State != "state-1" {
+		t.Fatalf("unexpected state: %+v", got)
+	}
+	if _, err := repo.GetAndDeleteState(ctx, "state-1"); !errors.Is(err, repositories.ErrStateNotFound) {
+		t.Fatalf("expected a consumed state to be gone, got %v", err)
+	}
+	if len(fake.dels) != 1 || fake.dels[0] != repo.stateKey("state-1") {
+		t.Fatalf("expected only the state key to be deleted, got %v", fake.dels)
+	}
+}
+
+func TestGetAndDeleteStateExpiredIsNotFound(t *testing.T) {
+	repo, fake := newTestTokenRepository(t)
+
+	_, err := repo.GetAndDeleteState(context.Background(), "expired-state")
+	if !errors.Is(err, repositories.ErrStateNotFound) {
+		t.Fatalf("expected ErrStateNotFound, got %v", err)
+	}
+	if len(fake.dels) != 0 {
+		t.Fatalf("expected nothing to be deleted for a missing state, got %v", fake.dels)
+	}
+}
+
+func TestGetAndDeleteAuthCodeExpiredIsNotFound(t *testing.T) {
+	repo, _ := newTestTokenRepository(t)
+
+	_, err := repo.GetAndDeleteAuthCode(context.Background(), "expired-code")
+	if !errors.Is(err, repositories.ErrAuthCodeNotFound) {
+		t.Fatalf("expected ErrAuthCodeNotFound, got %v", err)
+	}
+}
+
+func TestGetAndDeleteStateRedisFailureIsNotNotFound(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+	repo := &TokenRepository{client: client}
+
+	_, err := repo.GetAndDeleteState(context.Background(), "state-1")
+	if err == nil || errors.Is(err, repositories.ErrStateNotFound) {
+		t.Fatalf("expected a connection error distinct from ErrStateNotFound, got %v", err)
+	}
+}
+
+func TestIncrementAuthFailuresRunsAsOneScript(t *testing.T) {
+	repo, fake := newTestTokenRepository(t)
+	fake.evalReply = ":3\r\n"
+
+	count, err := repo.IncrementAuthFailures(context.Background(), "203.0.113.7", time.Minute)
+	if err != nil {
+		t.Fatalf("IncrementAuthFailures: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected count 3, got %d", count)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var eval []string
+	for _, cmd := range fake.cmds {
+		switch strings.ToUpper(cmd[0]) {
+		case "INCR", "EXPIRE", "PEXPIRE":
+			t.Fatalf("expected no standalone %s, got %v", cmd[0], fake.cmds)
+		case "EVAL":
+			eval = cmd
+		}
+	}
+	// EVAL script numkeys key window
+	if len(eval) != 5 || eval[3] != repo.authFailureKey("203.0.113.7") || eval[4] != "60000" {
+		t.Fatalf("unexpected EVAL: %v", eval)
+	}
+}
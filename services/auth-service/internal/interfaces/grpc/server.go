@@ -3,72 +3,76 @@ package grpc
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	appErrors "auth-service/internal/application/errors"
 	"auth-service/internal/application/services"
 	"auth-service/internal/application/services/dto"
+	"auth-service/pkg/health"
 	"auth-service/pkg/logger"
 
 	authv1 "github.com/nikitashilov/microblog_grpc/proto/auth/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // AuthServer exposes AuthService functionality over gRPC.
 type AuthServer struct {
 	authv1.UnimplementedAuthServiceServer
 	service *services.AuthService
+	health  *health.Checker
 	logger  *logger.Logger
 }
 
-func NewAuthServer(service *services.AuthService, logger *logger.Logger) *AuthServer {
-	return &AuthServer{service: service, logger: logger}
+func NewAuthServer(service *services.AuthService, checker *health.Checker, logger *logger.Logger) *AuthServer {
+	return &AuthServer{service: service, health: checker, logger: logger}
 }
 
 func (s *AuthServer) GetGoogleAuthURL(ctx context.Context, req *authv1.GetGoogleAuthURLRequest) (*authv1.GetGoogleAuthURLResponse, error) {
-	dtoReq := &dto.GoogleAuthURLRequest{
-		Platform:            toDTOPlatform(req.GetPlatform()),
-		ClientRedirectURI:   req.GetClientRedirectUri(),
-		CodeChallenge:       req.GetCodeChallenge(),
-		CodeChallengeMethod: req.GetCodeChallengeMethod(),
-		ClientState:         req.GetClientState(),
+	resp, err := s.service.GetGoogleAuthURL(ctx, toDTOAuthURLRequest(req))
+	if err != nil {
+		return nil, s.toGRPCError(err)
 	}
 
-	resp, err := s.service.GetGoogleAuthURL(ctx, dtoReq)
+	return toProtoAuthURLResponse(resp), nil
+}
+
+func (s *AuthServer) HandleGoogleCallback(ctx context.Context, req *authv1.GoogleCallbackRequest) (*authv1.GoogleCallbackResponse, error) {
+	resp, err := s.service.HandleGoogleCallback(ctx, toDTOCallbackRequest(req))
 	if err != nil {
 		return nil, s.toGRPCError(err)
 	}
 
-	return &authv1.GetGoogleAuthURLResponse{
-		AuthUrl: resp.AuthURL,
-		State:   resp.State,
-	}, nil
+	return toProtoCallbackResponse(resp), nil
 }
 
-func (s *AuthServer) HandleGoogleCallback(ctx context.Context, req *authv1.GoogleCallbackRequest) (*authv1.GoogleCallbackResponse, error) {
-	dtoReq := &dto.GoogleCallbackRequest{
-		State: req.GetState(),
-		Code:  req.GetCode(),
+func (s *AuthServer) GetGitHubAuthURL(ctx context.Context, req *authv1.GetGoogleAuthURLRequest) (*authv1.GetGoogleAuthURLResponse, error) {
+	resp, err := s.service.GetGitHubAuthURL(ctx, toDTOAuthURLRequest(req))
+	if err != nil {
+		return nil, s.toGRPCError(err)
 	}
 
-	resp, err := s.service.HandleGoogleCallback(ctx, dtoReq)
+	return toProtoAuthURLResponse(resp), nil
+}
+
+func (s *AuthServer) HandleGitHubCallback(ctx context.Context, req *authv1.GoogleCallbackRequest) (*authv1.GoogleCallbackResponse, error) {
+	resp, err := s.service.HandleGitHubCallback(ctx, toDTOCallbackRequest(req))
 	if err != nil {
 		return nil, s.toGRPCError(err)
 	}
 
-	return &authv1.GoogleCallbackResponse{
-		AuthCode:          resp.AuthCode,
-		ClientRedirectUri: resp.ClientRedirectURI,
-		ClientState:       resp.ClientState,
-		Platform:          toProtoPlatform(resp.Platform),
-	}, nil
+	return toProtoCallbackResponse(resp), nil
 }
 
 func (s *AuthServer) ExchangeAuthCode(ctx context.Context, req *authv1.ExchangeAuthCodeRequest) (*authv1.ExchangeAuthCodeResponse, error) {
 	dtoReq := &dto.ExchangeAuthCodeRequest{
 		AuthCode:     req.GetAuthCode(),
 		CodeVerifier: req.GetCodeVerifier(),
+		ClientIP:     req.GetClientIp(),
+		UserAgent:    req.GetUserAgent(),
 	}
 
 	resp, err := s.service.ExchangeAuthCode(ctx, dtoReq)
@@ -83,7 +87,11 @@ func (s *AuthServer) ExchangeAuthCode(ctx context.Context, req *authv1.ExchangeA
 }
 
 func (s *AuthServer) RefreshToken(ctx context.Context, req *authv1.RefreshTokenRequest) (*authv1.RefreshTokenResponse, error) {
-	dtoReq := &dto.RefreshTokenRequest{RefreshToken: req.GetRefreshToken()}
+	dtoReq := &dto.RefreshTokenRequest{
+		RefreshToken: req.GetRefreshToken(),
+		ClientIP:     req.GetClientIp(),
+		UserAgent:    req.GetUserAgent(),
+	}
 
 	resp, err := s.service.RefreshToken(ctx, dtoReq)
 	if err != nil {
@@ -106,6 +114,44 @@ func (s *AuthServer) Logout(ctx context.Context, req *authv1.LogoutRequest) (*em
 	return &emptypb.Empty{}, nil
 }
 
+func (s *AuthServer) LogoutAll(ctx context.Context, req *authv1.LogoutAllRequest) (*authv1.LogoutAllResponse, error) {
+	terminated, err := s.service.LogoutAll(ctx, req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &authv1.LogoutAllResponse{SessionsTerminated: int32(terminated)}, nil
+}
+
+func (s *AuthServer) ListSessions(ctx context.Context, req *authv1.ListSessionsRequest) (*authv1.ListSessionsResponse, error) {
+	sessions, err := s.service.ListSessions(ctx, req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	resp := &authv1.ListSessionsResponse{Sessions: make([]*authv1.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, &authv1.Session{
+			Id:         session.ID,
+			TokenTypes: session.TokenTypes,
+			CreatedAt:  timestamppb.New(session.CreatedAt),
+			ExpiresAt:  timestamppb.New(session.ExpiresAt),
+			Ip:         session.IP,
+			UserAgent:  session.UserAgent,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *AuthServer) RevokeSession(ctx context.Context, req *authv1.RevokeSessionRequest) (*emptypb.Empty, error) {
+	if err := s.service.RevokeSession(ctx, req.GetUserId(), req.GetSessionId()); err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
 func (s *AuthServer) ValidateToken(ctx context.Context, req *authv1.ValidateTokenRequest) (*authv1.ValidateTokenResponse, error) {
 	resp, err := s.service.ValidateToken(ctx, req.GetToken())
 	if err != nil {
@@ -116,9 +162,32 @@ func (s *AuthServer) ValidateToken(ctx context.Context, req *authv1.ValidateToke
 		Valid:  resp.Valid,
 		UserId: resp.UserID,
 		Email:  resp.Email,
+		Role:   resp.Role,
 	}, nil
 }
 
+func (s *AuthServer) IntrospectTokens(ctx context.Context, req *authv1.IntrospectTokensRequest) (*authv1.IntrospectTokensResponse, error) {
+	results, err := s.service.IntrospectTokens(ctx, req.GetTokens())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	resp := &authv1.IntrospectTokensResponse{Results: make([]*authv1.TokenIntrospection, 0, len(results))}
+	for _, result := range results {
+		introspection := &authv1.TokenIntrospection{
+			Active: result.Active,
+			UserId: result.UserID,
+			Email:  result.Email,
+		}
+		if result.ExpiresAt != nil {
+			introspection.ExpiresAt = timestamppb.New(*result.ExpiresAt)
+		}
+		resp.Results = append(resp.Results, introspection)
+	}
+
+	return resp, nil
+}
+
 func (s *AuthServer) Register(ctx context.Context, req *authv1.RegisterRequest) (*authv1.RegisterResponse, error) {
 	resp, err := s.service.Register(ctx, req.GetEmail(), req.GetPassword(), req.GetName())
 	if err != nil {
@@ -143,7 +212,13 @@ func (s *AuthServer) Login(ctx context.Context, req *authv1.LoginRequest) (*auth
 	}, nil
 }
 
+// HealthCheck fails with Unavailable when a backing dependency is unreachable,
+// so callers see more than whether the process is up.
 func (s *AuthServer) HealthCheck(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if _, err := s.health.Run(ctx); err != nil {
+		s.logger.Warn("Health check failed: " + err.Error())
+		return nil, status.Error(codes.Unavailable, "dependency check failed")
+	}
 	return &emptypb.Empty{}, nil
 }
 
@@ -153,24 +228,26 @@ func (s *AuthServer) toGRPCError(err error) error {
 	}
 
 	if authErr, ok := err.(*appErrors.AuthError); ok {
+		code := codes.Internal
 		switch authErr.StatusCode {
 		case http.StatusBadRequest:
-			return status.Error(codes.InvalidArgument, authErr.Message)
+			code = codes.InvalidArgument
 		case http.StatusUnauthorized:
-			return status.Error(codes.Unauthenticated, authErr.Message)
+			code = codes.Unauthenticated
 		case http.StatusForbidden:
-			return status.Error(codes.PermissionDenied, authErr.Message)
+			code = codes.PermissionDenied
 		case http.StatusNotFound:
-			return status.Error(codes.NotFound, authErr.Message)
+			code = codes.NotFound
 		case http.StatusConflict:
-			return status.Error(codes.AlreadyExists, authErr.Message)
+			code = codes.AlreadyExists
 		case http.StatusTooManyRequests:
-			return status.Error(codes.ResourceExhausted, authErr.Message)
+			code = codes.ResourceExhausted
 		case http.StatusServiceUnavailable:
-			return status.Error(codes.Unavailable, authErr.Message)
-		default:
-			return status.Error(codes.Internal, authErr.Message)
+			code = codes.Unavailable
+		case http.StatusGatewayTimeout:
+			code = codes.DeadlineExceeded
 		}
+		return appStatus(code, authErr)
 	}
 
 	s.logger.Error("unexpected error: " + err.Error())
@@ -203,6 +280,39 @@ func toProtoTokens(tokens *dto.TokenPair) *authv1.TokenPair {
 	}
 }
 
+func toDTOAuthURLRequest(req *authv1.GetGoogleAuthURLRequest) *dto.GoogleAuthURLRequest {
+	return &dto.GoogleAuthURLRequest{
+		Platform:            toDTOPlatform(req.GetPlatform()),
+		ClientRedirectURI:   req.GetClientRedirectUri(),
+		CodeChallenge:       req.GetCodeChallenge(),
+		CodeChallengeMethod: req.GetCodeChallengeMethod(),
+		ClientState:         req.GetClientState(),
+	}
+}
+
+func toProtoAuthURLResponse(resp *dto.GoogleAuthURLResponse) *authv1.GetGoogleAuthURLResponse {
+	return &authv1.GetGoogleAuthURLResponse{
+		AuthUrl: resp.AuthURL,
+		State:   resp.State,
+	}
+}
+
+func toDTOCallbackRequest(req *authv1.GoogleCallbackRequest) *dto.GoogleCallbackRequest {
+	return &dto.GoogleCallbackRequest{
+		State: req.GetState(),
+		Code:  req.GetCode(),
+	}
+}
+
+func toProtoCallbackResponse(resp *dto.GoogleCallbackResponse) *authv1.GoogleCallbackResponse {
+	return &authv1.GoogleCallbackResponse{
+		AuthCode:          resp.AuthCode,
+		ClientRedirectUri: resp.ClientRedirectURI,
+		ClientState:       resp.ClientState,
+		Platform:          toProtoPlatform(resp.Platform),
+	}
+}
+
 func toProtoPlatform(platform dto.OAuthPlatform) authv1.OAuthPlatform {
 	switch platform {
 	case dto.OAuthPlatformMobile:
@@ -220,3 +330,19 @@ func toDTOPlatform(platform authv1.OAuthPlatform) dto.OAuthPlatform {
 		return dto.OAuthPlatformWeb
 	}
 }
+
+// appStatus builds the gRPC status for an application error. The error code and
+// HTTP status travel as an ErrorInfo detail so the gateway can answer with them
+// instead of reducing every failure to its gRPC code.
+func appStatus(code codes.Code, authErr *appErrors.AuthError) error {
+	st := status.New(code, authErr.Message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   authErr.Code,
+		Domain:   "auth-service",
+		Metadata: map[string]string{"http_status": strconv.Itoa(authErr.StatusCode)},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -4,13 +4,16 @@ import (
 	"auth-service/internal/application/errors"
 	"auth-service/internal/application/services"
 	"auth-service/internal/application/services/dto"
+	"auth-service/internal/config"
 	"auth-service/internal/interfaces/validators"
+	"auth-service/pkg/health"
 	"auth-service/pkg/logger"
 	"auth-service/pkg/utils"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,20 +21,47 @@ import (
 type AuthHandler struct {
 	authService *services.AuthService
 	validator   *validators.AuthValidator
+	google      config.GoogleConfig
+	health      *health.Checker
 	logger      *logger.Logger
 }
 
-func NewAuthHandler(authService *services.AuthService, logger *logger.Logger) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, google config.GoogleConfig, checker *health.Checker, logger *logger.Logger) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
 		validator:   validators.NewAuthValidator(),
+		google:      google,
+		health:      checker,
 		logger:      logger,
 	}
 }
 
+type authURLFunc func(ctx context.Context, req *dto.GoogleAuthURLRequest) (*dto.GoogleAuthURLResponse, error)
+
+type callbackFunc func(ctx context.Context, req *dto.GoogleCallbackRequest) (*dto.GoogleCallbackResponse, error)
+
 // Step 1: Get Google Auth URL
 func (h *AuthHandler) GetGoogleAuthURL(c *gin.Context) {
-	h.logger.Info("Processing Google auth URL request")
+	h.getOAuthAuthURL(c, "Google", h.authService.GetGoogleAuthURL)
+}
+
+// Step 1 (GitHub): Get GitHub Auth URL
+func (h *AuthHandler) GetGitHubAuthURL(c *gin.Context) {
+	h.getOAuthAuthURL(c, "GitHub", h.authService.GetGitHubAuthURL)
+}
+
+// Step 2: Handle Google OAuth Callback (redirects user back from Google)
+func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+	h.oauthCallback(c, "Google", h.authService.HandleGoogleCallback)
+}
+
+// Step 2 (GitHub): Handle GitHub OAuth Callback (redirects user back from GitHub)
+func (h *AuthHandler) GitHubCallback(c *gin.Context) {
+	h.oauthCallback(c, "GitHub", h.authService.HandleGitHubCallback)
+}
+
+func (h *AuthHandler) getOAuthAuthURL(c *gin.Context, provider string, getAuthURL authURLFunc) {
+	h.logger.Info(fmt.Sprintf("Processing %s auth URL request", provider))
 
 	req := &dto.GoogleAuthURLRequest{
 		Platform:            dto.OAuthPlatform(c.Query("platform")),
@@ -42,14 +72,14 @@ func (h *AuthHandler) GetGoogleAuthURL(c *gin.Context) {
 	}
 
 	if err := h.validator.ValidateGoogleAuthURLRequest(req); err != nil {
-		h.logger.Warn("Invalid Google auth URL request: " + err.Error())
+		h.logger.Warn(fmt.Sprintf("Invalid %s auth URL request: %s", provider, err.Error()))
 		utils.ErrorResponse(c, errors.ErrInvalidRequest)
 		return
 	}
 
-	response, err := h.authService.GetGoogleAuthURL(c.Request.Context(), req)
+	response, err := getAuthURL(c.Request.Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to get Google auth URL: " + err.Error())
+		h.logger.Error(fmt.Sprintf("Failed to get %s auth URL: %s", provider, err.Error()))
 		if authErr, ok := err.(*errors.AuthError); ok {
 			utils.ErrorResponse(c, authErr)
 		} else {
@@ -58,23 +88,22 @@ func (h *AuthHandler) GetGoogleAuthURL(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("Google auth URL generated successfully")
-	utils.SuccessResponse(c, http.StatusOK, "Google auth URL generated", response)
+	h.logger.Info(fmt.Sprintf("%s auth URL generated successfully", provider))
+	utils.SuccessResponse(c, http.StatusOK, provider+" auth URL generated", response)
 }
 
-// Step 2: Handle Google OAuth Callback (redirects user back from Google)
-func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+func (h *AuthHandler) oauthCallback(c *gin.Context, provider string, handleCallback callbackFunc) {
 	state := c.Query("state")
 	code := c.Query("code")
 	errorParam := c.Query("error")
 
-	h.logger.Info(fmt.Sprintf("Processing Google callback - state: %s, code present: %t, error: %s",
-		state, code != "", errorParam))
+	h.logger.Info(fmt.Sprintf("Processing %s callback - state: %s, code present: %t, error: %s",
+		provider, state, code != "", errorParam))
 
-	// Handle OAuth errors from Google
+	// Handle OAuth errors from the provider
 	if errorParam != "" {
-		h.logger.Warn("Google OAuth error: " + errorParam)
-		frontendURL := h.getFrontendErrorURL("google_oauth_error")
+		h.logger.Warn(fmt.Sprintf("%s OAuth error: %s", provider, errorParam))
+		frontendURL := h.getFrontendErrorURL(strings.ToLower(provider) + "_oauth_error")
 		c.Redirect(http.StatusTemporaryRedirect, frontendURL)
 		return
 	}
@@ -93,22 +122,31 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 	}
 
 	if err := h.validator.ValidateGoogleCallbackRequest(callbackReq); err != nil {
-		h.logger.Warn("Google callback validation failed: " + err.Error())
+		h.logger.Warn(fmt.Sprintf("%s callback validation failed: %s", provider, err.Error()))
 		frontendURL := h.getFrontendErrorURL("validation_failed")
 		c.Redirect(http.StatusTemporaryRedirect, frontendURL)
 		return
 	}
 
-	response, err := h.authService.HandleGoogleCallback(c.Request.Context(), callbackReq)
+	response, err := handleCallback(c.Request.Context(), callbackReq)
 	if err != nil {
-		h.logger.Error("Google callback processing failed: " + err.Error())
+		h.logger.Error(fmt.Sprintf("%s callback processing failed: %s", provider, err.Error()))
 
 		// Provide more specific error handling
 		if authErr, ok := err.(*errors.AuthError); ok {
 			switch authErr.Code {
-			case "INVALID_GOOGLE_CODE":
+			case "INVALID_GOOGLE_CODE", "INVALID_OAUTH_CODE":
 				frontendURL := h.getFrontendErrorURL("invalid_code")
 				c.Redirect(http.StatusTemporaryRedirect, frontendURL)
+			case "EMAIL_DOMAIN_NOT_ALLOWED":
+				frontendURL := h.getFrontendErrorURL("domain_not_allowed")
+				c.Redirect(http.StatusTemporaryRedirect, frontendURL)
+			case "EMAIL_NOT_VERIFIED":
+				frontendURL := h.getFrontendErrorURL("email_not_verified")
+				c.Redirect(http.StatusTemporaryRedirect, frontendURL)
+			case "OAUTH_TIMEOUT":
+				frontendURL := h.getFrontendErrorURL("oauth_timeout")
+				c.Redirect(http.StatusTemporaryRedirect, frontendURL)
 			default:
 				frontendURL := h.getFrontendErrorURL("callback_failed")
 				c.Redirect(http.StatusTemporaryRedirect, frontendURL)
@@ -120,6 +158,13 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 		return
 	}
 
+	if !h.google.IsAllowedClientRedirectURI(response.ClientRedirectURI) {
+		h.logger.Error("Refusing to redirect to unlisted client URI: " + response.ClientRedirectURI)
+		frontendURL := h.getFrontendErrorURL("callback_failed")
+		c.Redirect(http.StatusTemporaryRedirect, frontendURL)
+		return
+	}
+
 	// Success - redirect to frontend with temporary auth code
 	clientURL, buildErr := h.buildClientSuccessURL(response.ClientRedirectURI, response.AuthCode, response.ClientState)
 	if buildErr != nil {
@@ -129,7 +174,7 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("Google callback processed successfully, redirecting to client")
+	h.logger.Info(fmt.Sprintf("%s callback processed successfully, redirecting to client", provider))
 	c.Redirect(http.StatusTemporaryRedirect, clientURL)
 }
 
@@ -149,6 +194,9 @@ func (h *AuthHandler) ExchangeAuthCode(c *gin.Context) {
 		return
 	}
 
+	req.ClientIP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
 	h.logger.Info("Processing auth code exchange")
 	response, err := h.authService.ExchangeAuthCode(c.Request.Context(), &req)
 	if err != nil {
@@ -180,6 +228,9 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
+	req.ClientIP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
 	response, err := h.authService.RefreshToken(c.Request.Context(), &req)
 	if err != nil {
 		if authErr, ok := err.(*errors.AuthError); ok {
@@ -249,6 +300,38 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Token is valid", response)
 }
 
+func (h *AuthHandler) GetTokenStats(c *gin.Context) {
+	var req dto.TokenStatsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid token stats request: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.authService.GetTokenStats(c.Request.Context(), &req)
+	if err != nil {
+		if authErr, ok := err.(*errors.AuthError); ok {
+			utils.ErrorResponse(c, authErr)
+		} else {
+			h.logger.Error("Unexpected error in token stats: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token stats retrieved successfully", response)
+}
+
+// JWKS serves the token verification keys in the standard JWK Set format,
+// without the usual response envelope, so off-the-shelf JWT libraries can use it.
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+// HealthCheck is the liveness probe: it answers 200 whenever the process is
+// up, without touching any dependency.
 func (h *AuthHandler) HealthCheck(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Auth service is healthy", gin.H{
 		"service": "auth-service",
@@ -256,13 +339,30 @@ func (h *AuthHandler) HealthCheck(c *gin.Context) {
 	})
 }
 
+// Readiness is the readiness probe: it checks the backing dependencies and
+// returns 503 when any of them is unreachable.
+func (h *AuthHandler) Readiness(c *gin.Context) {
+	dependencies, err := h.health.Run(c.Request.Context())
+	if err != nil {
+		h.logger.Warn("Readiness check failed: " + err.Error())
+		utils.SuccessResponse(c, http.StatusServiceUnavailable, "Auth service is not ready", gin.H{
+			"service":      "auth-service",
+			"status":       health.StatusUnhealthy,
+			"dependencies": dependencies,
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Auth service is ready", gin.H{
+		"service":      "auth-service",
+		"status":       health.StatusHealthy,
+		"dependencies": dependencies,
+	})
+}
+
 // Helper methods for frontend URL construction
 func (h *AuthHandler) getFrontendErrorURL(errorType string) string {
-	frontendURL := os.Getenv("FRONTEND_URL")
-	if frontendURL == "" {
-		frontendURL = "http://localhost:3000" // Default fallback
-	}
-	return fmt.Sprintf("%s/auth/login?error=%s", frontendURL, errorType)
+	return fmt.Sprintf("%s/auth/login?error=%s", h.google.FrontendURL(), url.QueryEscape(errorType))
 }
 
 func (h *AuthHandler) buildClientSuccessURL(clientRedirectURI, authCode, clientState string) (string, error) {
@@ -1,18 +1,14 @@
 package middleware
 
 import (
-	"fmt"
-	"net/http"
-	"strconv"
-
 	"github.com/gin-gonic/gin"
 
 	"auth-service/internal/application/errors"
-	"auth-service/pkg/logger"
+	logpkg "auth-service/pkg/logger"
 	"auth-service/pkg/utils"
 )
 
-func ErrorHandler(logger *logger.Logger) gin.HandlerFunc {
+func ErrorHandler(logger *logpkg.Logger) gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		if err, ok := recovered.(string); ok {
 			logger.Error("Panic recovered: " + err)
@@ -28,36 +24,14 @@ func ErrorHandler(logger *logger.Logger) gin.HandlerFunc {
 }
 
 // ✅ FIXED: Request logger with proper status code formatting
-func RequestLogger(logger *logger.Logger) gin.HandlerFunc {
+func RequestLogger(logger *logpkg.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		// Fix the status code display
-		statusStr := strconv.Itoa(param.StatusCode)
-
-		logMsg := fmt.Sprintf("Request: %s %s | Status: %s | Latency: %s | IP: %s",
-			param.Method,
-			param.Path,
-			statusStr,
-			param.Latency.String(),
-			param.ClientIP,
+		logger.Info("Request: "+param.Method+" "+param.Path,
+			logpkg.F("status", param.StatusCode),
+			logpkg.F("latency_ms", param.Latency.Milliseconds()),
+			logpkg.F("client_ip", param.ClientIP),
+			logpkg.F("request_id", param.Request.Header.Get("X-Request-ID")),
 		)
-
-		logger.Info(logMsg)
 		return ""
 	})
 }
-
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		}
-
-		c.Next()
-	}
-}
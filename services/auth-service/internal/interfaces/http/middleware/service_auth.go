@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+
+	"auth-service/internal/application/errors"
+	"auth-service/pkg/utils"
+)
+
+// InternalTokenHeader carries the shared service-to-service secret.
+const InternalTokenHeader = "X-Internal-Token"
+
+// ServiceAuthMiddleware admits only callers presenting token in
+// InternalTokenHeader. An empty token rejects every caller, so the routes it
+// guards are closed until INTERNAL_SERVICE_TOKEN is configured.
+func ServiceAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(InternalTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -4,25 +4,29 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"auth-service/internal/application/services"
+	"auth-service/internal/config"
 	"auth-service/internal/interfaces/http/handlers"
 	"auth-service/internal/interfaces/http/middleware"
+	"auth-service/pkg/health"
 	"auth-service/pkg/logger"
 )
 
 // Fix 4: Update internal/interfaces/http/routes/auth_routes.go
 // Clean up routes to remove legacy endpoint
 
-func SetupAuthRoutes(router *gin.Engine, authService *services.AuthService, logger *logger.Logger) {
+func SetupAuthRoutes(router *gin.Engine, authService *services.AuthService, google config.GoogleConfig, trustMode, internalServiceToken string, cors config.CORSConfig, checker *health.Checker, logger *logger.Logger) {
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService, logger)
+	authHandler := handlers.NewAuthHandler(authService, google, checker, logger)
 
 	// Add global middleware
 	router.Use(middleware.ErrorHandler(logger))
 	router.Use(middleware.RequestLogger(logger))
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORS(cors))
 
 	// Health check
 	router.GET("/health", authHandler.HealthCheck)
+	router.GET("/ready", authHandler.Readiness)
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
@@ -34,10 +38,25 @@ func SetupAuthRoutes(router *gin.Engine, authService *services.AuthService, logg
 			auth.GET("/google/callback", authHandler.GoogleCallback) // Step 2: Handle callback
 			auth.POST("/exchange", authHandler.ExchangeAuthCode)     // Step 3: Exchange for tokens
 
+			// GitHub sign-in shares the exchange step above
+			auth.GET("/github", authHandler.GetGitHubAuthURL)
+			auth.GET("/github/callback", authHandler.GitHubCallback)
+
 			// Token management
 			auth.POST("/refresh", authHandler.RefreshToken)
 			auth.POST("/logout", authHandler.Logout)
 			auth.GET("/validate", authHandler.ValidateToken)
 		}
 	}
+
+	// Token stats scan every session in Redis, so they require the
+	// service-to-service token and are not mounted when internal HTTP trust is
+	// disabled.
+	if trustMode != "disabled" {
+		internal := router.Group("/internal/tokens")
+		internal.Use(middleware.ServiceAuthMiddleware(internalServiceToken))
+		{
+			internal.GET("/stats", authHandler.GetTokenStats)
+		}
+	}
 }
@@ -0,0 +1,77 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"auth-service/internal/application/services"
+	"auth-service/internal/config"
+	"auth-service/internal/domain/entities"
+	"auth-service/internal/domain/repositories"
+	"auth-service/internal/interfaces/http/middleware"
+	"auth-service/pkg/health"
+	"auth-service/pkg/logger"
+)
+
+const testServiceToken = "0123456789abcdef0123456789abcdef"
+
+// statsRepo serves the token stats scan; anything else panics through the
+// nil embedded interface.
+type statsRepo struct {
+	repositories.TokenRepository
+	scans int
+}
+
+func (r *statsRepo) ScanTokenStats(ctx context.Context, cursor uint64, count int64) (*entities.TokenStatsPage, error) {
+	r.scans++
+	return &entities.TokenStatsPage{AccessTokens: 2, RefreshTokens: 1}, nil
+}
+
+func serveTokenStats(repo *statsRepo, token, provided string) int {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("error")
+	authService := services.NewAuthService(repo, nil, nil, nil, config.JWTConfig{}, config.GoogleConfig{}, config.AuthFailureLimitConfig{}, log)
+	router := gin.New()
+	SetupAuthRoutes(router, authService, config.GoogleConfig{}, "private_network", token, config.CORSConfig{}, health.NewChecker(), log)
+
+	req := httptest.NewRequest(http.MethodGet, "/internal/tokens/stats", nil)
+	if provided != "" {
+		req.Header.Set(middleware.InternalTokenHeader, provided)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestTokenStatsAcceptsServiceToken(t *testing.T) {
+	repo := &statsRepo{}
+	if code := serveTokenStats(repo, testServiceToken, testServiceToken); code != http.StatusOK {
+		t.Fatalf("expected the matching token to pass, got %d", code)
+	}
+	if repo.scans != 1 {
+		t.Fatalf("expected one stats scan, got %d", repo.scans)
+	}
+}
+
+func TestTokenStatsRejectsMissingOrWrongToken(t *testing.T) {
+	repo := &statsRepo{}
+	cases := []struct {
+		name, token, provided string
+	}{
+		{"missing token", testServiceToken, ""},
+		{"wrong token", testServiceToken, "wrong"},
+		{"no configured token", "", ""},
+	}
+	for _, tc := range cases {
+		if code := serveTokenStats(repo, tc.token, tc.provided); code != http.StatusForbidden {
+			t.Fatalf("%s: expected 403, got %d", tc.name, code)
+		}
+	}
+	if repo.scans != 0 {
+		t.Fatalf("expected no Redis scan for rejected calls, got %d", repo.scans)
+	}
+}
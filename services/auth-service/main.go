@@ -17,15 +17,21 @@ import (
 	"auth-service/internal/application/services"
 	"auth-service/internal/clients"
 	"auth-service/internal/config"
+	domainServices "auth-service/internal/domain/services"
 	"auth-service/internal/infrastructure/oauth"
 	"auth-service/internal/infrastructure/redis"
 	grpcinterface "auth-service/internal/interfaces/grpc"
 	"auth-service/internal/interfaces/http/routes"
+	"auth-service/pkg/health"
+	"auth-service/pkg/jwt"
 	"auth-service/pkg/logger"
 	"auth-service/pkg/metrics"
+	"auth-service/pkg/retry"
+	"auth-service/pkg/tracing"
 
 	"github.com/gin-gonic/gin"
 	authv1 "github.com/nikitashilov/microblog_grpc/proto/auth/v1"
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	grpc "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
@@ -40,19 +46,47 @@ func main() {
 	}
 
 	// Initialize logger
-	appLogger := logger.New(cfg.LogLevel)
-	metrics.Init()
+	appLogger := logger.NewWithOptions(logger.Options{Service: "auth-service", Level: cfg.LogLevel, Format: cfg.LogFormat})
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{ServiceName: "auth-service", Endpoint: cfg.Tracing.Endpoint, SampleRatio: cfg.Tracing.SampleRatio})
+	if err != nil {
+		appLogger.Fatal("Failed to initialize tracing: " + err.Error())
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			appLogger.Warn("Failed to flush traces", logger.Err(err))
+		}
+	}()
+	if cfg.MetricsEnabled {
+		metrics.Init()
+	}
 
 	// Initialize dependencies
 	tokenRepo := redis.NewTokenRepository(cfg.Redis)
-	googleProvider := oauth.NewGoogleProvider(cfg.Google)
+	if err := retry.Do(context.Background(), startupRetry(cfg.StartupRetry), appLogger, "redis", tokenRepo.Ping); err != nil {
+		appLogger.Fatal("Failed to connect to Redis: " + err.Error())
+	}
+	healthChecker := health.NewChecker().Register("redis", tokenRepo.Ping)
+	oauthProviders := map[string]domainServices.OAuthProvider{
+		domainServices.ProviderGoogle: oauth.NewGoogleProvider(cfg.Google),
+	}
+	if cfg.GitHub.Enabled() {
+		oauthProviders[domainServices.ProviderGitHub] = oauth.NewGitHubProvider(cfg.GitHub)
+	}
 	userClient, err := clients.NewUserClient(cfg.Services.UserGRPCAddr, cfg.GRPCTLS)
 	if err != nil {
 		log.Fatalf("Failed to create user gRPC client: %v", err)
 	}
 	defer userClient.Close()
 
-	authService := services.NewAuthService(tokenRepo, googleProvider, userClientAdapter{userClient}, cfg.JWT, cfg.Google, appLogger)
+	jwtManager, err := newJWTManager(cfg.JWT)
+	if err != nil {
+		appLogger.Fatal("Failed to configure JWT signing: " + err.Error())
+	}
+
+	authService := services.NewAuthService(tokenRepo, oauthProviders, userClientAdapter{userClient}, jwtManager, cfg.JWT, cfg.Google, cfg.AuthFailureLimit, appLogger)
 
 	// Setup gRPC server with options
 	grpcOptions := []grpc.ServerOption{
@@ -68,6 +102,8 @@ func main() {
 			Timeout:               1 * time.Second,
 		}),
 		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			tracing.UnaryServerInterceptor(),
 			metrics.UnaryServerInterceptor("auth-service"),
 			unaryServerLoggingInterceptor(appLogger),
 		),
@@ -81,7 +117,7 @@ func main() {
 	}
 
 	grpcServer := grpc.NewServer(grpcOptions...)
-	authv1.RegisterAuthServiceServer(grpcServer, grpcinterface.NewAuthServer(authService, appLogger))
+	authv1.RegisterAuthServiceServer(grpcServer, grpcinterface.NewAuthServer(authService, healthChecker, appLogger))
 	if cfg.EnableGRPCReflection {
 		grpc_reflection.Register(grpcServer)
 	}
@@ -101,11 +137,14 @@ func main() {
 	// Setup HTTP server
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(tracing.GinMiddleware())
 	router.Use(metrics.GinMiddleware("auth-service"))
-	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	if cfg.MetricsEnabled {
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
 
 	// Setup routes
-	routes.SetupAuthRoutes(router, authService, appLogger)
+	routes.SetupAuthRoutes(router, authService, cfg.Google, cfg.InternalHTTPTrustMode, cfg.InternalServiceToken, cfg.CORS, healthChecker, appLogger)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -147,12 +186,16 @@ func main() {
 // userClientAdapter adapts *clients.UserClient to services.UserServiceClient (return type UserInfoResult).
 type userClientAdapter struct{ *clients.UserClient }
 
-func (a userClientAdapter) CreateUser(ctx context.Context, id, email, name, picture, password string) (services.UserInfoResult, error) {
-	return a.UserClient.CreateUser(ctx, id, email, name, picture, password)
+func (a userClientAdapter) CreateUser(ctx context.Context, id, email, name, picture, password string, emailVerified bool) (services.UserInfoResult, error) {
+	return a.UserClient.CreateUser(ctx, id, email, name, picture, password, emailVerified)
 }
 
-func (a userClientAdapter) GetUserByEmail(ctx context.Context, email string) (services.UserInfoResult, error) {
-	return a.UserClient.GetUserByEmail(ctx, email)
+func (a userClientAdapter) GetUser(ctx context.Context, id string) (services.UserInfoResult, error) {
+	return a.UserClient.GetUser(ctx, id)
+}
+
+func (a userClientAdapter) GetUserByEmail(ctx context.Context, email string, reactivate bool) (services.UserInfoResult, error) {
+	return a.UserClient.GetUserByEmail(ctx, email, reactivate)
 }
 
 func (a userClientAdapter) ValidateCredentials(ctx context.Context, email, password string) (services.UserInfoResult, error) {
@@ -160,16 +203,21 @@ func (a userClientAdapter) ValidateCredentials(ctx context.Context, email, passw
 }
 
 // unaryServerLoggingInterceptor logs gRPC server requests and responses
-func unaryServerLoggingInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
+func unaryServerLoggingInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 		resp, err := handler(ctx, req)
 		duration := time.Since(start)
 
+		fields := []logger.Field{
+			logger.F("method", info.FullMethod),
+			logger.F("request_id", requestid.FromContext(ctx)),
+			logger.F("duration_ms", duration.Milliseconds()),
+		}
 		if err != nil {
-			logger.Warn(fmt.Sprintf("gRPC method %s failed: %v (duration: %v)", info.FullMethod, err, duration))
+			log.Warn("gRPC method failed", append(fields, logger.Err(err))...)
 		} else {
-			logger.Debug(fmt.Sprintf("gRPC method %s succeeded (duration: %v)", info.FullMethod, duration))
+			log.Debug("gRPC method succeeded", fields...)
 		}
 
 		return resp, err
@@ -204,3 +252,26 @@ func buildServerTransportCredentials(tlsCfg config.GRPCTLSConfig) (credentials.T
 
 	return credentials.NewTLS(tlsConfig), nil
 }
+
+func newJWTManager(jwtCfg config.JWTConfig) (*jwt.Manager, error) {
+	if jwtCfg.Algorithm != jwt.AlgorithmRS256 {
+		return jwt.NewManager(jwtCfg.Secret, jwtCfg.Issuer), nil
+	}
+
+	privateKey, err := jwt.LoadRSAPrivateKey(jwtCfg.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load JWT signing key: %w", err)
+	}
+
+	return jwt.NewRS256Manager(privateKey, jwtCfg.KeyID, jwtCfg.Issuer), nil
+}
+
+// startupRetry converts the configured limits for retry.Do.
+func startupRetry(cfg config.StartupRetryConfig) retry.Config {
+	return retry.Config{
+		Attempts:       cfg.Attempts,
+		InitialBackoff: time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+		MaxWait:        time.Duration(cfg.MaxWaitSeconds) * time.Second,
+	}
+}
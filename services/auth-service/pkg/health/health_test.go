@@ -0,0 +1,28 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckerReportsFailingDependency(t *testing.T) {
+	checker := NewChecker().
+		Register("postgres", func(context.Context) error { return nil }).
+		Register("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	statuses, err := checker.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected an error for the failing dependency")
+	}
+	if statuses["postgres"] != StatusHealthy || statuses["redis"] != StatusUnhealthy {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+}
+
+func TestNilCheckerIsHealthy(t *testing.T) {
+	var checker *Checker
+	if _, err := checker.Run(context.Background()); err != nil {
+		t.Fatalf("expected a nil checker to be healthy, got %v", err)
+	}
+}
@@ -2,62 +2,132 @@ package jwt
 
 import (
 	"auth-service/internal/domain/entities"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
 type Manager struct {
+	algorithm  string
 	secret     []byte
-	algorithms []string
-	//issuer     string
+	privateKey *rsa.PrivateKey
+	keyID      string
+	publicKeys map[string]*rsa.PublicKey
+	issuer     string
 }
 
 type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
 	Type   string `json:"type"`
 	jwt.RegisteredClaims
 }
 
+// JWK is the public half of an RSA signing key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewManager signs and verifies tokens with a shared HS256 secret.
 func NewManager(secret, issuer string) *Manager {
 	return &Manager{
-		secret:     []byte(secret),
-		algorithms: []string{"HS256"}, // Explicitly allow only secure algorithms
-		//issuer:     issuer,
+		algorithm: AlgorithmHS256,
+		secret:    []byte(secret),
+		issuer:    issuer,
+	}
+}
+
+// NewRS256Manager signs tokens with privateKey and advertises its public key
+// under keyID. An empty keyID defaults to the key's RFC 7638 thumbprint.
+func NewRS256Manager(privateKey *rsa.PrivateKey, keyID, issuer string) *Manager {
+	if keyID == "" {
+		keyID = thumbprint(&privateKey.PublicKey)
+	}
+
+	return &Manager{
+		algorithm:  AlgorithmRS256,
+		privateKey: privateKey,
+		keyID:      keyID,
+		publicKeys: map[string]*rsa.PublicKey{keyID: &privateKey.PublicKey},
+		issuer:     issuer,
 	}
 }
 
+// LoadRSAPrivateKey reads a PEM encoded (PKCS#1 or PKCS#8) RSA private key.
+func LoadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return key, nil
+}
+
 func (m *Manager) GenerateToken(tokenClaims *entities.TokenClaims, ttl time.Duration) (string, error) {
+	// A unique token ID keeps tokens minted within the same second distinct,
+	// which refresh-token rotation relies on.
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+
 	now := time.Now()
 	claims := &Claims{
 		UserID: tokenClaims.UserID,
 		Email:  tokenClaims.Email,
+		Role:   tokenClaims.Role,
 		Type:   tokenClaims.Type,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(jti),
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "auth-service",
+			Issuer:    m.issuer,
 			Subject:   tokenClaims.UserID,
 		},
 	}
 
+	if m.algorithm == AlgorithmRS256 {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = m.keyID
+		return token.SignedString(m.privateKey)
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(m.secret)
 }
 
 func (m *Manager) ValidateToken(tokenString string) (*entities.TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate algorithm
-		if method, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		} else if method.Alg() != "HS256" {
-			return nil, fmt.Errorf("unexpected signing algorithm: %v", method.Alg())
-		}
-		return m.secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, m.verificationKey)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -73,13 +143,76 @@ func (m *Manager) ValidateToken(tokenString string) (*entities.TokenClaims, erro
 	}
 
 	// Validate issuer
-	//if claims.Issuer != m.issuer {
-	//	return nil, fmt.Errorf("invalid token issuer")
-	//}
+	if claims.Issuer != m.issuer {
+		return nil, fmt.Errorf("invalid token issuer")
+	}
 
 	return &entities.TokenClaims{
 		UserID: claims.UserID,
 		Email:  claims.Email,
+		Role:   claims.Role,
 		Type:   claims.Type,
 	}, nil
 }
+
+// verificationKey selects the key for the token's alg and kid. Only the
+// manager's configured algorithm is accepted, so an RS256 deployment never
+// falls back to HMAC verification (alg confusion).
+func (m *Manager) verificationKey(token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	if alg != m.algorithm {
+		return nil, fmt.Errorf("unexpected signing algorithm: %v", token.Header["alg"])
+	}
+
+	switch alg {
+	case AlgorithmHS256:
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return m.secret, nil
+	case AlgorithmRS256:
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.publicKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %q", kid)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing algorithm: %v", alg)
+	}
+}
+
+// JWKS returns the public verification keys. It is empty for HS256, whose
+// shared secret must never be published.
+func (m *Manager) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for kid, key := range m.publicKeys {
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: AlgorithmRS256,
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	return set
+}
+
+func thumbprint(key *rsa.PublicKey) string {
+	// RFC 7638: hash the required members in lexicographic order.
+	members, _ := json.Marshal(struct {
+		E   string `json:"e"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+	}{
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+	})
+	sum := sha256.Sum256(members)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain/entities"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const testSecret = "01234567890123456789012345678901"
+
+func TestGenerateTokenUsesConfiguredIssuer(t *testing.T) {
+	manager := NewManager(testSecret, "https://auth.example.com")
+
+	token, err := manager.GenerateToken(&entities.TokenClaims{UserID: "user-1", Email: "dev@example.com", Type: "access"}, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("expected token with custom issuer to validate, got %v", err)
+	}
+	if claims.UserID != "user-1" || claims.Type != "access" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestValidateTokenRejectsForeignIssuer(t *testing.T) {
+	issuer := NewManager(testSecret, "other-service")
+	token, err := issuer.GenerateToken(&entities.TokenClaims{UserID: "user-1", Type: "access"}, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	if _, err := NewManager(testSecret, "auth-service").ValidateToken(token); err == nil {
+		t.Fatal("expected token from a different issuer to be rejected")
+	}
+}
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return key
+}
+
+func TestRS256TokenRoundTripWithKid(t *testing.T) {
+	manager := NewRS256Manager(newTestRSAKey(t), "key-1", "auth-service")
+
+	token, err := manager.GenerateToken(&entities.TokenClaims{UserID: "user-1", Type: "access"}, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, &Claims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified: %v", err)
+	}
+	if parsed.Header["alg"] != "RS256" || parsed.Header["kid"] != "key-1" {
+		t.Fatalf("unexpected header: %v", parsed.Header)
+	}
+
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.UserID != "user-1" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestRS256ManagerRejectsHS256Token(t *testing.T) {
+	token, err := NewManager(testSecret, "auth-service").GenerateToken(&entities.TokenClaims{UserID: "user-1", Type: "access"}, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	if _, err := NewRS256Manager(newTestRSAKey(t), "key-1", "auth-service").ValidateToken(token); err == nil {
+		t.Fatal("expected HS256 token to be rejected by an RS256 manager")
+	}
+}
+
+func TestRS256ManagerRejectsUnknownKid(t *testing.T) {
+	key := newTestRSAKey(t)
+	token, err := NewRS256Manager(key, "old-key", "auth-service").GenerateToken(&entities.TokenClaims{UserID: "user-1", Type: "access"}, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	if _, err := NewRS256Manager(key, "new-key", "auth-service").ValidateToken(token); err == nil {
+		t.Fatal("expected token with an unknown kid to be rejected")
+	}
+}
+
+func TestJWKSPublishesRSAPublicKey(t *testing.T) {
+	key := newTestRSAKey(t)
+	manager := NewRS256Manager(key, "", "auth-service")
+
+	set := manager.JWKS()
+	if len(set.Keys) != 1 {
+		t.Fatalf("expected one key, got %d", len(set.Keys))
+	}
+	jwk := set.Keys[0]
+	if jwk.Kty != "RSA" || jwk.Alg != "RS256" || jwk.Use != "sig" || jwk.Kid == "" {
+		t.Fatalf("unexpected jwk: %+v", jwk)
+	}
+	if jwk.E != "AQAB" {
+		t.Fatalf("expected exponent AQAB, got %q", jwk.E)
+	}
+
+	if len(NewManager(testSecret, "auth-service").JWKS().Keys) != 0 {
+		t.Fatal("expected HS256 manager to publish no keys")
+	}
+}
+
+func TestLoadRSAPrivateKey(t *testing.T) {
+	key := newTestRSAKey(t)
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(path, pemBytes, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	loaded, err := LoadRSAPrivateKey(path)
+	if err != nil {
+		t.Fatalf("LoadRSAPrivateKey: %v", err)
+	}
+	if loaded.N.Cmp(key.N) != 0 {
+		t.Fatal("loaded key does not match")
+	}
+
+	if _, err := LoadRSAPrivateKey(filepath.Join(t.TempDir(), "missing.pem")); err == nil || !strings.Contains(err.Error(), "read") {
+		t.Fatalf("expected read error for missing key, got %v", err)
+	}
+}
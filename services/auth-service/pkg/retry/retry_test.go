@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"auth-service/pkg/logger"
+)
+
+func quietLogger() *logger.Logger {
+	l := logger.New("error")
+	l.SetOutput(io.Discard)
+	return l
+}
+
+func TestDoRetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), Config{Attempts: 5, InitialBackoff: time.Millisecond}, quietLogger(), "redis", func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDoStopsAfterAttempts(t *testing.T) {
+	calls := 0
+	refused := errors.New("connection refused")
+	err := Do(context.Background(), Config{Attempts: 3, InitialBackoff: time.Millisecond}, quietLogger(), "redis", func(context.Context) error {
+		calls++
+		return refused
+	})
+	if !errors.Is(err, refused) {
+		t.Fatalf("expected the last error to be wrapped, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDoGivesUpAfterMaxWait(t *testing.T) {
+	start := time.Now()
+	err := Do(context.Background(), Config{Attempts: 100, InitialBackoff: 20 * time.Millisecond, MaxWait: 50 * time.Millisecond}, quietLogger(), "redis", func(context.Context) error {
+		return errors.New("connection refused")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected Do to give up after MaxWait, took %s", elapsed)
+	}
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ListDeadLettersRequest struct {
+	Queue  string `form:"queue,default=notifications"`
+	Offset int    `form:"offset,default=0" binding:"omitempty,min=0,max=900"`
+	Limit  int    `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
+}
+
+// DeadLetterResponse is one dead-lettered message. Payload holds the body
+// when it is JSON and PayloadText otherwise.
+type DeadLetterResponse struct {
+	Position     int                    `json:"position"`
+	MessageID    string                 `json:"message_id,omitempty"`
+	RoutingKey   string                 `json:"routing_key,omitempty"`
+	Queue        string                 `json:"queue,omitempty"`
+	Reason       string                 `json:"reason,omitempty"`
+	DeathCount   int64                  `json:"death_count"`
+	ReplayCount  int64                  `json:"replay_count"`
+	FirstDeathAt *time.Time             `json:"first_death_at,omitempty"`
+	ContentType  string                 `json:"content_type,omitempty"`
+	Headers      map[string]interface{} `json:"headers,omitempty"`
+	Payload      json.RawMessage        `json:"payload,omitempty"`
+	PayloadText  string                 `json:"payload_text,omitempty"`
+}
+
+// ListDeadLettersResponse is one page of a dead-letter queue. Total is the
+// number of messages in the queue when it was read.
+type ListDeadLettersResponse struct {
+	Queue    string                `json:"queue"`
+	Messages []*DeadLetterResponse `json:"messages"`
+	Offset   int                   `json:"offset"`
+	Limit    int                   `json:"limit"`
+	Total    int                   `json:"total"`
+	HasMore  bool                  `json:"has_more"`
+}
+
+// DeadLetterActionRequest selects dead-lettered messages by message ID to
+// replay or purge.
+type DeadLetterActionRequest struct {
+	Queue      string   `json:"queue"`
+	MessageIDs []string `json:"message_ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+// DeadLetterActionResponse lists the messages acted on and the requested IDs
+// that were not found in the queue.
+type DeadLetterActionResponse struct {
+	Queue    string   `json:"queue"`
+	Done     []string `json:"done"`
+	NotFound []string `json:"not_found"`
+}
@@ -3,36 +3,93 @@ package dto
 import "time"
 
 type NotificationResponse struct {
-	ID        string                 `json:"id"`
-	UserID    string                 `json:"user_id"`
-	Type      string                 `json:"type"`
-	Title     string                 `json:"title"`
-	Message   string                 `json:"message"`
-	Data      map[string]interface{} `json:"data,omitempty"`
-	Read      bool                   `json:"read"`
-	CreatedAt time.Time              `json:"created_at"`
-	ReadAt    *time.Time             `json:"read_at,omitempty"`
+	ID         string                 `json:"id"`
+	UserID     string                 `json:"user_id"`
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Message    string                 `json:"message"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Priority   string                 `json:"priority"`
+	Read       bool                   `json:"read"`
+	CreatedAt  time.Time              `json:"created_at"`
+	ReadAt     *time.Time             `json:"read_at,omitempty"`
+	ArchivedAt *time.Time             `json:"archived_at,omitempty"`
+}
+
+// AdminNotificationResponse is the internal view of a notification, surfacing
+// the broker message it was derived from for support and debugging.
+type AdminNotificationResponse struct {
+	NotificationResponse
+	SourceMessageID string `json:"source_message_id,omitempty"`
+}
+
+type AdminListNotificationsRequest struct {
+	Limit  int    `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+// AdminListNotificationsResponse is one page of the admin list. NextCursor is
+// passed back as the cursor query parameter to fetch the following page.
+type AdminListNotificationsResponse struct {
+	Notifications []*AdminNotificationResponse `json:"notifications"`
+	Limit         int                          `json:"limit"`
+	NextCursor    string                       `json:"next_cursor,omitempty"`
+	HasMore       bool                         `json:"has_more"`
+}
+
+type CleanupNotificationsRequest struct {
+	OlderThanDays int `form:"older_than_days" binding:"required,min=1,max=3650"`
+}
+
+type CleanupNotificationsResponse struct {
+	Deleted       int64 `json:"deleted"`
+	OlderThanDays int   `json:"older_than_days"`
 }
 
 type ListNotificationsRequest struct {
-	Limit  int  `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
-	Offset int  `form:"offset,default=0" binding:"omitempty,min=0"`
-	Unread bool `form:"unread,default=false"`
+	Limit    int    `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset,default=0" binding:"omitempty,min=0"`
+	Unread   bool   `form:"unread,default=false"`
+	Type     string `form:"type"`
+	Priority string `form:"priority"`
+	// IncludeArchived lists archived notifications alongside the inbox.
+	IncludeArchived bool `form:"include_archived,default=false"`
 }
 
+// ListNotificationsResponse is one page of the user's notifications. Total
+// counts every notification matching the request's filters; UnreadCount is
+// the user's overall unread count, whatever the filters.
 type ListNotificationsResponse struct {
 	Notifications []*NotificationResponse `json:"notifications"`
 	Limit         int                     `json:"limit"`
 	Offset        int                     `json:"offset"`
-	Total         int                     `json:"total"`
+	Total         int64                   `json:"total"`
+	HasMore       bool                    `json:"has_more"`
 	UnreadCount   int64                   `json:"unread_count"`
 }
 
+type NotificationCountsRequest struct {
+	Unread bool `form:"unread,default=false"`
+}
+
+// NotificationCountsResponse counts the user's notifications per type, only
+// unread ones when UnreadOnly. Every known type is present, with 0 when the
+// user has none, so clients get the same shape every time.
+type NotificationCountsResponse struct {
+	Counts     map[string]int64 `json:"counts"`
+	Total      int64            `json:"total"`
+	UnreadOnly bool             `json:"unread_only"`
+}
+
 type MarkAsReadRequest struct {
 	NotificationIDs []string `json:"notification_ids,omitempty"`
 	MarkAll         bool     `json:"mark_all,omitempty"`
 }
 
+type MarkAsUnreadRequest struct {
+	NotificationIDs []string `json:"notification_ids"`
+}
+
 type CreateNotificationRequest struct {
 	// UserID is always set from the authenticated caller, never from the request
 	// body, so a client cannot create notifications targeting another user.
@@ -41,6 +98,8 @@ type CreateNotificationRequest struct {
 	Title   string                 `json:"title" binding:"required,min=1,max=200"`
 	Message string                 `json:"message" binding:"required,min=1,max=1000"`
 	Data    map[string]interface{} `json:"data,omitempty"`
+	// Priority is low, normal or high; omitted means normal.
+	Priority string `json:"priority,omitempty"`
 }
 
 type NotificationStatsResponse struct {
@@ -48,3 +107,22 @@ type NotificationStatsResponse struct {
 	UnreadNotifications int64            `json:"unread_notifications"`
 	NotificationsByType map[string]int64 `json:"notifications_by_type"`
 }
+
+// NotificationPreference is one type/channel setting.
+type NotificationPreference struct {
+	Type    string `json:"type" binding:"required"`
+	Channel string `json:"channel,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+type NotificationPreferencesResponse struct {
+	Preferences []*NotificationPreference `json:"preferences"`
+	DigestMode  string                    `json:"digest_mode"`
+}
+
+// UpdatePreferencesRequest changes type/channel settings, the digest mode
+// (immediate, hourly or daily), or both; at least one must be given.
+type UpdatePreferencesRequest struct {
+	Preferences []NotificationPreference `json:"preferences" binding:"max=50,dive"`
+	DigestMode  string                   `json:"digest_mode,omitempty"`
+}
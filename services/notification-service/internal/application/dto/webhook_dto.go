@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateWebhookRequest subscribes URL to events. A secret is generated when
+// none is given; it is only ever returned in the create response.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret,omitempty" binding:"omitempty,min=16,max=255"`
+	Events []string `json:"events" binding:"required,min=1"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// UpdateWebhookRequest changes the fields that are set and leaves the rest.
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url,omitempty"`
+	Secret *string  `json:"secret,omitempty" binding:"omitempty,min=16,max=255"`
+	Events []string `json:"events,omitempty"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Limit int `form:"limit,default=50" binding:"omitempty,min=1,max=200"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID         string    `json:"id"`
+	Event      string    `json:"event"`
+	MessageID  string    `json:"message_id,omitempty"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookPayload is the body POSTed to subscribers. ID is the same for every
+// subscriber and every redelivery of the event; Data is the post event as
+// published by post-service.
+type WebhookPayload struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
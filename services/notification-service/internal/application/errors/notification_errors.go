@@ -31,4 +31,11 @@ var (
 	ErrInvalidRequest             = NewNotificationError("INVALID_REQUEST", "Invalid request parameters", http.StatusBadRequest)
 	ErrServiceUnavailable         = NewNotificationError("SERVICE_UNAVAILABLE", "Notification service temporarily unavailable", http.StatusServiceUnavailable)
 	ErrMessageProcessingFailed    = NewNotificationError("MESSAGE_PROCESSING_FAILED", "Failed to process message", http.StatusInternalServerError)
+	ErrInvalidPreferences         = NewNotificationError("INVALID_PREFERENCES", "Unknown notification type, channel or digest mode", http.StatusBadRequest)
+	ErrPreferencesFailed          = NewNotificationError("PREFERENCES_FAILED", "Failed to process notification preferences", http.StatusInternalServerError)
+	ErrWebhookNotFound            = NewNotificationError("WEBHOOK_NOT_FOUND", "Webhook subscription not found", http.StatusNotFound)
+	ErrInvalidWebhook             = NewNotificationError("INVALID_WEBHOOK", "Webhook URL must be an absolute http(s) URL and events must be post.created, post.updated or post.deleted", http.StatusBadRequest)
+	ErrWebhookFailed              = NewNotificationError("WEBHOOK_FAILED", "Failed to process webhook subscription", http.StatusInternalServerError)
+	ErrUnknownDeadLetterQueue     = NewNotificationError("UNKNOWN_DEAD_LETTER_QUEUE", "Unknown dead-letter queue", http.StatusBadRequest)
+	ErrDeadLetterQueueFailed      = NewNotificationError("DEAD_LETTER_QUEUE_FAILED", "Failed to process dead-letter queue", http.StatusBadGateway)
 )
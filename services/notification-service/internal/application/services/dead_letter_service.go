@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"notification-service/internal/application/dto"
+	"notification-service/internal/application/errors"
+	"notification-service/internal/domain/entities"
+	"notification-service/internal/domain/repositories"
+	"notification-service/pkg/logger"
+)
+
+// DefaultDeadLetterQueue is the queue used when a request names none.
+const DefaultDeadLetterQueue = "notifications"
+
+// DeadLetterService lets operators inspect dead-lettered messages and replay
+// or purge them. Queues are registered by name, e.g. "notifications" and
+// "webhooks".
+type DeadLetterService struct {
+	queues map[string]repositories.DeadLetterQueue
+	logger *logger.Logger
+}
+
+func NewDeadLetterService(queues map[string]repositories.DeadLetterQueue, logger *logger.Logger) *DeadLetterService {
+	return &DeadLetterService{queues: queues, logger: logger}
+}
+
+func (s *DeadLetterService) List(ctx context.Context, req *dto.ListDeadLettersRequest) (*dto.ListDeadLettersResponse, error) {
+	name, queue, err := s.queue(req.Queue)
+	if err != nil {
+		return nil, err
+	}
+
+	letters, total, err := queue.List(ctx, req.Offset, req.Limit)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to list dead letters in %s: %v", name, err))
+		return nil, errors.ErrDeadLetterQueueFailed
+	}
+
+	messages := make([]*dto.DeadLetterResponse, 0, len(letters))
+	for _, letter := range letters {
+		messages = append(messages, deadLetterResponse(letter))
+	}
+	return &dto.ListDeadLettersResponse{
+		Queue:    name,
+		Messages: messages,
+		Offset:   req.Offset,
+		Limit:    req.Limit,
+		Total:    total,
+		HasMore:  req.Offset+len(messages) < total,
+	}, nil
+}
+
+// Replay publishes the selected messages back to the queue they died in with
+// their original message IDs, so notification processing skips any it had
+// already handled.
+func (s *DeadLetterService) Replay(ctx context.Context, req *dto.DeadLetterActionRequest) (*dto.DeadLetterActionResponse, error) {
+	name, queue, err := s.queue(req.Queue)
+	if err != nil {
+		return nil, err
+	}
+
+	replayed, err := queue.Replay(ctx, req.MessageIDs)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to replay dead letters in %s after %d: %v", name, len(replayed), err))
+		return nil, errors.ErrDeadLetterQueueFailed
+	}
+	s.logger.Info(fmt.Sprintf("replayed %d of %d dead letters from %s", len(replayed), len(req.MessageIDs), name))
+	return deadLetterActionResponse(name, req.MessageIDs, replayed), nil
+}
+
+func (s *DeadLetterService) Purge(ctx context.Context, req *dto.DeadLetterActionRequest) (*dto.DeadLetterActionResponse, error) {
+	name, queue, err := s.queue(req.Queue)
+	if err != nil {
+		return nil, err
+	}
+
+	purged, err := queue.Purge(ctx, req.MessageIDs)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to purge dead letters in %s after %d: %v", name, len(purged), err))
+		return nil, errors.ErrDeadLetterQueueFailed
+	}
+	s.logger.Info(fmt.Sprintf("purged %d of %d dead letters from %s", len(purged), len(req.MessageIDs), name))
+	return deadLetterActionResponse(name, req.MessageIDs, purged), nil
+}
+
+func (s *DeadLetterService) queue(name string) (string, repositories.DeadLetterQueue, error) {
+	if name == "" {
+		name = DefaultDeadLetterQueue
+	}
+	queue, ok := s.queues[name]
+	if !ok {
+		return "", nil, errors.ErrUnknownDeadLetterQueue
+	}
+	return name, queue, nil
+}
+
+func deadLetterResponse(letter *entities.DeadLetter) *dto.DeadLetterResponse {
+	response := &dto.DeadLetterResponse{
+		Position:     letter.Position,
+		MessageID:    letter.MessageID,
+		RoutingKey:   letter.RoutingKey,
+		Queue:        letter.Queue,
+		Reason:       letter.Reason,
+		DeathCount:   letter.DeathCount,
+		ReplayCount:  letter.ReplayCount,
+		FirstDeathAt: letter.FirstDeathAt,
+		ContentType:  letter.ContentType,
+		Headers:      letter.Headers,
+	}
+	if json.Valid(letter.Body) {
+		response.Payload = letter.Body
+	} else {
+		response.PayloadText = string(letter.Body)
+	}
+	return response
+}
+
+func deadLetterActionResponse(queue string, requested, done []string) *dto.DeadLetterActionResponse {
+	found := make(map[string]bool, len(done))
+	for _, id := range done {
+		found[id] = true
+	}
+	notFound := []string{}
+	for _, id := range requested {
+		if !found[id] {
+			notFound = append(notFound, id)
+		}
+	}
+	if done == nil {
+		done = []string{}
+	}
+	return &dto.DeadLetterActionResponse{Queue: queue, Done: done, NotFound: notFound}
+}
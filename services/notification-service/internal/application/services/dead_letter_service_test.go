@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"notification-service/internal/application/dto"
+	appErrors "notification-service/internal/application/errors"
+	"notification-service/internal/domain/entities"
+	"notification-service/internal/domain/repositories"
+	"notification-service/pkg/logger"
+)
+
+// memoryDeadLetters is a dead-letter queue over a slice.
+type memoryDeadLetters struct {
+	letters  []*entities.DeadLetter
+	replayed []string
+}
+
+func (q *memoryDeadLetters) List(ctx context.Context, offset, limit int) ([]*entities.DeadLetter, int, error) {
+	if offset >= len(q.letters) {
+		return nil, len(q.letters), nil
+	}
+	return q.letters[offset:min(offset+limit, len(q.letters))], len(q.letters), nil
+}
+func (q *memoryDeadLetters) Replay(ctx context.Context, messageIDs []string) ([]string, error) {
+	done, err := q.Purge(ctx, messageIDs)
+	q.replayed = append(q.replayed, done...)
+	return done, err
+}
+func (q *memoryDeadLetters) Purge(ctx context.Context, messageIDs []string) ([]string, error) {
+	wanted := make(map[string]bool)
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+	var done []string
+	var kept []*entities.DeadLetter
+	for _, letter := range q.letters {
+		if wanted[letter.MessageID] {
+			done = append(done, letter.MessageID)
+			delete(wanted, letter.MessageID)
+			continue
+		}
+		kept = append(kept, letter)
+	}
+	q.letters = kept
+	return done, nil
+}
+
+func newTestDeadLetterService(queue *memoryDeadLetters) *DeadLetterService {
+	return NewDeadLetterService(map[string]repositories.DeadLetterQueue{DefaultDeadLetterQueue: queue}, logger.New("info"))
+}
+
+func TestDeadLetterList_PagesAndDecodesPayloads(t *testing.T) {
+	queue := &memoryDeadLetters{letters: []*entities.DeadLetter{
+		{Position: 0, MessageID: "m1", RoutingKey: "post.created", DeathCount: 1, Body: []byte(`{"post_id":"p1"}`)},
+		{Position: 1, MessageID: "m2", RoutingKey: "post.updated", DeathCount: 2, Body: []byte("not json")},
+		{Position: 2, MessageID: "m3", RoutingKey: "post.deleted", DeathCount: 1, Body: []byte(`{}`)},
+	}}
+	svc := newTestDeadLetterService(queue)
+
+	resp, err := svc.List(context.Background(), &dto.ListDeadLettersRequest{Offset: 0, Limit: 2})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if resp.Queue != DefaultDeadLetterQueue || resp.Total != 3 || !resp.HasMore || len(resp.Messages) != 2 {
+		t.Fatalf("expected the first 2 of 3 messages, got %+v", resp)
+	}
+	if string(resp.Messages[0].Payload) != `{"post_id":"p1"}` || resp.Messages[0].PayloadText != "" {
+		t.Errorf("expected a JSON payload, got %+v", resp.Messages[0])
+	}
+	if resp.Messages[1].Payload != nil || resp.Messages[1].PayloadText != "not json" || resp.Messages[1].DeathCount != 2 {
+		t.Errorf("expected a text payload and the death count, got %+v", resp.Messages[1])
+	}
+}
+
+func TestDeadLetterReplay_ReportsMissingIDs(t *testing.T) {
+	queue := &memoryDeadLetters{letters: []*entities.DeadLetter{{MessageID: "m1"}, {MessageID: "m2"}}}
+	svc := newTestDeadLetterService(queue)
+
+	resp, err := svc.Replay(context.Background(), &dto.DeadLetterActionRequest{MessageIDs: []string{"m2", "gone"}})
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Done, []string{"m2"}) || !reflect.DeepEqual(resp.NotFound, []string{"gone"}) {
+		t.Fatalf("expected m2 replayed and gone not found, got %+v", resp)
+	}
+	if len(queue.letters) != 1 || queue.letters[0].MessageID != "m1" {
+		t.Fatalf("expected only m1 left in the queue, got %+v", queue.letters)
+	}
+
+	// Replaying again is harmless: the message is no longer dead-lettered.
+	resp, err = svc.Replay(context.Background(), &dto.DeadLetterActionRequest{MessageIDs: []string{"m2"}})
+	if err != nil || len(resp.Done) != 0 || len(queue.replayed) != 1 {
+		t.Fatalf("expected a second replay to do nothing, got %+v, %v", resp, err)
+	}
+}
+
+func TestDeadLetter_UnknownQueue(t *testing.T) {
+	svc := newTestDeadLetterService(&memoryDeadLetters{})
+
+	if _, err := svc.List(context.Background(), &dto.ListDeadLettersRequest{Queue: "search", Limit: 20}); err != appErrors.ErrUnknownDeadLetterQueue {
+		t.Fatalf("expected ErrUnknownDeadLetterQueue, got %v", err)
+	}
+	if _, err := svc.Purge(context.Background(), &dto.DeadLetterActionRequest{Queue: "search", MessageIDs: []string{"m1"}}); err != appErrors.ErrUnknownDeadLetterQueue {
+		t.Fatalf("expected ErrUnknownDeadLetterQueue, got %v", err)
+	}
+}
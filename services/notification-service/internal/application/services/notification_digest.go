@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification-service/internal/domain/entities"
+)
+
+// DigestConfig sets the digest windows. A zero window disables that mode's
+// job; users in it keep receiving their notifications in the feed only.
+type DigestConfig struct {
+	HourlyWindow time.Duration
+	DailyWindow  time.Duration
+	BatchSize    int // users summarised per mode and run
+	MaxSources   int // notifications coalesced into one digest; the rest roll into the next
+}
+
+// defaultDigestMaxSources applies when DigestConfig.MaxSources is unset.
+const defaultDigestMaxSources = 500
+
+// digestLabels describe each type in a digest summary as singular and plural.
+var digestLabels = map[entities.NotificationType][2]string{
+	entities.NotificationTypePostCreated:    {"new post from people you follow", "new posts from people you follow"},
+	entities.NotificationTypeOwnPostCreated: {"post you created", "posts you created"},
+	entities.NotificationTypePostUpdated:    {"post update", "post updates"},
+	entities.NotificationTypePostDeleted:    {"deleted post", "deleted posts"},
+	entities.NotificationTypeUserFollowed:   {"new follower", "new followers"},
+	entities.NotificationTypeCommentAdded:   {"new comment", "new comments"},
+	entities.NotificationTypeSystemAlert:    {"system alert", "system alerts"},
+}
+
+// digestWindow returns the window of mode, or 0 when it has no digest job.
+func (c DigestConfig) digestWindow(mode entities.DigestMode) time.Duration {
+	switch mode {
+	case entities.DigestModeHourly:
+		return c.HourlyWindow
+	case entities.DigestModeDaily:
+		return c.DailyWindow
+	default:
+		return 0
+	}
+}
+
+// RunDigests sends a digest to every hourly and daily user whose window has
+// passed, at most DigestConfig.BatchSize per mode. It is run on a ticker; a
+// failure for one user is logged and the rest still get theirs.
+func (s *NotificationService) RunDigests(ctx context.Context, now time.Time) error {
+	if s.digest.BatchSize <= 0 {
+		return nil
+	}
+
+	for _, mode := range []entities.DigestMode{entities.DigestModeHourly, entities.DigestModeDaily} {
+		window := s.digest.digestWindow(mode)
+		if window <= 0 {
+			continue
+		}
+
+		due, err := s.preferenceRepo.ListDueDigests(ctx, mode, now.Add(-window), s.digest.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list due %s digests: %w", mode, err)
+		}
+		for _, subscription := range due {
+			if err := s.sendDigest(ctx, subscription, now); err != nil {
+				s.logger.Error(fmt.Sprintf("failed to send %s digest to user %s: %v", mode, subscription.UserID, err))
+			}
+		}
+	}
+	return nil
+}
+
+// sendDigest coalesces the user's notifications since their last window into
+// one digest and delivers it. An empty window only moves the window forward.
+func (s *NotificationService) sendDigest(ctx context.Context, subscription *entities.DigestSubscription, now time.Time) error {
+	limit := s.digest.MaxSources
+	if limit <= 0 {
+		limit = defaultDigestMaxSources
+	}
+	sources, err := s.notificationRepo.GetUndigested(ctx, subscription.UserID, subscription.Since, now, limit)
+	if err != nil {
+		return err
+	}
+
+	// When the window holds more than one digest's worth, end it at the last
+	// notification taken so the remainder is summarised on the next run.
+	windowEnd := now
+	if len(sources) == limit {
+		windowEnd = sources[len(sources)-1].CreatedAt
+	}
+
+	digest := buildDigest(subscription.UserID, subscription.Mode, sources, subscription.Since, windowEnd)
+	sourceIDs := make([]string, 0, len(sources))
+	for _, source := range sources {
+		sourceIDs = append(sourceIDs, source.ID)
+	}
+	if digest != nil {
+		digest.ID = uuid.New().String()
+		if err := digest.IsValid(); err != nil {
+			return fmt.Errorf("invalid digest: %w", err)
+		}
+	}
+
+	completed, err := s.notificationRepo.CompleteDigest(ctx, subscription.UserID, subscription.Since, windowEnd, digest, sourceIDs)
+	if err != nil {
+		return err
+	}
+	if !completed || digest == nil {
+		return nil
+	}
+
+	s.unreadCache.Invalidate(ctx, digest.UserID)
+	s.hub.Publish(digest)
+	s.sendEmail(digest)
+	s.logger.Info(fmt.Sprintf("sent %s digest of %d notif(s) to user %s", subscription.Mode, len(sources), subscription.UserID))
+	return nil
+}
+
+// buildDigest summarises sources, counted by type, in one notification for
+// userID. It returns nil when there is nothing to summarise. The ID is left
+// for the caller to assign.
+func buildDigest(userID string, mode entities.DigestMode, sources []*entities.Notification, windowStart, windowEnd time.Time) *entities.Notification {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	counts := make(map[entities.NotificationType]int)
+	for _, source := range sources {
+		counts[source.Type]++
+	}
+
+	parts := make([]string, 0, len(counts))
+	byType := make(map[string]interface{}, len(counts))
+	for _, notificationType := range entities.NotificationTypes {
+		count, ok := counts[notificationType]
+		if !ok {
+			continue
+		}
+		byType[string(notificationType)] = count
+		label, known := digestLabels[notificationType]
+		if !known {
+			label = [2]string{string(notificationType) + " notification", string(notificationType) + " notifications"}
+		}
+		if count == 1 {
+			parts = append(parts, fmt.Sprintf("1 %s", label[0]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", count, label[1]))
+		}
+	}
+
+	noun := "notifications"
+	if len(sources) == 1 {
+		noun = "notification"
+	}
+
+	return &entities.Notification{
+		UserID:  userID,
+		Type:    entities.NotificationTypeDigest,
+		Title:   fmt.Sprintf("Your %s notification digest", mode),
+		Message: fmt.Sprintf("You have %d new %s: %s.", len(sources), noun, strings.Join(parts, ", ")),
+		Data: map[string]interface{}{
+			"digest_mode":  string(mode),
+			"count":        len(sources),
+			"by_type":      byType,
+			"window_start": windowStart.UTC().Format(time.RFC3339),
+			"window_end":   windowEnd.UTC().Format(time.RFC3339),
+		},
+		Priority: entities.PriorityNormal,
+		Read:     false,
+	}
+}
+
+// filterDigestUsers returns which of the notifications' recipients are in a
+// digest mode. A failed lookup delivers to everyone immediately rather than
+// holding notifications back.
+func (s *NotificationService) filterDigestUsers(ctx context.Context, notifications []*entities.Notification) map[string]bool {
+	userIDs := make([]string, 0, len(notifications))
+	seen := make(map[string]bool, len(notifications))
+	for _, notification := range notifications {
+		if !seen[notification.UserID] {
+			seen[notification.UserID] = true
+			userIDs = append(userIDs, notification.UserID)
+		}
+	}
+
+	digestUsers, err := s.preferenceRepo.FilterDigest(ctx, userIDs)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to read notif digest settings, delivering immediately: %v", err))
+		return nil
+	}
+	return digestUsers
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+	"time"
+
+	"notification-service/internal/domain/entities"
+)
+
+// emailSendTimeout bounds one background delivery, including the preference
+// and address lookups.
+const emailSendTimeout = 30 * time.Second
+
+var emailBodyTemplate = template.Must(template.New("notification_email").Parse(`Hello,
+
+{{.Message}}
+
+You are receiving this email because {{.Type}} email notifications are enabled
+for your account. You can turn them off in your notification preferences.
+`))
+
+// sendEmail emails notification to its recipient in the background when they
+// opted in. Failures are logged and never affect the in-app notification.
+func (s *NotificationService) sendEmail(notification *entities.Notification) {
+	if s.emailSender == nil {
+		return
+	}
+
+	s.emails.Add(1)
+	go func() {
+		defer s.emails.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+		defer cancel()
+
+		if !s.isEnabled(ctx, notification.UserID, notification.Type, entities.ChannelEmail) {
+			return
+		}
+		address, err := s.preferenceRepo.GetEmailAddress(ctx, notification.UserID)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to get email address for notif %s: %v", notification.ID, err))
+			return
+		}
+		if address == "" {
+			s.logger.Warn(fmt.Sprintf("no email address for user %s, skipping email for notif %s", notification.UserID, notification.ID))
+			return
+		}
+
+		message, err := renderEmail(address, notification)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to render email for notif %s: %v", notification.ID, err))
+			return
+		}
+		if err := s.emailSender.Send(ctx, message); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to send email for notif %s: %v", notification.ID, err))
+			return
+		}
+		s.logger.Info(fmt.Sprintf("sent email for notif %s", notification.ID))
+	}()
+}
+
+// WaitForEmails blocks until every background email has finished, so a
+// shutdown does not cut deliveries off midway.
+func (s *NotificationService) WaitForEmails() {
+	s.emails.Wait()
+}
+
+func renderEmail(address string, notification *entities.Notification) (*entities.EmailMessage, error) {
+	var body bytes.Buffer
+	if err := emailBodyTemplate.Execute(&body, notification); err != nil {
+		return nil, err
+	}
+	return &entities.EmailMessage{
+		To:      address,
+		Subject: notification.Title,
+		Body:    body.String(),
+	}, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"notification-service/internal/domain/entities"
+)
+
+// FanoutConfig bounds the follower notifications created for one post.
+type FanoutConfig struct {
+	PageSize      int // followers fetched and inserted per batch
+	MaxRecipients int // followers notified per post; the rest are skipped
+}
+
+// notifyFollowers creates a post_created notification for each of the
+// author's followers who has not opted out, one batch insert per page of
+// followers. Each follower is deduplicated on their own, so a redelivery after
+// a partial fan-out only notifies those still missing, however the pages fall.
+func (s *NotificationService) notifyFollowers(ctx context.Context, messageID string, event *entities.PostCreatedEvent) error {
+	if s.followers == nil || s.fanout.PageSize <= 0 || s.fanout.MaxRecipients <= 0 {
+		return nil
+	}
+
+	cursor := ""
+	notified := 0
+	for {
+		limit := min(s.fanout.PageSize, s.fanout.MaxRecipients-notified)
+		followerIDs, nextCursor, err := s.followers.GetFollowerIDs(ctx, event.UserID, limit, cursor)
+		if err != nil {
+			return fmt.Errorf("failed to get followers of %s: %w", event.UserID, err)
+		}
+		notified += len(followerIDs)
+
+		recipients, err := s.preferenceRepo.FilterEnabled(ctx, followerIDs, entities.NotificationTypePostCreated, entities.ChannelInApp)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("failed to filter follower notif preferences, notifying all: %v", err))
+			recipients = followerIDs
+		}
+		if len(recipients) > 0 {
+			notifications := make([]*entities.Notification, 0, len(recipients))
+			for _, followerID := range recipients {
+				notifications = append(notifications, event.ToFollowerNotification(followerID))
+			}
+			if err := s.storeFollowerNotifications(ctx, messageID, notifications); err != nil {
+				return err
+			}
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+		if notified >= s.fanout.MaxRecipients {
+			s.logger.Warn(fmt.Sprintf("Follower fan-out for post %s capped at %d recipients", event.PostID, s.fanout.MaxRecipients))
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// storeFollowerNotifications stores one page of follower notifications,
+// skipping followers a previous delivery of messageID already notified, and
+// delivers the rest.
+func (s *NotificationService) storeFollowerNotifications(ctx context.Context, messageID string, notifications []*entities.Notification) error {
+	if err := prepareEventNotifications(messageID, notifications); err != nil {
+		return err
+	}
+
+	stored, err := s.notificationRepo.CreateBatchForRecipients(ctx, messageID, notifications)
+	if err != nil {
+		return fmt.Errorf("failed to create follower notifications: %w", err)
+	}
+	if skipped := len(notifications) - len(stored); skipped > 0 {
+		s.logger.Info(fmt.Sprintf("Skipping %d follower(s) already notified of event %s", skipped, messageID))
+	}
+
+	s.deliver(ctx, stored)
+	return nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
@@ -10,16 +11,36 @@ import (
 	"notification-service/internal/domain/entities"
 	"notification-service/internal/domain/repositories"
 	"notification-service/pkg/logger"
+	"strings"
+	"sync"
+	"time"
 )
 
 type NotificationService struct {
 	notificationRepo repositories.NotificationRepository
+	preferenceRepo   repositories.PreferenceRepository
+	unreadCache      repositories.UnreadCountCache
+	hub              repositories.NotificationHub
+	emailSender      repositories.EmailSender
+	emails           sync.WaitGroup
+	followers        repositories.FollowerDirectory
+	fanout           FanoutConfig
+	digest           DigestConfig
 	logger           *logger.Logger
 }
 
-func NewNotificationService(notificationRepo repositories.NotificationRepository, logger *logger.Logger) *NotificationService {
+// NewNotificationService wires the service. A nil emailSender disables the
+// email channel and a nil followers directory disables follower fan-out.
+func NewNotificationService(notificationRepo repositories.NotificationRepository, preferenceRepo repositories.PreferenceRepository, unreadCache repositories.UnreadCountCache, hub repositories.NotificationHub, emailSender repositories.EmailSender, followers repositories.FollowerDirectory, fanout FanoutConfig, digest DigestConfig, logger *logger.Logger) *NotificationService {
 	return &NotificationService{
 		notificationRepo: notificationRepo,
+		preferenceRepo:   preferenceRepo,
+		unreadCache:      unreadCache,
+		hub:              hub,
+		emailSender:      emailSender,
+		followers:        followers,
+		fanout:           fanout,
+		digest:           digest,
 		logger:           logger,
 	}
 }
@@ -28,13 +49,14 @@ func (s *NotificationService) CreateNotification(ctx context.Context, req *dto.C
 	s.logger.Info(fmt.Sprintf("creating notif for user: %s", req.UserID))
 
 	notification := &entities.Notification{
-		ID:      uuid.New().String(),
-		UserID:  req.UserID,
-		Type:    entities.NotificationType(req.Type),
-		Title:   req.Title,
-		Message: req.Message,
-		Data:    req.Data,
-		Read:    false,
+		ID:       uuid.New().String(),
+		UserID:   req.UserID,
+		Type:     entities.NotificationType(req.Type),
+		Title:    req.Title,
+		Message:  req.Message,
+		Data:     req.Data,
+		Priority: entities.NotificationPriority(req.Priority),
+		Read:     false,
 	}
 
 	notification.Sanitize()
@@ -47,20 +69,34 @@ func (s *NotificationService) CreateNotification(ctx context.Context, req *dto.C
 		s.logger.Error(fmt.Sprintf("failed to create notif: %v", err))
 		return nil, errors.ErrNotificationCreationFailed
 	}
+	s.deliver(ctx, []*entities.Notification{notification})
 
 	s.logger.Info(fmt.Sprintf("notif created successfully: %s", notification.ID))
 
+	return ToNotificationResponse(notification), nil
+}
+
+// Subscribe streams userID's newly created notifications until the returned
+// function is called.
+func (s *NotificationService) Subscribe(userID string) (<-chan *entities.Notification, func()) {
+	return s.hub.Subscribe(userID)
+}
+
+// ToNotificationResponse maps a notification to its API representation.
+func ToNotificationResponse(notification *entities.Notification) *dto.NotificationResponse {
 	return &dto.NotificationResponse{
-		ID:        notification.ID,
-		UserID:    notification.UserID,
-		Type:      string(notification.Type),
-		Title:     notification.Title,
-		Message:   notification.Message,
-		Data:      notification.Data,
-		Read:      notification.Read,
-		CreatedAt: notification.CreatedAt,
-		ReadAt:    notification.ReadAt,
-	}, nil
+		ID:         notification.ID,
+		UserID:     notification.UserID,
+		Type:       string(notification.Type),
+		Title:      notification.Title,
+		Message:    notification.Message,
+		Data:       notification.Data,
+		Priority:   string(notification.Priority),
+		Read:       notification.Read,
+		CreatedAt:  notification.CreatedAt,
+		ReadAt:     notification.ReadAt,
+		ArchivedAt: notification.ArchivedAt,
+	}
 }
 
 func (s *NotificationService) GetNotification(ctx context.Context, id string, userID string) (*dto.NotificationResponse, error) {
@@ -72,59 +108,61 @@ func (s *NotificationService) GetNotification(ctx context.Context, id string, us
 		return nil, errors.ErrNotificationNotFound
 	}
 
+	// Another user's notification is reported as missing rather than forbidden
+	// so callers cannot probe which notification IDs exist.
 	if notification.UserID != userID {
-		return nil, errors.ErrUnauthorizedAccess
+		s.logger.Warn(fmt.Sprintf("user %s requested notif %s owned by another user", userID, id))
+		return nil, errors.ErrNotificationNotFound
 	}
 
 	return &dto.NotificationResponse{
-		ID:        notification.ID,
-		UserID:    notification.UserID,
-		Type:      string(notification.Type),
-		Title:     notification.Title,
-		Message:   notification.Message,
-		Data:      notification.Data,
-		Read:      notification.Read,
-		CreatedAt: notification.CreatedAt,
-		ReadAt:    notification.ReadAt,
+		ID:         notification.ID,
+		UserID:     notification.UserID,
+		Type:       string(notification.Type),
+		Title:      notification.Title,
+		Message:    notification.Message,
+		Data:       notification.Data,
+		Priority:   string(notification.Priority),
+		Read:       notification.Read,
+		CreatedAt:  notification.CreatedAt,
+		ReadAt:     notification.ReadAt,
+		ArchivedAt: notification.ArchivedAt,
 	}, nil
 }
 
 func (s *NotificationService) ListNotifications(ctx context.Context, userID string, req *dto.ListNotificationsRequest) (*dto.ListNotificationsResponse, error) {
-	s.logger.Info(fmt.Sprintf("listing notif for user: %s, limit=%d, offset=%d, unread=%t",
-		userID, req.Limit, req.Offset, req.Unread))
-
-	var notifications []*entities.Notification
-	var err error
-
-	if req.Unread {
-		notifications, err = s.notificationRepo.GetUnreadByUserID(ctx, userID, req.Limit, req.Offset)
-	} else {
-		notifications, err = s.notificationRepo.GetByUserID(ctx, userID, req.Limit, req.Offset)
-	}
+	s.logger.Info(fmt.Sprintf("listing notif for user: %s, limit=%d, offset=%d, unread=%t, type=%s, priority=%s, include_archived=%t",
+		userID, req.Limit, req.Offset, req.Unread, req.Type, req.Priority, req.IncludeArchived))
 
+	notificationType := entities.NotificationType(req.Type)
+	priority := entities.NotificationPriority(req.Priority)
+	notifications, total, err := s.notificationRepo.ListByUserID(ctx, userID, req.Unread, req.IncludeArchived, notificationType, priority, req.Limit, req.Offset)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("failed to list notif: %v", err))
 		return nil, errors.ErrNotificationListFailed
 	}
 
-	unreadCount, err := s.notificationRepo.GetUnreadCount(ctx, userID)
+	// Always the overall unread count, independent of the unread/type filters.
+	// It has its own predicate, so it stays a separate (cached) query.
+	unreadCount, err := s.GetUnreadCount(ctx, userID)
 	if err != nil {
-		s.logger.Error(fmt.Sprintf("failed to get unread count: %v", err))
 		unreadCount = 0 // Continue with 0 instead of failing
 	}
 
 	var notificationResponses []*dto.NotificationResponse
 	for _, notification := range notifications {
 		notificationResponses = append(notificationResponses, &dto.NotificationResponse{
-			ID:        notification.ID,
-			UserID:    notification.UserID,
-			Type:      string(notification.Type),
-			Title:     notification.Title,
-			Message:   notification.Message,
-			Data:      notification.Data,
-			Read:      notification.Read,
-			CreatedAt: notification.CreatedAt,
-			ReadAt:    notification.ReadAt,
+			ID:         notification.ID,
+			UserID:     notification.UserID,
+			Type:       string(notification.Type),
+			Title:      notification.Title,
+			Message:    notification.Message,
+			Data:       notification.Data,
+			Priority:   string(notification.Priority),
+			Read:       notification.Read,
+			CreatedAt:  notification.CreatedAt,
+			ReadAt:     notification.ReadAt,
+			ArchivedAt: notification.ArchivedAt,
 		})
 	}
 
@@ -132,13 +170,15 @@ func (s *NotificationService) ListNotifications(ctx context.Context, userID stri
 		Notifications: notificationResponses,
 		Limit:         req.Limit,
 		Offset:        req.Offset,
-		Total:         len(notificationResponses),
-		UnreadCount:   int64(unreadCount),
+		Total:         total,
+		HasMore:       int64(req.Offset+len(notificationResponses)) < total,
+		UnreadCount:   unreadCount,
 	}, nil
 }
 
 func (s *NotificationService) MarkAsRead(ctx context.Context, userID string, req *dto.MarkAsReadRequest) error {
 	s.logger.Info(fmt.Sprintf("Marking notifications as read for user: %s", userID))
+	defer s.unreadCache.Invalidate(ctx, userID)
 
 	if req.MarkAll {
 		if err := s.notificationRepo.MakeAllAsRead(ctx, userID); err != nil {
@@ -149,15 +189,27 @@ func (s *NotificationService) MarkAsRead(ctx context.Context, userID string, req
 		return nil
 	}
 
-	// Mark specific notifications as read
-	for _, notificationID := range req.NotificationIDs {
-		if err := s.notificationRepo.MarkAsRead(ctx, notificationID, userID); err != nil {
-			s.logger.Error(fmt.Sprintf("Failed to mark notification %s as read: %v", notificationID, err))
-			// Continue with other notifications instead of failing completely
-		}
+	updated, err := s.notificationRepo.MarkManyAsRead(ctx, userID, req.NotificationIDs)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to mark notifications as read: %v", err))
+		return errors.ErrNotificationUpdateFailed
 	}
 
-	s.logger.Info(fmt.Sprintf("Notifications marked as read for user: %s", userID))
+	s.logger.Info(fmt.Sprintf("%d of %d notifications marked as read for user: %s", updated, len(req.NotificationIDs), userID))
+	return nil
+}
+
+func (s *NotificationService) MarkAsUnread(ctx context.Context, userID string, req *dto.MarkAsUnreadRequest) error {
+	s.logger.Info(fmt.Sprintf("Marking notifications as unread for user: %s", userID))
+	defer s.unreadCache.Invalidate(ctx, userID)
+
+	updated, err := s.notificationRepo.MarkAsUnread(ctx, req.NotificationIDs, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to mark notifications as unread: %v", err))
+		return errors.ErrNotificationUpdateFailed
+	}
+
+	s.logger.Info(fmt.Sprintf("%d of %d notifications marked as unread for user: %s", updated, len(req.NotificationIDs), userID))
 	return nil
 }
 
@@ -168,51 +220,131 @@ func (s *NotificationService) DeleteNotification(ctx context.Context, id string,
 		s.logger.Error(fmt.Sprintf("Failed to delete notification: %v", err))
 		return errors.ErrNotificationDeletionFailed
 	}
+	s.unreadCache.Invalidate(ctx, userID)
 
 	s.logger.Info(fmt.Sprintf("Notification deleted successfully: %s", id))
 	return nil
 }
 
+// ArchiveNotification moves the user's notification out of the inbox without
+// deleting it. Archiving an archived notification succeeds.
+func (s *NotificationService) ArchiveNotification(ctx context.Context, id string, userID string) error {
+	return s.setArchived(ctx, id, userID, true)
+}
+
+func (s *NotificationService) UnarchiveNotification(ctx context.Context, id string, userID string) error {
+	return s.setArchived(ctx, id, userID, false)
+}
+
+func (s *NotificationService) setArchived(ctx context.Context, id string, userID string, archived bool) error {
+	update, action := s.notificationRepo.UnarchiveNotification, "unarchive"
+	if archived {
+		update, action = s.notificationRepo.ArchiveNotification, "archive"
+	}
+
+	found, err := update(ctx, id, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to %s notification: %v", action, err))
+		return errors.ErrNotificationUpdateFailed
+	}
+	if !found {
+		return errors.ErrNotificationNotFound
+	}
+	// Archived notifications do not count as unread.
+	s.unreadCache.Invalidate(ctx, userID)
+	return nil
+}
+
 func (s *NotificationService) GetUnreadCount(ctx context.Context, userID string) (int64, error) {
+	if count, ok := s.unreadCache.Get(ctx, userID); ok {
+		return count, nil
+	}
+
 	count, err := s.notificationRepo.GetUnreadCount(ctx, userID)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to get unread count: %v", err))
 		return 0, errors.ErrNotificationListFailed
 	}
+	s.unreadCache.Set(ctx, userID, count)
 
 	return count, nil
 }
 
-func (s *NotificationService) ProcessPostCreatedEvent(ctx context.Context, eventData []byte) error {
-	var event entities.PostCreatedEvent
-	if err := json.Unmarshal(eventData, &event); err != nil {
-		return fmt.Errorf("failed to unmarshal post created event: %w", err)
+// CountByType counts the user's notifications per type, unread ones only when
+// unreadOnly.
+func (s *NotificationService) CountByType(ctx context.Context, userID string, unreadOnly bool) (*dto.NotificationCountsResponse, error) {
+	counts, err := s.notificationRepo.CountByType(ctx, userID, unreadOnly)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to count notifications by type: %v", err))
+		return nil, errors.ErrNotificationListFailed
 	}
 
-	s.logger.Info(fmt.Sprintf("Processing post created event: %s by user %s", event.PostID, event.UserID))
+	response := &dto.NotificationCountsResponse{
+		Counts:     make(map[string]int64, len(entities.NotificationTypes)),
+		UnreadOnly: unreadOnly,
+	}
+	for _, notificationType := range entities.NotificationTypes {
+		response.Counts[string(notificationType)] = 0
+	}
+	for notificationType, count := range counts {
+		response.Counts[string(notificationType)] = count
+		response.Total += count
+	}
+	return response, nil
+}
 
-	// Create notification for post author (optional - they might not want to be notified about their own posts)
-	// In a real system, you might want to notify followers instead
+// Routing keys of the post events the consumer handles.
+const (
+	RoutingKeyPostCreated = "post.created"
+	RoutingKeyPostUpdated = "post.updated"
+	RoutingKeyPostDeleted = "post.deleted"
+)
 
-	// For demo purposes, we'll create a notification for the author
-	notification := event.ToNotification(event.UserID)
-	notification.ID = uuid.New().String()
+// ProcessEvent dispatches a delivery to the handler for its routing key.
+// Unknown keys are logged and acknowledged rather than retried.
+func (s *NotificationService) ProcessEvent(ctx context.Context, routingKey, messageID string, eventData []byte) error {
+	switch routingKey {
+	case RoutingKeyPostCreated:
+		return s.ProcessPostCreatedEvent(ctx, messageID, eventData)
+	case RoutingKeyPostUpdated:
+		return s.ProcessPostUpdatedEvent(ctx, messageID, eventData)
+	case RoutingKeyPostDeleted:
+		return s.ProcessPostDeletedEvent(ctx, messageID, eventData)
+	default:
+		s.logger.Warn("received unsupported routing key: " + routingKey)
+		return nil
+	}
+}
 
-	// Validate and save
-	notification.Sanitize()
-	if err := notification.IsValid(); err != nil {
-		return fmt.Errorf("invalid notification from event: %w", err)
+func (s *NotificationService) ProcessPostCreatedEvent(ctx context.Context, messageID string, eventData []byte) error {
+	if processed, err := s.isEventProcessed(ctx, messageID); err != nil || processed {
+		return err
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
-		return fmt.Errorf("failed to create notification from event: %w", err)
+	var event entities.PostCreatedEvent
+	if err := json.Unmarshal(eventData, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal post created event: %w", err)
 	}
 
-	s.logger.Info(fmt.Sprintf("Created notification %s for post created event", notification.ID))
-	return nil
+	s.logger.Info(fmt.Sprintf("Processing post created event: %s by user %s", event.PostID, event.UserID))
+
+	if event.Published {
+		if err := s.notifyFollowers(ctx, messageID, &event); err != nil {
+			return err
+		}
+	}
+
+	// The author's own confirmation is opt-in, so this is usually skipped. It
+	// runs last: its record of messageID, made whether or not the author gets
+	// a notification, marks the whole event as processed.
+	return s.createEventNotification(ctx, messageID, event.ToNotification(event.UserID), "post created")
 }
 
-func (s *NotificationService) ProcessPostUpdatedEvent(ctx context.Context, eventData []byte) error {
+func (s *NotificationService) ProcessPostUpdatedEvent(ctx context.Context, messageID string, eventData []byte) error {
+	if processed, err := s.isEventProcessed(ctx, messageID); err != nil || processed {
+		return err
+	}
+
 	var event entities.PostUpdatedEvent
 	if err := json.Unmarshal(eventData, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal post updated event: %w", err)
@@ -221,24 +353,14 @@ func (s *NotificationService) ProcessPostUpdatedEvent(ctx context.Context, event
 	s.logger.Info(fmt.Sprintf("Processing post updated event: %s by user %s", event.PostID, event.UserID))
 
 	// Create notification for post author
-	notification := event.ToNotification(event.UserID)
-	notification.ID = uuid.New().String()
-
-	// Validate and save
-	notification.Sanitize()
-	if err := notification.IsValid(); err != nil {
-		return fmt.Errorf("invalid notification from event: %w", err)
-	}
+	return s.createEventNotification(ctx, messageID, event.ToNotification(event.UserID), "post updated")
+}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
-		return fmt.Errorf("failed to create notification from event: %w", err)
+func (s *NotificationService) ProcessPostDeletedEvent(ctx context.Context, messageID string, eventData []byte) error {
+	if processed, err := s.isEventProcessed(ctx, messageID); err != nil || processed {
+		return err
 	}
 
-	s.logger.Info(fmt.Sprintf("Created notification %s for post updated event", notification.ID))
-	return nil
-}
-
-func (s *NotificationService) ProcessPostDeletedEvent(ctx context.Context, eventData []byte) error {
 	var event entities.PostDeletedEvent
 	if err := json.Unmarshal(eventData, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal post deleted event: %w", err)
@@ -247,30 +369,314 @@ func (s *NotificationService) ProcessPostDeletedEvent(ctx context.Context, event
 	s.logger.Info(fmt.Sprintf("Processing post deleted event: %s by user %s", event.PostID, event.UserID))
 
 	// Create notification for post author
-	notification := event.ToNotification(event.UserID)
-	notification.ID = uuid.New().String()
+	return s.createEventNotification(ctx, messageID, event.ToNotification(event.UserID), "post deleted")
+}
 
-	// Validate and save
-	notification.Sanitize()
-	if err := notification.IsValid(); err != nil {
-		return fmt.Errorf("invalid notification from event: %w", err)
+// createEventNotification stores a notification derived from a broker event,
+// unless the recipient has opted out of its type. Either way messageID is
+// recorded as processed.
+func (s *NotificationService) createEventNotification(ctx context.Context, messageID string, notification *entities.Notification, eventName string) error {
+	if !s.isEnabled(ctx, notification.UserID, notification.Type, entities.ChannelInApp) {
+		s.logger.Info(fmt.Sprintf("Skipping %s notification: user %s disabled %s", eventName, notification.UserID, notification.Type))
+		if messageID == "" {
+			return nil
+		}
+		if _, err := s.notificationRepo.CreateBatchForEvent(ctx, messageID, nil); err != nil {
+			return fmt.Errorf("failed to record processed event %s: %w", messageID, err)
+		}
+		return nil
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	return s.storeEventNotifications(ctx, messageID, messageID, []*entities.Notification{notification}, eventName)
+}
+
+// storeEventNotifications validates and stores notifications derived from the
+// broker message messageID, then delivers them. dedupeKey is recorded with
+// the insert so a redelivery of the same batch is skipped.
+func (s *NotificationService) storeEventNotifications(ctx context.Context, messageID, dedupeKey string, notifications []*entities.Notification, eventName string) error {
+	if err := prepareEventNotifications(messageID, notifications); err != nil {
+		return err
+	}
+
+	created, err := s.notificationRepo.CreateBatchForEvent(ctx, dedupeKey, notifications)
+	if err != nil {
 		return fmt.Errorf("failed to create notification from event: %w", err)
 	}
+	if !created {
+		s.logger.Info(fmt.Sprintf("Skipping duplicate %s event %s", eventName, dedupeKey))
+		return nil
+	}
+
+	s.deliver(ctx, notifications)
 
-	s.logger.Info(fmt.Sprintf("Created notification %s for post deleted event", notification.ID))
+	s.logger.Info(fmt.Sprintf("Created %d notification(s) for %s event", len(notifications), eventName))
 	return nil
 }
 
-func (s *NotificationService) CleanupOldNotifications(ctx context.Context, olderThanDays int) error {
+// prepareEventNotifications assigns IDs to notifications derived from the
+// broker message messageID and validates them.
+func prepareEventNotifications(messageID string, notifications []*entities.Notification) error {
+	for _, notification := range notifications {
+		notification.ID = uuid.New().String()
+		notification.SetSourceMessageID(messageID)
+
+		notification.Sanitize()
+		if err := notification.IsValid(); err != nil {
+			return fmt.Errorf("invalid notification from event: %w", err)
+		}
+	}
+	return nil
+}
+
+// deliver pushes and emails newly stored notifications. Recipients in a
+// digest mode get all but high-priority ones in the feed only; RunDigests
+// pushes and emails a summary later.
+func (s *NotificationService) deliver(ctx context.Context, notifications []*entities.Notification) {
+	digestUsers := s.filterDigestUsers(ctx, notifications)
+	for _, notification := range notifications {
+		s.unreadCache.Invalidate(ctx, notification.UserID)
+		if digestUsers[notification.UserID] && notification.Priority != entities.PriorityHigh {
+			continue
+		}
+		s.hub.Publish(notification)
+		s.sendEmail(notification)
+	}
+}
+
+// isEventProcessed reports whether messageID already produced a notification.
+// Messages without an ID cannot be deduplicated and are always processed.
+func (s *NotificationService) isEventProcessed(ctx context.Context, messageID string) (bool, error) {
+	if messageID == "" {
+		return false, nil
+	}
+	processed, err := s.notificationRepo.IsEventProcessed(ctx, messageID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check processed event %s: %w", messageID, err)
+	}
+	if processed {
+		s.logger.Info(fmt.Sprintf("Skipping already processed event %s", messageID))
+	}
+	return processed, nil
+}
+
+// isEnabled reports whether userID receives notificationType on channel. A
+// failed lookup falls back to the channel default rather than dropping an
+// in-app notification or emailing a user who never opted in.
+func (s *NotificationService) isEnabled(ctx context.Context, userID string, notificationType entities.NotificationType, channel entities.NotificationChannel) bool {
+	enabled, err := s.preferenceRepo.IsEnabled(ctx, userID, notificationType, channel)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to read %s notif preference for user %s, using default: %v", channel, userID, err))
+		return entities.DefaultEnabled(notificationType, channel)
+	}
+	return enabled
+}
+
+// GetPreferences returns userID's setting for every notification type and
+// channel, filling in the channel default where nothing is stored.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) (*dto.NotificationPreferencesResponse, error) {
+	stored, err := s.preferenceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to get notif preferences: %v", err))
+		return nil, errors.ErrPreferencesFailed
+	}
+
+	type key struct {
+		notificationType entities.NotificationType
+		channel          entities.NotificationChannel
+	}
+	enabled := make(map[key]bool, len(stored))
+	for _, preference := range stored {
+		enabled[key{preference.Type, preference.Channel}] = preference.Enabled
+	}
+
+	preferences := make([]*dto.NotificationPreference, 0, len(entities.NotificationTypes)*len(entities.NotificationChannels))
+	for _, notificationType := range entities.NotificationTypes {
+		for _, channel := range entities.NotificationChannels {
+			value, ok := enabled[key{notificationType, channel}]
+			preferences = append(preferences, &dto.NotificationPreference{
+				Type:    string(notificationType),
+				Channel: string(channel),
+				Enabled: value || (!ok && entities.DefaultEnabled(notificationType, channel)),
+			})
+		}
+	}
+
+	digestMode, err := s.preferenceRepo.GetDigestMode(ctx, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to get notif digest mode: %v", err))
+		return nil, errors.ErrPreferencesFailed
+	}
+
+	return &dto.NotificationPreferencesResponse{Preferences: preferences, DigestMode: string(digestMode)}, nil
+}
+
+// UpdatePreferences stores the given settings and returns the full set. An
+// omitted channel means in-app. email is the address from the caller's access
+// token; it is remembered as the destination when an email preference is
+// enabled. A digest_mode, when given, is stored as well.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID, email string, req *dto.UpdatePreferencesRequest) (*dto.NotificationPreferencesResponse, error) {
+	digestMode := entities.DigestMode(req.DigestMode)
+	if (len(req.Preferences) == 0 && digestMode == "") || (digestMode != "" && !digestMode.IsValid()) {
+		return nil, errors.ErrInvalidPreferences
+	}
+
+	enablesEmail := false
+	preferences := make([]*entities.NotificationPreference, 0, len(req.Preferences))
+	for _, item := range req.Preferences {
+		channel := entities.NotificationChannel(item.Channel)
+		if channel == "" {
+			channel = entities.ChannelInApp
+		}
+		notificationType := entities.NotificationType(item.Type)
+		if !notificationType.IsValid() || !channel.IsValid() {
+			return nil, errors.ErrInvalidPreferences
+		}
+		if channel == entities.ChannelEmail && item.Enabled {
+			enablesEmail = true
+		}
+		preferences = append(preferences, &entities.NotificationPreference{
+			UserID:  userID,
+			Type:    notificationType,
+			Channel: channel,
+			Enabled: item.Enabled,
+		})
+	}
+
+	if enablesEmail {
+		if email == "" {
+			s.logger.Warn(fmt.Sprintf("user %s enabled email notifs without an email claim", userID))
+		} else if err := s.preferenceRepo.SetEmailAddress(ctx, userID, email); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to store notif email address: %v", err))
+			return nil, errors.ErrPreferencesFailed
+		}
+	}
+
+	if len(preferences) > 0 {
+		if err := s.preferenceRepo.Upsert(ctx, preferences); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to update notif preferences: %v", err))
+			return nil, errors.ErrPreferencesFailed
+		}
+	}
+	if digestMode != "" {
+		if err := s.preferenceRepo.SetDigestMode(ctx, userID, digestMode); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to update notif digest mode: %v", err))
+			return nil, errors.ErrPreferencesFailed
+		}
+	}
+
+	return s.GetPreferences(ctx, userID)
+}
+
+// AdminGetNotification returns any notification regardless of owner, for the
+// internal support view.
+func (s *NotificationService) AdminGetNotification(ctx context.Context, id string) (*dto.AdminNotificationResponse, error) {
+	notification, err := s.notificationRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("notif not found: %s", id))
+		return nil, errors.ErrNotificationNotFound
+	}
+
+	return toAdminNotificationResponse(notification), nil
+}
+
+// AdminListNotifications lists notifications across all users, newest first,
+// resuming after the cursor returned by the previous page.
+func (s *NotificationService) AdminListNotifications(ctx context.Context, req *dto.AdminListNotificationsRequest) (*dto.AdminListNotificationsResponse, error) {
+	var after *entities.NotificationCursor
+	if req.Cursor != "" {
+		cursor, err := decodeNotificationCursor(req.Cursor)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("invalid admin list cursor: %v", err))
+			return nil, errors.ErrInvalidRequest
+		}
+		after = cursor
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	notifications, err := s.notificationRepo.List(ctx, after, req.Limit+1)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to list all notifs: %v", err))
+		return nil, errors.ErrNotificationListFailed
+	}
+
+	hasMore := len(notifications) > req.Limit
+	if hasMore {
+		notifications = notifications[:req.Limit]
+	}
+
+	responses := make([]*dto.AdminNotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		responses = append(responses, toAdminNotificationResponse(notification))
+	}
+
+	response := &dto.AdminListNotificationsResponse{
+		Notifications: responses,
+		Limit:         req.Limit,
+		HasMore:       hasMore,
+	}
+	if hasMore {
+		last := notifications[len(notifications)-1]
+		response.NextCursor = encodeNotificationCursor(&entities.NotificationCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return response, nil
+}
+
+// Cursors are opaque to clients: base64 of "<created_at RFC3339Nano>|<id>".
+func encodeNotificationCursor(cursor *entities.NotificationCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeNotificationCursor(value string) (*entities.NotificationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", err)
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor timestamp: %w", err)
+	}
+
+	return &entities.NotificationCursor{CreatedAt: ts, ID: id}, nil
+}
+
+func toAdminNotificationResponse(notification *entities.Notification) *dto.AdminNotificationResponse {
+	return &dto.AdminNotificationResponse{
+		NotificationResponse: dto.NotificationResponse{
+			ID:         notification.ID,
+			UserID:     notification.UserID,
+			Type:       string(notification.Type),
+			Title:      notification.Title,
+			Message:    notification.Message,
+			Data:       notification.Data,
+			Priority:   string(notification.Priority),
+			Read:       notification.Read,
+			CreatedAt:  notification.CreatedAt,
+			ReadAt:     notification.ReadAt,
+			ArchivedAt: notification.ArchivedAt,
+		},
+		SourceMessageID: notification.SourceMessageID(),
+	}
+}
+
+// CleanupOldNotifications deletes notifications older than olderThanDays and
+// returns how many were removed.
+func (s *NotificationService) CleanupOldNotifications(ctx context.Context, olderThanDays int) (int64, error) {
 	s.logger.Info(fmt.Sprintf("Cleaning up notifications older than %d days", olderThanDays))
 
-	if err := s.notificationRepo.DeleteOld(ctx, olderThanDays); err != nil {
+	deleted, err := s.notificationRepo.DeleteOld(ctx, olderThanDays)
+	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to cleanup old notifications: %v", err))
-		return errors.ErrNotificationDeletionFailed
+		return 0, errors.ErrNotificationDeletionFailed
 	}
 
-	return nil
+	s.logger.Info(fmt.Sprintf("Deleted %d notifications older than %d days", deleted, olderThanDays))
+	return deleted, nil
 }
@@ -0,0 +1,1462 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"notification-service/internal/application/dto"
+	appErrors "notification-service/internal/application/errors"
+	"notification-service/internal/domain/entities"
+	"notification-service/internal/domain/repositories"
+	"notification-service/internal/infrastructure/cache"
+	"notification-service/internal/infrastructure/email"
+	"notification-service/internal/infrastructure/stream"
+	"notification-service/pkg/logger"
+)
+
+type mockNotificationRepo struct {
+	unreadCount      int64
+	unreadCountCalls int
+	created          []*entities.Notification
+	getByID          func(id string) (*entities.Notification, error)
+	all              []*entities.Notification
+	markManyCalls    int
+	processed        map[string]bool
+	digested         map[string]bool
+	digestConflict   bool // CompleteDigest reports the window as already sent
+}
+
+func (m *mockNotificationRepo) Create(ctx context.Context, notification *entities.Notification) error {
+	m.created = append(m.created, notification)
+	m.unreadCount++
+	return nil
+}
+func (m *mockNotificationRepo) CreateBatchForEvent(ctx context.Context, messageID string, notifications []*entities.Notification) (bool, error) {
+	if messageID != "" {
+		if m.processed[messageID] {
+			return false, nil
+		}
+		if m.processed == nil {
+			m.processed = make(map[string]bool)
+		}
+		m.processed[messageID] = true
+	}
+	for _, notification := range notifications {
+		m.Create(ctx, notification)
+	}
+	return true, nil
+}
+func (m *mockNotificationRepo) CreateBatchForRecipients(ctx context.Context, messageID string, notifications []*entities.Notification) ([]*entities.Notification, error) {
+	var stored []*entities.Notification
+	for _, notification := range notifications {
+		key := messageID + "#" + notification.UserID
+		if messageID != "" && m.processed[key] {
+			continue
+		}
+		if messageID != "" {
+			if m.processed == nil {
+				m.processed = make(map[string]bool)
+			}
+			m.processed[key] = true
+		}
+		m.Create(ctx, notification)
+		stored = append(stored, notification)
+	}
+	return stored, nil
+}
+func (m *mockNotificationRepo) IsEventProcessed(ctx context.Context, messageID string) (bool, error) {
+	return m.processed[messageID], nil
+}
+func (m *mockNotificationRepo) GetByID(ctx context.Context, id string) (*entities.Notification, error) {
+	if m.getByID != nil {
+		return m.getByID(id)
+	}
+	return nil, errors.New("not found")
+}
+
+// matching returns the user's notifications that pass the unread, archived,
+// type and priority filters, in insertion order.
+func (m *mockNotificationRepo) matching(userID string, unreadOnly, includeArchived bool, notificationType entities.NotificationType, priority entities.NotificationPriority) []*entities.Notification {
+	var matched []*entities.Notification
+	for _, n := range m.all {
+		if n.UserID == userID && (!unreadOnly || !n.Read) && (includeArchived || n.ArchivedAt == nil) &&
+			(notificationType == "" || n.Type == notificationType) && (priority == "" || n.Priority == priority) {
+			matched = append(matched, n)
+		}
+	}
+	return matched
+}
+func page(notifications []*entities.Notification, limit, offset int) []*entities.Notification {
+	if offset >= len(notifications) {
+		return nil
+	}
+	return notifications[offset:min(offset+limit, len(notifications))]
+}
+func (m *mockNotificationRepo) GetByUserID(ctx context.Context, userID string, notificationType entities.NotificationType, priority entities.NotificationPriority, limit, offset int) ([]*entities.Notification, error) {
+	return page(m.matching(userID, false, false, notificationType, priority), limit, offset), nil
+}
+func (m *mockNotificationRepo) GetUnreadByUserID(ctx context.Context, userID string, notificationType entities.NotificationType, priority entities.NotificationPriority, limit, offset int) ([]*entities.Notification, error) {
+	return page(m.matching(userID, true, false, notificationType, priority), limit, offset), nil
+}
+func (m *mockNotificationRepo) CountByUserID(ctx context.Context, userID string, unreadOnly, includeArchived bool, typeFilter entities.NotificationType, priorityFilter entities.NotificationPriority) (int64, error) {
+	return int64(len(m.matching(userID, unreadOnly, includeArchived, typeFilter, priorityFilter))), nil
+}
+func (m *mockNotificationRepo) CountByType(ctx context.Context, userID string, unreadOnly bool) (map[entities.NotificationType]int64, error) {
+	counts := make(map[entities.NotificationType]int64)
+	for _, n := range m.matching(userID, unreadOnly, false, "", "") {
+		counts[n.Type]++
+	}
+	return counts, nil
+}
+func (m *mockNotificationRepo) ListByUserID(ctx context.Context, userID string, unreadOnly, includeArchived bool, typeFilter entities.NotificationType, priorityFilter entities.NotificationPriority, limit, offset int) ([]*entities.Notification, int64, error) {
+	matched := m.matching(userID, unreadOnly, includeArchived, typeFilter, priorityFilter)
+	return page(matched, limit, offset), int64(len(matched)), nil
+}
+func (m *mockNotificationRepo) MarkAsRead(ctx context.Context, id string, userID string) error {
+	if m.unreadCount > 0 {
+		m.unreadCount--
+	}
+	return nil
+}
+func (m *mockNotificationRepo) MarkManyAsRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	m.markManyCalls++
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var updated int64
+	for _, n := range m.all {
+		if wanted[n.ID] && n.UserID == userID && !n.Read {
+			n.MarkAsRead()
+			updated++
+		}
+	}
+	m.unreadCount -= updated
+	return updated, nil
+}
+func (m *mockNotificationRepo) MarkAsUnread(ctx context.Context, ids []string, userID string) (int64, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var updated int64
+	for _, n := range m.all {
+		if wanted[n.ID] && n.UserID == userID && n.Read {
+			n.Read = false
+			n.ReadAt = nil
+			updated++
+		}
+	}
+	m.unreadCount += updated
+	return updated, nil
+}
+func (m *mockNotificationRepo) MakeAllAsRead(ctx context.Context, userID string) error {
+	m.unreadCount = 0
+	return nil
+}
+func (m *mockNotificationRepo) ArchiveNotification(ctx context.Context, id string, userID string) (bool, error) {
+	for _, n := range m.all {
+		if n.ID == id && n.UserID == userID {
+			if n.ArchivedAt == nil {
+				now := time.Now()
+				n.ArchivedAt = &now
+				if !n.Read {
+					m.unreadCount--
+				}
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockNotificationRepo) UnarchiveNotification(ctx context.Context, id string, userID string) (bool, error) {
+	for _, n := range m.all {
+		if n.ID == id && n.UserID == userID {
+			if n.ArchivedAt != nil {
+				n.ArchivedAt = nil
+				if !n.Read {
+					m.unreadCount++
+				}
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockNotificationRepo) Delete(ctx context.Context, id string, userID string) error {
+	return nil
+}
+func (m *mockNotificationRepo) GetUnreadCount(ctx context.Context, userID string) (int64, error) {
+	m.unreadCountCalls++
+	return m.unreadCount, nil
+}
+func (m *mockNotificationRepo) List(ctx context.Context, after *entities.NotificationCursor, limit int) ([]*entities.Notification, error) {
+	sorted := append([]*entities.Notification(nil), m.all...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+
+	var page []*entities.Notification
+	for _, n := range sorted {
+		if after != nil {
+			if n.CreatedAt.After(after.CreatedAt) || (n.CreatedAt.Equal(after.CreatedAt) && n.ID >= after.ID) {
+				continue
+			}
+		}
+		page = append(page, n)
+		if len(page) == limit {
+			break
+		}
+	}
+	return page, nil
+}
+func (m *mockNotificationRepo) GetUndigested(ctx context.Context, userID string, since, until time.Time, limit int) ([]*entities.Notification, error) {
+	var undigested []*entities.Notification
+	for _, n := range m.all {
+		if n.UserID == userID && n.Type != entities.NotificationTypeDigest && n.Priority != entities.PriorityHigh && !m.digested[n.ID] &&
+			!n.CreatedAt.Before(since) && !n.CreatedAt.After(until) {
+			undigested = append(undigested, n)
+		}
+	}
+	return page(undigested, limit, 0), nil
+}
+func (m *mockNotificationRepo) CompleteDigest(ctx context.Context, userID string, since, windowEnd time.Time, digest *entities.Notification, sourceIDs []string) (bool, error) {
+	if m.digestConflict {
+		return false, nil
+	}
+	if digest != nil {
+		m.Create(ctx, digest)
+	}
+	if m.digested == nil {
+		m.digested = make(map[string]bool)
+	}
+	for _, id := range sourceIDs {
+		m.digested[id] = true
+	}
+	return true, nil
+}
+func (m *mockNotificationRepo) DeleteOld(ctx context.Context, olderThan int) (int64, error) {
+	return 0, nil
+}
+
+var _ repositories.NotificationRepository = (*mockNotificationRepo)(nil)
+var _ repositories.UnreadCountCache = (*cache.UnreadCountCache)(nil)
+
+// mockPreferenceRepo stores preferences keyed by "user/type/channel".
+type mockPreferenceRepo struct {
+	enabled map[string]bool
+	emails  map[string]string
+	digests map[string]*entities.DigestSubscription
+	err     error
+}
+
+func preferenceKey(userID string, notificationType entities.NotificationType, channel entities.NotificationChannel) string {
+	return userID + "/" + string(notificationType) + "/" + string(channel)
+}
+
+func (m *mockPreferenceRepo) GetByUserID(ctx context.Context, userID string) ([]*entities.NotificationPreference, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var preferences []*entities.NotificationPreference
+	for _, notificationType := range entities.NotificationTypes {
+		for _, channel := range entities.NotificationChannels {
+			if enabled, ok := m.enabled[preferenceKey(userID, notificationType, channel)]; ok {
+				preferences = append(preferences, &entities.NotificationPreference{UserID: userID, Type: notificationType, Channel: channel, Enabled: enabled})
+			}
+		}
+	}
+	return preferences, nil
+}
+func (m *mockPreferenceRepo) Upsert(ctx context.Context, preferences []*entities.NotificationPreference) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.enabled == nil {
+		m.enabled = make(map[string]bool)
+	}
+	for _, preference := range preferences {
+		m.enabled[preferenceKey(preference.UserID, preference.Type, preference.Channel)] = preference.Enabled
+	}
+	return nil
+}
+func (m *mockPreferenceRepo) IsEnabled(ctx context.Context, userID string, notificationType entities.NotificationType, channel entities.NotificationChannel) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if enabled, ok := m.enabled[preferenceKey(userID, notificationType, channel)]; ok {
+		return enabled, nil
+	}
+	return entities.DefaultEnabled(notificationType, channel), nil
+}
+func (m *mockPreferenceRepo) FilterEnabled(ctx context.Context, userIDs []string, notificationType entities.NotificationType, channel entities.NotificationChannel) ([]string, error) {
+	var enabled []string
+	for _, userID := range userIDs {
+		ok, err := m.IsEnabled(ctx, userID, notificationType, channel)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			enabled = append(enabled, userID)
+		}
+	}
+	return enabled, nil
+}
+func (m *mockPreferenceRepo) GetEmailAddress(ctx context.Context, userID string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	return m.emails[userID], nil
+}
+func (m *mockPreferenceRepo) SetEmailAddress(ctx context.Context, userID, email string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.emails == nil {
+		m.emails = make(map[string]string)
+	}
+	m.emails[userID] = email
+	return nil
+}
+
+func (m *mockPreferenceRepo) GetDigestMode(ctx context.Context, userID string) (entities.DigestMode, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	if subscription, ok := m.digests[userID]; ok {
+		return subscription.Mode, nil
+	}
+	return entities.DigestModeImmediate, nil
+}
+func (m *mockPreferenceRepo) SetDigestMode(ctx context.Context, userID string, mode entities.DigestMode) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.digests == nil {
+		m.digests = make(map[string]*entities.DigestSubscription)
+	}
+	m.digests[userID] = &entities.DigestSubscription{UserID: userID, Mode: mode, Since: time.Now()}
+	return nil
+}
+func (m *mockPreferenceRepo) FilterDigest(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	digest := make(map[string]bool)
+	for _, userID := range userIDs {
+		if subscription, ok := m.digests[userID]; ok && subscription.Mode != entities.DigestModeImmediate {
+			digest[userID] = true
+		}
+	}
+	return digest, nil
+}
+func (m *mockPreferenceRepo) ListDueDigests(ctx context.Context, mode entities.DigestMode, dueBefore time.Time, limit int) ([]*entities.DigestSubscription, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var due []*entities.DigestSubscription
+	for _, subscription := range m.digests {
+		if subscription.Mode == mode && !subscription.Since.After(dueBefore) && len(due) < limit {
+			due = append(due, subscription)
+		}
+	}
+	return due, nil
+}
+
+var _ repositories.PreferenceRepository = (*mockPreferenceRepo)(nil)
+
+func newTestNotificationService(repo *mockNotificationRepo) *NotificationService {
+	return newTestNotificationServiceWithPreferences(repo, &mockPreferenceRepo{})
+}
+
+func newTestNotificationServiceWithPreferences(repo *mockNotificationRepo, preferences *mockPreferenceRepo) *NotificationService {
+	return NewNotificationService(repo, preferences, cache.NewUnreadCountCache(time.Minute), stream.NewHub(4), nil, nil, FanoutConfig{}, DigestConfig{}, logger.New("info"))
+}
+
+// mockFollowerDirectory pages through a fixed follower list with an index
+// cursor. With failOnCall set, that call (counting from 1) fails.
+type mockFollowerDirectory struct {
+	followerIDs []string
+	limits      []int
+	failOnCall  int
+}
+
+func (m *mockFollowerDirectory) GetFollowerIDs(ctx context.Context, userID string, limit int, cursor string) ([]string, string, error) {
+	m.limits = append(m.limits, limit)
+	if len(m.limits) == m.failOnCall {
+		return nil, "", errors.New("user service unavailable")
+	}
+	start, _ := strconv.Atoi(cursor)
+	end := start + limit
+	if end >= len(m.followerIDs) {
+		return m.followerIDs[start:], "", nil
+	}
+	return m.followerIDs[start:end], strconv.Itoa(end), nil
+}
+
+var _ repositories.FollowerDirectory = (*mockFollowerDirectory)(nil)
+
+func newTestNotificationServiceWithFollowers(repo *mockNotificationRepo, preferences *mockPreferenceRepo, followers *mockFollowerDirectory, fanout FanoutConfig) *NotificationService {
+	return NewNotificationService(repo, preferences, cache.NewUnreadCountCache(time.Minute), stream.NewHub(4), nil, followers, fanout, DigestConfig{}, logger.New("info"))
+}
+
+func TestGetUnreadCount_CacheHit(t *testing.T) {
+	repo := &mockNotificationRepo{unreadCount: 3}
+	svc := newTestNotificationService(repo)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		count, err := svc.GetUnreadCount(ctx, "user1")
+		if err != nil {
+			t.Fatalf("GetUnreadCount: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("expected count 3, got %d", count)
+		}
+	}
+	if repo.unreadCountCalls != 1 {
+		t.Errorf("expected 1 repository call, got %d", repo.unreadCountCalls)
+	}
+}
+
+func TestGetUnreadCount_InvalidatedOnCreate(t *testing.T) {
+	repo := &mockNotificationRepo{unreadCount: 1}
+	svc := newTestNotificationService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.GetUnreadCount(ctx, "user1"); err != nil {
+		t.Fatalf("GetUnreadCount: %v", err)
+	}
+
+	_, err := svc.CreateNotification(ctx, &dto.CreateNotificationRequest{
+		UserID:  "user1",
+		Type:    string(entities.NotificationTypePostCreated),
+		Title:   "New post",
+		Message: "A new post was published",
+	})
+	if err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+
+	count, err := svc.GetUnreadCount(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetUnreadCount: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected count 2 after create, got %d", count)
+	}
+	if repo.unreadCountCalls != 2 {
+		t.Errorf("expected 2 repository calls, got %d", repo.unreadCountCalls)
+	}
+}
+
+func TestGetUnreadCount_InvalidatedOnMarkAsRead(t *testing.T) {
+	repo := &mockNotificationRepo{unreadCount: 2, all: []*entities.Notification{{ID: "n1", UserID: "user1"}}}
+	svc := newTestNotificationService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.GetUnreadCount(ctx, "user1"); err != nil {
+		t.Fatalf("GetUnreadCount: %v", err)
+	}
+
+	if err := svc.MarkAsRead(ctx, "user1", &dto.MarkAsReadRequest{NotificationIDs: []string{"n1"}}); err != nil {
+		t.Fatalf("MarkAsRead: %v", err)
+	}
+
+	count, err := svc.GetUnreadCount(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetUnreadCount: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected count 1 after mark-read, got %d", count)
+	}
+	if repo.unreadCountCalls != 2 {
+		t.Errorf("expected 2 repository calls, got %d", repo.unreadCountCalls)
+	}
+}
+
+func TestProcessPostCreatedEvent_RecordsSourceMessageID(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	svc := newTestNotificationServiceWithPreferences(repo, &mockPreferenceRepo{enabled: map[string]bool{
+		preferenceKey("user1", entities.NotificationTypeOwnPostCreated, entities.ChannelInApp): true,
+	}})
+
+	body := []byte(`{"post_id":"post1","user_id":"user1","title":"Hello","slug":"hello","published":true}`)
+	if err := svc.ProcessPostCreatedEvent(context.Background(), "post.created-123", body); err != nil {
+		t.Fatalf("ProcessPostCreatedEvent: %v", err)
+	}
+
+	if len(repo.created) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(repo.created))
+	}
+	notification := repo.created[0]
+	if got := notification.Data[entities.DataKeySourceMessageID]; got != "post.created-123" {
+		t.Errorf("expected source message ID in data, got %v", got)
+	}
+	if notification.Data["post_id"] != "post1" {
+		t.Errorf("expected event data to be preserved, got %v", notification.Data)
+	}
+}
+
+func TestAdminGetNotification_ExposesSourceMessageID(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	svc := newTestNotificationService(repo)
+
+	body := []byte(`{"post_id":"post1","user_id":"user1","title":"Hello"}`)
+	if err := svc.ProcessPostDeletedEvent(context.Background(), "post.deleted-456", body); err != nil {
+		t.Fatalf("ProcessPostDeletedEvent: %v", err)
+	}
+	repo.getByID = func(id string) (*entities.Notification, error) { return repo.created[0], nil }
+
+	resp, err := svc.AdminGetNotification(context.Background(), repo.created[0].ID)
+	if err != nil {
+		t.Fatalf("AdminGetNotification: %v", err)
+	}
+	if resp.SourceMessageID != "post.deleted-456" {
+		t.Errorf("expected source message ID post.deleted-456, got %q", resp.SourceMessageID)
+	}
+}
+
+func TestAdminListNotifications_PagesThroughAllWithCursor(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockNotificationRepo{}
+	for i := 0; i < 7; i++ {
+		// Pairs share a timestamp so the id tiebreaker is exercised.
+		repo.all = append(repo.all, &entities.Notification{
+			ID:        fmt.Sprintf("n-%02d", i),
+			UserID:    "user-1",
+			CreatedAt: base.Add(time.Duration(i/2) * time.Minute),
+		})
+	}
+	svc := newTestNotificationService(repo)
+
+	seen := make(map[string]bool)
+	var order []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("pagination did not terminate")
+		}
+		resp, err := svc.AdminListNotifications(context.Background(), &dto.AdminListNotificationsRequest{Limit: 3, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("AdminListNotifications: %v", err)
+		}
+		for _, n := range resp.Notifications {
+			if seen[n.ID] {
+				t.Fatalf("notification %s returned twice", n.ID)
+			}
+			seen[n.ID] = true
+			order = append(order, n.ID)
+		}
+		if !resp.HasMore {
+			if resp.NextCursor != "" {
+				t.Fatalf("expected no cursor on the last page, got %q", resp.NextCursor)
+			}
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	if len(order) != 7 {
+		t.Fatalf("expected 7 notifications across pages, got %d", len(order))
+	}
+	if order[0] != "n-06" || order[6] != "n-00" {
+		t.Fatalf("expected newest-first order, got %v", order)
+	}
+}
+
+func TestAdminListNotifications_RejectsMalformedCursor(t *testing.T) {
+	svc := newTestNotificationService(&mockNotificationRepo{})
+
+	_, err := svc.AdminListNotifications(context.Background(), &dto.AdminListNotificationsRequest{Limit: 10, Cursor: "not-a-cursor"})
+	if err != appErrors.ErrInvalidRequest {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+}
+
+func TestMarkAsRead_MarksAllOwnedIDsInOneQuery(t *testing.T) {
+	repo := &mockNotificationRepo{
+		unreadCount: 3,
+		all: []*entities.Notification{
+			{ID: "n1", UserID: "user1"},
+			{ID: "n2", UserID: "user1"},
+			{ID: "n3", UserID: "user1"},
+			{ID: "foreign", UserID: "user2"},
+		},
+	}
+	svc := newTestNotificationService(repo)
+
+	req := &dto.MarkAsReadRequest{NotificationIDs: []string{"n1", "n2", "foreign"}}
+	if err := svc.MarkAsRead(context.Background(), "user1", req); err != nil {
+		t.Fatalf("MarkAsRead: %v", err)
+	}
+
+	if repo.markManyCalls != 1 {
+		t.Fatalf("expected a single bulk update, got %d", repo.markManyCalls)
+	}
+	for _, n := range repo.all {
+		switch n.ID {
+		case "n1", "n2":
+			if !n.Read {
+				t.Errorf("expected %s to be marked read", n.ID)
+			}
+		case "n3":
+			if n.Read {
+				t.Error("expected unrequested notification to stay unread")
+			}
+		case "foreign":
+			if n.Read {
+				t.Error("expected another user's notification to be ignored")
+			}
+		}
+	}
+	if repo.unreadCount != 1 {
+		t.Errorf("expected 1 unread notification left, got %d", repo.unreadCount)
+	}
+}
+
+func TestProcessEvent_DispatchesByRoutingKey(t *testing.T) {
+	body := []byte(`{"post_id":"post1","user_id":"user1","title":"Hello","slug":"hello","published":true}`)
+	cases := map[string]entities.NotificationType{
+		RoutingKeyPostCreated: entities.NotificationTypePostCreated,
+		RoutingKeyPostUpdated: entities.NotificationTypePostUpdated,
+		RoutingKeyPostDeleted: entities.NotificationTypePostDeleted,
+	}
+
+	for routingKey, want := range cases {
+		repo := &mockNotificationRepo{}
+		svc := newTestNotificationServiceWithFollowers(repo, &mockPreferenceRepo{}, &mockFollowerDirectory{followerIDs: []string{"follower1"}}, FanoutConfig{PageSize: 10, MaxRecipients: 10})
+
+		if err := svc.ProcessEvent(context.Background(), routingKey, "msg-1", body); err != nil {
+			t.Fatalf("%s: ProcessEvent: %v", routingKey, err)
+		}
+		if len(repo.created) != 1 || repo.created[0].Type != want {
+			t.Errorf("%s: expected one %s notification, got %+v", routingKey, want, repo.created)
+		}
+	}
+}
+
+func TestProcessEvent_IgnoresUnknownRoutingKey(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	svc := newTestNotificationService(repo)
+
+	if err := svc.ProcessEvent(context.Background(), "user.created", "msg-1", []byte(`{}`)); err != nil {
+		t.Fatalf("expected unknown keys to be acknowledged, got %v", err)
+	}
+	if len(repo.created) != 0 {
+		t.Errorf("expected no notifications, got %d", len(repo.created))
+	}
+}
+
+func TestProcessEvent_SkipsDisabledType(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	preferences := &mockPreferenceRepo{enabled: map[string]bool{
+		preferenceKey("user1", entities.NotificationTypePostUpdated, entities.ChannelInApp): false,
+	}}
+	svc := newTestNotificationServiceWithPreferences(repo, preferences)
+	body := []byte(`{"post_id":"post1","user_id":"user1","title":"Hello","slug":"hello","published":true}`)
+
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostUpdated, "msg-1", body); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+	if len(repo.created) != 0 {
+		t.Fatalf("expected the disabled type to be skipped, got %d notifications", len(repo.created))
+	}
+
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostDeleted, "msg-2", body); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected other types to stay enabled, got %d notifications", len(repo.created))
+	}
+}
+
+func TestProcessEvent_PreferenceLookupFailureKeepsNotification(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	svc := newTestNotificationServiceWithPreferences(repo, &mockPreferenceRepo{err: errors.New("db down")})
+	body := []byte(`{"post_id":"post1","user_id":"user1","title":"Hello","slug":"hello","published":true}`)
+
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostUpdated, "msg-1", body); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected the enabled default on lookup failure, got %d notifications", len(repo.created))
+	}
+}
+
+func TestGetPreferences_FillsChannelDefaults(t *testing.T) {
+	preferences := &mockPreferenceRepo{}
+	svc := newTestNotificationServiceWithPreferences(&mockNotificationRepo{}, preferences)
+
+	resp, err := svc.UpdatePreferences(context.Background(), "user1", "", &dto.UpdatePreferencesRequest{
+		Preferences: []dto.NotificationPreference{{Type: "post_deleted", Enabled: false}},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	if len(resp.Preferences) != len(entities.NotificationTypes)*len(entities.NotificationChannels) {
+		t.Fatalf("expected every type and channel listed, got %d", len(resp.Preferences))
+	}
+	for _, preference := range resp.Preferences {
+		want := entities.DefaultEnabled(entities.NotificationType(preference.Type), entities.NotificationChannel(preference.Channel)) && preference.Type != "post_deleted"
+		if preference.Enabled != want {
+			t.Errorf("%s/%s: expected enabled=%v", preference.Type, preference.Channel, want)
+		}
+	}
+}
+
+func TestUpdatePreferences_RejectsUnknownType(t *testing.T) {
+	preferences := &mockPreferenceRepo{}
+	svc := newTestNotificationServiceWithPreferences(&mockNotificationRepo{}, preferences)
+
+	_, err := svc.UpdatePreferences(context.Background(), "user1", "", &dto.UpdatePreferencesRequest{
+		Preferences: []dto.NotificationPreference{{Type: "post_liked", Enabled: false}},
+	})
+	if err != appErrors.ErrInvalidPreferences {
+		t.Fatalf("expected ErrInvalidPreferences, got %v", err)
+	}
+	if len(preferences.enabled) != 0 {
+		t.Error("expected nothing stored for an invalid request")
+	}
+}
+
+func TestCreateNotification_EmailsOptedInUser(t *testing.T) {
+	preferences := &mockPreferenceRepo{}
+	sender := &email.FakeSender{}
+	svc := NewNotificationService(&mockNotificationRepo{}, preferences, cache.NewUnreadCountCache(time.Minute), stream.NewHub(4), sender, nil, FanoutConfig{}, DigestConfig{}, logger.New("info"))
+
+	_, err := svc.UpdatePreferences(context.Background(), "user1", "user1@example.com", &dto.UpdatePreferencesRequest{
+		Preferences: []dto.NotificationPreference{{Type: "system_alert", Channel: "email", Enabled: true}},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+
+	for _, notificationType := range []string{"system_alert", "post_created"} {
+		_, err := svc.CreateNotification(context.Background(), &dto.CreateNotificationRequest{
+			UserID: "user1", Type: notificationType, Title: "Maintenance tonight", Message: "The blog is read-only from 22:00.",
+		})
+		if err != nil {
+			t.Fatalf("CreateNotification(%s): %v", notificationType, err)
+		}
+	}
+	svc.WaitForEmails()
+
+	messages := sender.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("expected one email for the opted-in type only, got %d", len(messages))
+	}
+	if messages[0].To != "user1@example.com" || messages[0].Subject != "Maintenance tonight" {
+		t.Errorf("unexpected email %+v", messages[0])
+	}
+	if !strings.Contains(messages[0].Body, "The blog is read-only from 22:00.") {
+		t.Errorf("expected the message in the body, got %q", messages[0].Body)
+	}
+}
+
+func TestCreateNotification_EmailFailureDoesNotFail(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	preferences := &mockPreferenceRepo{
+		enabled: map[string]bool{preferenceKey("user1", entities.NotificationTypeSystemAlert, entities.ChannelEmail): true},
+		emails:  map[string]string{"user1": "user1@example.com"},
+	}
+	sender := &email.FakeSender{Err: errors.New("smtp down")}
+	svc := NewNotificationService(repo, preferences, cache.NewUnreadCountCache(time.Minute), stream.NewHub(4), sender, nil, FanoutConfig{}, DigestConfig{}, logger.New("info"))
+
+	if _, err := svc.CreateNotification(context.Background(), &dto.CreateNotificationRequest{
+		UserID: "user1", Type: "system_alert", Title: "Hi", Message: "Hello",
+	}); err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+	svc.WaitForEmails()
+
+	if len(repo.created) != 1 {
+		t.Fatalf("expected the in-app notification to be stored, got %d", len(repo.created))
+	}
+}
+
+func TestProcessEvent_SkipsRedeliveredMessage(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	svc := newTestNotificationService(repo)
+	body := []byte(`{"post_id":"post1","user_id":"user1","title":"Hello","slug":"hello","published":true}`)
+
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessEvent(context.Background(), RoutingKeyPostUpdated, "post.updated-123", body); err != nil {
+			t.Fatalf("delivery %d: ProcessEvent: %v", i+1, err)
+		}
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected a single notification for a redelivered message, got %d", len(repo.created))
+	}
+}
+
+func TestProcessPostCreatedEvent_NotifiesFollowersInPages(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	preferences := &mockPreferenceRepo{enabled: map[string]bool{
+		preferenceKey("f2", entities.NotificationTypePostCreated, entities.ChannelInApp): false,
+	}}
+	followers := &mockFollowerDirectory{followerIDs: []string{"f1", "f2", "f3", "f4", "f5"}}
+	svc := newTestNotificationServiceWithFollowers(repo, preferences, followers, FanoutConfig{PageSize: 2, MaxRecipients: 100})
+	body := []byte(`{"post_id":"post1","user_id":"author","title":"Hello","slug":"hello-world","published":true}`)
+
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostCreated, "msg-1", body); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+
+	if len(followers.limits) != 3 {
+		t.Errorf("expected 3 follower pages, got %v", followers.limits)
+	}
+	var recipients []string
+	for _, notification := range repo.created {
+		if notification.Type != entities.NotificationTypePostCreated {
+			t.Errorf("unexpected %s notification for %s", notification.Type, notification.UserID)
+		}
+		if notification.Data["post_slug"] != "hello-world" {
+			t.Errorf("expected post_slug for deep-linking, got %v", notification.Data)
+		}
+		recipients = append(recipients, notification.UserID)
+	}
+	if strings.Join(recipients, ",") != "f1,f3,f4,f5" {
+		t.Errorf("expected every follower but the opted-out one and no author notification, got %v", recipients)
+	}
+}
+
+func TestProcessPostCreatedEvent_CapsFanout(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	followers := &mockFollowerDirectory{followerIDs: []string{"f1", "f2", "f3", "f4", "f5"}}
+	svc := newTestNotificationServiceWithFollowers(repo, &mockPreferenceRepo{}, followers, FanoutConfig{PageSize: 2, MaxRecipients: 3})
+	body := []byte(`{"post_id":"post1","user_id":"author","title":"Hello","slug":"hello","published":true}`)
+
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostCreated, "msg-1", body); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+	if len(repo.created) != 3 {
+		t.Errorf("expected fan-out capped at 3 followers, got %d", len(repo.created))
+	}
+	if got := followers.limits[len(followers.limits)-1]; got != 1 {
+		t.Errorf("expected the last page to request only the remaining follower, got limit %d", got)
+	}
+}
+
+func TestProcessPostCreatedEvent_RedeliveryDoesNotDuplicateFanout(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	followers := &mockFollowerDirectory{followerIDs: []string{"f1", "f2", "f3"}}
+	svc := newTestNotificationServiceWithFollowers(repo, &mockPreferenceRepo{}, followers, FanoutConfig{PageSize: 2, MaxRecipients: 100})
+	body := []byte(`{"post_id":"post1","user_id":"author","title":"Hello","slug":"hello","published":true}`)
+
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessEvent(context.Background(), RoutingKeyPostCreated, "msg-1", body); err != nil {
+			t.Fatalf("delivery %d: ProcessEvent: %v", i+1, err)
+		}
+	}
+	if len(repo.created) != 3 {
+		t.Errorf("expected one notification per follower across redeliveries, got %d", len(repo.created))
+	}
+}
+
+func TestProcessPostCreatedEvent_RedeliveryAfterUnfollowNotifiesEachFollowerOnce(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	followers := &mockFollowerDirectory{followerIDs: []string{"f1", "f2", "f3", "f4", "f5"}, failOnCall: 2}
+	svc := newTestNotificationServiceWithFollowers(repo, &mockPreferenceRepo{}, followers, FanoutConfig{PageSize: 2, MaxRecipients: 100})
+	body := []byte(`{"post_id":"post1","user_id":"author","title":"Hello","slug":"hello","published":true}`)
+
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostCreated, "msg-1", body); err == nil {
+		t.Fatal("expected the first delivery to fail after one page")
+	}
+
+	// f1 unfollows before the redelivery, so every later follower moves up a
+	// page: f2, already notified, now opens the first page.
+	followers.followerIDs = followers.followerIDs[1:]
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostCreated, "msg-1", body); err != nil {
+		t.Fatalf("redelivery: ProcessEvent: %v", err)
+	}
+
+	var recipients []string
+	for _, notification := range repo.created {
+		recipients = append(recipients, notification.UserID)
+	}
+	if strings.Join(recipients, ",") != "f1,f2,f3,f4,f5" {
+		t.Errorf("expected each follower notified exactly once, got %v", recipients)
+	}
+}
+
+func TestProcessPostCreatedEvent_MarksProcessedWithoutAuthorNotification(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	followers := &mockFollowerDirectory{followerIDs: []string{"f1"}}
+	svc := newTestNotificationServiceWithFollowers(repo, &mockPreferenceRepo{}, followers, FanoutConfig{PageSize: 10, MaxRecipients: 10})
+	body := []byte(`{"post_id":"post1","user_id":"author","title":"Hello","slug":"hello","published":true}`)
+
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostCreated, "msg-1", body); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+	if !repo.processed["msg-1"] {
+		t.Fatal("expected the message recorded as processed although the author opted out")
+	}
+
+	// A redelivery is skipped before the follower lookup.
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostCreated, "msg-1", body); err != nil {
+		t.Fatalf("redelivery: ProcessEvent: %v", err)
+	}
+	if len(followers.limits) != 1 {
+		t.Errorf("expected the redelivery to skip the fan-out, got %d follower lookups", len(followers.limits))
+	}
+}
+
+func TestProcessPostCreatedEvent_SkipsDraftsForFollowers(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	followers := &mockFollowerDirectory{followerIDs: []string{"f1"}}
+	svc := newTestNotificationServiceWithFollowers(repo, &mockPreferenceRepo{}, followers, FanoutConfig{PageSize: 10, MaxRecipients: 10})
+	body := []byte(`{"post_id":"post1","user_id":"author","title":"Draft","slug":"draft","published":false}`)
+
+	if err := svc.ProcessEvent(context.Background(), RoutingKeyPostCreated, "msg-1", body); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+	if len(repo.created) != 0 || len(followers.limits) != 0 {
+		t.Errorf("expected no follower lookup or notification for a draft, got %d notifications", len(repo.created))
+	}
+}
+
+func TestMarkAsUnread_ClearsReadStateOfOwnNotifications(t *testing.T) {
+	readAt := time.Now()
+	repo := &mockNotificationRepo{all: []*entities.Notification{
+		{ID: "n1", UserID: "user1", Read: true, ReadAt: &readAt},
+		{ID: "n2", UserID: "user2", Read: true, ReadAt: &readAt},
+	}}
+	svc := newTestNotificationService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.GetUnreadCount(ctx, "user1"); err != nil {
+		t.Fatalf("GetUnreadCount: %v", err)
+	}
+	if err := svc.MarkAsUnread(ctx, "user1", &dto.MarkAsUnreadRequest{NotificationIDs: []string{"n1", "n2"}}); err != nil {
+		t.Fatalf("MarkAsUnread: %v", err)
+	}
+
+	if repo.all[0].Read || repo.all[0].ReadAt != nil {
+		t.Errorf("expected n1 to be unread with no read_at, got %+v", repo.all[0])
+	}
+	if !repo.all[1].Read {
+		t.Error("expected another user's notification to stay read")
+	}
+	count, err := svc.GetUnreadCount(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetUnreadCount: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected the cached unread count to be refreshed to 1, got %d", count)
+	}
+}
+
+func TestListNotifications_FiltersByType(t *testing.T) {
+	repo := &mockNotificationRepo{all: []*entities.Notification{
+		{ID: "n1", UserID: "user1", Type: entities.NotificationTypePostCreated},
+		{ID: "n2", UserID: "user1", Type: entities.NotificationTypeSystemAlert},
+	}}
+	svc := newTestNotificationService(repo)
+
+	resp, err := svc.ListNotifications(context.Background(), "user1", &dto.ListNotificationsRequest{Limit: 20, Type: "system_alert"})
+	if err != nil {
+		t.Fatalf("ListNotifications: %v", err)
+	}
+	if len(resp.Notifications) != 1 || resp.Notifications[0].ID != "n2" {
+		t.Errorf("expected only the system_alert notification, got %+v", resp.Notifications)
+	}
+}
+
+func TestListNotifications_TotalAndHasMoreFollowFilters(t *testing.T) {
+	repo := &mockNotificationRepo{all: []*entities.Notification{
+		{ID: "n1", UserID: "user1", Type: entities.NotificationTypePostCreated},
+		{ID: "n2", UserID: "user1", Type: entities.NotificationTypePostCreated, Read: true},
+		{ID: "n3", UserID: "user1", Type: entities.NotificationTypeSystemAlert},
+		{ID: "n4", UserID: "user1", Type: entities.NotificationTypePostCreated},
+		{ID: "n5", UserID: "user2", Type: entities.NotificationTypePostCreated},
+	}}
+	repo.unreadCount = 3
+	svc := newTestNotificationService(repo)
+	ctx := context.Background()
+
+	cases := []struct {
+		name        string
+		req         dto.ListNotificationsRequest
+		wantPage    int
+		wantTotal   int64
+		wantHasMore bool
+	}{
+		{"unfiltered first page", dto.ListNotificationsRequest{Limit: 2}, 2, 4, true},
+		{"unfiltered last page", dto.ListNotificationsRequest{Limit: 2, Offset: 2}, 2, 4, false},
+		{"by type", dto.ListNotificationsRequest{Limit: 2, Type: "post_created"}, 2, 3, true},
+		{"unread by type", dto.ListNotificationsRequest{Limit: 2, Unread: true, Type: "post_created"}, 2, 2, false},
+	}
+	for _, tc := range cases {
+		resp, err := svc.ListNotifications(ctx, "user1", &tc.req)
+		if err != nil {
+			t.Fatalf("%s: ListNotifications: %v", tc.name, err)
+		}
+		if len(resp.Notifications) != tc.wantPage || resp.Total != tc.wantTotal || resp.HasMore != tc.wantHasMore {
+			t.Errorf("%s: got page=%d total=%d has_more=%v, want %d/%d/%v",
+				tc.name, len(resp.Notifications), resp.Total, resp.HasMore, tc.wantPage, tc.wantTotal, tc.wantHasMore)
+		}
+		if resp.UnreadCount != 3 {
+			t.Errorf("%s: expected the overall unread count 3 regardless of filters, got %d", tc.name, resp.UnreadCount)
+		}
+	}
+}
+
+func TestListNotifications_PageAfterTheEndKeepsTotal(t *testing.T) {
+	repo := &mockNotificationRepo{all: []*entities.Notification{
+		{ID: "n1", UserID: "user1", Type: entities.NotificationTypePostCreated},
+		{ID: "n2", UserID: "user1", Type: entities.NotificationTypePostCreated},
+	}}
+	svc := newTestNotificationService(repo)
+
+	resp, err := svc.ListNotifications(context.Background(), "user1", &dto.ListNotificationsRequest{Limit: 20, Offset: 40})
+	if err != nil {
+		t.Fatalf("ListNotifications: %v", err)
+	}
+	if len(resp.Notifications) != 0 || resp.Total != 2 || resp.HasMore {
+		t.Errorf("expected an empty page with total 2, got page=%d total=%d has_more=%v", len(resp.Notifications), resp.Total, resp.HasMore)
+	}
+}
+
+func TestListNotifications_UsesCachedUnreadCount(t *testing.T) {
+	repo := &mockNotificationRepo{unreadCount: 5}
+	svc := newTestNotificationService(repo)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		resp, err := svc.ListNotifications(ctx, "user1", &dto.ListNotificationsRequest{Limit: 20})
+		if err != nil {
+			t.Fatalf("ListNotifications: %v", err)
+		}
+		if resp.UnreadCount != 5 {
+			t.Fatalf("expected unread count 5, got %d", resp.UnreadCount)
+		}
+	}
+	if repo.unreadCountCalls != 1 {
+		t.Errorf("expected the unread count queried once, got %d", repo.unreadCountCalls)
+	}
+}
+
+func TestBuildDigest_CoalescesCountsByType(t *testing.T) {
+	windowStart := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	windowEnd := windowStart.Add(time.Hour)
+	var sources []*entities.Notification
+	for i, notificationType := range []entities.NotificationType{
+		entities.NotificationTypeUserFollowed,
+		entities.NotificationTypePostCreated,
+		entities.NotificationTypePostCreated,
+		entities.NotificationTypePostUpdated,
+		entities.NotificationTypePostCreated,
+	} {
+		sources = append(sources, &entities.Notification{ID: fmt.Sprintf("n%d", i), UserID: "user1", Type: notificationType})
+	}
+
+	digest := buildDigest("user1", entities.DigestModeHourly, sources, windowStart, windowEnd)
+	if digest == nil {
+		t.Fatal("expected a digest")
+	}
+	if digest.UserID != "user1" || digest.Type != entities.NotificationTypeDigest {
+		t.Fatalf("unexpected digest recipient or type: %+v", digest)
+	}
+	if digest.Title != "Your hourly notification digest" {
+		t.Errorf("unexpected title %q", digest.Title)
+	}
+	want := "You have 5 new notifications: 3 new posts from people you follow, 1 post update, 1 new follower."
+	if digest.Message != want {
+		t.Errorf("expected message %q, got %q", want, digest.Message)
+	}
+	if digest.Data["count"] != 5 {
+		t.Errorf("expected count 5, got %v", digest.Data["count"])
+	}
+	byType := digest.Data["by_type"].(map[string]interface{})
+	if byType["post_created"] != 3 || byType["post_updated"] != 1 || byType["user_followed"] != 1 || len(byType) != 3 {
+		t.Errorf("unexpected by_type %v", byType)
+	}
+	if digest.Data["window_start"] != "2026-01-01T10:00:00Z" || digest.Data["window_end"] != "2026-01-01T11:00:00Z" {
+		t.Errorf("unexpected window %v - %v", digest.Data["window_start"], digest.Data["window_end"])
+	}
+}
+
+func TestBuildDigest_EmptyWindow(t *testing.T) {
+	if digest := buildDigest("user1", entities.DigestModeDaily, nil, time.Now(), time.Now()); digest != nil {
+		t.Fatalf("expected no digest for an empty window, got %+v", digest)
+	}
+}
+
+func newTestDigestService(repo *mockNotificationRepo, preferences *mockPreferenceRepo, hub *stream.Hub) *NotificationService {
+	digest := DigestConfig{HourlyWindow: time.Hour, DailyWindow: 24 * time.Hour, BatchSize: 10}
+	return NewNotificationService(repo, preferences, cache.NewUnreadCountCache(time.Minute), hub, nil, nil, FanoutConfig{}, digest, logger.New("info"))
+}
+
+func TestRunDigests_CoalescesUndigestedNotificationsOnce(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-90 * time.Minute)
+	repo := &mockNotificationRepo{all: []*entities.Notification{
+		{ID: "before", UserID: "user1", Type: entities.NotificationTypePostCreated, CreatedAt: since.Add(-time.Minute)},
+		{ID: "a", UserID: "user1", Type: entities.NotificationTypePostCreated, CreatedAt: since.Add(time.Minute)},
+		{ID: "b", UserID: "user1", Type: entities.NotificationTypePostCreated, CreatedAt: since.Add(2 * time.Minute)},
+		{ID: "c", UserID: "user1", Type: entities.NotificationTypeUserFollowed, CreatedAt: since.Add(3 * time.Minute)},
+		{ID: "other", UserID: "user2", Type: entities.NotificationTypePostCreated, CreatedAt: since.Add(time.Minute)},
+	}}
+	preferences := &mockPreferenceRepo{digests: map[string]*entities.DigestSubscription{
+		"user1": {UserID: "user1", Mode: entities.DigestModeHourly, Since: since},
+		// Still inside its window, so not due yet.
+		"user2": {UserID: "user2", Mode: entities.DigestModeHourly, Since: now.Add(-time.Minute)},
+	}}
+	hub := stream.NewHub(4)
+	live, unsubscribe := hub.Subscribe("user1")
+	defer unsubscribe()
+	svc := newTestDigestService(repo, preferences, hub)
+
+	if err := svc.RunDigests(context.Background(), now); err != nil {
+		t.Fatalf("RunDigests: %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected one digest, got %d notifications", len(repo.created))
+	}
+	digest := repo.created[0]
+	if digest.UserID != "user1" || digest.Type != entities.NotificationTypeDigest || digest.Data["count"] != 3 {
+		t.Fatalf("expected a digest of user1's 3 notifications, got %+v", digest)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if !repo.digested[id] {
+			t.Errorf("expected %s to be marked digested", id)
+		}
+	}
+	if repo.digested["before"] || repo.digested["other"] {
+		t.Errorf("expected notifications outside the window to be left alone, got %v", repo.digested)
+	}
+	select {
+	case pushed := <-live:
+		if pushed.ID != digest.ID {
+			t.Errorf("expected the digest to be pushed, got %s", pushed.ID)
+		}
+	default:
+		t.Error("expected the digest to be pushed to the live stream")
+	}
+
+	// A later run over the same window finds nothing left to summarise.
+	if err := svc.RunDigests(context.Background(), now.Add(time.Minute)); err != nil {
+		t.Fatalf("RunDigests: %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected digested notifications not to be counted twice, got %d notifications", len(repo.created))
+	}
+}
+
+func TestRunDigests_CapsSourcesPerDigest(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-2 * time.Hour)
+	repo := &mockNotificationRepo{}
+	for i := 0; i < 5; i++ {
+		repo.all = append(repo.all, &entities.Notification{ID: strconv.Itoa(i), UserID: "user1", Type: entities.NotificationTypePostCreated, CreatedAt: since.Add(time.Duration(i+1) * time.Minute)})
+	}
+	preferences := &mockPreferenceRepo{digests: map[string]*entities.DigestSubscription{
+		"user1": {UserID: "user1", Mode: entities.DigestModeHourly, Since: since},
+	}}
+	svc := newTestDigestService(repo, preferences, stream.NewHub(4))
+	svc.digest.MaxSources = 3
+
+	if err := svc.RunDigests(context.Background(), now); err != nil {
+		t.Fatalf("RunDigests: %v", err)
+	}
+	if len(repo.created) != 1 || repo.created[0].Data["count"] != 3 {
+		t.Fatalf("expected one digest of 3 notifications, got %+v", repo.created)
+	}
+	if want := since.Add(3 * time.Minute).UTC().Format(time.RFC3339); repo.created[0].Data["window_end"] != want {
+		t.Errorf("expected the window to end at the last notification taken (%s), got %v", want, repo.created[0].Data["window_end"])
+	}
+	if repo.digested["3"] || repo.digested["4"] {
+		t.Error("expected the overflow to be left for the next digest")
+	}
+}
+
+func TestRunDigests_SkipsWindowSentByAnotherRun(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-2 * time.Hour)
+	repo := &mockNotificationRepo{
+		all:            []*entities.Notification{{ID: "a", UserID: "user1", Type: entities.NotificationTypePostCreated, CreatedAt: since.Add(time.Minute)}},
+		digestConflict: true,
+	}
+	preferences := &mockPreferenceRepo{digests: map[string]*entities.DigestSubscription{
+		"user1": {UserID: "user1", Mode: entities.DigestModeHourly, Since: since},
+	}}
+	hub := stream.NewHub(4)
+	live, unsubscribe := hub.Subscribe("user1")
+	defer unsubscribe()
+	svc := newTestDigestService(repo, preferences, hub)
+
+	if err := svc.RunDigests(context.Background(), now); err != nil {
+		t.Fatalf("RunDigests: %v", err)
+	}
+	select {
+	case pushed := <-live:
+		t.Fatalf("expected no push for a window another run sent, got %+v", pushed)
+	default:
+	}
+}
+
+func TestProcessEvent_DigestUsersAreNotPushedImmediately(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	preferences := &mockPreferenceRepo{digests: map[string]*entities.DigestSubscription{
+		"user1": {UserID: "user1", Mode: entities.DigestModeDaily, Since: time.Now()},
+	}}
+	hub := stream.NewHub(4)
+	digestLive, unsubscribeDigest := hub.Subscribe("user1")
+	defer unsubscribeDigest()
+	immediateLive, unsubscribeImmediate := hub.Subscribe("user2")
+	defer unsubscribeImmediate()
+	svc := newTestDigestService(repo, preferences, hub)
+
+	for i, userID := range []string{"user1", "user2"} {
+		body := []byte(`{"post_id":"post1","user_id":"` + userID + `","title":"Hello","slug":"hello","published":true}`)
+		if err := svc.ProcessEvent(context.Background(), RoutingKeyPostUpdated, "msg-"+strconv.Itoa(i), body); err != nil {
+			t.Fatalf("ProcessEvent: %v", err)
+		}
+	}
+	if len(repo.created) != 2 {
+		t.Fatalf("expected both notifications stored, got %d", len(repo.created))
+	}
+	select {
+	case pushed := <-digestLive:
+		t.Fatalf("expected the digest user's notification to wait for the digest, got %+v", pushed)
+	default:
+	}
+	select {
+	case <-immediateLive:
+	default:
+		t.Fatal("expected the immediate user's notification to be pushed")
+	}
+}
+
+func TestUpdatePreferences_SetsDigestMode(t *testing.T) {
+	preferences := &mockPreferenceRepo{}
+	svc := newTestNotificationServiceWithPreferences(&mockNotificationRepo{}, preferences)
+
+	resp, err := svc.UpdatePreferences(context.Background(), "user1", "", &dto.UpdatePreferencesRequest{DigestMode: "hourly"})
+	if err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	if resp.DigestMode != "hourly" {
+		t.Fatalf("expected digest mode hourly, got %q", resp.DigestMode)
+	}
+	if len(preferences.enabled) != 0 {
+		t.Error("expected type preferences untouched")
+	}
+
+	for _, req := range []*dto.UpdatePreferencesRequest{{DigestMode: "weekly"}, {}} {
+		if _, err := svc.UpdatePreferences(context.Background(), "user1", "", req); err != appErrors.ErrInvalidPreferences {
+			t.Fatalf("expected ErrInvalidPreferences for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestListNotifications_FiltersByPriority(t *testing.T) {
+	repo := &mockNotificationRepo{all: []*entities.Notification{
+		{ID: "n1", UserID: "user1", Type: entities.NotificationTypePostCreated, Priority: entities.PriorityNormal},
+		{ID: "n2", UserID: "user1", Type: entities.NotificationTypeSystemAlert, Priority: entities.PriorityHigh},
+		{ID: "n3", UserID: "user1", Type: entities.NotificationTypePostUpdated, Priority: entities.PriorityLow},
+	}}
+	svc := newTestNotificationService(repo)
+
+	resp, err := svc.ListNotifications(context.Background(), "user1", &dto.ListNotificationsRequest{Limit: 20, Priority: "high"})
+	if err != nil {
+		t.Fatalf("ListNotifications: %v", err)
+	}
+	if len(resp.Notifications) != 1 || resp.Notifications[0].ID != "n2" || resp.Notifications[0].Priority != "high" {
+		t.Errorf("expected only the high-priority notification, got %+v", resp.Notifications)
+	}
+	if resp.Total != 1 {
+		t.Errorf("expected the total to follow the priority filter, got %d", resp.Total)
+	}
+}
+
+func TestCreateNotification_DefaultsToNormalPriority(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	svc := newTestNotificationService(repo)
+
+	resp, err := svc.CreateNotification(context.Background(), &dto.CreateNotificationRequest{
+		UserID: "user1", Type: "system_alert", Title: "Hi", Message: "Hello",
+	})
+	if err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+	if resp.Priority != "normal" || repo.created[0].Priority != entities.PriorityNormal {
+		t.Fatalf("expected normal priority by default, got %q", resp.Priority)
+	}
+}
+
+func TestCreateNotification_HighPriorityBypassesDigest(t *testing.T) {
+	repo := &mockNotificationRepo{}
+	preferences := &mockPreferenceRepo{digests: map[string]*entities.DigestSubscription{
+		"user1": {UserID: "user1", Mode: entities.DigestModeDaily, Since: time.Now()},
+	}}
+	hub := stream.NewHub(4)
+	live, unsubscribe := hub.Subscribe("user1")
+	defer unsubscribe()
+	svc := newTestDigestService(repo, preferences, hub)
+
+	for _, priority := range []string{"normal", "high"} {
+		if _, err := svc.CreateNotification(context.Background(), &dto.CreateNotificationRequest{
+			UserID: "user1", Type: "system_alert", Title: "Hi", Message: priority, Priority: priority,
+		}); err != nil {
+			t.Fatalf("CreateNotification: %v", err)
+		}
+	}
+
+	select {
+	case pushed := <-live:
+		if pushed.Priority != entities.PriorityHigh {
+			t.Fatalf("expected only the high-priority notification pushed, got %+v", pushed)
+		}
+	default:
+		t.Fatal("expected the high-priority notification to be pushed despite the digest")
+	}
+	select {
+	case pushed := <-live:
+		t.Fatalf("expected the normal notification to wait for the digest, got %+v", pushed)
+	default:
+	}
+
+	// The high-priority one was already delivered, so the digest leaves it out.
+	repo.all = repo.created
+	for _, n := range repo.all {
+		n.CreatedAt = time.Now()
+	}
+	preferences.digests["user1"].Since = time.Now().Add(-48 * time.Hour)
+	if err := svc.RunDigests(context.Background(), time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("RunDigests: %v", err)
+	}
+	digest := repo.created[len(repo.created)-1]
+	if digest.Type != entities.NotificationTypeDigest || digest.Data["count"] != 1 {
+		t.Fatalf("expected a digest of the normal notification only, got %+v", digest)
+	}
+}
+
+func TestCountByType_ZeroFillsKnownTypes(t *testing.T) {
+	repo := &mockNotificationRepo{all: []*entities.Notification{
+		{ID: "n1", UserID: "user1", Type: entities.NotificationTypeCommentAdded},
+		{ID: "n2", UserID: "user1", Type: entities.NotificationTypeCommentAdded, Read: true},
+		{ID: "n3", UserID: "user1", Type: entities.NotificationTypeSystemAlert},
+		{ID: "n4", UserID: "user2", Type: entities.NotificationTypeSystemAlert},
+	}}
+	svc := newTestNotificationService(repo)
+	ctx := context.Background()
+
+	all, err := svc.CountByType(ctx, "user1", false)
+	if err != nil {
+		t.Fatalf("CountByType: %v", err)
+	}
+	if len(all.Counts) != len(entities.NotificationTypes) {
+		t.Fatalf("expected an entry for each of the %d types, got %v", len(entities.NotificationTypes), all.Counts)
+	}
+	if all.Counts["comment_added"] != 2 || all.Counts["system_alert"] != 1 || all.Counts["post_created"] != 0 || all.Total != 3 {
+		t.Fatalf("unexpected counts %+v", all)
+	}
+
+	unread, err := svc.CountByType(ctx, "user1", true)
+	if err != nil {
+		t.Fatalf("CountByType: %v", err)
+	}
+	if !unread.UnreadOnly || unread.Counts["comment_added"] != 1 || unread.Total != 2 {
+		t.Fatalf("unexpected unread counts %+v", unread)
+	}
+}
+
+func listIDs(t *testing.T, svc *NotificationService, req *dto.ListNotificationsRequest) []string {
+	t.Helper()
+	req.Limit = 20
+	resp, err := svc.ListNotifications(context.Background(), "user1", req)
+	if err != nil {
+		t.Fatalf("ListNotifications: %v", err)
+	}
+	ids := make([]string, 0, len(resp.Notifications))
+	for _, n := range resp.Notifications {
+		ids = append(ids, n.ID)
+	}
+	if resp.Total != int64(len(ids)) {
+		t.Fatalf("expected total %d to match the page, got %d", len(ids), resp.Total)
+	}
+	return ids
+}
+
+func TestArchiveNotification_FilterInteractions(t *testing.T) {
+	repo := &mockNotificationRepo{unreadCount: 2, all: []*entities.Notification{
+		{ID: "n1", UserID: "user1", Type: entities.NotificationTypeCommentAdded},
+		{ID: "n2", UserID: "user1", Type: entities.NotificationTypeCommentAdded},
+		{ID: "n3", UserID: "user1", Type: entities.NotificationTypeSystemAlert, Read: true},
+	}}
+	svc := newTestNotificationService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.GetUnreadCount(ctx, "user1"); err != nil {
+		t.Fatalf("GetUnreadCount: %v", err)
+	}
+	if err := svc.ArchiveNotification(ctx, "n1", "user1"); err != nil {
+		t.Fatalf("ArchiveNotification: %v", err)
+	}
+	// Archiving twice is not an error.
+	if err := svc.ArchiveNotification(ctx, "n1", "user1"); err != nil {
+		t.Fatalf("ArchiveNotification again: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		req  dto.ListNotificationsRequest
+		want string
+	}{
+		{"inbox", dto.ListNotificationsRequest{}, "n2,n3"},
+		{"inbox unread", dto.ListNotificationsRequest{Unread: true}, "n2"},
+		{"with archived", dto.ListNotificationsRequest{IncludeArchived: true}, "n1,n2,n3"},
+		{"with archived unread", dto.ListNotificationsRequest{IncludeArchived: true, Unread: true}, "n1,n2"},
+		{"with archived by type", dto.ListNotificationsRequest{IncludeArchived: true, Type: "comment_added"}, "n1,n2"},
+	}
+	for _, tc := range cases {
+		req := tc.req
+		if got := strings.Join(listIDs(t, svc, &req), ","); got != tc.want {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+
+	// The cached unread count was dropped when n1 left the inbox.
+	if count, err := svc.GetUnreadCount(ctx, "user1"); err != nil || count != 1 {
+		t.Fatalf("expected 1 unread after archiving, got %d (%v)", count, err)
+	}
+	counts, err := svc.CountByType(ctx, "user1", false)
+	if err != nil || counts.Counts["comment_added"] != 1 {
+		t.Fatalf("expected archived notifications left out of the counts, got %+v (%v)", counts, err)
+	}
+
+	if err := svc.UnarchiveNotification(ctx, "n1", "user1"); err != nil {
+		t.Fatalf("UnarchiveNotification: %v", err)
+	}
+	if got := strings.Join(listIDs(t, svc, &dto.ListNotificationsRequest{Unread: true}), ","); got != "n1,n2" {
+		t.Errorf("expected n1 back in the inbox, got %s", got)
+	}
+	if count, _ := svc.GetUnreadCount(ctx, "user1"); count != 2 {
+		t.Fatalf("expected 2 unread after unarchiving, got %d", count)
+	}
+}
+
+func TestArchiveNotification_OtherUsersNotificationIsNotFound(t *testing.T) {
+	repo := &mockNotificationRepo{all: []*entities.Notification{{ID: "n1", UserID: "user2"}}}
+	svc := newTestNotificationService(repo)
+
+	if err := svc.ArchiveNotification(context.Background(), "n1", "user1"); err != appErrors.ErrNotificationNotFound {
+		t.Fatalf("expected ErrNotificationNotFound, got %v", err)
+	}
+	if repo.all[0].ArchivedAt != nil {
+		t.Fatal("expected another user's notification to stay in their inbox")
+	}
+}
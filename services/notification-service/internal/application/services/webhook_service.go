@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification-service/internal/application/dto"
+	"notification-service/internal/application/errors"
+	"notification-service/internal/domain/entities"
+	"notification-service/internal/domain/repositories"
+	"notification-service/pkg/logger"
+)
+
+// WebhookDeliveryConfig bounds the attempts made for one event and one
+// subscription. The backoff doubles after every failed attempt up to
+// MaxBackoff.
+type WebhookDeliveryConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// maxDeliveryErrorLength keeps recorded errors within the delivery log column.
+const maxDeliveryErrorLength = 1000
+
+// WebhookService manages webhook subscriptions and sends post events to them.
+// It consumes its own queue, so slow subscribers never hold up notifications.
+type WebhookService struct {
+	repo     repositories.WebhookRepository
+	sender   repositories.WebhookSender
+	delivery WebhookDeliveryConfig
+	logger   *logger.Logger
+}
+
+func NewWebhookService(repo repositories.WebhookRepository, sender repositories.WebhookSender, delivery WebhookDeliveryConfig, logger *logger.Logger) *WebhookService {
+	if delivery.MaxAttempts < 1 {
+		delivery.MaxAttempts = 1
+	}
+	return &WebhookService{repo: repo, sender: sender, delivery: delivery, logger: logger}
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	events, ok := normalizeWebhookEvents(req.Events)
+	if !ok || !entities.IsValidWebhookURL(req.URL) {
+		return nil, errors.ErrInvalidWebhook
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to generate webhook secret: %v", err))
+			return nil, errors.ErrWebhookFailed
+		}
+	}
+
+	subscription := &entities.WebhookSubscription{
+		ID:     uuid.New().String(),
+		URL:    req.URL,
+		Secret: secret,
+		Events: events,
+		Active: req.Active == nil || *req.Active,
+	}
+	if err := s.repo.Create(ctx, subscription); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to create webhook subscription: %v", err))
+		return nil, errors.ErrWebhookFailed
+	}
+
+	s.logger.Info("webhook subscription created", logger.F("webhook_id", subscription.ID), logger.F("events", events))
+	response := webhookResponse(subscription)
+	response.Secret = subscription.Secret
+	return response, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*dto.WebhookResponse, error) {
+	subscriptions, err := s.repo.List(ctx)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to list webhook subscriptions: %v", err))
+		return nil, errors.ErrWebhookFailed
+	}
+
+	responses := make([]*dto.WebhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		responses = append(responses, webhookResponse(subscription))
+	}
+	return responses, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id string) (*dto.WebhookResponse, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return webhookResponse(subscription), nil
+}
+
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id string, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if !entities.IsValidWebhookURL(*req.URL) {
+			return nil, errors.ErrInvalidWebhook
+		}
+		subscription.URL = *req.URL
+	}
+	if req.Events != nil {
+		events, ok := normalizeWebhookEvents(req.Events)
+		if !ok {
+			return nil, errors.ErrInvalidWebhook
+		}
+		subscription.Events = events
+	}
+	if req.Secret != nil {
+		subscription.Secret = *req.Secret
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+
+	updated, err := s.repo.Update(ctx, subscription)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to update webhook subscription: %v", err))
+		return nil, errors.ErrWebhookFailed
+	}
+	if !updated {
+		return nil, errors.ErrWebhookNotFound
+	}
+	return webhookResponse(subscription), nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id string) error {
+	deleted, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to delete webhook subscription: %v", err))
+		return errors.ErrWebhookFailed
+	}
+	if !deleted {
+		return errors.ErrWebhookNotFound
+	}
+	s.logger.Info("webhook subscription deleted", logger.F("webhook_id", id))
+	return nil
+}
+
+// ListDeliveries returns the subscription's most recent deliveries, newest
+// first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, id string, limit int) ([]*dto.WebhookDeliveryResponse, error) {
+	if _, err := s.getSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to list webhook deliveries: %v", err))
+		return nil, errors.ErrWebhookFailed
+	}
+
+	responses := make([]*dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		responses = append(responses, &dto.WebhookDeliveryResponse{
+			ID:         delivery.ID,
+			Event:      delivery.Event,
+			MessageID:  delivery.MessageID,
+			Attempts:   delivery.Attempts,
+			StatusCode: delivery.StatusCode,
+			Success:    delivery.Success,
+			Error:      delivery.Error,
+			CreatedAt:  delivery.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// ProcessEvent sends a post event to every active subscription that wants it,
+// all subscriptions at once. A subscriber that still fails after its retries
+// is recorded in the delivery log rather than failing the message, which
+// would resend the event to the subscribers that did receive it.
+func (s *WebhookService) ProcessEvent(ctx context.Context, routingKey, messageID string, eventData []byte) error {
+	if !entities.IsValidWebhookEvent(routingKey) {
+		s.logger.Warn("received unsupported routing key for webhooks: " + routingKey)
+		return nil
+	}
+	if !json.Valid(eventData) {
+		return fmt.Errorf("malformed %s event", routingKey)
+	}
+
+	subscriptions, err := s.repo.ListActiveForEvent(ctx, routingKey)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payloadID := messageID
+	if payloadID == "" {
+		payloadID = uuid.New().String()
+	}
+	body, err := json.Marshal(&dto.WebhookPayload{ID: payloadID, Event: routingKey, CreatedAt: time.Now().UTC(), Data: eventData})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, subscription := range subscriptions {
+		wg.Add(1)
+		go func(subscription *entities.WebhookSubscription) {
+			defer wg.Done()
+			s.deliver(ctx, subscription, routingKey, messageID, body)
+		}(subscription)
+	}
+	wg.Wait()
+	return nil
+}
+
+// deliver sends body to subscription, retrying network errors, timeouts, rate
+// limiting and server errors with exponential backoff, and records the
+// outcome.
+func (s *WebhookService) deliver(ctx context.Context, subscription *entities.WebhookSubscription, event, messageID string, body []byte) {
+	delivery := &entities.WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscription.ID,
+		Event:          event,
+		MessageID:      messageID,
+	}
+	request := &entities.WebhookRequest{
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		Event:      event,
+		DeliveryID: delivery.ID,
+		Body:       body,
+	}
+
+	var err error
+	backoff := s.delivery.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		delivery.Attempts = attempt
+		delivery.StatusCode, err = s.sender.Send(ctx, request)
+		if err == nil || attempt >= s.delivery.MaxAttempts || !retryableWebhookStatus(delivery.StatusCode) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(backoff):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		backoff *= 2
+		if s.delivery.MaxBackoff > 0 && backoff > s.delivery.MaxBackoff {
+			backoff = s.delivery.MaxBackoff
+		}
+	}
+
+	delivery.Success = err == nil
+	if err != nil {
+		delivery.Error = truncateRunes(err.Error(), maxDeliveryErrorLength)
+		s.logger.Warn("webhook delivery failed",
+			logger.F("webhook_id", subscription.ID),
+			logger.F("event", event),
+			logger.F("attempts", delivery.Attempts),
+			logger.Err(err),
+		)
+	}
+
+	// The log must not lose the outcome because the message context ended.
+	if err := s.repo.RecordDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to record webhook delivery: %v", err))
+	}
+}
+
+func (s *WebhookService) getSubscription(ctx context.Context, id string) (*entities.WebhookSubscription, error) {
+	subscription, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to get webhook subscription: %v", err))
+		return nil, errors.ErrWebhookFailed
+	}
+	if subscription == nil {
+		return nil, errors.ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+// retryableWebhookStatus reports whether a failed attempt may succeed later:
+// no response at all, a timeout, rate limiting or a server error.
+func retryableWebhookStatus(status int) bool {
+	return status == 0 || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+// normalizeWebhookEvents drops duplicate events and reports whether events is
+// non-empty and every event is known.
+func normalizeWebhookEvents(events []string) ([]string, bool) {
+	if len(events) == 0 {
+		return nil, false
+	}
+	seen := make(map[string]bool, len(events))
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		if !entities.IsValidWebhookEvent(event) {
+			return nil, false
+		}
+		if !seen[event] {
+			seen[event] = true
+			normalized = append(normalized, event)
+		}
+	}
+	return normalized, true
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// webhookResponse leaves the secret out; only the create response carries it.
+func webhookResponse(subscription *entities.WebhookSubscription) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.Events,
+		Active:    subscription.Active,
+		CreatedAt: subscription.CreatedAt,
+		UpdatedAt: subscription.UpdatedAt,
+	}
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
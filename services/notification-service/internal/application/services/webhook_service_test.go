@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/internal/application/dto"
+	appErrors "notification-service/internal/application/errors"
+	"notification-service/internal/domain/entities"
+	"notification-service/pkg/logger"
+)
+
+type mockWebhookRepo struct {
+	mu            sync.Mutex
+	subscriptions map[string]*entities.WebhookSubscription
+	deliveries    []*entities.WebhookDelivery
+}
+
+func newMockWebhookRepo(subscriptions ...*entities.WebhookSubscription) *mockWebhookRepo {
+	repo := &mockWebhookRepo{subscriptions: make(map[string]*entities.WebhookSubscription)}
+	for _, subscription := range subscriptions {
+		repo.subscriptions[subscription.ID] = subscription
+	}
+	return repo
+}
+
+func (m *mockWebhookRepo) Create(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	m.subscriptions[subscription.ID] = subscription
+	return nil
+}
+func (m *mockWebhookRepo) GetByID(ctx context.Context, id string) (*entities.WebhookSubscription, error) {
+	if subscription, ok := m.subscriptions[id]; ok {
+		copied := *subscription
+		return &copied, nil
+	}
+	return nil, nil
+}
+func (m *mockWebhookRepo) List(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	var subscriptions []*entities.WebhookSubscription
+	for _, subscription := range m.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+func (m *mockWebhookRepo) Update(ctx context.Context, subscription *entities.WebhookSubscription) (bool, error) {
+	if _, ok := m.subscriptions[subscription.ID]; !ok {
+		return false, nil
+	}
+	m.subscriptions[subscription.ID] = subscription
+	return true, nil
+}
+func (m *mockWebhookRepo) Delete(ctx context.Context, id string) (bool, error) {
+	_, ok := m.subscriptions[id]
+	delete(m.subscriptions, id)
+	return ok, nil
+}
+func (m *mockWebhookRepo) ListActiveForEvent(ctx context.Context, event string) ([]*entities.WebhookSubscription, error) {
+	var subscriptions []*entities.WebhookSubscription
+	for _, subscription := range m.subscriptions {
+		if subscription.Wants(event) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+func (m *mockWebhookRepo) RecordDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+func (m *mockWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*entities.WebhookDelivery, error) {
+	var deliveries []*entities.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+// scriptedSender answers each URL's attempts with the statuses in script, in
+// order, then with 200.
+type scriptedSender struct {
+	mu       sync.Mutex
+	script   map[string][]int
+	requests []*entities.WebhookRequest
+}
+
+func (s *scriptedSender) Send(ctx context.Context, request *entities.WebhookRequest) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, request)
+
+	status := 200
+	if statuses := s.script[request.URL]; len(statuses) > 0 {
+		status, s.script[request.URL] = statuses[0], statuses[1:]
+	}
+	if status != 200 {
+		return status, errors.New("webhook endpoint failed")
+	}
+	return status, nil
+}
+
+func newTestWebhookService(repo *mockWebhookRepo, sender *scriptedSender) *WebhookService {
+	delivery := WebhookDeliveryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	return NewWebhookService(repo, sender, delivery, logger.New("info"))
+}
+
+func webhookSubscription(id string, events ...string) *entities.WebhookSubscription {
+	return &entities.WebhookSubscription{ID: id, URL: "https://" + id + ".example.com/hook", Secret: "secret-" + id, Events: events, Active: true}
+}
+
+func TestWebhookProcessEvent_SendsToSubscribersOfTheEvent(t *testing.T) {
+	inactive := webhookSubscription("inactive", entities.WebhookEventPostCreated)
+	inactive.Active = false
+	repo := newMockWebhookRepo(
+		webhookSubscription("created", entities.WebhookEventPostCreated),
+		webhookSubscription("deleted", entities.WebhookEventPostDeleted),
+		inactive,
+	)
+	sender := &scriptedSender{}
+	svc := newTestWebhookService(repo, sender)
+
+	event := []byte(`{"post_id":"p1","user_id":"u1"}`)
+	if err := svc.ProcessEvent(context.Background(), entities.WebhookEventPostCreated, "msg-1", event); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+
+	if len(sender.requests) != 1 || sender.requests[0].URL != "https://created.example.com/hook" {
+		t.Fatalf("expected one request to the post.created subscriber, got %+v", sender.requests)
+	}
+	request := sender.requests[0]
+	if request.Secret != "secret-created" || request.Event != entities.WebhookEventPostCreated {
+		t.Fatalf("expected the subscriber's secret and the event, got %+v", request)
+	}
+	var payload dto.WebhookPayload
+	if err := json.Unmarshal(request.Body, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.ID != "msg-1" || payload.Event != entities.WebhookEventPostCreated || string(payload.Data) != string(event) {
+		t.Fatalf("unexpected payload %s", request.Body)
+	}
+}
+
+func TestWebhookProcessEvent_RetriesServerErrorsWithBackoff(t *testing.T) {
+	repo := newMockWebhookRepo(webhookSubscription("flaky", entities.WebhookEventPostUpdated))
+	sender := &scriptedSender{script: map[string][]int{"https://flaky.example.com/hook": {503, 0}}}
+	svc := newTestWebhookService(repo, sender)
+
+	if err := svc.ProcessEvent(context.Background(), entities.WebhookEventPostUpdated, "msg-1", []byte(`{}`)); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+
+	if len(sender.requests) != 3 {
+		t.Fatalf("expected 2 failed attempts and a successful one, got %d", len(sender.requests))
+	}
+	if sender.requests[0].DeliveryID != sender.requests[2].DeliveryID {
+		t.Fatal("expected every attempt to carry the same delivery ID")
+	}
+	if len(repo.deliveries) != 1 || !repo.deliveries[0].Success || repo.deliveries[0].Attempts != 3 {
+		t.Fatalf("expected one successful delivery after 3 attempts, got %+v", repo.deliveries)
+	}
+}
+
+func TestWebhookProcessEvent_GivesUpAndLogsTheFailure(t *testing.T) {
+	repo := newMockWebhookRepo(
+		webhookSubscription("rejects", entities.WebhookEventPostDeleted),
+		webhookSubscription("down", entities.WebhookEventPostDeleted),
+	)
+	sender := &scriptedSender{script: map[string][]int{
+		"https://rejects.example.com/hook": {400},
+		"https://down.example.com/hook":    {500, 500, 500, 500},
+	}}
+	svc := newTestWebhookService(repo, sender)
+
+	// Failed subscribers do not fail the message.
+	if err := svc.ProcessEvent(context.Background(), entities.WebhookEventPostDeleted, "msg-1", []byte(`{}`)); err != nil {
+		t.Fatalf("ProcessEvent: %v", err)
+	}
+
+	attempts := map[string]int{}
+	for _, delivery := range repo.deliveries {
+		if delivery.Success || delivery.Error == "" {
+			t.Fatalf("expected a recorded failure, got %+v", delivery)
+		}
+		attempts[delivery.SubscriptionID] = delivery.Attempts
+	}
+	if attempts["rejects"] != 1 {
+		t.Errorf("expected a 400 not to be retried, got %d attempts", attempts["rejects"])
+	}
+	if attempts["down"] != 3 {
+		t.Errorf("expected a 500 to be retried up to 3 attempts, got %d", attempts["down"])
+	}
+}
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	repo := newMockWebhookRepo()
+	svc := newTestWebhookService(repo, &scriptedSender{})
+	ctx := context.Background()
+
+	invalid := []*dto.CreateWebhookRequest{
+		{URL: "ftp://example.com", Events: []string{entities.WebhookEventPostCreated}},
+		{URL: "/relative", Events: []string{entities.WebhookEventPostCreated}},
+		{URL: "https://example.com", Events: []string{"user.created"}},
+	}
+	for _, req := range invalid {
+		if _, err := svc.CreateSubscription(ctx, req); err != appErrors.ErrInvalidWebhook {
+			t.Errorf("%+v: expected ErrInvalidWebhook, got %v", req, err)
+		}
+	}
+
+	created, err := svc.CreateSubscription(ctx, &dto.CreateWebhookRequest{
+		URL:    "https://example.com/hook",
+		Events: []string{entities.WebhookEventPostCreated, entities.WebhookEventPostCreated},
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription: %v", err)
+	}
+	if len(created.Secret) != 64 || !created.Active || len(created.Events) != 1 {
+		t.Fatalf("expected an active subscription with a generated secret and deduplicated events, got %+v", created)
+	}
+
+	fetched, err := svc.GetSubscription(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetSubscription: %v", err)
+	}
+	if fetched.Secret != "" {
+		t.Fatal("expected the secret to be returned on create only")
+	}
+	if _, err := svc.GetSubscription(ctx, "missing"); err != appErrors.ErrWebhookNotFound {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// DeadLetter is a message parked in a dead-letter queue after its consumer
+// gave up on it.
+type DeadLetter struct {
+	Position     int // zero-based, from the head of the queue
+	MessageID    string
+	RoutingKey   string // the routing key it was originally published with
+	Queue        string // the queue it was dead-lettered from
+	Reason       string // "rejected", "expired", ...
+	DeathCount   int64  // times it was dead-lettered, each after the consumer's retries
+	ReplayCount  int64  // times it was replayed from the dead-letter queue
+	FirstDeathAt *time.Time
+	ContentType  string
+	Headers      map[string]interface{}
+	Body         []byte
+}
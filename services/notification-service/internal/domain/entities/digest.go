@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// DigestMode is how a user's notifications are delivered: pushed and emailed
+// one by one, or coalesced into one summary per hour or day.
+type DigestMode string
+
+const (
+	DigestModeImmediate DigestMode = "immediate"
+	DigestModeHourly    DigestMode = "hourly"
+	DigestModeDaily     DigestMode = "daily"
+)
+
+// DigestModes lists every digest mode.
+var DigestModes = []DigestMode{DigestModeImmediate, DigestModeHourly, DigestModeDaily}
+
+// IsValid reports whether m is a known digest mode.
+func (m DigestMode) IsValid() bool {
+	for _, known := range DigestModes {
+		if m == known {
+			return true
+		}
+	}
+	return false
+}
+
+// DigestSubscription is a user in a digest mode and the end of the last
+// window summarised for them; the next digest covers what arrived since.
+type DigestSubscription struct {
+	UserID string
+	Mode   DigestMode
+	Since  time.Time
+}
@@ -0,0 +1,8 @@
+package entities
+
+// EmailMessage is a rendered plain-text email ready to hand to a sender.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
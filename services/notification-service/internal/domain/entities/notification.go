@@ -9,11 +9,72 @@ import (
 type NotificationType string
 
 const (
+	// NotificationTypePostCreated tells followers that an author they follow
+	// published a post.
 	NotificationTypePostCreated NotificationType = "post_created"
-	NotificationTypePostUpdated NotificationType = "post_updated"
-	NotificationTypePostDeleted NotificationType = "post_deleted"
+	// NotificationTypeOwnPostCreated confirms a new post to its author. It is
+	// opt-in.
+	NotificationTypeOwnPostCreated NotificationType = "own_post_created"
+	NotificationTypePostUpdated    NotificationType = "post_updated"
+	NotificationTypePostDeleted    NotificationType = "post_deleted"
+	NotificationTypeUserFollowed   NotificationType = "user_followed"
+	NotificationTypeCommentAdded   NotificationType = "comment_added"
+	NotificationTypeSystemAlert    NotificationType = "system_alert"
+	// NotificationTypeDigest summarises the notifications a user in a digest
+	// mode received during one window.
+	NotificationTypeDigest NotificationType = "digest"
 )
 
+// NotificationTypes lists every notification type, in display order.
+var NotificationTypes = []NotificationType{
+	NotificationTypePostCreated,
+	NotificationTypeOwnPostCreated,
+	NotificationTypePostUpdated,
+	NotificationTypePostDeleted,
+	NotificationTypeUserFollowed,
+	NotificationTypeCommentAdded,
+	NotificationTypeSystemAlert,
+	NotificationTypeDigest,
+}
+
+// IsValid reports whether t is a known notification type.
+func (t NotificationType) IsValid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationPriority ranks how urgently a notification needs the user's
+// attention. High-priority notifications are pushed and emailed right away
+// even to users in a digest mode.
+type NotificationPriority string
+
+const (
+	PriorityLow    NotificationPriority = "low"
+	PriorityNormal NotificationPriority = "normal"
+	PriorityHigh   NotificationPriority = "high"
+)
+
+// NotificationPriorities lists every priority, lowest first.
+var NotificationPriorities = []NotificationPriority{PriorityLow, PriorityNormal, PriorityHigh}
+
+// IsValid reports whether p is a known priority.
+func (p NotificationPriority) IsValid() bool {
+	for _, known := range NotificationPriorities {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// DataKeySourceMessageID is the Data key holding the broker message ID of the
+// event a notification was derived from.
+const DataKeySourceMessageID = "source_message_id"
+
 type Notification struct {
 	ID        string                 `json:"id" db:"id"`
 	UserID    string                 `json:"user_id" db:"user_id"`
@@ -21,9 +82,20 @@ type Notification struct {
 	Title     string                 `json:"title" db:"title"`
 	Message   string                 `json:"message" db:"message"`
 	Data      map[string]interface{} `json:"data,omitempty" db:"data"`
+	Priority  NotificationPriority   `json:"priority" db:"priority"`
 	Read      bool                   `json:"read" db:"read"`
 	CreatedAt time.Time              `json:"created_at" db:"created_at"`
 	ReadAt    *time.Time             `json:"read_at,omitempty" db:"read_at"`
+	// ArchivedAt is set while the notification is archived: kept, but out of
+	// the inbox and the unread count.
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+}
+
+// NotificationCursor is a keyset position in the newest-first notification
+// ordering; rows strictly after it are returned on the next page.
+type NotificationCursor struct {
+	CreatedAt time.Time
+	ID        string
 }
 
 type PostCreatedEvent struct {
@@ -80,12 +152,40 @@ func (n *Notification) IsValid() error {
 		return fmt.Errorf("message must be less than 1000 characters")
 	}
 
+	if !n.Priority.IsValid() {
+		return fmt.Errorf("invalid priority: %s", n.Priority)
+	}
+
 	return nil
 }
 
+// Sanitize trims the text fields and defaults an unset priority to normal.
 func (n *Notification) Sanitize() {
 	n.Title = strings.TrimSpace(n.Title)
 	n.Message = strings.TrimSpace(n.Message)
+	if n.Priority == "" {
+		n.Priority = PriorityNormal
+	}
+}
+
+// SetSourceMessageID records the originating broker message on the
+// notification so it can be traced back to the event that produced it.
+func (n *Notification) SetSourceMessageID(messageID string) {
+	if messageID == "" {
+		return
+	}
+	if n.Data == nil {
+		n.Data = make(map[string]interface{})
+	}
+	n.Data[DataKeySourceMessageID] = messageID
+}
+
+// SourceMessageID returns the originating broker message ID, if any.
+func (n *Notification) SourceMessageID() string {
+	if id, ok := n.Data[DataKeySourceMessageID].(string); ok {
+		return id
+	}
+	return ""
 }
 
 func (n *Notification) MarkAsRead() {
@@ -105,10 +205,29 @@ func (e *PostCreatedEvent) ToNotification(userID string) *Notification {
 	}
 
 	return &Notification{
-		UserID:  userID,
-		Type:    NotificationTypePostCreated,
-		Title:   title,
-		Message: message,
+		UserID:   userID,
+		Type:     NotificationTypeOwnPostCreated,
+		Priority: PriorityLow,
+		Title:    title,
+		Message:  message,
+		Data: map[string]interface{}{
+			"post_id":   e.PostID,
+			"post_slug": e.Slug,
+			"author_id": e.UserID,
+		},
+		Read: false,
+	}
+}
+
+// ToFollowerNotification announces the published post to one of the author's
+// followers. post_slug lets the client deep-link to it.
+func (e *PostCreatedEvent) ToFollowerNotification(followerID string) *Notification {
+	return &Notification{
+		UserID:   followerID,
+		Type:     NotificationTypePostCreated,
+		Priority: PriorityNormal,
+		Title:    "New Post From Someone You Follow",
+		Message:  fmt.Sprintf("'%s' has just been published", e.Title),
 		Data: map[string]interface{}{
 			"post_id":   e.PostID,
 			"post_slug": e.Slug,
@@ -124,10 +243,11 @@ func (e *PostUpdatedEvent) ToNotification(userID string) *Notification {
 	message := fmt.Sprintf("A new post %s was updated", e.Title)
 
 	return &Notification{
-		UserID:  userID,
-		Type:    NotificationTypePostUpdated,
-		Title:   title,
-		Message: message,
+		UserID:   userID,
+		Type:     NotificationTypePostUpdated,
+		Priority: PriorityLow,
+		Title:    title,
+		Message:  message,
 		Data: map[string]interface{}{
 			"post_id":   e.PostID,
 			"post_slug": e.Slug,
@@ -142,10 +262,11 @@ func (e *PostDeletedEvent) ToNotification(userID string) *Notification {
 	message := fmt.Sprintf("The post %s was deleted", e.Title)
 
 	return &Notification{
-		UserID:  userID,
-		Type:    NotificationTypePostDeleted,
-		Title:   title,
-		Message: message,
+		UserID:   userID,
+		Type:     NotificationTypePostDeleted,
+		Priority: PriorityNormal,
+		Title:    title,
+		Message:  message,
 		Data: map[string]interface{}{
 			"post_id":   e.PostID,
 			"author_id": e.UserID,
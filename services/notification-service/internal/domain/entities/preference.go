@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// NotificationChannel is a delivery route for notifications.
+type NotificationChannel string
+
+const (
+	// ChannelInApp is the notification feed and live stream.
+	ChannelInApp NotificationChannel = "in_app"
+	// ChannelEmail sends a templated email to the user's address.
+	ChannelEmail NotificationChannel = "email"
+)
+
+// NotificationChannels lists every delivery channel.
+var NotificationChannels = []NotificationChannel{ChannelInApp, ChannelEmail}
+
+// IsValid reports whether c is a known channel.
+func (c NotificationChannel) IsValid() bool {
+	for _, known := range NotificationChannels {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultEnabled reports whether a user who has not configured t on c receives
+// it. Email and the author's own post confirmations are opt-in; everything
+// else is opt-out.
+func DefaultEnabled(t NotificationType, c NotificationChannel) bool {
+	return c != ChannelEmail && t != NotificationTypeOwnPostCreated
+}
+
+// NotificationPreference records whether a user receives one notification
+// type on one channel. A missing preference means the channel default.
+type NotificationPreference struct {
+	UserID    string              `json:"user_id" db:"user_id"`
+	Type      NotificationType    `json:"type" db:"type"`
+	Channel   NotificationChannel `json:"channel" db:"channel"`
+	Enabled   bool                `json:"enabled" db:"enabled"`
+	UpdatedAt time.Time           `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,84 @@
+package entities
+
+import (
+	"net/url"
+	"time"
+)
+
+// Webhook events a subscription can ask for. They are the routing keys of the
+// post events the webhook consumer receives.
+const (
+	WebhookEventPostCreated = "post.created"
+	WebhookEventPostUpdated = "post.updated"
+	WebhookEventPostDeleted = "post.deleted"
+)
+
+// WebhookEvents lists every event a subscription can ask for.
+var WebhookEvents = []string{WebhookEventPostCreated, WebhookEventPostUpdated, WebhookEventPostDeleted}
+
+func IsValidWebhookEvent(event string) bool {
+	for _, known := range WebhookEvents {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription is an external endpoint that receives the post events
+// in Events. Deliveries are signed with Secret.
+type WebhookSubscription struct {
+	ID        string
+	URL       string
+	Secret    string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Wants reports whether the subscription receives event.
+func (s *WebhookSubscription) Wants(event string) bool {
+	if !s.Active {
+		return false
+	}
+	for _, wanted := range s.Events {
+		if wanted == event {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidWebhookURL reports whether rawURL is an absolute http or https URL.
+func IsValidWebhookURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// WebhookDelivery records the outcome of sending one event to one
+// subscription, after all of its attempts.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	Event          string
+	MessageID      string // broker message the event arrived in
+	Attempts       int
+	StatusCode     int // of the last attempt; 0 when no response was received
+	Success        bool
+	Error          string
+	CreatedAt      time.Time
+}
+
+// WebhookRequest is one event ready to POST to a subscription. Body is sent
+// as is and signed with Secret.
+type WebhookRequest struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string // the same on every attempt, so receivers can drop repeats
+	Body       []byte
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"notification-service/internal/domain/entities"
+)
+
+// DeadLetterQueue inspects and drains one dead-letter queue.
+type DeadLetterQueue interface {
+	// List returns up to limit messages after skipping offset, and the
+	// number of messages in the queue. The messages stay in the queue.
+	List(ctx context.Context, offset, limit int) ([]*entities.DeadLetter, int, error)
+	// Replay publishes the messages with the given IDs back to the events
+	// exchange under their original routing key and message ID, then removes
+	// them from the queue. It returns the IDs it found and replayed.
+	Replay(ctx context.Context, messageIDs []string) ([]string, error)
+	// Purge removes the messages with the given IDs from the queue without
+	// replaying them and returns the IDs it found.
+	Purge(ctx context.Context, messageIDs []string) ([]string, error)
+}
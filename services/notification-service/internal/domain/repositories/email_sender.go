@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"notification-service/internal/domain/entities"
+)
+
+// EmailSender delivers a rendered email.
+type EmailSender interface {
+	Send(ctx context.Context, message *entities.EmailMessage) error
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import "context"
+
+// FollowerDirectory lists who follows a user. The follow graph is owned by
+// user-service.
+type FollowerDirectory interface {
+	// GetFollowerIDs returns one page of userID's follower IDs and the cursor
+	// of the next page, or "" on the last page.
+	GetFollowerIDs(ctx context.Context, userID string, limit int, cursor string) ([]string, string, error)
+}
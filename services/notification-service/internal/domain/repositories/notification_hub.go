@@ -0,0 +1,14 @@
+package repositories
+
+import "notification-service/internal/domain/entities"
+
+// NotificationHub fans newly created notifications out to the recipient's
+// live connections.
+type NotificationHub interface {
+	// Publish delivers notification to every current subscriber of its user.
+	// It never blocks on slow subscribers.
+	Publish(notification *entities.Notification)
+	// Subscribe returns a channel of the user's new notifications and a
+	// function that unsubscribes and closes it.
+	Subscribe(userID string) (<-chan *entities.Notification, func())
+}
@@ -3,17 +3,76 @@ package repositories
 import (
 	"context"
 	"notification-service/internal/domain/entities"
+	"time"
 )
 
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *entities.Notification) error
+	// CreateBatchForEvent records messageID as processed and stores
+	// notifications with a single insert, in one transaction. It returns false,
+	// storing nothing, when messageID was already processed. An empty
+	// messageID skips the deduplication. With no notifications it only
+	// records messageID.
+	CreateBatchForEvent(ctx context.Context, messageID string, notifications []*entities.Notification) (bool, error)
+	// CreateBatchForRecipients is CreateBatchForEvent deduplicated per
+	// recipient: it records messageID once for each notification's user and
+	// stores only the notifications of users not recorded before. It returns
+	// the notifications it stored.
+	CreateBatchForRecipients(ctx context.Context, messageID string, notifications []*entities.Notification) ([]*entities.Notification, error)
+	// IsEventProcessed reports whether a notification was already stored for
+	// the broker message messageID.
+	IsEventProcessed(ctx context.Context, messageID string) (bool, error)
 	GetByID(ctx context.Context, id string) (*entities.Notification, error)
-	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*entities.Notification, error)
-	GetUnreadByUserID(ctx context.Context, userID string, limit, offset int) ([]*entities.Notification, error)
+	// GetByUserID and GetUnreadByUserID return the user's notifications that
+	// are not archived, newest first. An empty notificationType or priority
+	// matches every type or priority.
+	GetByUserID(ctx context.Context, userID string, notificationType entities.NotificationType, priority entities.NotificationPriority, limit, offset int) ([]*entities.Notification, error)
+	GetUnreadByUserID(ctx context.Context, userID string, notificationType entities.NotificationType, priority entities.NotificationPriority, limit, offset int) ([]*entities.Notification, error)
 	MarkAsRead(ctx context.Context, id string, userID string) error
+	// MarkManyAsRead marks the user's unread notifications among ids as read in
+	// a single statement. IDs owned by other users are ignored.
+	MarkManyAsRead(ctx context.Context, userID string, ids []string) (int64, error)
+	// MarkAsUnread clears read and read_at on the user's read notifications
+	// among ids. IDs owned by other users are ignored.
+	MarkAsUnread(ctx context.Context, ids []string, userID string) (int64, error)
 	MakeAllAsRead(ctx context.Context, userID string) error
+	// ArchiveNotification and UnarchiveNotification move the user's
+	// notification out of and back into the inbox. They return false when the
+	// user has no notification with that ID.
+	ArchiveNotification(ctx context.Context, id string, userID string) (bool, error)
+	UnarchiveNotification(ctx context.Context, id string, userID string) (bool, error)
 	Delete(ctx context.Context, id string, userID string) error
+	// GetUnreadCount counts the user's unread notifications that are not
+	// archived.
 	GetUnreadCount(ctx context.Context, userID string) (int64, error)
-	List(ctx context.Context, limit, offset int) ([]*entities.Notification, error)
-	DeleteOld(ctx context.Context, olderThan int) error
+	// CountByUserID counts the notifications ListByUserID pages through. An
+	// empty typeFilter or priorityFilter matches everything; archived
+	// notifications are counted only when includeArchived.
+	CountByUserID(ctx context.Context, userID string, unreadOnly, includeArchived bool, typeFilter entities.NotificationType, priorityFilter entities.NotificationPriority) (int64, error)
+	// CountByType counts the user's notifications that are not archived (only
+	// unread ones when unreadOnly) per type. Types without notifications are
+	// left out.
+	CountByType(ctx context.Context, userID string, unreadOnly bool) (map[entities.NotificationType]int64, error)
+	// ListByUserID returns one page of the user's notifications, newest first,
+	// together with the number of notifications matching the filters, in a
+	// single query where it can. Archived notifications are left out unless
+	// includeArchived.
+	ListByUserID(ctx context.Context, userID string, unreadOnly, includeArchived bool, typeFilter entities.NotificationType, priorityFilter entities.NotificationPriority, limit, offset int) ([]*entities.Notification, int64, error)
+	// List returns notifications across all users, newest first. A nil cursor
+	// starts from the most recent notification.
+	List(ctx context.Context, after *entities.NotificationCursor, limit int) ([]*entities.Notification, error)
+	// GetUndigested returns up to limit of the user's notifications created
+	// from since through until that no digest has covered yet, oldest first.
+	// Digests themselves, high-priority notifications, which were delivered
+	// when created, and archived notifications are never returned.
+	GetUndigested(ctx context.Context, userID string, since, until time.Time, limit int) ([]*entities.Notification, error)
+	// CompleteDigest stores digest, marks sourceIDs as digested and moves the
+	// user's digest window from since to windowEnd, in one transaction. digest
+	// is nil when the window was empty. It returns false, changing nothing,
+	// when the window no longer starts at since because another run already
+	// sent it.
+	CompleteDigest(ctx context.Context, userID string, since, windowEnd time.Time, digest *entities.Notification, sourceIDs []string) (bool, error)
+	// DeleteOld removes notifications and processed event records older than
+	// olderThan days and returns how many notifications were deleted.
+	DeleteOld(ctx context.Context, olderThan int) (int64, error)
 }
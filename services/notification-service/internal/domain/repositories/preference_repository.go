@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"notification-service/internal/domain/entities"
+	"time"
+)
+
+type PreferenceRepository interface {
+	// GetByUserID returns the user's stored preferences; types and channels
+	// without a row are absent.
+	GetByUserID(ctx context.Context, userID string) ([]*entities.NotificationPreference, error)
+	// Upsert stores preferences in one transaction, replacing existing rows
+	// for the same user, type and channel.
+	Upsert(ctx context.Context, preferences []*entities.NotificationPreference) error
+	// IsEnabled reports whether userID receives notificationType on channel,
+	// defaulting to the channel default when no preference is stored.
+	IsEnabled(ctx context.Context, userID string, notificationType entities.NotificationType, channel entities.NotificationChannel) (bool, error)
+	// FilterEnabled returns the subset of userIDs that receive notificationType
+	// on channel, applying the default to users without a stored preference.
+	FilterEnabled(ctx context.Context, userIDs []string, notificationType entities.NotificationType, channel entities.NotificationChannel) ([]string, error)
+	// GetEmailAddress returns the address email notifications go to, or ""
+	// when none is known.
+	GetEmailAddress(ctx context.Context, userID string) (string, error)
+	// SetEmailAddress records the address email notifications go to.
+	SetEmailAddress(ctx context.Context, userID, email string) error
+	// GetDigestMode returns the user's digest mode, immediate when none is
+	// stored.
+	GetDigestMode(ctx context.Context, userID string) (entities.DigestMode, error)
+	// SetDigestMode stores the user's digest mode. Switching from immediate
+	// starts the first window now, so earlier notifications are not
+	// summarised.
+	SetDigestMode(ctx context.Context, userID string, mode entities.DigestMode) error
+	// FilterDigest returns the subset of userIDs in an hourly or daily digest
+	// mode.
+	FilterDigest(ctx context.Context, userIDs []string) (map[string]bool, error)
+	// ListDueDigests returns up to limit users in mode whose last window ended
+	// at or before dueBefore, longest waiting first.
+	ListDueDigests(ctx context.Context, mode entities.DigestMode, dueBefore time.Time, limit int) ([]*entities.DigestSubscription, error)
+}
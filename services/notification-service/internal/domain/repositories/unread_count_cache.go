@@ -0,0 +1,11 @@
+package repositories
+
+import "context"
+
+// UnreadCountCache holds short-lived per-user unread counts so that polling
+// clients do not hit the database on every request.
+type UnreadCountCache interface {
+	Get(ctx context.Context, userID string) (int64, bool)
+	Set(ctx context.Context, userID string, count int64)
+	Invalidate(ctx context.Context, userID string)
+}
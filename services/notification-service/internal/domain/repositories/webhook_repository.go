@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"notification-service/internal/domain/entities"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, subscription *entities.WebhookSubscription) error
+	// GetByID returns nil and no error when the subscription does not exist.
+	GetByID(ctx context.Context, id string) (*entities.WebhookSubscription, error)
+	// List returns every subscription, oldest first.
+	List(ctx context.Context) ([]*entities.WebhookSubscription, error)
+	// Update stores the URL, secret, events and active flag of subscription.
+	// It returns false when the subscription does not exist.
+	Update(ctx context.Context, subscription *entities.WebhookSubscription) (bool, error)
+	// Delete removes the subscription and its delivery log. It returns false
+	// when the subscription does not exist.
+	Delete(ctx context.Context, id string) (bool, error)
+	// ListActiveForEvent returns the active subscriptions that receive event.
+	ListActiveForEvent(ctx context.Context, event string) ([]*entities.WebhookSubscription, error)
+	RecordDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+	// ListDeliveries returns up to limit of the subscription's deliveries,
+	// newest first.
+	ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*entities.WebhookDelivery, error)
+}
+
+// WebhookSender makes one delivery attempt of a signed webhook request and
+// returns the response status code, or 0 when no response was received.
+type WebhookSender interface {
+	Send(ctx context.Context, request *entities.WebhookRequest) (int, error)
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type unreadCountEntry struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// UnreadCountCache is an in-process TTL cache for per-user unread counts.
+// A non-positive TTL disables caching entirely.
+type UnreadCountCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]unreadCountEntry
+	now     func() time.Time
+}
+
+func NewUnreadCountCache(ttl time.Duration) *UnreadCountCache {
+	return &UnreadCountCache{
+		ttl:     ttl,
+		entries: make(map[string]unreadCountEntry),
+		now:     time.Now,
+	}
+}
+
+func (c *UnreadCountCache) Get(ctx context.Context, userID string) (int64, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[userID]
+	c.mu.RUnlock()
+
+	if !ok {
+		return 0, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		c.mu.Lock()
+		if current, ok := c.entries[userID]; ok && current == entry {
+			delete(c.entries, userID)
+		}
+		c.mu.Unlock()
+		return 0, false
+	}
+
+	return entry.count, true
+}
+
+func (c *UnreadCountCache) Set(ctx context.Context, userID string, count int64) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.entries[userID] = unreadCountEntry{count: count, expiresAt: now.Add(c.ttl)}
+
+	// Opportunistically drop expired entries so the map does not grow with
+	// every user that has ever polled.
+	if len(c.entries)%256 == 0 {
+		for id, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+	}
+}
+
+func (c *UnreadCountCache) Invalidate(ctx context.Context, userID string) {
+	c.mu.Lock()
+	delete(c.entries, userID)
+	c.mu.Unlock()
+}
@@ -1,26 +1,45 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	_ "github.com/lib/pq"
-	"notification-service/internal/config"
+	"fmt"
 	"time"
+
+	"notification-service/internal/config"
+
+	"github.com/XSAM/otelsql"
+	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// NewConntection opens the pool, applies the configured limits and pings the
+// database, giving up after cfg.ConnectTimeout seconds.
 func NewConntection(cfg config.DatabaseConfig) (*sql.DB, error) {
-	db, err := sql.Open("postgres", cfg.URL)
+	// Every query runs in a child span of the caller's trace.
+	db, err := otelsql.Open("postgres", cfg.URL,
+		otelsql.WithAttributes(attribute.String("db.system.name", "postgresql")),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{DisableErrSkip: true, OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+	configurePool(db, cfg)
 
-	if err := db.Ping(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ConnectTimeout)*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
 		db.Close()
-		return nil, err
+		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
 	return db, nil
 }
+
+func configurePool(db *sql.DB, cfg config.DatabaseConfig) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+	db.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Minute)
+}
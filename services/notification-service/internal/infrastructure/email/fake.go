@@ -0,0 +1,33 @@
+package email
+
+import (
+	"context"
+	"sync"
+
+	"notification-service/internal/domain/entities"
+)
+
+// FakeSender records messages instead of sending them. Set Err to simulate a
+// delivery failure.
+type FakeSender struct {
+	mu       sync.Mutex
+	messages []entities.EmailMessage
+	Err      error
+}
+
+func (f *FakeSender) Send(ctx context.Context, message *entities.EmailMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.messages = append(f.messages, *message)
+	return nil
+}
+
+// Messages returns a copy of every message sent so far.
+func (f *FakeSender) Messages() []entities.EmailMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]entities.EmailMessage(nil), f.messages...)
+}
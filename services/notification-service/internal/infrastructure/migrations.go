@@ -20,11 +20,82 @@ func RunMigrations(db *sql.DB) error {
 	CREATE INDEX IF NOT EXISTS idx_notifications_user_read ON notifications(user_id, read);
 	CREATE INDEX IF NOT EXISTS idx_notifications_type ON notifications(type);
 	CREATE INDEX IF NOT EXISTS idx_notifications_created_at ON notifications(created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_notifications_created_at_id ON notifications(created_at DESC, id DESC);
 	CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id, read, created_at DESC) WHERE read = false;
 
+	-- Broker message IDs already turned into notifications, so redeliveries are skipped
+	CREATE TABLE IF NOT EXISTS processed_events (
+		message_id VARCHAR(255) PRIMARY KEY,
+		processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events(processed_at);
+
 	-- Gin index for JSONB data field for fast queries on notification data
 	CREATE INDEX IF NOT EXISTS idx_notifications_data_gin ON notifications USING gin(data);
 
+	-- Settings per user, type and channel; a missing row means the channel default
+	CREATE TABLE IF NOT EXISTS notification_preferences (
+		user_id VARCHAR(255) NOT NULL,
+		type VARCHAR(50) NOT NULL,
+		channel VARCHAR(20) NOT NULL DEFAULT 'in_app',
+		enabled BOOLEAN NOT NULL DEFAULT true,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (user_id, type, channel)
+	);
+
+	-- Email address taken from the user's access token when they save preferences
+	CREATE TABLE IF NOT EXISTS notification_recipients (
+		user_id VARCHAR(255) PRIMARY KEY,
+		email VARCHAR(255) NOT NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Set once a notification has been coalesced into a digest, so it is not summarised twice
+	ALTER TABLE notifications ADD COLUMN IF NOT EXISTS digested_at TIMESTAMP NULL;
+	CREATE INDEX IF NOT EXISTS idx_notifications_undigested ON notifications(user_id, created_at) WHERE digested_at IS NULL;
+
+	-- low, normal or high; high bypasses digests
+	ALTER TABLE notifications ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'normal';
+
+	-- Delivery mode per user; a missing row means immediate. last_digest_at is the end of the last window summarised
+	CREATE TABLE IF NOT EXISTS notification_digest_settings (
+		user_id VARCHAR(255) PRIMARY KEY,
+		mode VARCHAR(20) NOT NULL,
+		last_digest_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_notification_digest_settings_due ON notification_digest_settings(mode, last_digest_at);
+
+	-- External endpoints that receive post events; events lists the routing keys they want
+	CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+		id VARCHAR(255) PRIMARY KEY,
+		url VARCHAR(2048) NOT NULL,
+		secret VARCHAR(255) NOT NULL,
+		events TEXT[] NOT NULL,
+		active BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_events ON webhook_subscriptions USING gin(events) WHERE active;
+
+	-- Outcome of each event sent to a subscription, after all of its attempts
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id VARCHAR(255) PRIMARY KEY,
+		subscription_id VARCHAR(255) NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+		event VARCHAR(50) NOT NULL,
+		message_id VARCHAR(255) NOT NULL DEFAULT '',
+		attempts INTEGER NOT NULL,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		success BOOLEAN NOT NULL,
+		error VARCHAR(1000) NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at DESC);
+
+	-- Set while a notification is archived; archived notifications leave the inbox and the unread count
+	ALTER TABLE notifications ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP NULL;
+	CREATE INDEX IF NOT EXISTS idx_notifications_inbox ON notifications(user_id, created_at DESC) WHERE archived_at IS NULL;
+
 	`
 
 	_, err := db.Exec(query)
@@ -5,8 +5,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"notification-service/internal/domain/entities"
+	"strings"
 	"time"
 )
 
@@ -19,43 +20,177 @@ func NewNotificationRepository(db *sql.DB) *NotificationRepository {
 }
 
 func (r *NotificationRepository) Create(ctx context.Context, notification *entities.Notification) error {
+	return r.insert(ctx, r.db, []*entities.Notification{notification})
+}
+
+func (r *NotificationRepository) CreateBatchForEvent(ctx context.Context, messageID string, notifications []*entities.Notification) (bool, error) {
+	if len(notifications) == 0 && messageID == "" {
+		return false, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if messageID != "" {
+		query := `
+			INSERT INTO processed_events (message_id, processed_at)
+			VALUES ($1, $2)
+			ON CONFLICT (message_id) DO NOTHING
+		`
+		result, err := tx.ExecContext(ctx, query, messageID, time.Now())
+		if err != nil {
+			return false, fmt.Errorf("failed to record processed event: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return false, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return false, nil
+		}
+	}
+
+	if len(notifications) > 0 {
+		if err := r.insert(ctx, tx, notifications); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit notifs: %w", err)
+	}
+	return true, nil
+}
+
+func (r *NotificationRepository) CreateBatchForRecipients(ctx context.Context, messageID string, notifications []*entities.Notification) ([]*entities.Notification, error) {
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+	if messageID == "" {
+		if err := r.insert(ctx, r.db, notifications); err != nil {
+			return nil, err
+		}
+		return notifications, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	keys := make([]string, len(notifications))
+	for i, notification := range notifications {
+		keys[i] = recipientEventKey(messageID, notification.UserID)
+	}
 	query := `
-		INSERT INTO notifications (id, user_id, type, title, message, data, read, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO processed_events (message_id, processed_at)
+		SELECT key, $2 FROM unnest($1::text[]) AS key
+		ON CONFLICT (message_id) DO NOTHING
+		RETURNING message_id
 	`
-
-	dataJSON, err := json.Marshal(notification.Data)
+	rows, err := tx.QueryContext(ctx, query, pq.Array(keys), time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to marshal notif data: %w", err)
+		return nil, fmt.Errorf("failed to record processed recipients: %w", err)
+	}
+	recorded := make(map[string]bool, len(keys))
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan processed recipient: %w", err)
+		}
+		recorded[key] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to record processed recipients: %w", err)
+	}
+
+	stored := make([]*entities.Notification, 0, len(recorded))
+	for i, notification := range notifications {
+		if recorded[keys[i]] {
+			stored = append(stored, notification)
+		}
+	}
+	if len(stored) == 0 {
+		return nil, nil
+	}
+	if err := r.insert(ctx, tx, stored); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit notifs: %w", err)
 	}
+	return stored, nil
+}
+
+// recipientEventKey is the processed-event key of one recipient of messageID.
+func recipientEventKey(messageID, userID string) string {
+	return messageID + "#" + userID
+}
+
+func (r *NotificationRepository) IsEventProcessed(ctx context.Context, messageID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM processed_events WHERE message_id = $1)`
+
+	var processed bool
+	if err := r.db.QueryRowContext(ctx, query, messageID).Scan(&processed); err != nil {
+		return false, fmt.Errorf("failed to check processed event: %w", err)
+	}
+	return processed, nil
+}
 
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insert stores notifications with one multi-row INSERT.
+func (r *NotificationRepository) insert(ctx context.Context, db execer, notifications []*entities.Notification) error {
+	const columns = 9
 	now := time.Now()
-	_, err = r.db.ExecContext(
-		ctx, query, notification.ID, notification.UserID, notification.Type,
-		notification.Title, notification.Message, dataJSON, notification.Read, now)
+	values := make([]string, 0, len(notifications))
+	args := make([]any, 0, len(notifications)*columns)
+	for i, notification := range notifications {
+		dataJSON, err := json.Marshal(notification.Data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal notif data: %w", err)
+		}
+		n := i * columns
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9))
+		args = append(args, notification.ID, notification.UserID, notification.Type,
+			notification.Title, notification.Message, dataJSON, notification.Priority, notification.Read, now)
+	}
 
-	if err != nil {
+	query := `
+		INSERT INTO notifications (id, user_id, type, title, message, data, priority, read, created_at)
+		VALUES ` + strings.Join(values, ", ")
+
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
 		return fmt.Errorf("failed to create notif: %w", err)
 	}
 
-	notification.CreatedAt = now
+	for _, notification := range notifications {
+		notification.CreatedAt = now
+	}
 	return nil
-
 }
 
 func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*entities.Notification, error) {
 	query := `
-		SELECT id, user_id, type, title, message, data, read, created_at, read_at
+		SELECT id, user_id, type, title, message, data, priority, read, created_at, read_at, archived_at
 		FROM notifications 
 		WHERE id = $1
 	`
 
 	notification := &entities.Notification{}
 	var dataJSON []byte
-	var readAt sql.NullTime
+	var readAt, archivedAt sql.NullTime
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&notification.ID, &notification.UserID, &notification.Type, &notification.Title, &notification.Message, &dataJSON, &notification.Read, &notification.CreatedAt, &readAt)
+		&notification.ID, &notification.UserID, &notification.Type, &notification.Title, &notification.Message, &dataJSON, &notification.Priority, &notification.Read, &notification.CreatedAt, &readAt, &archivedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -73,19 +208,22 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*entit
 	if readAt.Valid {
 		notification.ReadAt = &readAt.Time
 	}
+	if archivedAt.Valid {
+		notification.ArchivedAt = &archivedAt.Time
+	}
 	return notification, nil
 }
 
-func (r *NotificationRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*entities.Notification, error) {
+func (r *NotificationRepository) GetByUserID(ctx context.Context, userID string, notificationType entities.NotificationType, priority entities.NotificationPriority, limit, offset int) ([]*entities.Notification, error) {
 	query := `
-		SELECT id, user_id, type, title, message, data, read, created_at, read_at
+		SELECT id, user_id, type, title, message, data, priority, read, created_at, read_at, archived_at
 		FROM notifications 
-		WHERE user_id = $1
+		WHERE user_id = $1 AND archived_at IS NULL AND ($4::text = '' OR type = $4::text) AND ($5::text = '' OR priority = $5::text)
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset, notificationType, priority)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user notif: %w", err)
 	}
@@ -96,16 +234,16 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID string,
 
 }
 
-func (r *NotificationRepository) GetUnreadByUserID(ctx context.Context, userID string, limit, offset int) ([]*entities.Notification, error) {
+func (r *NotificationRepository) GetUnreadByUserID(ctx context.Context, userID string, notificationType entities.NotificationType, priority entities.NotificationPriority, limit, offset int) ([]*entities.Notification, error) {
 	query := `
-	SELECT id, user_id, type, title, message, data, read, created_at, read_at
+	SELECT id, user_id, type, title, message, data, priority, read, created_at, read_at, archived_at
 	FROM notifications
-	WHERE user_id = $1 AND read = false
+	WHERE user_id = $1 AND read = false AND archived_at IS NULL AND ($4::text = '' OR type = $4::text) AND ($5::text = '' OR priority = $5::text)
 	ORDER BY created_at DESC
 	LIMIT $2 OFFSET $3
 		`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset, notificationType, priority)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get unread notif: %w", err)
 	}
@@ -119,6 +257,48 @@ func (r *NotificationRepository) GetUnreadByUserID(ctx context.Context, userID s
 	return r.scanNotifications(rows)
 }
 
+func (r *NotificationRepository) ListByUserID(ctx context.Context, userID string, unreadOnly, includeArchived bool, typeFilter entities.NotificationType, priorityFilter entities.NotificationPriority, limit, offset int) ([]*entities.Notification, int64, error) {
+	// COUNT(*) OVER() is computed before LIMIT/OFFSET, so every row carries the
+	// total of all matching notifications.
+	query := `
+		SELECT id, user_id, type, title, message, data, priority, read, created_at, read_at, archived_at, COUNT(*) OVER() AS total
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read = false) AND ($3::text = '' OR type = $3::text)
+			AND ($4::text = '' OR priority = $4::text) AND ($7 OR archived_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, typeFilter, priorityFilter, limit, offset, includeArchived)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list user notifs: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*entities.Notification
+	var total int64
+	for rows.Next() {
+		notification, err := scanNotification(rows, &total)
+		if err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	// A page past the end has no rows to carry the total; only then is a
+	// separate count needed.
+	if len(notifications) == 0 && offset > 0 {
+		total, err = r.CountByUserID(ctx, userID, unreadOnly, includeArchived, typeFilter, priorityFilter)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return notifications, total, nil
+}
+
 func (r *NotificationRepository) MarkAsRead(ctx context.Context, id, userID string) error {
 	query := `
 		UPDATE notifications 
@@ -144,6 +324,54 @@ func (r *NotificationRepository) MarkAsRead(ctx context.Context, id, userID stri
 	return nil
 }
 
+func (r *NotificationRepository) MarkManyAsRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		UPDATE notifications
+		SET read = true, read_at = $3
+		WHERE id = ANY($1) AND user_id = $2 AND read = false
+	`
+
+	result, err := r.db.ExecContext(ctx, query, pq.Array(ids), userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifs as read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *NotificationRepository) MarkAsUnread(ctx context.Context, ids []string, userID string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		UPDATE notifications
+		SET read = false, read_at = NULL
+		WHERE id = ANY($1) AND user_id = $2 AND read = true
+	`
+
+	result, err := r.db.ExecContext(ctx, query, pq.Array(ids), userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifs as unread: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
 func (r *NotificationRepository) MakeAllAsRead(ctx context.Context, userID string) error {
 	query := `
 	UPDATE notifications
@@ -159,6 +387,41 @@ func (r *NotificationRepository) MakeAllAsRead(ctx context.Context, userID strin
 	return err
 }
 
+// ArchiveNotification archives the user's notification, keeping the original
+// archived_at when it already was archived.
+func (r *NotificationRepository) ArchiveNotification(ctx context.Context, id, userID string) (bool, error) {
+	query := `
+		UPDATE notifications
+		SET archived_at = COALESCE(archived_at, $3)
+		WHERE id = $1 AND user_id = $2
+	`
+	return r.execOwned(ctx, "archive", query, id, userID, time.Now())
+}
+
+func (r *NotificationRepository) UnarchiveNotification(ctx context.Context, id, userID string) (bool, error) {
+	query := `
+		UPDATE notifications
+		SET archived_at = NULL
+		WHERE id = $1 AND user_id = $2
+	`
+	return r.execOwned(ctx, "unarchive", query, id, userID)
+}
+
+// execOwned runs an update of one of the user's notifications and reports
+// whether the user has a notification with that ID.
+func (r *NotificationRepository) execOwned(ctx context.Context, action, query string, args ...interface{}) (bool, error) {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to %s notif: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
 func (r *NotificationRepository) Delete(ctx context.Context, id, userID string) error {
 	query := `DELETE FROM notifications WHERE id = $1 AND user_id = $2`
 
@@ -181,7 +444,7 @@ func (r *NotificationRepository) Delete(ctx context.Context, id, userID string)
 }
 
 func (r *NotificationRepository) GetUnreadCount(ctx context.Context, userID string) (int64, error) {
-	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read = false`
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read = false AND archived_at IS NULL`
 
 	var count int64
 	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
@@ -191,15 +454,71 @@ func (r *NotificationRepository) GetUnreadCount(ctx context.Context, userID stri
 	return count, nil
 }
 
-func (r *NotificationRepository) List(ctx context.Context, limit, offset int) ([]*entities.Notification, error) {
+func (r *NotificationRepository) CountByUserID(ctx context.Context, userID string, unreadOnly, includeArchived bool, typeFilter entities.NotificationType, priorityFilter entities.NotificationPriority) (int64, error) {
 	query := `
-		SELECT id, user_id, type, title, message, data, read, created_at, read_at
-		FROM notifications 
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
+		SELECT COUNT(*)
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read = false) AND ($3::text = '' OR type = $3::text)
+			AND ($4::text = '' OR priority = $4::text) AND ($5 OR archived_at IS NULL)
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, userID, unreadOnly, typeFilter, priorityFilter, includeArchived).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count user notifs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *NotificationRepository) CountByType(ctx context.Context, userID string, unreadOnly bool) (map[entities.NotificationType]int64, error) {
+	query := `
+		SELECT type, COUNT(*)
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read = false) AND archived_at IS NULL
+		GROUP BY type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count user notifs by type: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[entities.NotificationType]int64)
+	for rows.Next() {
+		var notificationType entities.NotificationType
+		var count int64
+		if err := rows.Scan(&notificationType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan notif count: %w", err)
+		}
+		counts[notificationType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count user notifs by type: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *NotificationRepository) List(ctx context.Context, after *entities.NotificationCursor, limit int) ([]*entities.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, message, data, priority, read, created_at, read_at, archived_at
+		FROM notifications
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1
+	`
+	args := []interface{}{limit}
+
+	if after != nil {
+		query = `
+		SELECT id, user_id, type, title, message, data, priority, read, created_at, read_at, archived_at
+		FROM notifications
+		WHERE (created_at, id) < ($2, $3)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1
+	`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list notifs: %w", err)
 	}
@@ -209,26 +528,94 @@ func (r *NotificationRepository) List(ctx context.Context, limit, offset int) ([
 	return r.scanNotifications(rows)
 }
 
-func (r *NotificationRepository) DeleteOld(ctx context.Context, olderThan int) error {
+func (r *NotificationRepository) GetUndigested(ctx context.Context, userID string, since, until time.Time, limit int) ([]*entities.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, message, data, priority, read, created_at, read_at, archived_at
+		FROM notifications
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+			AND digested_at IS NULL AND archived_at IS NULL AND type <> $4 AND priority <> $6
+		ORDER BY created_at, id
+		LIMIT $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since, until, entities.NotificationTypeDigest, limit, entities.PriorityHigh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get undigested notifs: %w", err)
+	}
+
+	defer rows.Close()
+
+	return r.scanNotifications(rows)
+}
+
+func (r *NotificationRepository) CompleteDigest(ctx context.Context, userID string, since, windowEnd time.Time, digest *entities.Notification, sourceIDs []string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Claim the window first: a concurrent run that got here before us has
+	// already moved last_digest_at, so this one backs off.
+	query := `
+		UPDATE notification_digest_settings
+		SET last_digest_at = $3
+		WHERE user_id = $1 AND last_digest_at = $2
+	`
+	result, err := tx.ExecContext(ctx, query, userID, since, windowEnd)
+	if err != nil {
+		return false, fmt.Errorf("failed to advance notif digest window: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if digest != nil {
+		if err := r.insert(ctx, tx, []*entities.Notification{digest}); err != nil {
+			return false, err
+		}
+	}
+	if len(sourceIDs) > 0 {
+		query := `
+			UPDATE notifications
+			SET digested_at = $3
+			WHERE id = ANY($1) AND user_id = $2
+		`
+		if _, err := tx.ExecContext(ctx, query, pq.Array(sourceIDs), userID, time.Now()); err != nil {
+			return false, fmt.Errorf("failed to mark notifs as digested: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit notif digest: %w", err)
+	}
+	return true, nil
+}
+
+func (r *NotificationRepository) DeleteOld(ctx context.Context, olderThan int) (int64, error) {
 	query := `DELETE FROM notifications WHERE created_at < $1`
 
 	cutoffDate := time.Now().AddDate(0, 0, -olderThan)
 
 	result, err := r.db.ExecContext(ctx, query, cutoffDate)
 	if err != nil {
-		return fmt.Errorf("failed to delete old notifications: %w", err)
+		return 0, fmt.Errorf("failed to delete old notifications: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	if rowsAffected > 0 {
-		fmt.Printf("Deleted %d old notifications\n", rowsAffected)
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM processed_events WHERE processed_at < $1`, cutoffDate); err != nil {
+		return rowsAffected, fmt.Errorf("failed to delete old processed events: %w", err)
 	}
 
-	return nil
+	return rowsAffected, nil
 }
 
 // Helper
@@ -236,30 +623,10 @@ func (r *NotificationRepository) scanNotifications(rows *sql.Rows) ([]*entities.
 	var notifications []*entities.Notification
 
 	for rows.Next() {
-		notification := &entities.Notification{}
-		var dataJSON []byte
-		var readAt sql.NullTime
-
-		err := rows.Scan(
-			&notification.ID, &notification.UserID, &notification.Type,
-			&notification.Title, &notification.Message, &dataJSON,
-			&notification.Read, &notification.CreatedAt, &readAt,
-		)
+		notification, err := scanNotification(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan notification: %w", err)
-		}
-
-		// Parse JSON data
-		if len(dataJSON) > 0 {
-			if err := json.Unmarshal(dataJSON, &notification.Data); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal notification data: %w", err)
-			}
-		}
-
-		if readAt.Valid {
-			notification.ReadAt = &readAt.Time
+			return nil, err
 		}
-
 		notifications = append(notifications, notification)
 	}
 
@@ -269,3 +636,35 @@ func (r *NotificationRepository) scanNotifications(rows *sql.Rows) ([]*entities.
 
 	return notifications, nil
 }
+
+// scanNotification scans the notification columns of the current row,
+// followed by any extra columns the query selects.
+func scanNotification(rows *sql.Rows, extra ...interface{}) (*entities.Notification, error) {
+	notification := &entities.Notification{}
+	var dataJSON []byte
+	var readAt, archivedAt sql.NullTime
+
+	dest := []interface{}{
+		&notification.ID, &notification.UserID, &notification.Type,
+		&notification.Title, &notification.Message, &dataJSON,
+		&notification.Priority, &notification.Read, &notification.CreatedAt, &readAt, &archivedAt,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return nil, fmt.Errorf("failed to scan notification: %w", err)
+	}
+
+	// Parse JSON data
+	if len(dataJSON) > 0 {
+		if err := json.Unmarshal(dataJSON, &notification.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal notification data: %w", err)
+		}
+	}
+
+	if readAt.Valid {
+		notification.ReadAt = &readAt.Time
+	}
+	if archivedAt.Valid {
+		notification.ArchivedAt = &archivedAt.Time
+	}
+	return notification, nil
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+
+	"notification-service/internal/domain/entities"
+)
+
+// The list benchmarks need a scratch database. Run them with
+//
+//	NOTIFICATION_BENCH_DATABASE_URL=postgres://... go test -run '^$' -bench ListByUser ./internal/infrastructure/
+const benchDatabaseURLEnv = "NOTIFICATION_BENCH_DATABASE_URL"
+
+// openBenchRepo migrates the bench database and seeds count notifications for
+// a fresh user, removed again when the benchmark ends.
+func openBenchRepo(b *testing.B, count int) (*NotificationRepository, string) {
+	b.Helper()
+	url := os.Getenv(benchDatabaseURLEnv)
+	if url == "" {
+		b.Skipf("%s not set", benchDatabaseURLEnv)
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		b.Fatalf("open: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+	if err := RunMigrations(db); err != nil {
+		b.Fatalf("migrate: %v", err)
+	}
+
+	repo := NewNotificationRepository(db)
+	userID := uuid.NewString()
+	notifications := make([]*entities.Notification, count)
+	for i := range notifications {
+		notifications[i] = &entities.Notification{
+			ID:       uuid.NewString(),
+			UserID:   userID,
+			Type:     entities.NotificationTypePostCreated,
+			Title:    fmt.Sprintf("Post %d", i),
+			Message:  "A followed author published a post",
+			Priority: entities.PriorityNormal,
+		}
+	}
+	ctx := context.Background()
+	if _, err := repo.CreateBatchForEvent(ctx, "", notifications); err != nil {
+		b.Fatalf("seed: %v", err)
+	}
+	b.Cleanup(func() {
+		db.ExecContext(context.Background(), `DELETE FROM notifications WHERE user_id = $1`, userID)
+	})
+	return repo, userID
+}
+
+// BenchmarkListByUser_TwoQueries is the page-then-count approach that
+// ListByUserID replaced.
+func BenchmarkListByUser_TwoQueries(b *testing.B) {
+	repo, userID := openBenchRepo(b, 1000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetByUserID(ctx, userID, "", "", 20, 40); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.CountByUserID(ctx, userID, false, false, "", ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListByUser_WindowTotal(b *testing.B) {
+	repo, userID := openBenchRepo(b, 1000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := repo.ListByUserID(ctx, userID, false, false, "", "", 20, 40); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
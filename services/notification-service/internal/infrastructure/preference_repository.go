@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"notification-service/internal/domain/entities"
+	"time"
+)
+
+type PreferenceRepository struct {
+	db *sql.DB
+}
+
+func NewPreferenceRepository(db *sql.DB) *PreferenceRepository {
+	return &PreferenceRepository{db: db}
+}
+
+func (r *PreferenceRepository) GetByUserID(ctx context.Context, userID string) ([]*entities.NotificationPreference, error) {
+	query := `
+		SELECT user_id, type, channel, enabled, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+		ORDER BY type, channel
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notif preferences: %w", err)
+	}
+	defer rows.Close()
+
+	var preferences []*entities.NotificationPreference
+	for rows.Next() {
+		preference := &entities.NotificationPreference{}
+		if err := rows.Scan(&preference.UserID, &preference.Type, &preference.Channel, &preference.Enabled, &preference.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notif preference: %w", err)
+		}
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+func (r *PreferenceRepository) Upsert(ctx context.Context, preferences []*entities.NotificationPreference) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, type, channel, enabled, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, type, channel) DO UPDATE
+		SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, preference := range preferences {
+		if _, err := tx.ExecContext(ctx, query, preference.UserID, preference.Type, preference.Channel, preference.Enabled, now); err != nil {
+			return fmt.Errorf("failed to upsert notif preference: %w", err)
+		}
+		preference.UpdatedAt = now
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit notif preferences: %w", err)
+	}
+	return nil
+}
+
+func (r *PreferenceRepository) IsEnabled(ctx context.Context, userID string, notificationType entities.NotificationType, channel entities.NotificationChannel) (bool, error) {
+	query := `
+		SELECT enabled
+		FROM notification_preferences
+		WHERE user_id = $1 AND type = $2 AND channel = $3
+	`
+
+	var enabled bool
+	err := r.db.QueryRowContext(ctx, query, userID, notificationType, channel).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return entities.DefaultEnabled(notificationType, channel), nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get notif preference: %w", err)
+	}
+	return enabled, nil
+}
+
+func (r *PreferenceRepository) FilterEnabled(ctx context.Context, userIDs []string, notificationType entities.NotificationType, channel entities.NotificationChannel) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	// Only rows that differ from the default matter: opt-outs when the
+	// default is on, opt-ins when it is off.
+	defaultEnabled := entities.DefaultEnabled(notificationType, channel)
+	query := `
+		SELECT user_id
+		FROM notification_preferences
+		WHERE user_id = ANY($1) AND type = $2 AND channel = $3 AND enabled = $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs), notificationType, channel, !defaultEnabled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter notif preferences: %w", err)
+	}
+	defer rows.Close()
+
+	overridden := make(map[string]bool)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan notif preference: %w", err)
+		}
+		overridden[userID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	enabled := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if overridden[userID] != defaultEnabled {
+			enabled = append(enabled, userID)
+		}
+	}
+	return enabled, nil
+}
+
+func (r *PreferenceRepository) GetEmailAddress(ctx context.Context, userID string) (string, error) {
+	query := `
+		SELECT email
+		FROM notification_recipients
+		WHERE user_id = $1
+	`
+
+	var email string
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&email)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get notif email address: %w", err)
+	}
+	return email, nil
+}
+
+func (r *PreferenceRepository) SetEmailAddress(ctx context.Context, userID, email string) error {
+	query := `
+		INSERT INTO notification_recipients (user_id, email, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = EXCLUDED.email, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, email, time.Now()); err != nil {
+		return fmt.Errorf("failed to set notif email address: %w", err)
+	}
+	return nil
+}
+
+func (r *PreferenceRepository) GetDigestMode(ctx context.Context, userID string) (entities.DigestMode, error) {
+	query := `
+		SELECT mode
+		FROM notification_digest_settings
+		WHERE user_id = $1
+	`
+
+	var mode entities.DigestMode
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&mode)
+	if err == sql.ErrNoRows {
+		return entities.DigestModeImmediate, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get notif digest mode: %w", err)
+	}
+	return mode, nil
+}
+
+func (r *PreferenceRepository) SetDigestMode(ctx context.Context, userID string, mode entities.DigestMode) error {
+	// A user leaving immediate mode starts a fresh window; switching between
+	// hourly and daily keeps the pending one.
+	query := `
+		INSERT INTO notification_digest_settings (user_id, mode, last_digest_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET mode = EXCLUDED.mode,
+			last_digest_at = CASE
+				WHEN notification_digest_settings.mode = $4 THEN EXCLUDED.last_digest_at
+				ELSE notification_digest_settings.last_digest_at
+			END,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, mode, time.Now(), entities.DigestModeImmediate); err != nil {
+		return fmt.Errorf("failed to set notif digest mode: %w", err)
+	}
+	return nil
+}
+
+func (r *PreferenceRepository) FilterDigest(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT user_id
+		FROM notification_digest_settings
+		WHERE user_id = ANY($1) AND mode <> $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs), entities.DigestModeImmediate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter notif digest settings: %w", err)
+	}
+	defer rows.Close()
+
+	digest := make(map[string]bool)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan notif digest setting: %w", err)
+		}
+		digest[userID] = true
+	}
+	return digest, rows.Err()
+}
+
+func (r *PreferenceRepository) ListDueDigests(ctx context.Context, mode entities.DigestMode, dueBefore time.Time, limit int) ([]*entities.DigestSubscription, error) {
+	query := `
+		SELECT user_id, mode, last_digest_at
+		FROM notification_digest_settings
+		WHERE mode = $1 AND last_digest_at <= $2
+		ORDER BY last_digest_at
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, mode, dueBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due notif digests: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*entities.DigestSubscription
+	for rows.Next() {
+		subscription := &entities.DigestSubscription{}
+		if err := rows.Scan(&subscription.UserID, &subscription.Mode, &subscription.Since); err != nil {
+			return nil, fmt.Errorf("failed to scan notif digest setting: %w", err)
+		}
+		due = append(due, subscription)
+	}
+	return due, rows.Err()
+}
@@ -1,24 +1,42 @@
 package rabbitmq
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
+
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"notification-service/internal/config"
 	"notification-service/internal/domain/entities"
 	"notification-service/pkg/logger"
-	"time"
+	"notification-service/pkg/metrics"
+	"notification-service/pkg/tracing"
 )
 
+// consumerTag identifies the queue consumer so it can be cancelled on shutdown.
+const consumerTag = "notification-service"
+
 type Client struct {
 	config     config.RabbitMQConfig
 	connection *amqp.Connection
 	channel    *amqp.Channel
 	logger     *logger.Logger
 	done       chan error
+
+	// consuming is closed when the consume loop started by StartConsuming has
+	// returned and its last delivery has been settled.
+	consuming chan struct{}
 }
 
-type MessageHandler func(string, []byte) error
+// MessageHandler processes a delivery given its routing key, broker message ID
+// and body. ctx carries the trace the publisher started.
+type MessageHandler func(ctx context.Context, routingKey, messageID string, body []byte) error
 
 func NewClient(cfg config.RabbitMQConfig, logger *logger.Logger) *Client {
 	return &Client{
@@ -87,15 +105,17 @@ func (c *Client) Connect() error {
 		return fmt.Errorf("failed to declare queue: %w", err)
 	}
 
-	err = c.channel.QueueBind(
-		queue.Name,
-		c.config.RoutingKey,
-		c.config.ExchangeName,
-		false,
-		nil)
+	for _, routingKey := range c.config.RoutingKeys {
+		err = c.channel.QueueBind(
+			queue.Name,
+			routingKey,
+			c.config.ExchangeName,
+			false,
+			nil)
 
-	if err != nil {
-		return fmt.Errorf("failed to bind queue: %w", err)
+		if err != nil {
+			return fmt.Errorf("failed to bind queue to %s: %w", routingKey, err)
+		}
 	}
 
 	dlq, err := c.channel.QueueDeclare(
@@ -125,10 +145,14 @@ func (c *Client) Connect() error {
 	return nil
 }
 
-func (c *Client) StartConsuming(handler MessageHandler) error {
+// StartConsuming processes deliveries until ctx is cancelled. On cancellation
+// the consumer is cancelled at the broker, the delivery being processed is
+// finished and settled, and prefetched deliveries that were never handled are
+// requeued when the channel closes. Call Wait before Close.
+func (c *Client) StartConsuming(ctx context.Context, handler MessageHandler) error {
 	msgs, err := c.channel.Consume(
 		c.config.QueueName,
-		"",
+		consumerTag,
 		false,
 		false,
 		false,
@@ -140,9 +164,27 @@ func (c *Client) StartConsuming(handler MessageHandler) error {
 		return fmt.Errorf("failed to reg consumer: %w", err)
 	}
 
+	consuming := make(chan struct{})
+	c.consuming = consuming
 	go func() {
-		for d := range msgs {
-			c.processMessages(d, handler)
+		defer close(consuming)
+		for {
+			select {
+			case <-ctx.Done():
+				if err := c.channel.Cancel(consumerTag, false); err != nil {
+					c.logger.Warn("failed to cancel rabbit consumer", logger.Err(err))
+				}
+				return
+			case d, ok := <-msgs:
+				if !ok {
+					return
+				}
+				if ctx.Err() != nil {
+					_ = d.Nack(false, true)
+					continue
+				}
+				c.processMessages(ctx, d, handler)
+			}
 		}
 	}()
 
@@ -150,25 +192,74 @@ func (c *Client) StartConsuming(handler MessageHandler) error {
 	return nil
 }
 
-func (c *Client) processMessages(delivery amqp.Delivery, handler MessageHandler) {
+// Wait blocks until the consume loop has stopped after its context was
+// cancelled, or until ctx expires. Deliveries still unacknowledged when the
+// connection is closed afterwards are redelivered by the broker.
+func (c *Client) Wait(ctx context.Context) error {
+	if c.consuming == nil {
+		return nil
+	}
+	select {
+	case <-c.consuming:
+		c.logger.Info("rabbit consumer stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for in-flight messages: %w", ctx.Err())
+	}
+}
+
+func (c *Client) processMessages(ctx context.Context, delivery amqp.Delivery, handler MessageHandler) {
 	var err error
 	retries := 0
+	metrics.RecordMessage(delivery.RoutingKey, metrics.MessageConsumed)
+
+	// The handler context is not tied to ctx: shutdown waits for the delivery
+	// in progress instead of cancelling it.
+	msgCtx := otel.GetTextMapPropagator().Extract(context.Background(), headerCarrier(delivery.Headers))
+	msgCtx, span := tracing.Tracer().Start(msgCtx, delivery.RoutingKey+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", c.config.QueueName),
+			attribute.String("messaging.rabbitmq.destination.routing_key", delivery.RoutingKey),
+			attribute.String("messaging.message.id", delivery.MessageId),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
 
 	for retries <= c.config.MaxRetries {
-		err = handler(delivery.RoutingKey, delivery.Body)
+		err = handler(msgCtx, delivery.RoutingKey, delivery.MessageId, delivery.Body)
 		if err == nil {
 			if ackErr := delivery.Ack(false); ackErr != nil {
 				c.logger.Error(fmt.Sprintf("failed to ack message: %v", ackErr))
+				return
 			}
+			metrics.RecordMessage(delivery.RoutingKey, metrics.MessageAcked)
 			return
 		}
 
 		retries++
+		metrics.RecordMessage(delivery.RoutingKey, metrics.MessageRejected)
 		c.logger.Warn(fmt.Sprintf("message processing failed (attempt %d/%d): %v",
 			retries, c.config.MaxRetries+1, err))
 
 		if retries <= c.config.MaxRetries {
-			time.Sleep(time.Duration(retries) * time.Second)
+			select {
+			case <-time.After(time.Duration(retries) * time.Second):
+			case <-ctx.Done():
+				// Shutting down: hand the message back instead of waiting out
+				// the backoff, so another consumer can retry it.
+				if nackErr := delivery.Nack(false, true); nackErr != nil {
+					c.logger.Error(fmt.Sprintf("failed to requeue message: %v", nackErr))
+				}
+				return
+			}
 		}
 	}
 
@@ -176,7 +267,9 @@ func (c *Client) processMessages(delivery amqp.Delivery, handler MessageHandler)
 	c.logger.Error(fmt.Sprintf("message processing failed after %d atttmps, reject message", c.config.MaxRetries+1))
 	if nackErr := delivery.Nack(false, false); nackErr != nil {
 		c.logger.Error(fmt.Sprintf("failed to dead-letter message: %v", nackErr))
+		return
 	}
+	metrics.RecordMessage(delivery.RoutingKey, metrics.MessageDeadLettered)
 
 }
 
@@ -274,3 +367,23 @@ func (c *Client) Reconnect() error {
 	}
 	return fmt.Errorf("failed to reconnect after %d attempts", c.config.MaxRetries)
 }
+
+// headerCarrier adapts AMQP message headers to the OTel propagation API.
+type headerCarrier amqp.Table
+
+func (h headerCarrier) Get(key string) string {
+	value, _ := h[key].(string)
+	return value
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	return keys
+}
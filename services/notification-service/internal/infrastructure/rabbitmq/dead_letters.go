@@ -0,0 +1,253 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"notification-service/internal/domain/entities"
+)
+
+const (
+	// maxDeadLetterScan bounds how many messages one call takes off the
+	// dead-letter queue while looking for the requested ones.
+	maxDeadLetterScan = 1000
+	// replayCountHeader counts how often a message was replayed from the
+	// dead-letter queue.
+	replayCountHeader = "x-replay-count"
+	replayConfirmWait = 5 * time.Second
+)
+
+// DeadLetters reads and drains the dead-letter queue of a Client. RabbitMQ
+// cannot browse a queue, so messages are taken off unacknowledged and every
+// one not removed is requeued, in its original position, when the channel
+// closes at the end of the call.
+type DeadLetters struct {
+	client *Client
+}
+
+func NewDeadLetters(client *Client) *DeadLetters {
+	return &DeadLetters{client: client}
+}
+
+func (q *DeadLetters) List(ctx context.Context, offset, limit int) ([]*entities.DeadLetter, int, error) {
+	ch, err := q.channel()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer ch.Close()
+
+	queue, err := ch.QueueDeclarePassive(q.client.config.DLQName, true, false, false, false, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to inspect dead-letter queue: %w", err)
+	}
+
+	var letters []*entities.DeadLetter
+	for position := 0; position < offset+limit && position < maxDeadLetterScan; position++ {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		delivery, ok, err := ch.Get(queue.Name, false)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read dead-letter queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+		if position >= offset {
+			letters = append(letters, deadLetterFromDelivery(delivery, position))
+		}
+	}
+	return letters, queue.Messages, nil
+}
+
+func (q *DeadLetters) Replay(ctx context.Context, messageIDs []string) ([]string, error) {
+	return q.remove(ctx, messageIDs, true)
+}
+
+func (q *DeadLetters) Purge(ctx context.Context, messageIDs []string) ([]string, error) {
+	return q.remove(ctx, messageIDs, false)
+}
+
+// remove acks the messages with the given IDs, publishing each back to the
+// queue it died in first when replay is set. A message is only acked once the broker
+// confirmed its replay, so a failure leaves it in the dead-letter queue.
+func (q *DeadLetters) remove(ctx context.Context, messageIDs []string, replay bool) ([]string, error) {
+	wanted := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+
+	ch, err := q.channel()
+	if err != nil {
+		return nil, err
+	}
+	defer ch.Close()
+
+	var confirms chan amqp.Confirmation
+	var returns chan amqp.Return
+	if replay {
+		if err := ch.Confirm(false); err != nil {
+			return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+		}
+		confirms = ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+		returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	}
+
+	var removed []string
+	for scanned := 0; scanned < maxDeadLetterScan && len(wanted) > 0; scanned++ {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		delivery, ok, err := ch.Get(q.client.config.DLQName, false)
+		if err != nil {
+			return removed, fmt.Errorf("failed to read dead-letter queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+		if delivery.MessageId == "" || !wanted[delivery.MessageId] {
+			continue
+		}
+
+		if replay {
+			if err := q.republish(ctx, ch, confirms, returns, delivery); err != nil {
+				return removed, err
+			}
+		}
+		if err := delivery.Ack(false); err != nil {
+			return removed, fmt.Errorf("failed to remove dead-lettered message %s: %w", delivery.MessageId, err)
+		}
+		delete(wanted, delivery.MessageId)
+		removed = append(removed, delivery.MessageId)
+	}
+	return removed, nil
+}
+
+// republish sends delivery back to the queue it was dead-lettered from,
+// through the default exchange, keeping its message ID so a consumer that
+// deduplicates skips it if it did handle it before. Going through the events
+// exchange instead would deliver it again to every other queue bound there.
+func (q *DeadLetters) republish(ctx context.Context, ch *amqp.Channel, confirms chan amqp.Confirmation, returns chan amqp.Return, delivery amqp.Delivery) error {
+	letter := deadLetterFromDelivery(delivery, 0)
+	if letter.Queue == "" {
+		return fmt.Errorf("dead-lettered message %s has no source queue", delivery.MessageId)
+	}
+
+	headers := amqp.Table{}
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+	headers[replayCountHeader] = letter.ReplayCount + 1
+
+	err := ch.PublishWithContext(ctx, "", letter.Queue, true, false, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  delivery.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    delivery.MessageId,
+		Timestamp:    delivery.Timestamp,
+		Body:         delivery.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replay message %s: %w", delivery.MessageId, err)
+	}
+
+	select {
+	case confirm, ok := <-confirms:
+		if !ok || !confirm.Ack {
+			return fmt.Errorf("broker did not accept replay of message %s", delivery.MessageId)
+		}
+		// The broker returns a mandatory message it could not route before
+		// confirming it, so an unroutable replay is already waiting here.
+		select {
+		case <-returns:
+			return fmt.Errorf("queue %s no longer exists, message %s was not replayed", letter.Queue, delivery.MessageId)
+		default:
+		}
+		return nil
+	case <-time.After(replayConfirmWait):
+		return fmt.Errorf("timed out waiting for the broker to confirm replay of message %s", delivery.MessageId)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *DeadLetters) channel() (*amqp.Channel, error) {
+	if !q.client.IsConnected() {
+		return nil, errors.New("rabbitmq is not connected")
+	}
+	ch, err := q.client.connection.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	return ch, nil
+}
+
+// deadLetterFromDelivery reads the dead-lettering details RabbitMQ records in
+// the x-death header, whose most recent entry comes first.
+func deadLetterFromDelivery(delivery amqp.Delivery, position int) *entities.DeadLetter {
+	letter := &entities.DeadLetter{
+		Position:    position,
+		MessageID:   delivery.MessageId,
+		ContentType: delivery.ContentType,
+		Headers:     delivery.Headers,
+		Body:        delivery.Body,
+		ReplayCount: headerInt(delivery.Headers[replayCountHeader]),
+	}
+
+	deaths, _ := delivery.Headers["x-death"].([]interface{})
+	for i, entry := range deaths {
+		death, ok := entry.(amqp.Table)
+		if !ok {
+			continue
+		}
+		letter.DeathCount += headerInt(death["count"])
+		if i != 0 {
+			continue
+		}
+		letter.Queue, _ = death["queue"].(string)
+		letter.Reason, _ = death["reason"].(string)
+		if keys, ok := death["routing-keys"].([]interface{}); ok && len(keys) > 0 {
+			letter.RoutingKey, _ = keys[0].(string)
+		}
+	}
+	if firstDeath, ok := firstDeathTime(deaths); ok {
+		letter.FirstDeathAt = &firstDeath
+	}
+	return letter
+}
+
+// firstDeathTime returns the time of the oldest x-death entry.
+func firstDeathTime(deaths []interface{}) (time.Time, bool) {
+	if len(deaths) == 0 {
+		return time.Time{}, false
+	}
+	death, ok := deaths[len(deaths)-1].(amqp.Table)
+	if !ok {
+		return time.Time{}, false
+	}
+	at, ok := death["time"].(time.Time)
+	return at, ok
+}
+
+// headerInt reads an integer header, whichever integer type the broker or
+// publisher encoded it as.
+func headerInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int8:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,46 @@
+package rabbitmq
+
+import (
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestDeadLetterFromDelivery(t *testing.T) {
+	first := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	latest := first.Add(time.Hour)
+	delivery := amqp.Delivery{
+		MessageId:  "msg-1",
+		RoutingKey: "post.failed",
+		Headers: amqp.Table{
+			replayCountHeader: int32(1),
+			"x-death": []interface{}{
+				amqp.Table{"count": int64(1), "queue": "post_notifications", "reason": "rejected", "routing-keys": []interface{}{"post.created"}, "time": latest},
+				amqp.Table{"count": int64(2), "queue": "post_notifications", "reason": "expired", "routing-keys": []interface{}{"post.created"}, "time": first},
+			},
+		},
+		Body: []byte(`{"post_id":"p1"}`),
+	}
+
+	letter := deadLetterFromDelivery(delivery, 3)
+	if letter.Position != 3 || letter.MessageID != "msg-1" {
+		t.Fatalf("unexpected position or ID: %+v", letter)
+	}
+	if letter.RoutingKey != "post.created" || letter.Queue != "post_notifications" || letter.Reason != "rejected" {
+		t.Fatalf("expected the original routing key and the latest death, got %+v", letter)
+	}
+	if letter.DeathCount != 3 || letter.ReplayCount != 1 {
+		t.Fatalf("expected 3 deaths and 1 replay, got %d and %d", letter.DeathCount, letter.ReplayCount)
+	}
+	if letter.FirstDeathAt == nil || !letter.FirstDeathAt.Equal(first) {
+		t.Fatalf("expected the oldest death time, got %v", letter.FirstDeathAt)
+	}
+}
+
+func TestDeadLetterFromDelivery_WithoutDeathHeader(t *testing.T) {
+	letter := deadLetterFromDelivery(amqp.Delivery{MessageId: "msg-1"}, 0)
+	if letter.RoutingKey != "" || letter.DeathCount != 0 || letter.FirstDeathAt != nil {
+		t.Fatalf("expected no death details, got %+v", letter)
+	}
+}
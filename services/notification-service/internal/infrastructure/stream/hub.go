@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"sync"
+
+	"notification-service/internal/domain/entities"
+)
+
+// Hub is an in-process pub/sub of new notifications keyed by user ID. It only
+// reaches clients connected to this instance.
+type Hub struct {
+	mu          sync.RWMutex
+	bufferSize  int
+	subscribers map[string]map[chan *entities.Notification]struct{}
+}
+
+// NewHub returns a hub whose subscriber channels buffer bufferSize
+// notifications; a subscriber that falls further behind misses the overflow.
+func NewHub(bufferSize int) *Hub {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &Hub{
+		bufferSize:  bufferSize,
+		subscribers: make(map[string]map[chan *entities.Notification]struct{}),
+	}
+}
+
+func (h *Hub) Subscribe(userID string) (<-chan *entities.Notification, func()) {
+	ch := make(chan *entities.Notification, h.bufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan *entities.Notification]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+func (h *Hub) Publish(notification *entities.Notification) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
+
+// Subscribers returns the number of live subscriptions for userID.
+func (h *Hub) Subscribers(userID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers[userID])
+}
@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"testing"
+
+	"notification-service/internal/domain/entities"
+)
+
+func TestHubDeliversOnlyToRecipient(t *testing.T) {
+	hub := NewHub(4)
+	alice, unsubscribeAlice := hub.Subscribe("alice")
+	defer unsubscribeAlice()
+	bob, unsubscribeBob := hub.Subscribe("bob")
+	defer unsubscribeBob()
+
+	hub.Publish(&entities.Notification{ID: "n1", UserID: "alice"})
+
+	select {
+	case n := <-alice:
+		if n.ID != "n1" {
+			t.Errorf("expected n1, got %s", n.ID)
+		}
+	default:
+		t.Fatal("expected alice to receive the notification")
+	}
+	select {
+	case n := <-bob:
+		t.Errorf("expected bob to receive nothing, got %s", n.ID)
+	default:
+	}
+}
+
+func TestHubUnsubscribeClosesAndCleansUp(t *testing.T) {
+	hub := NewHub(1)
+	ch, unsubscribe := hub.Subscribe("alice")
+
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-ch; ok {
+		t.Fatal("expected channel to be closed")
+	}
+	if n := hub.Subscribers("alice"); n != 0 {
+		t.Fatalf("expected no subscribers left, got %d", n)
+	}
+	// Publishing after cleanup must not panic on the closed channel.
+	hub.Publish(&entities.Notification{ID: "n1", UserID: "alice"})
+}
+
+func TestHubDropsWhenSubscriberIsFull(t *testing.T) {
+	hub := NewHub(1)
+	ch, unsubscribe := hub.Subscribe("alice")
+	defer unsubscribe()
+
+	hub.Publish(&entities.Notification{ID: "n1", UserID: "alice"})
+	hub.Publish(&entities.Notification{ID: "n2", UserID: "alice"})
+
+	if n := <-ch; n.ID != "n1" {
+		t.Fatalf("expected the buffered notification, got %s", n.ID)
+	}
+	select {
+	case n := <-ch:
+		t.Fatalf("expected overflow to be dropped, got %s", n.ID)
+	default:
+	}
+}
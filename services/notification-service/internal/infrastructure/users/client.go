@@ -0,0 +1,76 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"notification-service/internal/config"
+	"notification-service/pkg/tracing"
+)
+
+// internalTokenHeader carries the shared secret user-service requires on its
+// internal routes.
+const internalTokenHeader = "X-Internal-Token"
+
+// Client reads the follow graph from user-service's internal HTTP API.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func NewClient(cfg config.UserServiceConfig) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(cfg.URL, "/"),
+		token:      cfg.InternalToken,
+		httpClient: &http.Client{Transport: tracing.Transport(nil), Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond},
+	}
+}
+
+type followerIDsResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		FollowerIDs []string `json:"follower_ids"`
+		NextCursor  string   `json:"next_cursor"`
+	} `json:"data"`
+}
+
+func (c *Client) GetFollowerIDs(ctx context.Context, userID string, limit int, cursor string) ([]string, string, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/users/%s/follower-ids?%s", c.baseURL, url.PathEscape(userID), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build follower IDs request: %w", err)
+	}
+	req.Header.Set(internalTokenHeader, c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get follower IDs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("user-service returned status %d for follower IDs", resp.StatusCode)
+	}
+
+	var body followerIDsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, "", fmt.Errorf("failed to decode follower IDs: %w", err)
+	}
+	if !body.Success {
+		return nil, "", fmt.Errorf("user-service rejected follower IDs request")
+	}
+	return body.Data.FollowerIDs, body.Data.NextCursor, nil
+}
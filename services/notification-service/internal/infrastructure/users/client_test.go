@@ -0,0 +1,48 @@
+package users
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"notification-service/internal/config"
+)
+
+func TestGetFollowerIDs_SendsTokenAndDecodesPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/author-1/follower-ids" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get(internalTokenHeader) != "secret-token" {
+			t.Errorf("expected the internal token header, got %q", r.Header.Get(internalTokenHeader))
+		}
+		if r.URL.Query().Get("limit") != "2" || r.URL.Query().Get("cursor") != "abc" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"message":"ok","data":{"follower_ids":["u1","u2"],"next_cursor":"def"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(config.UserServiceConfig{URL: server.URL + "/", InternalToken: "secret-token", TimeoutMs: 1000})
+	ids, next, err := client.GetFollowerIDs(context.Background(), "author-1", 2, "abc")
+	if err != nil {
+		t.Fatalf("GetFollowerIDs: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "u1" || next != "def" {
+		t.Errorf("unexpected page %v / %q", ids, next)
+	}
+}
+
+func TestGetFollowerIDs_ReturnsErrorOnNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(config.UserServiceConfig{URL: server.URL, TimeoutMs: 1000})
+	if _, _, err := client.GetFollowerIDs(context.Background(), "author-1", 10, ""); err == nil {
+		t.Fatal("expected an error when the internal route is rejected")
+	}
+}
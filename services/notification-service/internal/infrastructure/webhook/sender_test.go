@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"notification-service/internal/domain/entities"
+)
+
+func TestSend_SignsBody(t *testing.T) {
+	var gotSignature, gotEvent, gotDelivery string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(SignatureHeader)
+		gotEvent = r.Header.Get(EventHeader)
+		gotDelivery = r.Header.Get(DeliveryHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	body := []byte(`{"event":"post.created"}`)
+	status, err := NewSender(time.Second).Send(context.Background(), &entities.WebhookRequest{
+		URL: server.URL, Secret: "s3cret", Event: "post.created", DeliveryID: "d1", Body: body,
+	})
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("expected a delivered webhook, got %d, %v", status, err)
+	}
+
+	// HMAC-SHA256 of the body keyed with "s3cret".
+	const want = "sha256=37ac0d79bdd285b8dc09f0b9917a0a6ffeb6db5e73ddb2448591a290034ecc43"
+	if gotSignature != want {
+		t.Fatalf("expected signature %s, got %q", want, gotSignature)
+	}
+	if gotEvent != "post.created" || gotDelivery != "d1" || string(gotBody) != string(body) {
+		t.Fatalf("unexpected request: event=%q delivery=%q body=%s", gotEvent, gotDelivery, gotBody)
+	}
+}
+
+func TestSend_NonSuccessStatusIsAnError(t *testing.T) {
+	for _, status := range []int{http.StatusFound, http.StatusBadRequest, http.StatusServiceUnavailable} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if status == http.StatusFound {
+				http.Redirect(w, r, "/elsewhere", status)
+				return
+			}
+			w.WriteHeader(status)
+		}))
+
+		got, err := NewSender(time.Second).Send(context.Background(), &entities.WebhookRequest{URL: server.URL, Body: []byte(`{}`)})
+		server.Close()
+		if err == nil || got != status {
+			t.Fatalf("status %d: expected a failed delivery reporting the status, got %d, %v", status, got, err)
+		}
+	}
+}
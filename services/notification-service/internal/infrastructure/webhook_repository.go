@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"notification-service/internal/domain/entities"
+	"time"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `id, url, secret, events, active, created_at, updated_at`
+
+func (r *WebhookRepository) Create(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, url, secret, events, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+	`
+
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, query, subscription.ID, subscription.URL, subscription.Secret,
+		pq.Array(subscription.Events), subscription.Active, now)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+	return nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*entities.WebhookSubscription, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions WHERE id = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions, err := scanWebhookSubscriptions(rows)
+	if err != nil || len(subscriptions) == 0 {
+		return nil, err
+	}
+	return subscriptions[0], nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookSubscriptions(rows)
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, subscription *entities.WebhookSubscription) (bool, error) {
+	query := `
+		UPDATE webhook_subscriptions
+		SET url = $2, secret = $3, events = $4, active = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, subscription.ID, subscription.URL, subscription.Secret,
+		pq.Array(subscription.Events), subscription.Active, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	subscription.UpdatedAt = now
+	return rowsAffected > 0, nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *WebhookRepository) ListActiveForEvent(ctx context.Context, event string) ([]*entities.WebhookSubscription, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhook_subscriptions
+		WHERE active AND events @> ARRAY[$1]::text[]
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions for %s: %w", event, err)
+	}
+	defer rows.Close()
+
+	return scanWebhookSubscriptions(rows)
+}
+
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, subscription_id, event, message_id, attempts, status_code, success, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, query, delivery.ID, delivery.SubscriptionID, delivery.Event, delivery.MessageID,
+		delivery.Attempts, delivery.StatusCode, delivery.Success, delivery.Error, now)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	delivery.CreatedAt = now
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*entities.WebhookDelivery, error) {
+	query := `
+		SELECT id, subscription_id, event, message_id, attempts, status_code, success, error, created_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*entities.WebhookDelivery
+	for rows.Next() {
+		delivery := &entities.WebhookDelivery{}
+		if err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.Event, &delivery.MessageID,
+			&delivery.Attempts, &delivery.StatusCode, &delivery.Success, &delivery.Error, &delivery.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanWebhookSubscriptions(rows *sql.Rows) ([]*entities.WebhookSubscription, error) {
+	var subscriptions []*entities.WebhookSubscription
+	for rows.Next() {
+		subscription := &entities.WebhookSubscription{}
+		if err := rows.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, pq.Array(&subscription.Events),
+			&subscription.Active, &subscription.CreatedAt, &subscription.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"notification-service/internal/application/dto"
+	"notification-service/internal/application/errors"
+	"notification-service/internal/application/services"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/utils"
+)
+
+// DeadLetterHandler serves the internal dead-letter queue API.
+type DeadLetterHandler struct {
+	deadLetterService *services.DeadLetterService
+	logger            *logger.Logger
+}
+
+func NewDeadLetterHandler(deadLetterService *services.DeadLetterService, logger *logger.Logger) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetterService: deadLetterService, logger: logger}
+}
+
+// ListDeadLetters pages through a dead-letter queue without removing
+// anything from it.
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	var req dto.ListDeadLettersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("invalid list dead letters req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	response, err := h.deadLetterService.List(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "list dead letters")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "dead letters retrieved successfully", response)
+}
+
+func (h *DeadLetterHandler) ReplayDeadLetters(c *gin.Context) {
+	var req dto.DeadLetterActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("invalid replay dead letters req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.deadLetterService.Replay(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "replay dead letters")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "dead letters replayed successfully", response)
+}
+
+func (h *DeadLetterHandler) PurgeDeadLetters(c *gin.Context) {
+	var req dto.DeadLetterActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("invalid purge dead letters req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.deadLetterService.Purge(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "purge dead letters")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "dead letters purged successfully", response)
+}
+
+func (h *DeadLetterHandler) handleError(c *gin.Context, err error, action string) {
+	if notificationErr, ok := err.(*errors.NotificationError); ok {
+		utils.ErrorResponse(c, notificationErr)
+		return
+	}
+	h.logger.Error("unexpected error in " + action + ": " + err.Error())
+	utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+}
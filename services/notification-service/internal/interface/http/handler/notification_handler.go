@@ -1,27 +1,38 @@
 package handler
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"notification-service/internal/application/dto"
 	"notification-service/internal/application/errors"
 	"notification-service/internal/application/services"
 	"notification-service/internal/interface/validators"
+	"notification-service/pkg/health"
 	"notification-service/pkg/logger"
 	"notification-service/pkg/utils"
+	"time"
 )
 
+// streamHeartbeatInterval keeps idle SSE connections open through proxies.
+const streamHeartbeatInterval = 30 * time.Second
+
 type NotificationHandler struct {
 	notificationService *services.NotificationService
 	validator           *validators.NotificationValidator
+	health              *health.Checker
 	logger              *logger.Logger
+	heartbeatInterval   time.Duration
 }
 
-func NewNotificationHandler(notificationService *services.NotificationService, logger *logger.Logger) *NotificationHandler {
+func NewNotificationHandler(notificationService *services.NotificationService, checker *health.Checker, logger *logger.Logger) *NotificationHandler {
 	return &NotificationHandler{
 		notificationService: notificationService,
 		validator:           validators.NewNotificationValidator(),
+		health:              checker,
 		logger:              logger,
+		heartbeatInterval:   streamHeartbeatInterval,
 	}
 }
 
@@ -63,6 +74,103 @@ func (h *NotificationHandler) CreateNotification(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusCreated, "notif created successfully", response)
 }
 
+// StreamNotifications pushes the caller's new notifications as Server-Sent
+// Events until the client disconnects, with a comment heartbeat in between.
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	// The server's WriteTimeout would otherwise cut the stream short.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("failed to clear write deadline for notif stream: " + err.Error())
+	}
+
+	notifications, unsubscribe := h.notificationService.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(services.ToNotificationResponse(notification))
+			if err != nil {
+				h.logger.Error("failed to encode streamed notif: " + err.Error())
+				continue
+			}
+			fmt.Fprintf(c.Writer, "id: %s\nevent: notification\ndata: %s\n\n", notification.ID, payload)
+			c.Writer.Flush()
+		}
+	}
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	response, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		if notificationErr, ok := err.(*errors.NotificationError); ok {
+			utils.ErrorResponse(c, notificationErr)
+		} else {
+			h.logger.Error("unexpected err in get notif preferences " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "notif preferences retrieved successfully", response)
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	var req dto.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("invalid update notif preferences req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.notificationService.UpdatePreferences(c.Request.Context(), userID, c.GetString("userEmail"), &req)
+	if err != nil {
+		if notificationErr, ok := err.(*errors.NotificationError); ok {
+			utils.ErrorResponse(c, notificationErr)
+		} else {
+			h.logger.Error("unexpected err in update notif preferences " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "notif preferences updated successfully", response)
+}
+
 func (h *NotificationHandler) GetNotification(c *gin.Context) {
 	id := c.Param("id")
 	userID := c.GetString("userID")
@@ -100,6 +208,12 @@ func (h *NotificationHandler) ListNotifications(c *gin.Context) {
 		return
 	}
 
+	if err := h.validator.ValidateListNotificationsRequest(&req); err != nil {
+		h.logger.Warn("list notif validation failed: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
 	if req.Limit == 0 {
 		req.Limit = 20
 	}
@@ -151,6 +265,39 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "notifs marked as read successfully", nil)
 }
 
+func (h *NotificationHandler) MarkAsUnread(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	var req dto.MarkAsUnreadRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("invalid mark as unread req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	if err := h.validator.ValidateMarkAsUnreadRequest(&req); err != nil {
+		h.logger.Warn("mark as unread validation failed: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	if err := h.notificationService.MarkAsUnread(c.Request.Context(), userID, &req); err != nil {
+		if notificationErr, ok := err.(*errors.NotificationError); ok {
+			utils.ErrorResponse(c, notificationErr)
+		} else {
+			h.logger.Error("Unexpected error in mark as unread: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "notifs marked as unread successfully", nil)
+}
+
 func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
 	id := c.Param("id")
 	userID := c.GetString("userID")
@@ -174,6 +321,41 @@ func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Notification deleted successfully", nil)
 }
 
+func (h *NotificationHandler) ArchiveNotification(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+func (h *NotificationHandler) UnarchiveNotification(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *NotificationHandler) setArchived(c *gin.Context, archived bool) {
+	id := c.Param("id")
+	userID := c.GetString("userID")
+
+	if id == "" || userID == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	update, message := h.notificationService.UnarchiveNotification, "Notification unarchived successfully"
+	if archived {
+		update, message = h.notificationService.ArchiveNotification, "Notification archived successfully"
+	}
+
+	if err := update(c.Request.Context(), id, userID); err != nil {
+		if notificationErr, ok := err.(*errors.NotificationError); ok {
+			utils.ErrorResponse(c, notificationErr)
+		} else {
+			h.logger.Error("Unexpected error in archive notification: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, message, nil)
+}
+
 func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
 	userID := c.GetString("userID")
 	if userID == "" {
@@ -199,9 +381,134 @@ func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Unread count retrieved successfully", response)
 }
 
+func (h *NotificationHandler) GetCountsByType(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	var req dto.NotificationCountsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("invalid notif counts req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.notificationService.CountByType(c.Request.Context(), userID, req.Unread)
+	if err != nil {
+		if notificationErr, ok := err.(*errors.NotificationError); ok {
+			utils.ErrorResponse(c, notificationErr)
+		} else {
+			h.logger.Error("Unexpected error in get notification counts: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification counts retrieved successfully", response)
+}
+
+func (h *NotificationHandler) AdminGetNotification(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.notificationService.AdminGetNotification(c.Request.Context(), id)
+	if err != nil {
+		if notificationErr, ok := err.(*errors.NotificationError); ok {
+			utils.ErrorResponse(c, notificationErr)
+		} else {
+			h.logger.Error("unexpected error in admin get notif: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "notif retrieved successfully", response)
+}
+
+func (h *NotificationHandler) AdminListNotifications(c *gin.Context) {
+	var req dto.AdminListNotificationsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("invalid admin list notif req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	response, err := h.notificationService.AdminListNotifications(c.Request.Context(), &req)
+	if err != nil {
+		if notificationErr, ok := err.(*errors.NotificationError); ok {
+			utils.ErrorResponse(c, notificationErr)
+		} else {
+			h.logger.Error("unexpected error in admin list notif: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "notif retrieved successfully", response)
+}
+
+// AdminCleanupNotifications deletes notifications older than the
+// older_than_days query parameter right away, without waiting for the
+// scheduled cleanup.
+func (h *NotificationHandler) AdminCleanupNotifications(c *gin.Context) {
+	var req dto.CleanupNotificationsRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("invalid admin cleanup notif req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	deleted, err := h.notificationService.CleanupOldNotifications(c.Request.Context(), req.OlderThanDays)
+	if err != nil {
+		if notificationErr, ok := err.(*errors.NotificationError); ok {
+			utils.ErrorResponse(c, notificationErr)
+		} else {
+			h.logger.Error("unexpected error in admin cleanup notif: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "old notifs cleaned up successfully", &dto.CleanupNotificationsResponse{
+		Deleted:       deleted,
+		OlderThanDays: req.OlderThanDays,
+	})
+}
+
+// HealthCheck is the liveness probe: it answers 200 whenever the process is
+// up, without touching any dependency.
 func (h *NotificationHandler) HealthCheck(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Notification service is healthy", gin.H{
 		"service": "notification-service",
 		"status":  "running",
 	})
 }
+
+// Readiness is the readiness probe: it checks the backing dependencies and
+// returns 503 when any of them is unreachable.
+func (h *NotificationHandler) Readiness(c *gin.Context) {
+	dependencies, err := h.health.Run(c.Request.Context())
+	if err != nil {
+		h.logger.Warn("Readiness check failed: " + err.Error())
+		utils.SuccessResponse(c, http.StatusServiceUnavailable, "Notification service is not ready", gin.H{
+			"service":      "notification-service",
+			"status":       health.StatusUnhealthy,
+			"dependencies": dependencies,
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification service is ready", gin.H{
+		"service":      "notification-service",
+		"status":       health.StatusHealthy,
+		"dependencies": dependencies,
+	})
+}
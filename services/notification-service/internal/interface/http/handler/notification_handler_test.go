@@ -0,0 +1,361 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"notification-service/internal/application/dto"
+	"notification-service/internal/application/services"
+	"notification-service/internal/domain/entities"
+	"notification-service/internal/domain/repositories"
+	"notification-service/internal/infrastructure/cache"
+	"notification-service/internal/infrastructure/stream"
+	"notification-service/internal/interface/http/middleware"
+	"notification-service/pkg/health"
+	"notification-service/pkg/logger"
+)
+
+type stubNotificationRepo struct {
+	notifications map[string]*entities.Notification
+	getByIDCalls  int
+	listCalls     int
+	deleteOldDays int
+}
+
+func (m *stubNotificationRepo) Create(ctx context.Context, notification *entities.Notification) error {
+	return nil
+}
+func (m *stubNotificationRepo) GetByID(ctx context.Context, id string) (*entities.Notification, error) {
+	m.getByIDCalls++
+	if n, ok := m.notifications[id]; ok {
+		return n, nil
+	}
+	return nil, errors.New("not found")
+}
+func (m *stubNotificationRepo) GetByUserID(ctx context.Context, userID string, notificationType entities.NotificationType, priority entities.NotificationPriority, limit, offset int) ([]*entities.Notification, error) {
+	m.listCalls++
+	return nil, nil
+}
+func (m *stubNotificationRepo) GetUnreadByUserID(ctx context.Context, userID string, notificationType entities.NotificationType, priority entities.NotificationPriority, limit, offset int) ([]*entities.Notification, error) {
+	m.listCalls++
+	return nil, nil
+}
+func (m *stubNotificationRepo) CountByType(ctx context.Context, userID string, unreadOnly bool) (map[entities.NotificationType]int64, error) {
+	return nil, nil
+}
+func (m *stubNotificationRepo) ListByUserID(ctx context.Context, userID string, unreadOnly, includeArchived bool, typeFilter entities.NotificationType, priorityFilter entities.NotificationPriority, limit, offset int) ([]*entities.Notification, int64, error) {
+	m.listCalls++
+	return nil, 0, nil
+}
+func (m *stubNotificationRepo) MarkAsRead(ctx context.Context, id string, userID string) error {
+	return nil
+}
+func (m *stubNotificationRepo) MarkManyAsRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	return 0, nil
+}
+func (m *stubNotificationRepo) MarkAsUnread(ctx context.Context, ids []string, userID string) (int64, error) {
+	return 0, nil
+}
+func (m *stubNotificationRepo) MakeAllAsRead(ctx context.Context, userID string) error { return nil }
+func (m *stubNotificationRepo) ArchiveNotification(ctx context.Context, id string, userID string) (bool, error) {
+	return true, nil
+}
+func (m *stubNotificationRepo) UnarchiveNotification(ctx context.Context, id string, userID string) (bool, error) {
+	return true, nil
+}
+func (m *stubNotificationRepo) Delete(ctx context.Context, id string, userID string) error {
+	return nil
+}
+func (m *stubNotificationRepo) GetUnreadCount(ctx context.Context, userID string) (int64, error) {
+	return 0, nil
+}
+func (m *stubNotificationRepo) CountByUserID(ctx context.Context, userID string, unreadOnly, includeArchived bool, typeFilter entities.NotificationType, priorityFilter entities.NotificationPriority) (int64, error) {
+	return 0, nil
+}
+func (m *stubNotificationRepo) List(ctx context.Context, after *entities.NotificationCursor, limit int) ([]*entities.Notification, error) {
+	return nil, nil
+}
+func (m *stubNotificationRepo) CreateBatchForEvent(ctx context.Context, messageID string, notifications []*entities.Notification) (bool, error) {
+	return true, nil
+}
+func (m *stubNotificationRepo) CreateBatchForRecipients(ctx context.Context, messageID string, notifications []*entities.Notification) ([]*entities.Notification, error) {
+	return notifications, nil
+}
+func (m *stubNotificationRepo) IsEventProcessed(ctx context.Context, messageID string) (bool, error) {
+	return false, nil
+}
+func (m *stubNotificationRepo) GetUndigested(ctx context.Context, userID string, since, until time.Time, limit int) ([]*entities.Notification, error) {
+	return nil, nil
+}
+func (m *stubNotificationRepo) CompleteDigest(ctx context.Context, userID string, since, windowEnd time.Time, digest *entities.Notification, sourceIDs []string) (bool, error) {
+	return true, nil
+}
+func (m *stubNotificationRepo) DeleteOld(ctx context.Context, olderThan int) (int64, error) {
+	m.deleteOldDays = olderThan
+	return 42, nil
+}
+
+var _ repositories.NotificationRepository = (*stubNotificationRepo)(nil)
+
+type stubPreferenceRepo struct{}
+
+func (stubPreferenceRepo) GetByUserID(ctx context.Context, userID string) ([]*entities.NotificationPreference, error) {
+	return nil, nil
+}
+func (stubPreferenceRepo) Upsert(ctx context.Context, preferences []*entities.NotificationPreference) error {
+	return nil
+}
+func (stubPreferenceRepo) IsEnabled(ctx context.Context, userID string, notificationType entities.NotificationType, channel entities.NotificationChannel) (bool, error) {
+	return true, nil
+}
+func (stubPreferenceRepo) FilterEnabled(ctx context.Context, userIDs []string, notificationType entities.NotificationType, channel entities.NotificationChannel) ([]string, error) {
+	return userIDs, nil
+}
+func (stubPreferenceRepo) GetEmailAddress(ctx context.Context, userID string) (string, error) {
+	return "", nil
+}
+func (stubPreferenceRepo) SetEmailAddress(ctx context.Context, userID, email string) error {
+	return nil
+}
+
+func (stubPreferenceRepo) GetDigestMode(ctx context.Context, userID string) (entities.DigestMode, error) {
+	return entities.DigestModeImmediate, nil
+}
+func (stubPreferenceRepo) SetDigestMode(ctx context.Context, userID string, mode entities.DigestMode) error {
+	return nil
+}
+func (stubPreferenceRepo) FilterDigest(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	return nil, nil
+}
+func (stubPreferenceRepo) ListDueDigests(ctx context.Context, mode entities.DigestMode, dueBefore time.Time, limit int) ([]*entities.DigestSubscription, error) {
+	return nil, nil
+}
+
+var _ repositories.PreferenceRepository = stubPreferenceRepo{}
+
+func newTestHandler(repo *stubNotificationRepo) *NotificationHandler {
+	log := logger.New("error")
+	svc := services.NewNotificationService(repo, stubPreferenceRepo{}, cache.NewUnreadCountCache(time.Minute), stream.NewHub(4), nil, nil, services.FanoutConfig{}, services.DigestConfig{}, log)
+	return NewNotificationHandler(svc, nil, log)
+}
+
+func TestGetNotificationEmptyIDReturnsEarly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &stubNotificationRepo{}
+	h := newTestHandler(repo)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/notifications/", nil)
+	c.Set("userID", "user1")
+
+	h.GetNotification(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if repo.getByIDCalls != 0 {
+		t.Fatalf("expected no repository lookup for an empty id, got %d", repo.getByIDCalls)
+	}
+}
+
+func TestGetNotificationOtherUsersNotificationIsNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &stubNotificationRepo{notifications: map[string]*entities.Notification{
+		"n1": {ID: "n1", UserID: "owner", Type: entities.NotificationTypePostCreated, Title: "t", Message: "m"},
+	}}
+	h := newTestHandler(repo)
+
+	router := gin.New()
+	router.GET("/api/v1/notifications/:id", func(c *gin.Context) {
+		c.Set("userID", "intruder")
+		h.GetNotification(c)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/notifications/n1", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for another user's notification, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestListNotificationsRejectsUnknownType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &stubNotificationRepo{}
+	h := newTestHandler(repo)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/notifications?type=post_liked", nil)
+	c.Set("userID", "user1")
+
+	h.ListNotifications(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if repo.listCalls != 0 {
+		t.Fatalf("expected no repository query for an unknown type, got %d", repo.listCalls)
+	}
+}
+
+func TestAdminCleanupNotificationsRequiresServiceToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &stubNotificationRepo{}
+	h := newTestHandler(repo)
+	router := gin.New()
+	router.POST("/internal/notifications/cleanup", middleware.ServiceAuthMiddleware("service-token"), h.AdminCleanupNotifications)
+
+	cleanup := func(target, token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		if token != "" {
+			req.Header.Set(middleware.InternalTokenHeader, token)
+		}
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := cleanup("/internal/notifications/cleanup?older_than_days=7", ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d without the service token, got %d", http.StatusForbidden, rec.Code)
+	}
+	if rec := cleanup("/internal/notifications/cleanup?older_than_days=7", "wrong-token"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d with a wrong service token, got %d", http.StatusForbidden, rec.Code)
+	}
+	if rec := cleanup("/internal/notifications/cleanup", "service-token"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d without older_than_days, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if repo.deleteOldDays != 0 {
+		t.Fatal("expected nothing deleted for rejected requests")
+	}
+
+	rec := cleanup("/internal/notifications/cleanup?older_than_days=7", "service-token")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if repo.deleteOldDays != 7 {
+		t.Fatalf("expected cleanup of notifications older than 7 days, got %d", repo.deleteOldDays)
+	}
+	if !strings.Contains(rec.Body.String(), `"deleted":42`) {
+		t.Fatalf("expected the deleted count in the response, got %s", rec.Body.String())
+	}
+}
+
+func TestMarkAsUnreadRequiresIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newTestHandler(&stubNotificationRepo{})
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/notifications/mark-unread", strings.NewReader(`{"notification_ids":[]}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("userID", "user1")
+
+	h.MarkAsUnread(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestStreamNotificationsPushesNewNotificationsAndHeartbeats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("error")
+	hub := stream.NewHub(4)
+	svc := services.NewNotificationService(&stubNotificationRepo{}, stubPreferenceRepo{}, cache.NewUnreadCountCache(time.Minute), hub, nil, nil, services.FanoutConfig{}, services.DigestConfig{}, log)
+	h := NewNotificationHandler(svc, nil, log)
+	h.heartbeatInterval = 20 * time.Millisecond
+
+	router := gin.New()
+	router.GET("/api/v1/notifications/stream", func(c *gin.Context) {
+		c.Set("userID", "user1")
+		h.StreamNotifications(c)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/notifications/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	readUntil := func(prefix string) string {
+		t.Helper()
+		for lines.Scan() {
+			if strings.HasPrefix(lines.Text(), prefix) {
+				return lines.Text()
+			}
+		}
+		t.Fatalf("stream ended before %q: %v", prefix, lines.Err())
+		return ""
+	}
+
+	readUntil(": connected")
+	if _, err := svc.CreateNotification(context.Background(), &dto.CreateNotificationRequest{
+		UserID: "user1", Type: string(entities.NotificationTypePostCreated), Title: "Hello", Message: "New post",
+	}); err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+	if data := readUntil("data: "); !strings.Contains(data, `"title":"Hello"`) {
+		t.Errorf("expected the created notification, got %s", data)
+	}
+	readUntil(": ping")
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for hub.Subscribers("user1") != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the subscription to be released after disconnect")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHealthIsLiveWhileReadinessFollowsDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newTestHandler(&stubNotificationRepo{})
+	h.health = health.NewChecker().Register("rabbitmq", func(context.Context) error {
+		return errors.New("rabbitmq consumer is not connected")
+	})
+
+	router := gin.New()
+	router.GET("/health", h.HealthCheck)
+	router.GET("/ready", h.Readiness)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected liveness 200 with a dependency down, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected readiness 503 with a dependency down, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"rabbitmq":"unhealthy"`) {
+		t.Fatalf("expected the failing dependency in the body, got %s", rec.Body.String())
+	}
+
+	h.health = health.NewChecker().Register("postgres", func(context.Context) error { return nil })
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected readiness 200 with dependencies up, got %d", rec.Code)
+	}
+}
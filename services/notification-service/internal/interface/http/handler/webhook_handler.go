@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"notification-service/internal/application/dto"
+	"notification-service/internal/application/errors"
+	"notification-service/internal/application/services"
+	"notification-service/pkg/logger"
+	"notification-service/pkg/utils"
+)
+
+// WebhookHandler serves the internal webhook subscription API.
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+	logger         *logger.Logger
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService, logger *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService, logger: logger}
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("invalid create webhook req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.webhookService.CreateSubscription(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "create webhook")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusCreated, "webhook created successfully", response)
+}
+
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	response, err := h.webhookService.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "list webhooks")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "webhooks retrieved successfully", gin.H{"webhooks": response})
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	response, err := h.webhookService.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "get webhook")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "webhook retrieved successfully", response)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("invalid update webhook req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.webhookService.UpdateSubscription(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "update webhook")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "webhook updated successfully", response)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "delete webhook")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "webhook deleted successfully", nil)
+}
+
+// ListWebhookDeliveries returns the subscription's delivery log, newest first.
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	var req dto.ListWebhookDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("invalid list webhook deliveries req: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+
+	response, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Param("id"), req.Limit)
+	if err != nil {
+		h.handleError(c, err, "list webhook deliveries")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "webhook deliveries retrieved successfully", gin.H{"deliveries": response})
+}
+
+func (h *WebhookHandler) handleError(c *gin.Context, err error, action string) {
+	if notificationErr, ok := err.(*errors.NotificationError); ok {
+		utils.ErrorResponse(c, notificationErr)
+		return
+	}
+	h.logger.Error("unexpected error in " + action + ": " + err.Error())
+	utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"notification-service/internal/config"
+)
+
+func serveCORS(cfg config.CORSConfig, method, origin string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CORS(cfg))
+	router.GET("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(method, "/resource", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSEchoesAllowedOrigin(t *testing.T) {
+	cfg := config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}
+
+	rec := serveCORS(cfg, http.MethodGet, "https://app.example.com")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected the request origin to be echoed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Fatalf("expected credentials to be allowed, got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Origin" {
+		t.Fatalf("expected Vary: Origin, got %q", got)
+	}
+}
+
+func TestCORSOmitsHeadersForDisallowedOrigin(t *testing.T) {
+	cfg := config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}
+
+	rec := serveCORS(cfg, http.MethodGet, "https://evil.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no Access-Control-Allow-Origin, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Fatalf("expected no Access-Control-Allow-Credentials, got %q", got)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	cfg := config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}
+
+	rec := serveCORS(cfg, http.MethodOptions, "https://app.example.com")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got == "" {
+		t.Fatal("expected Access-Control-Allow-Methods on preflight")
+	}
+
+	rec = serveCORS(cfg, http.MethodOptions, "https://evil.example.com")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected disallowed preflight to get no Access-Control-Allow-Origin, got %q", got)
+	}
+}
+
+func TestCORSWildcardRequiresExplicitConfig(t *testing.T) {
+	rec := serveCORS(config.CORSConfig{}, http.MethodGet, "https://app.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no CORS headers without an allowlist, got %q", got)
+	}
+
+	rec = serveCORS(config.CORSConfig{AllowedOrigins: []string{"*"}}, http.MethodGet, "https://app.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Fatalf("expected *, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Fatalf("expected no credentials with a wildcard, got %q", got)
+	}
+
+	rec = serveCORS(config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, http.MethodGet, "https://app.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected a wildcard never to be honoured with credentials, got %q", got)
+	}
+}
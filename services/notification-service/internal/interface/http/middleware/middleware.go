@@ -1,14 +1,16 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
-	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 	"notification-service/internal/application/errors"
+	"notification-service/internal/config"
 	"notification-service/pkg/auth"
-	"notification-service/pkg/logger"
+	logpkg "notification-service/pkg/logger"
 	"notification-service/pkg/utils"
 )
 
@@ -20,17 +22,23 @@ const (
 	// ContextUserIDKey is the gin context key under which the authenticated user
 	// id is stored. Handlers must read identity from here, never from a header.
 	ContextUserIDKey = "userID"
+	// ContextUserEmailKey holds the email claim of a verified access token. It
+	// is absent for the insecure_dev header fallback.
+	ContextUserEmailKey = "userEmail"
+	// InternalTokenHeader carries the shared INTERNAL_SERVICE_TOKEN on
+	// service-to-service calls.
+	InternalTokenHeader = "X-Internal-Token"
 )
 
 // AuthMiddleware authenticates the caller from a signed access token carried in
 // the Authorization: Bearer header and stores the verified user id in the gin
-// context under ContextUserIDKey.
+// context under ContextUserIDKey (and its email claim under ContextUserEmailKey).
 //
 // A valid token is required in every mode (fail closed). As an explicit,
 // production-forbidden convenience, when trustMode is "insecure_dev" and no
 // bearer token is supplied, a plain X-User-ID header is accepted so the service
 // can be exercised locally without minting tokens.
-func AuthMiddleware(validator *auth.Validator, trustMode string, log *logger.Logger) gin.HandlerFunc {
+func AuthMiddleware(validator *auth.Validator, trustMode string, log *logpkg.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if token := bearerToken(c); token != "" {
 			if validator == nil {
@@ -39,14 +47,17 @@ func AuthMiddleware(validator *auth.Validator, trustMode string, log *logger.Log
 				c.Abort()
 				return
 			}
-			userID, err := validator.ValidateAccessToken(token)
+			claims, err := validator.ValidateAccessTokenClaims(token)
 			if err != nil {
 				log.Warn("rejected request with invalid access token: " + err.Error())
 				utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
 				c.Abort()
 				return
 			}
-			c.Set(ContextUserIDKey, userID)
+			c.Set(ContextUserIDKey, claims.UserID)
+			if claims.Email != "" {
+				c.Set(ContextUserEmailKey, claims.Email)
+			}
 			c.Next()
 			return
 		}
@@ -67,6 +78,21 @@ func AuthMiddleware(validator *auth.Validator, trustMode string, log *logger.Log
 	}
 }
 
+// ServiceAuthMiddleware admits only callers presenting token in
+// InternalTokenHeader. An empty token rejects every caller, so the routes it
+// guards are closed until INTERNAL_SERVICE_TOKEN is configured.
+func ServiceAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(InternalTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // bearerToken extracts the token from an "Authorization: Bearer <token>" header.
 func bearerToken(c *gin.Context) string {
 	header := c.GetHeader("Authorization")
@@ -80,25 +106,7 @@ func bearerToken(c *gin.Context) string {
 	return strings.TrimSpace(parts[1])
 }
 
-// CORS sets cross-origin headers for the notification API. The API authenticates
-// with a bearer token (not cookies), so credentials are intentionally NOT allowed:
-// a wildcard origin without credentials cannot be used to read responses with a
-// victim's token attached.
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		}
-		c.Next()
-	}
-}
-
-func ErrorHandler(logger *logger.Logger) gin.HandlerFunc {
+func ErrorHandler(logger *logpkg.Logger) gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		if err, ok := recovered.(string); ok {
 			logger.Error("panic recovered: " + err)
@@ -113,13 +121,91 @@ func ErrorHandler(logger *logger.Logger) gin.HandlerFunc {
 	})
 }
 
-func RequestLogger(logger *logger.Logger) gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.Info(
-			"Request: " + param.Method + " " + param.Path +
-				" | Status: " + strconv.Itoa(param.StatusCode) +
-				" | Latency: " + param.Latency.String(),
-		)
-		return ""
+func RequestLogger(logger *logpkg.Logger, cfg config.AccessLogConfig) gin.HandlerFunc {
+	filter := newAccessLogFilter(cfg)
+
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(param gin.LogFormatterParams) string {
+			userID, _ := param.Keys[ContextUserIDKey].(string)
+			logger.Info("Request: "+param.Method+" "+param.Path,
+				logpkg.F("status", param.StatusCode),
+				logpkg.F("latency_ms", param.Latency.Milliseconds()),
+				logpkg.F("request_id", requestIDFromHeader(param.Request)),
+				logpkg.F("user_id", userID),
+			)
+			return ""
+		},
+		Skip: filter.skip,
 	})
 }
+
+// requestIDFromHeader returns the X-Request-ID the API gateway forwarded, or
+// "-" when it is missing or malformed, so access log lines can be correlated
+// with the gateway's.
+func requestIDFromHeader(r *http.Request) string {
+	id := r.Header.Get("X-Request-ID")
+	if id == "" || len(id) > 128 {
+		return "-"
+	}
+	for _, ch := range id {
+		if ch <= ' ' || ch > '~' {
+			return "-"
+		}
+	}
+	return id
+}
+
+// accessLogFilter keeps health checks and unread-count polling out of the
+// access log. Mutations and server errors are always logged.
+type accessLogFilter struct {
+	skipPaths   map[string]struct{}
+	samplePaths map[string]struct{}
+	sampleRate  uint64
+	sampled     atomic.Uint64
+}
+
+func newAccessLogFilter(cfg config.AccessLogConfig) *accessLogFilter {
+	rate := cfg.SampleRate
+	if rate < 1 {
+		rate = 1
+	}
+
+	return &accessLogFilter{
+		skipPaths:   toPathSet(cfg.SkipPaths),
+		samplePaths: toPathSet(cfg.SamplePaths),
+		sampleRate:  uint64(rate),
+	}
+}
+
+func (f *accessLogFilter) skip(c *gin.Context) bool {
+	if !isReadOnlyMethod(c.Request.Method) || c.Writer.Status() >= http.StatusInternalServerError {
+		return false
+	}
+
+	path := c.Request.URL.Path
+	if _, ok := f.skipPaths[path]; ok {
+		return true
+	}
+	if _, ok := f.samplePaths[path]; ok {
+		return (f.sampled.Add(1)-1)%f.sampleRate != 0
+	}
+
+	return false
+}
+
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func toPathSet(paths []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		set[path] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"notification-service/internal/config"
+	"notification-service/pkg/logger"
+)
+
+func newLoggedRouter(t *testing.T, cfg config.AccessLogConfig) (*gin.Engine, *bytes.Buffer) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	appLogger := logger.New("info")
+	appLogger.SetOutput(&buf)
+
+	router := gin.New()
+	router.Use(RequestLogger(appLogger, cfg))
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/health", handler)
+	router.GET("/api/v1/notifications/unread-count", handler)
+	router.PUT("/api/v1/notifications/mark-read", handler)
+	return router, &buf
+}
+
+func TestRequestLoggerSkipsHealthChecks(t *testing.T) {
+	router, buf := newLoggedRouter(t, config.AccessLogConfig{SkipPaths: []string{"/health"}, SampleRate: 1})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if buf.Len() != 0 {
+		t.Fatalf("expected health check not to be logged, got %q", buf.String())
+	}
+}
+
+func TestRequestLoggerAlwaysLogsMutations(t *testing.T) {
+	router, buf := newLoggedRouter(t, config.AccessLogConfig{
+		SkipPaths:  []string{"/api/v1/notifications/mark-read"},
+		SampleRate: 1,
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/api/v1/notifications/mark-read", nil))
+	if !strings.Contains(buf.String(), "PUT /api/v1/notifications/mark-read") {
+		t.Fatalf("expected mutation to be logged, got %q", buf.String())
+	}
+}
+
+func TestRequestLoggerSamplesUnreadCountPolling(t *testing.T) {
+	router, buf := newLoggedRouter(t, config.AccessLogConfig{
+		SamplePaths: []string{"/api/v1/notifications/unread-count"},
+		SampleRate:  5,
+	})
+
+	for i := 0; i < 10; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/notifications/unread-count", nil))
+	}
+	if got := strings.Count(buf.String(), "unread-count"); got != 2 {
+		t.Fatalf("expected 2 of 10 polls to be logged, got %d", got)
+	}
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"notification-service/internal/application/services"
+	"notification-service/internal/interface/http/handler"
+	"notification-service/internal/interface/http/middleware"
+	"notification-service/pkg/logger"
+)
+
+// SetupDeadLetterRoutes mounts the dead-letter queue API. Every route
+// requires the service-to-service token and none is mounted when internal
+// HTTP trust is disabled. Call it after SetupNotificationRoutes, which
+// installs the global middleware.
+func SetupDeadLetterRoutes(router *gin.Engine, deadLetterService *services.DeadLetterService, trustMode, internalServiceToken string, logger *logger.Logger) {
+	if trustMode == "disabled" {
+		return
+	}
+	deadLetterHandler := handler.NewDeadLetterHandler(deadLetterService, logger)
+
+	dlq := router.Group("/internal/dlq")
+	dlq.Use(middleware.ServiceAuthMiddleware(internalServiceToken))
+	{
+		dlq.GET("", deadLetterHandler.ListDeadLetters)
+		dlq.POST("/replay", deadLetterHandler.ReplayDeadLetters)
+		dlq.POST("/purge", deadLetterHandler.PurgeDeadLetters)
+	}
+}
@@ -3,21 +3,24 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"notification-service/internal/application/services"
+	"notification-service/internal/config"
 	"notification-service/internal/interface/http/handler"
 	"notification-service/internal/interface/http/middleware"
 	"notification-service/pkg/auth"
+	"notification-service/pkg/health"
 	"notification-service/pkg/logger"
 )
 
-func SetupNotificationRoutes(router *gin.Engine, notificationService *services.NotificationService, validator *auth.Validator, trustMode string, logger *logger.Logger) {
-	notificationHandler := handler.NewNotificationHandler(notificationService, logger)
+func SetupNotificationRoutes(router *gin.Engine, notificationService *services.NotificationService, validator *auth.Validator, trustMode, internalServiceToken string, accessLog config.AccessLogConfig, cors config.CORSConfig, checker *health.Checker, logger *logger.Logger) {
+	notificationHandler := handler.NewNotificationHandler(notificationService, checker, logger)
 
 	// Global Middleware
 	router.Use(middleware.ErrorHandler(logger))
-	router.Use(middleware.RequestLogger(logger))
-	router.Use(middleware.CORS())
+	router.Use(middleware.RequestLogger(logger, accessLog))
+	router.Use(middleware.CORS(cors))
 
 	router.GET("/health", notificationHandler.HealthCheck)
+	router.GET("/ready", notificationHandler.Readiness)
 
 	v1 := router.Group("/api/v1")
 
@@ -30,10 +33,30 @@ func SetupNotificationRoutes(router *gin.Engine, notificationService *services.N
 				protected.POST("", notificationHandler.CreateNotification)
 				protected.GET("", notificationHandler.ListNotifications)
 				protected.GET("/unread-count", notificationHandler.GetUnreadCount)
+				protected.GET("/counts", notificationHandler.GetCountsByType)
+				protected.GET("/stream", notificationHandler.StreamNotifications)
+				protected.GET("/preferences", notificationHandler.GetPreferences)
+				protected.PUT("/preferences", notificationHandler.UpdatePreferences)
 				protected.GET("/:id", notificationHandler.GetNotification)
 				protected.PUT("/mark-read", notificationHandler.MarkAsRead)
+				protected.POST("/mark-unread", notificationHandler.MarkAsUnread)
+				protected.POST("/:id/archive", notificationHandler.ArchiveNotification)
+				protected.POST("/:id/unarchive", notificationHandler.UnarchiveNotification)
 				protected.DELETE("/:id", notificationHandler.DeleteNotification)
 			}
 		}
 	}
+
+	// Internal support views expose every user's notifications, so they
+	// require the service-to-service token and are not mounted at all when
+	// internal HTTP trust is disabled.
+	if trustMode != "disabled" {
+		internal := router.Group("/internal/notifications")
+		internal.Use(middleware.ServiceAuthMiddleware(internalServiceToken))
+		{
+			internal.GET("", notificationHandler.AdminListNotifications)
+			internal.GET("/:id", notificationHandler.AdminGetNotification)
+			internal.POST("/cleanup", notificationHandler.AdminCleanupNotifications)
+		}
+	}
 }
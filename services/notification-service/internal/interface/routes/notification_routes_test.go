@@ -0,0 +1,127 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"notification-service/internal/application/services"
+	"notification-service/internal/config"
+	"notification-service/internal/domain/entities"
+	"notification-service/internal/domain/repositories"
+	"notification-service/internal/infrastructure/cache"
+	"notification-service/internal/infrastructure/stream"
+	"notification-service/internal/interface/http/middleware"
+	"notification-service/pkg/auth"
+	"notification-service/pkg/health"
+	"notification-service/pkg/logger"
+)
+
+const testServiceToken = "0123456789abcdef0123456789abcdef"
+
+// adminRepo serves the calls the internal views make; anything else panics
+// through the nil embedded interface.
+type adminRepo struct {
+	repositories.NotificationRepository
+	calls int
+}
+
+func (r *adminRepo) List(ctx context.Context, after *entities.NotificationCursor, limit int) ([]*entities.Notification, error) {
+	r.calls++
+	now := time.Now()
+	return []*entities.Notification{
+		{ID: "n2", UserID: "user-1", CreatedAt: now},
+		{ID: "n1", UserID: "user-2", CreatedAt: now.Add(-time.Minute)},
+	}, nil
+}
+
+func (r *adminRepo) GetByID(ctx context.Context, id string) (*entities.Notification, error) {
+	r.calls++
+	return &entities.Notification{ID: id, UserID: "user-1", CreatedAt: time.Now()}, nil
+}
+
+func (r *adminRepo) DeleteOld(ctx context.Context, olderThanDays int) (int64, error) {
+	r.calls++
+	return 0, nil
+}
+
+func newInternalRouter(repo *adminRepo, token string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("error")
+	svc := services.NewNotificationService(repo, nil, cache.NewUnreadCountCache(time.Minute), stream.NewHub(4), nil, nil, services.FanoutConfig{}, services.DigestConfig{}, log)
+	router := gin.New()
+	SetupNotificationRoutes(router, svc, auth.NewValidator("jwt-secret"), "private_network", token, config.AccessLogConfig{}, config.CORSConfig{}, health.NewChecker(), log)
+	return router
+}
+
+func serveInternal(router *gin.Engine, method, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set(middleware.InternalTokenHeader, token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestInternalNotificationRoutesAcceptServiceToken(t *testing.T) {
+	repo := &adminRepo{}
+	router := newInternalRouter(repo, testServiceToken)
+
+	rec := serveInternal(router, http.MethodGet, "/internal/notifications?limit=1", testServiceToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the first page, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var page struct {
+		Data struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil || page.Data.NextCursor == "" {
+		t.Fatalf("expected a next cursor, got %s (%v)", rec.Body.String(), err)
+	}
+
+	for _, target := range []string{
+		"/internal/notifications?limit=1&cursor=" + url.QueryEscape(page.Data.NextCursor),
+		"/internal/notifications/n1",
+	} {
+		if rec := serveInternal(router, http.MethodGet, target, testServiceToken); rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", target, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := serveInternal(router, http.MethodPost, "/internal/notifications/cleanup?older_than_days=7", testServiceToken); rec.Code != http.StatusOK {
+		t.Fatalf("expected cleanup to run, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestInternalNotificationRoutesRejectMissingOrWrongToken(t *testing.T) {
+	repo := &adminRepo{}
+	routers := map[string]*gin.Engine{
+		"configured":     newInternalRouter(repo, testServiceToken),
+		"not configured": newInternalRouter(repo, ""),
+	}
+
+	for name, router := range routers {
+		for _, provided := range []string{"", "wrong"} {
+			for _, route := range []struct{ method, target string }{
+				{http.MethodGet, "/internal/notifications"},
+				{http.MethodGet, "/internal/notifications?cursor=abc&limit=5"},
+				{http.MethodGet, "/internal/notifications/n1"},
+				{http.MethodPost, "/internal/notifications/cleanup?older_than_days=7"},
+			} {
+				if rec := serveInternal(router, route.method, route.target, provided); rec.Code != http.StatusForbidden {
+					t.Fatalf("%s token, provided %q, %s %s: expected 403, got %d", name, provided, route.method, route.target, rec.Code)
+				}
+			}
+		}
+	}
+	if repo.calls != 0 {
+		t.Fatalf("expected no repository access for rejected calls, got %d", repo.calls)
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"notification-service/internal/application/services"
+	"notification-service/internal/interface/http/handler"
+	"notification-service/internal/interface/http/middleware"
+	"notification-service/pkg/logger"
+)
+
+// SetupWebhookRoutes mounts the webhook subscription API. Subscriptions hold
+// signing secrets, so every route requires the service-to-service token and
+// none is mounted when internal HTTP trust is disabled. Call it after
+// SetupNotificationRoutes, which installs the global middleware.
+func SetupWebhookRoutes(router *gin.Engine, webhookService *services.WebhookService, trustMode, internalServiceToken string, logger *logger.Logger) {
+	if trustMode == "disabled" {
+		return
+	}
+	webhookHandler := handler.NewWebhookHandler(webhookService, logger)
+
+	webhooks := router.Group("/internal/webhooks")
+	webhooks.Use(middleware.ServiceAuthMiddleware(internalServiceToken))
+	{
+		webhooks.POST("", webhookHandler.CreateWebhook)
+		webhooks.GET("", webhookHandler.ListWebhooks)
+		webhooks.GET("/:id", webhookHandler.GetWebhook)
+		webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+		webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", webhookHandler.ListWebhookDeliveries)
+	}
+}
@@ -3,6 +3,7 @@ package validators
 import (
 	"fmt"
 	"notification-service/internal/application/dto"
+	"notification-service/internal/domain/entities"
 	"strings"
 )
 
@@ -21,15 +22,7 @@ func (v *NotificationValidator) ValidateCreateNotificationRequest(req *dto.Creat
 		return fmt.Errorf("notif type is required")
 	}
 
-	validTypes := map[string]bool{
-		"post_created":  true,
-		"post_updated":  true,
-		"post_deleted":  true,
-		"user_followed": true,
-		"comment_added": true,
-		"system_alert":  true}
-
-	if !validTypes[req.Type] {
+	if !entities.NotificationType(req.Type).IsValid() {
 		return fmt.Errorf("invalid notif type: %s", req.Type)
 	}
 
@@ -49,10 +42,43 @@ func (v *NotificationValidator) ValidateCreateNotificationRequest(req *dto.Creat
 		return fmt.Errorf("message must be less than 1000 characters")
 	}
 
+	if req.Priority != "" && !entities.NotificationPriority(req.Priority).IsValid() {
+		return fmt.Errorf("invalid notif priority: %s", req.Priority)
+	}
+
 	return nil
 
 }
 
+func (v *NotificationValidator) ValidateListNotificationsRequest(req *dto.ListNotificationsRequest) error {
+	if req.Type != "" && !entities.NotificationType(req.Type).IsValid() {
+		return fmt.Errorf("invalid notif type: %s", req.Type)
+	}
+	if req.Priority != "" && !entities.NotificationPriority(req.Priority).IsValid() {
+		return fmt.Errorf("invalid notif priority: %s", req.Priority)
+	}
+	return nil
+}
+
+func (v *NotificationValidator) ValidateMarkAsUnreadRequest(req *dto.MarkAsUnreadRequest) error {
+	if len(req.NotificationIDs) == 0 {
+		return fmt.Errorf("notification_ids must be provided")
+	}
+
+	const maxNotificationIDs = 100
+	if len(req.NotificationIDs) > maxNotificationIDs {
+		return fmt.Errorf("notification_ids must not exceed %d entries", maxNotificationIDs)
+	}
+
+	for _, id := range req.NotificationIDs {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("notif id cannot be empty")
+		}
+	}
+
+	return nil
+}
+
 func (v *NotificationValidator) ValidateMarkAsReadRequest(req *dto.MarkAsReadRequest) error {
 	if !req.MarkAll && len(req.NotificationIDs) == 0 {
 		return fmt.Errorf("either mark_all must be true or notification_ids must be provided")
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -14,12 +16,22 @@ import (
 
 	"notification-service/internal/application/services"
 	"notification-service/internal/config"
+	"notification-service/internal/domain/entities"
+	"notification-service/internal/domain/repositories"
 	postgres "notification-service/internal/infrastructure"
+	"notification-service/internal/infrastructure/cache"
+	"notification-service/internal/infrastructure/email"
 	"notification-service/internal/infrastructure/rabbitmq"
+	"notification-service/internal/infrastructure/stream"
+	"notification-service/internal/infrastructure/users"
+	"notification-service/internal/infrastructure/webhook"
 	"notification-service/internal/interface/routes"
 	"notification-service/pkg/auth"
+	"notification-service/pkg/health"
 	"notification-service/pkg/logger"
 	"notification-service/pkg/metrics"
+	"notification-service/pkg/retry"
+	"notification-service/pkg/tracing"
 )
 
 func main() {
@@ -28,12 +40,31 @@ func main() {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	appLogger := logger.New(cfg.LogLevel)
-	metrics.Init()
+	appLogger := logger.NewWithOptions(logger.Options{Service: "notification-service", Level: cfg.LogLevel, Format: cfg.LogFormat})
 
-	db, err := postgres.NewConntection(cfg.Database)
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{ServiceName: "notification-service", Endpoint: cfg.Tracing.Endpoint, SampleRatio: cfg.Tracing.SampleRatio})
 	if err != nil {
-		appLogger.Fatalf("failed to connect to db: %v", err)
+		appLogger.Fatal("Failed to initialize tracing: " + err.Error())
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			appLogger.Warn("Failed to flush traces", logger.Err(err))
+		}
+	}()
+	if cfg.MetricsEnabled {
+		metrics.Init()
+	}
+
+	var db *sql.DB
+	err = retry.Do(context.Background(), startupRetry(cfg.StartupRetry), appLogger, "postgres", func(context.Context) error {
+		var err error
+		db, err = postgres.NewConntection(cfg.Database)
+		return err
+	})
+	if err != nil {
+		appLogger.Fatal("failed to connect to db", logger.Err(err))
 	}
 	defer db.Close()
 
@@ -42,40 +73,107 @@ func main() {
 	}
 
 	notificationRepo := postgres.NewNotificationRepository(db)
-	notificationService := services.NewNotificationService(notificationRepo, appLogger)
+	unreadCountCache := cache.NewUnreadCountCache(time.Duration(cfg.Notification.UnreadCountCacheTTLMs) * time.Millisecond)
+	notificationHub := stream.NewHub(16)
+	preferenceRepo := postgres.NewPreferenceRepository(db)
+	var emailSender repositories.EmailSender
+	if cfg.Email.SMTPHost != "" {
+		emailSender = email.NewSMTPSender(cfg.Email)
+	} else {
+		appLogger.Info("SMTP_HOST not set, email notifs disabled")
+	}
+	var followerDirectory repositories.FollowerDirectory
+	if cfg.UserService.URL != "" && cfg.UserService.InternalToken != "" {
+		followerDirectory = users.NewClient(cfg.UserService)
+	} else {
+		appLogger.Info("USER_SERVICE_URL or INTERNAL_SERVICE_TOKEN not set, follower notifs disabled")
+	}
+	fanout := services.FanoutConfig{PageSize: cfg.Notification.BatchSize, MaxRecipients: cfg.Notification.MaxFanout}
+	digest := services.DigestConfig{
+		HourlyWindow: time.Duration(cfg.Notification.DigestHourlyWindowMinutes) * time.Minute,
+		DailyWindow:  time.Duration(cfg.Notification.DigestDailyWindowMinutes) * time.Minute,
+		BatchSize:    cfg.Notification.DigestBatchSize,
+	}
+	notificationService := services.NewNotificationService(notificationRepo, preferenceRepo, unreadCountCache, notificationHub, emailSender, followerDirectory, fanout, digest, appLogger)
 	rabbitMQClient := rabbitmq.NewClient(cfg.RabbitMQ, appLogger)
 
-	if err := rabbitMQClient.Connect(); err != nil {
+	connectRabbit := func(context.Context) error { return rabbitMQClient.Connect() }
+	if err := retry.Do(context.Background(), startupRetry(cfg.StartupRetry), appLogger, "rabbitmq", connectRabbit); err != nil {
 		appLogger.Fatal("failed to connect to rabbit " + err.Error())
 	}
 	defer rabbitMQClient.Close()
 
-	messageHanlder := func(routingKey string, body []byte) error {
-		switch routingKey {
-		case "post.created":
-			return notificationService.ProcessPostCreatedEvent(context.Background(), body)
-		case "post.updated":
-			return notificationService.ProcessPostUpdatedEvent(context.Background(), body)
-		case "post.deleted":
-			return notificationService.ProcessPostDeletedEvent(context.Background(), body)
-		default:
-			appLogger.Warn("received unsupported routing key: " + routingKey)
-			return nil
-		}
+	messageHanlder := func(ctx context.Context, routingKey, messageID string, body []byte) error {
+		return notificationService.ProcessEvent(ctx, routingKey, messageID, body)
 	}
 
-	if err := rabbitMQClient.StartConsuming(messageHanlder); err != nil {
+	consumerCtx, stopConsuming := context.WithCancel(context.Background())
+	defer stopConsuming()
+
+	if err := rabbitMQClient.StartConsuming(consumerCtx, messageHanlder); err != nil {
 		appLogger.Fatal("failed to start consuming messages " + err.Error())
 	}
 
+	healthChecker := health.NewChecker().
+		Register("postgres", db.PingContext).
+		Register("rabbitmq", func(context.Context) error {
+			if !rabbitMQClient.IsConnected() {
+				return errors.New("rabbitmq consumer is not connected")
+			}
+			return nil
+		})
+
+	deadLetterQueues := map[string]repositories.DeadLetterQueue{
+		services.DefaultDeadLetterQueue: rabbitmq.NewDeadLetters(rabbitMQClient),
+	}
+
+	// Webhooks get their own consumer and queue, so a slow subscriber only
+	// delays other webhooks.
+	var webhookService *services.WebhookService
+	var webhookClient *rabbitmq.Client
+	if cfg.Webhook.Enabled {
+		webhookService = services.NewWebhookService(
+			postgres.NewWebhookRepository(db),
+			webhook.NewSender(time.Duration(cfg.Webhook.TimeoutMs)*time.Millisecond),
+			services.WebhookDeliveryConfig{
+				MaxAttempts:    cfg.Webhook.MaxAttempts,
+				InitialBackoff: time.Duration(cfg.Webhook.InitialBackoffMs) * time.Millisecond,
+				MaxBackoff:     time.Duration(cfg.Webhook.MaxBackoffMs) * time.Millisecond,
+			},
+			appLogger,
+		)
+		webhookClient = rabbitmq.NewClient(cfg.Webhook.Consumer(cfg.RabbitMQ, entities.WebhookEvents), appLogger)
+		connectWebhooks := func(context.Context) error { return webhookClient.Connect() }
+		if err := retry.Do(context.Background(), startupRetry(cfg.StartupRetry), appLogger, "rabbitmq webhooks", connectWebhooks); err != nil {
+			appLogger.Fatal("failed to connect webhook consumer to rabbit " + err.Error())
+		}
+		defer webhookClient.Close()
+		deadLetterQueues["webhooks"] = rabbitmq.NewDeadLetters(webhookClient)
+
+		if err := webhookClient.StartConsuming(consumerCtx, webhookService.ProcessEvent); err != nil {
+			appLogger.Fatal("failed to start consuming webhook events " + err.Error())
+		}
+		healthChecker.Register("rabbitmq_webhooks", func(context.Context) error {
+			if !webhookClient.IsConnected() {
+				return errors.New("rabbitmq webhook consumer is not connected")
+			}
+			return nil
+		})
+	} else {
+		appLogger.Info("WEBHOOKS_ENABLED is false, webhooks disabled")
+	}
+
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(tracing.GinMiddleware())
 	router.Use(metrics.GinMiddleware("notification-service"))
-	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	if cfg.MetricsEnabled {
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
 
 	// Verifies access tokens issued by auth-service. nil when running in
 	// insecure_dev with no JWT secret, in which case the X-User-ID header
@@ -85,7 +183,11 @@ func main() {
 		tokenValidator = auth.NewValidator(cfg.JWTSecret)
 	}
 
-	routes.SetupNotificationRoutes(router, notificationService, tokenValidator, cfg.InternalHTTPTrustMode, appLogger)
+	routes.SetupNotificationRoutes(router, notificationService, tokenValidator, cfg.InternalHTTPTrustMode, cfg.UserService.InternalToken, cfg.AccessLog, cfg.CORS, healthChecker, appLogger)
+	if webhookService != nil {
+		routes.SetupWebhookRoutes(router, webhookService, cfg.InternalHTTPTrustMode, cfg.UserService.InternalToken, appLogger)
+	}
+	routes.SetupDeadLetterRoutes(router, services.NewDeadLetterService(deadLetterQueues, appLogger), cfg.InternalHTTPTrustMode, cfg.UserService.InternalToken, appLogger)
 
 	server := &http.Server{
 		Addr:              ":" + cfg.Port,
@@ -96,19 +198,33 @@ func main() {
 	}
 
 	go func() {
-		ticker := time.NewTicker(24 * time.Hour)
+		ticker := time.NewTicker(time.Duration(cfg.Notification.CleanupIntervalHours) * time.Hour)
 		defer ticker.Stop()
 
 		for {
 			select {
 			case <-ticker.C:
-				if err := notificationService.CleanupOldNotifications(context.Background(), cfg.Notification.CleanupDays); err != nil {
+				if _, err := notificationService.CleanupOldNotifications(context.Background(), cfg.Notification.CleanupDays); err != nil {
 					appLogger.Error("failed to cleanup old notifs: " + err.Error())
 				}
 			}
 		}
 	}()
 
+	go func() {
+		ticker := time.NewTicker(time.Duration(cfg.Notification.DigestIntervalMinutes) * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				if err := notificationService.RunDigests(context.Background(), now); err != nil {
+					appLogger.Error("failed to send notif digests: " + err.Error())
+				}
+			}
+		}
+	}()
+
 	go func() {
 		appLogger.Info("notif server starting on port " + cfg.Port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -122,12 +238,35 @@ func main() {
 
 	appLogger.Info("shutting down server...")
 
+	// Stop taking new deliveries first so the in-flight one can be acked
+	// before the connection is closed.
+	stopConsuming()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		appLogger.Fatal("server forced to shutdown: " + err.Error())
 	}
+	if err := rabbitMQClient.Wait(ctx); err != nil {
+		appLogger.Warn("rabbit consumer did not stop in time", logger.Err(err))
+	}
+	if webhookClient != nil {
+		if err := webhookClient.Wait(ctx); err != nil {
+			appLogger.Warn("rabbit webhook consumer did not stop in time", logger.Err(err))
+		}
+	}
+	notificationService.WaitForEmails()
 
 	appLogger.Info("server exited")
 }
+
+// startupRetry converts the configured limits for retry.Do.
+func startupRetry(cfg config.StartupRetryConfig) retry.Config {
+	return retry.Config{
+		Attempts:       cfg.Attempts,
+		InitialBackoff: time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+		MaxWait:        time.Duration(cfg.MaxWaitSeconds) * time.Second,
+	}
+}
@@ -46,6 +46,16 @@ func NewValidator(secret string) *Validator {
 // HS256 algorithm (rejecting "none"/alg-confusion), confirms it is an access
 // token, and returns the authenticated user id from the verified claims.
 func (v *Validator) ValidateAccessToken(tokenString string) (string, error) {
+	claims, err := v.ValidateAccessTokenClaims(tokenString)
+	if err != nil {
+		return "", err
+	}
+	return claims.UserID, nil
+}
+
+// ValidateAccessTokenClaims performs the same checks as ValidateAccessToken and
+// returns the full verified claims.
+func (v *Validator) ValidateAccessTokenClaims(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Only accept HMAC-SHA256; never trust the alg header to pick the method.
 		method, ok := token.Method.(*jwt.SigningMethodHMAC)
@@ -55,22 +65,22 @@ func (v *Validator) ValidateAccessToken(tokenString string) (string, error) {
 		return v.secret, nil
 	})
 	if err != nil {
-		return "", fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
 	if !token.Valid {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(*Claims)
 	if !ok {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 	if claims.Type != accessTokenType {
-		return "", ErrWrongTokenType
+		return nil, ErrWrongTokenType
 	}
 	if claims.UserID == "" {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
-	return claims.UserID, nil
+	return claims, nil
 }
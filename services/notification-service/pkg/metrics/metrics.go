@@ -12,9 +12,21 @@ import (
 )
 
 var (
-	reg     = prometheus.NewRegistry()
-	httpReq *prometheus.CounterVec
-	httpDur *prometheus.HistogramVec
+	reg          = prometheus.NewRegistry()
+	httpReq      *prometheus.CounterVec
+	httpDur      *prometheus.HistogramVec
+	httpInFlight *prometheus.GaugeVec
+
+	rabbitMessages *prometheus.CounterVec
+)
+
+// Outcomes recorded by RecordMessage. A message is consumed once, rejected on
+// every failed processing attempt, and finally acked or dead-lettered.
+const (
+	MessageConsumed     = "consumed"
+	MessageAcked        = "acked"
+	MessageRejected     = "rejected"
+	MessageDeadLettered = "dead_lettered"
 )
 
 // Init registers collectors and HTTP metrics for this process.
@@ -32,12 +44,26 @@ func Init() {
 		Help:      "HTTP request duration in seconds.",
 		Buckets:   prometheus.DefBuckets,
 	}, []string{"service", "method", "route"})
+	httpInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "microblog",
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	}, []string{"service", "method", "route"})
+	rabbitMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "microblog",
+		Subsystem: "rabbitmq",
+		Name:      "messages_total",
+		Help:      "RabbitMQ messages handled by the consumer, by outcome.",
+	}, []string{"routing_key", "outcome"})
 
 	reg.MustRegister(
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 		httpReq,
 		httpDur,
+		httpInFlight,
+		rabbitMessages,
 	)
 }
 
@@ -46,17 +72,36 @@ func Handler() http.Handler {
 	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
 }
 
-// GinMiddleware records request counts and latency after routing.
+// RecordMessage counts a consumed message's outcome (one of the Message*
+// constants).
+func RecordMessage(routingKey, outcome string) {
+	if rabbitMessages == nil {
+		return
+	}
+	rabbitMessages.WithLabelValues(routingKey, outcome).Inc()
+}
+
+// GinMiddleware records request counts, latency and in-flight requests after
+// routing. It is a no-op when Init was not called (METRICS_ENABLED=false).
 func GinMiddleware(service string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if httpReq == nil {
+			c.Next()
+			return
+		}
+
 		start := time.Now()
-		c.Next()
-		status := strconv.Itoa(c.Writer.Status())
 		route := c.FullPath()
 		if route == "" {
 			route = c.Request.URL.Path
 		}
 		method := c.Request.Method
+		inFlight := httpInFlight.WithLabelValues(service, method, route)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		c.Next()
+		status := strconv.Itoa(c.Writer.Status())
 		httpReq.WithLabelValues(service, method, route, status).Inc()
 		httpDur.WithLabelValues(service, method, route).Observe(time.Since(start).Seconds())
 	}
@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"post-service/interfaces/http/middleware"
 	"post-service/interfaces/validators"
 	"post-service/internal/application/dto"
 	"post-service/internal/application/errors"
 	"post-service/internal/application/services"
+	"post-service/pkg/health"
 	"post-service/pkg/logger"
 	"post-service/pkg/utils"
 )
@@ -16,13 +19,15 @@ import (
 type PostHandler struct {
 	postService *services.PostService
 	validator   *validators.PostValidator
+	health      *health.Checker
 	logger      *logger.Logger
 }
 
-func NewPostHandler(postService *services.PostService, logger *logger.Logger) *PostHandler {
+func NewPostHandler(postService *services.PostService, checker *health.Checker, logger *logger.Logger) *PostHandler {
 	return &PostHandler{
 		postService: postService,
 		validator:   validators.NewPostValidator(),
+		health:      checker,
 		logger:      logger,
 	}
 }
@@ -42,7 +47,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetHeader("X-User-ID")
+	userID := c.GetString(middleware.ContextUserIDKey)
 	if userID == "" {
 		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
 		return
@@ -64,7 +69,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 func (h *PostHandler) GetPost(c *gin.Context) {
 	id := c.Param("id")
-	userID := c.GetHeader("X-User-ID")
+	userID := c.GetString(middleware.ContextUserIDKey)
 
 	if id == "" {
 		utils.ErrorResponse(c, errors.ErrInvalidRequest)
@@ -82,18 +87,23 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 		return
 	}
 
+	if wantsHTML(c) {
+		h.postService.RenderContentHTML(response)
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Post retrieved successfully", response)
 }
 
 func (h *PostHandler) GetPostBySlug(c *gin.Context) {
 	slug := c.Param("slug")
+	userID := c.GetString(middleware.ContextUserIDKey)
 
 	if slug == "" {
 		utils.ErrorResponse(c, errors.ErrInvalidRequest)
 		return
 	}
 
-	response, err := h.postService.GetPostBySlug(c.Request.Context(), slug)
+	response, err := h.postService.GetPostBySlug(c.Request.Context(), slug, userID)
 	if err != nil {
 		if postErr, ok := err.(*errors.PostError); ok {
 			utils.ErrorResponse(c, postErr)
@@ -104,12 +114,193 @@ func (h *PostHandler) GetPostBySlug(c *gin.Context) {
 		return
 	}
 
+	if wantsHTML(c) {
+		h.postService.RenderContentHTML(response)
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Post retrieved successfully", response)
 }
 
+func (h *PostHandler) ClonePost(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(middleware.ContextUserIDKey)
+
+	if id == "" || userID == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.postService.ClonePost(c.Request.Context(), id, userID)
+	if err != nil {
+		if postErr, ok := err.(*errors.PostError); ok {
+			utils.ErrorResponse(c, postErr)
+		} else {
+			h.logger.Error("Unexpected error in clone post: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Post cloned successfully", response)
+}
+
+func (h *PostHandler) ArchivePost(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(middleware.ContextUserIDKey)
+
+	if id == "" || userID == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.postService.ArchivePost(c.Request.Context(), id, userID)
+	if err != nil {
+		if postErr, ok := err.(*errors.PostError); ok {
+			utils.ErrorResponse(c, postErr)
+		} else {
+			h.logger.Error("Unexpected error in archive post: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post archived successfully", response)
+}
+
+// LikePost likes a post for the caller. Liking it again changes nothing.
+func (h *PostHandler) LikePost(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(middleware.ContextUserIDKey)
+
+	if id == "" || userID == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.postService.LikePost(c.Request.Context(), id, userID)
+	if err != nil {
+		if postErr, ok := err.(*errors.PostError); ok {
+			utils.ErrorResponse(c, postErr)
+		} else {
+			h.logger.Error("Unexpected error in like post: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post liked successfully", response)
+}
+
+// UnlikePost removes the caller's like, if any.
+func (h *PostHandler) UnlikePost(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(middleware.ContextUserIDKey)
+
+	if id == "" || userID == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.postService.UnlikePost(c.Request.Context(), id, userID)
+	if err != nil {
+		if postErr, ok := err.(*errors.PostError); ok {
+			utils.ErrorResponse(c, postErr)
+		} else {
+			h.logger.Error("Unexpected error in unlike post: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post unliked successfully", response)
+}
+
+// GetPostByPreviewToken serves a draft preview link. It needs no login: the
+// token is the credential.
+func (h *PostHandler) GetPostByPreviewToken(c *gin.Context) {
+	token := c.Param("token")
+
+	if token == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.postService.GetPostByPreviewToken(c.Request.Context(), token)
+	if err != nil {
+		if postErr, ok := err.(*errors.PostError); ok {
+			utils.ErrorResponse(c, postErr)
+		} else {
+			h.logger.Error("Unexpected error in get post by preview token: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	if wantsHTML(c) {
+		h.postService.RenderContentHTML(response)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post retrieved successfully", response)
+}
+
+func (h *PostHandler) GeneratePreviewToken(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(middleware.ContextUserIDKey)
+
+	if id == "" || userID == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	var req dto.CreatePreviewTokenRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Warn("Invalid preview token request: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrInvalidRequest)
+			return
+		}
+	}
+
+	ttl := time.Duration(req.ExpiresInHours) * time.Hour
+	response, err := h.postService.GeneratePreviewToken(c.Request.Context(), id, userID, ttl)
+	if err != nil {
+		if postErr, ok := err.(*errors.PostError); ok {
+			utils.ErrorResponse(c, postErr)
+		} else {
+			h.logger.Error("Unexpected error in generate preview token: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Preview token generated successfully", response)
+}
+
+func (h *PostHandler) RevokePreviewToken(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString(middleware.ContextUserIDKey)
+
+	if id == "" || userID == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	if err := h.postService.RevokePreviewToken(c.Request.Context(), id, userID); err != nil {
+		if postErr, ok := err.(*errors.PostError); ok {
+			utils.ErrorResponse(c, postErr)
+		} else {
+			h.logger.Error("Unexpected error in revoke preview token: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Preview token revoked successfully", nil)
+}
+
 func (h *PostHandler) UpdatePost(c *gin.Context) {
 	id := c.Param("id")
-	userID := c.GetHeader("X-User-ID")
+	userID := c.GetString(middleware.ContextUserIDKey)
 
 	if id == "" || userID == "" {
 		utils.ErrorResponse(c, errors.ErrInvalidRequest)
@@ -146,7 +337,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 
 func (h *PostHandler) DeletePost(c *gin.Context) {
 	id := c.Param("id")
-	userID := c.GetHeader("X-User-ID")
+	userID := c.GetString(middleware.ContextUserIDKey)
 
 	if id == "" || userID == "" {
 		utils.ErrorResponse(c, errors.ErrInvalidRequest)
@@ -167,6 +358,42 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Post deleted successfully", nil)
 }
 
+// BulkDeletePosts deletes up to dto.MaxBulkDeleteIDs of the caller's posts.
+// Ids the caller does not own are skipped and reported as not deleted.
+func (h *PostHandler) BulkDeletePosts(c *gin.Context) {
+	userID := c.GetString(middleware.ContextUserIDKey)
+	if userID == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	var req dto.BulkDeletePostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid bulk delete request: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	if err := h.validator.ValidateBulkDeletePostsRequest(&req); err != nil {
+		h.logger.Warn("Bulk delete validation failed: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.postService.DeleteMany(c.Request.Context(), req.IDs, userID)
+	if err != nil {
+		if postErr, ok := err.(*errors.PostError); ok {
+			utils.ErrorResponse(c, postErr)
+		} else {
+			h.logger.Error("Unexpected error in bulk delete posts: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Posts deleted successfully", response)
+}
+
 func (h *PostHandler) ListPosts(c *gin.Context) {
 	var req dto.ListPostsRequest
 
@@ -181,6 +408,12 @@ func (h *PostHandler) ListPosts(c *gin.Context) {
 		req.Limit = 20
 	}
 
+	if err := h.validator.ValidateListPostsRequest(&req); err != nil {
+		h.logger.Warn("List posts validation failed: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
 	response, err := h.postService.ListPosts(c.Request.Context(), &req)
 	if err != nil {
 		if postErr, ok := err.(*errors.PostError); ok {
@@ -215,7 +448,7 @@ func (h *PostHandler) GetUserPosts(c *gin.Context) {
 		req.Limit = 20
 	}
 
-	response, err := h.postService.GetUserPosts(c.Request.Context(), userID, &req)
+	response, err := h.postService.GetUserPosts(c.Request.Context(), userID, c.GetString(middleware.ContextUserIDKey), &req)
 	if err != nil {
 		if postErr, ok := err.(*errors.PostError); ok {
 			utils.ErrorResponse(c, postErr)
@@ -264,7 +497,7 @@ func (h *PostHandler) SearchPosts(c *gin.Context) {
 }
 
 func (h *PostHandler) GetStats(c *gin.Context) {
-	userID := c.GetHeader("X-User-ID") // Optional for public stats
+	userID := c.GetString(middleware.ContextUserIDKey) // Optional for public stats
 
 	response, err := h.postService.GetStats(c.Request.Context(), userID)
 	if err != nil {
@@ -280,9 +513,38 @@ func (h *PostHandler) GetStats(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Post statistics retrieved successfully", response)
 }
 
+// HealthCheck is the liveness probe: it answers 200 whenever the process is
+// up, without touching any dependency.
 func (h *PostHandler) HealthCheck(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Post service is healthy", gin.H{
 		"service": "post-service",
 		"status":  "running",
 	})
 }
+
+// Readiness is the readiness probe: it checks the backing dependencies and
+// returns 503 when any of them is unreachable.
+func (h *PostHandler) Readiness(c *gin.Context) {
+	dependencies, err := h.health.Run(c.Request.Context())
+	if err != nil {
+		h.logger.Warn("Readiness check failed: " + err.Error())
+		utils.SuccessResponse(c, http.StatusServiceUnavailable, "Post service is not ready", gin.H{
+			"service":      "post-service",
+			"status":       health.StatusUnhealthy,
+			"dependencies": dependencies,
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post service is ready", gin.H{
+		"service":      "post-service",
+		"status":       health.StatusHealthy,
+		"dependencies": dependencies,
+	})
+}
+
+// wantsHTML reports whether the caller asked for rendered content with
+// render=html. Without it responses carry the raw Markdown only.
+func wantsHTML(c *gin.Context) bool {
+	return c.Query("render") == "html"
+}
@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"net/http"
+	"crypto/subtle"
 
 	"post-service/internal/application/errors"
 	"post-service/pkg/utils"
@@ -9,45 +9,52 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+const (
+	// InternalTokenHeader carries the shared secret the gateway attaches to
+	// every request it forwards.
+	InternalTokenHeader = "X-Internal-Token"
+
+	// ContextUserIDKey holds the caller identity once it has been accepted.
+	ContextUserIDKey = "userID"
+
+	trustModeInsecureDev = "insecure_dev"
+)
+
+// AuthMiddleware requires a caller identity. X-User-ID is honoured only from a
+// trusted caller (see trustedCaller), so reaching the service directly cannot
+// impersonate a user.
+func AuthMiddleware(token, trustMode string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get user ID from header (set by API Gateway)
 		userID := c.GetHeader("X-User-ID")
-		if userID == "" {
+		if userID == "" || !trustedCaller(c, token, trustMode) {
 			utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
 			c.Abort()
 			return
 		}
 
-		// Set user ID in context for handlers to use
-		c.Set("userID", userID)
+		c.Set(ContextUserIDKey, userID)
 		c.Next()
 	}
 }
 
-func OptionalAuthMiddleware() gin.HandlerFunc {
+// OptionalAuthMiddleware records the caller identity when a trusted caller
+// sends one and otherwise treats the request as anonymous.
+func OptionalAuthMiddleware(token, trustMode string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get user ID from header (optional)
-		userID := c.GetHeader("X-User-ID")
-		if userID != "" {
-			c.Set("userID", userID)
+		if userID := c.GetHeader("X-User-ID"); userID != "" && trustedCaller(c, token, trustMode) {
+			c.Set(ContextUserIDKey, userID)
 		}
 		c.Next()
 	}
 }
 
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-User-ID")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		}
-
-		c.Next()
+// trustedCaller reports whether X-User-ID may be believed: the caller presents
+// the internal token or, when none is configured, the service runs in
+// insecure_dev mode. Without a token every other mode fails closed.
+func trustedCaller(c *gin.Context, token, trustMode string) bool {
+	if token == "" {
+		return trustMode == trustModeInsecureDev
 	}
+	provided := c.GetHeader(InternalTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
 }
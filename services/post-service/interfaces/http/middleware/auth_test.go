@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testToken = "0123456789abcdef0123456789abcdef"
+
+func serveWithIdentity(handler gin.HandlerFunc, providedToken string) (int, string) {
+	gin.SetMode(gin.TestMode)
+
+	var seen string
+	router := gin.New()
+	router.GET("/posts", handler, func(c *gin.Context) {
+		seen = c.GetString(ContextUserIDKey)
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	req.Header.Set("X-User-ID", "user-1")
+	if providedToken != "" {
+		req.Header.Set(InternalTokenHeader, providedToken)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code, seen
+}
+
+func TestAuthMiddlewareAcceptsGatewayCalls(t *testing.T) {
+	code, userID := serveWithIdentity(AuthMiddleware(testToken, "private_network"), testToken)
+	if code != http.StatusNoContent || userID != "user-1" {
+		t.Fatalf("expected the gateway's identity to be accepted, got %d %q", code, userID)
+	}
+
+	code, userID = serveWithIdentity(AuthMiddleware("", "insecure_dev"), "")
+	if code != http.StatusNoContent || userID != "user-1" {
+		t.Fatalf("expected insecure_dev to trust X-User-ID without a token, got %d %q", code, userID)
+	}
+}
+
+func TestAuthMiddlewareRejectsUntrustedCalls(t *testing.T) {
+	cases := []struct {
+		name, token, trustMode, provided string
+	}{
+		{"missing token", testToken, "private_network", ""},
+		{"wrong token", testToken, "insecure_dev", "wrong"},
+		{"no configured token", "", "private_network", ""},
+	}
+	for _, tc := range cases {
+		if code, _ := serveWithIdentity(AuthMiddleware(tc.token, tc.trustMode), tc.provided); code != http.StatusForbidden {
+			t.Fatalf("%s: expected 403, got %d", tc.name, code)
+		}
+	}
+}
+
+func TestOptionalAuthMiddlewareIgnoresUntrustedIdentity(t *testing.T) {
+	code, userID := serveWithIdentity(OptionalAuthMiddleware(testToken, "private_network"), "")
+	if code != http.StatusNoContent || userID != "" {
+		t.Fatalf("expected an anonymous request, got %d %q", code, userID)
+	}
+
+	if _, userID = serveWithIdentity(OptionalAuthMiddleware(testToken, "private_network"), testToken); userID != "user-1" {
+		t.Fatalf("expected the trusted identity to be recorded, got %q", userID)
+	}
+}
@@ -1,16 +1,14 @@
 package middleware
 
 import (
-	"strconv"
-
 	"post-service/internal/application/errors"
-	"post-service/pkg/logger"
+	logpkg "post-service/pkg/logger"
 	"post-service/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func ErrorHandler(logger *logger.Logger) gin.HandlerFunc {
+func ErrorHandler(logger *logpkg.Logger) gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		if err, ok := recovered.(string); ok {
 			logger.Error("Panic recovered: " + err)
@@ -25,12 +23,13 @@ func ErrorHandler(logger *logger.Logger) gin.HandlerFunc {
 	})
 }
 
-func RequestLogger(logger *logger.Logger) gin.HandlerFunc {
+func RequestLogger(logger *logpkg.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.Info(
-			"Request: " + param.Method + " " + param.Path +
-				" | Status: " + strconv.Itoa(param.StatusCode) +
-				" | Latency: " + param.Latency.String(),
+		logger.Info("Request: "+param.Method+" "+param.Path,
+			logpkg.F("status", param.StatusCode),
+			logpkg.F("latency_ms", param.Latency.Milliseconds()),
+			logpkg.F("request_id", param.Request.Header.Get("X-Request-ID")),
+			logpkg.F("user_id", param.Request.Header.Get("X-User-ID")),
 		)
 		return ""
 	})
@@ -7,20 +7,23 @@ import (
 	"post-service/interfaces/http/middleware"
 	"post-service/internal/application/services"
 
+	"post-service/internal/config"
+	"post-service/pkg/health"
 	"post-service/pkg/logger"
 )
 
-func SetupPostRoutes(router *gin.Engine, postService *services.PostService, logger *logger.Logger) {
+func SetupPostRoutes(router *gin.Engine, postService *services.PostService, internalServiceToken, trustMode string, cors config.CORSConfig, checker *health.Checker, logger *logger.Logger) {
 	// Initialize handlers
-	postHandler := handlers.NewPostHandler(postService, logger)
+	postHandler := handlers.NewPostHandler(postService, checker, logger)
 
 	// Add global middleware
 	router.Use(middleware.ErrorHandler(logger))
 	router.Use(middleware.RequestLogger(logger))
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORS(cors))
 
 	// Health check (no auth required)
 	router.GET("/health", postHandler.HealthCheck)
+	router.GET("/ready", postHandler.Readiness)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
@@ -28,20 +31,28 @@ func SetupPostRoutes(router *gin.Engine, postService *services.PostService, logg
 		posts := v1.Group("/posts")
 		{
 			// Public routes (no auth required)
-			posts.GET("", postHandler.ListPosts)                 // List published posts
-			posts.GET("/search", postHandler.SearchPosts)        // Search published posts
-			posts.GET("/stats", postHandler.GetStats)            // Public post statistics
-			posts.GET("/slug/:slug", postHandler.GetPostBySlug)  // Get post by slug (published only)
-			posts.GET("/user/:userId", postHandler.GetUserPosts) // Get user's published posts
+			posts.GET("", postHandler.ListPosts)                                                                                     // List published posts
+			posts.GET("/search", postHandler.SearchPosts)                                                                            // Search published posts
+			posts.GET("/stats", middleware.OptionalAuthMiddleware(internalServiceToken, trustMode), postHandler.GetStats)            // Public post statistics
+			posts.GET("/slug/:slug", middleware.OptionalAuthMiddleware(internalServiceToken, trustMode), postHandler.GetPostBySlug)  // Get post by slug (published, or the caller's own draft)
+			posts.GET("/user/:userId", middleware.OptionalAuthMiddleware(internalServiceToken, trustMode), postHandler.GetUserPosts) // Get user's posts (published, or any status for the owner)
+			posts.GET("/preview/:token", postHandler.GetPostByPreviewToken)                                                          // Get a post by draft preview token
 
 			// Protected routes (auth required)
 			protected := posts.Group("")
-			protected.Use(middleware.AuthMiddleware())
+			protected.Use(middleware.AuthMiddleware(internalServiceToken, trustMode))
 			{
-				protected.POST("", postHandler.CreatePost)       // Create new post
-				protected.GET("/:id", postHandler.GetPost)       // Get post by ID (own posts or published)
-				protected.PUT("/:id", postHandler.UpdatePost)    // Update own post
-				protected.DELETE("/:id", postHandler.DeletePost) // Delete own post
+				protected.POST("", postHandler.CreatePost)                             // Create new post
+				protected.GET("/:id", postHandler.GetPost)                             // Get post by ID (own posts or published)
+				protected.PUT("/:id", postHandler.UpdatePost)                          // Update own post
+				protected.DELETE("/:id", postHandler.DeletePost)                       // Delete own post
+				protected.POST("/bulk-delete", postHandler.BulkDeletePosts)            // Delete many own posts, skipping ones not owned
+				protected.POST("/:id/clone", postHandler.ClonePost)                    // Copy own post into a new draft
+				protected.POST("/:id/archive", postHandler.ArchivePost)                // Retire own post without deleting it
+				protected.POST("/:id/like", postHandler.LikePost)                      // Like a post; liking again is a no-op
+				protected.DELETE("/:id/like", postHandler.UnlikePost)                  // Remove the caller's like
+				protected.POST("/:id/preview-token", postHandler.GeneratePreviewToken) // Issue a draft preview link, revoking any earlier one
+				protected.DELETE("/:id/preview-token", postHandler.RevokePreviewToken) // Revoke the draft preview link
 			}
 		}
 	}
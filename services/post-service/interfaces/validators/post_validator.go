@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"post-service/internal/application/dto"
+	"post-service/internal/domain/entities"
 )
 
 type PostValidator struct{}
@@ -37,6 +40,14 @@ func (v *PostValidator) ValidateCreatePostRequest(req *dto.CreatePostRequest) er
 		}
 	}
 
+	if req.CoverImageURL != "" && !isValidURL(req.CoverImageURL) {
+		return fmt.Errorf("invalid cover image URL")
+	}
+
+	if err := v.validateExcerpt(req.Excerpt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -65,9 +76,23 @@ func (v *PostValidator) ValidateUpdatePostRequest(req *dto.UpdatePostRequest) er
 		}
 	}
 
+	if req.CoverImageURL != nil && *req.CoverImageURL != "" && !isValidURL(*req.CoverImageURL) {
+		return fmt.Errorf("invalid cover image URL")
+	}
+
+	if req.Excerpt != nil {
+		if err := v.validateExcerpt(*req.Excerpt); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func (v *PostValidator) ValidateListPostsRequest(req *dto.ListPostsRequest) error {
+	return v.ValidateDateRange(req.From, req.To)
+}
+
 func (v *PostValidator) ValidateSearchPostsRequest(req *dto.SearchPostsRequest) error {
 	if strings.TrimSpace(req.Query) == "" {
 		return fmt.Errorf("search query is required")
@@ -81,6 +106,29 @@ func (v *PostValidator) ValidateSearchPostsRequest(req *dto.SearchPostsRequest)
 		return fmt.Errorf("search query must be less than 100 characters")
 	}
 
+	return v.ValidateDateRange(req.From, req.To)
+}
+
+// ValidateDateRange checks that a from/to filter is not inverted.
+func (v *PostValidator) ValidateBulkDeletePostsRequest(req *dto.BulkDeletePostsRequest) error {
+	if len(req.IDs) == 0 {
+		return fmt.Errorf("ids must be provided")
+	}
+	if len(req.IDs) > dto.MaxBulkDeleteIDs {
+		return fmt.Errorf("ids must not exceed %d entries", dto.MaxBulkDeleteIDs)
+	}
+	for _, id := range req.IDs {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("post id cannot be empty")
+		}
+	}
+	return nil
+}
+
+func (v *PostValidator) ValidateDateRange(from, to *time.Time) error {
+	if from != nil && to != nil && from.After(*to) {
+		return fmt.Errorf("from must not be after to")
+	}
 	return nil
 }
 
@@ -111,3 +159,17 @@ func (v *PostValidator) validateSlug(slug string) error {
 
 	return nil
 }
+
+func (v *PostValidator) validateExcerpt(excerpt string) error {
+	if utf8.RuneCountInString(strings.TrimSpace(excerpt)) > entities.MaxExcerptLength {
+		return fmt.Errorf("excerpt must be at most %d characters", entities.MaxExcerptLength)
+	}
+
+	return nil
+}
+
+// isValidURL accepts absolute http(s) URLs, as user-service does for websites.
+func isValidURL(url string) bool {
+	urlRegex := regexp.MustCompile(`^https?://[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}(/.*)?$`)
+	return urlRegex.MatchString(url)
+}
@@ -5,56 +5,79 @@ import (
 )
 
 type CreatePostRequest struct {
-	Title     string `json:"title" binding:"required,min=1,max=200"`
-	Content   string `json:"content" binding:"required,min=1,max=50000"`
-	Slug      string `json:"slug,omitempty" binding:"omitempty,min=3,max=100"`
-	Published bool   `json:"published,omitempty"`
+	Title         string `json:"title" binding:"required,min=1,max=200"`
+	Content       string `json:"content" binding:"required,min=1,max=50000"`
+	Slug          string `json:"slug,omitempty" binding:"omitempty,min=3,max=100"`
+	Published     bool   `json:"published,omitempty"`
+	CoverImageURL string `json:"cover_image_url,omitempty" binding:"omitempty,max=2048"` // optional http(s) URL
+	Excerpt       string `json:"excerpt,omitempty" binding:"omitempty,max=300"`          // generated from content when empty
 }
 
 type UpdatePostRequest struct {
-	Title     *string `json:"title,omitempty" binding:"omitempty,min=1,max=200"`
-	Content   *string `json:"content,omitempty" binding:"omitempty,min=1,max=50000"`
-	Slug      *string `json:"slug,omitempty" binding:"omitempty,min=3,max=100"`
-	Published *bool   `json:"published,omitempty"`
+	Title         *string `json:"title,omitempty" binding:"omitempty,min=1,max=200"`
+	Content       *string `json:"content,omitempty" binding:"omitempty,min=1,max=50000"`
+	Slug          *string `json:"slug,omitempty" binding:"omitempty,min=3,max=100"`
+	Published     *bool   `json:"published,omitempty"`
+	CoverImageURL *string `json:"cover_image_url,omitempty" binding:"omitempty,max=2048"` // empty removes the cover image
+	Excerpt       *string `json:"excerpt,omitempty" binding:"omitempty,max=300"`          // empty goes back to a generated excerpt
 }
 
 type PostResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	Slug      string    `json:"slug"`
-	Published bool      `json:"published"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content"`
+	Slug          string    `json:"slug"`
+	Status        string    `json:"status"`    // draft, published or archived
+	Published     bool      `json:"published"` // status == published, kept for older clients
+	CoverImageURL string    `json:"cover_image_url,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	LikeCount     int64     `json:"like_count"`
+	// LikedByMe says whether the requesting user likes the post; nil when
+	// the post was read anonymously.
+	LikedByMe *bool `json:"liked_by_me,omitempty"`
+	// ContentHTML is Content rendered from Markdown and sanitized; only set
+	// when the caller asks for render=html.
+	ContentHTML string `json:"content_html,omitempty"`
 }
 
 type PostSummaryResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Title     string    `json:"title"`
-	Slug      string    `json:"slug"`
-	Published bool      `json:"published"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Title         string    `json:"title"`
+	Slug          string    `json:"slug"`
+	Status        string    `json:"status"`    // draft, published or archived
+	Published     bool      `json:"published"` // status == published, kept for older clients
+	CoverImageURL string    `json:"cover_image_url,omitempty"`
+	Excerpt       string    `json:"excerpt"` // author-written, or the start of the content
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type ListPostsRequest struct {
-	Limit         int  `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
-	Offset        int  `form:"offset,default=0" binding:"omitempty,min=0"`
-	PublishedOnly bool `form:"published_only,default=false"`
+	Limit         int        `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
+	Offset        int        `form:"offset,default=0" binding:"omitempty,min=0"`
+	PublishedOnly bool       `form:"published_only,default=false"`
+	From          *time.Time `form:"from"` // RFC3339, inclusive
+	To            *time.Time `form:"to"`   // RFC3339, inclusive
 }
 
 type SearchPostsRequest struct {
-	Query         string `form:"q" binding:"required,min=1"`
-	Limit         int    `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
-	Offset        int    `form:"offset,default=0" binding:"omitempty,min=0"`
-	PublishedOnly bool   `form:"published_only,default=true"`
+	Query         string     `form:"q" binding:"required,min=1"`
+	Limit         int        `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
+	Offset        int        `form:"offset,default=0" binding:"omitempty,min=0"`
+	PublishedOnly bool       `form:"published_only,default=true"`
+	From          *time.Time `form:"from"` // RFC3339, inclusive
+	To            *time.Time `form:"to"`   // RFC3339, inclusive
 }
 
 type UserPostsRequest struct {
 	Limit  int `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
 	Offset int `form:"offset,default=0" binding:"omitempty,min=0"`
+	// Status filters by status; empty means published. Only the owner may
+	// list their drafts or archived posts.
+	Status string `form:"status" binding:"omitempty,oneof=draft published archived"`
 }
 
 type ListPostsResponse struct {
@@ -67,4 +90,43 @@ type ListPostsResponse struct {
 type PostStatsResponse struct {
 	TotalPublishedPosts int64 `json:"total_published_posts"`
 	UserPostsCount      int64 `json:"user_posts_count,omitempty"`
+	UserDraftCount      int64 `json:"user_draft_count,omitempty"`
+	UserScheduledCount  int64 `json:"user_scheduled_count,omitempty"`
+}
+
+type CreatePreviewTokenRequest struct {
+	// ExpiresInHours limits how long the link works; zero means until revoked.
+	ExpiresInHours int `json:"expires_in_hours,omitempty" binding:"omitempty,min=1,max=720"`
+}
+
+// PostLikeResponse is a post's like state after a like or unlike.
+type PostLikeResponse struct {
+	PostID    string `json:"post_id"`
+	LikeCount int64  `json:"like_count"`
+	LikedByMe bool   `json:"liked_by_me"`
+}
+
+type PreviewTokenResponse struct {
+	PostID    string     `json:"post_id"`
+	Token     string     `json:"token"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// MaxBulkDeleteIDs caps how many posts one bulk delete may name.
+const MaxBulkDeleteIDs = 100
+
+type BulkDeletePostsRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// BulkDeleteResult reports one requested id. Deleted is false for posts that
+// do not exist or belong to someone else; those are skipped, not failed.
+type BulkDeleteResult struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+type BulkDeletePostsResponse struct {
+	Results []BulkDeleteResult `json:"results"`
+	Deleted int                `json:"deleted"`
 }
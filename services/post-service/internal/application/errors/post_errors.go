@@ -32,6 +32,8 @@ var (
 	ErrPostListFailed     = NewPostError("POST_LIST_FAILED", "Failed to retrieve posts", http.StatusInternalServerError)
 	ErrPostSearchFailed   = NewPostError("POST_SEARCH_FAILED", "Failed to search posts", http.StatusInternalServerError)
 	ErrPostStatsFailed    = NewPostError("POST_STATS_FAILED", "Failed to retrieve post statistics", http.StatusInternalServerError)
+	ErrPostLikeFailed     = NewPostError("POST_LIKE_FAILED", "Failed to update post like", http.StatusInternalServerError)
+	ErrPostLocked         = NewPostError("POST_LOCKED", "Post was published recently and cannot be edited yet", http.StatusLocked)
 	ErrUnauthorizedAccess = NewPostError("UNAUTHORIZED_ACCESS", "You don't have permission to access this resource", http.StatusForbidden)
 	ErrInvalidRequest     = NewPostError("INVALID_REQUEST", "Invalid request parameters", http.StatusBadRequest)
 	ErrServiceUnavailable = NewPostError("SERVICE_UNAVAILABLE", "Post service temporarily unavailable", http.StatusServiceUnavailable)
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"post-service/internal/application/dto"
+	apperrors "post-service/internal/application/errors"
+	"post-service/pkg/logger"
+	"post-service/pkg/markdown"
+)
+
+func TestCreatePost_SanitizesStoredContent(t *testing.T) {
+	repo := newMockPostRepo()
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	source := "> quote\n\nSee `<script>` <img src=\"x.png\" onerror=\"alert(1)\">\n\n<script>alert(2)</script>"
+	post, err := svc.CreatePost(context.Background(), &dto.CreatePostRequest{Title: "Hello", Content: source}, "author")
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	want := "> quote\n\nSee `<script>` <img src=\"x.png\">"
+	if post.Content != want || repo.posts[post.ID].Content != want {
+		t.Fatalf("expected stored content %q, got %q", want, repo.posts[post.ID].Content)
+	}
+
+	if _, err := svc.CreatePost(context.Background(), &dto.CreatePostRequest{Title: "Empty", Content: "<script>alert(1)</script>"}, "author"); err != apperrors.ErrInvalidPostData {
+		t.Fatalf("expected content that is only a script to be rejected, got %v", err)
+	}
+}
+
+func TestUpdatePost_SanitizesWithConfiguredPolicy(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(time.Hour))
+	sanitizer := markdown.NewSanitizer(markdown.ContentPolicy([]string{"marquee"}))
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, sanitizer, logger.New("info"))
+
+	content := "<marquee>kept</marquee> <iframe src=\"https://example.com\"></iframe>"
+	if _, err := svc.UpdatePost(context.Background(), "post1", &dto.UpdatePostRequest{Content: &content}, "user1"); err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+	if got := repo.posts["post1"].Content; got != "<marquee>kept</marquee>" {
+		t.Fatalf("expected only the configured extra tag to be kept, got %q", got)
+	}
+}
+
+func TestRenderContentHTML_UsesConfiguredPolicy(t *testing.T) {
+	svc := NewPostService(newMockPostRepo(), false, nil, EditLockPolicy{}, markdown.NewSanitizer(markdown.ContentPolicy([]string{"marquee"})), logger.New("info"))
+
+	resp := &dto.PostResponse{Content: "<marquee>kept</marquee>\n\n<script>alert(1)</script>"}
+	svc.RenderContentHTML(resp)
+	if !strings.Contains(resp.ContentHTML, "<marquee>kept</marquee>") || strings.Contains(resp.ContentHTML, "<script") {
+		t.Fatalf("expected the configured tag kept and scripts dropped, got %q", resp.ContentHTML)
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"post-service/internal/domain/entities"
+)
+
+// excerptLength is roughly how many characters a generated excerpt keeps.
+const excerptLength = 160
+
+// postExcerpt returns the teaser shown for post in lists: the author's own
+// excerpt when there is one, otherwise the start of its content as plain text.
+func postExcerpt(post *entities.Post) string {
+	if post.Excerpt != "" {
+		return post.Excerpt
+	}
+	return truncateExcerpt(contentRenderer.PlainText(post.Content), excerptLength)
+}
+
+// truncateExcerpt shortens text to at most limit characters plus an ellipsis,
+// cutting at the last word boundary so no word is split. A first word longer
+// than limit is cut where it stands.
+func truncateExcerpt(text string, limit int) string {
+	chars := []rune(text)
+	if len(chars) <= limit {
+		return text
+	}
+
+	cut := limit
+	for i := limit; i > 0; i-- {
+		if unicode.IsSpace(chars[i]) {
+			cut = i
+			break
+		}
+	}
+	excerpt := strings.TrimRightFunc(string(chars[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	return excerpt + "…"
+}
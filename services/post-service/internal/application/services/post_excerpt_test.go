@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"post-service/internal/application/dto"
+	"post-service/internal/domain/entities"
+	"post-service/pkg/logger"
+)
+
+func TestTruncateExcerpt(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{"short text is kept", "A short post.", 20, "A short post."},
+		{"cuts at a word boundary", "The quick brown fox jumps", 12, "The quick…"},
+		{"drops trailing punctuation", "Hello, world and more", 8, "Hello…"},
+		{"cuts a long first word", "Supercalifragilistic", 5, "Super…"},
+		{"counts characters not bytes", "Привет мир всем", 10, "Привет мир…"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := truncateExcerpt(tc.text, tc.limit); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGetUserPosts_Excerpt(t *testing.T) {
+	long := "# Heading\n\nThis **post** has " + strings.Repeat("plenty of words ", 20) + "<script>alert(1)</script>"
+	repo := newMockPostRepo(
+		&entities.Post{ID: "generated", UserID: "author", Slug: "generated", Content: long, Status: entities.PostStatusPublished},
+		&entities.Post{ID: "written", UserID: "author", Slug: "written", Content: long, Excerpt: "In the author's words.", Status: entities.PostStatusPublished},
+	)
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	resp, err := svc.GetUserPosts(context.Background(), "author", "", &dto.UserPostsRequest{Limit: 20})
+	if err != nil {
+		t.Fatalf("GetUserPosts: %v", err)
+	}
+	excerpts := make(map[string]string)
+	for _, post := range resp.Posts {
+		excerpts[post.ID] = post.Excerpt
+	}
+
+	if got := excerpts["written"]; got != "In the author's words." {
+		t.Fatalf("expected the author's excerpt, got %q", got)
+	}
+	generated := excerpts["generated"]
+	if !strings.HasPrefix(generated, "Heading This post has plenty of words") || !strings.HasSuffix(generated, "…") {
+		t.Fatalf("expected plain text cut with an ellipsis, got %q", generated)
+	}
+	if strings.ContainsAny(generated, "<>*#") || utf8.RuneCountInString(generated) > excerptLength+1 {
+		t.Fatalf("expected at most %d characters of plain text, got %q", excerptLength, generated)
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"post-service/internal/application/dto"
+	"post-service/internal/application/errors"
+	"post-service/pkg/logger"
+)
+
+// LikePost records userID's like of post id. Liking a post again is not an
+// error. Unpublished posts can only be liked by their owner.
+func (s *PostService) LikePost(ctx context.Context, id string, userID string) (*dto.PostLikeResponse, error) {
+	post, err := s.postRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Post not found: %s", id))
+		return nil, errors.ErrPostNotFound
+	}
+
+	if post.UserID != userID && !post.IsPublished() {
+		return nil, errors.ErrUnauthorizedAccess
+	}
+
+	if err := s.postRepo.Like(ctx, id, userID); err != nil {
+		s.logger.Error("Failed to like post", logger.F("post_id", id), logger.F("user_id", userID), logger.Err(err))
+		return nil, errors.ErrPostLikeFailed
+	}
+
+	return s.likeResponse(ctx, id, true)
+}
+
+// UnlikePost removes userID's like of post id. Unliking a post that was not
+// liked is not an error.
+func (s *PostService) UnlikePost(ctx context.Context, id string, userID string) (*dto.PostLikeResponse, error) {
+	if _, err := s.postRepo.GetByID(ctx, id); err != nil {
+		s.logger.Warn(fmt.Sprintf("Post not found: %s", id))
+		return nil, errors.ErrPostNotFound
+	}
+
+	if err := s.postRepo.Unlike(ctx, id, userID); err != nil {
+		s.logger.Error("Failed to unlike post", logger.F("post_id", id), logger.F("user_id", userID), logger.Err(err))
+		return nil, errors.ErrPostLikeFailed
+	}
+
+	return s.likeResponse(ctx, id, false)
+}
+
+func (s *PostService) likeResponse(ctx context.Context, id string, liked bool) (*dto.PostLikeResponse, error) {
+	count, err := s.postRepo.CountLikes(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to count post likes", logger.F("post_id", id), logger.Err(err))
+		return nil, errors.ErrPostLikeFailed
+	}
+
+	return &dto.PostLikeResponse{PostID: id, LikeCount: count, LikedByMe: liked}, nil
+}
+
+// addLikes fills in the like count of post and, when userID is set, whether
+// that user likes it. Failures are logged and leave the fields unset: the post
+// itself is still worth returning.
+func (s *PostService) addLikes(ctx context.Context, post *dto.PostResponse, userID string) {
+	count, err := s.postRepo.CountLikes(ctx, post.ID)
+	if err != nil {
+		s.logger.Warn("Failed to count post likes", logger.F("post_id", post.ID), logger.Err(err))
+		return
+	}
+	post.LikeCount = count
+
+	if userID == "" {
+		return
+	}
+	liked, err := s.postRepo.HasLiked(ctx, post.ID, userID)
+	if err != nil {
+		s.logger.Warn("Failed to read post like", logger.F("post_id", post.ID), logger.F("user_id", userID), logger.Err(err))
+		return
+	}
+	post.LikedByMe = &liked
+}
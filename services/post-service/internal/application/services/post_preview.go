@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"post-service/internal/application/dto"
+	"post-service/internal/application/errors"
+	"post-service/pkg/logger"
+)
+
+// previewTokenBytes is the entropy of a preview token before encoding.
+const previewTokenBytes = 32
+
+// GeneratePreviewToken issues a new preview link token for the owner's post,
+// replacing (and so revoking) any earlier one. A positive ttl makes it expire.
+// The token is returned once; only its hash is stored.
+func (s *PostService) GeneratePreviewToken(ctx context.Context, postID, userID string, ttl time.Duration) (*dto.PreviewTokenResponse, error) {
+	if err := s.authorizePreviewToken(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, previewTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.Error("Failed to generate preview token", logger.F("post_id", postID), logger.Err(err))
+		return nil, errors.ErrPostUpdateFailed
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	var expiresAt *time.Time
+	if ttl > 0 {
+		expiry := time.Now().Add(ttl).UTC()
+		expiresAt = &expiry
+	}
+
+	if err := s.postRepo.SetPreviewToken(ctx, postID, hashPreviewToken(token), expiresAt); err != nil {
+		s.logger.Error("Failed to store preview token", logger.F("post_id", postID), logger.Err(err))
+		return nil, errors.ErrPostUpdateFailed
+	}
+
+	s.logger.Info("Preview token generated", logger.F("post_id", postID), logger.F("user_id", userID))
+	return &dto.PreviewTokenResponse{PostID: postID, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// RevokePreviewToken disables the post's preview link, if it has one.
+func (s *PostService) RevokePreviewToken(ctx context.Context, postID, userID string) error {
+	if err := s.authorizePreviewToken(ctx, postID, userID); err != nil {
+		return err
+	}
+
+	if err := s.postRepo.SetPreviewToken(ctx, postID, "", nil); err != nil {
+		s.logger.Error("Failed to revoke preview token", logger.F("post_id", postID), logger.Err(err))
+		return errors.ErrPostUpdateFailed
+	}
+
+	s.logger.Info("Preview token revoked", logger.F("post_id", postID), logger.F("user_id", userID))
+	return nil
+}
+
+// GetPostByPreviewToken returns the post a preview token was issued for,
+// published or not. Unknown, revoked and expired tokens are all not found.
+func (s *PostService) GetPostByPreviewToken(ctx context.Context, token string) (*dto.PostResponse, error) {
+	if token == "" {
+		return nil, errors.ErrPostNotFound
+	}
+
+	post, err := s.postRepo.GetByPreviewToken(ctx, hashPreviewToken(token))
+	if err != nil {
+		s.logger.Debug("Post not found by preview token")
+		return nil, errors.ErrPostNotFound
+	}
+
+	response := &dto.PostResponse{
+		ID:            post.ID,
+		UserID:        post.UserID,
+		Title:         post.Title,
+		Content:       post.Content,
+		Slug:          post.Slug,
+		Status:        string(post.Status),
+		Published:     post.IsPublished(),
+		CoverImageURL: post.CoverImageURL,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+	}
+	s.addLikes(ctx, response, "")
+
+	return response, nil
+}
+
+func (s *PostService) authorizePreviewToken(ctx context.Context, postID, userID string) error {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Post not found: %s", postID))
+		return errors.ErrPostNotFound
+	}
+	if post.UserID != userID {
+		return errors.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+func hashPreviewToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
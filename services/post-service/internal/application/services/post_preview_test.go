@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "post-service/internal/application/errors"
+	"post-service/internal/domain/entities"
+	"post-service/pkg/logger"
+)
+
+func newPreviewTestService() (*PostService, *mockPostRepo) {
+	repo := newMockPostRepo(&entities.Post{ID: "post-1", UserID: "author", Slug: "draft", Status: entities.PostStatusDraft})
+	return NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info")), repo
+}
+
+func TestPreviewToken_OpensDraft(t *testing.T) {
+	svc, _ := newPreviewTestService()
+
+	issued, err := svc.GeneratePreviewToken(context.Background(), "post-1", "author", 0)
+	if err != nil {
+		t.Fatalf("GeneratePreviewToken: %v", err)
+	}
+	if issued.Token == "" || issued.ExpiresAt != nil {
+		t.Fatalf("expected a non-expiring token, got %+v", issued)
+	}
+
+	post, err := svc.GetPostByPreviewToken(context.Background(), issued.Token)
+	if err != nil {
+		t.Fatalf("expected the draft to open, got %v", err)
+	}
+	if post.ID != "post-1" || post.Published {
+		t.Fatalf("unexpected post: %+v", post)
+	}
+}
+
+func TestPreviewToken_RegenerateInvalidatesOldToken(t *testing.T) {
+	svc, _ := newPreviewTestService()
+
+	first, _ := svc.GeneratePreviewToken(context.Background(), "post-1", "author", 0)
+	second, err := svc.GeneratePreviewToken(context.Background(), "post-1", "author", 0)
+	if err != nil {
+		t.Fatalf("GeneratePreviewToken: %v", err)
+	}
+	if first.Token == second.Token {
+		t.Fatal("expected a fresh token")
+	}
+
+	if _, err := svc.GetPostByPreviewToken(context.Background(), first.Token); err != apperrors.ErrPostNotFound {
+		t.Fatalf("expected the old token to stop working, got %v", err)
+	}
+	if _, err := svc.GetPostByPreviewToken(context.Background(), second.Token); err != nil {
+		t.Fatalf("expected the new token to work, got %v", err)
+	}
+}
+
+func TestPreviewToken_RevokeAndExpiry(t *testing.T) {
+	svc, repo := newPreviewTestService()
+
+	issued, err := svc.GeneratePreviewToken(context.Background(), "post-1", "author", time.Hour)
+	if err != nil {
+		t.Fatalf("GeneratePreviewToken: %v", err)
+	}
+	if issued.ExpiresAt == nil || issued.ExpiresAt.Before(time.Now()) {
+		t.Fatalf("expected an expiry an hour out, got %v", issued.ExpiresAt)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	repo.previews["post-1"] = mockPreviewToken{hash: repo.previews["post-1"].hash, expiresAt: &past}
+	if _, err := svc.GetPostByPreviewToken(context.Background(), issued.Token); err != apperrors.ErrPostNotFound {
+		t.Fatalf("expected an expired token to be rejected, got %v", err)
+	}
+
+	issued, _ = svc.GeneratePreviewToken(context.Background(), "post-1", "author", 0)
+	if err := svc.RevokePreviewToken(context.Background(), "post-1", "author"); err != nil {
+		t.Fatalf("RevokePreviewToken: %v", err)
+	}
+	if _, err := svc.GetPostByPreviewToken(context.Background(), issued.Token); err != apperrors.ErrPostNotFound {
+		t.Fatalf("expected a revoked token to be rejected, got %v", err)
+	}
+}
+
+func TestPreviewToken_OnlyOwnerCanIssue(t *testing.T) {
+	svc, _ := newPreviewTestService()
+
+	if _, err := svc.GeneratePreviewToken(context.Background(), "post-1", "someone-else", 0); err != apperrors.ErrUnauthorizedAccess {
+		t.Fatalf("expected ErrUnauthorizedAccess, got %v", err)
+	}
+	if err := svc.RevokePreviewToken(context.Background(), "post-1", "someone-else"); err != apperrors.ErrUnauthorizedAccess {
+		t.Fatalf("expected ErrUnauthorizedAccess, got %v", err)
+	}
+}
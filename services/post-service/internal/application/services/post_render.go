@@ -0,0 +1,20 @@
+package services
+
+import (
+	"post-service/internal/application/dto"
+	"post-service/pkg/markdown"
+)
+
+// contentRenderer is shared: rendering keeps no per-call state. Plain-text
+// excerpts use it as they do not depend on the configured content policy.
+var contentRenderer = markdown.NewRenderer(nil)
+
+// RenderContentHTML sets post.ContentHTML to the sanitized HTML rendering of
+// its Markdown content. Callers opt in per request; the stored content is
+// never changed.
+func (s *PostService) RenderContentHTML(post *dto.PostResponse) {
+	if post == nil {
+		return
+	}
+	post.ContentHTML = s.renderer.Render(post.Content)
+}
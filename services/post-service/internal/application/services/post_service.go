@@ -5,43 +5,83 @@ import (
 	"fmt"
 	"post-service/internal/infrastructure/messaging"
 	"post-service/internal/infrastructure/search"
+	"time"
 
 	"post-service/internal/application/dto"
 	"post-service/internal/application/errors"
 	"post-service/internal/domain/entities"
 	"post-service/internal/domain/repositories"
 	"post-service/pkg/logger"
+	"post-service/pkg/markdown"
 
 	"github.com/google/uuid"
 )
 
 type PostService struct {
-	postRepo       repositories.PostRepository
-	eventPublisher *messaging.EventPublisher
-	searchIndexer  *search.Indexer
-	logger         *logger.Logger
+	postRepo      repositories.PostRepository
+	recordEvents  bool // store post events in the outbox for the relay
+	searchIndexer *search.Indexer
+	editLock      EditLockPolicy
+	sanitizer     *markdown.Sanitizer
+	renderer      *markdown.Renderer
+	logger        *logger.Logger
 }
 
-func NewPostService(postRepo repositories.PostRepository, eventPublisher *messaging.EventPublisher, searchIndexer *search.Indexer, logger *logger.Logger) *PostService {
+// EditLockPolicy prevents edits to a post for Window after it is published,
+// so updates do not race with downstream indexing and caching. A zero Window
+// disables the lock. Users listed in AdminUserIDs are never locked out.
+type EditLockPolicy struct {
+	Window       time.Duration
+	AdminUserIDs []string
+}
+
+func (p EditLockPolicy) isLocked(post *entities.Post, userID string, now time.Time) bool {
+	if p.Window <= 0 || !post.IsPublished() || post.PublishedAt == nil {
+		return false
+	}
+	for _, adminID := range p.AdminUserIDs {
+		if adminID == userID {
+			return false
+		}
+	}
+	return now.Sub(*post.PublishedAt) < p.Window
+}
+
+// NewPostService builds the service. recordEvents stores an outbox event with
+// every change, whether or not the broker is reachable right now. A nil
+// sanitizer applies markdown.ContentPolicy(nil); rendered HTML follows the
+// sanitizer's policy.
+func NewPostService(postRepo repositories.PostRepository, recordEvents bool, searchIndexer *search.Indexer, editLock EditLockPolicy, sanitizer *markdown.Sanitizer, logger *logger.Logger) *PostService {
+	if sanitizer == nil {
+		sanitizer = markdown.NewSanitizer(nil)
+	}
 	return &PostService{
-		postRepo:       postRepo,
-		eventPublisher: eventPublisher,
-		searchIndexer:  searchIndexer,
-		logger:         logger,
+		postRepo:      postRepo,
+		recordEvents:  recordEvents,
+		searchIndexer: searchIndexer,
+		editLock:      editLock,
+		sanitizer:     sanitizer,
+		renderer:      markdown.NewRenderer(sanitizer.Policy()),
+		logger:        logger,
 	}
 }
 
 func (s *PostService) CreatePost(ctx context.Context, req *dto.CreatePostRequest, userID string) (*dto.PostResponse, error) {
-	s.logger.Info(fmt.Sprintf("Creating post for user: %s", userID))
+	s.logger.Info("Creating post", logger.F("user_id", userID))
 
 	// Create post entity
 	post := &entities.Post{
-		ID:        uuid.New().String(),
-		UserID:    userID,
-		Title:     req.Title,
-		Content:   req.Content,
-		Slug:      req.Slug,
-		Published: req.Published,
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		Title:         req.Title,
+		Content:       req.Content,
+		Slug:          req.Slug,
+		Status:        entities.PostStatusDraft,
+		CoverImageURL: req.CoverImageURL,
+		Excerpt:       req.Excerpt,
+	}
+	if req.Published {
+		post.Status = entities.PostStatusPublished
 	}
 
 	// Generate slug if not provided
@@ -49,8 +89,9 @@ func (s *PostService) CreatePost(ctx context.Context, req *dto.CreatePostRequest
 
 	// Validate and sanitize
 	post.Sanitize()
+	post.SanitizeContent(s.sanitizer.Sanitize)
 	if err := post.IsValid(); err != nil {
-		s.logger.Warn(fmt.Sprintf("Post validation failed: %v", err))
+		s.logger.Warn("Post validation failed", logger.F("user_id", userID), logger.Err(err))
 		return nil, errors.ErrInvalidPostData
 	}
 
@@ -64,49 +105,47 @@ func (s *PostService) CreatePost(ctx context.Context, req *dto.CreatePostRequest
 		return nil, errors.ErrPostAlreadyExists
 	}
 
-	// Save to database
-	if err := s.postRepo.Create(ctx, post); err != nil {
-		s.logger.Error(fmt.Sprintf("Failed to create post: %v", err))
-		return nil, errors.ErrPostCreationFailed
-	}
-
-	s.logger.Info(fmt.Sprintf("Post created successfully: %s", post.ID))
-
-	if s.eventPublisher != nil {
-		event := messaging.PostCreatedEvent{
+	// Save the post and its created event in one transaction
+	err = s.postRepo.InTx(ctx, func(tx repositories.PostRepository) error {
+		if err := tx.Create(ctx, post); err != nil {
+			return err
+		}
+		return s.addEvent(ctx, tx, messaging.RoutingKeyPostCreated, messaging.PostCreatedEvent{
 			PostID:    post.ID,
 			UserID:    post.UserID,
 			Title:     post.Title,
 			Slug:      post.Slug,
-			Published: post.Published,
+			Published: post.IsPublished(),
 			CreatedAt: post.CreatedAt,
-		}
-
-		if err := s.eventPublisher.PublishPostCreated(event); err != nil {
-			s.logger.Error(fmt.Sprintf("failed to publish post created event: %v", err))
-		} else {
-			s.logger.Info(fmt.Sprintf("published post created event for post: %s", post.ID))
-		}
+		})
+	})
+	if err != nil {
+		s.logger.Error("Failed to create post", logger.F("user_id", userID), logger.Err(err))
+		return nil, errors.ErrPostCreationFailed
 	}
 
+	s.logger.Info("Post created", logger.F("post_id", post.ID), logger.F("user_id", userID))
+
 	if s.searchIndexer != nil {
 		s.searchIndexer.PostCreated(ctx, post)
 	}
 
 	return &dto.PostResponse{
-		ID:        post.ID,
-		UserID:    post.UserID,
-		Title:     post.Title,
-		Content:   post.Content,
-		Slug:      post.Slug,
-		Published: post.Published,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
+		ID:            post.ID,
+		UserID:        post.UserID,
+		Title:         post.Title,
+		Content:       post.Content,
+		Slug:          post.Slug,
+		Status:        string(post.Status),
+		Published:     post.IsPublished(),
+		CoverImageURL: post.CoverImageURL,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
 	}, nil
 }
 
 func (s *PostService) GetPost(ctx context.Context, id string, userID string) (*dto.PostResponse, error) {
-	s.logger.Info(fmt.Sprintf("Getting post: %s for user: %s", id, userID))
+	s.logger.Debug("Getting post", logger.F("post_id", id), logger.F("user_id", userID))
 
 	post, err := s.postRepo.GetByID(ctx, id)
 	if err != nil {
@@ -115,50 +154,68 @@ func (s *PostService) GetPost(ctx context.Context, id string, userID string) (*d
 	}
 
 	// Check if user owns the post or if it's published
-	if post.UserID != userID && !post.Published {
+	if post.UserID != userID && !post.IsPublished() {
 		return nil, errors.ErrUnauthorizedAccess
 	}
 
-	return &dto.PostResponse{
-		ID:        post.ID,
-		UserID:    post.UserID,
-		Title:     post.Title,
-		Content:   post.Content,
-		Slug:      post.Slug,
-		Published: post.Published,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
-	}, nil
+	response := &dto.PostResponse{
+		ID:            post.ID,
+		UserID:        post.UserID,
+		Title:         post.Title,
+		Content:       post.Content,
+		Slug:          post.Slug,
+		Status:        string(post.Status),
+		Published:     post.IsPublished(),
+		CoverImageURL: post.CoverImageURL,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+	}
+	s.addLikes(ctx, response, userID)
+
+	return response, nil
 }
 
-func (s *PostService) GetPostBySlug(ctx context.Context, slug string) (*dto.PostResponse, error) {
+// GetPostBySlug returns a published post by slug. When userID is set, that
+// user's own unpublished posts are found too, for draft previews.
+func (s *PostService) GetPostBySlug(ctx context.Context, slug string, userID string) (*dto.PostResponse, error) {
 	s.logger.Info(fmt.Sprintf("Getting post by slug: %s", slug))
 
-	post, err := s.postRepo.GetBySlug(ctx, slug)
+	var post *entities.Post
+	var err error
+	if userID == "" {
+		post, err = s.postRepo.GetBySlug(ctx, slug)
+	} else {
+		post, err = s.postRepo.GetBySlugForUser(ctx, slug, userID)
+	}
 	if err != nil {
 		s.logger.Warn(fmt.Sprintf("Post not found by slug: %s", slug))
 		return nil, errors.ErrPostNotFound
 	}
 
-	return &dto.PostResponse{
-		ID:        post.ID,
-		UserID:    post.UserID,
-		Title:     post.Title,
-		Content:   post.Content,
-		Slug:      post.Slug,
-		Published: post.Published,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
-	}, nil
+	response := &dto.PostResponse{
+		ID:            post.ID,
+		UserID:        post.UserID,
+		Title:         post.Title,
+		Content:       post.Content,
+		Slug:          post.Slug,
+		Status:        string(post.Status),
+		Published:     post.IsPublished(),
+		CoverImageURL: post.CoverImageURL,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+	}
+	s.addLikes(ctx, response, userID)
+
+	return response, nil
 }
 
 func (s *PostService) UpdatePost(ctx context.Context, id string, req *dto.UpdatePostRequest, userID string) (*dto.PostResponse, error) {
-	s.logger.Info(fmt.Sprintf("Updating post: %s by user: %s", id, userID))
+	s.logger.Info("Updating post", logger.F("post_id", id), logger.F("user_id", userID))
 
 	// Get existing post
 	post, err := s.postRepo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Warn(fmt.Sprintf("Post not found for update: %s", id))
+		s.logger.Warn("Post not found for update", logger.F("post_id", id), logger.F("user_id", userID))
 		return nil, errors.ErrPostNotFound
 	}
 
@@ -167,6 +224,12 @@ func (s *PostService) UpdatePost(ctx context.Context, id string, req *dto.Update
 		return nil, errors.ErrUnauthorizedAccess
 	}
 
+	now := time.Now()
+	if s.editLock.isLocked(post, userID, now) {
+		s.logger.Warn(fmt.Sprintf("Post %s is within the edit lock window", id))
+		return nil, errors.ErrPostLocked
+	}
+
 	// Update fields
 	if req.Title != nil {
 		post.Title = *req.Title
@@ -177,12 +240,27 @@ func (s *PostService) UpdatePost(ctx context.Context, id string, req *dto.Update
 	if req.Slug != nil {
 		post.Slug = *req.Slug
 	}
+	if req.CoverImageURL != nil {
+		post.CoverImageURL = *req.CoverImageURL
+	}
+	if req.Excerpt != nil {
+		post.Excerpt = *req.Excerpt
+	}
+	// Unpublishing turns a published post back into a draft; an archived post
+	// is already not public and stays archived.
 	if req.Published != nil {
-		post.Published = *req.Published
+		if *req.Published && !post.IsPublished() {
+			post.PublishedAt = &now
+			post.Status = entities.PostStatusPublished
+		} else if !*req.Published && post.IsPublished() {
+			post.PublishedAt = nil
+			post.Status = entities.PostStatusDraft
+		}
 	}
 
 	// Validate and sanitize
 	post.Sanitize()
+	post.SanitizeContent(s.sanitizer.Sanitize)
 	if err := post.IsValid(); err != nil {
 		s.logger.Warn(fmt.Sprintf("Post validation failed on update: %v", err))
 		return nil, errors.ErrInvalidPostData
@@ -204,56 +282,42 @@ func (s *PostService) UpdatePost(ctx context.Context, id string, req *dto.Update
 		}
 	}
 
-	// Update in database
-	if err := s.postRepo.Update(ctx, post); err != nil {
-		s.logger.Error(fmt.Sprintf("Failed to update post: %v", err))
+	// Update in database, with the updated event
+	if err := s.updateWithEvent(ctx, post); err != nil {
+		s.logger.Error("Failed to update post", logger.F("post_id", id), logger.F("user_id", userID), logger.Err(err))
 		return nil, errors.ErrPostUpdateFailed
 	}
 
-	s.logger.Info(fmt.Sprintf("Post updated successfully: %s", post.ID))
-
-	// Publish event after successful update
-	if s.eventPublisher != nil {
-		event := messaging.PostUpdatedEvent{
-			PostID:    post.ID,
-			UserID:    post.UserID,
-			Title:     post.Title,
-			Slug:      post.Slug,
-			Published: post.Published,
-			UpdatedAt: post.UpdatedAt,
-		}
-
-		if err := s.eventPublisher.PublishPostUpdated(event); err != nil {
-			s.logger.Error(fmt.Sprintf("Failed to publish post updated event: %v", err))
-			// Don't fail the request, just log the error
-		} else {
-			s.logger.Info(fmt.Sprintf("Published post updated event for post: %s", post.ID))
-		}
-	}
+	s.logger.Info("Post updated", logger.F("post_id", post.ID), logger.F("user_id", userID))
 
 	if s.searchIndexer != nil {
 		s.searchIndexer.PostUpdated(ctx, post)
 	}
 
-	return &dto.PostResponse{
-		ID:        post.ID,
-		UserID:    post.UserID,
-		Title:     post.Title,
-		Content:   post.Content,
-		Slug:      post.Slug,
-		Published: post.Published,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
-	}, nil
+	response := &dto.PostResponse{
+		ID:            post.ID,
+		UserID:        post.UserID,
+		Title:         post.Title,
+		Content:       post.Content,
+		Slug:          post.Slug,
+		Status:        string(post.Status),
+		Published:     post.IsPublished(),
+		CoverImageURL: post.CoverImageURL,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+	}
+	s.addLikes(ctx, response, userID)
+
+	return response, nil
 }
 
 func (s *PostService) DeletePost(ctx context.Context, id string, userID string) error {
-	s.logger.Info(fmt.Sprintf("Deleting post: %s by user: %s", id, userID))
+	s.logger.Info("Deleting post", logger.F("post_id", id), logger.F("user_id", userID))
 
 	// Get existing post to check ownership and for event data
 	post, err := s.postRepo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Warn(fmt.Sprintf("Post not found for deletion: %s", id))
+		s.logger.Warn("Post not found for deletion", logger.F("post_id", id), logger.F("user_id", userID))
 		return errors.ErrPostNotFound
 	}
 
@@ -262,39 +326,254 @@ func (s *PostService) DeletePost(ctx context.Context, id string, userID string)
 		return errors.ErrUnauthorizedAccess
 	}
 
-	// Store data for event before deletion
-	postTitle := post.Title
-	postUserID := post.UserID
-
-	if err := s.postRepo.Delete(ctx, id); err != nil {
-		s.logger.Error(fmt.Sprintf("Failed to delete post: %v", err))
+	err = s.postRepo.InTx(ctx, func(tx repositories.PostRepository) error {
+		if err := tx.Delete(ctx, id); err != nil {
+			return err
+		}
+		// Use updated time as deletion time
+		return s.addDeletedEvent(ctx, tx, post, post.UpdatedAt)
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete post", logger.F("post_id", id), logger.F("user_id", userID), logger.Err(err))
 		return errors.ErrPostDeletionFailed
 	}
 
-	s.logger.Info(fmt.Sprintf("Post deleted successfully: %s", id))
+	s.logger.Info("Post deleted", logger.F("post_id", id), logger.F("user_id", userID))
+
+	if s.searchIndexer != nil {
+		s.searchIndexer.PostDeleted(ctx, id)
+	}
+
+	return nil
+}
+
+// DeleteMany deletes those of ids that userID owns in one statement. Ids that
+// do not exist or belong to someone else are reported as not deleted rather
+// than failing the batch. Duplicate ids are reported once.
+func (s *PostService) DeleteMany(ctx context.Context, ids []string, userID string) (*dto.BulkDeletePostsResponse, error) {
+	if len(ids) == 0 || len(ids) > dto.MaxBulkDeleteIDs {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	s.logger.Info("Deleting posts", logger.F("count", len(ids)), logger.F("user_id", userID))
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	var deleted []*entities.Post
+	deletedAt := time.Now()
+	err := s.postRepo.InTx(ctx, func(tx repositories.PostRepository) error {
+		var err error
+		if deleted, err = tx.DeleteMany(ctx, unique, userID); err != nil {
+			return err
+		}
+		for _, post := range deleted {
+			if err := s.addDeletedEvent(ctx, tx, post, deletedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete posts", logger.F("user_id", userID), logger.Err(err))
+		return nil, errors.ErrPostDeletionFailed
+	}
+
+	deletedIDs := make(map[string]bool, len(deleted))
+	for _, post := range deleted {
+		deletedIDs[post.ID] = true
+		if s.searchIndexer != nil {
+			s.searchIndexer.PostDeleted(ctx, post.ID)
+		}
+	}
+
+	response := &dto.BulkDeletePostsResponse{
+		Results: make([]dto.BulkDeleteResult, 0, len(unique)),
+		Deleted: len(deleted),
+	}
+	for _, id := range unique {
+		response.Results = append(response.Results, dto.BulkDeleteResult{ID: id, Deleted: deletedIDs[id]})
+	}
+
+	s.logger.Info("Posts deleted", logger.F("deleted", len(deleted)), logger.F("requested", len(unique)), logger.F("user_id", userID))
+	return response, nil
+}
+
+// addEvent stores event in tx's outbox. The outbox relay publishes it once
+// tx has committed, so the event is sent if and only if the change is saved.
+// Nothing is stored when event publishing is not configured.
+func (s *PostService) addEvent(ctx context.Context, tx repositories.PostRepository, routingKey string, event interface{}) error {
+	if !s.recordEvents {
+		return nil
+	}
+
+	outboxEvent, err := messaging.NewOutboxEvent(ctx, routingKey, event)
+	if err != nil {
+		return err
+	}
+	return tx.AddOutboxEvent(ctx, outboxEvent)
+}
+
+// updateWithEvent saves post and its updated event in one transaction.
+func (s *PostService) updateWithEvent(ctx context.Context, post *entities.Post) error {
+	return s.postRepo.InTx(ctx, func(tx repositories.PostRepository) error {
+		if err := tx.Update(ctx, post); err != nil {
+			return err
+		}
+		return s.addEvent(ctx, tx, messaging.RoutingKeyPostUpdated, messaging.PostUpdatedEvent{
+			PostID:    post.ID,
+			UserID:    post.UserID,
+			Title:     post.Title,
+			Slug:      post.Slug,
+			Published: post.IsPublished(),
+			UpdatedAt: post.UpdatedAt,
+		})
+	})
+}
+
+func (s *PostService) addDeletedEvent(ctx context.Context, tx repositories.PostRepository, post *entities.Post, deletedAt time.Time) error {
+	return s.addEvent(ctx, tx, messaging.RoutingKeyPostDeleted, messaging.PostDeletedEvent{
+		PostID:    post.ID,
+		UserID:    post.UserID,
+		Title:     post.Title,
+		DeletedAt: deletedAt,
+	})
+}
+
+// ArchivePost retires the owner's post: it leaves public lists, search and
+// slug lookups but stays readable by the owner. Publishing it again through
+// UpdatePost brings it back. Archiving an archived post is a no-op.
+func (s *PostService) ArchivePost(ctx context.Context, id string, userID string) (*dto.PostResponse, error) {
+	s.logger.Info("Archiving post", logger.F("post_id", id), logger.F("user_id", userID))
+
+	post, err := s.postRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("Post not found for archive", logger.F("post_id", id), logger.F("user_id", userID))
+		return nil, errors.ErrPostNotFound
+	}
+
+	if post.UserID != userID {
+		return nil, errors.ErrUnauthorizedAccess
+	}
 
-	// Publish event after successful deletion
-	if s.eventPublisher != nil {
-		event := messaging.PostDeletedEvent{
-			PostID:    id,
-			UserID:    postUserID,
-			Title:     postTitle,
-			DeletedAt: post.UpdatedAt, // Use updated time as deletion time
+	if post.Status != entities.PostStatusArchived {
+		post.Status = entities.PostStatusArchived
+		if err := s.updateWithEvent(ctx, post); err != nil {
+			s.logger.Error("Failed to archive post", logger.F("post_id", id), logger.F("user_id", userID), logger.Err(err))
+			return nil, errors.ErrPostUpdateFailed
 		}
 
-		if err := s.eventPublisher.PublishPostDeleted(event); err != nil {
-			s.logger.Error(fmt.Sprintf("Failed to publish post deleted event: %v", err))
-			// Don't fail the request, just log the error
-		} else {
-			s.logger.Info(fmt.Sprintf("Published post deleted event for post: %s", id))
+		s.logger.Info("Post archived", logger.F("post_id", id), logger.F("user_id", userID))
+
+		if s.searchIndexer != nil {
+			s.searchIndexer.PostUpdated(ctx, post)
 		}
 	}
 
+	response := &dto.PostResponse{
+		ID:            post.ID,
+		UserID:        post.UserID,
+		Title:         post.Title,
+		Content:       post.Content,
+		Slug:          post.Slug,
+		Status:        string(post.Status),
+		Published:     post.IsPublished(),
+		CoverImageURL: post.CoverImageURL,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+	}
+	s.addLikes(ctx, response, userID)
+
+	return response, nil
+}
+
+// maxCloneSlugAttempts bounds the search for a free "-copy" slug.
+const maxCloneSlugAttempts = 20
+
+// ClonePost copies the owner's post into a new unpublished draft with a fresh
+// ID and a "-copy" slug. The original is left untouched. No created event is
+// published for the draft; it reaches followers only once it is published.
+func (s *PostService) ClonePost(ctx context.Context, id string, userID string) (*dto.PostResponse, error) {
+	s.logger.Info("Cloning post", logger.F("post_id", id), logger.F("user_id", userID))
+
+	original, err := s.postRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("Post not found for clone", logger.F("post_id", id), logger.F("user_id", userID))
+		return nil, errors.ErrPostNotFound
+	}
+
+	if original.UserID != userID {
+		return nil, errors.ErrUnauthorizedAccess
+	}
+
+	slug, err := s.cloneSlug(ctx, original.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &entities.Post{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		Title:         original.Title,
+		Content:       original.Content,
+		Slug:          slug,
+		Status:        entities.PostStatusDraft,
+		CoverImageURL: original.CoverImageURL,
+		Excerpt:       original.Excerpt,
+	}
+
+	clone.Sanitize()
+	clone.SanitizeContent(s.sanitizer.Sanitize)
+	if err := clone.IsValid(); err != nil {
+		s.logger.Warn("Cloned post validation failed", logger.F("post_id", id), logger.Err(err))
+		return nil, errors.ErrInvalidPostData
+	}
+
+	if err := s.postRepo.Create(ctx, clone); err != nil {
+		s.logger.Error("Failed to create cloned post", logger.F("post_id", id), logger.F("user_id", userID), logger.Err(err))
+		return nil, errors.ErrPostCreationFailed
+	}
+
+	s.logger.Info("Post cloned", logger.F("post_id", id), logger.F("clone_id", clone.ID), logger.F("user_id", userID))
+
 	if s.searchIndexer != nil {
-		s.searchIndexer.PostDeleted(ctx, id)
+		s.searchIndexer.PostCreated(ctx, clone)
 	}
 
-	return nil
+	return &dto.PostResponse{
+		ID:            clone.ID,
+		UserID:        clone.UserID,
+		Title:         clone.Title,
+		Content:       clone.Content,
+		Slug:          clone.Slug,
+		Status:        string(clone.Status),
+		Published:     clone.IsPublished(),
+		CoverImageURL: clone.CoverImageURL,
+		CreatedAt:     clone.CreatedAt,
+		UpdatedAt:     clone.UpdatedAt,
+	}, nil
+}
+
+// cloneSlug returns the first free copy slug for slug.
+func (s *PostService) cloneSlug(ctx context.Context, slug string) (string, error) {
+	for n := 1; n <= maxCloneSlugAttempts; n++ {
+		candidate := entities.CopySlug(slug, n)
+		exists, err := s.postRepo.ExistsBySlug(ctx, candidate)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to check slug existence: %v", err))
+			return "", errors.ErrPostCreationFailed
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", errors.ErrPostAlreadyExists
 }
 
 // BackfillSearchIndex republishes every post to the search index. It exists to
@@ -309,7 +588,7 @@ func (s *PostService) BackfillSearchIndex(ctx context.Context) error {
 	const page = 100
 	offset, total := 0, 0
 	for {
-		posts, err := s.postRepo.List(ctx, page, offset, false) // include drafts; query filters published
+		posts, err := s.postRepo.List(ctx, page, offset, false, repositories.DateRange{}) // include drafts; query filters published
 		if err != nil {
 			return err
 		}
@@ -333,22 +612,25 @@ func (s *PostService) ListPosts(ctx context.Context, req *dto.ListPostsRequest)
 	req.PublishedOnly = true
 	s.logger.Info(fmt.Sprintf("Listing posts: limit=%d, offset=%d, published_only=%t", req.Limit, req.Offset, req.PublishedOnly))
 
-	posts, err := s.postRepo.List(ctx, req.Limit, req.Offset, req.PublishedOnly)
+	posts, err := s.postRepo.List(ctx, req.Limit, req.Offset, req.PublishedOnly, repositories.DateRange{From: req.From, To: req.To})
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to list posts: %v", err))
 		return nil, errors.ErrPostListFailed
 	}
 
-	var postResponses []*dto.PostSummaryResponse
+	postResponses := make([]*dto.PostSummaryResponse, 0, len(posts))
 	for _, post := range posts {
 		postResponses = append(postResponses, &dto.PostSummaryResponse{
-			ID:        post.ID,
-			UserID:    post.UserID,
-			Title:     post.Title,
-			Slug:      post.Slug,
-			Published: post.Published,
-			CreatedAt: post.CreatedAt,
-			UpdatedAt: post.UpdatedAt,
+			ID:            post.ID,
+			UserID:        post.UserID,
+			Title:         post.Title,
+			Slug:          post.Slug,
+			Status:        string(post.Status),
+			Published:     post.IsPublished(),
+			CoverImageURL: post.CoverImageURL,
+			Excerpt:       postExcerpt(post),
+			CreatedAt:     post.CreatedAt,
+			UpdatedAt:     post.UpdatedAt,
 		})
 	}
 
@@ -360,10 +642,23 @@ func (s *PostService) ListPosts(ctx context.Context, req *dto.ListPostsRequest)
 	}, nil
 }
 
-func (s *PostService) GetUserPosts(ctx context.Context, userID string, req *dto.UserPostsRequest) (*dto.ListPostsResponse, error) {
-	s.logger.Info(fmt.Sprintf("Getting posts for user: %s, limit=%d, offset=%d", userID, req.Limit, req.Offset))
+// GetUserPosts lists userID's posts with req.Status, published by default.
+// Drafts and archived posts are listed only when requestingUserID is userID.
+func (s *PostService) GetUserPosts(ctx context.Context, userID, requestingUserID string, req *dto.UserPostsRequest) (*dto.ListPostsResponse, error) {
+	status := entities.PostStatus(req.Status)
+	if status == "" {
+		status = entities.PostStatusPublished
+	}
+	if !status.IsValid() {
+		return nil, errors.ErrInvalidRequest
+	}
+	if status != entities.PostStatusPublished && requestingUserID != userID {
+		return nil, errors.ErrUnauthorizedAccess
+	}
+
+	s.logger.Info(fmt.Sprintf("Getting posts for user: %s, status=%s, limit=%d, offset=%d", userID, status, req.Limit, req.Offset))
 
-	posts, err := s.postRepo.GetByUserID(ctx, userID, req.Limit, req.Offset)
+	posts, err := s.postRepo.GetByUserID(ctx, userID, status, req.Limit, req.Offset)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to get user posts: %v", err))
 		return nil, errors.ErrPostListFailed
@@ -372,13 +667,16 @@ func (s *PostService) GetUserPosts(ctx context.Context, userID string, req *dto.
 	var postResponses []*dto.PostSummaryResponse
 	for _, post := range posts {
 		postResponses = append(postResponses, &dto.PostSummaryResponse{
-			ID:        post.ID,
-			UserID:    post.UserID,
-			Title:     post.Title,
-			Slug:      post.Slug,
-			Published: post.Published,
-			CreatedAt: post.CreatedAt,
-			UpdatedAt: post.UpdatedAt,
+			ID:            post.ID,
+			UserID:        post.UserID,
+			Title:         post.Title,
+			Slug:          post.Slug,
+			Status:        string(post.Status),
+			Published:     post.IsPublished(),
+			CoverImageURL: post.CoverImageURL,
+			Excerpt:       postExcerpt(post),
+			CreatedAt:     post.CreatedAt,
+			UpdatedAt:     post.UpdatedAt,
 		})
 	}
 
@@ -395,22 +693,26 @@ func (s *PostService) SearchPosts(ctx context.Context, req *dto.SearchPostsReque
 	req.PublishedOnly = true
 	s.logger.Info(fmt.Sprintf("Searching posts: query=%s, limit=%d, offset=%d, published_only=%t", req.Query, req.Limit, req.Offset, req.PublishedOnly))
 
-	posts, err := s.postRepo.Search(ctx, req.Query, req.Limit, req.Offset, req.PublishedOnly)
+	posts, err := s.postRepo.Search(ctx, req.Query, req.Limit, req.Offset, req.PublishedOnly, repositories.DateRange{From: req.From, To: req.To})
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to search posts: %v", err))
 		return nil, errors.ErrPostSearchFailed
 	}
 
-	var postResponses []*dto.PostSummaryResponse
+	// No matches is not an error: return an empty, non-nil page.
+	postResponses := make([]*dto.PostSummaryResponse, 0, len(posts))
 	for _, post := range posts {
 		postResponses = append(postResponses, &dto.PostSummaryResponse{
-			ID:        post.ID,
-			UserID:    post.UserID,
-			Title:     post.Title,
-			Slug:      post.Slug,
-			Published: post.Published,
-			CreatedAt: post.CreatedAt,
-			UpdatedAt: post.UpdatedAt,
+			ID:            post.ID,
+			UserID:        post.UserID,
+			Title:         post.Title,
+			Slug:          post.Slug,
+			Status:        string(post.Status),
+			Published:     post.IsPublished(),
+			CoverImageURL: post.CoverImageURL,
+			Excerpt:       postExcerpt(post),
+			CreatedAt:     post.CreatedAt,
+			UpdatedAt:     post.UpdatedAt,
 		})
 	}
 
@@ -443,6 +745,16 @@ func (s *PostService) GetStats(ctx context.Context, userID string) (*dto.PostSta
 			return nil, errors.ErrPostStatsFailed
 		}
 		response.UserPostsCount = userCount
+
+		draftCount, err := s.postRepo.GetUserDraftCount(ctx, userID)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("Failed to get user draft count: %v", err))
+			return nil, errors.ErrPostStatsFailed
+		}
+		response.UserDraftCount = draftCount
+
+		// UserScheduledCount stays zero until posts support a future publish_at;
+		// every non-draft post is published immediately today.
 	}
 
 	return response, nil
@@ -0,0 +1,629 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"post-service/internal/application/dto"
+	apperrors "post-service/internal/application/errors"
+	"post-service/internal/domain/entities"
+	"post-service/internal/domain/repositories"
+	"post-service/internal/infrastructure/messaging"
+	"post-service/pkg/logger"
+)
+
+type mockPostRepo struct {
+	posts      map[string]*entities.Post
+	previews   map[string]mockPreviewToken
+	updated    []*entities.Post
+	draftCalls int
+	searchErr  error
+	createdIn  repositories.DateRange
+	likes      map[string]map[string]bool // post ID -> user IDs
+	outbox     []*entities.OutboxEvent
+	outboxErr  error
+}
+
+type mockPreviewToken struct {
+	hash      string
+	expiresAt *time.Time
+}
+
+func newMockPostRepo(posts ...*entities.Post) *mockPostRepo {
+	repo := &mockPostRepo{
+		posts:    make(map[string]*entities.Post),
+		previews: make(map[string]mockPreviewToken),
+		likes:    make(map[string]map[string]bool),
+	}
+	for _, post := range posts {
+		repo.posts[post.ID] = post
+	}
+	return repo
+}
+
+func (m *mockPostRepo) InTx(ctx context.Context, fn func(tx repositories.PostRepository) error) error {
+	return fn(m)
+}
+func (m *mockPostRepo) AddOutboxEvent(ctx context.Context, event *entities.OutboxEvent) error {
+	if m.outboxErr != nil {
+		return m.outboxErr
+	}
+	m.outbox = append(m.outbox, event)
+	return nil
+}
+func (m *mockPostRepo) Create(ctx context.Context, post *entities.Post) error {
+	m.posts[post.ID] = post
+	return nil
+}
+func (m *mockPostRepo) GetByID(ctx context.Context, id string) (*entities.Post, error) {
+	post, ok := m.posts[id]
+	if !ok {
+		return nil, errors.New("post not found")
+	}
+	copied := *post
+	return &copied, nil
+}
+func (m *mockPostRepo) GetBySlug(ctx context.Context, slug string) (*entities.Post, error) {
+	return m.GetBySlugForUser(ctx, slug, "")
+}
+func (m *mockPostRepo) GetBySlugForUser(ctx context.Context, slug, userID string) (*entities.Post, error) {
+	for _, post := range m.posts {
+		if post.Slug == slug && (post.IsPublished() || (userID != "" && post.UserID == userID)) {
+			copied := *post
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("post not found")
+}
+func (m *mockPostRepo) GetByPreviewToken(ctx context.Context, tokenHash string) (*entities.Post, error) {
+	for id, preview := range m.previews {
+		if preview.hash == tokenHash && (preview.expiresAt == nil || preview.expiresAt.After(time.Now())) {
+			return m.GetByID(ctx, id)
+		}
+	}
+	return nil, errors.New("post not found")
+}
+func (m *mockPostRepo) SetPreviewToken(ctx context.Context, id, tokenHash string, expiresAt *time.Time) error {
+	if tokenHash == "" {
+		delete(m.previews, id)
+		return nil
+	}
+	m.previews[id] = mockPreviewToken{hash: tokenHash, expiresAt: expiresAt}
+	return nil
+}
+func (m *mockPostRepo) GetByUserID(ctx context.Context, userID string, status entities.PostStatus, limit, offset int) ([]*entities.Post, error) {
+	var posts []*entities.Post
+	for _, post := range m.posts {
+		if post.UserID == userID && post.Status == status {
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+func (m *mockPostRepo) Update(ctx context.Context, post *entities.Post) error {
+	m.updated = append(m.updated, post)
+	m.posts[post.ID] = post
+	return nil
+}
+func (m *mockPostRepo) Delete(ctx context.Context, id string) error { return nil }
+func (m *mockPostRepo) DeleteMany(ctx context.Context, ids []string, userID string) ([]*entities.Post, error) {
+	var deleted []*entities.Post
+	for _, id := range ids {
+		if post, ok := m.posts[id]; ok && post.UserID == userID {
+			delete(m.posts, id)
+			deleted = append(deleted, post)
+		}
+	}
+	return deleted, nil
+}
+func (m *mockPostRepo) List(ctx context.Context, limit, offset int, publishedOnly bool, createdIn repositories.DateRange) ([]*entities.Post, error) {
+	m.createdIn = createdIn
+	return nil, nil
+}
+func (m *mockPostRepo) Search(ctx context.Context, query string, limit, offset int, publishedOnly bool, createdIn repositories.DateRange) ([]*entities.Post, error) {
+	return nil, m.searchErr
+}
+func (m *mockPostRepo) Exists(ctx context.Context, id string) (bool, error) { return false, nil }
+func (m *mockPostRepo) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	for _, post := range m.posts {
+		if post.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockPostRepo) GetPublishedCount(ctx context.Context) (int64, error) { return 0, nil }
+func (m *mockPostRepo) GetUserPostsCount(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	for _, post := range m.posts {
+		if post.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+func (m *mockPostRepo) GetUserDraftCount(ctx context.Context, userID string) (int64, error) {
+	m.draftCalls++
+	var count int64
+	for _, post := range m.posts {
+		if post.UserID == userID && post.Status == entities.PostStatusDraft {
+			count++
+		}
+	}
+	return count, nil
+}
+func (m *mockPostRepo) Like(ctx context.Context, postID, userID string) error {
+	if m.likes[postID] == nil {
+		m.likes[postID] = make(map[string]bool)
+	}
+	m.likes[postID][userID] = true
+	return nil
+}
+func (m *mockPostRepo) Unlike(ctx context.Context, postID, userID string) error {
+	delete(m.likes[postID], userID)
+	return nil
+}
+func (m *mockPostRepo) CountLikes(ctx context.Context, postID string) (int64, error) {
+	return int64(len(m.likes[postID])), nil
+}
+func (m *mockPostRepo) HasLiked(ctx context.Context, postID, userID string) (bool, error) {
+	return m.likes[postID][userID], nil
+}
+
+var _ repositories.PostRepository = (*mockPostRepo)(nil)
+
+func publishedPost(publishedAgo time.Duration) *entities.Post {
+	publishedAt := time.Now().Add(-publishedAgo)
+	return &entities.Post{
+		ID:          "post1",
+		UserID:      "user1",
+		Title:       "Hello world",
+		Content:     "Some content",
+		Slug:        "hello-world",
+		Status:      entities.PostStatusPublished,
+		PublishedAt: &publishedAt,
+	}
+}
+
+func newTitleUpdate(title string) *dto.UpdatePostRequest {
+	return &dto.UpdatePostRequest{Title: &title}
+}
+
+func TestUpdatePost_WithinEditLockWindow(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(2 * time.Minute))
+	svc := NewPostService(repo, false, nil, EditLockPolicy{Window: 10 * time.Minute}, nil, logger.New("info"))
+
+	_, err := svc.UpdatePost(context.Background(), "post1", newTitleUpdate("Edited"), "user1")
+	if err != apperrors.ErrPostLocked {
+		t.Fatalf("expected ErrPostLocked, got %v", err)
+	}
+	if len(repo.updated) != 0 {
+		t.Errorf("expected no repository update, got %d", len(repo.updated))
+	}
+}
+
+func TestUpdatePost_OutsideEditLockWindow(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(30 * time.Minute))
+	svc := NewPostService(repo, false, nil, EditLockPolicy{Window: 10 * time.Minute}, nil, logger.New("info"))
+
+	resp, err := svc.UpdatePost(context.Background(), "post1", newTitleUpdate("Edited"), "user1")
+	if err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+	if resp.Title != "Edited" {
+		t.Errorf("expected updated title, got %q", resp.Title)
+	}
+}
+
+func TestUpdatePost_AdminExemptFromEditLock(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(2 * time.Minute))
+	policy := EditLockPolicy{Window: 10 * time.Minute, AdminUserIDs: []string{"user1"}}
+	svc := NewPostService(repo, false, nil, policy, nil, logger.New("info"))
+
+	if _, err := svc.UpdatePost(context.Background(), "post1", newTitleUpdate("Edited"), "user1"); err != nil {
+		t.Fatalf("expected admin to bypass edit lock, got %v", err)
+	}
+}
+
+func TestUpdatePost_EditLockDisabledByDefault(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(time.Second))
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	if _, err := svc.UpdatePost(context.Background(), "post1", newTitleUpdate("Edited"), "user1"); err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+}
+
+func TestGetStats_IncludesDraftCountForUser(t *testing.T) {
+	draft := &entities.Post{ID: "post2", UserID: "user1", Title: "Draft", Content: "WIP", Slug: "draft", Status: entities.PostStatusDraft}
+	repo := newMockPostRepo(publishedPost(time.Hour), draft)
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	stats, err := svc.GetStats(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.UserPostsCount != 2 {
+		t.Errorf("expected 2 user posts, got %d", stats.UserPostsCount)
+	}
+	if stats.UserDraftCount != 1 {
+		t.Errorf("expected 1 draft, got %d", stats.UserDraftCount)
+	}
+}
+
+func TestGetStats_AnonymousSkipsUserCounts(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(time.Hour))
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	stats, err := svc.GetStats(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.UserDraftCount != 0 || repo.draftCalls != 0 {
+		t.Errorf("expected no draft lookup for anonymous caller, got count=%d calls=%d", stats.UserDraftCount, repo.draftCalls)
+	}
+}
+
+func TestSearchPosts_NoMatchesReturnsEmptyPage(t *testing.T) {
+	svc := NewPostService(newMockPostRepo(), false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	resp, err := svc.SearchPosts(context.Background(), &dto.SearchPostsRequest{Query: "nothing", Limit: 20})
+	if err != nil {
+		t.Fatalf("SearchPosts: %v", err)
+	}
+	if resp.Posts == nil || len(resp.Posts) != 0 || resp.Total != 0 {
+		t.Errorf("expected empty non-nil page with total 0, got posts=%v total=%d", resp.Posts, resp.Total)
+	}
+}
+
+func TestSearchPosts_RepositoryErrorIsPropagated(t *testing.T) {
+	repo := newMockPostRepo()
+	repo.searchErr = errors.New("connection reset")
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	_, err := svc.SearchPosts(context.Background(), &dto.SearchPostsRequest{Query: "hello", Limit: 20})
+	if err != apperrors.ErrPostSearchFailed {
+		t.Fatalf("expected ErrPostSearchFailed, got %v", err)
+	}
+}
+
+func TestGetPostBySlug_OwnerSeesDraft(t *testing.T) {
+	draft := &entities.Post{ID: "post-1", UserID: "author", Slug: "my-draft", Status: entities.PostStatusDraft}
+	svc := NewPostService(newMockPostRepo(draft), false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	resp, err := svc.GetPostBySlug(context.Background(), "my-draft", "author")
+	if err != nil {
+		t.Fatalf("expected the author to see their draft, got %v", err)
+	}
+	if resp.ID != "post-1" || resp.Published {
+		t.Fatalf("unexpected post: %+v", resp)
+	}
+}
+
+func TestGetPostBySlug_DraftHiddenFromOthers(t *testing.T) {
+	draft := &entities.Post{ID: "post-1", UserID: "author", Slug: "my-draft", Status: entities.PostStatusDraft}
+	svc := NewPostService(newMockPostRepo(draft), false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	for _, userID := range []string{"", "someone-else"} {
+		if _, err := svc.GetPostBySlug(context.Background(), "my-draft", userID); err != apperrors.ErrPostNotFound {
+			t.Fatalf("user %q: expected ErrPostNotFound, got %v", userID, err)
+		}
+	}
+}
+
+func TestClonePost_CreatesUnpublishedCopy(t *testing.T) {
+	publishedAt := time.Now().Add(-time.Hour)
+	original := &entities.Post{ID: "post-1", UserID: "author", Title: "Hello", Content: "World", Slug: "hello", Status: entities.PostStatusPublished, PublishedAt: &publishedAt}
+	repo := newMockPostRepo(original)
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	clone, err := svc.ClonePost(context.Background(), "post-1", "author")
+	if err != nil {
+		t.Fatalf("ClonePost: %v", err)
+	}
+	if clone.ID == "post-1" || clone.Slug != "hello-copy" || clone.Published {
+		t.Fatalf("expected an unpublished hello-copy draft with a new ID, got %+v", clone)
+	}
+	if clone.Title != "Hello" || clone.Content != "World" {
+		t.Fatalf("expected the content to be copied, got %+v", clone)
+	}
+
+	stored := repo.posts["post-1"]
+	if stored.Slug != "hello" || !stored.IsPublished() || len(repo.updated) != 0 {
+		t.Fatalf("expected the original to be unchanged, got %+v", stored)
+	}
+
+	second, err := svc.ClonePost(context.Background(), "post-1", "author")
+	if err != nil {
+		t.Fatalf("second ClonePost: %v", err)
+	}
+	if second.Slug != "hello-copy-2" {
+		t.Fatalf("expected hello-copy-2, got %s", second.Slug)
+	}
+}
+
+func TestClonePost_OnlyOwnerCanClone(t *testing.T) {
+	repo := newMockPostRepo(&entities.Post{ID: "post-1", UserID: "author", Title: "Hello", Content: "World", Slug: "hello", Status: entities.PostStatusPublished})
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	if _, err := svc.ClonePost(context.Background(), "post-1", "someone-else"); err != apperrors.ErrUnauthorizedAccess {
+		t.Fatalf("expected ErrUnauthorizedAccess, got %v", err)
+	}
+	if len(repo.posts) != 1 {
+		t.Fatalf("expected no copy to be created, got %d posts", len(repo.posts))
+	}
+}
+
+func TestCopySlug_FitsLengthLimit(t *testing.T) {
+	long := strings.Repeat("a", 98) + "-b"
+	slug := entities.CopySlug(long, 12)
+	if len(slug) > 100 || !strings.HasSuffix(slug, "-copy-12") || strings.Contains(slug, "--") {
+		t.Fatalf("unexpected copy slug %q (%d chars)", slug, len(slug))
+	}
+}
+
+func TestListPosts_DateRangeExcludingEverythingIsEmptyPage(t *testing.T) {
+	repo := newMockPostRepo()
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+	from := time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	resp, err := svc.ListPosts(context.Background(), &dto.ListPostsRequest{Limit: 20, From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("expected an empty page, got error %v", err)
+	}
+	if resp.Posts == nil || len(resp.Posts) != 0 || resp.Total != 0 {
+		t.Fatalf("expected an empty, non-nil page, got %+v", resp)
+	}
+	if repo.createdIn.From != &from || repo.createdIn.To != &to {
+		t.Fatalf("expected the range to reach the repository, got %+v", repo.createdIn)
+	}
+}
+
+func TestDeleteMany_SkipsPostsNotOwned(t *testing.T) {
+	repo := newMockPostRepo(
+		&entities.Post{ID: "mine-1", UserID: "author", Slug: "mine-1"},
+		&entities.Post{ID: "mine-2", UserID: "author", Slug: "mine-2"},
+		&entities.Post{ID: "theirs", UserID: "someone-else", Slug: "theirs"},
+	)
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	resp, err := svc.DeleteMany(context.Background(), []string{"mine-1", "theirs", "missing", "mine-2", "mine-1"}, "author")
+	if err != nil {
+		t.Fatalf("DeleteMany: %v", err)
+	}
+	if resp.Deleted != 2 {
+		t.Fatalf("expected 2 deleted, got %d", resp.Deleted)
+	}
+
+	want := []dto.BulkDeleteResult{{ID: "mine-1", Deleted: true}, {ID: "theirs"}, {ID: "missing"}, {ID: "mine-2", Deleted: true}}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("expected %d results, got %+v", len(want), resp.Results)
+	}
+	for i := range want {
+		if resp.Results[i] != want[i] {
+			t.Fatalf("result %d: expected %+v, got %+v", i, want[i], resp.Results[i])
+		}
+	}
+	if _, ok := repo.posts["theirs"]; !ok {
+		t.Fatal("expected another user's post to survive")
+	}
+}
+
+func TestDeleteMany_RejectsOversizedBatch(t *testing.T) {
+	svc := NewPostService(newMockPostRepo(), false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	ids := make([]string, dto.MaxBulkDeleteIDs+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("post-%d", i)
+	}
+	if _, err := svc.DeleteMany(context.Background(), ids, "author"); err != apperrors.ErrInvalidRequest {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+}
+
+func TestArchivePost_HidesPostFromPublicButNotOwner(t *testing.T) {
+	repo := newMockPostRepo(&entities.Post{ID: "post-1", UserID: "author", Title: "Old", Content: "News", Slug: "old-news", Status: entities.PostStatusPublished})
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	if _, err := svc.ArchivePost(context.Background(), "post-1", "someone-else"); err != apperrors.ErrUnauthorizedAccess {
+		t.Fatalf("expected ErrUnauthorizedAccess, got %v", err)
+	}
+
+	resp, err := svc.ArchivePost(context.Background(), "post-1", "author")
+	if err != nil {
+		t.Fatalf("ArchivePost: %v", err)
+	}
+	if resp.Status != string(entities.PostStatusArchived) || resp.Published {
+		t.Fatalf("expected an archived, unpublished post, got %+v", resp)
+	}
+
+	if _, err := svc.GetPostBySlug(context.Background(), "old-news", ""); err != apperrors.ErrPostNotFound {
+		t.Fatalf("expected archived post to be hidden from slug lookups, got %v", err)
+	}
+	if _, err := svc.GetPost(context.Background(), "post-1", "someone-else"); err != apperrors.ErrUnauthorizedAccess {
+		t.Fatalf("expected archived post to be hidden from other users, got %v", err)
+	}
+	if _, err := svc.GetPost(context.Background(), "post-1", "author"); err != nil {
+		t.Fatalf("expected owner to still see the archived post, got %v", err)
+	}
+}
+
+func TestGetUserPosts_OnlyOwnerListsArchived(t *testing.T) {
+	repo := newMockPostRepo(
+		&entities.Post{ID: "post-1", UserID: "author", Slug: "live", Status: entities.PostStatusPublished},
+		&entities.Post{ID: "post-2", UserID: "author", Slug: "retired", Status: entities.PostStatusArchived},
+	)
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	public, err := svc.GetUserPosts(context.Background(), "author", "", &dto.UserPostsRequest{Limit: 20})
+	if err != nil {
+		t.Fatalf("GetUserPosts: %v", err)
+	}
+	if len(public.Posts) != 1 || public.Posts[0].Slug != "live" {
+		t.Fatalf("expected only the published post by default, got %+v", public.Posts)
+	}
+
+	if _, err := svc.GetUserPosts(context.Background(), "author", "someone-else", &dto.UserPostsRequest{Limit: 20, Status: "archived"}); err != apperrors.ErrUnauthorizedAccess {
+		t.Fatalf("expected ErrUnauthorizedAccess, got %v", err)
+	}
+
+	archived, err := svc.GetUserPosts(context.Background(), "author", "author", &dto.UserPostsRequest{Limit: 20, Status: "archived"})
+	if err != nil {
+		t.Fatalf("GetUserPosts: %v", err)
+	}
+	if len(archived.Posts) != 1 || archived.Posts[0].Status != "archived" {
+		t.Fatalf("expected the archived post for its owner, got %+v", archived.Posts)
+	}
+}
+
+func TestRenderContentHTML_LeavesStoredContentUntouched(t *testing.T) {
+	source := "# Hello\n\n<script>alert(1)</script>"
+	repo := newMockPostRepo(&entities.Post{ID: "post-1", UserID: "author", Title: "Hello", Content: source, Slug: "hello", Status: entities.PostStatusPublished})
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	resp, err := svc.GetPost(context.Background(), "post-1", "")
+	if err != nil {
+		t.Fatalf("GetPost: %v", err)
+	}
+	if resp.ContentHTML != "" {
+		t.Fatalf("expected no HTML unless asked for, got %q", resp.ContentHTML)
+	}
+
+	svc.RenderContentHTML(resp)
+	if !strings.Contains(resp.ContentHTML, "<h1>Hello</h1>") || strings.Contains(resp.ContentHTML, "<script") {
+		t.Fatalf("unexpected rendered HTML %q", resp.ContentHTML)
+	}
+	if resp.Content != source || repo.posts["post-1"].Content != source {
+		t.Fatal("expected the raw Markdown content to be left as is")
+	}
+}
+
+func TestUpdatePost_SetsAndClearsCoverImage(t *testing.T) {
+	repo := newMockPostRepo(&entities.Post{ID: "post-1", UserID: "author", Title: "Hello", Content: "World", Slug: "hello", Status: entities.PostStatusDraft})
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	cover := "https://cdn.example.com/hello.png"
+	resp, err := svc.UpdatePost(context.Background(), "post-1", &dto.UpdatePostRequest{CoverImageURL: &cover}, "author")
+	if err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+	if resp.CoverImageURL != cover {
+		t.Fatalf("expected cover %q, got %q", cover, resp.CoverImageURL)
+	}
+
+	list, err := svc.GetUserPosts(context.Background(), "author", "author", &dto.UserPostsRequest{Limit: 20, Status: "draft"})
+	if err != nil {
+		t.Fatalf("GetUserPosts: %v", err)
+	}
+	if len(list.Posts) != 1 || list.Posts[0].CoverImageURL != cover {
+		t.Fatalf("expected the cover on the summary, got %+v", list.Posts)
+	}
+
+	empty := ""
+	resp, err = svc.UpdatePost(context.Background(), "post-1", &dto.UpdatePostRequest{CoverImageURL: &empty}, "author")
+	if err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+	if resp.CoverImageURL != "" {
+		t.Fatalf("expected the cover to be removed, got %q", resp.CoverImageURL)
+	}
+
+	bad := "javascript:alert(1)"
+	if _, err := svc.UpdatePost(context.Background(), "post-1", &dto.UpdatePostRequest{CoverImageURL: &bad}, "author"); err != apperrors.ErrInvalidPostData {
+		t.Fatalf("expected ErrInvalidPostData, got %v", err)
+	}
+}
+
+func TestLikePost_IsIdempotent(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(time.Hour))
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	for i := 0; i < 2; i++ {
+		state, err := svc.LikePost(context.Background(), "post1", "reader")
+		if err != nil {
+			t.Fatalf("LikePost #%d: %v", i+1, err)
+		}
+		if state.LikeCount != 1 || !state.LikedByMe {
+			t.Fatalf("expected one like by the reader, got %+v", state)
+		}
+	}
+
+	post, err := svc.GetPost(context.Background(), "post1", "reader")
+	if err != nil {
+		t.Fatalf("GetPost: %v", err)
+	}
+	if post.LikeCount != 1 || post.LikedByMe == nil || !*post.LikedByMe {
+		t.Fatalf("expected the post to show the reader's like, got count=%d liked=%v", post.LikeCount, post.LikedByMe)
+	}
+
+	for i := 0; i < 2; i++ {
+		state, err := svc.UnlikePost(context.Background(), "post1", "reader")
+		if err != nil {
+			t.Fatalf("UnlikePost #%d: %v", i+1, err)
+		}
+		if state.LikeCount != 0 || state.LikedByMe {
+			t.Fatalf("expected no likes, got %+v", state)
+		}
+	}
+}
+
+func TestLikePost_UnpublishedOnlyByOwner(t *testing.T) {
+	draft := &entities.Post{ID: "draft-1", UserID: "author", Slug: "draft", Status: entities.PostStatusDraft}
+	repo := newMockPostRepo(draft)
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	if _, err := svc.LikePost(context.Background(), "draft-1", "reader"); err != apperrors.ErrUnauthorizedAccess {
+		t.Fatalf("expected ErrUnauthorizedAccess, got %v", err)
+	}
+	if _, err := svc.LikePost(context.Background(), "draft-1", "author"); err != nil {
+		t.Fatalf("expected the owner to like their draft, got %v", err)
+	}
+	if _, err := svc.LikePost(context.Background(), "missing", "reader"); err != apperrors.ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+}
+
+func TestGetPost_AnonymousHasNoLikedByMe(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(time.Hour))
+	repo.likes["post1"] = map[string]bool{"reader": true}
+	svc := NewPostService(repo, false, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	post, err := svc.GetPostBySlug(context.Background(), "hello-world", "")
+	if err != nil {
+		t.Fatalf("GetPostBySlug: %v", err)
+	}
+	if post.LikeCount != 1 || post.LikedByMe != nil {
+		t.Fatalf("expected a count without liked_by_me, got count=%d liked=%v", post.LikeCount, post.LikedByMe)
+	}
+}
+
+func TestCreatePost_StoresCreatedEventInOutbox(t *testing.T) {
+	repo := newMockPostRepo()
+	svc := NewPostService(repo, true, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	post, err := svc.CreatePost(context.Background(), &dto.CreatePostRequest{Title: "Hello", Content: "Body"}, "author")
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	if len(repo.outbox) != 1 || repo.outbox[0].RoutingKey != messaging.RoutingKeyPostCreated {
+		t.Fatalf("expected one post.created event in the outbox, got %+v", repo.outbox)
+	}
+	if !strings.Contains(string(repo.outbox[0].Payload), post.ID) {
+		t.Fatalf("expected the event payload to name post %s, got %s", post.ID, repo.outbox[0].Payload)
+	}
+}
+
+func TestDeletePost_FailsWhenEventCannotBeStored(t *testing.T) {
+	repo := newMockPostRepo(publishedPost(time.Hour))
+	repo.outboxErr = errors.New("outbox unavailable")
+	svc := NewPostService(repo, true, nil, EditLockPolicy{}, nil, logger.New("info"))
+
+	if err := svc.DeletePost(context.Background(), "post1", "user1"); err != apperrors.ErrPostDeletionFailed {
+		t.Fatalf("expected the delete to fail with its event, got %v", err)
+	}
+}
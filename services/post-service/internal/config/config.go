@@ -11,14 +11,24 @@ type Config struct {
 	Port                     string
 	GRPCPort                 string
 	Environment              string
+	MetricsEnabled           bool // exposes /metrics and records Prometheus metrics
 	LogLevel                 string
+	LogFormat                string // "json" or "text"; defaults to json in production
+	Tracing                  TracingConfig
+	StartupRetry             StartupRetryConfig
 	Database                 DatabaseConfig
 	RabbitMQ                 RabbitMQConfig
 	GRPCTLS                  GRPCTLSConfig
 	Kafka                    KafkaConfig
+	EditLock                 EditLockConfig
+	ContentAllowedTags       []string // HTML tags allowed in post content on top of the UGC defaults
 	ServiceTransportSecurity string
 	InternalHTTPTrustMode    string
-	EnableGRPCReflection     bool
+	CORS                     CORSConfig
+	// InternalServiceToken is the shared secret (X-Internal-Token) the gateway
+	// presents; X-User-ID is only trusted alongside it. Required in production.
+	InternalServiceToken string
+	EnableGRPCReflection bool
 }
 
 // KafkaConfig configures publishing post change events for search indexing.
@@ -28,17 +38,39 @@ type KafkaConfig struct {
 	Enabled    bool // true when KAFKA_BROKERS is provided
 }
 
+// EditLockConfig configures the window after publishing during which a post
+// cannot be edited. AdminUserIDs are exempt.
+type EditLockConfig struct {
+	WindowMinutes int // 0 disables the lock
+	AdminUserIDs  []string
+}
+
+// StartupRetryConfig bounds how long the service waits for its dependencies
+// to accept connections at startup.
+type StartupRetryConfig struct {
+	Attempts         int
+	InitialBackoffMs int
+	MaxBackoffMs     int
+	MaxWaitSeconds   int
+}
+
 type DatabaseConfig struct {
 	URL             string
 	MaxOpenConns    int
 	MaxIdleConns    int
-	ConnMaxLifetime int
+	ConnMaxLifetime int // minutes
+	ConnMaxIdleTime int // minutes, 0 keeps idle connections until ConnMaxLifetime
+	ConnectTimeout  int // seconds allowed for the startup ping
 }
 
 type RabbitMQConfig struct {
 	URL          string
 	ExchangeName string
 	Enabled      bool
+	// RelayIntervalSeconds is how often the outbox relay polls for unsent
+	// events; OutboxRetentionHours how long sent ones are kept (0 keeps them).
+	RelayIntervalSeconds int
+	OutboxRetentionHours int
 }
 
 type GRPCTLSConfig struct {
@@ -49,22 +81,53 @@ type GRPCTLSConfig struct {
 	RequireClientCert bool
 }
 
+// CORSConfig lists the browser origins allowed to call the service directly.
+// An empty list sends no CORS headers; "*" must be configured explicitly and
+// is rejected together with credentials.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+}
+
+// TracingConfig enables OpenTelemetry trace export. An empty Endpoint keeps
+// tracing a no-op apart from propagating incoming trace context.
+type TracingConfig struct {
+	Endpoint    string
+	SampleRatio float64
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
-		Port:        getEnv("PORT", "8083"),
-		GRPCPort:    getEnv("GRPC_PORT", "50053"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
+		Port:           getEnv("PORT", "8083"),
+		GRPCPort:       getEnv("GRPC_PORT", "50053"),
+		Environment:    getEnv("ENVIRONMENT", "development"),
+		MetricsEnabled: getEnvAsBool("METRICS_ENABLED", true),
+		LogLevel:       getEnv("LOG_LEVEL", "info"),
+		LogFormat:      getEnv("LOG_FORMAT", defaultLogFormat(getEnv("ENVIRONMENT", "development"))),
+		Tracing: TracingConfig{
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			SampleRatio: getEnvAsFloat("OTEL_TRACES_SAMPLE_RATIO", 1),
+		},
+		StartupRetry: StartupRetryConfig{
+			Attempts:         getEnvAsInt("STARTUP_RETRY_ATTEMPTS", 10),
+			InitialBackoffMs: getEnvAsInt("STARTUP_RETRY_INITIAL_BACKOFF_MS", 500),
+			MaxBackoffMs:     getEnvAsInt("STARTUP_RETRY_MAX_BACKOFF_MS", 5000),
+			MaxWaitSeconds:   getEnvAsInt("STARTUP_RETRY_MAX_WAIT_SECONDS", 60),
+		},
 		Database: DatabaseConfig{
 			URL:             os.Getenv("DATABASE_URL"),
 			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
 			ConnMaxLifetime: getEnvAsInt("DB_CONN_MAX_LIFETIME", 60),
+			ConnMaxIdleTime: getEnvAsInt("DB_CONN_MAX_IDLE_TIME", 5),
+			ConnectTimeout:  getEnvAsInt("DB_CONNECT_TIMEOUT", 10),
 		},
 		RabbitMQ: RabbitMQConfig{
-			URL:          getEnv("RABBITMQ_URL", ""),
-			ExchangeName: getEnv("RABBITMQ_EXCHANGE", "blog_events"),
-			Enabled:      getEnv("RABBITMQ_URL", "") != "", // Enabled if URL is provided
+			URL:                  getEnv("RABBITMQ_URL", ""),
+			ExchangeName:         getEnv("RABBITMQ_EXCHANGE", "blog_events"),
+			Enabled:              getEnv("RABBITMQ_URL", "") != "", // Enabled if URL is provided
+			RelayIntervalSeconds: getEnvAsInt("OUTBOX_RELAY_INTERVAL_SECONDS", 1),
+			OutboxRetentionHours: getEnvAsInt("OUTBOX_RETENTION_HOURS", 168),
 		},
 		GRPCTLS: GRPCTLSConfig{
 			Enabled:           getEnvAsBool("GRPC_TLS_ENABLED", false),
@@ -78,9 +141,19 @@ func Load() (*Config, error) {
 			TopicPosts: getEnv("KAFKA_TOPIC_POSTS", "search.posts"),
 			Enabled:    getEnv("KAFKA_BROKERS", "") != "",
 		},
+		EditLock: EditLockConfig{
+			WindowMinutes: getEnvAsInt("POST_EDIT_LOCK_MINUTES", 0),
+			AdminUserIDs:  parseCSVEnv("POST_ADMIN_USER_IDS"),
+		},
+		ContentAllowedTags:       parseCSVEnv("POST_CONTENT_ALLOWED_TAGS"),
 		ServiceTransportSecurity: resolveTransportSecurityMode(getEnv("SERVICE_TRANSPORT_SECURITY", ""), getEnv("ENVIRONMENT", "development"), getEnvAsBool("GRPC_TLS_ENABLED", false)),
 		InternalHTTPTrustMode:    resolveInternalHTTPTrustMode(getEnv("INTERNAL_HTTP_TRUST_MODE", ""), getEnv("ENVIRONMENT", "development")),
-		EnableGRPCReflection:     getEnvAsBool("GRPC_REFLECTION_ENABLED", getEnv("ENVIRONMENT", "development") != "production"),
+		CORS: CORSConfig{
+			AllowedOrigins:   parseCSVEnv("CORS_ALLOWED_ORIGINS"),
+			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", true),
+		},
+		InternalServiceToken: getEnv("INTERNAL_SERVICE_TOKEN", ""),
+		EnableGRPCReflection: getEnvAsBool("GRPC_REFLECTION_ENABLED", getEnv("ENVIRONMENT", "development") != "production"),
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -90,6 +163,22 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+func containsWildcard(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultLogFormat(environment string) string {
+	if environment == "production" {
+		return "json"
+	}
+	return "text"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -98,12 +187,39 @@ func getEnv(key, defaultValue string) string {
 }
 
 func (c *Config) validate() error {
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		return fmt.Errorf("LOG_FORMAT must be json or text")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("OTEL_TRACES_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if c.StartupRetry.Attempts < 1 || c.StartupRetry.MaxWaitSeconds < 1 {
+		return fmt.Errorf("STARTUP_RETRY_ATTEMPTS and STARTUP_RETRY_MAX_WAIT_SECONDS must be at least 1")
+	}
+	if c.StartupRetry.InitialBackoffMs < 0 || c.StartupRetry.MaxBackoffMs < c.StartupRetry.InitialBackoffMs {
+		return fmt.Errorf("STARTUP_RETRY_MAX_BACKOFF_MS must be at least STARTUP_RETRY_INITIAL_BACKOFF_MS")
+	}
 	if c.Database.URL == "" {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
+	if c.Database.MaxOpenConns < 1 {
+		return fmt.Errorf("DB_MAX_OPEN_CONNS must be at least 1")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+	}
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 {
+		return fmt.Errorf("DB_CONN_MAX_LIFETIME and DB_CONN_MAX_IDLE_TIME must not be negative")
+	}
+	if c.Database.ConnectTimeout < 1 {
+		return fmt.Errorf("DB_CONNECT_TIMEOUT must be at least 1 second")
+	}
 	if c.GRPCPort == "" {
 		return fmt.Errorf("GRPC_PORT is required")
 	}
+	if c.EditLock.WindowMinutes < 0 {
+		return fmt.Errorf("POST_EDIT_LOCK_MINUTES must not be negative")
+	}
 	if c.GRPCTLS.Enabled {
 		if c.GRPCTLS.CAFile == "" {
 			return fmt.Errorf("GRPC_TLS_CA_FILE is required when GRPC_TLS_ENABLED=true")
@@ -121,6 +237,12 @@ func (c *Config) validate() error {
 	if err := validateInternalHTTPTrustMode(c.Environment, c.InternalHTTPTrustMode); err != nil {
 		return err
 	}
+	if err := validateInternalServiceToken(c.Environment, c.InternalServiceToken); err != nil {
+		return err
+	}
+	if c.CORS.AllowCredentials && containsWildcard(c.CORS.AllowedOrigins) {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS cannot contain * when CORS_ALLOW_CREDENTIALS=true")
+	}
 	if c.Environment == "production" && c.EnableGRPCReflection {
 		return fmt.Errorf("GRPC_REFLECTION_ENABLED cannot be true in production")
 	}
@@ -136,6 +258,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -227,3 +358,18 @@ func validateInternalHTTPTrustMode(environment, mode string) error {
 		return fmt.Errorf("INTERNAL_HTTP_TRUST_MODE must be one of private_network, disabled, insecure_dev")
 	}
 }
+
+// validateInternalServiceToken fails closed: production refuses to start
+// without a token, since X-User-ID would otherwise be unverifiable.
+func validateInternalServiceToken(environment, token string) error {
+	if token == "" {
+		if environment == "production" {
+			return fmt.Errorf("INTERNAL_SERVICE_TOKEN is required in production")
+		}
+		return nil
+	}
+	if len(token) < 32 {
+		return fmt.Errorf("INTERNAL_SERVICE_TOKEN must be at least 32 characters")
+	}
+	return nil
+}
@@ -28,3 +28,46 @@ func TestValidateInternalHTTPTrustMode(t *testing.T) {
 		t.Fatal("expected production to reject insecure_dev")
 	}
 }
+
+func TestValidateRejectsInvalidPoolSettings(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			LogFormat:    "text",
+			GRPCPort:     "50053",
+			StartupRetry: StartupRetryConfig{Attempts: 1, MaxWaitSeconds: 1},
+			Database: DatabaseConfig{
+				URL:            "postgres://localhost/posts",
+				MaxOpenConns:   25,
+				MaxIdleConns:   10,
+				ConnectTimeout: 10,
+			},
+		}
+	}
+
+	cfg := valid()
+	cfg.Database.MaxIdleConns = 30
+	if err := cfg.validate(); err == nil || err.Error() != "DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS" {
+		t.Fatalf("expected idle > open to be rejected, got %v", err)
+	}
+
+	cfg = valid()
+	cfg.Database.ConnectTimeout = 0
+	if err := cfg.validate(); err == nil || err.Error() != "DB_CONNECT_TIMEOUT must be at least 1 second" {
+		t.Fatalf("expected a zero connect timeout to be rejected, got %v", err)
+	}
+}
+
+func TestValidateInternalServiceToken(t *testing.T) {
+	if err := validateInternalServiceToken("production", ""); err == nil {
+		t.Fatal("expected production to require INTERNAL_SERVICE_TOKEN")
+	}
+	if err := validateInternalServiceToken("development", ""); err != nil {
+		t.Fatalf("expected an empty token outside production to be allowed: %v", err)
+	}
+	if err := validateInternalServiceToken("production", "short"); err == nil {
+		t.Fatal("expected a short token to be rejected")
+	}
+	if err := validateInternalServiceToken("production", "0123456789abcdef0123456789abcdef"); err != nil {
+		t.Fatalf("expected a 32-character token to be accepted: %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// OutboxEvent is a domain event stored in the same transaction as the change
+// it describes. The outbox relay publishes it afterwards, so a crash between
+// commit and publish delays the event instead of losing it.
+type OutboxEvent struct {
+	ID         int64             `json:"id" db:"id"`
+	RoutingKey string            `json:"routing_key" db:"routing_key"`
+	Payload    []byte            `json:"payload" db:"payload"` // JSON event body
+	Headers    map[string]string `json:"headers" db:"headers"` // trace context of the request that caused it
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+}
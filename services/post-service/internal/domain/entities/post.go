@@ -2,43 +2,82 @@ package entities
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+const (
+	maxSlugLength          = 100
+	maxCoverImageURLLength = 2048
+	MaxExcerptLength       = 300 // characters, for author-written excerpts
+)
+
+// PostStatus is where a post is in its lifecycle. Only published posts are
+// public; drafts and archived posts are visible to their owner alone.
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+func (s PostStatus) IsValid() bool {
+	switch s {
+	case PostStatusDraft, PostStatusPublished, PostStatusArchived:
+		return true
+	default:
+		return false
+	}
+}
+
 type Post struct {
-	ID        string    `json:"id" db:"id"`
-	UserID    string    `json:"user_id" db:"user_id"`
-	Title     string    `json:"title" db:"title"`
-	Content   string    `json:"content" db:"content"`
-	Slug      string    `json:"slug" db:"slug"`
-	Published bool      `json:"published" db:"published"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID            string     `json:"id" db:"id"`
+	UserID        string     `json:"user_id" db:"user_id"`
+	Title         string     `json:"title" db:"title"`
+	Content       string     `json:"content" db:"content"`
+	Slug          string     `json:"slug" db:"slug"`
+	Status        PostStatus `json:"status" db:"status"`
+	CoverImageURL string     `json:"cover_image_url,omitempty" db:"cover_image_url"`
+	Excerpt       string     `json:"excerpt,omitempty" db:"excerpt"` // author-written; empty means generated from content
+	PublishedAt   *time.Time `json:"published_at,omitempty" db:"published_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type PostSummary struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Title     string    `json:"title"`
-	Slug      string    `json:"slug"`
-	Published bool      `json:"published"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string     `json:"id"`
+	UserID        string     `json:"user_id"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	Status        PostStatus `json:"status"`
+	CoverImageURL string     `json:"cover_image_url,omitempty"`
+	Excerpt       string     `json:"excerpt,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 func (p *Post) ToSummary() *PostSummary {
 	return &PostSummary{
-		ID:        p.ID,
-		UserID:    p.UserID,
-		Title:     p.Title,
-		Slug:      p.Slug,
-		Published: p.Published,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
+		ID:            p.ID,
+		UserID:        p.UserID,
+		Title:         p.Title,
+		Slug:          p.Slug,
+		Status:        p.Status,
+		CoverImageURL: p.CoverImageURL,
+		Excerpt:       p.Excerpt,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
 	}
 }
 
+// IsPublished reports whether the post is public.
+func (p *Post) IsPublished() bool {
+	return p.Status == PostStatusPublished
+}
+
 func (p *Post) IsValid() error {
 	if strings.TrimSpace(p.ID) == "" {
 		return fmt.Errorf("post ID is required")
@@ -72,6 +111,22 @@ func (p *Post) IsValid() error {
 		return fmt.Errorf("invalid slug format")
 	}
 
+	if len(p.CoverImageURL) > maxCoverImageURLLength {
+		return fmt.Errorf("cover image URL must be at most %d characters", maxCoverImageURLLength)
+	}
+
+	if p.CoverImageURL != "" && !isValidURL(p.CoverImageURL) {
+		return fmt.Errorf("invalid cover image URL")
+	}
+
+	if utf8.RuneCountInString(p.Excerpt) > MaxExcerptLength {
+		return fmt.Errorf("excerpt must be at most %d characters", MaxExcerptLength)
+	}
+
+	if !p.Status.IsValid() {
+		return fmt.Errorf("invalid status %q", p.Status)
+	}
+
 	return nil
 }
 
@@ -79,6 +134,17 @@ func (p *Post) Sanitize() {
 	p.Title = strings.TrimSpace(p.Title)
 	p.Content = strings.TrimSpace(p.Content)
 	p.Slug = strings.ToLower(strings.TrimSpace(p.Slug))
+	p.CoverImageURL = strings.TrimSpace(p.CoverImageURL)
+	p.Excerpt = strings.TrimSpace(p.Excerpt)
+	if p.Status == "" {
+		p.Status = PostStatusDraft
+	}
+}
+
+// SanitizeContent replaces Content with clean(Content). The HTML policy lives
+// outside the entity so it can follow the service configuration.
+func (p *Post) SanitizeContent(clean func(string) string) {
+	p.Content = strings.TrimSpace(clean(p.Content))
 }
 
 func (p *Post) GenerateSlug() {
@@ -87,8 +153,21 @@ func (p *Post) GenerateSlug() {
 	}
 }
 
+// CopySlug derives the slug for the n-th copy of a post: "<slug>-copy", then
+// "<slug>-copy-2" and so on, trimmed to fit the slug length limit.
+func CopySlug(slug string, n int) string {
+	suffix := "-copy"
+	if n > 1 {
+		suffix = fmt.Sprintf("-copy-%d", n)
+	}
+	if len(slug)+len(suffix) > maxSlugLength {
+		slug = strings.TrimSuffix(slug[:maxSlugLength-len(suffix)], "-")
+	}
+	return slug + suffix
+}
+
 func isValidSlug(slug string) bool {
-	if len(slug) < 3 || len(slug) > 100 {
+	if len(slug) < 3 || len(slug) > maxSlugLength {
 		return false
 	}
 
@@ -102,7 +181,7 @@ func isValidSlug(slug string) bool {
 }
 
 func slugify(text string) string {
-	text = strings.ToLower(text)
+	text = transliterate(text)
 	text = strings.ReplaceAll(text, " ", "-")
 
 	var result strings.Builder
@@ -120,8 +199,8 @@ func slugify(text string) string {
 		slug = strings.ReplaceAll(slug, "--", "-")
 	}
 
-	if len(slug) > 100 {
-		slug = slug[:100]
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
 		slug = strings.TrimSuffix(slug, "-")
 	}
 
@@ -131,3 +210,8 @@ func slugify(text string) string {
 
 	return slug
 }
+
+func isValidURL(url string) bool {
+	urlRegex := regexp.MustCompile(`^https?://[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}(/.*)?$`)
+	return urlRegex.MatchString(url)
+}
@@ -0,0 +1,65 @@
+package entities
+
+import "testing"
+
+func TestGenerateSlug_TransliteratesUnicodeTitles(t *testing.T) {
+	cases := []struct {
+		title string
+		want  string
+	}{
+		{"Hello World", "hello-world"},
+		{"Café déjà vu", "cafe-deja-vu"},
+		{"Straße über Åland", "strasse-uber-aland"},
+		{"Привет, мир", "privet-mir"},
+		{"Щука и ёж", "shchuka-i-yozh"},
+		{"Αθήνα", "athina"},
+		{"Rocket 🚀 launch", "rocket-launch"},
+		{"🚀🚀", "post"},
+		{"你好", "post"},
+	}
+
+	for _, tc := range cases {
+		post := &Post{Title: tc.title}
+		post.GenerateSlug()
+		if post.Slug != tc.want {
+			t.Errorf("slug for %q: expected %q, got %q", tc.title, tc.want, post.Slug)
+		}
+		if !isValidSlug(post.Slug) {
+			t.Errorf("slug for %q is not valid: %q", tc.title, post.Slug)
+		}
+	}
+}
+
+func TestIsValid_RejectsUnknownStatus(t *testing.T) {
+	post := &Post{ID: "post-1", UserID: "user-1", Title: "Title", Content: "Body", Slug: "title", Status: "hidden"}
+	if err := post.IsValid(); err == nil {
+		t.Fatal("expected an unknown status to be rejected")
+	}
+
+	post.Status = ""
+	post.Sanitize()
+	if post.Status != PostStatusDraft {
+		t.Fatalf("expected an empty status to default to draft, got %q", post.Status)
+	}
+	if err := post.IsValid(); err != nil {
+		t.Fatalf("expected a draft to be valid, got %v", err)
+	}
+}
+
+func TestIsValid_CoverImageURL(t *testing.T) {
+	cases := map[string]bool{
+		"":                                  true,
+		"https://cdn.example.com/cover.png": true,
+		"http://example.com":                true,
+		"ftp://example.com/cover.png":       false,
+		"javascript:alert(1)":               false,
+		"cover.png":                         false,
+	}
+
+	for url, valid := range cases {
+		post := &Post{ID: "post-1", UserID: "user-1", Title: "Title", Content: "Body", Slug: "title", Status: PostStatusDraft, CoverImageURL: url}
+		if err := post.IsValid(); (err == nil) != valid {
+			t.Errorf("cover image URL %q: expected valid=%t, got %v", url, valid, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stripMarks folds accented letters to their base letter: "é" becomes "e".
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// romanization spells out letters that have no ASCII base letter to fold to.
+// It covers Latin ligatures and special letters, Cyrillic (Russian and
+// Ukrainian, roughly BGN/PCGN) and Greek. Keys are lower case.
+var romanization = map[rune]string{
+	// Latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th",
+	'ł': "l", 'ı': "i", 'ħ': "h", 'ŧ': "t",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// transliterate lower-cases text and rewrites it in ASCII where it can:
+// accents are dropped and the scripts in romanization are spelled out. Runes
+// it has no spelling for, such as CJK or emoji, are left for slugify to drop.
+func transliterate(text string) string {
+	// Look composed letters up before their base letter, so "й" is romanized
+	// as "y" rather than as "и" while "ή" still falls back to "η".
+	var spelled strings.Builder
+	for _, char := range norm.NFC.String(strings.ToLower(text)) {
+		if latin, ok := romanization[char]; ok {
+			spelled.WriteString(latin)
+			continue
+		}
+		base := []rune(norm.NFD.String(string(char)))
+		if latin, ok := romanization[base[0]]; ok {
+			spelled.WriteString(latin)
+			continue
+		}
+		spelled.WriteRune(char)
+	}
+
+	folded, _, err := transform.String(stripMarks, spelled.String())
+	if err != nil {
+		return spelled.String()
+	}
+	return folded
+}
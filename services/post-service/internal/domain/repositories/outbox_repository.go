@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"post-service/internal/domain/entities"
+)
+
+// OutboxRepository is the relay's view of the event outbox. Events are added
+// through PostRepository.AddOutboxEvent, inside the post's transaction.
+type OutboxRepository interface {
+	// ListUnsent returns up to limit unsent events, oldest first.
+	ListUnsent(ctx context.Context, limit int) ([]*entities.OutboxEvent, error)
+	MarkSent(ctx context.Context, id int64) error
+	// DeleteSentBefore removes events sent before the cutoff and returns how many.
+	DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
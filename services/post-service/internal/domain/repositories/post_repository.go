@@ -2,20 +2,49 @@ package repositories
 
 import (
 	"context"
+	"time"
+
 	"post-service/internal/domain/entities"
 )
 
+// DateRange bounds created_at, inclusive at both ends. A nil end is open.
+type DateRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
 type PostRepository interface {
+	// InTx runs fn with a repository bound to one transaction, committed when
+	// fn returns nil and rolled back otherwise. Calls on a repository that is
+	// already in a transaction join it.
+	InTx(ctx context.Context, fn func(tx PostRepository) error) error
+	// AddOutboxEvent stores event for the outbox relay to publish. Call it on
+	// the repository InTx passes, so the event commits with the change.
+	AddOutboxEvent(ctx context.Context, event *entities.OutboxEvent) error
 	Create(ctx context.Context, post *entities.Post) error
 	GetByID(ctx context.Context, id string) (*entities.Post, error)
 	GetBySlug(ctx context.Context, slug string) (*entities.Post, error)
-	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*entities.Post, error)
+	GetBySlugForUser(ctx context.Context, slug, userID string) (*entities.Post, error)
+	GetByUserID(ctx context.Context, userID string, status entities.PostStatus, limit, offset int) ([]*entities.Post, error)
+	// GetByPreviewToken returns the post whose unexpired preview token hashes to tokenHash.
+	GetByPreviewToken(ctx context.Context, tokenHash string) (*entities.Post, error)
+	// SetPreviewToken replaces the post's preview token; an empty tokenHash revokes it.
+	SetPreviewToken(ctx context.Context, id, tokenHash string, expiresAt *time.Time) error
 	Update(ctx context.Context, post *entities.Post) error
 	Delete(ctx context.Context, id string) error
-	List(ctx context.Context, limit, offset int, publishedOnly bool) ([]*entities.Post, error)
-	Search(ctx context.Context, query string, limit, offset int, publishedOnly bool) ([]*entities.Post, error)
+	// DeleteMany deletes those of ids owned by userID and returns the deleted posts.
+	DeleteMany(ctx context.Context, ids []string, userID string) ([]*entities.Post, error)
+	List(ctx context.Context, limit, offset int, publishedOnly bool, createdIn DateRange) ([]*entities.Post, error)
+	Search(ctx context.Context, query string, limit, offset int, publishedOnly bool, createdIn DateRange) ([]*entities.Post, error)
 	Exists(ctx context.Context, id string) (bool, error)
 	ExistsBySlug(ctx context.Context, slug string) (bool, error)
 	GetPublishedCount(ctx context.Context) (int64, error)
 	GetUserPostsCount(ctx context.Context, userID string) (int64, error)
+	GetUserDraftCount(ctx context.Context, userID string) (int64, error)
+	// Like records userID's like of postID; liking a post twice is a no-op.
+	Like(ctx context.Context, postID, userID string) error
+	// Unlike removes userID's like of postID, if there is one.
+	Unlike(ctx context.Context, postID, userID string) error
+	CountLikes(ctx context.Context, postID string) (int64, error)
+	HasLiked(ctx context.Context, postID, userID string) (bool, error)
 }
@@ -0,0 +1,145 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"post-service/internal/domain/entities"
+	"post-service/internal/domain/repositories"
+	"post-service/pkg/logger"
+)
+
+// NewOutboxEvent builds the outbox row for event, carrying the trace context
+// of ctx so the relay's publish joins the request's trace.
+func NewOutboxEvent(ctx context.Context, routingKey string, event interface{}) (*entities.OutboxEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	headers := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, headers)
+
+	return &entities.OutboxEvent{RoutingKey: routingKey, Payload: payload, Headers: headers}, nil
+}
+
+// outboxPublisher sends one outbox event and reports whether the broker
+// confirmed it. EnsureConnected (re)connects to the broker when needed.
+type outboxPublisher interface {
+	EnsureConnected() error
+	PublishOutboxEvent(ctx context.Context, event *entities.OutboxEvent) error
+}
+
+// OutboxRelayConfig tunes the relay.
+type OutboxRelayConfig struct {
+	Interval  time.Duration // pause between polls once the outbox is drained
+	BatchSize int           // events read per query
+	Retention time.Duration // how long sent events are kept; zero keeps them
+}
+
+// OutboxRelay publishes outbox events in the order they were written. An event
+// is marked sent only after the broker confirmed it, so delivery is at least
+// once: a relay stopped between the confirm and the mark sends the event
+// again on its next run, with the same message ID. The relay owns the broker
+// connection: every run connects first if the connection is down, so events
+// stored while RabbitMQ was unreachable go out once it is back.
+type OutboxRelay struct {
+	store     repositories.OutboxRepository
+	publisher outboxPublisher
+	config    OutboxRelayConfig
+	logger    *logger.Logger
+	lastPrune time.Time
+}
+
+func NewOutboxRelay(store repositories.OutboxRepository, publisher *EventPublisher, config OutboxRelayConfig, logger *logger.Logger) *OutboxRelay {
+	return newOutboxRelay(store, publisher, config, logger)
+}
+
+func newOutboxRelay(store repositories.OutboxRepository, publisher outboxPublisher, config OutboxRelayConfig, logger *logger.Logger) *OutboxRelay {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	return &OutboxRelay{store: store, publisher: publisher, config: config, logger: logger}
+}
+
+// Run relays events until ctx is done.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := r.RunOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.logger.Error(fmt.Sprintf("Outbox relay failed: %v", err))
+				}
+				break
+			}
+			// A full batch means more are waiting; read on without pausing.
+			if sent < r.config.BatchSize {
+				break
+			}
+		}
+		r.prune(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce publishes one batch of unsent events and returns how many were sent.
+// It stops at the first event that fails, so later events do not overtake it;
+// that event and the rest are tried again on the next run.
+func (r *OutboxRelay) RunOnce(ctx context.Context) (int, error) {
+	if err := r.publisher.EnsureConnected(); err != nil {
+		return 0, err
+	}
+	events, err := r.store.ListUnsent(ctx, r.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		if err := r.publisher.PublishOutboxEvent(ctx, event); err != nil {
+			return sent, fmt.Errorf("failed to publish outbox event %d: %w", event.ID, err)
+		}
+		if err := r.store.MarkSent(ctx, event.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// prune deletes sent events past the retention, at most once an hour.
+func (r *OutboxRelay) prune(ctx context.Context) {
+	if r.config.Retention <= 0 || time.Since(r.lastPrune) < time.Hour {
+		return
+	}
+	r.lastPrune = time.Now()
+
+	deleted, err := r.store.DeleteSentBefore(ctx, time.Now().Add(-r.config.Retention))
+	if err != nil {
+		r.logger.Warn(fmt.Sprintf("Failed to prune sent outbox events: %v", err))
+		return
+	}
+	if deleted > 0 {
+		r.logger.Info(fmt.Sprintf("Pruned %d sent outbox event(s)", deleted))
+	}
+}
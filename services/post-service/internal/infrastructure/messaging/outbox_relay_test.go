@@ -0,0 +1,185 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"post-service/internal/domain/entities"
+	"post-service/pkg/logger"
+)
+
+// memoryOutbox is an OutboxRepository over a slice. markFails makes MarkSent
+// fail once for the given event IDs, as a relay killed right after the broker
+// confirmed the event would.
+type memoryOutbox struct {
+	events    []*entities.OutboxEvent
+	sent      map[int64]bool
+	markFails map[int64]bool
+}
+
+func newMemoryOutbox(n int) *memoryOutbox {
+	store := &memoryOutbox{sent: make(map[int64]bool), markFails: make(map[int64]bool)}
+	for i := 1; i <= n; i++ {
+		store.events = append(store.events, &entities.OutboxEvent{ID: int64(i), RoutingKey: RoutingKeyPostCreated})
+	}
+	return store
+}
+
+func (s *memoryOutbox) ListUnsent(ctx context.Context, limit int) ([]*entities.OutboxEvent, error) {
+	var unsent []*entities.OutboxEvent
+	for _, event := range s.events {
+		if !s.sent[event.ID] && len(unsent) < limit {
+			unsent = append(unsent, event)
+		}
+	}
+	return unsent, nil
+}
+
+func (s *memoryOutbox) MarkSent(ctx context.Context, id int64) error {
+	if s.markFails[id] {
+		delete(s.markFails, id)
+		return errors.New("connection reset")
+	}
+	s.sent[id] = true
+	return nil
+}
+
+func (s *memoryOutbox) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+// recordingPublisher records every event it confirms. It cancels the relay's
+// context after stopAfter publishes, and fails the events in fail. While down
+// is set it cannot connect.
+type recordingPublisher struct {
+	delivered []int64
+	stopAfter int
+	cancel    context.CancelFunc
+	fail      map[int64]bool
+	down      bool
+	connects  int
+}
+
+func (p *recordingPublisher) EnsureConnected() error {
+	if p.down {
+		return errors.New("connection refused")
+	}
+	p.connects++
+	return nil
+}
+
+func (p *recordingPublisher) PublishOutboxEvent(ctx context.Context, event *entities.OutboxEvent) error {
+	if p.fail[event.ID] {
+		return errors.New("nacked")
+	}
+	p.delivered = append(p.delivered, event.ID)
+	if p.cancel != nil && len(p.delivered) == p.stopAfter {
+		p.cancel()
+	}
+	return nil
+}
+
+// assertDeliveredInOrder checks that every one of n events was delivered at
+// least once and that no event was first delivered before an earlier one.
+func assertDeliveredInOrder(t *testing.T, delivered []int64, n int) {
+	t.Helper()
+	var next int64 = 1
+	for _, id := range delivered {
+		if id > next {
+			t.Fatalf("event %d delivered before event %d: %v", id, next, delivered)
+		}
+		if id == next {
+			next++
+		}
+	}
+	if next != int64(n)+1 {
+		t.Fatalf("expected all %d events delivered, got %v", n, delivered)
+	}
+}
+
+func TestOutboxRelay_KilledMidRunResumesWhereItStopped(t *testing.T) {
+	store := newMemoryOutbox(5)
+	ctx, cancel := context.WithCancel(context.Background())
+	publisher := &recordingPublisher{stopAfter: 2, cancel: cancel}
+
+	if _, err := newOutboxRelay(store, publisher, OutboxRelayConfig{}, logger.New("info")).RunOnce(ctx); err == nil {
+		t.Fatal("expected the killed run to stop with an error")
+	}
+
+	// A fresh relay, as after a restart, picks up the rest.
+	publisher.cancel = nil
+	sent, err := newOutboxRelay(store, publisher, OutboxRelayConfig{}, logger.New("info")).RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if sent == 0 {
+		t.Fatal("expected the restarted relay to send the remaining events")
+	}
+	assertDeliveredInOrder(t, publisher.delivered, 5)
+}
+
+func TestOutboxRelay_KilledBeforeMarkSentRedelivers(t *testing.T) {
+	store := newMemoryOutbox(3)
+	store.markFails[2] = true
+	publisher := &recordingPublisher{}
+	relay := newOutboxRelay(store, publisher, OutboxRelayConfig{}, logger.New("info"))
+
+	if sent, err := relay.RunOnce(context.Background()); err == nil || sent != 1 {
+		t.Fatalf("expected the run to stop after event 1, got %d sent, %v", sent, err)
+	}
+	if _, err := relay.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	want := []int64{1, 2, 2, 3}
+	if len(publisher.delivered) != len(want) {
+		t.Fatalf("expected deliveries %v, got %v", want, publisher.delivered)
+	}
+	for i := range want {
+		if publisher.delivered[i] != want[i] {
+			t.Fatalf("expected deliveries %v, got %v", want, publisher.delivered)
+		}
+	}
+}
+
+func TestOutboxRelay_FailedEventHoldsBackLaterOnes(t *testing.T) {
+	store := newMemoryOutbox(3)
+	publisher := &recordingPublisher{fail: map[int64]bool{2: true}}
+	relay := newOutboxRelay(store, publisher, OutboxRelayConfig{}, logger.New("info"))
+
+	if _, err := relay.RunOnce(context.Background()); err == nil {
+		t.Fatal("expected the run to stop at the failed event")
+	}
+	if len(publisher.delivered) != 1 || store.sent[3] {
+		t.Fatalf("expected event 3 to wait behind event 2, got %v", publisher.delivered)
+	}
+
+	delete(publisher.fail, 2)
+	if _, err := relay.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	assertDeliveredInOrder(t, publisher.delivered, 3)
+}
+
+func TestOutboxRelay_ConnectsOnceBrokerIsUp(t *testing.T) {
+	store := newMemoryOutbox(2)
+	publisher := &recordingPublisher{down: true}
+	relay := newOutboxRelay(store, publisher, OutboxRelayConfig{}, logger.New("info"))
+
+	if _, err := relay.RunOnce(context.Background()); err == nil {
+		t.Fatal("expected the run to fail while the broker is down")
+	}
+	if len(publisher.delivered) != 0 || store.sent[1] {
+		t.Fatalf("expected the events to stay unsent, got %v", publisher.delivered)
+	}
+
+	publisher.down = false
+	if sent, err := relay.RunOnce(context.Background()); err != nil || sent != 2 {
+		t.Fatalf("expected both events sent once connected, got %d, %v", sent, err)
+	}
+	if publisher.connects != 1 {
+		t.Fatalf("expected one connect, got %d", publisher.connects)
+	}
+}
@@ -1,19 +1,56 @@
 package messaging
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"sync"
+	"time"
+
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"post-service/internal/domain/entities"
 	"post-service/pkg/logger"
-	"time"
+	"post-service/pkg/metrics"
+	"post-service/pkg/tracing"
+)
+
+// amqpChannel is the part of *amqp.Channel the publisher uses.
+type amqpChannel interface {
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+	Close() error
+}
+
+const (
+	// DefaultConfirmTimeout bounds the wait for the broker to confirm an event.
+	DefaultConfirmTimeout = 5 * time.Second
+
+	// confirmBuffer holds confirmations that arrive after their publish timed
+	// out, so the connection is not blocked delivering them.
+	confirmBuffer = 64
 )
 
+// EventPublisher publishes events from the transactional outbox to RabbitMQ.
+// An event counts as sent once the broker confirms it; until then its outbox
+// row stays unsent and the relay tries again.
 type EventPublisher struct {
+	mu           sync.Mutex // guards connection and channel, and keeps sends in order
 	connection   *amqp.Connection
-	channel      *amqp.Channel
+	channel      amqpChannel
+	confirms     chan amqp.Confirmation // publisher confirms of channel
+	deliveryTag  uint64                 // tag of the last event sent on channel
+	url          string
 	exchangeName string
 	logger       *logger.Logger
-	done         chan error
+
+	confirmTimeout time.Duration
 }
 
 type PostCreatedEvent struct {
@@ -41,134 +78,205 @@ type PostDeletedEvent struct {
 	DeletedAt time.Time `json:"deleted_at"`
 }
 
-func NewEventPublisher(rabbitMQURL, exchangeName string, logger *logger.Logger) (*EventPublisher, error) {
-	conn, err := amqp.Dial(rabbitMQURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
-	}
-
-	ch, err := conn.Channel()
-	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+// NewEventPublisher returns a publisher for rabbitMQURL. It does not connect:
+// the outbox relay calls EnsureConnected before it publishes, so the service
+// starts, and keeps storing events, while RabbitMQ is unreachable.
+func NewEventPublisher(rabbitMQURL, exchangeName string, logger *logger.Logger) *EventPublisher {
+	return &EventPublisher{
+		url:            rabbitMQURL,
+		exchangeName:   exchangeName,
+		logger:         logger,
+		confirmTimeout: DefaultConfirmTimeout,
 	}
+}
 
-	// Declare exchange
-	err = ch.ExchangeDeclare(
-		exchangeName, // name
-		"topic",      // type
-		true,         // durable
-		false,        // auto-deleted
-		false,        // internal
-		false,        // no-wait
-		nil,          // arguments
-	)
-	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("failed to declare exchange: %w", err)
-	}
+// Routing keys of the post events.
+const (
+	RoutingKeyPostCreated = "post.created"
+	RoutingKeyPostUpdated = "post.updated"
+	RoutingKeyPostDeleted = "post.deleted"
+)
 
-	publisher := &EventPublisher{
-		connection:   conn,
-		channel:      ch,
-		exchangeName: exchangeName,
-		logger:       logger,
-		done:         make(chan error),
+// PublishOutboxEvent sends an event from the transactional outbox and waits
+// for the broker to confirm it. It never queues the event in memory: the
+// outbox row is the retry. The message ID is derived from the row, so a
+// resend after a crash can be recognised downstream.
+func (p *EventPublisher) PublishOutboxEvent(ctx context.Context, event *entities.OutboxEvent) error {
+	// Continue the trace of the request that wrote the event.
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(event.Headers))
+	ctx, span := p.startPublishSpan(ctx, event.RoutingKey)
+	defer span.End()
+
+	headers := amqp.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+
+	msg := amqp.Publishing{
+		Headers:      headers,
+		ContentType:  "application/json",
+		Body:         event.Payload,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    event.CreatedAt,
+		MessageId:    fmt.Sprintf("outbox-%d", event.ID),
 	}
 
-	// Monitor connection
-	go publisher.monitorConnection()
-
-	logger.Info("Event publisher initialized successfully")
-	return publisher, nil
-}
+	p.mu.Lock()
+	err := p.send(event.RoutingKey, msg)
+	p.mu.Unlock()
 
-func (p *EventPublisher) PublishPostCreated(event PostCreatedEvent) error {
-	return p.publishEvent("post.created", event)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
 }
 
-func (p *EventPublisher) PublishPostUpdated(event PostUpdatedEvent) error {
-	return p.publishEvent("post.updated", event)
+func (p *EventPublisher) startPublishSpan(ctx context.Context, routingKey string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, routingKey+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", p.exchangeName),
+			attribute.String("messaging.rabbitmq.destination.routing_key", routingKey),
+		),
+	)
 }
 
-func (p *EventPublisher) PublishPostDeleted(event PostDeletedEvent) error {
-	return p.publishEvent("post.deleted", event)
+// send publishes one event on the current channel. The caller holds p.mu.
+func (p *EventPublisher) send(routingKey string, msg amqp.Publishing) error {
+	err := p.sendOnChannel(routingKey, msg)
+	metrics.RecordEventPublish(routingKey, err)
+	return err
 }
 
-func (p *EventPublisher) publishEvent(routingKey string, event interface{}) error {
+func (p *EventPublisher) sendOnChannel(routingKey string, msg amqp.Publishing) error {
 	if p.channel == nil {
 		return fmt.Errorf("publisher channel is not available")
 	}
 
-	body, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
-	}
-
-	err = p.channel.Publish(
+	err := p.channel.Publish(
 		p.exchangeName, // exchange
 		routingKey,     // routing key
 		false,          // mandatory
 		false,          // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: amqp.Persistent, // Make message persistent
-			Timestamp:    time.Now(),
-			MessageId:    fmt.Sprintf("%s-%d", routingKey, time.Now().UnixNano()),
-		},
+		msg,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
+	p.deliveryTag++
+
+	if err := p.waitForConfirm(p.deliveryTag); err != nil {
+		return fmt.Errorf("failed to publish event %s: %w", routingKey, err)
+	}
+
+	p.logger.Info(fmt.Sprintf("Published event: %s with %d bytes", routingKey, len(msg.Body)))
+	return nil
+}
+
+// useChannel puts ch in confirm mode and publishes on it from now on. The
+// caller holds p.mu, or has not shared p yet.
+func (p *EventPublisher) useChannel(ch amqpChannel) error {
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
 
-	p.logger.Info(fmt.Sprintf("Published event: %s with %d bytes", routingKey, len(body)))
+	p.channel = ch
+	p.confirms = ch.NotifyPublish(make(chan amqp.Confirmation, confirmBuffer))
+	p.deliveryTag = 0 // tags are numbered per channel, from 1
 	return nil
 }
 
-func (p *EventPublisher) monitorConnection() {
+// waitForConfirm waits for the broker to ack the event sent with tag. A nack,
+// a closed channel or no answer within p.confirmTimeout is an error; the
+// event may or may not have been routed and the relay sends it again.
+func (p *EventPublisher) waitForConfirm(tag uint64) error {
+	timer := time.NewTimer(p.confirmTimeout)
+	defer timer.Stop()
+
 	for {
 		select {
-		case err := <-p.connection.NotifyClose(make(chan *amqp.Error)):
-			if err != nil {
-				p.logger.Error(fmt.Sprintf("RabbitMQ connection closed: %v", err))
-				p.done <- err
-				return
+		case confirm, ok := <-p.confirms:
+			if !ok {
+				return fmt.Errorf("channel closed before the broker confirmed the event")
+			}
+			if confirm.DeliveryTag < tag {
+				continue // a late answer for an event that already timed out
 			}
-		case err := <-p.channel.NotifyClose(make(chan *amqp.Error)):
-			if err != nil {
-				p.logger.Error(fmt.Sprintf("RabbitMQ channel closed: %v", err))
-				p.done <- err
-				return
+			if !confirm.Ack {
+				return fmt.Errorf("broker rejected the event")
 			}
+			return nil
+		case <-timer.C:
+			return fmt.Errorf("no confirmation from the broker within %s", p.confirmTimeout)
+		}
+	}
+}
+
+// monitorConnection logs an unexpected close of conn or ch and drops them, so
+// the next EnsureConnected dials again. A nil error means Close was called, so
+// it exits quietly.
+func (p *EventPublisher) monitorConnection(conn *amqp.Connection, ch amqpChannel) {
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chanClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	var err *amqp.Error
+	select {
+	case err = <-connClosed:
+		if err != nil {
+			p.logger.Error(fmt.Sprintf("RabbitMQ connection closed: %v", err))
+		}
+	case err = <-chanClosed:
+		if err != nil {
+			p.logger.Error(fmt.Sprintf("RabbitMQ channel closed: %v", err))
 		}
 	}
+	if err == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.connection == conn {
+		p.disconnect()
+	}
 }
 
 func (p *EventPublisher) IsConnected() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.connected()
+}
+
+// connected is IsConnected for callers that already hold p.mu.
+func (p *EventPublisher) connected() bool {
 	return p.connection != nil && !p.connection.IsClosed() && p.channel != nil
 }
 
-func (p *EventPublisher) Reconnect(rabbitMQURL string) error {
-	p.logger.Info("Attempting to reconnect to RabbitMQ...")
+// EnsureConnected dials RabbitMQ and declares the exchange unless the
+// publisher is already connected.
+func (p *EventPublisher) EnsureConnected() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	// Close existing connections
-	p.Close()
+	if p.connected() {
+		return nil
+	}
+	p.disconnect()
 
-	// Create new connection
-	conn, err := amqp.Dial(rabbitMQURL)
+	conn, err := amqp.Dial(p.url)
 	if err != nil {
-		return fmt.Errorf("failed to reconnect to RabbitMQ: %w", err)
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
 	}
 
 	ch, err := conn.Channel()
 	if err != nil {
 		conn.Close()
-		return fmt.Errorf("failed to open channel during reconnect: %w", err)
+		return fmt.Errorf("failed to open channel: %w", err)
 	}
 
-	// Redeclare exchange
+	// Declare exchange
 	err = ch.ExchangeDeclare(
 		p.exchangeName, // name
 		"topic",        // type
@@ -181,37 +289,48 @@ func (p *EventPublisher) Reconnect(rabbitMQURL string) error {
 	if err != nil {
 		ch.Close()
 		conn.Close()
-		return fmt.Errorf("failed to redeclare exchange during reconnect: %w", err)
+		return fmt.Errorf("failed to declare exchange: %w", err)
 	}
 
+	if err := p.useChannel(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return err
+	}
 	p.connection = conn
-	p.channel = ch
-	p.done = make(chan error)
 
-	// Restart monitoring
-	go p.monitorConnection()
+	// Monitor connection
+	go p.monitorConnection(conn, ch)
 
-	p.logger.Info("Successfully reconnected to RabbitMQ")
+	p.logger.Info("Connected to RabbitMQ")
 	return nil
 }
 
-func (p *EventPublisher) Close() error {
-	p.logger.Info("Closing event publisher...")
-
+// disconnect closes and forgets the connection and channel, if any. The
+// caller holds p.mu.
+func (p *EventPublisher) disconnect() {
 	if p.channel != nil {
-		if err := p.channel.Close(); err != nil {
+		if err := p.channel.Close(); err != nil && err != amqp.ErrClosed {
 			p.logger.Error(fmt.Sprintf("Failed to close channel: %v", err))
 		}
 		p.channel = nil
+		p.confirms = nil
 	}
 
 	if p.connection != nil {
-		if err := p.connection.Close(); err != nil {
+		if err := p.connection.Close(); err != nil && err != amqp.ErrClosed {
 			p.logger.Error(fmt.Sprintf("Failed to close connection: %v", err))
 		}
 		p.connection = nil
 	}
+}
+
+func (p *EventPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
+	p.logger.Info("Closing event publisher...")
+	p.disconnect()
 	p.logger.Info("Event publisher closed")
 	return nil
 }
@@ -222,3 +341,23 @@ func (p *EventPublisher) HealthCheck() error {
 	}
 	return nil
 }
+
+// headerCarrier adapts AMQP message headers to the OTel propagation API.
+type headerCarrier amqp.Table
+
+func (h headerCarrier) Get(key string) string {
+	value, _ := h[key].(string)
+	return value
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	return keys
+}
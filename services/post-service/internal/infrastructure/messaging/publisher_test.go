@@ -0,0 +1,128 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"post-service/internal/domain/entities"
+	"post-service/pkg/logger"
+)
+
+// fakeChannel accepts publishes until failAfter of them have gone through,
+// then fails every later one as a closed channel would. Accepted publishes are
+// answered on the confirm channel according to confirm.
+type fakeChannel struct {
+	failAfter int
+	confirm   string // "ack" (the default), "nack" or "none"
+	confirms  chan amqp.Confirmation
+	sent      []amqp.Publishing
+	attempts  int
+}
+
+func (c *fakeChannel) Confirm(noWait bool) error { return nil }
+
+func (c *fakeChannel) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	c.confirms = confirm
+	return confirm
+}
+
+func (c *fakeChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	c.attempts++
+	if c.failAfter >= 0 && len(c.sent) >= c.failAfter {
+		return amqp.ErrClosed
+	}
+	c.sent = append(c.sent, msg)
+
+	tag := uint64(len(c.sent))
+	switch c.confirm {
+	case "none":
+	case "nack":
+		c.confirms <- amqp.Confirmation{DeliveryTag: tag, Ack: false}
+	default:
+		c.confirms <- amqp.Confirmation{DeliveryTag: tag, Ack: true}
+	}
+	return nil
+}
+
+func (c *fakeChannel) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error { return receiver }
+func (c *fakeChannel) Close() error                                           { return nil }
+
+func newTestPublisher(ch *fakeChannel) *EventPublisher {
+	publisher := &EventPublisher{
+		exchangeName: "blog_events",
+		logger:       logger.New("info"),
+
+		confirmTimeout: 50 * time.Millisecond,
+	}
+	if ch != nil {
+		publisher.useChannel(ch)
+	}
+	return publisher
+}
+
+func outboxEvent(id int64) *entities.OutboxEvent {
+	return &entities.OutboxEvent{ID: id, RoutingKey: RoutingKeyPostCreated, Payload: []byte(`{}`), CreatedAt: time.Now()}
+}
+
+func TestPublishOutboxEvent_WaitsForBrokerAck(t *testing.T) {
+	ch := &fakeChannel{failAfter: -1}
+	publisher := newTestPublisher(ch)
+
+	if err := publisher.PublishOutboxEvent(context.Background(), outboxEvent(7)); err != nil {
+		t.Fatalf("expected an acked publish to succeed, got %v", err)
+	}
+	if len(ch.sent) != 1 || ch.sent[0].MessageId != "outbox-7" {
+		t.Fatalf("expected outbox-7 sent once, got %v", ch.sent)
+	}
+}
+
+func TestPublishOutboxEvent_FailsWithoutChannel(t *testing.T) {
+	publisher := newTestPublisher(nil)
+
+	if err := publisher.PublishOutboxEvent(context.Background(), outboxEvent(1)); err == nil {
+		t.Fatal("expected an error while disconnected")
+	}
+}
+
+func TestPublishOutboxEvent_ClosedChannelFails(t *testing.T) {
+	ch := &fakeChannel{failAfter: 0}
+	publisher := newTestPublisher(ch)
+
+	if err := publisher.PublishOutboxEvent(context.Background(), outboxEvent(1)); err == nil {
+		t.Fatal("expected an error from a closed channel")
+	}
+}
+
+func TestPublishOutboxEvent_NackFails(t *testing.T) {
+	ch := &fakeChannel{failAfter: -1, confirm: "nack"}
+	publisher := newTestPublisher(ch)
+
+	if err := publisher.PublishOutboxEvent(context.Background(), outboxEvent(1)); err == nil {
+		t.Fatal("expected a nacked event to fail so its row stays unsent")
+	}
+
+	ch.confirm = "ack"
+	if err := publisher.PublishOutboxEvent(context.Background(), outboxEvent(1)); err != nil {
+		t.Fatalf("expected the resend to succeed once acked, got %v", err)
+	}
+}
+
+func TestPublishOutboxEvent_UnconfirmedEventTimesOut(t *testing.T) {
+	ch := &fakeChannel{failAfter: -1, confirm: "none"}
+	publisher := newTestPublisher(ch)
+
+	if err := publisher.PublishOutboxEvent(context.Background(), outboxEvent(1)); err == nil {
+		t.Fatal("expected an unconfirmed event to time out")
+	}
+
+	// The broker answers for the first send only after it timed out. The resend
+	// must wait for its own ack rather than take the stale one.
+	ch.confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+	ch.confirm = "nack"
+	if err := publisher.PublishOutboxEvent(context.Background(), outboxEvent(1)); err == nil {
+		t.Fatal("expected the resend's own nack, not the stale ack")
+	}
+}
@@ -17,9 +17,54 @@ func RunMigrations(db *sql.DB) error {
 		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
 
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+	UPDATE posts SET published_at = created_at WHERE published = true AND published_at IS NULL;
+
+	-- status (draft, published, archived) replaces the published flag. The
+	-- flag is still written, as status = 'published', for older readers.
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS status VARCHAR(20);
+	UPDATE posts SET status = CASE WHEN published THEN 'published' ELSE 'draft' END WHERE status IS NULL;
+	ALTER TABLE posts ALTER COLUMN status SET DEFAULT 'draft';
+	ALTER TABLE posts ALTER COLUMN status SET NOT NULL;
+
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS cover_image_url VARCHAR(2048);
+
+	-- Author-written teaser. NULL means the excerpt is generated from content.
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS excerpt VARCHAR(300);
+
+	-- Draft preview links. Only a SHA-256 of the token is stored; a NULL hash
+	-- means the post has no active preview link.
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS preview_token_hash VARCHAR(64);
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS preview_token_expires_at TIMESTAMP;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_preview_token_hash ON posts(preview_token_hash) WHERE preview_token_hash IS NOT NULL;
+
+	-- One row per reader who liked a post; liking again is a no-op.
+	CREATE TABLE IF NOT EXISTS post_likes (
+		post_id VARCHAR(255) NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+		user_id VARCHAR(255) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (post_id, user_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_post_likes_user_id ON post_likes(user_id);
+
+	-- Transactional outbox: events are written with the change they describe
+	-- and published by the relay. sent_at is set once the broker confirmed.
+	CREATE TABLE IF NOT EXISTS outbox (
+		id BIGSERIAL PRIMARY KEY,
+		routing_key VARCHAR(100) NOT NULL,
+		payload JSONB NOT NULL,
+		headers JSONB NOT NULL DEFAULT '{}',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		sent_at TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_outbox_unsent ON outbox(id) WHERE sent_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_outbox_sent_at ON outbox(sent_at) WHERE sent_at IS NOT NULL;
+
 	CREATE INDEX IF NOT EXISTS idx_posts_user_id ON posts(user_id);
 	CREATE INDEX IF NOT EXISTS idx_posts_slug ON posts(slug);
 	CREATE INDEX IF NOT EXISTS idx_posts_published ON posts(published);
+	CREATE INDEX IF NOT EXISTS idx_posts_status ON posts(status);
+	CREATE INDEX IF NOT EXISTS idx_posts_user_id_status ON posts(user_id, status);
 	CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts(created_at DESC);
 	CREATE INDEX IF NOT EXISTS idx_posts_search ON posts USING gin(to_tsvector('english', title || ' ' || content));
 	
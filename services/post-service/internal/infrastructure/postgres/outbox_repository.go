@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"post-service/internal/domain/entities"
+)
+
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// insertOutboxEvent adds event through q, which is the post's transaction
+// when the event has to commit with it. It sets event.ID and CreatedAt.
+func insertOutboxEvent(ctx context.Context, q dbtx, event *entities.OutboxEvent) error {
+	headers := []byte("{}")
+	if len(event.Headers) > 0 {
+		var err error
+		if headers, err = json.Marshal(event.Headers); err != nil {
+			return fmt.Errorf("failed to marshal outbox headers: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO outbox (routing_key, payload, headers, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	event.CreatedAt = time.Now()
+	err := q.QueryRowContext(ctx, query, event.RoutingKey, event.Payload, headers, event.CreatedAt).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) ListUnsent(ctx context.Context, limit int) ([]*entities.OutboxEvent, error) {
+	query := `
+		SELECT id, routing_key, payload, headers, created_at
+		FROM outbox
+		WHERE sent_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unsent outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entities.OutboxEvent
+	for rows.Next() {
+		event := &entities.OutboxEvent{}
+		var headers []byte
+		if err := rows.Scan(&event.ID, &event.RoutingKey, &event.Payload, &headers, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		if err := json.Unmarshal(headers, &event.Headers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal outbox headers of event %d: %w", event.ID, err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	query := `UPDATE outbox SET sent_at = $2 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark outbox event %d sent: %w", id, err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM outbox WHERE sent_at IS NOT NULL AND sent_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sent outbox events: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted outbox events: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -5,26 +5,66 @@ import (
 	"database/sql"
 	"fmt"
 	"post-service/internal/domain/entities"
+	"post-service/internal/domain/repositories"
 	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+// dbtx is what the repositories query through: the pool or one transaction.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 type PostRepository struct {
-	db *sql.DB
+	db   dbtx
+	pool *sql.DB // nil when the repository is bound to a transaction
 }
 
 func NewPostRepository(db *sql.DB) *PostRepository {
-	return &PostRepository{db: db}
+	return &PostRepository{db: db, pool: db}
+}
+
+func (r *PostRepository) InTx(ctx context.Context, fn func(tx repositories.PostRepository) error) error {
+	if r.pool == nil {
+		return fn(r)
+	}
+
+	tx, err := r.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(&PostRepository{db: tx}); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *PostRepository) AddOutboxEvent(ctx context.Context, event *entities.OutboxEvent) error {
+	return insertOutboxEvent(ctx, r.db, event)
 }
 
 func (r *PostRepository) Create(ctx context.Context, post *entities.Post) error {
 	query := `
-		INSERT INTO posts (id, user_id, title, content, slug, published, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO posts (id, user_id, title, content, slug, status, published, cover_image_url, excerpt, published_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10, $11, $12)
 	`
 
 	now := time.Now()
-	_, err := r.db.ExecContext(ctx, query, post.ID, post.UserID, post.Title, post.Content, post.Slug, post.Published, now, now)
+	if post.IsPublished() && post.PublishedAt == nil {
+		post.PublishedAt = &now
+	}
+	_, err := r.db.ExecContext(ctx, query, post.ID, post.UserID, post.Title, post.Content, post.Slug,
+		post.Status, post.IsPublished(), post.CoverImageURL, post.Excerpt, post.PublishedAt, now, now)
 
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
@@ -44,7 +84,7 @@ func (r *PostRepository) Create(ctx context.Context, post *entities.Post) error
 
 func (r *PostRepository) GetByID(ctx context.Context, id string) (*entities.Post, error) {
 	query := `
-		SELECT id, user_id, title, content, slug, published, created_at, updated_at
+		SELECT id, user_id, title, content, slug, status, COALESCE(cover_image_url, ''), COALESCE(excerpt, ''), published_at, created_at, updated_at
 		FROM posts 
 		WHERE id = $1
 	`
@@ -52,7 +92,7 @@ func (r *PostRepository) GetByID(ctx context.Context, id string) (*entities.Post
 	post := &entities.Post{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&post.ID, &post.UserID, &post.Title, &post.Content, &post.Slug,
-		&post.Published, &post.CreatedAt, &post.UpdatedAt,
+		&post.Status, &post.CoverImageURL, &post.Excerpt, &post.PublishedAt, &post.CreatedAt, &post.UpdatedAt,
 	)
 
 	if err != nil {
@@ -67,15 +107,40 @@ func (r *PostRepository) GetByID(ctx context.Context, id string) (*entities.Post
 
 func (r *PostRepository) GetBySlug(ctx context.Context, slug string) (*entities.Post, error) {
 	query := `
-		SELECT id, user_id, title, content, slug, published, created_at, updated_at
+		SELECT id, user_id, title, content, slug, status, COALESCE(cover_image_url, ''), COALESCE(excerpt, ''), published_at, created_at, updated_at
 		FROM posts 
-		WHERE slug = $1 AND published = true
+		WHERE slug = $1 AND status = 'published'
 	`
 
 	post := &entities.Post{}
 	err := r.db.QueryRowContext(ctx, query, slug).Scan(
 		&post.ID, &post.UserID, &post.Title, &post.Content, &post.Slug,
-		&post.Published, &post.CreatedAt, &post.UpdatedAt,
+		&post.Status, &post.CoverImageURL, &post.Excerpt, &post.PublishedAt, &post.CreatedAt, &post.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("post not found")
+		}
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	return post, nil
+}
+
+// GetBySlugForUser returns the post with slug if it is published or owned by
+// userID, so authors can preview their own drafts.
+func (r *PostRepository) GetBySlugForUser(ctx context.Context, slug, userID string) (*entities.Post, error) {
+	query := `
+		SELECT id, user_id, title, content, slug, status, COALESCE(cover_image_url, ''), COALESCE(excerpt, ''), published_at, created_at, updated_at
+		FROM posts
+		WHERE slug = $1 AND (status = 'published' OR user_id = $2)
+	`
+
+	post := &entities.Post{}
+	err := r.db.QueryRowContext(ctx, query, slug, userID).Scan(
+		&post.ID, &post.UserID, &post.Title, &post.Content, &post.Slug,
+		&post.Status, &post.CoverImageURL, &post.Excerpt, &post.PublishedAt, &post.CreatedAt, &post.UpdatedAt,
 	)
 
 	if err != nil {
@@ -88,16 +153,66 @@ func (r *PostRepository) GetBySlug(ctx context.Context, slug string) (*entities.
 	return post, nil
 }
 
-func (r *PostRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*entities.Post, error) {
+func (r *PostRepository) GetByPreviewToken(ctx context.Context, tokenHash string) (*entities.Post, error) {
+	query := `
+		SELECT id, user_id, title, content, slug, status, COALESCE(cover_image_url, ''), COALESCE(excerpt, ''), published_at, created_at, updated_at
+		FROM posts
+		WHERE preview_token_hash = $1
+		  AND (preview_token_expires_at IS NULL OR preview_token_expires_at > CURRENT_TIMESTAMP)
+	`
+
+	post := &entities.Post{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&post.ID, &post.UserID, &post.Title, &post.Content, &post.Slug,
+		&post.Status, &post.CoverImageURL, &post.Excerpt, &post.PublishedAt, &post.CreatedAt, &post.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("post not found")
+		}
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	return post, nil
+}
+
+func (r *PostRepository) SetPreviewToken(ctx context.Context, id, tokenHash string, expiresAt *time.Time) error {
+	query := `
+		UPDATE posts
+		SET preview_token_hash = NULLIF($2, ''), preview_token_expires_at = $3
+		WHERE id = $1
+	`
+
+	if tokenHash == "" {
+		expiresAt = nil
+	}
+	result, err := r.db.ExecContext(ctx, query, id, tokenHash, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to set preview token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("post not found")
+	}
+
+	return nil
+}
+
+func (r *PostRepository) GetByUserID(ctx context.Context, userID string, status entities.PostStatus, limit, offset int) ([]*entities.Post, error) {
 	query := `
-		SELECT id, user_id, title, content, slug, published, created_at, updated_at
+		SELECT id, user_id, title, content, slug, status, COALESCE(cover_image_url, ''), COALESCE(excerpt, ''), published_at, created_at, updated_at
 		FROM posts 
-		WHERE user_id = $1 AND published = true
+		WHERE user_id = $1 AND status = $2
 		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, userID, status, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user posts: %w", err)
 	}
@@ -109,12 +224,13 @@ func (r *PostRepository) GetByUserID(ctx context.Context, userID string, limit,
 func (r *PostRepository) Update(ctx context.Context, post *entities.Post) error {
 	query := `
 		UPDATE posts 
-		SET title = $2, content = $3, slug = $4, published = $5, updated_at = $6
+		SET title = $2, content = $3, slug = $4, status = $5, published = $6, cover_image_url = NULLIF($7, ''),
+			excerpt = NULLIF($8, ''), published_at = $9, updated_at = $10
 		WHERE id = $1
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
-		post.ID, post.Title, post.Content, post.Slug, post.Published, time.Now())
+		post.ID, post.Title, post.Content, post.Slug, post.Status, post.IsPublished(), post.CoverImageURL, post.Excerpt, post.PublishedAt, time.Now())
 
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), "slug") {
@@ -155,18 +271,44 @@ func (r *PostRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *PostRepository) List(ctx context.Context, limit, offset int, publishedOnly bool) ([]*entities.Post, error) {
+func (r *PostRepository) DeleteMany(ctx context.Context, ids []string, userID string) ([]*entities.Post, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
 	query := `
-		SELECT id, user_id, title, content, slug, published, created_at, updated_at
+		DELETE FROM posts
+		WHERE id = ANY($1) AND user_id = $2
+		RETURNING id, user_id, title, content, slug, status, COALESCE(cover_image_url, ''), COALESCE(excerpt, ''), published_at, created_at, updated_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete posts: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanPosts(rows)
+}
+
+func (r *PostRepository) List(ctx context.Context, limit, offset int, publishedOnly bool, createdIn repositories.DateRange) ([]*entities.Post, error) {
+	query := `
+		SELECT id, user_id, title, content, slug, status, COALESCE(cover_image_url, ''), COALESCE(excerpt, ''), published_at, created_at, updated_at
 		FROM posts 
 	`
-	args := []interface{}{limit, offset}
+	var conditions []string
+	var args []interface{}
 
 	if publishedOnly {
-		query += "WHERE published = true "
+		conditions = append(conditions, "status = 'published'")
+	}
+	conditions, args = appendCreatedIn(conditions, args, createdIn)
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + " "
 	}
 
-	query += "ORDER BY created_at DESC LIMIT $1 OFFSET $2"
+	args = append(args, limit, offset)
+	query += fmt.Sprintf("ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -177,25 +319,31 @@ func (r *PostRepository) List(ctx context.Context, limit, offset int, publishedO
 	return r.scanPosts(rows)
 }
 
-func (r *PostRepository) Search(ctx context.Context, query string, limit, offset int, publishedOnly bool) ([]*entities.Post, error) {
+func (r *PostRepository) Search(ctx context.Context, query string, limit, offset int, publishedOnly bool, createdIn repositories.DateRange) ([]*entities.Post, error) {
 	searchQuery := `
-		SELECT id, user_id, title, content, slug, published, created_at, updated_at
+		SELECT id, user_id, title, content, slug, status, COALESCE(cover_image_url, ''), COALESCE(excerpt, ''), published_at, created_at, updated_at
 		FROM posts 
 		WHERE to_tsvector('english', COALESCE(title, '') || ' ' || COALESCE(content, '')) @@ plainto_tsquery('english', $1)
 	`
-	args := []interface{}{query, limit, offset}
+	conditions := []string{}
+	args := []interface{}{query}
 
 	if publishedOnly {
-		searchQuery += " AND published = true"
+		conditions = append(conditions, "status = 'published'")
+	}
+	conditions, args = appendCreatedIn(conditions, args, createdIn)
+	for _, condition := range conditions {
+		searchQuery += " AND " + condition
 	}
 
-	searchQuery += `
+	args = append(args, limit, offset)
+	searchQuery += fmt.Sprintf(`
 		ORDER BY ts_rank(
 			to_tsvector('english', COALESCE(title, '') || ' ' || COALESCE(content, '')),
 			plainto_tsquery('english', $1)
 		) DESC, created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
 
 	rows, err := r.db.QueryContext(ctx, searchQuery, args...)
 	if err != nil {
@@ -206,6 +354,23 @@ func (r *PostRepository) Search(ctx context.Context, query string, limit, offset
 	return r.scanPosts(rows)
 }
 
+// appendCreatedIn adds the created_at bounds of createdIn to a WHERE clause,
+// numbering its placeholders after the existing args.
+func appendCreatedIn(conditions []string, args []interface{}, createdIn repositories.DateRange) ([]string, []interface{}) {
+	switch {
+	case createdIn.From != nil && createdIn.To != nil:
+		args = append(args, createdIn.From.UTC(), createdIn.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at BETWEEN $%d AND $%d", len(args)-1, len(args)))
+	case createdIn.From != nil:
+		args = append(args, createdIn.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	case createdIn.To != nil:
+		args = append(args, createdIn.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+	return conditions, args
+}
+
 func (r *PostRepository) Exists(ctx context.Context, id string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`
 
@@ -231,7 +396,7 @@ func (r *PostRepository) ExistsBySlug(ctx context.Context, slug string) (bool, e
 }
 
 func (r *PostRepository) GetPublishedCount(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM posts WHERE published = true`
+	query := `SELECT COUNT(*) FROM posts WHERE status = 'published'`
 
 	var count int64
 	err := r.db.QueryRowContext(ctx, query).Scan(&count)
@@ -254,6 +419,66 @@ func (r *PostRepository) GetUserPostsCount(ctx context.Context, userID string) (
 	return count, nil
 }
 
+func (r *PostRepository) GetUserDraftCount(ctx context.Context, userID string) (int64, error) {
+	query := `SELECT COUNT(*) FROM posts WHERE user_id = $1 AND status = 'draft'`
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user draft count: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *PostRepository) Like(ctx context.Context, postID, userID string) error {
+	query := `
+		INSERT INTO post_likes (post_id, user_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (post_id, user_id) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, postID, userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to like post: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostRepository) Unlike(ctx context.Context, postID, userID string) error {
+	query := `DELETE FROM post_likes WHERE post_id = $1 AND user_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, postID, userID); err != nil {
+		return fmt.Errorf("failed to unlike post: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostRepository) CountLikes(ctx context.Context, postID string) (int64, error) {
+	query := `SELECT COUNT(*) FROM post_likes WHERE post_id = $1`
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, postID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count post likes: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *PostRepository) HasLiked(ctx context.Context, postID, userID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM post_likes WHERE post_id = $1 AND user_id = $2)`
+
+	var liked bool
+	err := r.db.QueryRowContext(ctx, query, postID, userID).Scan(&liked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check post like: %w", err)
+	}
+
+	return liked, nil
+}
+
 func (r *PostRepository) scanPosts(rows *sql.Rows) ([]*entities.Post, error) {
 	var posts []*entities.Post
 
@@ -261,7 +486,7 @@ func (r *PostRepository) scanPosts(rows *sql.Rows) ([]*entities.Post, error) {
 		post := &entities.Post{}
 		err := rows.Scan(
 			&post.ID, &post.UserID, &post.Title, &post.Content, &post.Slug,
-			&post.Published, &post.CreatedAt, &post.UpdatedAt,
+			&post.Status, &post.CoverImageURL, &post.Excerpt, &post.PublishedAt, &post.CreatedAt, &post.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan post: %w", err)
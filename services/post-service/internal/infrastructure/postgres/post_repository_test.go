@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"post-service/internal/domain/repositories"
+)
+
+func TestAppendCreatedIn(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		createdIn repositories.DateRange
+		wantCond  []string
+		wantArgs  []interface{}
+	}{
+		{"open", repositories.DateRange{}, []string{"published = true"}, []interface{}{"q"}},
+		{"both", repositories.DateRange{From: &from, To: &to}, []string{"published = true", "created_at BETWEEN $2 AND $3"}, []interface{}{"q", from, to}},
+		{"from only", repositories.DateRange{From: &from}, []string{"published = true", "created_at >= $2"}, []interface{}{"q", from}},
+		{"to only", repositories.DateRange{To: &to}, []string{"published = true", "created_at <= $2"}, []interface{}{"q", to}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions, args := appendCreatedIn([]string{"published = true"}, []interface{}{"q"}, tc.createdIn)
+			if !reflect.DeepEqual(conditions, tc.wantCond) {
+				t.Fatalf("conditions: expected %v, got %v", tc.wantCond, conditions)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Fatalf("args: expected %v, got %v", tc.wantArgs, args)
+			}
+		})
+	}
+}
@@ -77,7 +77,7 @@ func fromPost(p *entities.Post) postPayload {
 		Title:     p.Title,
 		Slug:      p.Slug,
 		Content:   p.Content,
-		Published: p.Published,
+		Published: p.IsPublished(),
 	}
 }
 
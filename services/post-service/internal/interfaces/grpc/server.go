@@ -3,40 +3,47 @@ package grpc
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"post-service/internal/application/dto"
 	appErrors "post-service/internal/application/errors"
 	"post-service/internal/application/services"
+	"post-service/pkg/health"
 	"post-service/pkg/logger"
 
 	postv1 "github.com/nikitashilov/microblog_grpc/proto/post/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type PostServer struct {
 	postv1.UnimplementedPostServiceServer
 	service *services.PostService
+	health  *health.Checker
 	logger  *logger.Logger
 }
 
-func NewPostServer(service *services.PostService, logger *logger.Logger) *PostServer {
-	return &PostServer{service: service, logger: logger}
+func NewPostServer(service *services.PostService, checker *health.Checker, logger *logger.Logger) *PostServer {
+	return &PostServer{service: service, health: checker, logger: logger}
 }
 
 func (s *PostServer) CreatePost(ctx context.Context, req *postv1.CreatePostRequest) (*postv1.Post, error) {
 	if req.GetUserId() == "" {
-		return nil, status.Error(codes.Unauthenticated, appErrors.ErrUnauthorizedAccess.Message)
+		return nil, appStatus(codes.Unauthenticated, appErrors.ErrUnauthorizedAccess)
 	}
 
 	dtoReq := &dto.CreatePostRequest{
-		Title:     req.GetTitle(),
-		Content:   req.GetContent(),
-		Slug:      req.GetSlug(),
-		Published: req.GetPublished(),
+		Title:         req.GetTitle(),
+		Content:       req.GetContent(),
+		Slug:          req.GetSlug(),
+		Published:     req.GetPublished(),
+		CoverImageURL: req.GetCoverImageUrl(),
+		Excerpt:       req.GetExcerpt(),
 	}
 
 	resp, err := s.service.CreatePost(ctx, dtoReq, req.GetUserId())
@@ -49,7 +56,7 @@ func (s *PostServer) CreatePost(ctx context.Context, req *postv1.CreatePostReque
 
 func (s *PostServer) GetPost(ctx context.Context, req *postv1.GetPostRequest) (*postv1.Post, error) {
 	if req.GetId() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 
 	resp, err := s.service.GetPost(ctx, req.GetId(), req.GetRequestingUserId())
@@ -57,25 +64,33 @@ func (s *PostServer) GetPost(ctx context.Context, req *postv1.GetPostRequest) (*
 		return nil, s.toGRPCError(err)
 	}
 
+	if req.GetRenderHtml() {
+		s.service.RenderContentHTML(resp)
+	}
+
 	return toProtoPost(resp), nil
 }
 
 func (s *PostServer) GetPostBySlug(ctx context.Context, req *postv1.GetPostBySlugRequest) (*postv1.Post, error) {
 	if req.GetSlug() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 
-	resp, err := s.service.GetPostBySlug(ctx, req.GetSlug())
+	resp, err := s.service.GetPostBySlug(ctx, req.GetSlug(), req.GetRequestingUserId())
 	if err != nil {
 		return nil, s.toGRPCError(err)
 	}
 
+	if req.GetRenderHtml() {
+		s.service.RenderContentHTML(resp)
+	}
+
 	return toProtoPost(resp), nil
 }
 
 func (s *PostServer) UpdatePost(ctx context.Context, req *postv1.UpdatePostRequest) (*postv1.Post, error) {
 	if req.GetId() == "" || req.GetUserId() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 
 	// Verify ownership - users can only update their own posts
@@ -86,7 +101,7 @@ func (s *PostServer) UpdatePost(ctx context.Context, req *postv1.UpdatePostReque
 	}
 
 	if ownerID != req.GetUserId() {
-		return nil, status.Error(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess.Message)
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
 	}
 
 	dtoReq := &dto.UpdatePostRequest{}
@@ -103,6 +118,14 @@ func (s *PostServer) UpdatePost(ctx context.Context, req *postv1.UpdatePostReque
 		value := req.GetSlug().GetValue()
 		dtoReq.Slug = &value
 	}
+	if req.GetCoverImageUrl() != nil {
+		value := req.GetCoverImageUrl().GetValue()
+		dtoReq.CoverImageURL = &value
+	}
+	if req.GetExcerpt() != nil {
+		value := req.GetExcerpt().GetValue()
+		dtoReq.Excerpt = &value
+	}
 	if req.GetPublished() != nil {
 		value := req.GetPublished().GetValue()
 		dtoReq.Published = &value
@@ -118,7 +141,7 @@ func (s *PostServer) UpdatePost(ctx context.Context, req *postv1.UpdatePostReque
 
 func (s *PostServer) DeletePost(ctx context.Context, req *postv1.DeletePostRequest) (*emptypb.Empty, error) {
 	if req.GetId() == "" || req.GetUserId() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 
 	// Verify ownership - users can only delete their own posts
@@ -129,7 +152,7 @@ func (s *PostServer) DeletePost(ctx context.Context, req *postv1.DeletePostReque
 	}
 
 	if ownerID != req.GetUserId() {
-		return nil, status.Error(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess.Message)
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
 	}
 
 	if err := s.service.DeletePost(ctx, req.GetId(), req.GetUserId()); err != nil {
@@ -139,14 +162,38 @@ func (s *PostServer) DeletePost(ctx context.Context, req *postv1.DeletePostReque
 	return &emptypb.Empty{}, nil
 }
 
+func (s *PostServer) DeletePosts(ctx context.Context, req *postv1.DeletePostsRequest) (*postv1.DeletePostsResponse, error) {
+	if len(req.GetIds()) == 0 || req.GetUserId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+
+	resp, err := s.service.DeleteMany(ctx, req.GetIds(), req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	results := make([]*postv1.DeletePostResult, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		results = append(results, &postv1.DeletePostResult{Id: result.ID, Deleted: result.Deleted})
+	}
+	return &postv1.DeletePostsResponse{Results: results, Deleted: int32(resp.Deleted)}, nil
+}
+
 func (s *PostServer) ListPosts(ctx context.Context, req *postv1.ListPostsRequest) (*postv1.ListPostsResponse, error) {
 	limit := normalizeLimit(int(req.GetLimit()))
 	offset := normalizeOffset(int(req.GetOffset()))
 
+	from, to, err := dateRangeFromProto(req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, err
+	}
+
 	dtoReq := &dto.ListPostsRequest{
 		Limit:         limit,
 		Offset:        offset,
 		PublishedOnly: req.GetPublishedOnly(),
+		From:          from,
+		To:            to,
 	}
 
 	resp, err := s.service.ListPosts(ctx, dtoReq)
@@ -159,7 +206,7 @@ func (s *PostServer) ListPosts(ctx context.Context, req *postv1.ListPostsRequest
 
 func (s *PostServer) GetUserPosts(ctx context.Context, req *postv1.GetUserPostsRequest) (*postv1.ListPostsResponse, error) {
 	if req.GetUserId() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 
 	limit := normalizeLimit(int(req.GetLimit()))
@@ -168,9 +215,10 @@ func (s *PostServer) GetUserPosts(ctx context.Context, req *postv1.GetUserPostsR
 	dtoReq := &dto.UserPostsRequest{
 		Limit:  limit,
 		Offset: offset,
+		Status: req.GetStatus(),
 	}
 
-	resp, err := s.service.GetUserPosts(ctx, req.GetUserId(), dtoReq)
+	resp, err := s.service.GetUserPosts(ctx, req.GetUserId(), req.GetRequestingUserId(), dtoReq)
 	if err != nil {
 		return nil, s.toGRPCError(err)
 	}
@@ -180,17 +228,24 @@ func (s *PostServer) GetUserPosts(ctx context.Context, req *postv1.GetUserPostsR
 
 func (s *PostServer) SearchPosts(ctx context.Context, req *postv1.SearchPostsRequest) (*postv1.ListPostsResponse, error) {
 	if req.GetQuery() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 
 	limit := normalizeLimit(int(req.GetLimit()))
 	offset := normalizeOffset(int(req.GetOffset()))
 
+	from, to, err := dateRangeFromProto(req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, err
+	}
+
 	dtoReq := &dto.SearchPostsRequest{
 		Query:         req.GetQuery(),
 		Limit:         limit,
 		Offset:        offset,
 		PublishedOnly: req.GetPublishedOnly(),
+		From:          from,
+		To:            to,
 	}
 
 	resp, err := s.service.SearchPosts(ctx, dtoReq)
@@ -210,10 +265,123 @@ func (s *PostServer) GetStats(ctx context.Context, req *postv1.GetStatsRequest)
 	return &postv1.PostStatsResponse{
 		TotalPublishedPosts: resp.TotalPublishedPosts,
 		UserPostsCount:      resp.UserPostsCount,
+		UserDraftCount:      resp.UserDraftCount,
+		UserScheduledCount:  resp.UserScheduledCount,
 	}, nil
 }
 
-func (s *PostServer) HealthCheck(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+// HealthCheck fails with Unavailable when a backing dependency is unreachable,
+// so callers see more than whether the process is up.
+func (s *PostServer) ArchivePost(ctx context.Context, req *postv1.ArchivePostRequest) (*postv1.Post, error) {
+	if req.GetId() == "" || req.GetUserId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+
+	resp, err := s.service.ArchivePost(ctx, req.GetId(), req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return toProtoPost(resp), nil
+}
+
+func (s *PostServer) LikePost(ctx context.Context, req *postv1.LikePostRequest) (*postv1.PostLikeState, error) {
+	if req.GetId() == "" || req.GetUserId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+
+	resp, err := s.service.LikePost(ctx, req.GetId(), req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return toProtoLikeState(resp), nil
+}
+
+func (s *PostServer) UnlikePost(ctx context.Context, req *postv1.LikePostRequest) (*postv1.PostLikeState, error) {
+	if req.GetId() == "" || req.GetUserId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+
+	resp, err := s.service.UnlikePost(ctx, req.GetId(), req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return toProtoLikeState(resp), nil
+}
+
+func (s *PostServer) ClonePost(ctx context.Context, req *postv1.ClonePostRequest) (*postv1.Post, error) {
+	if req.GetId() == "" || req.GetUserId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+
+	resp, err := s.service.ClonePost(ctx, req.GetId(), req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return toProtoPost(resp), nil
+}
+
+// maxPreviewTokenHours caps preview link lifetimes, matching the HTTP API.
+const maxPreviewTokenHours = 720
+
+func (s *PostServer) CreatePreviewToken(ctx context.Context, req *postv1.CreatePreviewTokenRequest) (*postv1.PreviewToken, error) {
+	if req.GetId() == "" || req.GetUserId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+	if req.GetExpiresInHours() < 0 || req.GetExpiresInHours() > maxPreviewTokenHours {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+
+	ttl := time.Duration(req.GetExpiresInHours()) * time.Hour
+	resp, err := s.service.GeneratePreviewToken(ctx, req.GetId(), req.GetUserId(), ttl)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	token := &postv1.PreviewToken{PostId: resp.PostID, Token: resp.Token}
+	if resp.ExpiresAt != nil {
+		token.ExpiresAt = timestamppb.New(*resp.ExpiresAt)
+	}
+	return token, nil
+}
+
+func (s *PostServer) RevokePreviewToken(ctx context.Context, req *postv1.RevokePreviewTokenRequest) (*emptypb.Empty, error) {
+	if req.GetId() == "" || req.GetUserId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+
+	if err := s.service.RevokePreviewToken(ctx, req.GetId(), req.GetUserId()); err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *PostServer) GetPostByPreviewToken(ctx context.Context, req *postv1.GetPostByPreviewTokenRequest) (*postv1.Post, error) {
+	if req.GetToken() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+
+	resp, err := s.service.GetPostByPreviewToken(ctx, req.GetToken())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	if req.GetRenderHtml() {
+		s.service.RenderContentHTML(resp)
+	}
+
+	return toProtoPost(resp), nil
+}
+
+func (s *PostServer) HealthCheck(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if _, err := s.health.Run(ctx); err != nil {
+		s.logger.Warn("Health check failed: " + err.Error())
+		return nil, status.Error(codes.Unavailable, "dependency check failed")
+	}
 	return &emptypb.Empty{}, nil
 }
 
@@ -223,24 +391,26 @@ func (s *PostServer) toGRPCError(err error) error {
 	}
 
 	if postErr, ok := err.(*appErrors.PostError); ok {
+		code := codes.Internal
 		switch postErr.StatusCode {
 		case http.StatusBadRequest:
-			return status.Error(codes.InvalidArgument, postErr.Message)
+			code = codes.InvalidArgument
 		case http.StatusUnauthorized:
-			return status.Error(codes.Unauthenticated, postErr.Message)
+			code = codes.Unauthenticated
 		case http.StatusForbidden:
-			return status.Error(codes.PermissionDenied, postErr.Message)
+			code = codes.PermissionDenied
 		case http.StatusNotFound:
-			return status.Error(codes.NotFound, postErr.Message)
+			code = codes.NotFound
 		case http.StatusConflict:
-			return status.Error(codes.AlreadyExists, postErr.Message)
+			code = codes.AlreadyExists
+		case http.StatusLocked:
+			code = codes.FailedPrecondition
 		case http.StatusTooManyRequests:
-			return status.Error(codes.ResourceExhausted, postErr.Message)
+			code = codes.ResourceExhausted
 		case http.StatusServiceUnavailable:
-			return status.Error(codes.Unavailable, postErr.Message)
-		default:
-			return status.Error(codes.Internal, postErr.Message)
+			code = codes.Unavailable
 		}
+		return appStatus(code, postErr)
 	}
 
 	s.logger.Error("unexpected error: " + err.Error())
@@ -252,15 +422,31 @@ func toProtoPost(post *dto.PostResponse) *postv1.Post {
 		return nil
 	}
 
-	return &postv1.Post{
-		Id:        post.ID,
-		UserId:    post.UserID,
-		Title:     post.Title,
-		Content:   post.Content,
-		Slug:      post.Slug,
-		Published: post.Published,
-		CreatedAt: toTimestamp(post.CreatedAt),
-		UpdatedAt: toTimestamp(post.UpdatedAt),
+	protoPost := &postv1.Post{
+		Id:            post.ID,
+		UserId:        post.UserID,
+		Title:         post.Title,
+		Content:       post.Content,
+		Slug:          post.Slug,
+		Published:     post.Published,
+		Status:        post.Status,
+		ContentHtml:   post.ContentHTML,
+		CoverImageUrl: post.CoverImageURL,
+		LikeCount:     post.LikeCount,
+		CreatedAt:     toTimestamp(post.CreatedAt),
+		UpdatedAt:     toTimestamp(post.UpdatedAt),
+	}
+	if post.LikedByMe != nil {
+		protoPost.LikedByMe = wrapperspb.Bool(*post.LikedByMe)
+	}
+	return protoPost
+}
+
+func toProtoLikeState(state *dto.PostLikeResponse) *postv1.PostLikeState {
+	return &postv1.PostLikeState{
+		PostId:    state.PostID,
+		LikeCount: state.LikeCount,
+		LikedByMe: state.LikedByMe,
 	}
 }
 
@@ -270,13 +456,16 @@ func toProtoSummary(post *dto.PostSummaryResponse) *postv1.PostSummary {
 	}
 
 	return &postv1.PostSummary{
-		Id:        post.ID,
-		UserId:    post.UserID,
-		Title:     post.Title,
-		Slug:      post.Slug,
-		Published: post.Published,
-		CreatedAt: toTimestamp(post.CreatedAt),
-		UpdatedAt: toTimestamp(post.UpdatedAt),
+		Id:            post.ID,
+		UserId:        post.UserID,
+		Title:         post.Title,
+		Slug:          post.Slug,
+		Published:     post.Published,
+		Status:        post.Status,
+		CoverImageUrl: post.CoverImageURL,
+		Excerpt:       post.Excerpt,
+		CreatedAt:     toTimestamp(post.CreatedAt),
+		UpdatedAt:     toTimestamp(post.UpdatedAt),
 	}
 }
 
@@ -305,6 +494,24 @@ func toTimestamp(t time.Time) *timestamppb.Timestamp {
 	return timestamppb.New(t)
 }
 
+// dateRangeFromProto converts optional from/to bounds, rejecting an inverted
+// range.
+func dateRangeFromProto(fromTS, toTS *timestamppb.Timestamp) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromTS != nil {
+		t := fromTS.AsTime()
+		from = &t
+	}
+	if toTS != nil {
+		t := toTS.AsTime()
+		to = &t
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+	return from, to, nil
+}
+
 func normalizeLimit(limit int) int {
 	if limit <= 0 || limit > 100 {
 		return 20
@@ -318,3 +525,19 @@ func normalizeOffset(offset int) int {
 	}
 	return offset
 }
+
+// appStatus builds the gRPC status for an application error. The error code and
+// HTTP status travel as an ErrorInfo detail so the gateway can answer with them
+// instead of reducing every failure to its gRPC code.
+func appStatus(code codes.Code, postErr *appErrors.PostError) error {
+	st := status.New(code, postErr.Message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   postErr.Code,
+		Domain:   "post-service",
+		Metadata: map[string]string{"http_status": strconv.Itoa(postErr.StatusCode)},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
@@ -24,10 +25,15 @@ import (
 	"post-service/internal/infrastructure/search"
 	grpcinterface "post-service/internal/interfaces/grpc"
 
+	"post-service/pkg/health"
 	"post-service/pkg/logger"
+	"post-service/pkg/markdown"
 	"post-service/pkg/metrics"
+	"post-service/pkg/retry"
+	"post-service/pkg/tracing"
 
 	postv1 "github.com/nikitashilov/microblog_grpc/proto/post/v1"
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	grpc "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
@@ -41,10 +47,29 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	appLogger := logger.New(cfg.LogLevel)
-	metrics.Init()
+	appLogger := logger.NewWithOptions(logger.Options{Service: "post-service", Level: cfg.LogLevel, Format: cfg.LogFormat})
 
-	db, err := postgres.NewConnection(cfg.Database)
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{ServiceName: "post-service", Endpoint: cfg.Tracing.Endpoint, SampleRatio: cfg.Tracing.SampleRatio})
+	if err != nil {
+		appLogger.Fatal("Failed to initialize tracing: " + err.Error())
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			appLogger.Warn("Failed to flush traces", logger.Err(err))
+		}
+	}()
+	if cfg.MetricsEnabled {
+		metrics.Init()
+	}
+
+	var db *sql.DB
+	err = retry.Do(context.Background(), startupRetry(cfg.StartupRetry), appLogger, "postgres", func(context.Context) error {
+		var err error
+		db, err = postgres.NewConnection(cfg.Database)
+		return err
+	})
 	if err != nil {
 		appLogger.Fatal("Failed to connect to database: " + err.Error())
 	}
@@ -56,22 +81,14 @@ func main() {
 
 	postRepo := postgres.NewPostRepository(db)
 
+	// Events are stored in the outbox whenever RabbitMQ is configured, even
+	// while it is unreachable; the relay connects and sends them once it is up.
 	var eventPublisher *messaging.EventPublisher
 
 	if cfg.RabbitMQ.Enabled {
-		eventPublisher, err = messaging.NewEventPublisher(cfg.RabbitMQ.URL, cfg.RabbitMQ.ExchangeName, appLogger)
-		if err != nil {
-			appLogger.Warn("Failed to initialize event publisher, continuing without events: " + err.Error())
-			eventPublisher = nil
-		} else {
-			appLogger.Info("Event publisher initialized successfully")
-			// Ensure we close the event publisher on shutdown
-			defer func() {
-				if eventPublisher != nil {
-					eventPublisher.Close()
-				}
-			}()
-		}
+		eventPublisher = messaging.NewEventPublisher(cfg.RabbitMQ.URL, cfg.RabbitMQ.ExchangeName, appLogger)
+		// Ensure we close the event publisher on shutdown
+		defer eventPublisher.Close()
 	} else {
 		appLogger.Info("RabbitMQ not configured, running without event publishing")
 	}
@@ -90,7 +107,19 @@ func main() {
 		appLogger.Info("KAFKA_BROKERS not set, running without search indexing")
 	}
 
-	postService := services.NewPostService(postRepo, eventPublisher, searchIndexer, appLogger)
+	healthChecker := health.NewChecker().Register("postgres", db.PingContext)
+	if eventPublisher != nil {
+		healthChecker.Register("rabbitmq", func(context.Context) error {
+			return eventPublisher.HealthCheck()
+		})
+	}
+
+	editLock := services.EditLockPolicy{
+		Window:       time.Duration(cfg.EditLock.WindowMinutes) * time.Minute,
+		AdminUserIDs: cfg.EditLock.AdminUserIDs,
+	}
+	contentSanitizer := markdown.NewSanitizer(markdown.ContentPolicy(cfg.ContentAllowedTags))
+	postService := services.NewPostService(postRepo, cfg.RabbitMQ.Enabled, searchIndexer, editLock, contentSanitizer, appLogger)
 
 	// One-shot search backfill (re-index existing posts). Gated by env so normal
 	// restarts don't re-run it; idempotent if it does. Use to index posts created
@@ -119,6 +148,8 @@ func main() {
 			Timeout:               1 * time.Second,
 		}),
 		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			tracing.UnaryServerInterceptor(),
 			metrics.UnaryServerInterceptor("post-service"),
 			unaryServerLoggingInterceptor(appLogger),
 		),
@@ -132,7 +163,7 @@ func main() {
 	}
 
 	grpcServer := grpc.NewServer(grpcOptions...)
-	postv1.RegisterPostServiceServer(grpcServer, grpcinterface.NewPostServer(postService, appLogger))
+	postv1.RegisterPostServiceServer(grpcServer, grpcinterface.NewPostServer(postService, healthChecker, appLogger))
 	if cfg.EnableGRPCReflection {
 		grpc_reflection.Register(grpcServer)
 	}
@@ -155,10 +186,13 @@ func main() {
 
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(tracing.GinMiddleware())
 	router.Use(metrics.GinMiddleware("post-service"))
-	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	if cfg.MetricsEnabled {
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
 
-	routes.SetupPostRoutes(router, postService, appLogger)
+	routes.SetupPostRoutes(router, postService, cfg.InternalServiceToken, cfg.InternalHTTPTrustMode, cfg.CORS, healthChecker, appLogger)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -168,23 +202,20 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Outbox relay: publishes the events the service stored with each change.
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
 	if eventPublisher != nil {
+		relay := messaging.NewOutboxRelay(postgres.NewOutboxRepository(db), eventPublisher, messaging.OutboxRelayConfig{
+			Interval:  time.Duration(cfg.RabbitMQ.RelayIntervalSeconds) * time.Second,
+			Retention: time.Duration(cfg.RabbitMQ.OutboxRetentionHours) * time.Hour,
+		}, appLogger)
 		go func() {
-			ticker := time.NewTicker(30 * time.Second)
-			defer ticker.Stop()
-
-			for {
-				select {
-				case <-ticker.C:
-					if !eventPublisher.IsConnected() {
-						appLogger.Warn("Event publisher disconnected, attempting reconnection...")
-						if err := eventPublisher.Reconnect(cfg.RabbitMQ.URL); err != nil {
-							appLogger.Error("Failed to reconnect event publisher: " + err.Error())
-						}
-					}
-				}
-			}
+			defer close(relayDone)
+			relay.Run(relayCtx)
 		}()
+	} else {
+		close(relayDone)
 	}
 
 	go func() {
@@ -200,6 +231,12 @@ func main() {
 
 	appLogger.Info("Shutting down server...")
 
+	// Stop the relay before the deferred publisher Close so it cannot publish
+	// or reopen the connection during shutdown. Events the relay has not sent
+	// yet stay in the outbox for the next start.
+	stopRelay()
+	<-relayDone
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -213,16 +250,21 @@ func main() {
 }
 
 // unaryServerLoggingInterceptor logs gRPC server requests and responses
-func unaryServerLoggingInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
+func unaryServerLoggingInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 		resp, err := handler(ctx, req)
 		duration := time.Since(start)
 
+		fields := []logger.Field{
+			logger.F("method", info.FullMethod),
+			logger.F("request_id", requestid.FromContext(ctx)),
+			logger.F("duration_ms", duration.Milliseconds()),
+		}
 		if err != nil {
-			logger.Warn(fmt.Sprintf("gRPC method %s failed: %v (duration: %v)", info.FullMethod, err, duration))
+			log.Warn("gRPC method failed", append(fields, logger.Err(err))...)
 		} else {
-			logger.Debug(fmt.Sprintf("gRPC method %s succeeded (duration: %v)", info.FullMethod, duration))
+			log.Debug("gRPC method succeeded", fields...)
 		}
 
 		return resp, err
@@ -257,3 +299,13 @@ func buildServerTransportCredentials(tlsCfg config.GRPCTLSConfig) (credentials.T
 
 	return credentials.NewTLS(tlsConfig), nil
 }
+
+// startupRetry converts the configured limits for retry.Do.
+func startupRetry(cfg config.StartupRetryConfig) retry.Config {
+	return retry.Config{
+		Attempts:       cfg.Attempts,
+		InitialBackoff: time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+		MaxWait:        time.Duration(cfg.MaxWaitSeconds) * time.Second,
+	}
+}
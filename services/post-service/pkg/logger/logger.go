@@ -1,14 +1,21 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Logger struct {
 	*log.Logger
-	level LogLevel
+	level  LogLevel
+	json   bool
+	fields []Field
 }
 
 type LogLevel int
@@ -21,40 +28,188 @@ const (
 	FATAL
 )
 
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// callDepth skips Output, log and the level method so the text format's
+	// file:line points at the caller.
+	callDepth = 3
+)
+
+// Field is a key/value pair attached to a log line.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F builds a Field.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err builds an "error" Field; a nil error is logged as an empty string.
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Value: ""}
+	}
+	return Field{Key: "error", Value: err.Error()}
+}
+
+// Options configures NewWithOptions.
+type Options struct {
+	Service string // prefix in text mode, "service" field in JSON mode
+	Level   string
+	Format  string // FormatText or FormatJSON
+}
+
+// New creates a human-readable logger at level.
 func New(level string) *Logger {
+	return NewWithOptions(Options{Level: level, Format: FormatText})
+}
+
+// NewWithOptions creates a logger. FormatJSON writes one JSON object per line
+// with time, level, msg and every field; any other format is human-readable.
+func NewWithOptions(opts Options) *Logger {
+	if strings.EqualFold(opts.Format, FormatJSON) {
+		l := &Logger{
+			Logger: log.New(os.Stdout, "", 0),
+			level:  parseLogLevel(opts.Level),
+			json:   true,
+		}
+		if opts.Service != "" {
+			l.fields = []Field{F("service", opts.Service)}
+		}
+		return l
+	}
+
+	prefix := ""
+	if opts.Service != "" {
+		prefix = "[" + strings.ToUpper(opts.Service) + "] "
+	}
 	return &Logger{
-		Logger: log.New(os.Stdout, "", log.LstdFlags|log.Lshortfile),
-		level:  parseLogLevel(level),
+		Logger: log.New(os.Stdout, prefix, log.LstdFlags|log.Lshortfile),
+		level:  parseLogLevel(opts.Level),
+	}
+}
+
+// With returns a logger that adds fields to every line. The result shares the
+// underlying writer.
+func (l *Logger) With(fields ...Field) *Logger {
+	child := *l
+	child.fields = append(append([]Field(nil), l.fields...), fields...)
+	return &child
+}
+
+func (l *Logger) Debug(msg string, fields ...Field) {
+	l.log(DEBUG, msg, fields)
+}
+
+func (l *Logger) Info(msg string, fields ...Field) {
+	l.log(INFO, msg, fields)
+}
+
+func (l *Logger) Warn(msg string, fields ...Field) {
+	l.log(WARN, msg, fields)
+}
+
+func (l *Logger) Error(msg string, fields ...Field) {
+	l.log(ERROR, msg, fields)
+}
+
+func (l *Logger) Fatal(msg string, fields ...Field) {
+	l.log(FATAL, msg, fields)
+	os.Exit(1)
+}
+
+func (l *Logger) log(level LogLevel, msg string, fields []Field) {
+	if level < l.level {
+		return
+	}
+
+	all := fields
+	if len(l.fields) > 0 {
+		all = append(append([]Field(nil), l.fields...), fields...)
+	}
+
+	if l.json {
+		_ = l.Output(callDepth, jsonLine(level, msg, all))
+		return
 	}
+	_ = l.Output(callDepth, textLine(level, msg, all))
 }
 
-func (l *Logger) Debug(msg string) {
-	if l.level <= DEBUG {
-		l.Printf("[DEBUG] %s", msg)
+func jsonLine(level LogLevel, msg string, fields []Field) string {
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSON(&buf, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSON(&buf, strings.ToLower(level.String()))
+	buf.WriteString(`,"msg":`)
+	writeJSON(&buf, msg)
+	for _, f := range fields {
+		buf.WriteByte(',')
+		writeJSON(&buf, f.Key)
+		buf.WriteByte(':')
+		writeJSON(&buf, fieldValue(f.Value))
 	}
+	buf.WriteByte('}')
+	return buf.String()
 }
 
-func (l *Logger) Info(msg string) {
-	if l.level <= INFO {
-		l.Printf("[INFO] %s", msg)
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		encoded, _ = json.Marshal(fmt.Sprint(v))
 	}
+	buf.Write(encoded)
 }
 
-func (l *Logger) Warn(msg string) {
-	if l.level <= WARN {
-		l.Printf("[WARN] %s", msg)
+func textLine(level LogLevel, msg string, fields []Field) string {
+	var b strings.Builder
+	b.WriteString("[")
+	b.WriteString(level.String())
+	b.WriteString("] ")
+	b.WriteString(msg)
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		value := fmt.Sprint(fieldValue(f.Value))
+		if value == "" || strings.ContainsAny(value, " =\"\n\t") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(value)
 	}
+	return b.String()
 }
 
-func (l *Logger) Error(msg string) {
-	if l.level <= ERROR {
-		l.Printf("[ERROR] %s", msg)
+// fieldValue renders errors and Stringers (durations, levels) as text in both
+// formats.
+func fieldValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return v
 	}
 }
 
-func (l *Logger) Fatal(msg string) {
-	l.Printf("[FATAL] %s", msg)
-	os.Exit(1)
+func (l LogLevel) String() string {
+	switch l {
+	case DEBUG:
+		return "DEBUG"
+	case INFO:
+		return "INFO"
+	case WARN:
+		return "WARN"
+	case ERROR:
+		return "ERROR"
+	default:
+		return "FATAL"
+	}
 }
 
 func parseLogLevel(level string) LogLevel {
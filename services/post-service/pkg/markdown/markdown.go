@@ -0,0 +1,68 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// blockTag matches the tags that separate words in rendered HTML.
+var blockTag = regexp.MustCompile(`(?i)</?(p|h[1-6]|li|ul|ol|blockquote|pre|table|tr|td|th|div|br|hr)\b[^>]*>`)
+
+// Renderer turns post Markdown into HTML that is safe to embed in a page.
+// Raw HTML in the source is passed to the renderer and then run through the
+// content policy, so harmless tags survive and scripts, event handlers and
+// javascript: links do not.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+	strict *bluemonday.Policy
+}
+
+// NewRenderer returns a Renderer that sanitizes its output with policy, or
+// ContentPolicy(nil) when policy is nil.
+func NewRenderer(policy *bluemonday.Policy) *Renderer {
+	if policy == nil {
+		policy = ContentPolicy(nil)
+	}
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+		),
+		policy: policy,
+		strict: bluemonday.StrictPolicy(),
+	}
+}
+
+// Render returns the sanitized HTML for source. Content that fails to render
+// comes back escaped as a single paragraph rather than being dropped.
+func (r *Renderer) Render(source string) string {
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(source), &buf); err != nil {
+		return "<p>" + html.EscapeString(source) + "</p>\n"
+	}
+	return r.policy.SanitizeReader(&buf).String()
+}
+
+// PlainText returns the words of source with the Markdown and any HTML tags
+// stripped and runs of whitespace collapsed to single spaces, for teasers and
+// other places that cannot show markup.
+func (r *Renderer) PlainText(source string) string {
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(source), &buf); err != nil {
+		buf.Reset()
+		buf.WriteString(html.EscapeString(source))
+	}
+	// Tags are removed without a trace, so space out the block ones first or
+	// "<p>one</p><p>two</p>" would read "onetwo".
+	spaced := blockTag.ReplaceAllString(buf.String(), " $0")
+	text := html.UnescapeString(r.strict.Sanitize(spaced))
+	return strings.Join(strings.Fields(text), " ")
+}
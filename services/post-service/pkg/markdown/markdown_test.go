@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	r := NewRenderer(nil)
+
+	cases := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "headings",
+			source: "# Title\n\n## Section\n\nBody text.",
+			want:   []string{"<h1>Title</h1>", "<h2>Section</h2>", "<p>Body text.</p>"},
+		},
+		{
+			name:   "links",
+			source: "See [the docs](https://example.com/docs).",
+			want:   []string{`<a href="https://example.com/docs" rel="nofollow">the docs</a>`},
+		},
+		{
+			name:    "javascript links are dropped",
+			source:  "[click](javascript:alert(1))",
+			want:    []string{"click"},
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:    "embedded html keeps safe tags only",
+			source:  "<em>kept</em>\n\n<script>alert(1)</script>\n\n<img src=\"x.png\" onerror=\"alert(1)\">",
+			want:    []string{"<em>kept</em>", `<img src="x.png">`},
+			notWant: []string{"<script", "onerror", "alert(1)"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := r.Render(tc.source)
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in %q", want, got)
+				}
+			}
+			for _, notWant := range tc.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("did not expect %q in %q", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	r := NewRenderer(nil)
+
+	cases := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"markup is stripped", "# Title\n\nSome **bold** and [a link](https://example.com).", "Title Some bold and a link."},
+		{"blocks keep words apart", "- one\n- two\n\nthree", "one two three"},
+		{"entities are decoded", "Fish & chips < 5", "Fish & chips < 5"},
+		{"scripts are dropped", "before <script>alert(1)</script> after", "before after"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := r.PlainText(tc.source); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
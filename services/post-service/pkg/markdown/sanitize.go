@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// ContentPolicy returns the policy applied to HTML in post content: the
+// user-generated-content allowlist plus extraTags. Scripts and styles are
+// dropped whatever is configured.
+func ContentPolicy(extraTags []string) *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	for _, tag := range extraTags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			policy.AllowElements(tag)
+		}
+	}
+	return policy
+}
+
+// Sanitizer removes unsafe HTML from post Markdown before it is stored. Only
+// the raw HTML the Markdown parser finds goes through the policy, so text,
+// code spans and fenced blocks are kept byte for byte and a post can still
+// show "<script>" as an example.
+type Sanitizer struct {
+	parser parser.Parser
+	policy *bluemonday.Policy
+}
+
+// NewSanitizer returns a Sanitizer applying policy, or ContentPolicy(nil)
+// when policy is nil.
+func NewSanitizer(policy *bluemonday.Policy) *Sanitizer {
+	if policy == nil {
+		policy = ContentPolicy(nil)
+	}
+	return &Sanitizer{
+		parser: goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser(),
+		policy: policy,
+	}
+}
+
+// Policy returns the policy the Sanitizer applies, for rendering the same
+// content to HTML.
+func (s *Sanitizer) Policy() *bluemonday.Policy {
+	return s.policy
+}
+
+// Sanitize returns source with every HTML block and inline tag replaced by
+// its sanitized form.
+func (s *Sanitizer) Sanitize(source string) string {
+	src := []byte(source)
+	var spans []text.Segment
+	_ = ast.Walk(s.parser.Parse(text.NewReader(src)), func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.HTMLBlock:
+			lines := node.Lines().Sliced(0, node.Lines().Len())
+			if node.HasClosure() {
+				lines = append(lines, node.ClosureLine)
+			}
+			spans = appendRuns(spans, lines)
+		case *ast.RawHTML:
+			spans = appendRuns(spans, node.Segments.Sliced(0, node.Segments.Len()))
+		}
+		return ast.WalkContinue, nil
+	})
+	if len(spans) == 0 {
+		return source
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, span := range spans {
+		out.Write(src[last:span.Start])
+		out.WriteString(s.policy.Sanitize(string(span.Value(src))))
+		last = span.Stop
+	}
+	out.Write(src[last:])
+	return out.String()
+}
+
+// appendRuns adds segments to spans, joining the ones that follow each other
+// in the source. Lines inside a blockquote or list are not adjacent, since the
+// container's markers sit between them, and are sanitized one by one.
+func appendRuns(spans, segments []text.Segment) []text.Segment {
+	for i, segment := range segments {
+		if i > 0 && spans[len(spans)-1].Stop == segment.Start {
+			spans[len(spans)-1].Stop = segment.Stop
+			continue
+		}
+		spans = append(spans, text.NewSegment(segment.Start, segment.Stop))
+	}
+	return spans
+}
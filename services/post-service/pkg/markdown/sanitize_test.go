@@ -0,0 +1,96 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize_StripsXSSPayloads(t *testing.T) {
+	s := NewSanitizer(nil)
+
+	cases := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "script block",
+			source:  "before\n\n<script>alert(document.cookie)</script>\n\nafter",
+			want:    []string{"before", "after"},
+			notWant: []string{"<script", "alert"},
+		},
+		{
+			name:    "inline event handler",
+			source:  `Look <img src="x.png" onerror="alert(1)"> here`,
+			want:    []string{`<img src="x.png">`, "Look", "here"},
+			notWant: []string{"onerror", "alert"},
+		},
+		{
+			name:    "javascript url",
+			source:  `<a href="javascript:alert(1)">click</a>`,
+			want:    []string{"click"},
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:    "embedded document",
+			source:  "<iframe src=\"https://evil.example\"></iframe>\n\ntext",
+			want:    []string{"text"},
+			notWant: []string{"iframe", "evil"},
+		},
+		{
+			name:    "html inside a blockquote",
+			source:  "> quoted\n> <svg onload=\"alert(1)\"></svg>",
+			want:    []string{"> quoted"},
+			notWant: []string{"onload", "alert"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.Sanitize(tc.source)
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in %q", want, got)
+				}
+			}
+			for _, notWant := range tc.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("did not expect %q in %q", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitize_KeepsMarkdownAndCode(t *testing.T) {
+	s := NewSanitizer(nil)
+
+	for _, source := range []string{
+		"# Title\n\n> quoted & kept\n\n3 < 4, see [docs](https://example.com) or <https://example.com>",
+		"Use the `<script>` tag with care.",
+		"```html\n<script>alert(1)</script>\n```\n\nThe sample above is not run.",
+		"```\n<script>\n```\n\nThe rest of the post survives an unclosed tag in a code block.",
+		"    <iframe src=\"https://example.com\"></iframe>\n\nIndented code.",
+		"<em>kept</em> and <strong>kept</strong>",
+	} {
+		if got := s.Sanitize(source); got != source {
+			t.Errorf("expected %q unchanged, got %q", source, got)
+		}
+	}
+}
+
+func TestContentPolicy_ExtraTags(t *testing.T) {
+	s := NewSanitizer(ContentPolicy([]string{" MARQUEE ", "script"}))
+
+	got := s.Sanitize("<marquee>moving</marquee> <script>alert(1)</script>")
+	if !strings.Contains(got, "<marquee>moving</marquee>") {
+		t.Errorf("expected the configured tag to be kept, got %q", got)
+	}
+	if strings.Contains(got, "<script") {
+		t.Errorf("expected script to be dropped even when configured, got %q", got)
+	}
+	if got := NewSanitizer(nil).Sanitize("<marquee>moving</marquee>"); strings.Contains(got, "marquee") {
+		t.Errorf("expected marquee to be dropped by default, got %q", got)
+	}
+}
@@ -16,11 +16,15 @@ import (
 )
 
 var (
-	reg     = prometheus.NewRegistry()
-	httpReq *prometheus.CounterVec
-	httpDur *prometheus.HistogramVec
-	grpcReq *prometheus.CounterVec
-	grpcDur *prometheus.HistogramVec
+	reg          = prometheus.NewRegistry()
+	httpReq      *prometheus.CounterVec
+	httpDur      *prometheus.HistogramVec
+	httpInFlight *prometheus.GaugeVec
+	grpcReq      *prometheus.CounterVec
+	grpcDur      *prometheus.HistogramVec
+
+	eventsPublished      *prometheus.CounterVec
+	eventPublishFailures *prometheus.CounterVec
 )
 
 // Init registers collectors and HTTP/gRPC metrics for this process.
@@ -38,6 +42,12 @@ func Init() {
 		Help:      "HTTP request duration in seconds.",
 		Buckets:   prometheus.DefBuckets,
 	}, []string{"service", "method", "route"})
+	httpInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "microblog",
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	}, []string{"service", "method", "route"})
 	grpcReq = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "microblog",
 		Subsystem: "grpc",
@@ -51,14 +61,29 @@ func Init() {
 		Help:      "gRPC unary server request duration in seconds.",
 		Buckets:   prometheus.DefBuckets,
 	}, []string{"service", "grpc_method"})
+	eventsPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "microblog",
+		Subsystem: "events",
+		Name:      "published_total",
+		Help:      "Domain events published to RabbitMQ.",
+	}, []string{"routing_key"})
+	eventPublishFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "microblog",
+		Subsystem: "events",
+		Name:      "publish_failures_total",
+		Help:      "Domain events that could not be published to RabbitMQ.",
+	}, []string{"routing_key"})
 
 	reg.MustRegister(
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 		httpReq,
 		httpDur,
+		httpInFlight,
 		grpcReq,
 		grpcDur,
+		eventsPublished,
+		eventPublishFailures,
 	)
 }
 
@@ -67,25 +92,53 @@ func Handler() http.Handler {
 	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
 }
 
-// GinMiddleware records request counts and latency after routing.
+// RecordEventPublish counts one attempt to publish a domain event, as a
+// failure when err is non-nil.
+func RecordEventPublish(routingKey string, err error) {
+	if eventsPublished == nil {
+		return
+	}
+	if err != nil {
+		eventPublishFailures.WithLabelValues(routingKey).Inc()
+		return
+	}
+	eventsPublished.WithLabelValues(routingKey).Inc()
+}
+
+// GinMiddleware records request counts, latency and in-flight requests after
+// routing. It is a no-op when Init was not called (METRICS_ENABLED=false).
 func GinMiddleware(service string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if httpReq == nil {
+			c.Next()
+			return
+		}
+
 		start := time.Now()
-		c.Next()
-		status := strconv.Itoa(c.Writer.Status())
 		route := c.FullPath()
 		if route == "" {
 			route = c.Request.URL.Path
 		}
 		method := c.Request.Method
+		inFlight := httpInFlight.WithLabelValues(service, method, route)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		c.Next()
+		status := strconv.Itoa(c.Writer.Status())
 		httpReq.WithLabelValues(service, method, route, status).Inc()
 		httpDur.WithLabelValues(service, method, route).Observe(time.Since(start).Seconds())
 	}
 }
 
 // UnaryServerInterceptor records gRPC unary call counts, codes, and latency.
+// It is a no-op when Init was not called (METRICS_ENABLED=false).
 func UnaryServerInterceptor(service string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if grpcReq == nil {
+			return handler(ctx, req)
+		}
+
 		start := time.Now()
 		resp, err := handler(ctx, req)
 		code := codes.OK
@@ -12,7 +12,9 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	searchv1 "github.com/nikitashilov/microblog_grpc/proto/search/v1"
 	userv1 "github.com/nikitashilov/microblog_grpc/proto/user/v1"
 	"search-service/internal/infrastructure/opensearch"
@@ -29,9 +31,38 @@ type SearchService struct {
 	postsIndex string
 	userConn   *grpc.ClientConn
 	userClient userv1.UserServiceClient
+	results    ResultOptions
 	log        *logger.Logger
 }
 
+const (
+	maxResultsPerPage        = 50
+	defaultSnippetLength     = 200
+	defaultContentMaxResults = 10
+)
+
+// ResultOptions controls how post hits are rendered. Zero values fall back to
+// the defaults so tests can build a SearchService literal.
+type ResultOptions struct {
+	SnippetLength     int  // runes kept in content_preview
+	ContentEnabled    bool // honour SearchRequest.include_content
+	ContentMaxResults int  // posts page cap when content is included
+}
+
+func (o ResultOptions) snippetLength() int {
+	if o.SnippetLength <= 0 {
+		return defaultSnippetLength
+	}
+	return o.SnippetLength
+}
+
+func (o ResultOptions) contentMaxResults() int {
+	if o.ContentMaxResults <= 0 {
+		return defaultContentMaxResults
+	}
+	return o.ContentMaxResults
+}
+
 type GRPCTLSOptions struct {
 	Enabled  bool
 	CAFile   string
@@ -39,13 +70,16 @@ type GRPCTLSOptions struct {
 	KeyFile  string
 }
 
-func NewSearchService(os *opensearch.Client, usersIndex, postsIndex string, userServiceAddr string, tlsOpts GRPCTLSOptions, log *logger.Logger) (*SearchService, error) {
+func NewSearchService(os *opensearch.Client, usersIndex, postsIndex string, userServiceAddr string, tlsOpts GRPCTLSOptions, results ResultOptions, log *logger.Logger) (*SearchService, error) {
 	transportCreds, err := buildClientTransportCredentials(tlsOpts)
 	if err != nil {
 		return nil, fmt.Errorf("build user-service transport credentials: %w", err)
 	}
 
-	conn, err := grpc.NewClient(userServiceAddr, grpc.WithTransportCredentials(transportCreds))
+	conn, err := grpc.NewClient(userServiceAddr,
+		grpc.WithTransportCredentials(transportCreds),
+		grpc.WithUnaryInterceptor(requestid.UnaryClientInterceptor()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("user service gRPC client: %w", err)
 	}
@@ -55,6 +89,7 @@ func NewSearchService(os *opensearch.Client, usersIndex, postsIndex string, user
 		postsIndex: postsIndex,
 		userConn:   conn,
 		userClient: userv1.NewUserServiceClient(conn),
+		results:    results,
 		log:        log,
 	}, nil
 }
@@ -73,13 +108,18 @@ func (s *SearchService) Search(ctx context.Context, req *searchv1.SearchRequest)
 	}
 
 	usersLimit := int(req.GetUsersLimit())
-	if usersLimit <= 0 || usersLimit > 50 {
+	if usersLimit <= 0 || usersLimit > maxResultsPerPage {
 		usersLimit = 20
 	}
 	postsLimit := int(req.GetPostsLimit())
-	if postsLimit <= 0 || postsLimit > 50 {
+	if postsLimit <= 0 || postsLimit > maxResultsPerPage {
 		postsLimit = 20
 	}
+	// Full content makes every hit much larger, so those pages are capped lower.
+	includeContent := req.GetIncludeContent() && s.results.ContentEnabled
+	if includeContent && postsLimit > s.results.contentMaxResults() {
+		postsLimit = s.results.contentMaxResults()
+	}
 
 	usersFrom := decodeCursor(req.GetUsersCursor())
 	postsFrom := decodeCursor(req.GetPostsCursor())
@@ -107,7 +147,7 @@ func (s *SearchService) Search(ctx context.Context, req *searchv1.SearchRequest)
 		done <- struct{}{}
 	}()
 	go func() {
-		postHits, postNext, postPartial, postErr = s.searchPosts(ctx, query, postsLimit, postsFrom)
+		postHits, postNext, postPartial, postErr = s.searchPosts(ctx, query, postsLimit, postsFrom, includeContent)
 		done <- struct{}{}
 	}()
 	<-done
@@ -189,7 +229,7 @@ func (s *SearchService) searchUsers(ctx context.Context, query string, limit, fr
 	return hits, nextFrom, partial, nil
 }
 
-func (s *SearchService) searchPosts(ctx context.Context, query string, limit, from int) ([]searchv1.SearchPostHit, int, bool, error) {
+func (s *SearchService) searchPosts(ctx context.Context, query string, limit, from int, includeContent bool) ([]searchv1.SearchPostHit, int, bool, error) {
 	if s.os == nil {
 		return nil, 0, true, fmt.Errorf("opensearch not configured")
 	}
@@ -225,23 +265,32 @@ func (s *SearchService) searchPosts(ctx context.Context, query string, limit, fr
 	partial := out.Shards.Failed > 0
 	hits := make([]searchv1.SearchPostHit, 0, len(out.Hits.Hits))
 	for _, h := range out.Hits.Hits {
-		preview := h.Source.Content
-		if len(preview) > 200 {
-			preview = preview[:200] + "..."
-		}
 		hits = append(hits, searchv1.SearchPostHit{
 			Id:             h.Source.ID,
 			UserId:         h.Source.UserID,
 			Title:          h.Source.Title,
 			Slug:           h.Source.Slug,
-			ContentPreview: preview,
+			ContentPreview: snippet(h.Source.Content, s.results.snippetLength()),
 			Published:      h.Source.Published,
 		})
+		if includeContent {
+			hits[len(hits)-1].Content = h.Source.Content
+		}
 	}
 	nextFrom := from + len(hits)
 	return hits, nextFrom, partial, nil
 }
 
+// snippet truncates content to at most n runes so multi-byte characters are
+// never split, appending "..." when anything was cut.
+func snippet(content string, n int) string {
+	if utf8.RuneCountInString(content) <= n {
+		return content
+	}
+	runes := []rune(content)
+	return string(runes[:n]) + "..."
+}
+
 func buildUserSearchBody(query string, size, from int) []byte {
 	// Prefix + fuzzy on name (and bio). Lowercase the prefix value: prefix queries
 	// match raw index terms (lowercased by the analyzer), so an upper/mixed-case
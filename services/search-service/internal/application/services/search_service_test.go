@@ -4,11 +4,18 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	searchv1 "github.com/nikitashilov/microblog_grpc/proto/search/v1"
+	"search-service/internal/infrastructure/opensearch"
 	"search-service/pkg/logger"
+
+	opensearchgo "github.com/opensearch-project/opensearch-go/v2"
 )
 
 func TestEncodeDecodeCursor(t *testing.T) {
@@ -147,3 +154,114 @@ func TestSearch_OpenSearchUnavailable_PartialResult(t *testing.T) {
 		t.Errorf("expected no posts when OpenSearch nil, got %d", len(resp.GetPosts()))
 	}
 }
+
+// fakePostsIndex serves a single long post for any search and records the
+// size requested against the posts index.
+type fakePostsIndex struct {
+	mu        sync.Mutex
+	postsSize string
+}
+
+func (f *fakePostsIndex) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !strings.HasPrefix(r.URL.Path, "/posts/") {
+		_, _ = w.Write([]byte(`{"hits":{"hits":[]},"_shards":{"failed":0}}`))
+		return
+	}
+	f.mu.Lock()
+	f.postsSize = r.URL.Query().Get("size")
+	f.mu.Unlock()
+
+	source, _ := json.Marshal(map[string]interface{}{
+		"id":        "p1",
+		"user_id":   "u1",
+		"title":     "Long read",
+		"slug":      "long-read",
+		"content":   strings.Repeat("ж", 300),
+		"published": true,
+	})
+	_, _ = w.Write([]byte(`{"hits":{"hits":[{"_source":` + string(source) + `}]},"_shards":{"failed":0}}`))
+}
+
+func newFakeSearchService(t *testing.T, results ResultOptions) (*SearchService, *fakePostsIndex) {
+	t.Helper()
+	fake := &fakePostsIndex{}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client, err := opensearchgo.NewClient(opensearchgo.Config{Addresses: []string{srv.URL}})
+	if err != nil {
+		t.Fatalf("opensearch client: %v", err)
+	}
+	return &SearchService{
+		os:         &opensearch.Client{Client: client},
+		usersIndex: "users",
+		postsIndex: "posts",
+		results:    results,
+		log:        logger.New("error"),
+	}, fake
+}
+
+func TestSearch_DefaultsToSummariesOnly(t *testing.T) {
+	s, fake := newFakeSearchService(t, ResultOptions{SnippetLength: 50, ContentEnabled: true})
+
+	resp, err := s.Search(context.Background(), &searchv1.SearchRequest{Query: "long", PostsLimit: 40})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(resp.GetPosts()) != 1 {
+		t.Fatalf("expected 1 post, got %d", len(resp.GetPosts()))
+	}
+	post := resp.GetPosts()[0]
+	if post.GetContent() != "" {
+		t.Errorf("expected no content without include_content, got %d bytes", len(post.GetContent()))
+	}
+	if want := strings.Repeat("ж", 50) + "..."; post.GetContentPreview() != want {
+		t.Errorf("preview = %q, want %q", post.GetContentPreview(), want)
+	}
+	if fake.postsSize != "40" {
+		t.Errorf("posts size = %q, want 40", fake.postsSize)
+	}
+}
+
+func TestSearch_IncludeContentUsesStricterPageCap(t *testing.T) {
+	s, fake := newFakeSearchService(t, ResultOptions{ContentEnabled: true, ContentMaxResults: 5})
+
+	resp, err := s.Search(context.Background(), &searchv1.SearchRequest{Query: "long", PostsLimit: 40, IncludeContent: true})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if fake.postsSize != "5" {
+		t.Errorf("posts size = %q, want content cap 5", fake.postsSize)
+	}
+	if len(resp.GetPosts()) != 1 {
+		t.Fatalf("expected 1 post, got %d", len(resp.GetPosts()))
+	}
+	if got := resp.GetPosts()[0].GetContent(); got != strings.Repeat("ж", 300) {
+		t.Errorf("expected full content, got %d bytes", len(got))
+	}
+}
+
+func TestSearch_IncludeContentIgnoredWhenDisabled(t *testing.T) {
+	s, fake := newFakeSearchService(t, ResultOptions{ContentEnabled: false, ContentMaxResults: 5})
+
+	resp, err := s.Search(context.Background(), &searchv1.SearchRequest{Query: "long", PostsLimit: 40, IncludeContent: true})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if fake.postsSize != "40" {
+		t.Errorf("posts size = %q, want 40", fake.postsSize)
+	}
+	if got := resp.GetPosts()[0].GetContent(); got != "" {
+		t.Errorf("expected no content when disabled, got %d bytes", len(got))
+	}
+}
+
+func TestSnippetKeepsRunesIntact(t *testing.T) {
+	if got := snippet("héllo", 10); got != "héllo" {
+		t.Errorf("short content should be unchanged, got %q", got)
+	}
+	if got := snippet("héllo wörld", 7); got != "héllo w..." {
+		t.Errorf("snippet = %q, want %q", got, "héllo w...")
+	}
+}
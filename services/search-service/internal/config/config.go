@@ -11,12 +11,15 @@ type Config struct {
 	GRPCPort                 string
 	MetricsHTTPPort          string
 	Environment              string
+	MetricsEnabled           bool // exposes /metrics and records Prometheus metrics
 	LogLevel                 string
+	LogFormat                string // "json" or "text"; defaults to json in production
 	OpenSearch               OpenSearchConfig
 	Kafka                    KafkaConfig
 	UserServiceGRPC          string
 	UsersIndexName           string
 	PostsIndexName           string
+	Results                  ResultsConfig
 	GRPCTLS                  GRPCTLSConfig
 	ServiceTransportSecurity string
 	EnableGRPCReflection     bool
@@ -27,6 +30,14 @@ type OpenSearchConfig struct {
 	Enabled bool
 }
 
+// ResultsConfig shapes post hits. Search returns previews unless the caller
+// opts in to full content, which is capped at a smaller page size.
+type ResultsConfig struct {
+	SnippetLength     int
+	ContentEnabled    bool
+	ContentMaxResults int
+}
+
 type KafkaConfig struct {
 	Brokers              []string
 	TopicUsers           string
@@ -51,7 +62,9 @@ func Load() (*Config, error) {
 		GRPCPort:        getEnv("GRPC_PORT", "50054"),
 		MetricsHTTPPort: getEnv("METRICS_HTTP_PORT", "9095"),
 		Environment:     getEnv("ENVIRONMENT", "development"),
+		MetricsEnabled:  getEnvAsBool("METRICS_ENABLED", true),
 		LogLevel:        getEnv("LOG_LEVEL", "info"),
+		LogFormat:       getEnv("LOG_FORMAT", defaultLogFormat(getEnv("ENVIRONMENT", "development"))),
 		UserServiceGRPC: getEnv("USER_SERVICE_GRPC_ADDR", "user-service:50052"),
 		UsersIndexName:  getEnv("OPENSEARCH_USERS_INDEX", "users"),
 		PostsIndexName:  getEnv("OPENSEARCH_POSTS_INDEX", "posts"),
@@ -59,6 +72,11 @@ func Load() (*Config, error) {
 			URL:     getEnv("OPENSEARCH_URL", "http://opensearch:9200"),
 			Enabled: getEnv("OPENSEARCH_URL", "") != "",
 		},
+		Results: ResultsConfig{
+			SnippetLength:     getEnvAsInt("SEARCH_SNIPPET_LENGTH", 200),
+			ContentEnabled:    getEnvAsBool("SEARCH_CONTENT_ENABLED", true),
+			ContentMaxResults: getEnvAsInt("SEARCH_CONTENT_MAX_RESULTS", 10),
+		},
 		Kafka: KafkaConfig{
 			Brokers:              getEnvSlice("KAFKA_BROKERS", []string{"kafka:9092"}),
 			TopicUsers:           getEnv("KAFKA_TOPIC_USERS", "search.users"),
@@ -87,6 +105,13 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+func defaultLogFormat(environment string) string {
+	if environment == "production" {
+		return "json"
+	}
+	return "text"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -102,6 +127,9 @@ func getEnvSlice(key string, defaultVal []string) []string {
 }
 
 func (c *Config) validate() error {
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		return fmt.Errorf("LOG_FORMAT must be json or text")
+	}
 	if c.GRPCPort == "" {
 		return fmt.Errorf("GRPC_PORT is required")
 	}
@@ -123,6 +151,12 @@ func (c *Config) validate() error {
 	if c.Kafka.RetryBackoffMS < 0 {
 		return fmt.Errorf("KAFKA_RETRY_BACKOFF_MS must be >= 0")
 	}
+	if c.Results.SnippetLength < 1 {
+		return fmt.Errorf("SEARCH_SNIPPET_LENGTH must be >= 1")
+	}
+	if c.Results.ContentMaxResults < 1 || c.Results.ContentMaxResults > 50 {
+		return fmt.Errorf("SEARCH_CONTENT_MAX_RESULTS must be between 1 and 50")
+	}
 	if c.GRPCTLS.Enabled {
 		if c.GRPCTLS.CAFile == "" {
 			return fmt.Errorf("GRPC_TLS_CA_FILE is required when GRPC_TLS_ENABLED=true")
@@ -27,3 +27,10 @@ func TestValidateTransportSecurityMode(t *testing.T) {
 		t.Fatal("expected app_mtls to require GRPC_TLS_ENABLED")
 	}
 }
+
+func TestLoad_rejectsInvalidResultsConfig(t *testing.T) {
+	t.Setenv("SEARCH_CONTENT_MAX_RESULTS", "100")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error when SEARCH_CONTENT_MAX_RESULTS exceeds the page cap")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	searchv1 "github.com/nikitashilov/microblog_grpc/proto/search/v1"
 	"search-service/internal/application/services"
 	"search-service/internal/config"
@@ -34,9 +35,11 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	metrics.Init()
+	if cfg.MetricsEnabled {
+		metrics.Init()
+	}
 
-	appLogger := logger.New(cfg.LogLevel)
+	appLogger := logger.NewWithOptions(logger.Options{Service: "search-service", Level: cfg.LogLevel, Format: cfg.LogFormat})
 
 	var osClient *opensearch.Client
 	if cfg.OpenSearch.Enabled {
@@ -62,6 +65,11 @@ func main() {
 			CertFile: cfg.GRPCTLS.CertFile,
 			KeyFile:  cfg.GRPCTLS.KeyFile,
 		},
+		services.ResultOptions{
+			SnippetLength:     cfg.Results.SnippetLength,
+			ContentEnabled:    cfg.Results.ContentEnabled,
+			ContentMaxResults: cfg.Results.ContentMaxResults,
+		},
 		appLogger,
 	)
 	if err != nil {
@@ -71,6 +79,7 @@ func main() {
 
 	grpcOptions := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
 			metrics.UnaryServerInterceptor("search-service"),
 			unaryLoggingInterceptor(appLogger),
 		),
@@ -102,7 +111,9 @@ func main() {
 	}()
 
 	metricsMux := http.NewServeMux()
-	metricsMux.Handle("/metrics", metrics.Handler())
+	if cfg.MetricsEnabled {
+		metricsMux.Handle("/metrics", metrics.Handler())
+	}
 	metricsMux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
@@ -172,7 +183,11 @@ func unaryLoggingInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 		resp, err := handler(ctx, req)
-		log.Debug(info.FullMethod + " " + time.Since(start).String())
+		log.Debug("gRPC method handled",
+			logger.F("method", info.FullMethod),
+			logger.F("request_id", requestid.FromContext(ctx)),
+			logger.F("duration_ms", time.Since(start).Milliseconds()),
+		)
 		return resp, err
 	}
 }
@@ -49,8 +49,13 @@ func Handler() http.Handler {
 }
 
 // UnaryServerInterceptor records gRPC unary call counts, codes, and latency.
+// It is a no-op when Init was not called (METRICS_ENABLED=false).
 func UnaryServerInterceptor(service string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if grpcReq == nil {
+			return handler(ctx, req)
+		}
+
 		start := time.Now()
 		resp, err := handler(ctx, req)
 		code := codes.OK
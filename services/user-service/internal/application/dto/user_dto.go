@@ -10,6 +10,9 @@ type CreateUserRequest struct {
 	Name     string `json:"name" binding:"required,min=1,max=100"`
 	Picture  string `json:"picture,omitempty"`
 	Password string `json:"password,omitempty"` // optional; for email/password signup only
+	Username string `json:"username,omitempty"`
+	// EmailVerified is only set by auth-service over gRPC, never from a client body.
+	EmailVerified bool `json:"-"`
 }
 
 type UpdateUserRequest struct {
@@ -18,34 +21,57 @@ type UpdateUserRequest struct {
 	Bio      *string `json:"bio,omitempty" binding:"omitempty,max=500"`
 	Location *string `json:"location,omitempty" binding:"omitempty,max=100"`
 	Website  *string `json:"website,omitempty" binding:"omitempty,url"`
+	Username *string `json:"username,omitempty"`
 }
 
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Picture   string    `json:"picture,omitempty"`
-	Bio       string    `json:"bio,omitempty"`
-	Location  string    `json:"location,omitempty"`
-	Website   string    `json:"website,omitempty"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Username        string     `json:"username,omitempty"`
+	Picture         string     `json:"picture,omitempty"`
+	Bio             string     `json:"bio,omitempty"`
+	Location        string     `json:"location,omitempty"`
+	Website         string     `json:"website,omitempty"`
+	Role            string     `json:"role"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	PendingEmail    string     `json:"pending_email,omitempty"` // account owner only
+	LastSeenAt      *time.Time `json:"last_seen_at,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
+// UserProfileResponse is served on public routes, so it has no email field;
+// only the authenticated UserResponse carries it.
 type UserProfileResponse struct {
 	ID       string `json:"id"`
-	Email    string `json:"email"`
 	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
 	Picture  string `json:"picture,omitempty"`
 	Bio      string `json:"bio,omitempty"`
 	Location string `json:"location,omitempty"`
 	Website  string `json:"website,omitempty"`
 }
 
+type GetUsersBatchRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
 type ListUsersRequest struct {
 	Limit  int `form:"limit,default=20" binding:"omitempty,min=1,max=100"`
 	Offset int `form:"offset,default=0" binding:"omitempty,min=0"`
+	// Sort is created_at (the default), name or email. Order is asc or desc
+	// and defaults to desc for created_at and asc otherwise.
+	Sort  string `form:"sort"`
+	Order string `form:"order"`
+	// CreatedAfter is inclusive and CreatedBefore exclusive, both RFC 3339.
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	// IncludeInactive lists deactivated users too; only set for admins.
+	IncludeInactive bool `form:"-"`
 }
 
 type SearchUsersRequest struct {
@@ -55,10 +81,38 @@ type SearchUsersRequest struct {
 }
 
 type ListUsersResponse struct {
-	Users  []*UserResponse `json:"users"`
-	Limit  int             `json:"limit"`
-	Offset int             `json:"offset"`
-	Total  int             `json:"total"`
+	Users   []*UserResponse `json:"users"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+	Total   int             `json:"total"`
+	HasMore bool            `json:"has_more"`
+}
+
+// GetFollowerIDsRequest pages through a user's follower IDs for internal
+// fan-out; pages are larger than the public follower list.
+type GetFollowerIDsRequest struct {
+	Limit  int    `form:"limit,default=500" binding:"omitempty,min=1,max=1000"`
+	Cursor string `form:"cursor"`
+}
+
+type FollowerIDsResponse struct {
+	FollowerIDs []string `json:"follower_ids"`
+	NextCursor  string   `json:"next_cursor,omitempty"`
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ChangeEmailResponse confirms a change is pending; the token goes to the new
+// address only.
+type ChangeEmailResponse struct {
+	PendingEmail string    `json:"pending_email"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type VerifyEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
 }
 
 type UserStatsResponse struct {
@@ -71,4 +125,5 @@ type ValidateCredentialsResponse struct {
 	Email   string `json:"email"`
 	Name    string `json:"name"`
 	Picture string `json:"picture,omitempty"`
+	Role    string `json:"role"`
 }
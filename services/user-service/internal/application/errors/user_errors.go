@@ -38,4 +38,18 @@ var (
 	ErrInvalidRequest     = NewUserError("INVALID_REQUEST", "Invalid request parameters", http.StatusBadRequest)
 	ErrServiceUnavailable = NewUserError("SERVICE_UNAVAILABLE", "User service temporarily unavailable", http.StatusServiceUnavailable)
 	ErrCannotFollowSelf   = NewUserError("CANNOT_FOLLOW_SELF", "Cannot follow yourself", http.StatusBadRequest)
+	ErrFollowBlocked      = NewUserError("FOLLOW_BLOCKED", "This user has blocked you", http.StatusForbidden)
+	ErrCannotBlockSelf    = NewUserError("CANNOT_BLOCK_SELF", "Cannot block yourself", http.StatusBadRequest)
+	ErrAlreadyBlocked     = NewUserError("ALREADY_BLOCKED", "User is already blocked", http.StatusConflict)
+	ErrUsernameTaken      = NewUserError("USERNAME_TAKEN", "Username is already taken", http.StatusConflict)
+	ErrInvalidUsername    = NewUserError("INVALID_USERNAME", "Username must be 3-30 characters of lowercase letters, digits or underscores", http.StatusBadRequest)
+	ErrRouteNotFound      = NewUserError("NOT_FOUND", "Route not found", http.StatusNotFound)
+	ErrBatchTooLarge      = NewUserError("BATCH_TOO_LARGE", "At most 200 distinct user IDs per batch", http.StatusBadRequest)
+	ErrInvalidEmail       = NewUserError("INVALID_EMAIL", "Invalid email address", http.StatusBadRequest)
+	ErrEmailUnchanged     = NewUserError("EMAIL_UNCHANGED", "New email matches the current one", http.StatusBadRequest)
+	ErrEmailTaken         = NewUserError("EMAIL_TAKEN", "Email is already in use", http.StatusConflict)
+	ErrEmailChangeInvalid = NewUserError("EMAIL_CHANGE_INVALID", "Email change token is invalid or expired", http.StatusBadRequest)
+	ErrEmailChangeFailed  = NewUserError("EMAIL_CHANGE_FAILED", "Failed to change email", http.StatusInternalServerError)
+	ErrNoEmailSender      = NewUserError("EMAIL_CHANGE_UNAVAILABLE", "Email changes are not available yet", http.StatusServiceUnavailable)
+	ErrAccountDeactivated = NewUserError("ACCOUNT_DEACTIVATED", "Account was deactivated by an administrator", http.StatusForbidden)
 )
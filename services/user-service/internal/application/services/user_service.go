@@ -2,8 +2,14 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stdErrors "errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"user-service/internal/application/dto"
 	"user-service/internal/application/errors"
@@ -16,27 +22,34 @@ import (
 )
 
 type UserService struct {
-	userRepo   repositories.UserRepository
-	followRepo repositories.FollowRepository
-	logger     *logger.Logger
+	userRepo    repositories.UserRepository
+	followRepo  repositories.FollowRepository
+	blockRepo   repositories.BlockRepository
+	emailSender repositories.EmailChangeSender
+	logger      *logger.Logger
 }
 
-func NewUserService(userRepo repositories.UserRepository, followRepo repositories.FollowRepository, logger *logger.Logger) *UserService {
+func NewUserService(userRepo repositories.UserRepository, followRepo repositories.FollowRepository, blockRepo repositories.BlockRepository, emailSender repositories.EmailChangeSender, logger *logger.Logger) *UserService {
 	return &UserService{
-		userRepo:   userRepo,
-		followRepo: followRepo,
-		logger:     logger,
+		userRepo:    userRepo,
+		followRepo:  followRepo,
+		blockRepo:   blockRepo,
+		emailSender: emailSender,
+		logger:      logger,
 	}
 }
 
 func (s *UserService) CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*dto.UserResponse, error) {
 	s.logger.Info(fmt.Sprintf("Creating user with email: %s", req.Email))
 
-	// Check if user already exists
+	// Check if user already exists; a deactivated account still holds its email.
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil && existingUser != nil {
 		return nil, errors.ErrUserAlreadyExists
 	}
+	if deactivated, err := s.userRepo.GetDeactivatedByEmail(ctx, req.Email); err == nil && deactivated != nil {
+		return nil, errors.ErrUserAlreadyExists
+	}
 
 	id := strings.TrimSpace(req.ID)
 	if id == "" {
@@ -47,9 +60,27 @@ func (s *UserService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 		ID:       id,
 		Email:    req.Email,
 		Name:     req.Name,
+		Username: entities.NormalizeUsername(req.Username),
 		Picture:  req.Picture,
+		Role:     entities.RoleUser,
 		IsActive: true,
 	}
+	if req.EmailVerified {
+		verifiedAt := time.Now()
+		user.EmailVerified = true
+		user.EmailVerifiedAt = &verifiedAt
+	}
+
+	if user.Username != "" {
+		if !entities.IsValidUsername(user.Username) {
+			return nil, errors.ErrInvalidUsername
+		}
+		if taken, err := s.usernameTaken(ctx, user.Username, id); err != nil {
+			return nil, errors.ErrUserCreationFailed
+		} else if taken {
+			return nil, errors.ErrUsernameTaken
+		}
+	}
 
 	if req.Password != "" {
 		// Enforce password policy on the receiving service: the gRPC CreateUser
@@ -75,6 +106,9 @@ func (s *UserService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
+		if stdErrors.Is(err, repositories.ErrUsernameTaken) {
+			return nil, errors.ErrUsernameTaken
+		}
 		s.logger.Error(fmt.Sprintf("Failed to create user: %v", err))
 		return nil, errors.ErrUserCreationFailed
 	}
@@ -82,17 +116,33 @@ func (s *UserService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 	s.logger.Info(fmt.Sprintf("User created successfully: %s", user.ID))
 
 	return &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Picture:   user.Picture,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		Username:        user.Username,
+		Picture:         user.Picture,
+		Role:            user.Role,
+		EmailVerified:   user.EmailVerified,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		LastSeenAt:      user.LastSeenAt,
+		Status:          user.Status,
+		IsActive:        user.IsActive,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
 	}, nil
 }
 
 func (s *UserService) GetUser(ctx context.Context, id string) (*dto.UserResponse, error) {
+	return s.getUser(ctx, id, false)
+}
+
+// GetOwnUser is GetUser for the account owner: the response also carries the
+// address of an unconfirmed email change.
+func (s *UserService) GetOwnUser(ctx context.Context, id string) (*dto.UserResponse, error) {
+	return s.getUser(ctx, id, true)
+}
+
+func (s *UserService) getUser(ctx context.Context, id string, owner bool) (*dto.UserResponse, error) {
 	s.logger.Info(fmt.Sprintf("Getting user: %s", id))
 
 	user, err := s.userRepo.GetByID(ctx, id)
@@ -101,21 +151,36 @@ func (s *UserService) GetUser(ctx context.Context, id string) (*dto.UserResponse
 		return nil, errors.ErrUserNotFound
 	}
 
-	return &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Picture:   user.Picture,
-		Bio:       user.Bio,
-		Location:  user.Location,
-		Website:   user.Website,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, nil
+	response := &dto.UserResponse{
+		ID:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		Username:        user.Username,
+		Picture:         user.Picture,
+		Bio:             user.Bio,
+		Location:        user.Location,
+		Website:         user.Website,
+		Role:            user.Role,
+		EmailVerified:   user.EmailVerified,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		LastSeenAt:      user.LastSeenAt,
+		Status:          user.Status,
+		IsActive:        user.IsActive,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+	if owner {
+		response.PendingEmail = user.PendingEmail
+	}
+	return response, nil
 }
 
 func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*dto.UserResponse, error) {
+	// Emails are stored lowercased (see User.Sanitize).
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil, errors.ErrUserNotFound
+	}
 	s.logger.Info(fmt.Sprintf("Getting user by email: %s", email))
 
 	user, err := s.userRepo.GetByEmail(ctx, email)
@@ -125,16 +190,22 @@ func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*dto.Us
 	}
 
 	return &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Picture:   user.Picture,
-		Bio:       user.Bio,
-		Location:  user.Location,
-		Website:   user.Website,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		Username:        user.Username,
+		Picture:         user.Picture,
+		Bio:             user.Bio,
+		Location:        user.Location,
+		Website:         user.Website,
+		Role:            user.Role,
+		EmailVerified:   user.EmailVerified,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		LastSeenAt:      user.LastSeenAt,
+		Status:          user.Status,
+		IsActive:        user.IsActive,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
 	}, nil
 }
 
@@ -150,8 +221,83 @@ func (s *UserService) GetUserProfile(ctx context.Context, id string) (*dto.UserP
 	profile := user.ToProfile()
 	return &dto.UserProfileResponse{
 		ID:       profile.ID,
-		Email:    profile.Email,
 		Name:     profile.Name,
+		Username: profile.Username,
+		Picture:  profile.Picture,
+		Bio:      profile.Bio,
+		Location: profile.Location,
+		Website:  profile.Website,
+	}, nil
+}
+
+// MaxUsersBatch caps the distinct IDs one GetUsersBatch call resolves.
+const MaxUsersBatch = 200
+
+// GetUsersBatch resolves public profiles for ids in one query, keyed by ID.
+// Duplicate and empty IDs are ignored; unknown or deactivated IDs are simply
+// missing from the result.
+func (s *UserService) GetUsersBatch(ctx context.Context, ids []string) (map[string]*dto.UserProfileResponse, error) {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	if len(unique) > MaxUsersBatch {
+		return nil, errors.ErrBatchTooLarge
+	}
+
+	profiles := make(map[string]*dto.UserProfileResponse, len(unique))
+	if len(unique) == 0 {
+		return profiles, nil
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to get users batch: %v", err))
+		return nil, errors.ErrUserListFailed
+	}
+
+	// Email stays out: batches enrich other users' content.
+	for _, u := range users {
+		profiles[u.ID] = &dto.UserProfileResponse{
+			ID:       u.ID,
+			Name:     u.Name,
+			Username: u.Username,
+			Picture:  u.Picture,
+			Bio:      u.Bio,
+			Location: u.Location,
+			Website:  u.Website,
+		}
+	}
+	return profiles, nil
+}
+
+// GetUserProfileByUsername resolves a public profile by its handle.
+func (s *UserService) GetUserProfileByUsername(ctx context.Context, username string) (*dto.UserProfileResponse, error) {
+	username = entities.NormalizeUsername(username)
+	if !entities.IsValidUsername(username) {
+		return nil, errors.ErrInvalidUsername
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("User not found by username: %s", username))
+		return nil, errors.ErrUserNotFound
+	}
+
+	profile := user.ToProfile()
+	return &dto.UserProfileResponse{
+		ID:       profile.ID,
+		Name:     profile.Name,
+		Username: profile.Username,
 		Picture:  profile.Picture,
 		Bio:      profile.Bio,
 		Location: profile.Location,
@@ -185,6 +331,20 @@ func (s *UserService) UpdateUser(ctx context.Context, id string, req *dto.Update
 	if req.Website != nil {
 		user.Website = *req.Website
 	}
+	if req.Username != nil {
+		username := entities.NormalizeUsername(*req.Username)
+		if username != "" && username != user.Username {
+			if !entities.IsValidUsername(username) {
+				return nil, errors.ErrInvalidUsername
+			}
+			if taken, err := s.usernameTaken(ctx, username, user.ID); err != nil {
+				return nil, errors.ErrUserUpdateFailed
+			} else if taken {
+				return nil, errors.ErrUsernameTaken
+			}
+		}
+		user.Username = username
+	}
 
 	// Validate and sanitize
 	user.Sanitize()
@@ -195,6 +355,9 @@ func (s *UserService) UpdateUser(ctx context.Context, id string, req *dto.Update
 
 	// Update in database
 	if err := s.userRepo.Update(ctx, user); err != nil {
+		if stdErrors.Is(err, repositories.ErrUsernameTaken) {
+			return nil, errors.ErrUsernameTaken
+		}
 		s.logger.Error(fmt.Sprintf("Failed to update user: %v", err))
 		return nil, errors.ErrUserUpdateFailed
 	}
@@ -202,19 +365,200 @@ func (s *UserService) UpdateUser(ctx context.Context, id string, req *dto.Update
 	s.logger.Info(fmt.Sprintf("User updated successfully: %s", user.ID))
 
 	return &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Picture:   user.Picture,
-		Bio:       user.Bio,
-		Location:  user.Location,
-		Website:   user.Website,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		Username:        user.Username,
+		Picture:         user.Picture,
+		Bio:             user.Bio,
+		Location:        user.Location,
+		Website:         user.Website,
+		Role:            user.Role,
+		EmailVerified:   user.EmailVerified,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PendingEmail:    user.PendingEmail,
+		LastSeenAt:      user.LastSeenAt,
+		Status:          user.Status,
+		IsActive:        user.IsActive,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
 	}, nil
 }
 
+// VerifyEmail marks id's email verified; repeating it is a no-op.
+func (s *UserService) VerifyEmail(ctx context.Context, id string) (*dto.UserResponse, error) {
+	s.logger.Info(fmt.Sprintf("Verifying email for user: %s", id))
+
+	if err := s.userRepo.MarkEmailVerified(ctx, id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.ErrUserNotFound
+		}
+		s.logger.Error(fmt.Sprintf("Failed to verify email: %v", err))
+		return nil, errors.ErrUserUpdateFailed
+	}
+
+	return s.GetUser(ctx, id)
+}
+
+// emailChangeTTL is how long an email change token stays valid.
+const emailChangeTTL = 24 * time.Hour
+
+// emailChangeTokenBytes is the entropy of an email change token before
+// encoding.
+const emailChangeTokenBytes = 32
+
+// ChangeEmail starts moving id to a new address: the address is stored as
+// pending and a token is sent to it. The current email stays in use until
+// VerifyEmailChange confirms the token. A new request replaces an earlier one.
+// Without a sender nothing is stored and ErrNoEmailSender is returned.
+func (s *UserService) ChangeEmail(ctx context.Context, id string, req *dto.ChangeEmailRequest) (*dto.ChangeEmailResponse, error) {
+	if s.emailSender == nil {
+		return nil, errors.ErrNoEmailSender
+	}
+	email := entities.NormalizeEmail(req.Email)
+	if !entities.IsValidEmail(email) {
+		return nil, errors.ErrInvalidEmail
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("User not found for email change: %s", id))
+		return nil, errors.ErrUserNotFound
+	}
+	if email == user.Email {
+		return nil, errors.ErrEmailUnchanged
+	}
+
+	// Confirming re-checks through the unique constraint; this gives the
+	// common case a clean error before a token is sent.
+	if existing, err := s.userRepo.GetByEmail(ctx, email); err == nil && existing != nil {
+		return nil, errors.ErrEmailTaken
+	}
+	if deactivated, err := s.userRepo.GetDeactivatedByEmail(ctx, email); err == nil && deactivated != nil {
+		return nil, errors.ErrEmailTaken
+	}
+
+	raw := make([]byte, emailChangeTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to generate email change token: %v", err))
+		return nil, errors.ErrEmailChangeFailed
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	expiresAt := time.Now().Add(emailChangeTTL).UTC()
+
+	if err := s.userRepo.SetPendingEmail(ctx, id, email, hashEmailChangeToken(token), expiresAt); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.ErrUserNotFound
+		}
+		s.logger.Error(fmt.Sprintf("Failed to store pending email: %v", err))
+		return nil, errors.ErrEmailChangeFailed
+	}
+
+	if err := s.emailSender.SendEmailChangeToken(ctx, id, email, token); err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to send email change token: %v", err))
+		return nil, errors.ErrEmailChangeFailed
+	}
+
+	s.logger.Info(fmt.Sprintf("Email change requested for user: %s", id))
+	return &dto.ChangeEmailResponse{PendingEmail: email, ExpiresAt: expiresAt}, nil
+}
+
+// VerifyEmailChange makes id's pending email its email, verified, when token
+// matches the latest unexpired change request.
+func (s *UserService) VerifyEmailChange(ctx context.Context, id string, req *dto.VerifyEmailChangeRequest) (*dto.UserResponse, error) {
+	if strings.TrimSpace(req.Token) == "" {
+		return nil, errors.ErrEmailChangeInvalid
+	}
+
+	if err := s.userRepo.ConfirmPendingEmail(ctx, id, hashEmailChangeToken(req.Token), time.Now()); err != nil {
+		switch {
+		case stdErrors.Is(err, repositories.ErrEmailChangeNotFound):
+			return nil, errors.ErrEmailChangeInvalid
+		case stdErrors.Is(err, repositories.ErrEmailTaken):
+			return nil, errors.ErrEmailTaken
+		}
+		s.logger.Error(fmt.Sprintf("Failed to confirm email change: %v", err))
+		return nil, errors.ErrEmailChangeFailed
+	}
+
+	s.logger.Info(fmt.Sprintf("Email changed for user: %s", id))
+	return s.GetOwnUser(ctx, id)
+}
+
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetUserByEmailForLogin is GetUserByEmail for sign-in flows: an account its
+// owner deactivated is reactivated and returned instead of reported missing.
+// One an admin deactivated stays deactivated.
+func (s *UserService) GetUserByEmailForLogin(ctx context.Context, email string) (*dto.UserResponse, error) {
+	resp, err := s.GetUserByEmail(ctx, email)
+	if err != errors.ErrUserNotFound {
+		return resp, err
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	user, lookupErr := s.userRepo.GetDeactivatedByEmail(ctx, email)
+	if lookupErr != nil {
+		return nil, errors.ErrUserNotFound
+	}
+	if user.DeactivatedBy != user.ID {
+		s.logger.Warn(fmt.Sprintf("Sign-in refused for user deactivated by an admin: %s", user.ID))
+		return nil, errors.ErrAccountDeactivated
+	}
+
+	s.logger.Info(fmt.Sprintf("Reactivating user on login: %s", user.ID))
+	return s.ReactivateUser(ctx, user.ID)
+}
+
+// DeactivateUser hides id from reads until it is reactivated. Unlike
+// DeleteUser it is reversible. actorID is who asked: sign-in reactivates the
+// account only when that was its owner.
+func (s *UserService) DeactivateUser(ctx context.Context, id, actorID string) error {
+	s.logger.Info(fmt.Sprintf("Deactivating user: %s by %s", id, actorID))
+
+	if err := s.userRepo.Deactivate(ctx, id, actorID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.ErrUserNotFound
+		}
+		s.logger.Error(fmt.Sprintf("Failed to deactivate user: %v", err))
+		return errors.ErrUserUpdateFailed
+	}
+
+	return nil
+}
+
+// ReactivateUser restores a deactivated account; repeating it is a no-op.
+// Deleted accounts stay deleted.
+func (s *UserService) ReactivateUser(ctx context.Context, id string) (*dto.UserResponse, error) {
+	s.logger.Info(fmt.Sprintf("Reactivating user: %s", id))
+
+	if err := s.userRepo.Reactivate(ctx, id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.ErrUserNotFound
+		}
+		s.logger.Error(fmt.Sprintf("Failed to reactivate user: %v", err))
+		return nil, errors.ErrUserUpdateFailed
+	}
+
+	return s.GetUser(ctx, id)
+}
+
+// TouchLastSeen records that id was active just now. Callers are expected to
+// throttle; every call writes.
+func (s *UserService) TouchLastSeen(ctx context.Context, id string) error {
+	if err := s.userRepo.UpdateLastSeen(ctx, id, time.Now()); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.ErrUserNotFound
+		}
+		s.logger.Error(fmt.Sprintf("Failed to update last seen: %v", err))
+		return errors.ErrUserUpdateFailed
+	}
+	return nil
+}
+
 func (s *UserService) DeleteUser(ctx context.Context, id string) error {
 	s.logger.Info(fmt.Sprintf("Deleting user: %s", id))
 
@@ -228,38 +572,96 @@ func (s *UserService) DeleteUser(ctx context.Context, id string) error {
 }
 
 func (s *UserService) ListUsers(ctx context.Context, req *dto.ListUsersRequest) (*dto.ListUsersResponse, error) {
-	s.logger.Info(fmt.Sprintf("Listing users: limit=%d, offset=%d", req.Limit, req.Offset))
+	s.logger.Info(fmt.Sprintf("Listing users: limit=%d, offset=%d, include_inactive=%t, sort=%s, order=%s", req.Limit, req.Offset, req.IncludeInactive, req.Sort, req.Order))
+
+	// The gRPC path does not run the HTTP validator, so the options are
+	// checked here as well.
+	opts, err := userListOptions(req)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Invalid list users options: %v", err))
+		return nil, errors.ErrInvalidRequest
+	}
+
+	list, count := s.userRepo.List, s.userRepo.Count
+	if req.IncludeInactive {
+		list, count = s.userRepo.ListAll, s.userRepo.CountAll
+	}
 
-	users, err := s.userRepo.List(ctx, req.Limit, req.Offset)
+	users, err := list(ctx, opts, req.Limit, req.Offset)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed to list users: %v", err))
 		return nil, errors.ErrUserListFailed
 	}
 
+	total, err := count(ctx, opts)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to count users: %v", err))
+		return nil, errors.ErrUserListFailed
+	}
+
 	var userResponses []*dto.UserResponse
 	for _, user := range users {
 		userResponses = append(userResponses, &dto.UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			Picture:   user.Picture,
-			Bio:       user.Bio,
-			Location:  user.Location,
-			Website:   user.Website,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
+			ID:              user.ID,
+			Email:           user.Email,
+			Name:            user.Name,
+			Username:        user.Username,
+			Picture:         user.Picture,
+			Bio:             user.Bio,
+			Location:        user.Location,
+			Website:         user.Website,
+			Role:            user.Role,
+			EmailVerified:   user.EmailVerified,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			LastSeenAt:      user.LastSeenAt,
+			Status:          user.Status,
+			IsActive:        user.IsActive,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
 		})
 	}
 
 	return &dto.ListUsersResponse{
-		Users:  userResponses,
-		Limit:  req.Limit,
-		Offset: req.Offset,
-		Total:  len(userResponses),
+		Users:   userResponses,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		Total:   int(total),
+		HasMore: int64(req.Offset+len(userResponses)) < total,
 	}, nil
 }
 
+// userListOptions resolves the sort, order and created-date bounds of req,
+// defaulting to newest first.
+func userListOptions(req *dto.ListUsersRequest) (repositories.UserListOptions, error) {
+	opts := repositories.UserListOptions{
+		Sort:          req.Sort,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+	}
+	switch opts.Sort {
+	case "":
+		opts.Sort = repositories.UserSortCreatedAt
+	case repositories.UserSortCreatedAt, repositories.UserSortName, repositories.UserSortEmail:
+	default:
+		return opts, fmt.Errorf("unsupported sort %q", req.Sort)
+	}
+
+	switch req.Order {
+	case "":
+		opts.Descending = opts.Sort == repositories.UserSortCreatedAt
+	case "asc":
+	case "desc":
+		opts.Descending = true
+	default:
+		return opts, fmt.Errorf("unsupported order %q", req.Order)
+	}
+
+	if opts.CreatedAfter != nil && opts.CreatedBefore != nil && !opts.CreatedAfter.Before(*opts.CreatedBefore) {
+		return opts, fmt.Errorf("created_after must be before created_before")
+	}
+	return opts, nil
+}
+
 func (s *UserService) SearchUsers(ctx context.Context, req *dto.SearchUsersRequest) (*dto.ListUsersResponse, error) {
 	s.logger.Info(fmt.Sprintf("Searching users: query=%s, limit=%d, offset=%d", req.Query, req.Limit, req.Offset))
 
@@ -269,30 +671,59 @@ func (s *UserService) SearchUsers(ctx context.Context, req *dto.SearchUsersReque
 		return nil, errors.ErrUserSearchFailed
 	}
 
-	var userResponses []*dto.UserResponse
+	total, err := s.userRepo.CountSearch(ctx, req.Query)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to count search results: %v", err))
+		return nil, errors.ErrUserSearchFailed
+	}
+
+	// No matches is not an error: return an empty, non-nil page.
+	userResponses := make([]*dto.UserResponse, 0, len(users))
 	for _, user := range users {
 		userResponses = append(userResponses, &dto.UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			Picture:   user.Picture,
-			Bio:       user.Bio,
-			Location:  user.Location,
-			Website:   user.Website,
-			IsActive:  user.IsActive,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
+			ID:              user.ID,
+			Email:           user.Email,
+			Name:            user.Name,
+			Username:        user.Username,
+			Picture:         user.Picture,
+			Bio:             user.Bio,
+			Location:        user.Location,
+			Website:         user.Website,
+			Role:            user.Role,
+			EmailVerified:   user.EmailVerified,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			LastSeenAt:      user.LastSeenAt,
+			Status:          user.Status,
+			IsActive:        user.IsActive,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
 		})
 	}
 
 	return &dto.ListUsersResponse{
-		Users:  userResponses,
-		Limit:  req.Limit,
-		Offset: req.Offset,
-		Total:  len(userResponses),
+		Users:   userResponses,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		Total:   int(total),
+		HasMore: int64(req.Offset+len(userResponses)) < total,
 	}, nil
 }
 
+// usernameTaken reports whether username belongs to a user other than selfID.
+// The unique index still arbitrates races; this check gives the common case a
+// clean error before the insert.
+func (s *UserService) usernameTaken(ctx context.Context, username, selfID string) (bool, error) {
+	existing, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		s.logger.Error(fmt.Sprintf("Failed to check username: %v", err))
+		return false, err
+	}
+	return existing != nil && existing.ID != selfID, nil
+}
+
 func (s *UserService) GetStats(ctx context.Context) (*dto.UserStatsResponse, error) {
 	s.logger.Info("Getting user statistics")
 
@@ -330,6 +761,7 @@ func (s *UserService) ValidateCredentials(ctx context.Context, email, password s
 		Email:   user.Email,
 		Name:    user.Name,
 		Picture: user.Picture,
+		Role:    user.Role,
 	}, nil
 }
 
@@ -340,6 +772,11 @@ func (s *UserService) Follow(ctx context.Context, followerID, followeeID string)
 	if _, err := s.userRepo.GetByID(ctx, followeeID); err != nil {
 		return errors.ErrUserNotFound
 	}
+	if blocked, err := s.IsBlocked(ctx, followeeID, followerID); err != nil {
+		return err
+	} else if blocked {
+		return errors.ErrFollowBlocked
+	}
 	if err := s.followRepo.Create(ctx, followerID, followeeID); err != nil {
 		s.logger.Error(fmt.Sprintf("Follow create: %v", err))
 		return errors.ErrUserUpdateFailed
@@ -365,8 +802,8 @@ func (s *UserService) GetFollowers(ctx context.Context, userID string, limit int
 	for _, u := range users {
 		out = append(out, &dto.UserProfileResponse{
 			ID:       u.ID,
-			Email:    u.Email,
 			Name:     u.Name,
+			Username: u.Username,
 			Picture:  u.Picture,
 			Bio:      u.Bio,
 			Location: u.Location,
@@ -376,6 +813,20 @@ func (s *UserService) GetFollowers(ctx context.Context, userID string, limit int
 	return out, nextCursor, nil
 }
 
+// GetFollowerIDs pages through userID's follower IDs for internal callers
+// that notify followers.
+func (s *UserService) GetFollowerIDs(ctx context.Context, userID string, limit int, cursor string) (*dto.FollowerIDsResponse, error) {
+	ids, nextCursor, err := s.followRepo.GetFollowerIDs(ctx, userID, limit, cursor)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("GetFollowerIDs: %v", err))
+		return nil, errors.ErrUserListFailed
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	return &dto.FollowerIDsResponse{FollowerIDs: ids, NextCursor: nextCursor}, nil
+}
+
 func (s *UserService) GetFollowing(ctx context.Context, userID string, limit int, cursor string) ([]*dto.UserProfileResponse, string, error) {
 	users, nextCursor, err := s.followRepo.GetFollowing(ctx, userID, limit, cursor)
 	if err != nil {
@@ -386,8 +837,94 @@ func (s *UserService) GetFollowing(ctx context.Context, userID string, limit int
 	for _, u := range users {
 		out = append(out, &dto.UserProfileResponse{
 			ID:       u.ID,
-			Email:    u.Email,
 			Name:     u.Name,
+			Username: u.Username,
+			Picture:  u.Picture,
+			Bio:      u.Bio,
+			Location: u.Location,
+			Website:  u.Website,
+		})
+	}
+	return out, nextCursor, nil
+}
+
+func (s *UserService) IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error) {
+	following, err := s.followRepo.Exists(ctx, followerID, followeeID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("IsFollowing: %v", err))
+		return false, errors.ErrUserListFailed
+	}
+	return following, nil
+}
+
+func (s *UserService) CountFollowers(ctx context.Context, userID string) (int64, error) {
+	count, err := s.followRepo.CountFollowers(ctx, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("CountFollowers: %v", err))
+		return 0, errors.ErrUserListFailed
+	}
+	return count, nil
+}
+
+func (s *UserService) CountFollowing(ctx context.Context, userID string) (int64, error) {
+	count, err := s.followRepo.CountFollowing(ctx, userID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("CountFollowing: %v", err))
+		return 0, errors.ErrUserListFailed
+	}
+	return count, nil
+}
+
+// Block stops blockedID from following blockerID and keeps blockerID out of
+// blockedID's follower fan-out. Blocking twice is a conflict.
+func (s *UserService) Block(ctx context.Context, blockerID, blockedID string) error {
+	if blockerID == blockedID {
+		return errors.ErrCannotBlockSelf
+	}
+	if _, err := s.userRepo.GetByID(ctx, blockedID); err != nil {
+		return errors.ErrUserNotFound
+	}
+	created, err := s.blockRepo.Block(ctx, blockerID, blockedID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Block create: %v", err))
+		return errors.ErrUserUpdateFailed
+	}
+	if !created {
+		return errors.ErrAlreadyBlocked
+	}
+	return nil
+}
+
+// Unblock lifts a block; unblocking a user who is not blocked is a no-op.
+func (s *UserService) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	if err := s.blockRepo.Unblock(ctx, blockerID, blockedID); err != nil {
+		s.logger.Error(fmt.Sprintf("Unblock: %v", err))
+		return errors.ErrUserUpdateFailed
+	}
+	return nil
+}
+
+func (s *UserService) IsBlocked(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	blocked, err := s.blockRepo.IsBlocked(ctx, blockerID, blockedID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("IsBlocked: %v", err))
+		return false, errors.ErrUserListFailed
+	}
+	return blocked, nil
+}
+
+func (s *UserService) ListBlocked(ctx context.Context, blockerID string, limit int, cursor string) ([]*dto.UserProfileResponse, string, error) {
+	users, nextCursor, err := s.blockRepo.ListBlocked(ctx, blockerID, limit, cursor)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("ListBlocked: %v", err))
+		return nil, "", errors.ErrUserListFailed
+	}
+	out := make([]*dto.UserProfileResponse, 0, len(users))
+	for _, u := range users {
+		out = append(out, &dto.UserProfileResponse{
+			ID:       u.ID,
+			Name:     u.Name,
+			Username: u.Username,
 			Picture:  u.Picture,
 			Bio:      u.Bio,
 			Location: u.Location,
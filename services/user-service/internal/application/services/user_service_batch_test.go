@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apperrors "user-service/internal/application/errors"
+	"user-service/internal/domain/entities"
+	"user-service/pkg/logger"
+)
+
+func TestGetUsersBatch_DeduplicatesAndOmitsUnknown(t *testing.T) {
+	userRepo := &mockUserRepo{users: []*entities.User{
+		{ID: "u1", Email: "a@example.com", Name: "Alice"},
+		{ID: "u2", Email: "b@example.com", Name: "Bob"},
+	}}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	users, err := svc.GetUsersBatch(context.Background(), []string{"u1", "u2", "u1", "", "missing"})
+	if err != nil {
+		t.Fatalf("GetUsersBatch: %v", err)
+	}
+	if len(userRepo.batchIDs) != 3 {
+		t.Errorf("expected 3 distinct ids queried, got %v", userRepo.batchIDs)
+	}
+	if len(users) != 2 || users["u1"].Name != "Alice" || users["u2"].Name != "Bob" {
+		t.Fatalf("unexpected batch: %+v", users)
+	}
+	if _, ok := users["missing"]; ok {
+		t.Error("expected unknown ids to be omitted")
+	}
+}
+
+func TestGetUsersBatch_CapsDistinctIDs(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	ids := make([]string, 0, MaxUsersBatch+1)
+	for i := 0; i <= MaxUsersBatch; i++ {
+		ids = append(ids, fmt.Sprintf("user-%d", i))
+	}
+	if _, err := svc.GetUsersBatch(context.Background(), ids); err != apperrors.ErrBatchTooLarge {
+		t.Fatalf("expected ErrBatchTooLarge, got %v", err)
+	}
+
+	// Duplicates do not count toward the cap.
+	dups := append(ids[:MaxUsersBatch:MaxUsersBatch], ids[0], ids[1])
+	if _, err := svc.GetUsersBatch(context.Background(), dups); err != nil {
+		t.Fatalf("expected %d distinct ids to be accepted, got %v", MaxUsersBatch, err)
+	}
+}
+
+func TestGetUsersBatch_EmptySkipsQuery(t *testing.T) {
+	userRepo := &mockUserRepo{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	users, err := svc.GetUsersBatch(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("GetUsersBatch: %v", err)
+	}
+	if users == nil || len(users) != 0 || userRepo.batchIDs != nil {
+		t.Errorf("expected an empty map without a query, got %v (queried %v)", users, userRepo.batchIDs)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	apperrors "user-service/internal/application/errors"
+	"user-service/internal/domain/entities"
+	"user-service/pkg/logger"
+)
+
+// mockBlockRepo keeps blocks as "blocker:blocked" keys.
+type mockBlockRepo struct {
+	blocks map[string]bool
+}
+
+func (m *mockBlockRepo) Block(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	if m.blocks == nil {
+		m.blocks = make(map[string]bool)
+	}
+	key := blockerID + ":" + blockedID
+	if m.blocks[key] {
+		return false, nil
+	}
+	m.blocks[key] = true
+	return true, nil
+}
+func (m *mockBlockRepo) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	delete(m.blocks, blockerID+":"+blockedID)
+	return nil
+}
+func (m *mockBlockRepo) IsBlocked(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	return m.blocks[blockerID+":"+blockedID], nil
+}
+func (m *mockBlockRepo) ListBlocked(ctx context.Context, blockerID string, limit int, cursor string) ([]*entities.User, string, error) {
+	return nil, "", nil
+}
+
+func existingUsers() *mockUserRepo {
+	return &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Name: id}, nil
+		},
+	}
+}
+
+func TestBlock_RejectsSelfAndDuplicateBlocks(t *testing.T) {
+	blockRepo := &mockBlockRepo{}
+	svc := NewUserService(existingUsers(), &mockFollowRepo{}, blockRepo, nil, logger.New("info"))
+	ctx := context.Background()
+
+	if err := svc.Block(ctx, "alice", "alice"); err != apperrors.ErrCannotBlockSelf {
+		t.Fatalf("expected ErrCannotBlockSelf, got %v", err)
+	}
+	if err := svc.Block(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if err := svc.Block(ctx, "alice", "bob"); err != apperrors.ErrAlreadyBlocked {
+		t.Fatalf("expected ErrAlreadyBlocked, got %v", err)
+	}
+
+	if err := svc.Unblock(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("Unblock: %v", err)
+	}
+	if blocked, _ := svc.IsBlocked(ctx, "alice", "bob"); blocked {
+		t.Fatal("expected bob to be unblocked")
+	}
+	if err := svc.Unblock(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("expected a repeated unblock to be a no-op, got %v", err)
+	}
+}
+
+func TestFollow_BlockedUserCannotFollowBlocker(t *testing.T) {
+	blockRepo := &mockBlockRepo{}
+	svc := NewUserService(existingUsers(), &mockFollowRepo{}, blockRepo, nil, logger.New("info"))
+	ctx := context.Background()
+
+	if err := svc.Block(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if err := svc.Follow(ctx, "bob", "alice"); err != apperrors.ErrFollowBlocked {
+		t.Fatalf("expected ErrFollowBlocked, got %v", err)
+	}
+	// The block is one-way.
+	if err := svc.Follow(ctx, "alice", "bob"); err != nil {
+		t.Fatalf("expected the blocker to still be able to follow, got %v", err)
+	}
+}
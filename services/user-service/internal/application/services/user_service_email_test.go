@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"user-service/internal/application/dto"
+	apperrors "user-service/internal/application/errors"
+	"user-service/internal/domain/entities"
+	"user-service/internal/domain/repositories"
+	"user-service/pkg/logger"
+)
+
+func TestGetUserByEmail_NormalizesBeforeLookup(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByEmail: func(ctx context.Context, email string) (*entities.User, error) {
+			if email != "jane@example.com" {
+				t.Errorf("expected normalized lookup, got %q", email)
+			}
+			return &entities.User{ID: "user-1", Email: email, Name: "Jane", IsActive: true}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.GetUserByEmail(context.Background(), "  Jane@Example.COM ")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if resp.ID != "user-1" || resp.Email != "jane@example.com" {
+		t.Errorf("unexpected user: %+v", resp)
+	}
+}
+
+func TestGetUserByEmail_MissingIsNotFound(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	for _, email := range []string{"", "   ", "nobody@example.com"} {
+		if _, err := svc.GetUserByEmail(context.Background(), email); err != apperrors.ErrUserNotFound {
+			t.Errorf("email %q: expected ErrUserNotFound, got %v", email, err)
+		}
+	}
+}
+
+func TestCreateUser_ProviderVerifiedEmail(t *testing.T) {
+	userRepo := &mockUserRepo{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{
+		ID: "google-1", Email: "jane@example.com", Name: "Jane", EmailVerified: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if !resp.EmailVerified || resp.EmailVerifiedAt == nil {
+		t.Errorf("expected a verified email with a timestamp, got %+v", resp)
+	}
+}
+
+func TestCreateUser_DefaultsToUnverifiedEmail(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{
+		Email: "jane@example.com", Name: "Jane", Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if resp.EmailVerified || resp.EmailVerifiedAt != nil {
+		t.Errorf("expected an unverified email, got %+v", resp)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	verifiedAt := time.Now()
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Email: "jane@example.com", EmailVerified: true, EmailVerifiedAt: &verifiedAt}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.VerifyEmail(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if len(userRepo.verified) != 1 || userRepo.verified[0] != "user-1" {
+		t.Errorf("expected user-1 to be marked verified, got %v", userRepo.verified)
+	}
+	if !resp.EmailVerified {
+		t.Errorf("expected the returned user to be verified, got %+v", resp)
+	}
+}
+
+func TestVerifyEmail_UnknownUser(t *testing.T) {
+	userRepo := &mockUserRepo{verifyErr: errors.New("user not found")}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if _, err := svc.VerifyEmail(context.Background(), "missing"); err != apperrors.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+// recordingEmailSender keeps the last token sent to each address.
+type recordingEmailSender struct {
+	tokens map[string]string
+}
+
+func (s *recordingEmailSender) SendEmailChangeToken(ctx context.Context, userID, email, token string) error {
+	if s.tokens == nil {
+		s.tokens = make(map[string]string)
+	}
+	s.tokens[email] = token
+	return nil
+}
+
+func TestChangeEmail_KeepsCurrentEmailUntilVerified(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Email: "jane@example.com", EmailVerified: true, IsActive: true}, nil
+		},
+	}
+	sender := &recordingEmailSender{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, sender, logger.New("info"))
+	ctx := context.Background()
+
+	resp, err := svc.ChangeEmail(ctx, "user-1", &dto.ChangeEmailRequest{Email: " Jane.New@Example.com "})
+	if err != nil {
+		t.Fatalf("ChangeEmail: %v", err)
+	}
+	if resp.PendingEmail != "jane.new@example.com" || !resp.ExpiresAt.After(time.Now()) {
+		t.Fatalf("unexpected pending change: %+v", resp)
+	}
+	token := sender.tokens["jane.new@example.com"]
+	if token == "" {
+		t.Fatal("expected the token to be sent to the new address")
+	}
+	if change := userRepo.pending["user-1"]; change.email != "jane.new@example.com" || change.tokenHash == token {
+		t.Fatalf("expected the pending email stored with a hashed token, got %+v", change)
+	}
+	if len(userRepo.updated) != 0 || len(userRepo.verified) != 0 {
+		t.Fatal("expected the current email to stay in place until verified")
+	}
+
+	if _, err := svc.VerifyEmailChange(ctx, "user-1", &dto.VerifyEmailChangeRequest{Token: "wrong"}); err != apperrors.ErrEmailChangeInvalid {
+		t.Fatalf("expected ErrEmailChangeInvalid for a wrong token, got %v", err)
+	}
+	if _, err := svc.VerifyEmailChange(ctx, "user-1", &dto.VerifyEmailChangeRequest{Token: token}); err != nil {
+		t.Fatalf("VerifyEmailChange: %v", err)
+	}
+	if len(userRepo.verified) != 1 || len(userRepo.pending) != 0 {
+		t.Fatalf("expected the pending email to be promoted, got pending %+v", userRepo.pending)
+	}
+	if _, err := svc.VerifyEmailChange(ctx, "user-1", &dto.VerifyEmailChangeRequest{Token: token}); err != apperrors.ErrEmailChangeInvalid {
+		t.Fatalf("expected a used token to be rejected, got %v", err)
+	}
+}
+
+func TestChangeEmail_RejectsInvalidOrTakenAddresses(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Email: "jane@example.com", IsActive: true}, nil
+		},
+		getByEmail: func(ctx context.Context, email string) (*entities.User, error) {
+			if email == "taken@example.com" {
+				return &entities.User{ID: "user-2", Email: email}, nil
+			}
+			return nil, errors.New("user not found")
+		},
+		deactivated: []*entities.User{{ID: "user-3", Email: "resting@example.com"}},
+	}
+	sender := &recordingEmailSender{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, sender, logger.New("info"))
+
+	cases := map[string]error{
+		"not-an-email":        apperrors.ErrInvalidEmail,
+		"JANE@example.com":    apperrors.ErrEmailUnchanged,
+		"taken@example.com":   apperrors.ErrEmailTaken,
+		"resting@example.com": apperrors.ErrEmailTaken,
+	}
+	for email, want := range cases {
+		if _, err := svc.ChangeEmail(context.Background(), "user-1", &dto.ChangeEmailRequest{Email: email}); err != want {
+			t.Errorf("%s: expected %v, got %v", email, want, err)
+		}
+	}
+	if len(userRepo.pending) != 0 || len(sender.tokens) != 0 {
+		t.Fatal("expected no change to be stored or sent")
+	}
+}
+
+func TestPendingEmail_OnlyShownToOwner(t *testing.T) {
+	pendingUser := &entities.User{ID: "user-1", Email: "jane@example.com", PendingEmail: "jane.new@example.com", IsActive: true}
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return pendingUser, nil
+		},
+		getByEmail: func(ctx context.Context, email string) (*entities.User, error) {
+			return pendingUser, nil
+		},
+		users: []*entities.User{pendingUser},
+		total: 1,
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+	ctx := context.Background()
+
+	own, err := svc.GetOwnUser(ctx, "user-1")
+	if err != nil || own.PendingEmail != "jane.new@example.com" {
+		t.Fatalf("expected the owner to see the pending email, got %+v (%v)", own, err)
+	}
+
+	other, err := svc.GetUser(ctx, "user-1")
+	if err != nil || other.PendingEmail != "" {
+		t.Fatalf("expected GetUser to leave out the pending email, got %+v (%v)", other, err)
+	}
+	byEmail, err := svc.GetUserByEmail(ctx, "jane@example.com")
+	if err != nil || byEmail.PendingEmail != "" {
+		t.Fatalf("expected GetUserByEmail to leave out the pending email, got %+v (%v)", byEmail, err)
+	}
+	listed, err := svc.ListUsers(ctx, &dto.ListUsersRequest{Limit: 20})
+	if err != nil || len(listed.Users) != 1 || listed.Users[0].PendingEmail != "" {
+		t.Fatalf("expected ListUsers to leave out the pending email, got %+v (%v)", listed, err)
+	}
+	found, err := svc.SearchUsers(ctx, &dto.SearchUsersRequest{Query: "jane", Limit: 20})
+	if err != nil || len(found.Users) != 1 || found.Users[0].PendingEmail != "" {
+		t.Fatalf("expected SearchUsers to leave out the pending email, got %+v (%v)", found, err)
+	}
+}
+
+func TestChangeEmail_NoSenderStoresNothing(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Email: "jane@example.com", IsActive: true}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if _, err := svc.ChangeEmail(context.Background(), "user-1", &dto.ChangeEmailRequest{Email: "jane.new@example.com"}); err != apperrors.ErrNoEmailSender {
+		t.Fatalf("expected ErrNoEmailSender, got %v", err)
+	}
+	if len(userRepo.pending) != 0 {
+		t.Fatalf("expected no pending email without a sender, got %+v", userRepo.pending)
+	}
+}
+
+func TestVerifyEmailChange_AddressTakenMeanwhile(t *testing.T) {
+	userRepo := &mockUserRepo{confirmErr: repositories.ErrEmailTaken}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if _, err := svc.VerifyEmailChange(context.Background(), "user-1", &dto.VerifyEmailChangeRequest{Token: "token"}); err != apperrors.ErrEmailTaken {
+		t.Fatalf("expected ErrEmailTaken, got %v", err)
+	}
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"strconv"
 	"testing"
+	"time"
 
 	apperrors "user-service/internal/application/errors"
 	"user-service/internal/domain/entities"
@@ -12,10 +14,40 @@ import (
 )
 
 type mockUserRepo struct {
-	getByID func(ctx context.Context, id string) (*entities.User, error)
+	getByID       func(ctx context.Context, id string) (*entities.User, error)
+	getByUsername func(ctx context.Context, username string) (*entities.User, error)
+	getByEmail    func(ctx context.Context, email string) (*entities.User, error)
+	users         []*entities.User
+	total         int64
+	listedAll     bool
+	listOptions   repositories.UserListOptions
+	batchIDs      []string
+	verified      []string
+	verifyErr     error
+	seen          map[string]time.Time
+	seenErr       error
+	deactivated   []*entities.User
+	statuses      map[string]string
+	deactivatedBy map[string]string
+	statusErr     error
+	searchErr     error
+	createErr     error
+	created       []*entities.User
+	updated       []*entities.User
+	pending       map[string]pendingEmail
+	confirmErr    error
 }
 
-func (m *mockUserRepo) Create(ctx context.Context, user *entities.User) error { return nil }
+type pendingEmail struct {
+	email     string
+	tokenHash string
+	expiresAt time.Time
+}
+
+func (m *mockUserRepo) Create(ctx context.Context, user *entities.User) error {
+	m.created = append(m.created, user)
+	return m.createErr
+}
 func (m *mockUserRepo) GetByID(ctx context.Context, id string) (*entities.User, error) {
 	if m.getByID != nil {
 		return m.getByID(ctx, id)
@@ -23,15 +55,115 @@ func (m *mockUserRepo) GetByID(ctx context.Context, id string) (*entities.User,
 	return nil, errors.New("not found")
 }
 func (m *mockUserRepo) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
-	return nil, nil
+	if m.getByEmail != nil {
+		return m.getByEmail(ctx, email)
+	}
+	return nil, errors.New("user not found")
 }
-func (m *mockUserRepo) Update(ctx context.Context, user *entities.User) error { return nil }
-func (m *mockUserRepo) Delete(ctx context.Context, id string) error           { return nil }
-func (m *mockUserRepo) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
-	return nil, nil
+func (m *mockUserRepo) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
+	if m.getByUsername != nil {
+		return m.getByUsername(ctx, username)
+	}
+	return nil, errors.New("user not found")
+}
+func (m *mockUserRepo) GetByIDs(ctx context.Context, ids []string) ([]*entities.User, error) {
+	m.batchIDs = ids
+	var out []*entities.User
+	for _, u := range m.users {
+		for _, id := range ids {
+			if u.ID == id {
+				out = append(out, u)
+			}
+		}
+	}
+	return out, nil
+}
+func (m *mockUserRepo) Update(ctx context.Context, user *entities.User) error {
+	m.updated = append(m.updated, user)
+	return nil
+}
+func (m *mockUserRepo) Delete(ctx context.Context, id string) error { return nil }
+func (m *mockUserRepo) MarkEmailVerified(ctx context.Context, id string) error {
+	m.verified = append(m.verified, id)
+	return m.verifyErr
+}
+func (m *mockUserRepo) SetPendingEmail(ctx context.Context, id, email, tokenHash string, expiresAt time.Time) error {
+	if m.pending == nil {
+		m.pending = make(map[string]pendingEmail)
+	}
+	m.pending[id] = pendingEmail{email: email, tokenHash: tokenHash, expiresAt: expiresAt}
+	return nil
+}
+func (m *mockUserRepo) ConfirmPendingEmail(ctx context.Context, id, tokenHash string, now time.Time) error {
+	if m.confirmErr != nil {
+		return m.confirmErr
+	}
+	change, ok := m.pending[id]
+	if !ok || change.tokenHash != tokenHash || !change.expiresAt.After(now) {
+		return repositories.ErrEmailChangeNotFound
+	}
+	delete(m.pending, id)
+	m.verified = append(m.verified, id)
+	return nil
+}
+func (m *mockUserRepo) UpdateLastSeen(ctx context.Context, id string, seenAt time.Time) error {
+	if m.seenErr != nil {
+		return m.seenErr
+	}
+	if m.seen == nil {
+		m.seen = make(map[string]time.Time)
+	}
+	m.seen[id] = seenAt
+	return nil
+}
+func (m *mockUserRepo) GetDeactivatedByEmail(ctx context.Context, email string) (*entities.User, error) {
+	for _, u := range m.deactivated {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+func (m *mockUserRepo) Deactivate(ctx context.Context, id, actorID string) error {
+	if m.deactivatedBy == nil {
+		m.deactivatedBy = make(map[string]string)
+	}
+	m.deactivatedBy[id] = actorID
+	return m.setStatus(id, entities.StatusDeactivated)
+}
+func (m *mockUserRepo) Reactivate(ctx context.Context, id string) error {
+	return m.setStatus(id, entities.StatusActive)
+}
+func (m *mockUserRepo) setStatus(id, status string) error {
+	if m.statusErr != nil {
+		return m.statusErr
+	}
+	if m.statuses == nil {
+		m.statuses = make(map[string]string)
+	}
+	m.statuses[id] = status
+	return nil
+}
+func (m *mockUserRepo) List(ctx context.Context, opts repositories.UserListOptions, limit, offset int) ([]*entities.User, error) {
+	m.listOptions = opts
+	return m.users, nil
 }
 func (m *mockUserRepo) Search(ctx context.Context, query string, limit, offset int) ([]*entities.User, error) {
-	return nil, nil
+	return m.users, m.searchErr
+}
+func (m *mockUserRepo) ListAll(ctx context.Context, opts repositories.UserListOptions, limit, offset int) ([]*entities.User, error) {
+	m.listedAll = true
+	m.listOptions = opts
+	return m.users, nil
+}
+func (m *mockUserRepo) Count(ctx context.Context, opts repositories.UserListOptions) (int64, error) {
+	return m.total, nil
+}
+func (m *mockUserRepo) CountAll(ctx context.Context, opts repositories.UserListOptions) (int64, error) {
+	return m.total, nil
+}
+func (m *mockUserRepo) CountSearch(ctx context.Context, query string) (int64, error) {
+	return m.total, nil
 }
 func (m *mockUserRepo) Exists(ctx context.Context, id string) (bool, error)    { return false, nil }
 func (m *mockUserRepo) GetActiveUsersCount(ctx context.Context) (int64, error) { return 0, nil }
@@ -39,6 +171,12 @@ func (m *mockUserRepo) GetActiveUsersCount(ctx context.Context) (int64, error) {
 type mockFollowRepo struct {
 	createErr error
 	deleteErr error
+	followers int64
+	following int64
+	countErr  error
+	exists    bool
+	// followerIDs is paged by GetFollowerIDs with an index cursor.
+	followerIDs []string
 }
 
 func (m *mockFollowRepo) Create(ctx context.Context, followerID, followeeID string) error {
@@ -48,11 +186,22 @@ func (m *mockFollowRepo) Delete(ctx context.Context, followerID, followeeID stri
 	return m.deleteErr
 }
 func (m *mockFollowRepo) Exists(ctx context.Context, followerID, followeeID string) (bool, error) {
-	return false, nil
+	return m.exists, nil
 }
 func (m *mockFollowRepo) GetFollowers(ctx context.Context, userID string, limit int, cursor string) ([]*entities.User, string, error) {
 	return nil, "", nil
 }
+func (m *mockFollowRepo) GetFollowerIDs(ctx context.Context, userID string, limit int, cursor string) ([]string, string, error) {
+	start, _ := strconv.Atoi(cursor)
+	if start > len(m.followerIDs) {
+		start = len(m.followerIDs)
+	}
+	end := start + limit
+	if end >= len(m.followerIDs) {
+		return m.followerIDs[start:], "", nil
+	}
+	return m.followerIDs[start:end], strconv.Itoa(end), nil
+}
 func (m *mockFollowRepo) GetFollowing(ctx context.Context, userID string, limit int, cursor string) ([]*entities.User, string, error) {
 	return nil, "", nil
 }
@@ -60,8 +209,15 @@ func (m *mockFollowRepo) AreFollowed(ctx context.Context, followerID string, fol
 	return nil, nil
 }
 
+func (m *mockFollowRepo) CountFollowers(ctx context.Context, userID string) (int64, error) {
+	return m.followers, m.countErr
+}
+func (m *mockFollowRepo) CountFollowing(ctx context.Context, userID string) (int64, error) {
+	return m.following, m.countErr
+}
+
 func TestFollow_CannotFollowSelf(t *testing.T) {
-	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, logger.New("info"))
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
 	ctx := context.Background()
 	err := svc.Follow(ctx, "user1", "user1")
 	if err == nil {
@@ -78,7 +234,7 @@ func TestFollow_UserNotFound(t *testing.T) {
 			return nil, errors.New("not found")
 		},
 	}
-	svc := NewUserService(userRepo, &mockFollowRepo{}, logger.New("info"))
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
 	ctx := context.Background()
 	err := svc.Follow(ctx, "follower", "nonexistent")
 	if err == nil {
@@ -95,7 +251,7 @@ func TestFollow_Success(t *testing.T) {
 			return &entities.User{ID: id, Name: "u"}, nil
 		},
 	}
-	svc := NewUserService(userRepo, &mockFollowRepo{}, logger.New("info"))
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
 	ctx := context.Background()
 	err := svc.Follow(ctx, "follower", "followee")
 	if err != nil {
@@ -111,7 +267,7 @@ func TestFollow_Idempotent(t *testing.T) {
 	}
 	// Create succeeds (e.g. ON CONFLICT DO NOTHING); second Follow also succeeds
 	followRepo := &mockFollowRepo{}
-	svc := NewUserService(userRepo, followRepo, logger.New("info"))
+	svc := NewUserService(userRepo, followRepo, &mockBlockRepo{}, nil, logger.New("info"))
 	ctx := context.Background()
 	err1 := svc.Follow(ctx, "f", "e")
 	err2 := svc.Follow(ctx, "f", "e")
@@ -124,7 +280,7 @@ func TestFollow_Idempotent(t *testing.T) {
 }
 
 func TestUnfollow_Success(t *testing.T) {
-	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, logger.New("info"))
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
 	ctx := context.Background()
 	err := svc.Unfollow(ctx, "follower", "followee")
 	if err != nil {
@@ -133,7 +289,7 @@ func TestUnfollow_Success(t *testing.T) {
 }
 
 func TestUnfollow_Idempotent(t *testing.T) {
-	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, logger.New("info"))
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
 	ctx := context.Background()
 	err1 := svc.Unfollow(ctx, "f", "e")
 	err2 := svc.Unfollow(ctx, "f", "e")
@@ -145,6 +301,66 @@ func TestUnfollow_Idempotent(t *testing.T) {
 	}
 }
 
+func TestCountFollowers(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{followers: 42}, &mockBlockRepo{}, nil, logger.New("info"))
+	count, err := svc.CountFollowers(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("CountFollowers: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("expected 42 followers, got %d", count)
+	}
+}
+
+func TestCountFollowers_RepositoryError(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{countErr: errors.New("db down")}, &mockBlockRepo{}, nil, logger.New("info"))
+	if _, err := svc.CountFollowers(context.Background(), "user1"); err != apperrors.ErrUserListFailed {
+		t.Errorf("expected ErrUserListFailed, got %v", err)
+	}
+}
+
+func TestIsFollowing(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{exists: true}, &mockBlockRepo{}, nil, logger.New("info"))
+	following, err := svc.IsFollowing(context.Background(), "f", "e")
+	if err != nil {
+		t.Fatalf("IsFollowing: %v", err)
+	}
+	if !following {
+		t.Error("expected IsFollowing to report an existing follow")
+	}
+}
+
 // Ensure mockFollowRepo implements repositories.FollowRepository
 var _ repositories.FollowRepository = (*mockFollowRepo)(nil)
 var _ repositories.UserRepository = (*mockUserRepo)(nil)
+
+func TestGetFollowerIDs_PagesThroughFollowers(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{followerIDs: []string{"u1", "u2", "u3"}}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	first, err := svc.GetFollowerIDs(context.Background(), "author", 2, "")
+	if err != nil {
+		t.Fatalf("GetFollowerIDs: %v", err)
+	}
+	if len(first.FollowerIDs) != 2 || first.NextCursor == "" {
+		t.Fatalf("expected a full first page with a cursor, got %+v", first)
+	}
+	second, err := svc.GetFollowerIDs(context.Background(), "author", 2, first.NextCursor)
+	if err != nil {
+		t.Fatalf("GetFollowerIDs: %v", err)
+	}
+	if len(second.FollowerIDs) != 1 || second.FollowerIDs[0] != "u3" || second.NextCursor != "" {
+		t.Fatalf("expected the last follower and no cursor, got %+v", second)
+	}
+}
+
+func TestGetFollowerIDs_EmptyListIsNotNil(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.GetFollowerIDs(context.Background(), "author", 500, "")
+	if err != nil {
+		t.Fatalf("GetFollowerIDs: %v", err)
+	}
+	if resp.FollowerIDs == nil {
+		t.Error("expected an empty slice so the JSON payload is [] rather than null")
+	}
+}
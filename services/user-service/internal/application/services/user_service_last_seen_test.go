@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "user-service/internal/application/errors"
+	"user-service/internal/domain/entities"
+	"user-service/pkg/logger"
+)
+
+func TestTouchLastSeen_RecordsTimestamp(t *testing.T) {
+	userRepo := &mockUserRepo{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	before := time.Now()
+	if err := svc.TouchLastSeen(context.Background(), "user-1"); err != nil {
+		t.Fatalf("TouchLastSeen: %v", err)
+	}
+	seenAt, ok := userRepo.seen["user-1"]
+	if !ok || seenAt.Before(before) {
+		t.Errorf("expected last seen recorded after %v, got %v (ok=%v)", before, seenAt, ok)
+	}
+}
+
+func TestTouchLastSeen_UnknownUser(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{seenErr: errors.New("user not found")}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if err := svc.TouchLastSeen(context.Background(), "gone"); err != apperrors.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestGetUser_ExposesLastSeen(t *testing.T) {
+	seenAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Email: "a@example.com", Name: "Alice", LastSeenAt: &seenAt, IsActive: true}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.GetUser(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if resp.LastSeenAt == nil || !resp.LastSeenAt.Equal(seenAt) {
+		t.Errorf("expected last_seen_at %v, got %v", seenAt, resp.LastSeenAt)
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"user-service/internal/domain/entities"
+	"user-service/pkg/logger"
+)
+
+func TestGetUserProfile_PayloadOmitsEmail(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Email: "secret@example.com", Name: "Alice", Username: "alice", IsActive: true}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	profile, err := svc.GetUserProfile(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	raw, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := payload["email"]; ok {
+		t.Errorf("public profile must not expose email, got %s", raw)
+	}
+	if payload["username"] != "alice" {
+		t.Errorf("expected username in profile, got %s", raw)
+	}
+}
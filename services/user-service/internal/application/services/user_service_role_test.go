@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"user-service/internal/application/dto"
+	apperrors "user-service/internal/application/errors"
+	"user-service/internal/domain/entities"
+	"user-service/internal/domain/repositories"
+	"user-service/pkg/logger"
+)
+
+func TestCreateUser_DefaultsToUserRole(t *testing.T) {
+	userRepo := &mockUserRepo{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{
+		ID: "user-1", Email: "jane@example.com", Name: "Jane",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if resp.Role != entities.RoleUser || userRepo.created[0].Role != entities.RoleUser {
+		t.Errorf("expected role %q, got response=%q stored=%q", entities.RoleUser, resp.Role, userRepo.created[0].Role)
+	}
+}
+
+func TestListUsers_IncludeInactiveUsesAdminListing(t *testing.T) {
+	userRepo := &mockUserRepo{
+		users: []*entities.User{
+			{ID: "u1", Role: entities.RoleAdmin, IsActive: true},
+			{ID: "u2", Role: entities.RoleUser, IsActive: false},
+		},
+		total: 2,
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.ListUsers(context.Background(), &dto.ListUsersRequest{Limit: 20, IncludeInactive: true})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if !userRepo.listedAll {
+		t.Error("expected the admin listing to include inactive users")
+	}
+	if len(resp.Users) != 2 || resp.Users[0].Role != entities.RoleAdmin || resp.Users[1].IsActive {
+		t.Errorf("unexpected users: %+v", resp.Users)
+	}
+}
+
+func TestListUsers_DefaultSkipsInactive(t *testing.T) {
+	userRepo := &mockUserRepo{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if _, err := svc.ListUsers(context.Background(), &dto.ListUsersRequest{Limit: 20}); err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if userRepo.listedAll {
+		t.Error("expected the public listing to exclude inactive users")
+	}
+}
+
+func TestListUsers_SortAndCreatedRange(t *testing.T) {
+	userRepo := &mockUserRepo{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+	ctx := context.Background()
+
+	if _, err := svc.ListUsers(ctx, &dto.ListUsersRequest{Limit: 20}); err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if userRepo.listOptions.Sort != repositories.UserSortCreatedAt || !userRepo.listOptions.Descending {
+		t.Errorf("expected newest first by default, got %+v", userRepo.listOptions)
+	}
+
+	if _, err := svc.ListUsers(ctx, &dto.ListUsersRequest{Limit: 20, Sort: "name"}); err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if userRepo.listOptions.Sort != repositories.UserSortName || userRepo.listOptions.Descending {
+		t.Errorf("expected names ascending by default, got %+v", userRepo.listOptions)
+	}
+
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := after.AddDate(0, 1, 0)
+	if _, err := svc.ListUsers(ctx, &dto.ListUsersRequest{Limit: 20, Sort: "email", Order: "desc", CreatedAfter: &after, CreatedBefore: &before}); err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if opts := userRepo.listOptions; !opts.Descending || !opts.CreatedAfter.Equal(after) || !opts.CreatedBefore.Equal(before) {
+		t.Errorf("expected the range and order to reach the repository, got %+v", opts)
+	}
+
+	invalid := []*dto.ListUsersRequest{
+		{Limit: 20, Sort: "password_hash"},
+		{Limit: 20, Sort: "name; DROP TABLE users"},
+		{Limit: 20, Order: "sideways"},
+		{Limit: 20, CreatedAfter: &before, CreatedBefore: &after},
+	}
+	for _, req := range invalid {
+		if _, err := svc.ListUsers(ctx, req); err != apperrors.ErrInvalidRequest {
+			t.Errorf("%+v: expected ErrInvalidRequest, got %v", req, err)
+		}
+	}
+}
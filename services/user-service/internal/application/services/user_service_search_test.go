@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"user-service/internal/application/dto"
+	apperrors "user-service/internal/application/errors"
+	"user-service/internal/domain/entities"
+	"user-service/pkg/logger"
+)
+
+func TestSearchUsers_NoMatchesReturnsEmptyPage(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.SearchUsers(context.Background(), &dto.SearchUsersRequest{Query: "nobody", Limit: 20})
+	if err != nil {
+		t.Fatalf("SearchUsers: %v", err)
+	}
+	if resp.Users == nil || len(resp.Users) != 0 || resp.Total != 0 {
+		t.Errorf("expected empty non-nil page with total 0, got users=%v total=%d", resp.Users, resp.Total)
+	}
+}
+
+func TestSearchUsers_RepositoryErrorIsPropagated(t *testing.T) {
+	userRepo := &mockUserRepo{searchErr: errors.New("connection reset")}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	_, err := svc.SearchUsers(context.Background(), &dto.SearchUsersRequest{Query: "alice", Limit: 20})
+	if err != apperrors.ErrUserSearchFailed {
+		t.Fatalf("expected ErrUserSearchFailed, got %v", err)
+	}
+}
+
+func TestSearchUsers_TotalCountsBeyondPage(t *testing.T) {
+	userRepo := &mockUserRepo{
+		users: []*entities.User{{ID: "u1"}, {ID: "u2"}},
+		total: 5,
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.SearchUsers(context.Background(), &dto.SearchUsersRequest{Query: "alice", Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchUsers: %v", err)
+	}
+	if resp.Total != 5 || !resp.HasMore {
+		t.Errorf("expected total=5 has_more=true, got total=%d has_more=%v", resp.Total, resp.HasMore)
+	}
+}
+
+func TestListUsers_TotalCountsBeyondPage(t *testing.T) {
+	userRepo := &mockUserRepo{
+		users: []*entities.User{{ID: "u1"}, {ID: "u2"}},
+		total: 5,
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.ListUsers(context.Background(), &dto.ListUsersRequest{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if len(resp.Users) != 2 || resp.Total != 5 || !resp.HasMore {
+		t.Errorf("expected 2 users of 5 with more to come, got users=%d total=%d has_more=%v", len(resp.Users), resp.Total, resp.HasMore)
+	}
+}
+
+func TestListUsers_LastPageHasNoMore(t *testing.T) {
+	userRepo := &mockUserRepo{
+		users: []*entities.User{{ID: "u5"}},
+		total: 5,
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.ListUsers(context.Background(), &dto.ListUsersRequest{Limit: 2, Offset: 4})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if resp.Total != 5 || resp.HasMore {
+		t.Errorf("expected total=5 has_more=false, got total=%d has_more=%v", resp.Total, resp.HasMore)
+	}
+}
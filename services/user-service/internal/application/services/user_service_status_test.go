@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"user-service/internal/application/dto"
+	apperrors "user-service/internal/application/errors"
+	"user-service/internal/domain/entities"
+	"user-service/pkg/logger"
+)
+
+func TestDeactivateUser_MarksDeactivated(t *testing.T) {
+	userRepo := &mockUserRepo{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if err := svc.DeactivateUser(context.Background(), "user-1", "admin-1"); err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+	if userRepo.statuses["user-1"] != entities.StatusDeactivated {
+		t.Errorf("expected user-1 deactivated, got %q", userRepo.statuses["user-1"])
+	}
+	if userRepo.deactivatedBy["user-1"] != "admin-1" {
+		t.Errorf("expected admin-1 recorded as the actor, got %q", userRepo.deactivatedBy["user-1"])
+	}
+}
+
+func TestReactivateUser_DeletedStaysDeleted(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{statusErr: errors.New("user not found")}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if _, err := svc.ReactivateUser(context.Background(), "deleted-1"); err != apperrors.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestGetUserByEmailForLogin_ReactivatesSelfDeactivatedAccount(t *testing.T) {
+	account := &entities.User{ID: "user-1", Email: "jane@example.com", Name: "Jane", Status: entities.StatusDeactivated, DeactivatedBy: "user-1"}
+	userRepo := &mockUserRepo{deactivated: []*entities.User{account}}
+	userRepo.getByID = func(ctx context.Context, id string) (*entities.User, error) {
+		if userRepo.statuses[id] != entities.StatusActive {
+			return nil, errors.New("not found")
+		}
+		return &entities.User{ID: id, Email: account.Email, Name: account.Name, Status: entities.StatusActive, IsActive: true}, nil
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if _, err := svc.GetUserByEmail(context.Background(), "jane@example.com"); err != apperrors.ErrUserNotFound {
+		t.Fatalf("expected plain lookup to hide deactivated account, got %v", err)
+	}
+
+	resp, err := svc.GetUserByEmailForLogin(context.Background(), " Jane@Example.com ")
+	if err != nil {
+		t.Fatalf("GetUserByEmailForLogin: %v", err)
+	}
+	if resp.ID != "user-1" || resp.Status != entities.StatusActive {
+		t.Errorf("expected reactivated user-1, got %+v", resp)
+	}
+}
+
+func TestGetUserByEmailForLogin_KeepsAdminDeactivationInPlace(t *testing.T) {
+	for name, actor := range map[string]string{"admin": "admin-1", "unrecorded": ""} {
+		t.Run(name, func(t *testing.T) {
+			account := &entities.User{ID: "user-1", Email: "jane@example.com", Status: entities.StatusDeactivated, DeactivatedBy: actor}
+			userRepo := &mockUserRepo{deactivated: []*entities.User{account}}
+			svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+			if _, err := svc.GetUserByEmailForLogin(context.Background(), "jane@example.com"); err != apperrors.ErrAccountDeactivated {
+				t.Fatalf("expected ErrAccountDeactivated, got %v", err)
+			}
+			if status, ok := userRepo.statuses["user-1"]; ok {
+				t.Errorf("expected the account left deactivated, got status %q", status)
+			}
+		})
+	}
+}
+
+func TestCreateUser_DeactivatedEmailIsTaken(t *testing.T) {
+	userRepo := &mockUserRepo{deactivated: []*entities.User{{ID: "user-1", Email: "jane@example.com"}}}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	_, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{Email: "jane@example.com", Name: "Jane"})
+	if err != apperrors.ErrUserAlreadyExists {
+		t.Fatalf("expected ErrUserAlreadyExists, got %v", err)
+	}
+	if len(userRepo.created) != 0 {
+		t.Error("expected no user to be created")
+	}
+}
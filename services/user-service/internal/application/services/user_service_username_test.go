@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"user-service/internal/application/dto"
+	apperrors "user-service/internal/application/errors"
+	"user-service/internal/domain/entities"
+	"user-service/internal/domain/repositories"
+	"user-service/pkg/logger"
+)
+
+func TestCreateUser_NormalizesUsername(t *testing.T) {
+	userRepo := &mockUserRepo{}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	resp, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{
+		ID: "user-1", Email: "jane@example.com", Name: "Jane", Username: "  Jane_Doe ",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if resp.Username != "jane_doe" || userRepo.created[0].Username != "jane_doe" {
+		t.Errorf("expected lowercased username, got response=%q stored=%q", resp.Username, userRepo.created[0].Username)
+	}
+}
+
+func TestCreateUser_RejectsInvalidUsername(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	for _, username := range []string{"ab", "jane-doe", "thirty_one_characters_long_name"} {
+		_, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{
+			ID: "user-1", Email: "jane@example.com", Name: "Jane", Username: username,
+		})
+		if err != apperrors.ErrInvalidUsername {
+			t.Errorf("username %q: expected ErrInvalidUsername, got %v", username, err)
+		}
+	}
+}
+
+func TestCreateUser_UsernameTaken(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByUsername: func(ctx context.Context, username string) (*entities.User, error) {
+			return &entities.User{ID: "someone-else", Username: username}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	_, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{
+		ID: "user-1", Email: "jane@example.com", Name: "Jane", Username: "jane",
+	})
+	if err != apperrors.ErrUsernameTaken {
+		t.Fatalf("expected ErrUsernameTaken, got %v", err)
+	}
+	if len(userRepo.created) != 0 {
+		t.Error("expected no insert for a taken username")
+	}
+}
+
+func TestCreateUser_UniqueIndexRaceIsUsernameTaken(t *testing.T) {
+	userRepo := &mockUserRepo{createErr: repositories.ErrUsernameTaken}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	_, err := svc.CreateUser(context.Background(), &dto.CreateUserRequest{
+		ID: "user-1", Email: "jane@example.com", Name: "Jane", Username: "jane",
+	})
+	if err != apperrors.ErrUsernameTaken {
+		t.Fatalf("expected ErrUsernameTaken, got %v", err)
+	}
+}
+
+func TestUpdateUser_KeepingOwnUsernameIsAllowed(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Email: "jane@example.com", Name: "Jane", Username: "jane"}, nil
+		},
+		getByUsername: func(ctx context.Context, username string) (*entities.User, error) {
+			return &entities.User{ID: "user-1", Username: username}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	username := "JANE"
+	resp, err := svc.UpdateUser(context.Background(), "user-1", &dto.UpdateUserRequest{Username: &username})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if resp.Username != "jane" {
+		t.Errorf("expected username jane, got %q", resp.Username)
+	}
+}
+
+func TestUpdateUser_UsernameTakenByAnotherUser(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByID: func(ctx context.Context, id string) (*entities.User, error) {
+			return &entities.User{ID: id, Email: "jane@example.com", Name: "Jane"}, nil
+		},
+		getByUsername: func(ctx context.Context, username string) (*entities.User, error) {
+			return &entities.User{ID: "someone-else", Username: username}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	username := "taken_name"
+	_, err := svc.UpdateUser(context.Background(), "user-1", &dto.UpdateUserRequest{Username: &username})
+	if err != apperrors.ErrUsernameTaken {
+		t.Fatalf("expected ErrUsernameTaken, got %v", err)
+	}
+	if len(userRepo.updated) != 0 {
+		t.Error("expected no update for a taken username")
+	}
+}
+
+func TestGetUserProfileByUsername(t *testing.T) {
+	userRepo := &mockUserRepo{
+		getByUsername: func(ctx context.Context, username string) (*entities.User, error) {
+			if username != "jane" {
+				t.Errorf("expected normalized lookup, got %q", username)
+			}
+			return &entities.User{ID: "user-1", Name: "Jane", Username: username}, nil
+		},
+	}
+	svc := NewUserService(userRepo, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	profile, err := svc.GetUserProfileByUsername(context.Background(), "Jane")
+	if err != nil {
+		t.Fatalf("GetUserProfileByUsername: %v", err)
+	}
+	if profile.ID != "user-1" || profile.Username != "jane" {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+}
+
+func TestGetUserProfileByUsername_NotFound(t *testing.T) {
+	svc := NewUserService(&mockUserRepo{}, &mockFollowRepo{}, &mockBlockRepo{}, nil, logger.New("info"))
+
+	if _, err := svc.GetUserProfileByUsername(context.Background(), "nobody"); err != apperrors.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
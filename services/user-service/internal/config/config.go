@@ -11,12 +11,42 @@ type Config struct {
 	Port                     string
 	GRPCPort                 string
 	Environment              string
+	MetricsEnabled           bool // exposes /metrics and records Prometheus metrics
 	LogLevel                 string
+	LogFormat                string // "json" or "text"; defaults to json in production
+	Tracing                  TracingConfig
+	StartupRetry             StartupRetryConfig
 	Database                 DatabaseConfig
 	GRPCTLS                  GRPCTLSConfig
 	ServiceTransportSecurity string
 	InternalHTTPTrustMode    string
-	EnableGRPCReflection     bool
+	CORS                     CORSConfig
+	// InternalServiceToken is the shared secret (X-Internal-Token) the gateway
+	// and other services present. It guards internal-only routes and must
+	// accompany X-User-ID for the header to be trusted. Required in production;
+	// elsewhere empty disables internal routes.
+	InternalServiceToken string
+	EnableGRPCReflection bool
+	Email                EmailConfig
+}
+
+// EmailConfig configures the SMTP relay that delivers email change tokens.
+// Email changes are disabled when SMTPHost is empty.
+type EmailConfig struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+}
+
+// StartupRetryConfig bounds how long the service waits for its dependencies
+// to accept connections at startup.
+type StartupRetryConfig struct {
+	Attempts         int
+	InitialBackoffMs int
+	MaxBackoffMs     int
+	MaxWaitSeconds   int
 }
 
 type DatabaseConfig struct {
@@ -24,6 +54,8 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime int // minutes
+	ConnMaxIdleTime int // minutes, 0 keeps idle connections until ConnMaxLifetime
+	ConnectTimeout  int // seconds allowed for the startup ping
 }
 
 type GRPCTLSConfig struct {
@@ -34,17 +66,46 @@ type GRPCTLSConfig struct {
 	RequireClientCert bool
 }
 
+// CORSConfig lists the browser origins allowed to call the service directly.
+// An empty list sends no CORS headers; "*" must be configured explicitly and
+// is rejected together with credentials.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+}
+
+// TracingConfig enables OpenTelemetry trace export. An empty Endpoint keeps
+// tracing a no-op apart from propagating incoming trace context.
+type TracingConfig struct {
+	Endpoint    string
+	SampleRatio float64
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
-		Port:        getEnv("PORT", "8082"),
-		GRPCPort:    getEnv("GRPC_PORT", "50052"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
+		Port:           getEnv("PORT", "8082"),
+		GRPCPort:       getEnv("GRPC_PORT", "50052"),
+		Environment:    getEnv("ENVIRONMENT", "development"),
+		MetricsEnabled: getEnvAsBool("METRICS_ENABLED", true),
+		LogLevel:       getEnv("LOG_LEVEL", "info"),
+		LogFormat:      getEnv("LOG_FORMAT", defaultLogFormat(getEnv("ENVIRONMENT", "development"))),
+		Tracing: TracingConfig{
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			SampleRatio: getEnvAsFloat("OTEL_TRACES_SAMPLE_RATIO", 1),
+		},
+		StartupRetry: StartupRetryConfig{
+			Attempts:         getEnvAsInt("STARTUP_RETRY_ATTEMPTS", 10),
+			InitialBackoffMs: getEnvAsInt("STARTUP_RETRY_INITIAL_BACKOFF_MS", 500),
+			MaxBackoffMs:     getEnvAsInt("STARTUP_RETRY_MAX_BACKOFF_MS", 5000),
+			MaxWaitSeconds:   getEnvAsInt("STARTUP_RETRY_MAX_WAIT_SECONDS", 60),
+		},
 		Database: DatabaseConfig{
 			URL:             os.Getenv("DATABASE_URL"),
 			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
 			ConnMaxLifetime: getEnvAsInt("DB_CONN_MAX_LIFETIME", 60),
+			ConnMaxIdleTime: getEnvAsInt("DB_CONN_MAX_IDLE_TIME", 5),
+			ConnectTimeout:  getEnvAsInt("DB_CONNECT_TIMEOUT", 10),
 		},
 		GRPCTLS: GRPCTLSConfig{
 			Enabled:           getEnvAsBool("GRPC_TLS_ENABLED", false),
@@ -55,7 +116,19 @@ func Load() (*Config, error) {
 		},
 		ServiceTransportSecurity: resolveTransportSecurityMode(getEnv("SERVICE_TRANSPORT_SECURITY", ""), getEnv("ENVIRONMENT", "development"), getEnvAsBool("GRPC_TLS_ENABLED", false)),
 		InternalHTTPTrustMode:    resolveInternalHTTPTrustMode(getEnv("INTERNAL_HTTP_TRUST_MODE", ""), getEnv("ENVIRONMENT", "development")),
-		EnableGRPCReflection:     getEnvAsBool("GRPC_REFLECTION_ENABLED", getEnv("ENVIRONMENT", "development") != "production"),
+		CORS: CORSConfig{
+			AllowedOrigins:   parseCSV(getEnv("CORS_ALLOWED_ORIGINS", "")),
+			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", true),
+		},
+		InternalServiceToken: os.Getenv("INTERNAL_SERVICE_TOKEN"),
+		EnableGRPCReflection: getEnvAsBool("GRPC_REFLECTION_ENABLED", getEnv("ENVIRONMENT", "development") != "production"),
+		Email: EmailConfig{
+			SMTPHost:     os.Getenv("SMTP_HOST"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: os.Getenv("SMTP_USERNAME"),
+			SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+			From:         os.Getenv("EMAIL_FROM"),
+		},
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -66,9 +139,33 @@ func Load() (*Config, error) {
 }
 
 func (c *Config) validate() error {
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		return fmt.Errorf("LOG_FORMAT must be json or text")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("OTEL_TRACES_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if c.StartupRetry.Attempts < 1 || c.StartupRetry.MaxWaitSeconds < 1 {
+		return fmt.Errorf("STARTUP_RETRY_ATTEMPTS and STARTUP_RETRY_MAX_WAIT_SECONDS must be at least 1")
+	}
+	if c.StartupRetry.InitialBackoffMs < 0 || c.StartupRetry.MaxBackoffMs < c.StartupRetry.InitialBackoffMs {
+		return fmt.Errorf("STARTUP_RETRY_MAX_BACKOFF_MS must be at least STARTUP_RETRY_INITIAL_BACKOFF_MS")
+	}
 	if c.Database.URL == "" {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
+	if c.Database.MaxOpenConns < 1 {
+		return fmt.Errorf("DB_MAX_OPEN_CONNS must be at least 1")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+	}
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 {
+		return fmt.Errorf("DB_CONN_MAX_LIFETIME and DB_CONN_MAX_IDLE_TIME must not be negative")
+	}
+	if c.Database.ConnectTimeout < 1 {
+		return fmt.Errorf("DB_CONNECT_TIMEOUT must be at least 1 second")
+	}
 	if c.GRPCPort == "" {
 		return fmt.Errorf("GRPC_PORT is required")
 	}
@@ -89,12 +186,53 @@ func (c *Config) validate() error {
 	if err := validateInternalHTTPTrustMode(c.Environment, c.InternalHTTPTrustMode); err != nil {
 		return err
 	}
+	if c.CORS.AllowCredentials && containsWildcard(c.CORS.AllowedOrigins) {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS cannot contain * when CORS_ALLOW_CREDENTIALS=true")
+	}
+	if err := validateInternalServiceToken(c.Environment, c.InternalServiceToken); err != nil {
+		return err
+	}
 	if c.Environment == "production" && c.EnableGRPCReflection {
 		return fmt.Errorf("GRPC_REFLECTION_ENABLED cannot be true in production")
 	}
+	if c.Email.SMTPHost != "" && c.Email.From == "" {
+		return fmt.Errorf("EMAIL_FROM is required when SMTP_HOST is set")
+	}
 	return nil
 }
 
+func containsWildcard(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCSV(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	parts := strings.Split(value, ",")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		item := strings.TrimSpace(part)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func defaultLogFormat(environment string) string {
+	if environment == "production" {
+		return "json"
+	}
+	return "text"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -111,6 +249,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -187,3 +334,18 @@ func validateInternalHTTPTrustMode(environment, mode string) error {
 		return fmt.Errorf("INTERNAL_HTTP_TRUST_MODE must be one of private_network, disabled, insecure_dev")
 	}
 }
+
+// validateInternalServiceToken fails closed: production refuses to start
+// without a token, since X-User-ID would otherwise be unverifiable.
+func validateInternalServiceToken(environment, token string) error {
+	if token == "" {
+		if environment == "production" {
+			return fmt.Errorf("INTERNAL_SERVICE_TOKEN is required in production")
+		}
+		return nil
+	}
+	if len(token) < 32 {
+		return fmt.Errorf("INTERNAL_SERVICE_TOKEN must be at least 32 characters")
+	}
+	return nil
+}
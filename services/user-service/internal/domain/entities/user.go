@@ -8,23 +8,55 @@ import (
 )
 
 type User struct {
-	ID           string    `json:"id" db:"id"`
-	Email        string    `json:"email" db:"email"`
-	Name         string    `json:"name" db:"name"`
-	Picture      string    `json:"picture,omitempty" db:"picture"`
-	PasswordHash string    `json:"-" db:"password_hash"` // never expose; nullable for OAuth users
-	Bio          string    `json:"bio,omitempty" db:"bio"`
-	Location     string    `json:"location,omitempty" db:"location"`
-	Website      string    `json:"website,omitempty" db:"website"`
-	IsActive     bool      `json:"is_active" db:"is_active"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID           string `json:"id" db:"id"`
+	Email        string `json:"email" db:"email"`
+	Name         string `json:"name" db:"name"`
+	Username     string `json:"username,omitempty" db:"username"` // optional unique handle
+	Picture      string `json:"picture,omitempty" db:"picture"`
+	PasswordHash string `json:"-" db:"password_hash"` // never expose; nullable for OAuth users
+	Bio          string `json:"bio,omitempty" db:"bio"`
+	Location     string `json:"location,omitempty" db:"location"`
+	Website      string `json:"website,omitempty" db:"website"`
+	Role         string `json:"role" db:"role"`
+	// EmailVerified is set at signup when the identity provider vouched for
+	// the address, or later by an admin.
+	EmailVerified   bool       `json:"email_verified" db:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	// PendingEmail is a requested new address awaiting confirmation; Email
+	// stays in use until then.
+	PendingEmail string     `json:"pending_email,omitempty" db:"pending_email"`
+	LastSeenAt   *time.Time `json:"last_seen_at,omitempty" db:"last_seen_at"`
+	// Status tells a restorable deactivation apart from a terminal delete;
+	// IsActive is true exactly when Status is StatusActive.
+	Status   string `json:"status" db:"status"`
+	IsActive bool   `json:"is_active" db:"is_active"`
+	// DeactivatedBy is who deactivated the account: its own ID when the user
+	// did, an admin's otherwise. Only GetDeactivatedByEmail fills it in.
+	DeactivatedBy string    `json:"-" db:"deactivated_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Roles a user can hold. Every account starts as RoleUser; admins are
+// promoted directly in the database.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Account statuses. Deactivated accounts are hidden from reads but can be
+// reactivated; deleted is terminal.
+const (
+	StatusActive      = "active"
+	StatusDeactivated = "deactivated"
+	StatusDeleted     = "deleted"
+)
+
+// UserProfile is the public view of a user. It never carries the email.
 type UserProfile struct {
 	ID       string `json:"id"`
-	Email    string `json:"email"`
 	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
 	Picture  string `json:"picture,omitempty"`
 	Bio      string `json:"bio,omitempty"`
 	Location string `json:"location,omitempty"`
@@ -34,8 +66,8 @@ type UserProfile struct {
 func (u *User) ToProfile() *UserProfile {
 	return &UserProfile{
 		ID:       u.ID,
-		Email:    u.Email,
 		Name:     u.Name,
+		Username: u.Username,
 		Picture:  u.Picture,
 		Bio:      u.Bio,
 		Location: u.Location,
@@ -52,7 +84,7 @@ func (u *User) IsValid() error {
 		return fmt.Errorf("email is required")
 	}
 
-	if !isValidEmail(u.Email) {
+	if !IsValidEmail(u.Email) {
 		return fmt.Errorf("invalid email format")
 	}
 
@@ -64,6 +96,10 @@ func (u *User) IsValid() error {
 		return fmt.Errorf("name must be less than 100 characters")
 	}
 
+	if u.Username != "" && !IsValidUsername(u.Username) {
+		return fmt.Errorf("username must be 3-30 characters of a-z, 0-9 or _")
+	}
+
 	if len(u.Bio) > 500 {
 		return fmt.Errorf("bio must be less than 500 characters")
 	}
@@ -76,14 +112,46 @@ func (u *User) IsValid() error {
 }
 
 func (u *User) Sanitize() {
-	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+	u.Email = NormalizeEmail(u.Email)
 	u.Name = strings.TrimSpace(u.Name)
+	u.Username = NormalizeUsername(u.Username)
 	u.Bio = strings.TrimSpace(u.Bio)
 	u.Location = strings.TrimSpace(u.Location)
 	u.Website = strings.TrimSpace(u.Website)
+	if u.Role == "" {
+		u.Role = RoleUser
+	}
+	if u.Status == "" {
+		u.Status = StatusActive
+	}
+}
+
+// IsAdmin reports whether the user holds the admin role.
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+var usernameRegex = regexp.MustCompile(`^[a-z0-9_]{3,30}$`)
+
+// NormalizeUsername trims and lowercases a username; handles are stored and
+// compared in lowercase.
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// IsValidUsername reports whether username is an already-normalized handle.
+func IsValidUsername(username string) bool {
+	return usernameRegex.MatchString(username)
+}
+
+// NormalizeEmail trims and lowercases an email; addresses are stored and
+// compared in lowercase.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
-func isValidEmail(email string) bool {
+// IsValidEmail reports whether email looks like an address.
+func IsValidEmail(email string) bool {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	return emailRegex.MatchString(email)
 }
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"user-service/internal/domain/entities"
+)
+
+type BlockRepository interface {
+	// Block reports false when blockerID already blocks blockedID. Follows
+	// between the two users, in either direction, are removed atomically with
+	// the block.
+	Block(ctx context.Context, blockerID, blockedID string) (bool, error)
+	Unblock(ctx context.Context, blockerID, blockedID string) error
+	IsBlocked(ctx context.Context, blockerID, blockedID string) (bool, error)
+	ListBlocked(ctx context.Context, blockerID string, limit int, cursor string) ([]*entities.User, string, error)
+}
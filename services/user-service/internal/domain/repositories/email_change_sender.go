@@ -0,0 +1,9 @@
+package repositories
+
+import "context"
+
+// EmailChangeSender delivers an email change token to the requested address;
+// confirming the token proves the user controls it.
+type EmailChangeSender interface {
+	SendEmailChangeToken(ctx context.Context, userID, email, token string) error
+}
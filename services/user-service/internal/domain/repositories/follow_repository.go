@@ -10,6 +10,13 @@ type FollowRepository interface {
 	Delete(ctx context.Context, followerID, followeeID string) error
 	Exists(ctx context.Context, followerID, followeeID string) (bool, error)
 	GetFollowers(ctx context.Context, userID string, limit int, cursor string) ([]*entities.User, string, error)
+	// GetFollowerIDs pages through the IDs of userID's active followers,
+	// leaving out those who block userID. limit is capped at 1000. The cursor
+	// is a keyset position: following or unfollowing between calls neither
+	// skips nor repeats anyone else.
+	GetFollowerIDs(ctx context.Context, userID string, limit int, cursor string) ([]string, string, error)
 	GetFollowing(ctx context.Context, userID string, limit int, cursor string) ([]*entities.User, string, error)
 	AreFollowed(ctx context.Context, followerID string, followeeIDs []string) ([]string, error)
+	CountFollowers(ctx context.Context, userID string) (int64, error)
+	CountFollowing(ctx context.Context, userID string) (int64, error)
 }
@@ -2,17 +2,65 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 	"user-service/internal/domain/entities"
 )
 
+// ErrUsernameTaken is returned by Create and Update when another user already
+// holds the username.
+var ErrUsernameTaken = errors.New("username already taken")
+
+// ErrEmailTaken is returned by ConfirmPendingEmail when another account took
+// the address after the change was requested.
+var ErrEmailTaken = errors.New("email already taken")
+
+// ErrEmailChangeNotFound is returned by ConfirmPendingEmail when the user has
+// no pending change matching the token, or it expired.
+var ErrEmailChangeNotFound = errors.New("email change not found")
+
+// Sort keys List and ListAll accept.
+const (
+	UserSortCreatedAt = "created_at"
+	UserSortName      = "name"
+	UserSortEmail     = "email"
+)
+
+// UserListOptions orders and filters List, ListAll, Count and CountAll. An
+// empty Sort orders by creation time. CreatedAfter is inclusive and
+// CreatedBefore exclusive.
+type UserListOptions struct {
+	Sort          string
+	Descending    bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	GetByID(ctx context.Context, id string) (*entities.User, error)
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+	GetByUsername(ctx context.Context, username string) (*entities.User, error)
+	// GetByIDs returns the active users among ids, in no particular order.
+	GetByIDs(ctx context.Context, ids []string) ([]*entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
 	Delete(ctx context.Context, id string) error
-	List(ctx context.Context, limit, offset int) ([]*entities.User, error)
+	MarkEmailVerified(ctx context.Context, id string) error
+	SetPendingEmail(ctx context.Context, id, email, tokenHash string, expiresAt time.Time) error
+	ConfirmPendingEmail(ctx context.Context, id, tokenHash string, now time.Time) error
+	UpdateLastSeen(ctx context.Context, id string, seenAt time.Time) error
+	GetDeactivatedByEmail(ctx context.Context, email string) (*entities.User, error)
+	Deactivate(ctx context.Context, id, actorID string) error
+	Reactivate(ctx context.Context, id string) error
+	List(ctx context.Context, opts UserListOptions, limit, offset int) ([]*entities.User, error)
+	// ListAll also returns deactivated users; it backs the admin listing.
+	ListAll(ctx context.Context, opts UserListOptions, limit, offset int) ([]*entities.User, error)
 	Search(ctx context.Context, query string, limit, offset int) ([]*entities.User, error)
+	// Count and CountSearch return the number of rows List and Search would
+	// page through, ignoring limit and offset.
+	Count(ctx context.Context, opts UserListOptions) (int64, error)
+	CountSearch(ctx context.Context, query string) (int64, error)
+	CountAll(ctx context.Context, opts UserListOptions) (int64, error)
 	Exists(ctx context.Context, id string) (bool, error)
 	GetActiveUsersCount(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"user-service/internal/domain/entities"
+)
+
+type BlockRepository struct {
+	db *sql.DB
+}
+
+func NewBlockRepository(db *sql.DB) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+// Block records the block and drops any follow between the two users, in
+// either direction, in the same transaction.
+func (r *BlockRepository) Block(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("block begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2) ON CONFLICT (blocker_id, blocked_id) DO NOTHING`
+	result, err := tx.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return false, fmt.Errorf("block create: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	unfollow := `
+		DELETE FROM follows
+		WHERE (follower_id = $1 AND followee_id = $2) OR (follower_id = $2 AND followee_id = $1)
+	`
+	if _, err := tx.ExecContext(ctx, unfollow, blockerID, blockedID); err != nil {
+		return false, fmt.Errorf("block unfollow: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("block commit: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *BlockRepository) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, blockerID, blockedID); err != nil {
+		return fmt.Errorf("block delete: %w", err)
+	}
+	return nil
+}
+
+func (r *BlockRepository) IsBlocked(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2)`
+	var blocked bool
+	if err := r.db.QueryRowContext(ctx, query, blockerID, blockedID).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("block exists: %w", err)
+	}
+	return blocked, nil
+}
+
+// ListBlocked pages through the active users blockerID blocks, most recently
+// blocked first.
+func (r *BlockRepository) ListBlocked(ctx context.Context, blockerID string, limit int, cursor string) ([]*entities.User, string, error) {
+	offset := decodeCursor(cursor)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	query := `
+		SELECT u.id, u.email, u.name, COALESCE(u.username, ''), u.picture, COALESCE(u.password_hash, ''), u.bio, u.location, u.website, u.is_active, u.created_at, u.updated_at
+		FROM users u
+		INNER JOIN user_blocks b ON b.blocked_id = u.id
+		WHERE b.blocker_id = $1 AND u.is_active = true
+		ORDER BY b.created_at DESC, b.blocked_id
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, blockerID, limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+	var users []*entities.User
+	for rows.Next() {
+		u := &entities.User{}
+		err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Username, &u.Picture, &u.PasswordHash, &u.Bio, &u.Location, &u.Website, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+		if err != nil {
+			return nil, "", err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	nextCursor := ""
+	if len(users) > limit {
+		users = users[:limit]
+		nextCursor = encodeCursor(offset + limit)
+	}
+	return users, nextCursor, nil
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// The repository tests need a scratch database. Run them with
+//
+//	USER_TEST_DATABASE_URL=postgres://... go test ./internal/infrastructure/postgres/
+const testDatabaseURLEnv = "USER_TEST_DATABASE_URL"
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv(testDatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s not set", testDatabaseURLEnv)
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := RunMigrations(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// seedUser inserts a user that is removed again, with its follows and blocks,
+// when the test ends.
+func seedUser(t *testing.T, db *sql.DB) string {
+	t.Helper()
+	id := uuid.NewString()
+	_, err := db.Exec(`INSERT INTO users (id, email, name) VALUES ($1, $2, $3)`, id, id+"@example.com", "Test User")
+	if err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id = $1`, id) })
+	return id
+}
+
+func TestBlockRemovesFollowsBothWays(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	blocker, blocked := seedUser(t, db), seedUser(t, db)
+
+	follows := NewFollowRepository(db)
+	if err := follows.Create(ctx, blocker, blocked); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if err := follows.Create(ctx, blocked, blocker); err != nil {
+		t.Fatalf("follow back: %v", err)
+	}
+
+	created, err := NewBlockRepository(db).Block(ctx, blocker, blocked)
+	if err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if !created {
+		t.Fatal("expected a new block")
+	}
+	for _, pair := range [][2]string{{blocker, blocked}, {blocked, blocker}} {
+		exists, err := follows.Exists(ctx, pair[0], pair[1])
+		if err != nil {
+			t.Fatalf("Exists: %v", err)
+		}
+		if exists {
+			t.Errorf("follow %s -> %s survived the block", pair[0], pair[1])
+		}
+	}
+}
+
+func TestBlockTwiceReportsExisting(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	blocker, blocked := seedUser(t, db), seedUser(t, db)
+
+	repo := NewBlockRepository(db)
+	if _, err := repo.Block(ctx, blocker, blocked); err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	created, err := repo.Block(ctx, blocker, blocked)
+	if err != nil {
+		t.Fatalf("second Block: %v", err)
+	}
+	if created {
+		t.Error("expected the second block to report an existing row")
+	}
+}
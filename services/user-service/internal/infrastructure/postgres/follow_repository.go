@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"user-service/internal/domain/entities"
 )
@@ -78,7 +79,7 @@ func (r *FollowRepository) GetFollowers(ctx context.Context, userID string, limi
 		limit = 20
 	}
 	query := `
-		SELECT u.id, u.email, u.name, u.picture, COALESCE(u.password_hash, ''), u.bio, u.location, u.website, u.is_active, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.name, COALESCE(u.username, ''), u.picture, COALESCE(u.password_hash, ''), u.bio, u.location, u.website, u.is_active, u.created_at, u.updated_at
 		FROM users u
 		INNER JOIN follows f ON f.follower_id = u.id
 		WHERE f.followee_id = $1 AND u.is_active = true
@@ -93,7 +94,7 @@ func (r *FollowRepository) GetFollowers(ctx context.Context, userID string, limi
 	var users []*entities.User
 	for rows.Next() {
 		u := &entities.User{}
-		err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Picture, &u.PasswordHash, &u.Bio, &u.Location, &u.Website, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+		err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Username, &u.Picture, &u.PasswordHash, &u.Bio, &u.Location, &u.Website, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
 		if err != nil {
 			return nil, "", err
 		}
@@ -110,13 +111,91 @@ func (r *FollowRepository) GetFollowers(ctx context.Context, userID string, limi
 	return users, nextCursor, nil
 }
 
+// GetFollowerIDs pages by keyset on (created_at, follower_id) rather than by
+// offset, so a page starts where the previous one ended even when followers
+// come and go between calls.
+func (r *FollowRepository) GetFollowerIDs(ctx context.Context, userID string, limit int, cursor string) ([]string, string, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 500
+	}
+	after, hasAfter := decodeFollowerCursor(cursor)
+	query := `
+		SELECT f.follower_id, f.created_at
+		FROM follows f
+		INNER JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1 AND u.is_active = true
+			AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE b.blocker_id = f.follower_id AND b.blocked_id = f.followee_id)
+			AND (NOT $3 OR (f.created_at, f.follower_id) < ($4::timestamp, $5))
+		ORDER BY f.created_at DESC, f.follower_id DESC
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit+1, hasAfter, after.createdAt.Format(followerCursorTime), after.followerID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+	var ids []string
+	var last followerCursor
+	for rows.Next() {
+		var id string
+		var createdAt time.Time
+		if err := rows.Scan(&id, &createdAt); err != nil {
+			return nil, "", err
+		}
+		if len(ids) < limit {
+			last = followerCursor{createdAt: createdAt, followerID: id}
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	nextCursor := ""
+	if len(ids) > limit {
+		ids = ids[:limit]
+		nextCursor = encodeFollowerCursor(last)
+	}
+	return ids, nextCursor, nil
+}
+
+// followerCursor is the last row of a GetFollowerIDs page.
+type followerCursor struct {
+	createdAt  time.Time
+	followerID string
+}
+
+// followerCursorTime keeps the microseconds of a TIMESTAMP column.
+const followerCursorTime = "2006-01-02T15:04:05.999999"
+
+func encodeFollowerCursor(c followerCursor) string {
+	return base64.StdEncoding.EncodeToString([]byte(c.createdAt.Format(followerCursorTime) + "|" + c.followerID))
+}
+
+// decodeFollowerCursor reports false for an empty or malformed cursor, which
+// starts from the first page.
+func decodeFollowerCursor(c string) (followerCursor, bool) {
+	b, err := base64.StdEncoding.DecodeString(c)
+	if c == "" || err != nil {
+		return followerCursor{}, false
+	}
+	createdAt, followerID, ok := strings.Cut(string(b), "|")
+	if !ok || followerID == "" {
+		return followerCursor{}, false
+	}
+	t, err := time.Parse(followerCursorTime, createdAt)
+	if err != nil {
+		return followerCursor{}, false
+	}
+	return followerCursor{createdAt: t, followerID: followerID}, true
+}
+
 func (r *FollowRepository) GetFollowing(ctx context.Context, userID string, limit int, cursor string) ([]*entities.User, string, error) {
 	offset := decodeCursor(cursor)
 	if limit <= 0 || limit > 100 {
 		limit = 20
 	}
 	query := `
-		SELECT u.id, u.email, u.name, u.picture, COALESCE(u.password_hash, ''), u.bio, u.location, u.website, u.is_active, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.name, COALESCE(u.username, ''), u.picture, COALESCE(u.password_hash, ''), u.bio, u.location, u.website, u.is_active, u.created_at, u.updated_at
 		FROM users u
 		INNER JOIN follows f ON f.followee_id = u.id
 		WHERE f.follower_id = $1 AND u.is_active = true
@@ -131,7 +210,7 @@ func (r *FollowRepository) GetFollowing(ctx context.Context, userID string, limi
 	var users []*entities.User
 	for rows.Next() {
 		u := &entities.User{}
-		err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Picture, &u.PasswordHash, &u.Bio, &u.Location, &u.Website, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+		err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Username, &u.Picture, &u.PasswordHash, &u.Bio, &u.Location, &u.Website, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
 		if err != nil {
 			return nil, "", err
 		}
@@ -177,3 +256,33 @@ func (r *FollowRepository) AreFollowed(ctx context.Context, followerID string, f
 	}
 	return out, rows.Err()
 }
+
+// CountFollowers counts active users following userID, matching GetFollowers.
+func (r *FollowRepository) CountFollowers(ctx context.Context, userID string) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM follows f
+		INNER JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1 AND u.is_active = true
+	`
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count followers: %w", err)
+	}
+	return count, nil
+}
+
+// CountFollowing counts active users userID follows, matching GetFollowing.
+func (r *FollowRepository) CountFollowing(ctx context.Context, userID string) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM follows f
+		INNER JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1 AND u.is_active = true
+	`
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count following: %w", err)
+	}
+	return count, nil
+}
@@ -54,6 +54,70 @@ func RunMigrations(db *sql.DB) error {
 		return err
 	}
 
+	// Usernames are optional, so existing rows stay NULL; the partial unique
+	// index only constrains users that have picked one.
+	alterQuery = `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(30);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users(username) WHERE username IS NOT NULL;
+	`
+	if _, err := db.Exec(alterQuery); err != nil {
+		return err
+	}
+
+	// Existing rows become regular users; admins are promoted by hand.
+	alterQuery = `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+	`
+	if _, err := db.Exec(alterQuery); err != nil {
+		return err
+	}
+
+	// Existing rows start unverified.
+	alterQuery = `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT false;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+	`
+	if _, err := db.Exec(alterQuery); err != nil {
+		return err
+	}
+
+	alterQuery = `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP;
+	`
+	if _, err := db.Exec(alterQuery); err != nil {
+		return err
+	}
+
+	// Rows soft-deleted before status existed become deleted, not deactivated.
+	alterQuery = `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+	UPDATE users SET status = 'deleted' WHERE is_active = false AND status = 'active';
+	`
+	if _, err := db.Exec(alterQuery); err != nil {
+		return err
+	}
+
+	// Sign-in only reactivates accounts their owner deactivated. Accounts
+	// deactivated before the actor was recorded have none and stay deactivated
+	// until an admin reactivates them.
+	alterQuery = `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_by VARCHAR(255);
+	`
+	if _, err := db.Exec(alterQuery); err != nil {
+		return err
+	}
+
+	// A requested email change waits in pending_email until its token is
+	// confirmed; only the token's hash is stored.
+	alterQuery = `
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255);
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email_token_hash VARCHAR(64);
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email_expires_at TIMESTAMP;
+	`
+	if _, err := db.Exec(alterQuery); err != nil {
+		return err
+	}
+
 	// Follows table for follow/subscription graph
 	followsQuery := `
 	CREATE TABLE IF NOT EXISTS follows (
@@ -66,8 +130,26 @@ func RunMigrations(db *sql.DB) error {
 		FOREIGN KEY (followee_id) REFERENCES users(id) ON DELETE CASCADE
 	);
 	CREATE INDEX IF NOT EXISTS idx_follows_followee_id ON follows(followee_id);
+	CREATE INDEX IF NOT EXISTS idx_follows_followee_created ON follows(followee_id, created_at DESC, follower_id DESC);
 	CREATE INDEX IF NOT EXISTS idx_follows_follower_id ON follows(follower_id);
 	`
-	_, err := db.Exec(followsQuery)
+	if _, err := db.Exec(followsQuery); err != nil {
+		return err
+	}
+
+	// Blocks are one-way: blocker_id no longer hears from blocked_id.
+	blocksQuery := `
+	CREATE TABLE IF NOT EXISTS user_blocks (
+		blocker_id VARCHAR(255) NOT NULL,
+		blocked_id VARCHAR(255) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (blocker_id, blocked_id),
+		CHECK (blocker_id != blocked_id),
+		FOREIGN KEY (blocker_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (blocked_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked_id ON user_blocks(blocked_id);
+	`
+	_, err := db.Exec(blocksQuery)
 	return err
 }
@@ -8,8 +8,18 @@ import (
 	"time"
 
 	"user-service/internal/domain/entities"
+	"user-service/internal/domain/repositories"
+
+	"github.com/lib/pq"
 )
 
+// usernameIndex is the unique index whose violation means the username is taken.
+const usernameIndex = "idx_users_username"
+
+// emailConstraint is the unique constraint whose violation means the email
+// belongs to another account.
+const emailConstraint = "users_email_key"
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -20,15 +30,26 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 
 func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
 	query := `
-		INSERT INTO users (id, email, name, picture, password_hash, bio, location, website, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO users (id, email, name, username, picture, password_hash, bio, location, website, role, email_verified, email_verified_at, status, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
+	role := user.Role
+	if role == "" {
+		role = entities.RoleUser
+	}
+	accountStatus := user.Status
+	if accountStatus == "" {
+		accountStatus = entities.StatusActive
+	}
 	now := time.Now()
 	_, err := r.db.ExecContext(ctx, query,
-		user.ID, user.Email, user.Name, user.Picture, nullIfEmpty(user.PasswordHash), user.Bio,
-		user.Location, user.Website, user.IsActive, now, now)
+		user.ID, user.Email, user.Name, nullIfEmpty(user.Username), user.Picture, nullIfEmpty(user.PasswordHash), user.Bio,
+		user.Location, user.Website, role, user.EmailVerified, user.EmailVerifiedAt, accountStatus, user.IsActive, now, now)
 
 	if err != nil {
+		if strings.Contains(err.Error(), usernameIndex) {
+			return repositories.ErrUsernameTaken
+		}
 		if strings.Contains(err.Error(), "duplicate key") {
 			return fmt.Errorf("user with email %s already exists", user.Email)
 		}
@@ -42,14 +63,14 @@ func (r *UserRepository) Create(ctx context.Context, user *entities.User) error
 
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
 	query := `
-		SELECT id, email, name, picture, COALESCE(password_hash, ''), bio, location, website, is_active, created_at, updated_at
+		SELECT id, email, name, COALESCE(username, ''), picture, COALESCE(password_hash, ''), bio, location, website, role, email_verified, email_verified_at, COALESCE(pending_email, ''), last_seen_at, status, is_active, created_at, updated_at
 		FROM users 
 		WHERE id = $1 AND is_active = true
 	`
 	user := &entities.User{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.Name, &user.Picture, &user.PasswordHash, &user.Bio,
-		&user.Location, &user.Website, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Email, &user.Name, &user.Username, &user.Picture, &user.PasswordHash, &user.Bio,
+		&user.Location, &user.Website, &user.Role, &user.EmailVerified, &user.EmailVerifiedAt, &user.PendingEmail, &user.LastSeenAt, &user.Status, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -64,14 +85,14 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*entities.User
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
 	query := `
-		SELECT id, email, name, picture, COALESCE(password_hash, ''), bio, location, website, is_active, created_at, updated_at
+		SELECT id, email, name, COALESCE(username, ''), picture, COALESCE(password_hash, ''), bio, location, website, role, email_verified, email_verified_at, COALESCE(pending_email, ''), last_seen_at, status, is_active, created_at, updated_at
 		FROM users 
 		WHERE email = $1 AND is_active = true
 	`
 	user := &entities.User{}
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.Name, &user.Picture, &user.PasswordHash, &user.Bio,
-		&user.Location, &user.Website, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Email, &user.Name, &user.Username, &user.Picture, &user.PasswordHash, &user.Bio,
+		&user.Location, &user.Website, &user.Role, &user.EmailVerified, &user.EmailVerifiedAt, &user.PendingEmail, &user.LastSeenAt, &user.Status, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -84,17 +105,102 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entitie
 	return user, nil
 }
 
+// GetDeactivatedByEmail finds a deactivated (not deleted) account by email,
+// with who deactivated it.
+func (r *UserRepository) GetDeactivatedByEmail(ctx context.Context, email string) (*entities.User, error) {
+	query := `
+		SELECT id, email, name, COALESCE(username, ''), picture, COALESCE(password_hash, ''), bio, location, website, role, email_verified, email_verified_at, COALESCE(pending_email, ''), last_seen_at, status, is_active, COALESCE(deactivated_by, ''), created_at, updated_at
+		FROM users
+		WHERE email = $1 AND status = 'deactivated'
+	`
+	user := &entities.User{}
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
+		&user.ID, &user.Email, &user.Name, &user.Username, &user.Picture, &user.PasswordHash, &user.Bio,
+		&user.Location, &user.Website, &user.Role, &user.EmailVerified, &user.EmailVerifiedAt, &user.PendingEmail, &user.LastSeenAt, &user.Status, &user.IsActive, &user.DeactivatedBy, &user.CreatedAt, &user.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
+	query := `
+		SELECT id, email, name, COALESCE(username, ''), picture, COALESCE(password_hash, ''), bio, location, website, role, email_verified, email_verified_at, COALESCE(pending_email, ''), last_seen_at, status, is_active, created_at, updated_at
+		FROM users 
+		WHERE username = $1 AND is_active = true
+	`
+	user := &entities.User{}
+	err := r.db.QueryRowContext(ctx, query, username).Scan(
+		&user.ID, &user.Email, &user.Name, &user.Username, &user.Picture, &user.PasswordHash, &user.Bio,
+		&user.Location, &user.Website, &user.Role, &user.EmailVerified, &user.EmailVerifiedAt, &user.PendingEmail, &user.LastSeenAt, &user.Status, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []string) ([]*entities.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, email, name, COALESCE(username, ''), picture, COALESCE(password_hash, ''), bio, location, website, role, email_verified, email_verified_at, COALESCE(pending_email, ''), last_seen_at, status, is_active, created_at, updated_at
+		FROM users
+		WHERE id = ANY($1) AND is_active = true
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users by ids: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*entities.User, 0, len(ids))
+	for rows.Next() {
+		user := &entities.User{}
+		err := rows.Scan(
+			&user.ID, &user.Email, &user.Name, &user.Username, &user.Picture, &user.PasswordHash, &user.Bio,
+			&user.Location, &user.Website, &user.Role, &user.EmailVerified, &user.EmailVerifiedAt, &user.PendingEmail, &user.LastSeenAt, &user.Status, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return users, nil
+}
+
 func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
 	query := `
 		UPDATE users 
-		SET name = $2, picture = $3, bio = $4, location = $5, website = $6, updated_at = $7
+		SET name = $2, picture = $3, bio = $4, location = $5, website = $6, username = $7, updated_at = $8
 		WHERE id = $1 AND is_active = true
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
-		user.ID, user.Name, user.Picture, user.Bio, user.Location, user.Website, time.Now())
+		user.ID, user.Name, user.Picture, user.Bio, user.Location, user.Website, nullIfEmpty(user.Username), time.Now())
 
 	if err != nil {
+		if strings.Contains(err.Error(), usernameIndex) {
+			return repositories.ErrUsernameTaken
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
@@ -110,9 +216,160 @@ func (r *UserRepository) Update(ctx context.Context, user *entities.User) error
 	return nil
 }
 
+// MarkEmailVerified flags the user's email verified, keeping the first
+// verification time when it is already set.
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id string) error {
+	query := `
+		UPDATE users
+		SET email_verified = true, email_verified_at = COALESCE(email_verified_at, $2), updated_at = $2
+		WHERE id = $1 AND is_active = true
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// SetPendingEmail records a requested email change, replacing any earlier
+// one and its token. The current email is left as it is.
+func (r *UserRepository) SetPendingEmail(ctx context.Context, id, email, tokenHash string, expiresAt time.Time) error {
+	query := `
+		UPDATE users
+		SET pending_email = $2, pending_email_token_hash = $3, pending_email_expires_at = $4, updated_at = $5
+		WHERE id = $1 AND is_active = true
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, email, tokenHash, expiresAt, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to set pending email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// ConfirmPendingEmail promotes the pending email to email when tokenHash
+// matches an unexpired change, marking the new address verified as of now.
+func (r *UserRepository) ConfirmPendingEmail(ctx context.Context, id, tokenHash string, now time.Time) error {
+	query := `
+		UPDATE users
+		SET email = pending_email, email_verified = true, email_verified_at = $3,
+			pending_email = NULL, pending_email_token_hash = NULL, pending_email_expires_at = NULL, updated_at = $3
+		WHERE id = $1 AND is_active = true AND pending_email IS NOT NULL
+			AND pending_email_token_hash = $2 AND pending_email_expires_at > $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, tokenHash, now)
+	if err != nil {
+		if strings.Contains(err.Error(), emailConstraint) {
+			return repositories.ErrEmailTaken
+		}
+		return fmt.Errorf("failed to confirm pending email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return repositories.ErrEmailChangeNotFound
+	}
+
+	return nil
+}
+
+// Deactivate hides the account until it is reactivated and records actorID
+// as who deactivated it. Deactivating an already deactivated account only
+// replaces the actor when an admin does it, so the owner cannot turn an admin
+// deactivation into one that sign-in undoes. Deleted accounts are not found.
+func (r *UserRepository) Deactivate(ctx context.Context, id, actorID string) error {
+	query := `
+		UPDATE users
+		SET status = 'deactivated', is_active = false, updated_at = $3,
+			deactivated_by = CASE WHEN status = 'deactivated' AND $2 = id THEN deactivated_by ELSE $2 END
+		WHERE id = $1 AND status IN ('active', 'deactivated')
+	`
+	return r.setStatus(ctx, query, "deactivate", id, actorID, time.Now())
+}
+
+// Reactivate restores a deactivated account. Reactivating an active account
+// is a no-op; deleted accounts are not found.
+func (r *UserRepository) Reactivate(ctx context.Context, id string) error {
+	query := `
+		UPDATE users
+		SET status = 'active', is_active = true, deactivated_by = NULL, updated_at = $2
+		WHERE id = $1 AND status IN ('active', 'deactivated')
+	`
+	return r.setStatus(ctx, query, "reactivate", id, time.Now())
+}
+
+func (r *UserRepository) setStatus(ctx context.Context, query, action string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to %s user: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// UpdateLastSeen records that the user was active at seenAt.
+func (r *UserRepository) UpdateLastSeen(ctx context.Context, id string, seenAt time.Time) error {
+	query := `
+		UPDATE users
+		SET last_seen_at = $2
+		WHERE id = $1 AND is_active = true
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, seenAt)
+	if err != nil {
+		return fmt.Errorf("failed to update last seen: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
-	// Soft delete by setting is_active to false
-	query := `UPDATE users SET is_active = false, updated_at = $2 WHERE id = $1 AND is_active = true`
+	// Soft delete; a deactivated account can still be deleted.
+	query := `UPDATE users SET status = 'deleted', is_active = false, updated_at = $2 WHERE id = $1 AND status <> 'deleted'`
 
 	result, err := r.db.ExecContext(ctx, query, id, time.Now())
 	if err != nil {
@@ -131,15 +388,48 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
-	query := `
-		SELECT id, email, name, picture, COALESCE(password_hash, ''), bio, location, website, is_active, created_at, updated_at
-		FROM users 
-		WHERE is_active = true
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+func (r *UserRepository) List(ctx context.Context, opts repositories.UserListOptions, limit, offset int) ([]*entities.User, error) {
+	return r.list(ctx, true, opts, limit, offset)
+}
+
+// ListAll pages through every user, deactivated ones included, for admins.
+func (r *UserRepository) ListAll(ctx context.Context, opts repositories.UserListOptions, limit, offset int) ([]*entities.User, error) {
+	return r.list(ctx, false, opts, limit, offset)
+}
+
+// userSortColumns whitelists the columns a list may be ordered by; the sort
+// key is never interpolated into SQL as given.
+var userSortColumns = map[string]string{
+	repositories.UserSortCreatedAt: "created_at",
+	repositories.UserSortName:      "name",
+	repositories.UserSortEmail:     "email",
+}
+
+func (r *UserRepository) list(ctx context.Context, activeOnly bool, opts repositories.UserListOptions, limit, offset int) ([]*entities.User, error) {
+	column, ok := userSortColumns[opts.Sort]
+	if opts.Sort == "" {
+		column, ok = "created_at", true
+	}
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort %q", opts.Sort)
+	}
+	direction := "ASC"
+	if opts.Descending {
+		direction = "DESC"
+	}
+
+	where, args := userListFilter(activeOnly, opts)
+	args = append(args, limit, offset)
+	// id breaks ties so pages stay stable when many rows share the sort value.
+	query := fmt.Sprintf(`
+		SELECT id, email, name, COALESCE(username, ''), picture, COALESCE(password_hash, ''), bio, location, website, role, email_verified, email_verified_at, COALESCE(pending_email, ''), last_seen_at, status, is_active, created_at, updated_at
+		FROM users
+		%s
+		ORDER BY %s %s, id %s
+		LIMIT $%d OFFSET $%d
+	`, where, column, direction, direction, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
@@ -149,8 +439,8 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*entiti
 	for rows.Next() {
 		user := &entities.User{}
 		err := rows.Scan(
-			&user.ID, &user.Email, &user.Name, &user.Picture, &user.PasswordHash, &user.Bio,
-			&user.Location, &user.Website, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+			&user.ID, &user.Email, &user.Name, &user.Username, &user.Picture, &user.PasswordHash, &user.Bio,
+			&user.Location, &user.Website, &user.Role, &user.EmailVerified, &user.EmailVerifiedAt, &user.PendingEmail, &user.LastSeenAt, &user.Status, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -165,9 +455,31 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*entiti
 	return users, nil
 }
 
+// userListFilter builds the WHERE clause shared by list and count, with the
+// created_at bounds as placeholders.
+func userListFilter(activeOnly bool, opts repositories.UserListOptions) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if activeOnly {
+		conditions = append(conditions, "is_active = true")
+	}
+	if opts.CreatedAfter != nil {
+		args = append(args, *opts.CreatedAfter)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if opts.CreatedBefore != nil {
+		args = append(args, *opts.CreatedBefore)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
 func (r *UserRepository) Search(ctx context.Context, query string, limit, offset int) ([]*entities.User, error) {
 	searchQuery := `
-		SELECT id, email, name, picture, COALESCE(password_hash, ''), bio, location, website, is_active, created_at, updated_at
+		SELECT id, email, name, COALESCE(username, ''), picture, COALESCE(password_hash, ''), bio, location, website, role, email_verified, email_verified_at, COALESCE(pending_email, ''), last_seen_at, status, is_active, created_at, updated_at
 		FROM users 
 		WHERE is_active = true
 		AND to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(email, '')) @@ plainto_tsquery('simple', $1)
@@ -187,8 +499,8 @@ func (r *UserRepository) Search(ctx context.Context, query string, limit, offset
 	for rows.Next() {
 		user := &entities.User{}
 		err := rows.Scan(
-			&user.ID, &user.Email, &user.Name, &user.Picture, &user.PasswordHash, &user.Bio,
-			&user.Location, &user.Website, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+			&user.ID, &user.Email, &user.Name, &user.Username, &user.Picture, &user.PasswordHash, &user.Bio,
+			&user.Location, &user.Website, &user.Role, &user.EmailVerified, &user.EmailVerifiedAt, &user.PendingEmail, &user.LastSeenAt, &user.Status, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -203,6 +515,39 @@ func (r *UserRepository) Search(ctx context.Context, query string, limit, offset
 	return users, nil
 }
 
+// Count matches List, which only pages through active users.
+func (r *UserRepository) Count(ctx context.Context, opts repositories.UserListOptions) (int64, error) {
+	return r.count(ctx, true, opts)
+}
+
+// CountAll matches ListAll.
+func (r *UserRepository) CountAll(ctx context.Context, opts repositories.UserListOptions) (int64, error) {
+	return r.count(ctx, false, opts)
+}
+
+func (r *UserRepository) count(ctx context.Context, activeOnly bool, opts repositories.UserListOptions) (int64, error) {
+	where, args := userListFilter(activeOnly, opts)
+	var count int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *UserRepository) CountSearch(ctx context.Context, query string) (int64, error) {
+	countQuery := `
+		SELECT COUNT(*)
+		FROM users
+		WHERE is_active = true
+		AND to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(email, '')) @@ plainto_tsquery('simple', $1)
+	`
+	var count int64
+	if err := r.db.QueryRowContext(ctx, countQuery, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+	return count, nil
+}
+
 func (r *UserRepository) Exists(ctx context.Context, id string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND is_active = true)`
 
@@ -3,15 +3,19 @@ package grpc
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"user-service/internal/application/dto"
 	appErrors "user-service/internal/application/errors"
 	"user-service/internal/application/services"
+	"user-service/internal/domain/entities"
+	"user-service/pkg/health"
 	"user-service/pkg/logger"
 
 	// userv1 "/microblog_grpc/proto/user/v1"
 	userv1 "github.com/nikitashilov/microblog_grpc/proto/user/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -22,20 +26,23 @@ import (
 type UserServer struct {
 	userv1.UnimplementedUserServiceServer
 	service *services.UserService
+	health  *health.Checker
 	logger  *logger.Logger
 }
 
-func NewUserServer(service *services.UserService, logger *logger.Logger) *UserServer {
-	return &UserServer{service: service, logger: logger}
+func NewUserServer(service *services.UserService, checker *health.Checker, logger *logger.Logger) *UserServer {
+	return &UserServer{service: service, health: checker, logger: logger}
 }
 
 func (s *UserServer) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.User, error) {
 	dtoReq := &dto.CreateUserRequest{
-		ID:       req.GetId(),
-		Email:    req.GetEmail(),
-		Name:     req.GetName(),
-		Picture:  req.GetPicture(),
-		Password: req.GetPassword(),
+		ID:            req.GetId(),
+		Email:         req.GetEmail(),
+		Name:          req.GetName(),
+		Picture:       req.GetPicture(),
+		Password:      req.GetPassword(),
+		Username:      req.GetUsername(),
+		EmailVerified: req.GetEmailVerified(),
 	}
 
 	resp, err := s.service.CreateUser(ctx, dtoReq)
@@ -57,6 +64,7 @@ func (s *UserServer) ValidateCredentials(ctx context.Context, req *userv1.Valida
 		Email:   resp.Email,
 		Name:    resp.Name,
 		Picture: resp.Picture,
+		Role:    resp.Role,
 	}, nil
 }
 
@@ -70,7 +78,12 @@ func (s *UserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*
 }
 
 func (s *UserServer) GetUserByEmail(ctx context.Context, req *userv1.GetUserByEmailRequest) (*userv1.User, error) {
-	resp, err := s.service.GetUserByEmail(ctx, req.GetEmail())
+	lookup := s.service.GetUserByEmail
+	if req.GetReactivate() {
+		lookup = s.service.GetUserByEmailForLogin
+	}
+
+	resp, err := lookup(ctx, req.GetEmail())
 	if err != nil {
 		return nil, s.toGRPCError(err)
 	}
@@ -87,9 +100,18 @@ func (s *UserServer) GetUserProfile(ctx context.Context, req *userv1.GetUserProf
 	return toProtoUserProfile(resp), nil
 }
 
+func (s *UserServer) GetUserByUsername(ctx context.Context, req *userv1.GetUserByUsernameRequest) (*userv1.UserProfile, error) {
+	resp, err := s.service.GetUserProfileByUsername(ctx, req.GetUsername())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return toProtoUserProfile(resp), nil
+}
+
 func (s *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest) (*userv1.User, error) {
 	if req.GetActorId() == "" || req.GetActorId() != req.GetId() {
-		return nil, status.Error(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess.Message)
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
 	}
 
 	dtoReq := &dto.UpdateUserRequest{}
@@ -114,6 +136,10 @@ func (s *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserReque
 		value := req.GetWebsite().GetValue()
 		dtoReq.Website = &value
 	}
+	if req.GetUsername() != nil {
+		value := req.GetUsername().GetValue()
+		dtoReq.Username = &value
+	}
 
 	resp, err := s.service.UpdateUser(ctx, req.GetId(), dtoReq)
 	if err != nil {
@@ -124,8 +150,9 @@ func (s *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserReque
 }
 
 func (s *UserServer) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*emptypb.Empty, error) {
-	if req.GetActorId() == "" || req.GetActorId() != req.GetId() {
-		return nil, status.Error(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess.Message)
+	// Admins may delete any account; everyone else only their own.
+	if !canManageAccount(req.GetId(), req.GetActorId(), req.GetActorRole()) {
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
 	}
 
 	if err := s.service.DeleteUser(ctx, req.GetId()); err != nil {
@@ -135,6 +162,104 @@ func (s *UserServer) DeleteUser(ctx context.Context, req *userv1.DeleteUserReque
 	return &emptypb.Empty{}, nil
 }
 
+func (s *UserServer) DeactivateUser(ctx context.Context, req *userv1.DeactivateUserRequest) (*emptypb.Empty, error) {
+	if !canManageAccount(req.GetId(), req.GetActorId(), req.GetActorRole()) {
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
+	}
+
+	if err := s.service.DeactivateUser(ctx, req.GetId(), req.GetActorId()); err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) ReactivateUser(ctx context.Context, req *userv1.ReactivateUserRequest) (*userv1.User, error) {
+	if !canManageAccount(req.GetId(), req.GetActorId(), req.GetActorRole()) {
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
+	}
+
+	resp, err := s.service.ReactivateUser(ctx, req.GetId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return toProtoUser(resp), nil
+}
+
+// canManageAccount reports whether the actor may change id's account state:
+// admins may act on anyone, everyone else only on themselves.
+func canManageAccount(id, actorID, actorRole string) bool {
+	if actorID == "" {
+		return false
+	}
+	return actorID == id || actorRole == entities.RoleAdmin
+}
+
+func (s *UserServer) VerifyEmail(ctx context.Context, req *userv1.VerifyEmailRequest) (*userv1.User, error) {
+	if req.GetActorId() == "" || req.GetActorRole() != entities.RoleAdmin {
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
+	}
+
+	resp, err := s.service.VerifyEmail(ctx, req.GetId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return toProtoUser(resp), nil
+}
+
+func (s *UserServer) ChangeEmail(ctx context.Context, req *userv1.ChangeEmailRequest) (*userv1.ChangeEmailResponse, error) {
+	if req.GetActorId() == "" || req.GetActorId() != req.GetId() {
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
+	}
+
+	resp, err := s.service.ChangeEmail(ctx, req.GetId(), &dto.ChangeEmailRequest{Email: req.GetEmail()})
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &userv1.ChangeEmailResponse{PendingEmail: resp.PendingEmail, ExpiresAt: toTimestamp(resp.ExpiresAt)}, nil
+}
+
+func (s *UserServer) VerifyEmailChange(ctx context.Context, req *userv1.VerifyEmailChangeRequest) (*userv1.User, error) {
+	if req.GetActorId() == "" || req.GetActorId() != req.GetId() {
+		return nil, appStatus(codes.PermissionDenied, appErrors.ErrUnauthorizedAccess)
+	}
+
+	resp, err := s.service.VerifyEmailChange(ctx, req.GetId(), &dto.VerifyEmailChangeRequest{Token: req.GetToken()})
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return toProtoUser(resp), nil
+}
+
+func (s *UserServer) TouchLastSeen(ctx context.Context, req *userv1.TouchLastSeenRequest) (*emptypb.Empty, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := s.service.TouchLastSeen(ctx, req.GetId()); err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) GetUsersBatch(ctx context.Context, req *userv1.GetUsersBatchRequest) (*userv1.GetUsersBatchResponse, error) {
+	profiles, err := s.service.GetUsersBatch(ctx, req.GetIds())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	users := make(map[string]*userv1.UserProfile, len(profiles))
+	for id, profile := range profiles {
+		users[id] = toProtoUserProfile(profile)
+	}
+	return &userv1.GetUsersBatchResponse{Users: users}, nil
+}
+
 func (s *UserServer) ListUsers(ctx context.Context, req *userv1.ListUsersRequest) (*userv1.ListUsersResponse, error) {
 	limit := int(req.GetLimit())
 	offset := int(req.GetOffset())
@@ -146,8 +271,19 @@ func (s *UserServer) ListUsers(ctx context.Context, req *userv1.ListUsersRequest
 	}
 
 	dtoReq := &dto.ListUsersRequest{
-		Limit:  limit,
-		Offset: offset,
+		Limit:           limit,
+		Offset:          offset,
+		Sort:            req.GetSort(),
+		Order:           req.GetOrder(),
+		IncludeInactive: req.GetIncludeInactive(),
+	}
+	if req.GetCreatedAfter() != nil {
+		createdAfter := req.GetCreatedAfter().AsTime()
+		dtoReq.CreatedAfter = &createdAfter
+	}
+	if req.GetCreatedBefore() != nil {
+		createdBefore := req.GetCreatedBefore().AsTime()
+		dtoReq.CreatedBefore = &createdBefore
 	}
 
 	resp, err := s.service.ListUsers(ctx, dtoReq)
@@ -160,7 +296,7 @@ func (s *UserServer) ListUsers(ctx context.Context, req *userv1.ListUsersRequest
 
 func (s *UserServer) SearchUsers(ctx context.Context, req *userv1.SearchUsersRequest) (*userv1.ListUsersResponse, error) {
 	if req.GetQuery() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 
 	limit := int(req.GetLimit())
@@ -197,7 +333,7 @@ func (s *UserServer) GetStats(ctx context.Context, _ *emptypb.Empty) (*userv1.Us
 
 func (s *UserServer) Follow(ctx context.Context, req *userv1.FollowRequest) (*emptypb.Empty, error) {
 	if req.GetFollowerId() == "" || req.GetFolloweeId() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 	if err := s.service.Follow(ctx, req.GetFollowerId(), req.GetFolloweeId()); err != nil {
 		return nil, s.toGRPCError(err)
@@ -207,7 +343,7 @@ func (s *UserServer) Follow(ctx context.Context, req *userv1.FollowRequest) (*em
 
 func (s *UserServer) Unfollow(ctx context.Context, req *userv1.UnfollowRequest) (*emptypb.Empty, error) {
 	if req.GetFollowerId() == "" || req.GetFolloweeId() == "" {
-		return nil, status.Error(codes.InvalidArgument, appErrors.ErrInvalidRequest.Message)
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
 	}
 	if err := s.service.Unfollow(ctx, req.GetFollowerId(), req.GetFolloweeId()); err != nil {
 		return nil, s.toGRPCError(err)
@@ -224,11 +360,15 @@ func (s *UserServer) GetFollowers(ctx context.Context, req *userv1.GetFollowersR
 	if err != nil {
 		return nil, s.toGRPCError(err)
 	}
+	total, err := s.service.CountFollowers(ctx, req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
 	profiles := make([]*userv1.UserProfile, 0, len(users))
 	for _, u := range users {
 		profiles = append(profiles, toProtoUserProfile(u))
 	}
-	return &userv1.ListFollowResponse{Users: profiles, NextCursor: nextCursor}, nil
+	return &userv1.ListFollowResponse{Users: profiles, NextCursor: nextCursor, Total: total}, nil
 }
 
 func (s *UserServer) GetFollowing(ctx context.Context, req *userv1.GetFollowingRequest) (*userv1.ListFollowResponse, error) {
@@ -240,11 +380,15 @@ func (s *UserServer) GetFollowing(ctx context.Context, req *userv1.GetFollowingR
 	if err != nil {
 		return nil, s.toGRPCError(err)
 	}
+	total, err := s.service.CountFollowing(ctx, req.GetUserId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
 	profiles := make([]*userv1.UserProfile, 0, len(users))
 	for _, u := range users {
 		profiles = append(profiles, toProtoUserProfile(u))
 	}
-	return &userv1.ListFollowResponse{Users: profiles, NextCursor: nextCursor}, nil
+	return &userv1.ListFollowResponse{Users: profiles, NextCursor: nextCursor, Total: total}, nil
 }
 
 func (s *UserServer) AreFollowed(ctx context.Context, req *userv1.AreFollowedRequest) (*userv1.AreFollowedResponse, error) {
@@ -255,7 +399,49 @@ func (s *UserServer) AreFollowed(ctx context.Context, req *userv1.AreFollowedReq
 	return &userv1.AreFollowedResponse{FollowedIds: ids}, nil
 }
 
+func (s *UserServer) Block(ctx context.Context, req *userv1.BlockRequest) (*emptypb.Empty, error) {
+	if req.GetBlockerId() == "" || req.GetBlockedId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+	if err := s.service.Block(ctx, req.GetBlockerId(), req.GetBlockedId()); err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) Unblock(ctx context.Context, req *userv1.UnblockRequest) (*emptypb.Empty, error) {
+	if req.GetBlockerId() == "" || req.GetBlockedId() == "" {
+		return nil, appStatus(codes.InvalidArgument, appErrors.ErrInvalidRequest)
+	}
+	if err := s.service.Unblock(ctx, req.GetBlockerId(), req.GetBlockedId()); err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) ListBlocked(ctx context.Context, req *userv1.ListBlockedRequest) (*userv1.ListBlockedResponse, error) {
+	limit := int(req.GetLimit())
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	users, nextCursor, err := s.service.ListBlocked(ctx, req.GetUserId(), limit, req.GetCursor())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	profiles := make([]*userv1.UserProfile, 0, len(users))
+	for _, u := range users {
+		profiles = append(profiles, toProtoUserProfile(u))
+	}
+	return &userv1.ListBlockedResponse{Users: profiles, NextCursor: nextCursor}, nil
+}
+
+// HealthCheck fails with Unavailable when a backing dependency is unreachable,
+// so callers see more than whether the process is up.
 func (s *UserServer) HealthCheck(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if _, err := s.health.Run(ctx); err != nil {
+		s.logger.Warn("Health check failed: " + err.Error())
+		return nil, status.Error(codes.Unavailable, "dependency check failed")
+	}
 	return &emptypb.Empty{}, nil
 }
 
@@ -265,24 +451,24 @@ func (s *UserServer) toGRPCError(err error) error {
 	}
 
 	if userErr, ok := err.(*appErrors.UserError); ok {
+		code := codes.Internal
 		switch userErr.StatusCode {
 		case http.StatusBadRequest:
-			return status.Error(codes.InvalidArgument, userErr.Message)
+			code = codes.InvalidArgument
 		case http.StatusUnauthorized:
-			return status.Error(codes.Unauthenticated, userErr.Message)
+			code = codes.Unauthenticated
 		case http.StatusForbidden:
-			return status.Error(codes.PermissionDenied, userErr.Message)
+			code = codes.PermissionDenied
 		case http.StatusNotFound:
-			return status.Error(codes.NotFound, userErr.Message)
+			code = codes.NotFound
 		case http.StatusConflict:
-			return status.Error(codes.AlreadyExists, userErr.Message)
+			code = codes.AlreadyExists
 		case http.StatusTooManyRequests:
-			return status.Error(codes.ResourceExhausted, userErr.Message)
+			code = codes.ResourceExhausted
 		case http.StatusServiceUnavailable:
-			return status.Error(codes.Unavailable, userErr.Message)
-		default:
-			return status.Error(codes.Internal, userErr.Message)
+			code = codes.Unavailable
 		}
+		return appStatus(code, userErr)
 	}
 
 	s.logger.Error("unexpected error: " + err.Error())
@@ -295,16 +481,22 @@ func toProtoUser(user *dto.UserResponse) *userv1.User {
 	}
 
 	return &userv1.User{
-		Id:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Picture:   user.Picture,
-		Bio:       user.Bio,
-		Location:  user.Location,
-		Website:   user.Website,
-		IsActive:  user.IsActive,
-		CreatedAt: toTimestamp(user.CreatedAt),
-		UpdatedAt: toTimestamp(user.UpdatedAt),
+		Id:              user.ID,
+		Email:           user.Email,
+		Name:            user.Name,
+		Username:        user.Username,
+		Picture:         user.Picture,
+		Bio:             user.Bio,
+		Location:        user.Location,
+		Website:         user.Website,
+		Role:            user.Role,
+		EmailVerified:   user.EmailVerified,
+		EmailVerifiedAt: toTimestampPtr(user.EmailVerifiedAt),
+		LastSeenAt:      toTimestampPtr(user.LastSeenAt),
+		Status:          user.Status,
+		IsActive:        user.IsActive,
+		CreatedAt:       toTimestamp(user.CreatedAt),
+		UpdatedAt:       toTimestamp(user.UpdatedAt),
 	}
 }
 
@@ -320,6 +512,7 @@ func toProtoUserProfile(profile *dto.UserProfileResponse) *userv1.UserProfile {
 	return &userv1.UserProfile{
 		Id:       profile.ID,
 		Name:     profile.Name,
+		Username: profile.Username,
 		Picture:  profile.Picture,
 		Bio:      profile.Bio,
 		Location: profile.Location,
@@ -340,10 +533,12 @@ func toProtoListUsers(resp *dto.ListUsersResponse) *userv1.ListUsersResponse {
 		protoUsers = append(protoUsers, &userv1.User{
 			Id:        user.ID,
 			Name:      user.Name,
+			Username:  user.Username,
 			Picture:   user.Picture,
 			Bio:       user.Bio,
 			Location:  user.Location,
 			Website:   user.Website,
+			Role:      user.Role,
 			IsActive:  user.IsActive,
 			CreatedAt: toTimestamp(user.CreatedAt),
 			UpdatedAt: toTimestamp(user.UpdatedAt),
@@ -351,11 +546,19 @@ func toProtoListUsers(resp *dto.ListUsersResponse) *userv1.ListUsersResponse {
 	}
 
 	return &userv1.ListUsersResponse{
-		Users:  protoUsers,
-		Limit:  int32(resp.Limit),
-		Offset: int32(resp.Offset),
-		Total:  int32(resp.Total),
+		Users:   protoUsers,
+		Limit:   int32(resp.Limit),
+		Offset:  int32(resp.Offset),
+		Total:   int32(resp.Total),
+		HasMore: resp.HasMore,
+	}
+}
+
+func toTimestampPtr(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
 	}
+	return toTimestamp(*t)
 }
 
 func toTimestamp(t time.Time) *timestamppb.Timestamp {
@@ -364,3 +567,19 @@ func toTimestamp(t time.Time) *timestamppb.Timestamp {
 	}
 	return timestamppb.New(t)
 }
+
+// appStatus builds the gRPC status for an application error. The error code and
+// HTTP status travel as an ErrorInfo detail so the gateway can answer with them
+// instead of reducing every failure to its gRPC code.
+func appStatus(code codes.Code, userErr *appErrors.UserError) error {
+	st := status.New(code, userErr.Message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   userErr.Code,
+		Domain:   "user-service",
+		Metadata: map[string]string{"http_status": strconv.Itoa(userErr.StatusCode)},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -8,7 +8,10 @@ import (
 	"user-service/internal/application/dto"
 	"user-service/internal/application/errors"
 	"user-service/internal/application/services"
+	"user-service/internal/domain/entities"
+	"user-service/internal/interfaces/http/middleware"
 	"user-service/internal/interfaces/validators"
+	"user-service/pkg/health"
 	"user-service/pkg/logger"
 	"user-service/pkg/utils"
 )
@@ -16,13 +19,15 @@ import (
 type UserHandler struct {
 	userService *services.UserService
 	validator   *validators.UserValidator
+	health      *health.Checker
 	logger      *logger.Logger
 }
 
-func NewUserHandler(userService *services.UserService, logger *logger.Logger) *UserHandler {
+func NewUserHandler(userService *services.UserService, checker *health.Checker, logger *logger.Logger) *UserHandler {
 	return &UserHandler{
 		userService: userService,
 		validator:   validators.NewUserValidator(),
+		health:      checker,
 		logger:      logger,
 	}
 }
@@ -64,7 +69,11 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	response, err := h.userService.GetUser(c.Request.Context(), id)
+	getUser := h.userService.GetUser
+	if id == c.GetString(middleware.ContextUserIDKey) {
+		getUser = h.userService.GetOwnUser
+	}
+	response, err := getUser(c.Request.Context(), id)
 	if err != nil {
 		if userErr, ok := err.(*errors.UserError); ok {
 			utils.ErrorResponse(c, userErr)
@@ -100,9 +109,94 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User profile retrieved successfully", response)
 }
 
+func (h *UserHandler) GetUserProfileByUsername(c *gin.Context) {
+	username := c.Param("username")
+
+	if username == "" {
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.userService.GetUserProfileByUsername(c.Request.Context(), username)
+	if err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in get user profile by username: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User profile retrieved successfully", response)
+}
+
+// GetUserByEmail backs the internal by-email lookup. Every miss, including a
+// missing email parameter, is a plain 404.
+func (h *UserHandler) GetUserByEmail(c *gin.Context) {
+	response, err := h.userService.GetUserByEmail(c.Request.Context(), c.Query("email"))
+	if err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in get user by email: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User retrieved successfully", response)
+}
+
+// GetFollowerIDs backs the internal follower fan-out used by
+// notification-service.
+func (h *UserHandler) GetFollowerIDs(c *gin.Context) {
+	var req dto.GetFollowerIDsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid follower IDs request: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.userService.GetFollowerIDs(c.Request.Context(), c.Param("id"), req.Limit, req.Cursor)
+	if err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in get follower IDs: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Follower IDs retrieved successfully", response)
+}
+
+func (h *UserHandler) GetUsersBatch(c *gin.Context) {
+	var req dto.GetUsersBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid users batch request: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	users, err := h.userService.GetUsersBatch(c.Request.Context(), req.IDs)
+	if err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in get users batch: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", gin.H{"users": users})
+}
+
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	id := c.Param("id")
-	userID := c.GetHeader("X-User-ID")
+	userID := c.GetString(middleware.ContextUserIDKey)
 
 	// Check if user is updating their own profile
 	if id != userID {
@@ -138,12 +232,91 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User updated successfully", response)
 }
 
+// VerifyEmail lets an admin mark a user's email verified by hand.
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	if c.GetString(middleware.ContextUserRoleKey) != entities.RoleAdmin {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	response, err := h.userService.VerifyEmail(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in verify email: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verified successfully", response)
+}
+
+// ChangeEmail starts moving the caller to a new address; it takes effect once
+// VerifyEmailChange confirms the token sent there.
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	id := c.Param("id")
+	if id != c.GetString(middleware.ContextUserIDKey) {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid change email request: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidEmail)
+		return
+	}
+
+	response, err := h.userService.ChangeEmail(c.Request.Context(), id, &req)
+	if err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in change email: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Verification sent to the new email", response)
+}
+
+func (h *UserHandler) VerifyEmailChange(c *gin.Context) {
+	id := c.Param("id")
+	if id != c.GetString(middleware.ContextUserIDKey) {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	var req dto.VerifyEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid verify email change request: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
+	response, err := h.userService.VerifyEmailChange(c.Request.Context(), id, &req)
+	if err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in verify email change: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email changed successfully", response)
+}
+
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
-	userID := c.GetHeader("X-User-ID")
+	userID := c.GetString(middleware.ContextUserIDKey)
 
-	// Check if user is deleting their own account
-	if id != userID {
+	// Check if user is deleting their own account; admins may delete any
+	if id != userID && c.GetString(middleware.ContextUserRoleKey) != entities.RoleAdmin {
 		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
 		return
 	}
@@ -162,6 +335,50 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
 }
 
+func (h *UserHandler) DeactivateUser(c *gin.Context) {
+	id := c.Param("id")
+	actorID := c.GetString(middleware.ContextUserIDKey)
+
+	if id != actorID && c.GetString(middleware.ContextUserRoleKey) != entities.RoleAdmin {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	if err := h.userService.DeactivateUser(c.Request.Context(), id, actorID); err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in deactivate user: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User deactivated successfully", nil)
+}
+
+func (h *UserHandler) ReactivateUser(c *gin.Context) {
+	id := c.Param("id")
+
+	if id != c.GetString(middleware.ContextUserIDKey) && c.GetString(middleware.ContextUserRoleKey) != entities.RoleAdmin {
+		utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
+		return
+	}
+
+	response, err := h.userService.ReactivateUser(c.Request.Context(), id)
+	if err != nil {
+		if userErr, ok := err.(*errors.UserError); ok {
+			utils.ErrorResponse(c, userErr)
+		} else {
+			h.logger.Error("Unexpected error in reactivate user: " + err.Error())
+			utils.ErrorResponse(c, errors.ErrServiceUnavailable)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User reactivated successfully", response)
+}
+
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	var req dto.ListUsersRequest
 
@@ -171,6 +388,12 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		return
 	}
 
+	if err := h.validator.ValidateListUsersRequest(&req); err != nil {
+		h.logger.Warn("List users validation failed: " + err.Error())
+		utils.ErrorResponse(c, errors.ErrInvalidRequest)
+		return
+	}
+
 	// Set defaults
 	if req.Limit == 0 {
 		req.Limit = 20
@@ -239,9 +462,32 @@ func (h *UserHandler) GetStats(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User statistics retrieved successfully", response)
 }
 
+// HealthCheck is the liveness probe: it answers 200 whenever the process is
+// up, without touching any dependency.
 func (h *UserHandler) HealthCheck(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User service is healthy", gin.H{
 		"service": "user-service",
 		"status":  "running",
 	})
 }
+
+// Readiness is the readiness probe: it checks the backing dependencies and
+// returns 503 when any of them is unreachable.
+func (h *UserHandler) Readiness(c *gin.Context) {
+	dependencies, err := h.health.Run(c.Request.Context())
+	if err != nil {
+		h.logger.Warn("Readiness check failed: " + err.Error())
+		utils.SuccessResponse(c, http.StatusServiceUnavailable, "User service is not ready", gin.H{
+			"service":      "user-service",
+			"status":       health.StatusUnhealthy,
+			"dependencies": dependencies,
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User service is ready", gin.H{
+		"service":      "user-service",
+		"status":       health.StatusHealthy,
+		"dependencies": dependencies,
+	})
+}
@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"net/http"
+	"crypto/subtle"
 
 	"user-service/internal/application/errors"
 	"user-service/pkg/utils"
@@ -9,45 +9,81 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+const (
+	// InternalTokenHeader carries the shared secret of service-to-service calls.
+	InternalTokenHeader = "X-Internal-Token"
+
+	// ContextUserIDKey and ContextUserRoleKey hold the caller identity once it
+	// has been accepted by AuthMiddleware or OptionalAuthMiddleware.
+	ContextUserIDKey   = "userID"
+	ContextUserRoleKey = "userRole"
+
+	// trustModeInsecureDev lets a deployment without INTERNAL_SERVICE_TOKEN
+	// trust X-User-ID from anyone. It is rejected in production by config.
+	trustModeInsecureDev = "insecure_dev"
+)
+
+// AuthMiddleware requires a caller identity. X-User-ID and X-User-Role are
+// honoured only from a trusted caller (see trustedCaller); anyone else gets
+// 403, so reaching the service directly cannot impersonate a user.
+func AuthMiddleware(token, trustMode string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get user ID from header (set by API Gateway)
 		userID := c.GetHeader("X-User-ID")
-		if userID == "" {
+		if userID == "" || !trustedCaller(c, token, trustMode) {
 			utils.ErrorResponse(c, errors.ErrUnauthorizedAccess)
 			c.Abort()
 			return
 		}
 
-		// Set user ID in context for handlers to use
-		c.Set("userID", userID)
+		setCaller(c, userID)
 		c.Next()
 	}
 }
 
-func OptionalAuthMiddleware() gin.HandlerFunc {
+// ServiceAuthMiddleware admits only callers presenting token. Everyone else,
+// and every caller when token is empty, gets a 404 so internal routes stay
+// indistinguishable from missing ones on the public surface.
+func ServiceAuthMiddleware(token string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get user ID from header (optional)
-		userID := c.GetHeader("X-User-ID")
-		if userID != "" {
-			c.Set("userID", userID)
+		if !validInternalToken(c, token) {
+			utils.ErrorResponse(c, errors.ErrRouteNotFound)
+			c.Abort()
+			return
 		}
 		c.Next()
 	}
 }
 
-func CORS() gin.HandlerFunc {
+// OptionalAuthMiddleware records the caller identity when a trusted caller
+// sends one and otherwise treats the request as anonymous.
+func OptionalAuthMiddleware(token, trustMode string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-User-ID")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
+		if userID := c.GetHeader("X-User-ID"); userID != "" && trustedCaller(c, token, trustMode) {
+			setCaller(c, userID)
 		}
-
 		c.Next()
 	}
 }
+
+// trustedCaller reports whether identity headers on the request may be
+// believed: the caller presents the internal token or, when no token is
+// configured, the service runs in insecure_dev mode. Without a token any
+// other mode fails closed.
+func trustedCaller(c *gin.Context, token, trustMode string) bool {
+	if token != "" {
+		return validInternalToken(c, token)
+	}
+	return trustMode == trustModeInsecureDev
+}
+
+func validInternalToken(c *gin.Context, token string) bool {
+	provided := c.GetHeader(InternalTokenHeader)
+	return token != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
+
+func setCaller(c *gin.Context, userID string) {
+	c.Set(ContextUserIDKey, userID)
+	if role := c.GetHeader("X-User-Role"); role != "" {
+		c.Set(ContextUserRoleKey, role)
+	}
+}
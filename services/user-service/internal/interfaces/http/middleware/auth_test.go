@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serveInternal(token, provided string) int {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/internal", ServiceAuthMiddleware(token), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/internal", nil)
+	if provided != "" {
+		req.Header.Set(InternalTokenHeader, provided)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestServiceAuthMiddleware(t *testing.T) {
+	const token = "0123456789abcdef0123456789abcdef"
+
+	if code := serveInternal(token, token); code != http.StatusNoContent {
+		t.Fatalf("expected the matching token to pass, got %d", code)
+	}
+	if code := serveInternal(token, ""); code != http.StatusNotFound {
+		t.Fatalf("expected a missing token to look like a missing route, got %d", code)
+	}
+	if code := serveInternal(token, "wrong"); code != http.StatusNotFound {
+		t.Fatalf("expected a wrong token to look like a missing route, got %d", code)
+	}
+	if code := serveInternal("", ""); code != http.StatusNotFound {
+		t.Fatalf("expected internal routes to be disabled without a configured token, got %d", code)
+	}
+}
+
+func serveAuthenticated(token, trustMode, providedToken string) (int, string) {
+	gin.SetMode(gin.TestMode)
+
+	var seen string
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(token, trustMode), func(c *gin.Context) {
+		seen = c.GetString(ContextUserIDKey)
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("X-User-ID", "user-1")
+	if providedToken != "" {
+		req.Header.Set(InternalTokenHeader, providedToken)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code, seen
+}
+
+func TestAuthMiddlewareHonoursUserIDOnlyFromTrustedCallers(t *testing.T) {
+	const token = "0123456789abcdef0123456789abcdef"
+
+	if code, userID := serveAuthenticated(token, "private_network", token); code != http.StatusNoContent || userID != "user-1" {
+		t.Fatalf("expected the gateway's identity to be accepted, got %d %q", code, userID)
+	}
+	if code, _ := serveAuthenticated(token, "private_network", ""); code != http.StatusForbidden {
+		t.Fatalf("expected X-User-ID without the token to be rejected, got %d", code)
+	}
+	if code, _ := serveAuthenticated(token, "insecure_dev", "wrong"); code != http.StatusForbidden {
+		t.Fatalf("expected a wrong token to be rejected even in insecure_dev, got %d", code)
+	}
+	if code, _ := serveAuthenticated("", "private_network", ""); code != http.StatusForbidden {
+		t.Fatalf("expected X-User-ID to be rejected without a configured token, got %d", code)
+	}
+	if code, userID := serveAuthenticated("", "insecure_dev", ""); code != http.StatusNoContent || userID != "user-1" {
+		t.Fatalf("expected insecure_dev to trust X-User-ID without a token, got %d %q", code, userID)
+	}
+}
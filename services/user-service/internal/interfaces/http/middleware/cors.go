@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"user-service/internal/config"
+)
+
+// CORS sets cross-origin headers for origins in cfg.AllowedOrigins. The
+// request's Origin is echoed back only when it is on the allowlist; "*" is
+// honoured only when configured explicitly and never alongside credentials.
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := strings.TrimSpace(c.GetHeader("Origin"))
+		if origin != "" {
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+
+		if allowed := resolveAllowedOrigin(origin, cfg); allowed != "" {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", allowed)
+			if cfg.AllowCredentials && allowed != "*" {
+				c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-User-ID")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func resolveAllowedOrigin(origin string, cfg config.CORSConfig) string {
+	if origin == "" {
+		return ""
+	}
+	for _, allowed := range cfg.AllowedOrigins {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" {
+			// Config validation rejects this pairing; skip it here too so a
+			// wildcard can never grant credentialed access.
+			if cfg.AllowCredentials {
+				continue
+			}
+			return "*"
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
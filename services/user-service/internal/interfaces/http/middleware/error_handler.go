@@ -1,16 +1,16 @@
 package middleware
 
 import (
-	"strconv"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"user-service/internal/application/errors"
-	"user-service/pkg/logger"
+	logpkg "user-service/pkg/logger"
 	"user-service/pkg/utils"
 )
 
-func ErrorHandler(logger *logger.Logger) gin.HandlerFunc {
+func ErrorHandler(logger *logpkg.Logger) gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		if err, ok := recovered.(string); ok {
 			logger.Error("Panic recovered: " + err)
@@ -25,13 +25,30 @@ func ErrorHandler(logger *logger.Logger) gin.HandlerFunc {
 	})
 }
 
-func RequestLogger(logger *logger.Logger) gin.HandlerFunc {
+func RequestLogger(logger *logpkg.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.Info(
-			"Request: " + param.Method + " " + param.Path +
-				" | Status: " + strconv.Itoa(param.StatusCode) +
-				" | Latency: " + param.Latency.String(),
+		logger.Info("Request: "+param.Method+" "+param.Path,
+			logpkg.F("status", param.StatusCode),
+			logpkg.F("latency_ms", param.Latency.Milliseconds()),
+			logpkg.F("request_id", requestIDFromHeader(param.Request)),
+			logpkg.F("user_id", param.Request.Header.Get("X-User-ID")),
 		)
 		return ""
 	})
 }
+
+// requestIDFromHeader returns the X-Request-ID the API gateway forwarded, or
+// "-" when it is missing or malformed, so access log lines can be correlated
+// with the gateway's.
+func requestIDFromHeader(r *http.Request) string {
+	id := r.Header.Get("X-Request-ID")
+	if id == "" || len(id) > 128 {
+		return "-"
+	}
+	for _, ch := range id {
+		if ch <= ' ' || ch > '~' {
+			return "-"
+		}
+	}
+	return id
+}
@@ -4,22 +4,25 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"user-service/internal/application/services"
+	"user-service/internal/config"
 	"user-service/internal/interfaces/http/handlers"
 	"user-service/internal/interfaces/http/middleware"
+	"user-service/pkg/health"
 	"user-service/pkg/logger"
 )
 
-func SetupUserRoutes(router *gin.Engine, userService *services.UserService, logger *logger.Logger) {
+func SetupUserRoutes(router *gin.Engine, userService *services.UserService, internalServiceToken, trustMode string, cors config.CORSConfig, checker *health.Checker, logger *logger.Logger) {
 	// Initialize handlers
-	userHandler := handlers.NewUserHandler(userService, logger)
+	userHandler := handlers.NewUserHandler(userService, checker, logger)
 
 	// Add global middleware
 	router.Use(middleware.ErrorHandler(logger))
 	router.Use(middleware.RequestLogger(logger))
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORS(cors))
 
 	// Health check (no auth required)
 	router.GET("/health", userHandler.HealthCheck)
+	router.GET("/ready", userHandler.Readiness)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
@@ -30,16 +33,31 @@ func SetupUserRoutes(router *gin.Engine, userService *services.UserService, logg
 			users.GET("/search", userHandler.SearchUsers)
 			users.GET("/stats", userHandler.GetStats)
 			users.GET("/:id/profile", userHandler.GetUserProfile)
+			users.GET("/by-username/:username", userHandler.GetUserProfileByUsername)
+
+			// Internal routes (service-to-service only; 404 without the token)
+			internal := users.Group("")
+			internal.Use(middleware.ServiceAuthMiddleware(internalServiceToken))
+			{
+				internal.POST("/batch", userHandler.GetUsersBatch)
+				internal.GET("/by-email", userHandler.GetUserByEmail)
+				internal.GET("/:id/follower-ids", userHandler.GetFollowerIDs)
+			}
 
 			// Protected routes (auth required)
 			protected := users.Group("")
-			protected.Use(middleware.AuthMiddleware())
+			protected.Use(middleware.AuthMiddleware(internalServiceToken, trustMode))
 			{
 				protected.POST("", userHandler.CreateUser)
 				protected.GET("", userHandler.ListUsers)
 				protected.GET("/:id", userHandler.GetUser)
 				protected.PUT("/:id", userHandler.UpdateUser)
 				protected.DELETE("/:id", userHandler.DeleteUser)
+				protected.POST("/:id/verify-email", userHandler.VerifyEmail)
+				protected.POST("/:id/change-email", userHandler.ChangeEmail)
+				protected.POST("/:id/verify-email-change", userHandler.VerifyEmailChange)
+				protected.POST("/:id/deactivate", userHandler.DeactivateUser)
+				protected.POST("/:id/reactivate", userHandler.ReactivateUser)
 			}
 		}
 	}
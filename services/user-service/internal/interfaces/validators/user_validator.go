@@ -36,6 +36,10 @@ func (v *UserValidator) ValidateCreateUserRequest(req *dto.CreateUserRequest) er
 		return fmt.Errorf("name must be less than 100 characters")
 	}
 
+	if req.Username != "" && !isValidUsername(req.Username) {
+		return fmt.Errorf("username must be 3-30 characters of a-z, 0-9 or _")
+	}
+
 	return nil
 }
 
@@ -63,6 +67,30 @@ func (v *UserValidator) ValidateUpdateUserRequest(req *dto.UpdateUserRequest) er
 		}
 	}
 
+	if req.Username != nil && *req.Username != "" && !isValidUsername(*req.Username) {
+		return fmt.Errorf("username must be 3-30 characters of a-z, 0-9 or _")
+	}
+
+	return nil
+}
+
+func (v *UserValidator) ValidateListUsersRequest(req *dto.ListUsersRequest) error {
+	switch req.Sort {
+	case "", "created_at", "name", "email":
+	default:
+		return fmt.Errorf("sort must be one of created_at, name or email")
+	}
+
+	switch req.Order {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("order must be asc or desc")
+	}
+
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedAfter.Before(*req.CreatedBefore) {
+		return fmt.Errorf("created_after must be before created_before")
+	}
+
 	return nil
 }
 
@@ -78,6 +106,13 @@ func (v *UserValidator) ValidateSearchUsersRequest(req *dto.SearchUsersRequest)
 	return nil
 }
 
+// isValidUsername accepts mixed-case input; usernames are lowercased before
+// they are stored.
+func isValidUsername(username string) bool {
+	usernameRegex := regexp.MustCompile(`^[a-z0-9_]{3,30}$`)
+	return usernameRegex.MatchString(strings.ToLower(strings.TrimSpace(username)))
+}
+
 func isValidEmail(email string) bool {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	return emailRegex.MatchString(email)
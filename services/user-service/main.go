@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
@@ -19,16 +20,22 @@ import (
 
 	"user-service/internal/application/services"
 	"user-service/internal/config"
+	"user-service/internal/domain/repositories"
+	"user-service/internal/infrastructure/email"
 	"user-service/internal/infrastructure/postgres"
 	grpcinterface "user-service/internal/interfaces/grpc"
 	"user-service/internal/interfaces/http/routes"
+	"user-service/pkg/health"
 	"user-service/pkg/logger"
 	"user-service/pkg/metrics"
+	"user-service/pkg/retry"
+	"user-service/pkg/tracing"
 
+	"github.com/nikitashilov/microblog_grpc/proto/requestid"
 	userv1 "github.com/nikitashilov/microblog_grpc/proto/user/v1"
 	grpc "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/health"
+	grpchealth "google.golang.org/grpc/health"
 	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 	grpc_reflection "google.golang.org/grpc/reflection"
@@ -46,11 +53,30 @@ func main() {
 	}
 
 	// Initialize logger
-	appLogger := logger.New(cfg.LogLevel)
-	metrics.Init()
+	appLogger := logger.NewWithOptions(logger.Options{Service: "user-service", Level: cfg.LogLevel, Format: cfg.LogFormat})
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{ServiceName: "user-service", Endpoint: cfg.Tracing.Endpoint, SampleRatio: cfg.Tracing.SampleRatio})
+	if err != nil {
+		appLogger.Fatal("Failed to initialize tracing: " + err.Error())
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			appLogger.Warn("Failed to flush traces", logger.Err(err))
+		}
+	}()
+	if cfg.MetricsEnabled {
+		metrics.Init()
+	}
 
 	// Initialize database connection
-	db, err := postgres.NewConnection(cfg.Database)
+	var db *sql.DB
+	err = retry.Do(context.Background(), startupRetry(cfg.StartupRetry), appLogger, "postgres", func(context.Context) error {
+		var err error
+		db, err = postgres.NewConnection(cfg.Database)
+		return err
+	})
 	if err != nil {
 		appLogger.Fatal("Failed to connect to database: " + err.Error())
 	}
@@ -61,12 +87,24 @@ func main() {
 		appLogger.Fatal("Failed to run migrations: " + err.Error())
 	}
 
+	healthChecker := health.NewChecker().Register("postgres", db.PingContext)
+
 	// Initialize repositories
 	userRepo := postgres.NewUserRepository(db)
 	followRepo := postgres.NewFollowRepository(db)
+	blockRepo := postgres.NewBlockRepository(db)
+
+	// Email changes need a mailer to deliver the confirmation token; without
+	// SMTP_HOST they are rejected before anything is stored.
+	var emailSender repositories.EmailChangeSender
+	if cfg.Email.SMTPHost != "" {
+		emailSender = email.NewSMTPSender(cfg.Email)
+	} else {
+		appLogger.Info("SMTP_HOST not set, email changes disabled")
+	}
 
 	// Initialize services
-	userService := services.NewUserService(userRepo, followRepo, appLogger)
+	userService := services.NewUserService(userRepo, followRepo, blockRepo, emailSender, appLogger)
 
 	// Setup gRPC server with options
 	grpcOptions := []grpc.ServerOption{
@@ -82,6 +120,8 @@ func main() {
 			Timeout:               1 * time.Second,
 		}),
 		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			tracing.UnaryServerInterceptor(),
 			metrics.UnaryServerInterceptor("user-service"),
 			unaryServerLoggingInterceptor(appLogger),
 		),
@@ -95,11 +135,11 @@ func main() {
 	}
 
 	grpcServer := grpc.NewServer(grpcOptions...)
-	userv1.RegisterUserServiceServer(grpcServer, grpcinterface.NewUserServer(userService, appLogger))
+	userv1.RegisterUserServiceServer(grpcServer, grpcinterface.NewUserServer(userService, healthChecker, appLogger))
 
 	// gRPC health server (grpc.health.v1.Health) — the signal Consul/Envoy and
 	// Kubernetes use to gate traffic to this instance. Mark SERVING once ready.
-	healthServer := health.NewServer()
+	healthServer := grpchealth.NewServer()
 	healthgrpc.RegisterHealthServer(grpcServer, healthServer)
 	healthServer.SetServingStatus("", healthgrpc.HealthCheckResponse_SERVING)
 	healthServer.SetServingStatus(grpcHealthServiceName, healthgrpc.HealthCheckResponse_SERVING)
@@ -123,11 +163,14 @@ func main() {
 	// Setup HTTP server
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(tracing.GinMiddleware())
 	router.Use(metrics.GinMiddleware("user-service"))
-	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	if cfg.MetricsEnabled {
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
 
 	// Setup routes
-	routes.SetupUserRoutes(router, userService, appLogger)
+	routes.SetupUserRoutes(router, userService, cfg.InternalServiceToken, cfg.InternalHTTPTrustMode, cfg.CORS, healthChecker, appLogger)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -171,16 +214,21 @@ func main() {
 }
 
 // unaryServerLoggingInterceptor logs gRPC server requests and responses
-func unaryServerLoggingInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
+func unaryServerLoggingInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 		resp, err := handler(ctx, req)
 		duration := time.Since(start)
 
+		fields := []logger.Field{
+			logger.F("method", info.FullMethod),
+			logger.F("request_id", requestid.FromContext(ctx)),
+			logger.F("duration_ms", duration.Milliseconds()),
+		}
 		if err != nil {
-			logger.Warn(fmt.Sprintf("gRPC method %s failed: %v (duration: %v)", info.FullMethod, err, duration))
+			log.Warn("gRPC method failed", append(fields, logger.Err(err))...)
 		} else {
-			logger.Debug(fmt.Sprintf("gRPC method %s succeeded (duration: %v)", info.FullMethod, duration))
+			log.Debug("gRPC method succeeded", fields...)
 		}
 
 		return resp, err
@@ -215,3 +263,13 @@ func buildServerTransportCredentials(tlsCfg config.GRPCTLSConfig) (credentials.T
 
 	return credentials.NewTLS(tlsConfig), nil
 }
+
+// startupRetry converts the configured limits for retry.Do.
+func startupRetry(cfg config.StartupRetryConfig) retry.Config {
+	return retry.Config{
+		Attempts:       cfg.Attempts,
+		InitialBackoff: time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+		MaxWait:        time.Duration(cfg.MaxWaitSeconds) * time.Second,
+	}
+}
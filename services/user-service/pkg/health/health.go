@@ -0,0 +1,66 @@
+// Package health checks the backing dependencies (database, cache, broker) a
+// service needs to serve traffic, for its HTTP and gRPC health endpoints.
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+
+	checkTimeout = 2 * time.Second
+)
+
+// Check reports whether one dependency is reachable.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs a fixed set of named dependency checks. A nil Checker has no
+// dependencies and is always healthy.
+type Checker struct {
+	checks []namedCheck
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Register adds a dependency check and returns the checker for chaining.
+func (c *Checker) Register(name string, check Check) *Checker {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+	return c
+}
+
+// Run executes every check, each bounded by a short timeout, and returns the
+// status of each dependency along with the first failure.
+func (c *Checker) Run(ctx context.Context) (map[string]string, error) {
+	statuses := make(map[string]string)
+	if c == nil {
+		return statuses, nil
+	}
+
+	var firstErr error
+	for _, nc := range c.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := nc.check(checkCtx)
+		cancel()
+
+		if err != nil {
+			statuses[nc.name] = StatusUnhealthy
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", nc.name, err)
+			}
+			continue
+		}
+		statuses[nc.name] = StatusHealthy
+	}
+	return statuses, firstErr
+}
@@ -0,0 +1,65 @@
+// Package retry waits for a backing dependency (database, cache, broker) to
+// become reachable during service startup.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-service/pkg/logger"
+)
+
+// Config bounds how long Do keeps trying. The backoff doubles after every
+// failed attempt up to MaxBackoff; MaxWait caps the total time spent.
+type Config struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxWait        time.Duration
+}
+
+// Do calls fn until it succeeds, cfg.Attempts calls have failed or cfg.MaxWait
+// has elapsed, logging every failed attempt. It returns the last error.
+func Do(ctx context.Context, cfg Config, log *logger.Logger, name string, fn func(ctx context.Context) error) error {
+	if cfg.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.MaxWait)
+		defer cancel()
+	}
+	attempts := cfg.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	backoff := cfg.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if attempt > 1 {
+				log.Info(name+" is available", logger.F("attempt", attempt))
+			}
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("%s unavailable after %d attempts: %w", name, attempt, err)
+		}
+
+		log.Warn(name+" is not available yet, retrying",
+			logger.F("attempt", attempt),
+			logger.F("max_attempts", attempts),
+			logger.F("backoff_ms", backoff.Milliseconds()),
+			logger.Err(err),
+		)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s unavailable after %d attempts (gave up waiting): %w", name, attempt, err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if cfg.MaxBackoff > 0 && backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+}